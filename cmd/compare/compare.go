@@ -11,6 +11,7 @@ import (
 	"github.com/stokaro/ptah/cmd/internal/cmdutil"
 	"github.com/stokaro/ptah/cmd/internal/dbcli"
 	"github.com/stokaro/ptah/cmd/internal/exitcode"
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema"
 	"github.com/stokaro/ptah/migration/planner"
@@ -22,12 +23,14 @@ const (
 	rootDirFlag  = "root-dir"
 	dbURLFlag    = "db-url"
 	exitCodeFlag = "exit-code"
+	explainFlag  = "explain"
 )
 
 type options struct {
 	rootDir        string
 	dbURL          string
 	exitOnDiff     bool
+	explain        bool
 	connectTimeout string
 	schemas        string
 }
@@ -55,6 +58,7 @@ func registerFlags(cmd *cobra.Command, opts *options) {
 	flags.StringVar(&opts.rootDir, rootDirFlag, "./", "Root directory to scan for Go entities")
 	flags.StringVar(&opts.dbURL, dbURLFlag, "", "Database URL (required). Example: postgres://localhost:5432/dbname")
 	flags.BoolVar(&opts.exitOnDiff, exitCodeFlag, false, "Exit with 1 when the schema diff is non-empty")
+	flags.BoolVar(&opts.explain, explainFlag, false, "Show the raw and normalized values behind each column change")
 	dbcli.RegisterConnectTimeoutFlag(flags, &opts.connectTimeout)
 	dbcli.RegisterSchemasFlag(flags, &opts.schemas)
 }
@@ -103,7 +107,10 @@ func compareCommand(cmd *cobra.Command, opts *options) error {
 
 	// 3. Compare schemas (dialect-aware: MySQL/MariaDB RESTRICT == NO ACTION)
 	info := conn.Info()
-	diff := schemadiff.CompareWithDialect(result, dbSchema, info.Dialect)
+	compareOpts := config.DefaultCompareOptions()
+	compareOpts.Dialect = info.Dialect
+	compareOpts.Explain = opts.explain
+	diff := schemadiff.CompareWithOptions(result, dbSchema, compareOpts)
 
 	// 4. Display differences
 	output, err := planner.GenerateSchemaDiffSQLStatementsWithCapabilities(diff, result, info.Dialect, info.Capabilities)
@@ -111,6 +118,11 @@ func compareCommand(cmd *cobra.Command, opts *options) error {
 		return fmt.Errorf("error generating schema diff SQL: %w", err)
 	}
 	fmt.Fprint(out, output)
+	if opts.explain {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "=== CHANGE EXPLANATIONS ===")
+		fmt.Fprint(out, schemadiff.Explain(diff))
+	}
 
 	if opts.exitOnDiff {
 		return nonEmptyDiffExitCode(diff)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/stokaro/ptah/cmd/internal/buildinfo"
 	"github.com/stokaro/ptah/cmd/internal/cmdutil"
 	"github.com/stokaro/ptah/cmd/internal/dbcli"
 	"github.com/stokaro/ptah/dbschema"
@@ -22,6 +23,17 @@ const (
 	generateCheckDestructiveFlag = "check-destructive"
 	generateAllowDestructiveFlag = "allow-destructive"
 	generateReportFormatFlag     = "report"
+	generateVersionStrategyFlag  = "version-strategy"
+	generateVersionFlag          = "migration-version"
+	generateSplitFlag            = "split"
+	generateTargetDialectFlag    = "target-dialect"
+	generateStrictDialectFlag    = "strict-dialect"
+	generateStrictFlag           = "strict"
+	generateAllowEmptySchemaFlag = "allow-empty-schema"
+	generateMinTablesFlag        = "min-expected-tables"
+	generateMaxDropRatioFlag     = "max-drop-ratio"
+	generateAllowMassDropFlag    = "allow-mass-drop"
+	generateSourceSHAFlag        = "source-sha"
 )
 
 func NewMigrateGenerateCommand() *cobra.Command {
@@ -46,6 +58,17 @@ and performs an up/down/up round-trip.`,
 	flags.Bool(generateCheckDestructiveFlag, false, "Fail when generated migration SQL contains destructive statements")
 	flags.Bool(generateAllowDestructiveFlag, false, "Allow destructive statements when --check-destructive is set")
 	flags.String(generateReportFormatFlag, "", `Safety report format next to the migration files: "", html, or json`)
+	flags.String(generateVersionStrategyFlag, string(generator.VersionStrategyTimestamp), "Migration version strategy: timestamp, sequential, or explicit")
+	flags.String(generateVersionFlag, "", "Migration version to use with --version-strategy explicit")
+	flags.String(generateSplitFlag, string(generator.SplitStrategySingleFile), "Split the diff into several migrations: single, per-table, or per-phase")
+	flags.String(generateTargetDialectFlag, "", "Generate SQL for this dialect instead of the connected database's (e.g. mysql while connected to mariadb)")
+	flags.Bool(generateStrictDialectFlag, false, "Fail when the target dialect cannot express part of the schema (functions, RLS, extensions, roles, grants) instead of skipping it")
+	flags.Bool(generateStrictFlag, false, "Fail on unknown or misspelled //migrator: annotations and attributes, listing every one, instead of ignoring them")
+	flags.Bool(generateAllowEmptySchemaFlag, false, "Generate even when the Go entities declare no tables (or fewer than --min-expected-tables)")
+	flags.Int(generateMinTablesFlag, 0, "Fewest tables the Go entities must declare (0 requires at least one)")
+	flags.Float64(generateMaxDropRatioFlag, 0, "Refuse migrations dropping more than this fraction (0-1) of the database tables; 0 disables the check")
+	flags.Bool(generateAllowMassDropFlag, false, "Write migrations that exceed --max-drop-ratio")
+	flags.String(generateSourceSHAFlag, "", "Git commit of the Go entities, recorded in the ptah:source_sha header of generated files")
 	flags.String(dbcli.ConfigFlagName, "", "Path to a ptah.yaml config file (default: ./ptah.yaml when present)")
 	flags.String(dbcli.ConnectTimeoutFlagName, dbcli.DefaultConnectTimeout.String(), "Initial database connection timeout")
 	flags.String(dbcli.EnvFlagName, "", "Project env name to read from ptah.yaml or atlas.hcl")
@@ -99,6 +122,58 @@ func migrateGenerateCommand(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	versionStrategyValue, err := cmd.Flags().GetString(generateVersionStrategyFlag)
+	if err != nil {
+		return err
+	}
+	versionStrategy, err := generator.ParseVersionStrategy(versionStrategyValue)
+	if err != nil {
+		return err
+	}
+	version, err := cmd.Flags().GetString(generateVersionFlag)
+	if err != nil {
+		return err
+	}
+	splitValue, err := cmd.Flags().GetString(generateSplitFlag)
+	if err != nil {
+		return err
+	}
+	splitStrategy, err := generator.ParseSplitStrategy(splitValue)
+	if err != nil {
+		return err
+	}
+	targetDialect, err := cmd.Flags().GetString(generateTargetDialectFlag)
+	if err != nil {
+		return err
+	}
+	strictDialect, err := cmd.Flags().GetBool(generateStrictDialectFlag)
+	if err != nil {
+		return err
+	}
+	strict, err := cmd.Flags().GetBool(generateStrictFlag)
+	if err != nil {
+		return err
+	}
+	allowEmptySchema, err := cmd.Flags().GetBool(generateAllowEmptySchemaFlag)
+	if err != nil {
+		return err
+	}
+	minExpectedTables, err := cmd.Flags().GetInt(generateMinTablesFlag)
+	if err != nil {
+		return err
+	}
+	maxDropRatio, err := cmd.Flags().GetFloat64(generateMaxDropRatioFlag)
+	if err != nil {
+		return err
+	}
+	allowMassDrop, err := cmd.Flags().GetBool(generateAllowMassDropFlag)
+	if err != nil {
+		return err
+	}
+	sourceSHA, err := cmd.Flags().GetString(generateSourceSHAFlag)
+	if err != nil {
+		return err
+	}
 	connectTimeoutValue, err := cmd.Flags().GetString(dbcli.ConnectTimeoutFlagName)
 	if err != nil {
 		return err
@@ -138,6 +213,18 @@ func migrateGenerateCommand(cmd *cobra.Command, _ []string) error {
 		AllowDestructive:  allowDestructive,
 		ReportFormat:      reportFormat,
 		ShadowDatabaseURL: shadowDB,
+		VersionStrategy:   versionStrategy,
+		Version:           version,
+		SplitStrategy:     splitStrategy,
+		TargetDialect:     targetDialect,
+		StrictDialect:     strictDialect,
+		Strict:            strict,
+		AllowEmptySchema:  allowEmptySchema,
+		MinExpectedTables: minExpectedTables,
+		MaxDropRatio:      maxDropRatio,
+		AllowMassDrop:     allowMassDrop,
+		PtahVersion:       buildinfo.Resolve().Version,
+		SourceSHA:         sourceSHA,
 		DiffPolicy: generator.DiffPolicy{
 			SkipChangeKinds: projectCfg.Diff.SkipChangeKinds(),
 			ConcurrentIndex: projectCfg.Diff.ConcurrentIndexCreate(),
@@ -159,5 +246,11 @@ func migrateGenerateCommand(cmd *cobra.Command, _ []string) error {
 			fmt.Fprintf(out, "REPORT: %s\n", pair.ReportFile)
 		}
 	}
+	for _, feature := range files.SkippedFeatures {
+		fmt.Fprintf(out, "SKIPPED: %s\n", feature)
+	}
+	for _, change := range files.ManualChanges {
+		fmt.Fprintf(out, "MANUAL: %s\n", change)
+	}
 	return nil
 }
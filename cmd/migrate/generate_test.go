@@ -61,6 +61,67 @@ func TestMigrateGenerateProjectConfigPrecedence(t *testing.T) {
 	c.Assert(shadowDB, qt.Equals, "postgres://localhost/atlas_shadow")
 }
 
+func TestMigrateGenerateSequentialVersionStrategy(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	entitiesDir := writeMigrateGenerateShadowEntities(c, dir)
+	migrationsDir := filepath.Join(dir, "migrations")
+	c.Assert(os.MkdirAll(migrationsDir, 0755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(migrationsDir, "0000000004_init.up.sql"), []byte("SELECT 1;\n"), 0o600), qt.IsNil)
+
+	var out bytes.Buffer
+	cmd := migrate.NewMigrateGenerateCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{
+		"--root-dir", entitiesDir,
+		"--db-url", "sqlite://" + filepath.Join(dir, "app.db"),
+		"--migrations-dir", migrationsDir,
+		"--name", "create_users",
+		"--version-strategy", "sequential",
+	})
+
+	c.Assert(cmd.Execute(), qt.IsNil)
+	c.Assert(out.String(), qt.Contains, "0000000005_create_users.up.sql")
+}
+
+func TestMigrateGenerateRejectsUnknownVersionStrategy(t *testing.T) {
+	c := qt.New(t)
+
+	cmd := migrate.NewMigrateGenerateCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--db-url", "sqlite://app.db", "--migrations-dir", c.TempDir(), "--version-strategy", "semver"})
+
+	c.Assert(cmd.Execute(), qt.ErrorMatches, `unsupported version strategy "semver".*`)
+}
+
+func TestMigrateGenerateTargetDialect(t *testing.T) {
+	c := qt.New(t)
+	dir := c.TempDir()
+	entitiesDir := writeMigrateGenerateShadowEntities(c, dir)
+	migrationsDir := filepath.Join(dir, "migrations")
+	c.Assert(os.MkdirAll(migrationsDir, 0755), qt.IsNil)
+
+	var out bytes.Buffer
+	cmd := migrate.NewMigrateGenerateCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{
+		"--root-dir", entitiesDir,
+		"--db-url", "sqlite://" + filepath.Join(dir, "app.db"),
+		"--migrations-dir", migrationsDir,
+		"--name", "create_users",
+		"--target-dialect", "postgres",
+	})
+
+	c.Assert(cmd.Execute(), qt.IsNil)
+	upFiles, err := filepath.Glob(filepath.Join(migrationsDir, "*_create_users.up.sql"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(upFiles, qt.HasLen, 1)
+	up, err := os.ReadFile(upFiles[0])
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(up), qt.Contains, "-- POSTGRES TABLE: users --")
+}
+
 func TestMigratePlanCommandRejectsAtlasApplyAtRoot(t *testing.T) {
 	c := qt.New(t)
 
@@ -2,6 +2,8 @@ package migrate
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/stokaro/ptah/cmd/internal/cmdutil"
 	"github.com/stokaro/ptah/cmd/internal/dbcli"
+	"github.com/stokaro/ptah/cmd/internal/exitcode"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/renderer"
 	"github.com/stokaro/ptah/core/sqlutil"
@@ -18,6 +21,7 @@ import (
 	"github.com/stokaro/ptah/migration/planner"
 	"github.com/stokaro/ptah/migration/safety"
 	"github.com/stokaro/ptah/migration/schemadiff"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
 )
 
 const (
@@ -26,6 +30,8 @@ const (
 	checkDestructiveFlag = "check-destructive"
 	allowDestructiveFlag = "allow-destructive"
 	reportFormatFlag     = "report"
+	jsonFlag             = "json"
+	exitCodeFlag         = "exit-code"
 )
 
 type options struct {
@@ -34,6 +40,8 @@ type options struct {
 	checkDestructive bool
 	allowDestructive bool
 	reportFormat     string
+	jsonOutput       bool
+	exitOnChanges    bool
 	connectTimeout   string
 	schemas          string
 }
@@ -63,6 +71,8 @@ func registerFlags(cmd *cobra.Command, opts *options) {
 	flags.BoolVar(&opts.checkDestructive, checkDestructiveFlag, false, "Fail when generated migration SQL contains destructive statements")
 	flags.BoolVar(&opts.allowDestructive, allowDestructiveFlag, false, "Allow destructive statements when --check-destructive is set")
 	flags.StringVar(&opts.reportFormat, reportFormatFlag, "text", "Safety report format: text, html, or json")
+	flags.BoolVar(&opts.jsonOutput, jsonFlag, false, "Output the schema diff, planned statements, and safety report as one JSON document")
+	flags.BoolVar(&opts.exitOnChanges, exitCodeFlag, false, "Exit with 1 when the plan contains schema changes")
 	dbcli.RegisterConnectTimeoutFlag(flags, &opts.connectTimeout)
 	dbcli.RegisterSchemasFlag(flags, &opts.schemas)
 }
@@ -77,7 +87,10 @@ func migrateCommandWithOptions(cmd *cobra.Command, opts *options) error {
 	if reportFormat != "text" && reportFormat != "html" && reportFormat != "json" {
 		return fmt.Errorf("unsupported report format %q", reportFormat)
 	}
-	if reportFormat == "text" {
+	if opts.jsonOutput && reportFormat != "text" {
+		return fmt.Errorf("--%s cannot be combined with --%s %s", jsonFlag, reportFormatFlag, reportFormat)
+	}
+	if reportFormat == "text" && !opts.jsonOutput {
 		fmt.Fprintf(out, "Generating migration from %s to database %s\n", opts.rootDir, dbschema.FormatDatabaseURL(opts.dbURL))
 		fmt.Fprintln(out, "=== GENERATE MIGRATION SQL ===")
 		fmt.Fprintln(out)
@@ -127,14 +140,17 @@ func migrateCommandWithOptions(cmd *cobra.Command, opts *options) error {
 	if err != nil {
 		return fmt.Errorf("error assessing migration safety: %w", err)
 	}
+	if opts.jsonOutput {
+		if err := renderPlanJSON(out, info.Dialect, diff, assessments); err != nil {
+			return fmt.Errorf("error rendering plan: %w", err)
+		}
+		return planResult(opts, diff, assessments)
+	}
 	if reportFormat == "html" || reportFormat == "json" {
 		if err := renderSafetyReport(out, reportFormat, assessments); err != nil {
 			return fmt.Errorf("error rendering safety report: %w", err)
 		}
-		if opts.checkDestructive && safety.HasDestructiveAssessment(assessments) && !opts.allowDestructive {
-			return fmt.Errorf("destructive migration statements require --allow-destructive")
-		}
-		return nil
+		return planResult(opts, diff, assessments)
 	}
 	if err := renderSafetyReport(out, reportFormat, assessments); err != nil {
 		return fmt.Errorf("error rendering safety report: %w", err)
@@ -171,9 +187,46 @@ func migrateCommandWithOptions(cmd *cobra.Command, opts *options) error {
 	fmt.Fprintf(out, "Generated %d migration statements.\n", countRenderedStatements(migrationSQL))
 	fmt.Fprintln(out, "⚠️  Review the SQL carefully before executing!")
 
+	return planResult(opts, diff, nil)
+}
+
+// planResult applies the destructive-statement gate and the --exit-code
+// contract: 1 when the plan has changes, 0 when the database is in sync.
+func planResult(opts *options, diff *difftypes.SchemaDiff, assessments []safety.StatementAssessment) error {
+	if opts.checkDestructive && safety.HasDestructiveAssessment(assessments) && !opts.allowDestructive {
+		return fmt.Errorf("destructive migration statements require --allow-destructive")
+	}
+	if opts.exitOnChanges && diff.HasChanges() {
+		return exitcode.New(1, errors.New("schema changes planned"))
+	}
 	return nil
 }
 
+// planReport is the --json document of the plan command.
+type planReport struct {
+	Dialect    string                `json:"dialect"`
+	HasChanges bool                  `json:"has_changes"`
+	Diff       *difftypes.SchemaDiff `json:"diff"`
+	Statements []string              `json:"statements"`
+	Safety     safety.Report         `json:"safety"`
+}
+
+func renderPlanJSON(w io.Writer, dialect string, diff *difftypes.SchemaDiff, assessments []safety.StatementAssessment) error {
+	statements := make([]string, 0, len(assessments))
+	for _, assessment := range assessments {
+		statements = append(statements, assessment.Statement)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(planReport{
+		Dialect:    dialect,
+		HasChanges: diff.HasChanges(),
+		Diff:       diff,
+		Statements: statements,
+		Safety:     safety.NewReport(assessments),
+	})
+}
+
 func countRenderedStatements(sql string) int {
 	statements := sqlutil.SplitSQLStatements(sql)
 	count := 0
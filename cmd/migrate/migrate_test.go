@@ -2,20 +2,20 @@ package migrate_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/stokaro/ptah/cmd/internal/exitcode"
 	"github.com/stokaro/ptah/cmd/migrate"
 )
 
-func TestMigratePlanTextOutputContainsSQLNotASTPointers(t *testing.T) {
-	c := qt.New(t)
-
-	dir := t.TempDir()
-	modelsDir := filepath.Join(dir, "models")
+func writeUserModel(c *qt.C) (modelsDir, dbURL string) {
+	dir := c.TempDir()
+	modelsDir = filepath.Join(dir, "models")
 	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
 	c.Assert(os.WriteFile(filepath.Join(modelsDir, "models.go"), []byte(`package models
 
@@ -25,6 +25,12 @@ type User struct {
 	ID int
 }
 `), 0o600), qt.IsNil)
+	return modelsDir, "sqlite:///" + filepath.Join(dir, "ptah.db")
+}
+
+func TestMigratePlanTextOutputContainsSQLNotASTPointers(t *testing.T) {
+	c := qt.New(t)
+	modelsDir, dbURL := writeUserModel(c)
 
 	var out bytes.Buffer
 	cmd := migrate.NewMigrateCommand()
@@ -32,7 +38,7 @@ type User struct {
 	cmd.SetErr(&out)
 	cmd.SetArgs([]string{
 		"--root-dir", modelsDir,
-		"--db-url", "sqlite:///" + filepath.Join(dir, "ptah.db"),
+		"--db-url", dbURL,
 	})
 
 	err := cmd.Execute()
@@ -42,3 +48,43 @@ type User struct {
 	c.Assert(out.String(), qt.Not(qt.Contains), "[0x")
 	c.Assert(out.String(), qt.Not(qt.Contains), "&{")
 }
+
+func TestMigratePlanJSONOutputWithExitCode(t *testing.T) {
+	c := qt.New(t)
+	modelsDir, dbURL := writeUserModel(c)
+
+	var out bytes.Buffer
+	cmd := migrate.NewMigrateCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--root-dir", modelsDir, "--db-url", dbURL, "--json", "--exit-code"})
+
+	err := cmd.Execute()
+
+	c.Assert(exitcode.Code(err, 0), qt.Equals, 1)
+	var report struct {
+		Dialect    string   `json:"dialect"`
+		HasChanges bool     `json:"has_changes"`
+		Statements []string `json:"statements"`
+		Diff       struct {
+			TablesAdded []string `json:"tables_added"`
+		} `json:"diff"`
+	}
+	c.Assert(json.Unmarshal(out.Bytes(), &report), qt.IsNil)
+	c.Assert(report.Dialect, qt.Equals, "sqlite")
+	c.Assert(report.HasChanges, qt.IsTrue)
+	c.Assert(report.Diff.TablesAdded, qt.DeepEquals, []string{"users"})
+	c.Assert(report.Statements, qt.HasLen, 1)
+	c.Assert(report.Statements[0], qt.Contains, `CREATE TABLE "users"`)
+}
+
+func TestMigratePlanJSONRejectsReportFormat(t *testing.T) {
+	c := qt.New(t)
+
+	cmd := migrate.NewMigrateCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--db-url", "sqlite:///unused.db", "--json", "--report", "html"})
+
+	c.Assert(cmd.Execute(), qt.ErrorMatches, "--json cannot be combined with --report html")
+}
@@ -28,3 +28,28 @@ func TestMigrateStatusExitCode_Pending(t *testing.T) {
 	c.Assert(err, qt.IsNotNil)
 	c.Assert(exitcode.Code(err, 0), qt.Equals, 1)
 }
+
+func TestMigrateStatusExitCode_Discrepancies(t *testing.T) {
+	tests := []struct {
+		name          string
+		discrepancies []migrator.Discrepancy
+		wantCode      int
+	}{
+		{name: "consistent history", wantCode: 0},
+		{
+			name:          "missing migration",
+			discrepancies: []migrator.Discrepancy{{Kind: migrator.DiscrepancyMissingMigration, Version: 2}},
+			wantCode:      1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			err := discrepanciesExitCode(tt.discrepancies)
+
+			c.Assert(exitcode.Code(err, 0), qt.Equals, tt.wantCode)
+		})
+	}
+}
@@ -27,6 +27,7 @@ const (
 	verboseFlag    = "verbose"
 	jsonFlag       = "json"
 	exitCodeFlag   = "exit-code"
+	verifyFlag     = "verify"
 )
 
 type options struct {
@@ -37,6 +38,7 @@ type options struct {
 	verbose             bool
 	jsonOutput          bool
 	exitOnPending       bool
+	verify              bool
 	connectTimeout      string
 	configPath          string
 	envName             string
@@ -62,7 +64,11 @@ This command displays information about:
 - List of pending migration versions
 
 This is useful for checking the state of your database before running
-migrations or for debugging migration issues.`,
+migrations or for debugging migration issues.
+
+With --verify it also checks the migration history: applied versions whose
+migration file is gone (they cannot be rolled back) and applied migrations
+edited since they ran. Any problem exits with 1.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return migrateStatusCommand(cmd, &opts)
 		},
@@ -81,6 +87,7 @@ func registerFlags(cmd *cobra.Command, opts *options) {
 	flags.BoolVar(&opts.verbose, verboseFlag, false, "Enable verbose output with detailed migration information")
 	flags.BoolVar(&opts.jsonOutput, jsonFlag, false, "Output status in JSON format")
 	flags.BoolVar(&opts.exitOnPending, exitCodeFlag, false, "Exit with 1 when pending migrations are available")
+	flags.BoolVar(&opts.verify, verifyFlag, false, "Check applied migrations against the migration files and exit with 1 on any discrepancy")
 	flags.StringVar(&opts.logFormat, cliobs.LogFormatFlagName, "text", "Log format: text or json")
 	flags.StringVar(&opts.logLevel, cliobs.LogLevelFlagName, "info", "Log level: debug, info, warn, or error")
 	flags.StringVar(&opts.metricsAddr, cliobs.MetricsAddrFlagName, "", "Address for the Prometheus /metrics endpoint, such as :9090")
@@ -185,20 +192,48 @@ func migrateStatusCommand(cmd *cobra.Command, opts *options) error {
 		return fmt.Errorf("error getting migration status: %w", err)
 	}
 
+	var discrepancies []migrator.Discrepancy
+	if opts.verify {
+		discrepancies, err = mig.Verify(context.Background())
+		if err != nil {
+			return fmt.Errorf("error verifying migration history: %w", err)
+		}
+	}
+
 	if opts.jsonOutput {
-		if err := outputJSON(cmd.OutOrStdout(), status); err != nil {
+		var report any = status
+		if opts.verify {
+			report = verifiedStatus{MigrationStatus: status, Discrepancies: discrepancies}
+		}
+		if err := outputJSON(cmd.OutOrStdout(), report); err != nil {
 			return err
 		}
-	} else if err := outputHuman(emit, status, conn, opts.verbose); err != nil {
-		return err
+	} else {
+		if err := outputHuman(emit, status, conn, opts.verbose); err != nil {
+			return err
+		}
+		if opts.verify {
+			outputDiscrepancies(emit, discrepancies)
+		}
 	}
 
+	if opts.verify {
+		if err := discrepanciesExitCode(discrepancies); err != nil {
+			return err
+		}
+	}
 	if opts.exitOnPending {
 		return pendingMigrationsExitCode(status)
 	}
 	return nil
 }
 
+// verifiedStatus is the JSON report of status --verify.
+type verifiedStatus struct {
+	*migrator.MigrationStatus
+	Discrepancies []migrator.Discrepancy `json:"discrepancies"`
+}
+
 func shutdownObservability(runtime *cliobs.Runtime) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -214,10 +249,29 @@ func pendingMigrationsExitCode(status *migrator.MigrationStatus) error {
 	return nil
 }
 
-func outputJSON(w io.Writer, status *migrator.MigrationStatus) error {
+func discrepanciesExitCode(discrepancies []migrator.Discrepancy) error {
+	if len(discrepancies) > 0 {
+		return exitcode.New(1, fmt.Errorf("migration history has %d problem(s)", len(discrepancies)))
+	}
+	return nil
+}
+
+func outputJSON(w io.Writer, report any) error {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(status)
+	return encoder.Encode(report)
+}
+
+func outputDiscrepancies(emit cliobs.Emitter, discrepancies []migrator.Discrepancy) {
+	emit.Println("\n=== MIGRATION HISTORY ===")
+	if len(discrepancies) == 0 {
+		emit.Println("Status: ✅ Applied migrations match the migration files")
+		return
+	}
+	emit.Printf("Status: ❌ Migration history has %d problem(s)\n", len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		emit.Printf("  - %s\n", discrepancy)
+	}
 }
 
 func outputHuman(emit cliobs.Emitter, status *migrator.MigrationStatus, conn *dbschema.DatabaseConnection, verbose bool) error { //revive:disable-line:flag-parameter // it's ok here
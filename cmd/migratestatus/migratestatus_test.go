@@ -2,6 +2,9 @@ package migratestatus_test
 
 import (
 	"bytes"
+	"context"
+	"net/url"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -11,6 +14,8 @@ import (
 	"github.com/stokaro/ptah/cmd/internal/dbcli"
 	"github.com/stokaro/ptah/cmd/internal/exitcode"
 	"github.com/stokaro/ptah/cmd/migratestatus"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
 )
 
 func TestMigrateStatusCommand_Creation(t *testing.T) {
@@ -25,6 +30,7 @@ func TestMigrateStatusCommand_Creation(t *testing.T) {
 	c.Assert(cmd.Flag(dbcli.MigrationsSchemaFlagName), qt.IsNotNil)
 	c.Assert(cmd.Flag(dbcli.MigrationsTableFlagName), qt.IsNotNil)
 	c.Assert(cmd.Flag("exit-code"), qt.IsNotNil)
+	c.Assert(cmd.Flag("verify"), qt.IsNotNil)
 	c.Assert(cmd.Flag(cliobs.LogFormatFlagName), qt.IsNotNil)
 	c.Assert(cmd.Flag(cliobs.LogLevelFlagName), qt.IsNotNil)
 	c.Assert(cmd.Flag(cliobs.MetricsAddrFlagName), qt.IsNotNil)
@@ -51,3 +57,39 @@ func TestMigrateStatusCommand_UnreachableDatabaseExits2(t *testing.T) {
 	c.Assert(errOut.String(), qt.Contains, "error connecting to database")
 	c.Assert(errOut.String(), qt.Not(qt.Contains), "Usage:")
 }
+
+func TestMigrateStatusCommand_VerifyReportsDeletedMigration(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	files := map[string]string{
+		"000001_create_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+		"000001_create_users.down.sql": "DROP TABLE users;",
+		"000002_create_posts.up.sql":   "CREATE TABLE posts (id INTEGER PRIMARY KEY);",
+		"000002_create_posts.down.sql": "DROP TABLE posts;",
+	}
+	for name, body := range files {
+		c.Assert(os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600), qt.IsNil)
+	}
+	dbURL := (&url.URL{Scheme: "sqlite", Path: filepath.Join(t.TempDir(), "status.sqlite")}).String()
+	conn, err := dbschema.ConnectToDatabase(ctx, dbURL)
+	c.Assert(err, qt.IsNil)
+	m, err := migrator.NewFSMigrator(conn, os.DirFS(dir))
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+	c.Assert(conn.Close(), qt.IsNil)
+	c.Assert(os.Remove(filepath.Join(dir, "000002_create_posts.up.sql")), qt.IsNil)
+	c.Assert(os.Remove(filepath.Join(dir, "000002_create_posts.down.sql")), qt.IsNil)
+
+	cmd := migratestatus.NewMigrateStatusCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--db-url", dbURL, "--migrations-dir", dir, "--verify"})
+
+	err = cmd.Execute()
+
+	c.Assert(exitcode.Code(err, 0), qt.Equals, 1)
+	c.Assert(out.String(), qt.Contains, "=== MIGRATION HISTORY ===")
+	c.Assert(out.String(), qt.Contains, "migration 2 (Create Posts) is applied but has no migration file; it cannot be rolled back")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/stokaro/ptah/cmd/drift"
 	"github.com/stokaro/ptah/cmd/generate"
 	"github.com/stokaro/ptah/cmd/internal/cmdutil"
+	"github.com/stokaro/ptah/cmd/internal/exitcode"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/internal/annotationschema"
 	hclrender "github.com/stokaro/ptah/internal/atlashclrender"
@@ -57,6 +58,7 @@ under ptah atlas.`,
 	cmdutil.ConfigureCommandArgs(cmd, cmdutil.NoPositionalArgs)
 	cmd.AddCommand(newSchemaAnnotationsCommand())
 	cmd.AddCommand(newSchemaExportCommand())
+	cmd.AddCommand(newSchemaValidateCommand())
 	renderCmd := generate.NewGenerateCommand()
 	renderCmd.Short = "Render desired schema SQL"
 	renderCmd.Long = "Render desired schema SQL from Go annotations, YAML schema files, or HCL schema files."
@@ -123,6 +125,49 @@ func runAnnotations(cmd *cobra.Command, format, outPath string) error {
 	return nil
 }
 
+func newSchemaValidateCommand() *cobra.Command {
+	var rootDir string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check Go annotations for typos and unknown attributes",
+		Long: `Check Go annotations for typos and unknown attributes.
+
+The parser ignores comments it does not recognize, so a misspelled directive
+such as //migator:schema:field or //migrator:schema:feild silently drops part of
+the schema. validate parses the Go entities strictly and lists every problem
+with its file:line:column:
+
+  ptah schema validate --root-dir ./models
+
+The command exits 1 when it finds annotation problems.`,
+		Args:          cmdutil.NoPositionalArgs,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runValidate(cmd, rootDir)
+		},
+	}
+	cmd.Flags().StringVar(&rootDir, exportRootDirFlag, ".", "Root directory to scan for Go annotations")
+	cmdutil.ConfigureCommandArgs(cmd, cmdutil.NoPositionalArgs)
+	return cmd
+}
+
+func runValidate(cmd *cobra.Command, rootDir string) error {
+	database, annotationErrors, err := goschema.ParseDirStrict(rootDir)
+	if err != nil {
+		return cmdutil.Fail(cmd, fmt.Errorf("parse Go entities: %w", err))
+	}
+	for _, annotationError := range annotationErrors {
+		annotationError.File = filepath.Join(rootDir, annotationError.File)
+		fmt.Fprintln(cmd.OutOrStdout(), annotationError.Error())
+	}
+	if len(annotationErrors) > 0 {
+		return exitcode.New(1, fmt.Errorf("found %d annotation problem(s)", len(annotationErrors)))
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Annotations are valid (%d table(s))\n", len(database.Tables))
+	return nil
+}
+
 func newSchemaExportCommand() *cobra.Command {
 	var from string
 	var to string
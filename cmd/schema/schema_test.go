@@ -8,6 +8,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/stokaro/ptah/cmd/internal/exitcode"
 	"github.com/stokaro/ptah/cmd/schema"
 	"github.com/stokaro/ptah/internal/atlashcl"
 )
@@ -298,6 +299,49 @@ func TestSchemaExportCleanupDryRunAndWrite(t *testing.T) {
 	c.Assert(string(content), qt.Contains, "// User is business documentation.")
 }
 
+func TestSchemaValidateCommandAcceptsValidAnnotations(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	writeModel(c, dir)
+
+	cmd := schema.NewSchemaCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"validate", "--root-dir", dir})
+
+	err := cmd.Execute()
+
+	c.Assert(err, qt.IsNil, qt.Commentf("stderr:\n%s", stderr.String()))
+	c.Assert(stdout.String(), qt.Equals, "Annotations are valid (1 table(s))\n")
+}
+
+func TestSchemaValidateCommandListsAnnotationProblems(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+	content := `package models
+
+//migator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" tpye="SERIAL" primary="true"
+	ID int64
+}
+`
+	c.Assert(os.WriteFile(filepath.Join(dir, "model.go"), []byte(content), 0o600), qt.IsNil)
+
+	cmd := schema.NewSchemaCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"validate", "--root-dir", dir})
+
+	err := cmd.Execute()
+
+	c.Assert(exitcode.Code(err, 0), qt.Equals, 1)
+	c.Assert(stdout.String(), qt.Equals, filepath.Join(dir, "model.go")+`:3:3: unknown annotation prefix "migator:"; did you mean //migrator:schema:table?`+"\n"+
+		filepath.Join(dir, "model.go")+`:5:36: unknown attribute "tpye" on //migrator:schema:field; did you mean type?`+"\n")
+}
+
 func writeModel(c *qt.C, dir string) string {
 	path := filepath.Join(dir, "model.go")
 	content := `package models
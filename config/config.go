@@ -5,7 +5,12 @@
 // clean Go APIs rather than external configuration file management.
 package config
 
-import "slices"
+import (
+	"slices"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+)
 
 // CompareOptions contains configuration options for schema comparison operations.
 // These options control how schema differences are calculated and what elements
@@ -32,6 +37,87 @@ type CompareOptions struct {
 	// unchanged foreign key. PostgreSQL distinguishes the two at DDL, so the
 	// fold is deliberately NOT applied there.
 	Dialect string
+
+	// IncludeSystemRelations makes the database readers introspect temporary
+	// tables and relations in system schemas (PostgreSQL pg_catalog,
+	// information_schema, pg_toast and pg_temp_*; MySQL mysql,
+	// performance_schema and sys). They are skipped by default so that a
+	// concurrent session's temporary tables never show up as tables to drop.
+	IncludeSystemRelations bool
+
+	// CustomComparators run after the built-in column comparison on every
+	// annotated field that has a database column of the same name. A change
+	// they report is recorded in ColumnDiff.Changes under CustomChangeKey(name)
+	// as "before -> after". Comparators run in registration order.
+	CustomComparators []NamedComparator
+
+	// Explain records a types.ChangeExplanation next to every column change,
+	// with the raw and normalized values the comparison used. Use
+	// schemadiff.Explain to print them when a diff keeps reappearing.
+	Explain bool
+
+	// StrictIndexStorageParams reports PostgreSQL index storage parameters
+	// (WITH (fillfactor=70), ...) set in the database as drift even when the
+	// index annotation declares none. By default such parameters are
+	// preserved: only indexes whose annotation sets storage are compared.
+	StrictIndexStorageParams bool
+
+	// SkipRolePasswords leaves role passwords out of the comparison and out
+	// of generated migrations: no password change is reported, and CREATE
+	// ROLE is planned without a PASSWORD clause. Use it when passwords are
+	// managed outside the migrations.
+	SkipRolePasswords bool
+
+	// RevokeUndeclaredRoleMemberships revokes memberships of annotated roles
+	// that their member_of attribute does not list. By default such
+	// memberships are kept, since they may have been granted outside the
+	// migrations; only declared memberships are added.
+	RevokeUndeclaredRoleMemberships bool
+
+	// IgnoreDefaults leaves column defaults out of the comparison: no default
+	// or default_expr change is ever reported, so no SET DEFAULT or DROP
+	// DEFAULT is planned. Use it when defaults are managed in application
+	// code.
+	IgnoreDefaults bool
+
+	// StructuredComments compares column comments written as
+	// semicolon-separated key=value pairs as unordered sets, so "a=1;b=2"
+	// equals "b=2; a=1". A comment on either side that does not parse that
+	// way is compared verbatim.
+	StructuredComments bool
+
+	// TypeEquivalences declares column types the comparison treats as
+	// interchangeable, for custom domains and aliases the built-in type
+	// normalization does not know. Each key is rewritten to its value on both
+	// the annotation and the database side before the built-in rules run, so
+	// {"email_address": "VARCHAR(320)"} makes an email_address domain column
+	// equal to VARCHAR(320). Keys match case-insensitively.
+	TypeEquivalences map[string]string
+}
+
+// CustomComparator compares one property of an annotated field with the
+// matching database column. It returns the current (database) and desired
+// (annotation) values and whether they differ. Custom metadata can be carried
+// on the annotation as platform attributes, e.g. platform.postgres.encrypt,
+// and read from field.Overrides.
+type CustomComparator func(field goschema.Field, column types.DBColumn) (before, after string, changed bool)
+
+// NamedComparator is a CustomComparator registered under a name.
+type NamedComparator struct {
+	// Name identifies the comparator and the change key it writes.
+	Name string
+	// Compare is the comparison function.
+	Compare CustomComparator
+}
+
+// CustomChangePrefix prefixes the ColumnDiff.Changes keys written by custom
+// comparators, keeping them apart from the built-in change kinds.
+const CustomChangePrefix = "custom:"
+
+// CustomChangeKey returns the ColumnDiff.Changes key for changes reported by
+// the comparator registered as name.
+func CustomChangeKey(name string) string {
+	return CustomChangePrefix + name
 }
 
 // DefaultCompareOptions returns the default comparison options with sensible defaults.
@@ -75,6 +161,84 @@ func WithAdditionalIgnoredExtensions(extensions ...string) *CompareOptions {
 	}
 }
 
+// WithCustomComparator returns the default options with one custom column
+// comparator registered. Use AddCustomComparator to register more.
+//
+// Example:
+//
+//	opts := config.WithCustomComparator("encrypt", func(field goschema.Field, column types.DBColumn) (string, string, bool) {
+//		want := field.Overrides["postgres"]["encrypt"]
+//		have := encryptionOf(column)
+//		return have, want, have != want
+//	})
+func WithCustomComparator(name string, fn CustomComparator) *CompareOptions {
+	return DefaultCompareOptions().AddCustomComparator(name, fn)
+}
+
+// WithSkipRolePasswords returns the default options with SkipRolePasswords
+// set to skip.
+//
+// Example:
+//
+//	opts := config.WithSkipRolePasswords(true)
+func WithSkipRolePasswords(skip bool) *CompareOptions {
+	opts := DefaultCompareOptions()
+	opts.SkipRolePasswords = skip
+	return opts
+}
+
+// WithRevokeUndeclaredRoleMemberships returns the default options with
+// RevokeUndeclaredRoleMemberships set to revoke.
+//
+// Example:
+//
+//	opts := config.WithRevokeUndeclaredRoleMemberships(true)
+func WithRevokeUndeclaredRoleMemberships(revoke bool) *CompareOptions {
+	opts := DefaultCompareOptions()
+	opts.RevokeUndeclaredRoleMemberships = revoke
+	return opts
+}
+
+// WithIgnoreDefaults returns the default options with IgnoreDefaults set to
+// ignore.
+//
+// Example:
+//
+//	opts := config.WithIgnoreDefaults(true)
+func WithIgnoreDefaults(ignore bool) *CompareOptions {
+	opts := DefaultCompareOptions()
+	opts.IgnoreDefaults = ignore
+	return opts
+}
+
+// AddCustomComparator registers a custom column comparator and returns c for
+// chaining. Registering a name again replaces the earlier comparator in place.
+func (c *CompareOptions) AddCustomComparator(name string, fn CustomComparator) *CompareOptions {
+	comparator := NamedComparator{Name: name, Compare: fn}
+	if i := slices.IndexFunc(c.CustomComparators, func(existing NamedComparator) bool {
+		return existing.Name == name
+	}); i >= 0 {
+		c.CustomComparators[i] = comparator
+		return c
+	}
+	c.CustomComparators = append(c.CustomComparators, comparator)
+	return c
+}
+
+// AddTypeEquivalence declares typ interchangeable with equivalent and returns
+// c for chaining.
+//
+// Example:
+//
+//	opts := config.DefaultCompareOptions().AddTypeEquivalence("email_address", "VARCHAR(320)")
+func (c *CompareOptions) AddTypeEquivalence(typ, equivalent string) *CompareOptions {
+	if c.TypeEquivalences == nil {
+		c.TypeEquivalences = make(map[string]string)
+	}
+	c.TypeEquivalences[typ] = equivalent
+	return c
+}
+
 // IsExtensionIgnored checks if the given extension name should be ignored
 // during schema migrations based on the current configuration.
 func (c *CompareOptions) IsExtensionIgnored(extensionName string) bool {
@@ -93,3 +257,15 @@ func (c *CompareOptions) FilterIgnoredExtensions(extensions []string) []string {
 	}
 	return filtered
 }
+
+// WithStructuredComments returns the default options with StructuredComments
+// set to structured.
+//
+// Example:
+//
+//	opts := config.WithStructuredComments(true)
+func WithStructuredComments(structured bool) *CompareOptions {
+	opts := DefaultCompareOptions()
+	opts.StructuredComments = structured
+	return opts
+}
@@ -6,6 +6,8 @@ import (
 	qt "github.com/frankban/quicktest"
 
 	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
 )
 
 func TestDefaultCompareOptions(t *testing.T) {
@@ -217,3 +219,35 @@ func TestLibraryUsageExamples(t *testing.T) {
 		c.Assert(opts.IsExtensionIgnored("pg_trgm"), qt.IsFalse)
 	})
 }
+
+func TestCompareOptions_AddCustomComparator(t *testing.T) {
+	c := qt.New(t)
+	never := func(goschema.Field, types.DBColumn) (string, string, bool) { return "", "", false }
+	always := func(goschema.Field, types.DBColumn) (string, string, bool) { return "a", "b", true }
+
+	opts := config.WithCustomComparator("first", never).
+		AddCustomComparator("second", never).
+		AddCustomComparator("first", always)
+
+	c.Assert(opts.IgnoredExtensions, qt.DeepEquals, []string{"plpgsql"})
+	c.Assert(opts.CustomComparators, qt.HasLen, 2)
+	c.Assert(opts.CustomComparators[0].Name, qt.Equals, "first")
+	c.Assert(opts.CustomComparators[1].Name, qt.Equals, "second")
+	_, _, changed := opts.CustomComparators[0].Compare(goschema.Field{}, types.DBColumn{})
+	c.Assert(changed, qt.IsTrue)
+	c.Assert(config.CustomChangeKey("first"), qt.Equals, "custom:first")
+}
+
+func TestCompareOptions_AddTypeEquivalence(t *testing.T) {
+	c := qt.New(t)
+
+	opts := config.DefaultCompareOptions().
+		AddTypeEquivalence("email_address", "VARCHAR(320)").
+		AddTypeEquivalence("money_amount", "NUMERIC(12,2)")
+
+	c.Assert(opts.IgnoredExtensions, qt.DeepEquals, []string{"plpgsql"})
+	c.Assert(opts.TypeEquivalences, qt.DeepEquals, map[string]string{
+		"email_address": "VARCHAR(320)",
+		"money_amount":  "NUMERIC(12,2)",
+	})
+}
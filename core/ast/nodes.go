@@ -79,6 +79,8 @@ type CreateTableNode struct {
 	Options map[string]string
 	// Partition stores PostgreSQL PARTITION BY metadata.
 	Partition *PartitionSpec
+	// Inherits lists PostgreSQL INHERITS parent tables.
+	Inherits []string
 	// SelectBody stores the SELECT tail for CREATE TABLE ... SELECT statements.
 	SelectBody string
 	// Comment is an optional table comment
@@ -494,6 +496,10 @@ type ConstraintNode struct {
 	ExcludeElements string
 	// WhereCondition contains the optional WHERE clause for EXCLUDE constraints
 	WhereCondition string
+	// NotValid adds a CHECK or FOREIGN KEY constraint without checking existing
+	// rows (PostgreSQL ADD CONSTRAINT ... NOT VALID). Only meaningful in
+	// ALTER TABLE ... ADD; pair it with a ValidateConstraintOperation.
+	NotValid bool
 }
 
 // Accept implements the Node interface for ConstraintNode.
@@ -564,12 +570,20 @@ type IndexNode struct {
 	// StorageParams contains PostgreSQL index storage parameters rendered as
 	// WITH (key='value'), for example pages_per_range for BRIN indexes.
 	StorageParams map[string]string
+	// Invisible renders a MySQL INVISIBLE (MariaDB IGNORED) index. Dialects
+	// without invisible indexes ignore it.
+	Invisible bool
 	// Concurrently requests CREATE INDEX CONCURRENTLY, PostgreSQL's
 	// non-locking index build. Set by planners only when the target
 	// capability set includes capability.CreateIndexConcurrently and the
 	// caller opted into concurrent builds (they cannot run inside a
 	// transaction block). Ignored by non-PostgreSQL renderers.
 	Concurrently bool
+	// Online requests MySQL online DDL (ALGORITHM=INPLACE, LOCK=NONE), the
+	// MySQL-family counterpart of Concurrently. Set by planners only for
+	// index types the server can build without blocking writes. Ignored by
+	// non-MySQL-family renderers.
+	Online bool
 
 	// ClickHouse-specific features
 	// Granularity is the GRANULARITY value for data-skipping indexes. Zero
@@ -1439,7 +1453,10 @@ type CreateMaterializedViewNode struct {
 	Name            string
 	Body            string
 	RefreshStrategy string
-	Comment         string
+	// WithNoData renders WITH NO DATA: the view is created unpopulated and
+	// cannot be queried until its first REFRESH MATERIALIZED VIEW.
+	WithNoData bool
+	Comment    string
 }
 
 func NewCreateMaterializedView(name string) *CreateMaterializedViewNode {
@@ -1456,6 +1473,11 @@ func (n *CreateMaterializedViewNode) SetRefreshStrategy(refreshStrategy string)
 	return n
 }
 
+func (n *CreateMaterializedViewNode) SetWithNoData(withNoData bool) *CreateMaterializedViewNode {
+	n.WithNoData = withNoData
+	return n
+}
+
 func (n *CreateMaterializedViewNode) SetComment(comment string) *CreateMaterializedViewNode {
 	n.Comment = comment
 	return n
@@ -2104,6 +2126,10 @@ type CreateRoleNode struct {
 	Login bool
 	// Password contains the role password (optional, should be encrypted)
 	Password string
+	// PasswordEnv names the environment variable the migrator reads the
+	// password from when the statement runs (optional). It takes precedence
+	// over Password.
+	PasswordEnv string
 	// Superuser indicates whether the role is a superuser (default: false)
 	Superuser bool
 	// CreateDB indicates whether the role can create databases (default: false)
@@ -2158,6 +2184,17 @@ func (n *CreateRoleNode) SetPassword(password string) *CreateRoleNode {
 	return n
 }
 
+// SetPasswordEnv sets the environment variable the password is read from
+// when the migration runs, keeping the password out of the rendered SQL.
+//
+// Example:
+//
+//	role.SetPasswordEnv("APP_USER_PASSWORD")
+func (n *CreateRoleNode) SetPasswordEnv(name string) *CreateRoleNode {
+	n.PasswordEnv = name
+	return n
+}
+
 // SetSuperuser sets whether the role is a superuser.
 //
 // Example:
@@ -2436,8 +2473,12 @@ type RoleOperation interface {
 }
 
 // SetPasswordOperation represents setting a new password for a role.
+// PasswordEnv, when set, names the environment variable the migrator reads
+// the password from when the statement runs, and takes precedence over
+// Password.
 type SetPasswordOperation struct {
-	Password string
+	Password    string
+	PasswordEnv string
 }
 
 // GetOperationType returns the operation type identifier.
@@ -2450,6 +2491,12 @@ func NewSetPasswordOperation(password string) *SetPasswordOperation {
 	return &SetPasswordOperation{Password: password}
 }
 
+// NewSetPasswordFromEnvOperation creates a password setting operation that
+// reads the password from the environment variable name when it runs.
+func NewSetPasswordFromEnvOperation(name string) *SetPasswordOperation {
+	return &SetPasswordOperation{PasswordEnv: name}
+}
+
 // SetLoginOperation represents changing the login capability of a role.
 type SetLoginOperation struct {
 	Login bool
@@ -17,6 +17,10 @@ type AlterOperation interface {
 type AddColumnOperation struct {
 	// Column contains the complete column definition to add
 	Column *ColumnNode
+	// Online requests MySQL online DDL (ALGORITHM=INPLACE, LOCK=NONE). Set by
+	// planners only for columns the server can add without blocking writes.
+	// Ignored by non-MySQL-family renderers.
+	Online bool
 }
 
 // Accept implements the Node interface for AddColumnOperation.
@@ -70,6 +74,11 @@ type ModifyColumnOperation struct {
 	PreviousNullable bool
 	// HasPreviousNullable reports whether PreviousNullable was populated.
 	HasPreviousNullable bool
+	// SkipNullBackfill suppresses the implicit NULL backfill some renderers
+	// emit before SET NOT NULL. Planners set it when they have already emitted
+	// an explicit backfill, or when the statement should fail on existing
+	// NULLs rather than have them replaced with a type-based fallback.
+	SkipNullBackfill bool
 }
 
 // Accept implements the Node interface for ModifyColumnOperation.
@@ -101,6 +110,23 @@ func (op *AlterGeneratedColumnExpressionOperation) Accept(_visitor Visitor) erro
 // alterOperation implements the marker method for type safety.
 func (op *AlterGeneratedColumnExpressionOperation) alterOperation() {}
 
+// AlterColumnDefaultOperation changes only the default of an existing column:
+// ALTER COLUMN ... SET DEFAULT, or DROP DEFAULT when Column.Default is nil.
+// The column's type, nullability, and other attributes are left untouched.
+type AlterColumnDefaultOperation struct {
+	// Column carries the target default. Its type is used only to render the
+	// default literal.
+	Column *ColumnNode
+}
+
+// Accept implements the Node interface for AlterColumnDefaultOperation.
+func (op *AlterColumnDefaultOperation) Accept(visitor Visitor) error {
+	return op.Column.Accept(visitor)
+}
+
+// alterOperation implements the marker method for type safety.
+func (op *AlterColumnDefaultOperation) alterOperation() {}
+
 // AddConstraintOperation represents an ADD CONSTRAINT operation in ALTER TABLE statements.
 //
 // This operation adds a new constraint to an existing table. The constraint can be
@@ -168,6 +194,24 @@ func (op *DropConstraintOperation) Accept(_visitor Visitor) error {
 // alterOperation implements the marker method for type safety.
 func (op *DropConstraintOperation) alterOperation() {}
 
+// ValidateConstraintOperation represents an ALTER TABLE ... VALIDATE CONSTRAINT
+// operation, which checks existing rows against a constraint previously added
+// NOT VALID (PostgreSQL family).
+type ValidateConstraintOperation struct {
+	// ConstraintName is the name of the constraint to validate
+	ConstraintName string
+}
+
+// Accept implements the Node interface for ValidateConstraintOperation.
+//
+// The actual rendering is handled by the visitor's VisitAlterTable method.
+func (op *ValidateConstraintOperation) Accept(_visitor Visitor) error {
+	return nil
+}
+
+// alterOperation implements the marker method for type safety.
+func (op *ValidateConstraintOperation) alterOperation() {}
+
 // RenameColumnOperation represents a RENAME COLUMN operation in ALTER TABLE statements.
 //
 // Both PostgreSQL and MySQL 8.0+/MariaDB 10.5.2+ natively support
@@ -260,3 +304,64 @@ func (op *ModifyTTLOperation) Accept(_visitor Visitor) error { return nil }
 
 // alterOperation implements the marker method for type safety.
 func (op *ModifyTTLOperation) alterOperation() {}
+
+// InheritOperation represents PostgreSQL's `ALTER TABLE x INHERIT parent` and
+// `ALTER TABLE x NO INHERIT parent`.
+//
+// Table inheritance is a PostgreSQL-only concept; other dialects emit an
+// explanatory comment and otherwise treat the operation as a no-op.
+type InheritOperation struct {
+	// Parent is the parent table name.
+	Parent string
+	// NoInherit removes the parent instead of adding it.
+	NoInherit bool
+}
+
+// Accept implements the Node interface for InheritOperation.
+//
+// The actual rendering is handled by the dialect's VisitAlterTable method.
+func (op *InheritOperation) Accept(_visitor Visitor) error { return nil }
+
+// alterOperation implements the marker method for type safety.
+func (op *InheritOperation) alterOperation() {}
+
+// SetAutoIncrementOperation represents MySQL/MariaDB's
+// `ALTER TABLE x AUTO_INCREMENT = n`, which sets the next value of the
+// table's AUTO_INCREMENT counter.
+//
+// The table counter option only exists in the MySQL family; other dialects
+// emit an explanatory comment and otherwise treat the operation as a no-op.
+type SetAutoIncrementOperation struct {
+	// Value is the new counter value.
+	Value int64
+}
+
+// Accept implements the Node interface for SetAutoIncrementOperation.
+//
+// The actual rendering is handled by the dialect's VisitAlterTable method.
+func (op *SetAutoIncrementOperation) Accept(_visitor Visitor) error { return nil }
+
+// alterOperation implements the marker method for type safety.
+func (op *SetAutoIncrementOperation) alterOperation() {}
+
+// AlterIndexVisibilityOperation represents MySQL's
+// `ALTER TABLE x ALTER INDEX i VISIBLE|INVISIBLE` (MariaDB:
+// `NOT IGNORED|IGNORED`), which toggles whether the optimizer uses an index
+// without rebuilding it.
+//
+// Invisible indexes only exist in the MySQL family; other dialects emit an
+// explanatory comment and otherwise treat the operation as a no-op.
+type AlterIndexVisibilityOperation struct {
+	// Index is the index name.
+	Index string
+	// Visible is the target visibility.
+	Visible bool
+}
+
+// Accept implements the Node interface for AlterIndexVisibilityOperation.
+//
+// The actual rendering is handled by the dialect's VisitAlterTable method.
+func (op *AlterIndexVisibilityOperation) Accept(_visitor Visitor) error { return nil }
+
+// alterOperation implements the marker method for type safety.
+func (op *AlterIndexVisibilityOperation) alterOperation() {}
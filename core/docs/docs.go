@@ -0,0 +1,178 @@
+// Package docs renders human-readable documentation from a parsed schema: a
+// Mermaid entity relationship diagram and a Markdown reference with one
+// section per table. Both are pure functions of the goschema.Database, so the
+// output can be regenerated and committed next to the migrations.
+package docs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/internal/convert/fromschema"
+	"github.com/stokaro/ptah/internal/schemaviz"
+)
+
+// GenerateMermaidERD returns a Mermaid erDiagram for db: one entity per table
+// with its column types and names, PK and FK markers, and one relationship
+// per foreign key. Foreign keys come from field foreign references,
+// relation-mode embedded fields, and FOREIGN KEY constraints.
+func GenerateMermaidERD(db *goschema.Database) (string, error) {
+	if db == nil {
+		return "", errors.New("docs: schema is required")
+	}
+	rendered, err := schemaviz.Render(db, schemaviz.Options{
+		Format:         schemaviz.FormatMermaid,
+		IncludeColumns: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("docs: %w", err)
+	}
+	return string(rendered), nil
+}
+
+// GenerateMarkdown returns a Markdown reference for db. It starts with the
+// GenerateMermaidERD diagram in a mermaid code block, followed by one section
+// per table listing its comment, columns, indexes, and enum values.
+func GenerateMarkdown(db *goschema.Database) (string, error) {
+	erd, err := GenerateMermaidERD(db)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("# Database Schema\n\n")
+	b.WriteString("## Entity Relationship Diagram\n\n")
+	b.WriteString("```mermaid\n" + erd + "```\n")
+	fields := fieldsByTable(db)
+	indexes := indexesByTable(db)
+	for _, table := range db.Tables {
+		name := table.QualifiedName()
+		writeTableSection(&b, table, fields[name], indexes[name])
+	}
+	return b.String(), nil
+}
+
+func writeTableSection(b *strings.Builder, table goschema.Table, fields []goschema.Field, indexes []goschema.Index) {
+	fmt.Fprintf(b, "\n## %s\n", table.QualifiedName())
+	if table.Comment != "" {
+		fmt.Fprintf(b, "\n%s\n", table.Comment)
+	}
+
+	b.WriteString("\n| Column | Type | Nullable | Default | Key | Comment |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, field := range fields {
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s |\n",
+			cell(field.Name), cell(field.Type), nullable(field), cell(defaultValue(field)), cell(keys(field)), cell(field.Comment))
+	}
+
+	if len(indexes) > 0 {
+		b.WriteString("\n### Indexes\n\n")
+		b.WriteString("| Index | Columns | Unique | Comment |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, index := range indexes {
+			fmt.Fprintf(b, "| %s | %s | %s | %s |\n",
+				cell(index.Name), cell(strings.Join(index.Fields, ", ")), yesNo(index.Unique), cell(index.Comment))
+		}
+	}
+
+	var enums []goschema.Field
+	for _, field := range fields {
+		if len(field.Enum) > 0 {
+			enums = append(enums, field)
+		}
+	}
+	if len(enums) > 0 {
+		b.WriteString("\n### Enums\n\n")
+		b.WriteString("| Column | Values |\n")
+		b.WriteString("| --- | --- |\n")
+		for _, field := range enums {
+			fmt.Fprintf(b, "| %s | %s |\n", cell(field.Name), cell(strings.Join(field.Enum, ", ")))
+		}
+	}
+}
+
+// fieldsByTable groups the columns of every table by qualified table name,
+// with embedded fields expanded the way the migration planner expands them.
+func fieldsByTable(db *goschema.Database) map[string][]goschema.Field {
+	tables := make(map[string]string, len(db.Tables))
+	for _, table := range db.Tables {
+		tables[table.StructName] = table.QualifiedName()
+	}
+	grouped := make(map[string][]goschema.Field)
+	seen := make(map[string]bool)
+	for _, field := range fromschema.ProcessEmbeddedFields(db.EmbeddedFields, db.Fields) {
+		table, ok := tables[field.StructName]
+		if !ok || seen[table+"."+field.Name] {
+			continue
+		}
+		seen[table+"."+field.Name] = true
+		grouped[table] = append(grouped[table], field)
+	}
+	return grouped
+}
+
+// indexesByTable groups indexes by qualified table name. An index names its
+// table explicitly or belongs to the table of the struct it is declared on.
+func indexesByTable(db *goschema.Database) map[string][]goschema.Index {
+	byStruct := make(map[string]string, len(db.Tables))
+	byName := make(map[string]string, 2*len(db.Tables))
+	for _, table := range db.Tables {
+		byStruct[table.StructName] = table.QualifiedName()
+		byName[table.Name] = table.QualifiedName()
+		byName[table.QualifiedName()] = table.QualifiedName()
+	}
+	grouped := make(map[string][]goschema.Index)
+	for _, index := range db.Indexes {
+		table, ok := byName[index.TableName]
+		if !ok {
+			table, ok = byStruct[index.StructName]
+		}
+		if ok {
+			grouped[table] = append(grouped[table], index)
+		}
+	}
+	return grouped
+}
+
+func keys(field goschema.Field) string {
+	var parts []string
+	if field.Primary {
+		parts = append(parts, "PK")
+	}
+	if field.Foreign != "" {
+		parts = append(parts, "FK "+field.Foreign)
+	}
+	if field.Unique {
+		parts = append(parts, "UNIQUE")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func defaultValue(field goschema.Field) string {
+	switch {
+	case field.DefaultExpr != "":
+		return field.DefaultExpr
+	case field.DefaultSet || field.Default != "":
+		return "'" + field.Default + "'"
+	default:
+		return ""
+	}
+}
+
+func nullable(field goschema.Field) string {
+	return yesNo(field.Nullable && !field.Primary)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// cell escapes value for use in a Markdown table cell.
+func cell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
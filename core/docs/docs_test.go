@@ -0,0 +1,88 @@
+package docs_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/docs"
+	"github.com/stokaro/ptah/core/goschema"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+const embeddedFieldsFixture = "../../integration/fixtures/entities/013-embedded-fields"
+
+// assertGolden compares got with testdata/name, rewriting the file first
+// under -update.
+func assertGolden(c *qt.C, name, got string) {
+	c.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		c.Assert(os.WriteFile(path, []byte(got), 0o644), qt.IsNil)
+	}
+	want, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, string(want))
+}
+
+func TestGoldenEmbeddedFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		golden   string
+		generate func(*goschema.Database) (string, error)
+	}{
+		{name: "mermaid ERD", golden: "embedded_fields.mmd", generate: docs.GenerateMermaidERD},
+		{name: "markdown", golden: "embedded_fields.md", generate: docs.GenerateMarkdown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			db, err := goschema.ParseDir(embeddedFieldsFixture)
+			c.Assert(err, qt.IsNil)
+
+			got, err := tt.generate(db)
+
+			c.Assert(err, qt.IsNil)
+			assertGolden(c, tt.golden, got)
+		})
+	}
+}
+
+func TestGenerateMarkdown_IndexesAndComments(t *testing.T) {
+	c := qt.New(t)
+	db, err := goschema.ParseSource("models.go", `package models
+
+//migrator:schema:table name="users" comment="Registered accounts"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="VARCHAR(255)" not_null="true" comment="Login | contact address"
+	//migrator:schema:index name="idx_users_email" fields="email" unique="true"
+	Email string
+}
+`)
+	c.Assert(err, qt.IsNil)
+
+	got, err := docs.GenerateMarkdown(&db)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Contains, "## users\n\nRegistered accounts\n")
+	c.Assert(got, qt.Contains, "| email | VARCHAR(255) | no |  |  | Login \\| contact address |\n")
+	c.Assert(got, qt.Contains, "### Indexes\n\n| Index | Columns | Unique | Comment |\n| --- | --- | --- | --- |\n| idx_users_email | email | yes |  |\n")
+}
+
+func TestGenerateMermaidERD_NilSchema(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := docs.GenerateMermaidERD(nil)
+	c.Assert(err, qt.ErrorMatches, "docs: schema is required")
+
+	_, err = docs.GenerateMarkdown(nil)
+	c.Assert(err, qt.ErrorMatches, "docs: schema is required")
+}
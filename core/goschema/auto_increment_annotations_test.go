@@ -0,0 +1,56 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/ptaherr"
+)
+
+func TestParseTableAutoIncrementAnnotation(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="orders" auto_increment="1000"
+type Order struct {
+	//migrator:schema:field name="id" type="INT" primary="true" auto_increment="true"
+	ID int
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Tables, qt.HasLen, 1)
+	c.Assert(db.Tables[0].AutoIncrement, qt.Equals, "1000")
+}
+
+func TestParseTableAutoIncrementRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "not a number", value: "abc"},
+		{name: "zero", value: "0"},
+		{name: "negative", value: "-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			src := `package fixture
+
+//migrator:schema:table name="orders" auto_increment="` + tt.value + `"
+type Order struct {
+	//migrator:schema:field name="id" type="INT" primary="true"
+	ID int
+}
+`
+			_, err := goschema.ParseSource("fixture.go", src)
+
+			var parseErr *ptaherr.ParseError
+			c.Assert(err, qt.ErrorAs, &parseErr)
+			c.Assert(parseErr.Attribute, qt.Equals, "auto_increment")
+			c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/ptaherr"
+)
+
+const embeddedRelationUsers = `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="BIGSERIAL" primary="true"
+	ID int64
+}
+
+//migrator:schema:table name="accounts"
+type Account struct {
+	//migrator:schema:field name="id" type="UUID" primary="true" platform.mysql.type="CHAR(36)"
+	ID string
+}
+
+//migrator:schema:table name="tenants"
+type Tenant struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+
+//migrator:schema:table name="memberships" primary_key="tenant_id,user_id"
+type Membership struct {
+	//migrator:schema:field name="tenant_id" type="INTEGER"
+	TenantID int64
+
+	//migrator:schema:field name="user_id" type="BIGINT"
+	UserID int64
+}
+`
+
+func TestParseSources_EmbeddedRelationUsesReferencedColumnType(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotation    string
+		wantType      string
+		wantOverrides map[string]map[string]string
+	}{
+		{
+			name:          "bigserial primary key",
+			annotation:    `//migrator:embedded mode="relation" field="owner_id" ref="users(id)"`,
+			wantType:      "BIGINT",
+			wantOverrides: map[string]map[string]string{},
+		},
+		{
+			name:          "uuid primary key through a table-only ref",
+			annotation:    `//migrator:embedded mode="relation" field="owner_id" ref="accounts"`,
+			wantType:      "UUID",
+			wantOverrides: map[string]map[string]string{"mysql": {"type": "CHAR(36)"}},
+		},
+		{
+			name:       "serial primary key keeps the MySQL INT override",
+			annotation: `//migrator:embedded mode="relation" field="owner_id" ref="tenants(id)"`,
+			wantType:   "INTEGER",
+			wantOverrides: map[string]map[string]string{
+				"mysql":   {"type": "INT"},
+				"mariadb": {"type": "INT"},
+			},
+		},
+		{
+			name:          "explicit type wins",
+			annotation:    `//migrator:embedded mode="relation" field="owner_id" ref="accounts(id)" type="VARCHAR(36)"`,
+			wantType:      "VARCHAR(36)",
+			wantOverrides: map[string]map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			source := `package entities
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	` + tt.annotation + `
+	Owner User
+}
+`
+
+			database, err := goschema.ParseSources(map[string][]byte{
+				"users.go": []byte(embeddedRelationUsers),
+				"posts.go": []byte(source),
+			})
+			c.Assert(err, qt.IsNil)
+
+			owner := findParsedField(database.Fields, "Post", "owner_id")
+			c.Assert(owner, qt.IsNotNil)
+			c.Assert(owner.Type, qt.Equals, tt.wantType)
+			c.Assert(owner.Overrides, qt.DeepEquals, tt.wantOverrides)
+		})
+	}
+}
+
+func findParsedField(fields []goschema.Field, structName, name string) *goschema.Field {
+	for i, field := range fields {
+		if field.StructName == structName && field.Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func TestParseSources_EmbeddedRelationRejectsCompositeRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		wantError  string
+	}{
+		{
+			name:       "ref names two columns",
+			annotation: `//migrator:embedded mode="relation" field="member_id" ref="memberships(tenant_id, user_id)"`,
+			wantError:  `invalid ref "memberships\(tenant_id, user_id\)" on //migrator:embedded at Post: it names 2 columns, .*`,
+		},
+		{
+			name:       "table-only ref to a composite primary key",
+			annotation: `//migrator:embedded mode="relation" field="member_id" ref="memberships"`,
+			wantError:  `invalid ref "memberships" on //migrator:embedded at Post: it points at the composite primary key of memberships, .*`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			source := `package entities
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	` + tt.annotation + `
+	Member Membership
+}
+`
+
+			_, err := goschema.ParseSources(map[string][]byte{
+				"users.go": []byte(embeddedRelationUsers),
+				"posts.go": []byte(source),
+			})
+
+			c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+			c.Assert(err, qt.ErrorMatches, tt.wantError)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/ptaherr"
+)
+
+func TestParseExcludeAnnotation(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="bookings"
+type Booking struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="room_id" type="INTEGER"
+	RoomID int64
+
+	//migrator:schema:exclude name="no_overlapping_bookings" expressions="room_id, tsrange(starts_at, ends_at)" operators="=, &&" where="NOT cancelled"
+	_ int
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Constraints, qt.HasLen, 1)
+	constraint := db.Constraints[0]
+	c.Assert(constraint.Name, qt.Equals, "no_overlapping_bookings")
+	c.Assert(constraint.Type, qt.Equals, "EXCLUDE")
+	c.Assert(constraint.Table, qt.Equals, "bookings")
+	c.Assert(constraint.UsingMethod, qt.Equals, "gist")
+	c.Assert(constraint.ExcludeElements, qt.Equals, "room_id WITH =, tsrange(starts_at, ends_at) WITH &&")
+	c.Assert(constraint.WhereCondition, qt.Equals, "NOT cancelled")
+}
+
+func TestParseExcludeAnnotation_ExplicitMethod(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="reservations"
+//migrator:schema:exclude name="one_active_per_user" method="BTREE" expressions="user_id" operators="="
+type Reservation struct {
+	//migrator:schema:field name="user_id" type="INTEGER"
+	UserID int64
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Constraints, qt.HasLen, 1)
+	c.Assert(db.Constraints[0].UsingMethod, qt.Equals, "btree")
+	c.Assert(db.Constraints[0].ExcludeElements, qt.Equals, "user_id WITH =")
+}
+
+func TestParseExcludeAnnotation_OperatorCountMismatch(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="bookings"
+//migrator:schema:exclude name="no_overlap" expressions="room_id, during" operators="="
+type Booking struct {
+	//migrator:schema:field name="room_id" type="INTEGER"
+	RoomID int64
+}
+`
+	c := qt.New(t)
+	_, err := goschema.ParseSource("fixture.go", src)
+	var parseErr *ptaherr.ParseError
+	c.Assert(err, qt.ErrorAs, &parseErr)
+	c.Assert(parseErr.Directive, qt.Equals, "migrator:schema:exclude")
+	c.Assert(parseErr.Attribute, qt.Equals, "operators")
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+	c.Assert(err, qt.ErrorMatches, `.*declares 2 expressions but 1 operators`)
+}
+
+func TestParseExcludeAnnotation_RequiresOperators(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="bookings"
+//migrator:schema:exclude name="no_overlap" expressions="room_id"
+type Booking struct {
+	//migrator:schema:field name="room_id" type="INTEGER"
+	RoomID int64
+}
+`
+	c := qt.New(t)
+	_, err := goschema.ParseSource("fixture.go", src)
+	c.Assert(err, qt.ErrorMatches, `.*missing required annotation attribute "operators".*`)
+}
@@ -0,0 +1,22 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseTableExternalColumnsAnnotation(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="articles" external_columns="search_vector, audit_hash"
+type Article struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Tables, qt.HasLen, 1)
+	c.Assert(db.Tables[0].ExternalColumns, qt.DeepEquals, []string{"search_vector", "audit_hash"})
+}
@@ -0,0 +1,143 @@
+package goschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Fingerprint returns a stable SHA-256 hex digest of the schema contents of
+// db: tables, columns, indexes, constraints, enums, functions, policies and
+// every other declared object. The digest ignores declaration order and
+// Go-only naming (struct and field identifiers), so reordering declarations
+// or renaming a struct leaves it unchanged.
+//
+// Equal fingerprints mean equal normalized contents, so comparing either
+// schema against the same database yields the same diff. Use it as a cheap
+// "has anything changed?" gate before the full comparison or to record
+// drift over time; a collision is astronomically unlikely, but the schema
+// diff stays authoritative. Different fingerprints do not guarantee a
+// non-empty diff, since the comparison also treats spellings such as type
+// aliases as equal.
+func Fingerprint(db *Database) string {
+	canonical := canonicalDatabase(db)
+	payload, err := json.Marshal(canonical)
+	if err != nil {
+		// Every schema value is a plain string, number, bool, slice or map,
+		// so encoding cannot fail; fall back to the Go syntax representation
+		// to stay total.
+		payload = fmt.Appendf(nil, "%#v", canonical)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalDatabase returns a copy of db with struct names resolved to table
+// names, Go field identifiers dropped, column types normalized, and every
+// object list sorted. Dependency maps are derived from the fields and are
+// left out.
+func canonicalDatabase(db *Database) Database {
+	if db == nil {
+		return Database{}
+	}
+	tables := make(map[string]string, len(db.Tables))
+	for _, table := range db.Tables {
+		tables[table.StructName] = table.QualifiedName()
+	}
+
+	canonical := Database{
+		Schemas:           slices.Clone(db.Schemas),
+		Tables:            slices.Clone(db.Tables),
+		Fields:            slices.Clone(db.Fields),
+		Indexes:           slices.Clone(db.Indexes),
+		Constraints:       slices.Clone(db.Constraints),
+		Enums:             slices.Clone(db.Enums),
+		EmbeddedFields:    slices.Clone(db.EmbeddedFields),
+		Extensions:        slices.Clone(db.Extensions),
+		Functions:         slices.Clone(db.Functions),
+		Sequences:         slices.Clone(db.Sequences),
+		Domains:           slices.Clone(db.Domains),
+		CompositeTypes:    slices.Clone(db.CompositeTypes),
+		Ranges:            slices.Clone(db.Ranges),
+		Views:             slices.Clone(db.Views),
+		MaterializedViews: slices.Clone(db.MaterializedViews),
+		Triggers:          slices.Clone(db.Triggers),
+		RLSPolicies:       slices.Clone(db.RLSPolicies),
+		RLSEnabledTables:  slices.Clone(db.RLSEnabledTables),
+		Roles:             slices.Clone(db.Roles),
+		Grants:            slices.Clone(db.Grants),
+		Seeds:             slices.Clone(db.Seeds),
+	}
+	for i := range canonical.Fields {
+		canonical.Fields[i].FieldName = ""
+		canonical.Fields[i].Type = normalizeFingerprintType(canonical.Fields[i].Type)
+	}
+	for i := range canonical.Indexes {
+		if canonical.Indexes[i].TableName == "" {
+			canonical.Indexes[i].TableName = tables[canonical.Indexes[i].StructName]
+		}
+	}
+
+	value := reflect.ValueOf(&canonical).Elem()
+	for i := range value.NumField() {
+		field := value.Field(i)
+		if !field.CanSet() || field.Kind() != reflect.Slice {
+			continue
+		}
+		resolveStructNames(field, tables)
+		sortByEncoding(field)
+	}
+	return canonical
+}
+
+// resolveStructNames replaces the StructName of every element in list with
+// the table the struct maps to, so a renamed Go struct fingerprints the same.
+func resolveStructNames(list reflect.Value, tables map[string]string) {
+	for i := range list.Len() {
+		structName := list.Index(i).FieldByName("StructName")
+		if !structName.IsValid() || structName.Kind() != reflect.String {
+			continue
+		}
+		if table, ok := tables[structName.String()]; ok {
+			structName.SetString(table)
+		}
+	}
+}
+
+// sortByEncoding orders list by the JSON encoding of its elements, which is
+// a total order over the normalized contents.
+func sortByEncoding(list reflect.Value) {
+	if list.Len() < 2 {
+		return
+	}
+	keys := make([][]byte, list.Len())
+	for i := range keys {
+		keys[i], _ = json.Marshal(list.Index(i).Interface())
+	}
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return bytes.Compare(keys[a], keys[b]) })
+
+	sorted := reflect.MakeSlice(list.Type(), list.Len(), list.Len())
+	for i, from := range order {
+		sorted.Index(i).Set(list.Index(from))
+	}
+	list.Set(sorted)
+}
+
+// normalizeFingerprintType collapses whitespace and upper-cases a column type.
+// Types carrying quoted literals, such as inline ENUM values, keep their case.
+func normalizeFingerprintType(typ string) string {
+	typ = strings.Join(strings.Fields(typ), " ")
+	if strings.ContainsAny(typ, `'"`) {
+		return typ
+	}
+	return strings.ToUpper(typ)
+}
@@ -0,0 +1,142 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+)
+
+const fingerprintSource = `package models
+
+//migrator:schema:table name="users"
+//migrator:schema:rls:policy name="tenant_isolation" table="users" for="ALL" to="PUBLIC" using="tenant_id = current_setting('app.tenant')"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="VARCHAR(255)" not_null="true"
+	//migrator:schema:index name="idx_users_email" fields="email" unique="true"
+	Email string
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="status" type="ENUM('draft','published')"
+	Status string
+}
+`
+
+func TestFingerprintIgnoresDeclarationOrderAndGoNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{
+			name: "structs declared in another order",
+			source: `package models
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="status" type="ENUM('draft','published')"
+	Status string
+}
+
+//migrator:schema:table name="users"
+//migrator:schema:rls:policy name="tenant_isolation" table="users" for="ALL" to="PUBLIC" using="tenant_id = current_setting('app.tenant')"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="VARCHAR(255)" not_null="true"
+	//migrator:schema:index name="idx_users_email" fields="email" unique="true"
+	Email string
+}
+`,
+		},
+		{
+			name: "renamed Go struct and field with differently cased type",
+			source: `package models
+
+//migrator:schema:table name="users"
+//migrator:schema:rls:policy name="tenant_isolation" table="users" for="ALL" to="PUBLIC" using="tenant_id = current_setting('app.tenant')"
+type Account struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	AccountID int
+
+	//migrator:schema:field name="email" type="varchar(255)" not_null="true"
+	//migrator:schema:index name="idx_users_email" fields="email" unique="true"
+	Mail string
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="status" type="ENUM('draft','published')"
+	Status string
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			base := mustParseSource(c, "models.go", fingerprintSource)
+			other := mustParseSource(c, "models.go", tt.source)
+
+			c.Assert(goschema.Fingerprint(&other), qt.Equals, goschema.Fingerprint(&base))
+		})
+	}
+}
+
+func TestFingerprintDetectsSchemaChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*goschema.Database)
+	}{
+		{name: "column type", mutate: func(db *goschema.Database) { db.Fields[1].Type = "VARCHAR(320)" }},
+		{name: "column nullability", mutate: func(db *goschema.Database) { db.Fields[1].Nullable = true }},
+		{name: "enum value case", mutate: func(db *goschema.Database) { db.Fields[3].Type = "ENUM('Draft','published')" }},
+		{name: "index uniqueness", mutate: func(db *goschema.Database) { db.Indexes[0].Unique = false }},
+		{name: "policy expression", mutate: func(db *goschema.Database) { db.RLSPolicies[0].UsingExpression = "true" }},
+		{name: "dropped table", mutate: func(db *goschema.Database) { db.Tables = db.Tables[:1] }},
+		{name: "added function", mutate: func(db *goschema.Database) {
+			db.Functions = append(db.Functions, goschema.Function{Name: "now_utc", Returns: "TIMESTAMPTZ", Language: "sql", Body: "SELECT now()"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			base := mustParseSource(c, "models.go", fingerprintSource)
+			changed := mustParseSource(c, "models.go", fingerprintSource)
+
+			tt.mutate(&changed)
+
+			c.Assert(goschema.Fingerprint(&changed), qt.Not(qt.Equals), goschema.Fingerprint(&base))
+		})
+	}
+}
+
+func TestFingerprintIsStableAndDoesNotMutateInput(t *testing.T) {
+	c := qt.New(t)
+	db := mustParseSource(c, "models.go", fingerprintSource)
+	fields := append([]goschema.Field(nil), db.Fields...)
+
+	first := goschema.Fingerprint(&db)
+
+	c.Assert(first, qt.HasLen, 64)
+	c.Assert(goschema.Fingerprint(&db), qt.Equals, first)
+	c.Assert(db.Fields, qt.DeepEquals, fields)
+	c.Assert(goschema.Fingerprint(nil), qt.Equals, goschema.Fingerprint(&goschema.Database{}))
+}
@@ -0,0 +1,313 @@
+package goschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphNodeKind identifies the schema object type represented by a GraphNode.
+type GraphNodeKind string
+
+const (
+	// GraphNodeTable represents a table.
+	GraphNodeTable GraphNodeKind = "table"
+	// GraphNodeEnum represents an enum type.
+	GraphNodeEnum GraphNodeKind = "enum"
+	// GraphNodeFunction represents a PostgreSQL function.
+	GraphNodeFunction GraphNodeKind = "function"
+	// GraphNodePolicy represents a PostgreSQL row-level security policy.
+	GraphNodePolicy GraphNodeKind = "policy"
+)
+
+// GraphEdgeKind identifies why one schema object depends on another.
+type GraphEdgeKind string
+
+const (
+	// GraphEdgeForeignKey links a table to the table its foreign key references.
+	GraphEdgeForeignKey GraphEdgeKind = "foreign_key"
+	// GraphEdgeEnumType links a table to an enum type used by one of its columns.
+	GraphEdgeEnumType GraphEdgeKind = "enum_type"
+	// GraphEdgePolicyFunction links a policy to a function called from its
+	// USING or WITH CHECK expression.
+	GraphEdgePolicyFunction GraphEdgeKind = "policy_function"
+	// GraphEdgePolicyTable links a policy to the table it is attached to.
+	GraphEdgePolicyTable GraphEdgeKind = "policy_table"
+)
+
+// GraphNode is one schema object in a dependency graph.
+type GraphNode struct {
+	ID   string        // Stable identifier, e.g. "table:public.users"
+	Kind GraphNodeKind // Object type
+	Name string        // Display name, e.g. "public.users" or "users.tenant_isolation"
+}
+
+// GraphEdge records that From depends on To.
+type GraphEdge struct {
+	From  string        // ID of the dependent node
+	To    string        // ID of the node it depends on
+	Kind  GraphEdgeKind // Dependency reason
+	Label string        // Optional detail, such as a constraint or column name
+}
+
+// Graph is a dependency graph of schema objects built from a parsed Database.
+//
+// Nodes are sorted by kind (tables, enums, functions, policies) and then by
+// name; edges are sorted by their endpoints and kind. The ordering is stable
+// across runs so rendered output can be committed and diffed.
+type Graph struct {
+	Nodes []GraphNode
+	Edges []GraphEdge
+}
+
+// BuildDependencyGraph returns the dependency graph of tables, enums,
+// functions, and RLS policies declared in db.
+//
+// Edges point from the dependent object to its dependency:
+//   - table -> table for foreign keys declared on fields or table constraints
+//   - table -> enum for columns whose type is a declared enum
+//   - policy -> function for functions called from USING / WITH CHECK
+//   - policy -> table for the table the policy is attached to
+//
+// References to objects that are not declared in db are ignored.
+func BuildDependencyGraph(db *Database) *Graph {
+	builder := newGraphBuilder()
+	if db == nil {
+		return builder.graph()
+	}
+
+	for _, table := range db.Tables {
+		builder.addNode(GraphNodeTable, table.QualifiedName())
+	}
+	for _, enum := range db.Enums {
+		builder.addNode(GraphNodeEnum, enum.Name)
+	}
+	for _, function := range db.Functions {
+		builder.addNode(GraphNodeFunction, function.Name)
+	}
+	for _, policy := range db.RLSPolicies {
+		builder.addNode(GraphNodePolicy, policyNodeName(policy))
+	}
+
+	addFieldGraphEdges(builder, db)
+	addConstraintGraphEdges(builder, db)
+	addPolicyGraphEdges(builder, db)
+
+	return builder.graph()
+}
+
+func addFieldGraphEdges(builder *graphBuilder, db *Database) {
+	enumNames := make(map[string]string, len(db.Enums))
+	for _, enum := range db.Enums {
+		enumNames[strings.ToLower(enum.Name)] = enum.Name
+	}
+	for _, field := range db.Fields {
+		table := findTableByStructName(db.Tables, field.StructName)
+		if table == nil {
+			continue
+		}
+		tableName := table.QualifiedName()
+		if enumName, ok := enumNames[strings.ToLower(strings.TrimSpace(field.Type))]; ok {
+			builder.addEdge(GraphNodeTable, tableName, GraphNodeEnum, enumName, GraphEdgeEnumType, field.Name)
+		}
+		if field.Foreign == "" {
+			continue
+		}
+		refTable := resolveReferenceTableName(db.Tables, *table, strings.TrimSpace(strings.Split(field.Foreign, "(")[0]))
+		label := field.ForeignKeyName
+		if label == "" {
+			label = field.Name
+		}
+		builder.addEdge(GraphNodeTable, tableName, GraphNodeTable, refTable, GraphEdgeForeignKey, label)
+	}
+}
+
+func addConstraintGraphEdges(builder *graphBuilder, db *Database) {
+	for _, constraint := range db.Constraints {
+		if constraint.ForeignTable == "" || !strings.EqualFold(constraint.Type, "FOREIGN KEY") {
+			continue
+		}
+		table := resolveTableReference(db.Tables, constraint.StructName, constraint.Table)
+		if table == nil {
+			continue
+		}
+		refTable := resolveReferenceTableName(db.Tables, *table, constraint.ForeignTable)
+		builder.addEdge(GraphNodeTable, table.QualifiedName(), GraphNodeTable, refTable, GraphEdgeForeignKey, constraint.Name)
+	}
+}
+
+func addPolicyGraphEdges(builder *graphBuilder, db *Database) {
+	for _, policy := range db.RLSPolicies {
+		policyName := policyNodeName(policy)
+		tableName := policy.Table
+		if table := resolveTableReference(db.Tables, policy.StructName, policy.Table); table != nil {
+			tableName = table.QualifiedName()
+		}
+		builder.addEdge(GraphNodePolicy, policyName, GraphNodeTable, tableName, GraphEdgePolicyTable, "")
+
+		expressions := policy.UsingExpression + "\n" + policy.WithCheckExpression
+		for _, function := range db.Functions {
+			if getCachedRegex(function.Name).FindStringIndex(expressions) != nil {
+				builder.addEdge(GraphNodePolicy, policyName, GraphNodeFunction, function.Name, GraphEdgePolicyFunction, "")
+			}
+		}
+	}
+}
+
+func policyNodeName(policy RLSPolicy) string {
+	if policy.Table == "" {
+		return policy.Name
+	}
+	return policy.Table + "." + policy.Name
+}
+
+func graphNodeID(kind GraphNodeKind, name string) string {
+	return string(kind) + ":" + name
+}
+
+type graphBuilder struct {
+	nodes map[string]GraphNode
+	edges map[GraphEdge]struct{}
+}
+
+func newGraphBuilder() *graphBuilder {
+	return &graphBuilder{
+		nodes: make(map[string]GraphNode),
+		edges: make(map[GraphEdge]struct{}),
+	}
+}
+
+func (b *graphBuilder) addNode(kind GraphNodeKind, name string) {
+	if name == "" {
+		return
+	}
+	id := graphNodeID(kind, name)
+	b.nodes[id] = GraphNode{ID: id, Kind: kind, Name: name}
+}
+
+// addEdge records an edge only when both endpoints are declared nodes, so
+// references to objects managed outside the schema do not create dangling
+// edges.
+func (b *graphBuilder) addEdge(fromKind GraphNodeKind, from string, toKind GraphNodeKind, to string, kind GraphEdgeKind, label string) {
+	fromID := graphNodeID(fromKind, from)
+	toID := graphNodeID(toKind, to)
+	if _, ok := b.nodes[fromID]; !ok {
+		return
+	}
+	if _, ok := b.nodes[toID]; !ok {
+		return
+	}
+	b.edges[GraphEdge{From: fromID, To: toID, Kind: kind, Label: label}] = struct{}{}
+}
+
+var graphNodeKindOrder = map[GraphNodeKind]int{
+	GraphNodeTable:    0,
+	GraphNodeEnum:     1,
+	GraphNodeFunction: 2,
+	GraphNodePolicy:   3,
+}
+
+func (b *graphBuilder) graph() *Graph {
+	graph := &Graph{
+		Nodes: make([]GraphNode, 0, len(b.nodes)),
+		Edges: make([]GraphEdge, 0, len(b.edges)),
+	}
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		left, right := graph.Nodes[i], graph.Nodes[j]
+		if left.Kind != right.Kind {
+			return graphNodeKindOrder[left.Kind] < graphNodeKindOrder[right.Kind]
+		}
+		return left.Name < right.Name
+	})
+	for edge := range b.edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		left, right := graph.Edges[i], graph.Edges[j]
+		if left.From != right.From {
+			return left.From < right.From
+		}
+		if left.To != right.To {
+			return left.To < right.To
+		}
+		if left.Kind != right.Kind {
+			return left.Kind < right.Kind
+		}
+		return left.Label < right.Label
+	})
+	return graph
+}
+
+var graphDOTShapes = map[GraphNodeKind]string{
+	GraphNodeTable:    "box",
+	GraphNodeEnum:     "hexagon",
+	GraphNodeFunction: "ellipse",
+	GraphNodePolicy:   "note",
+}
+
+// ToDOT renders the graph in Graphviz DOT format.
+func (g *Graph) ToDOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph ptah_dependencies {\n")
+	builder.WriteString("  rankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(&builder, "  %s [label=%s, shape=%s];\n", dotQuote(node.ID), dotQuote(node.Name), graphDOTShapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		label := string(edge.Kind)
+		if edge.Label != "" {
+			label += ": " + edge.Label
+		}
+		fmt.Fprintf(&builder, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(label))
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// ToMermaid renders the graph as a Mermaid flowchart. Node identifiers are
+// positional (n0, n1, ...) because schema-qualified names are not valid
+// Mermaid identifiers; the object names are kept as node labels.
+func (g *Graph) ToMermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+	for i, node := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[node.ID] = id
+		fmt.Fprintf(&builder, "  %s%s\n", id, mermaidNodeShape(node))
+	}
+	for _, edge := range g.Edges {
+		label := string(edge.Kind)
+		if edge.Label != "" {
+			label += ": " + edge.Label
+		}
+		fmt.Fprintf(&builder, "  %s -->|%s| %s\n", ids[edge.From], mermaidQuote(label), ids[edge.To])
+	}
+	return builder.String()
+}
+
+func mermaidNodeShape(node GraphNode) string {
+	label := mermaidQuote(string(node.Kind) + ": " + node.Name)
+	switch node.Kind {
+	case GraphNodeEnum:
+		return "{{" + label + "}}"
+	case GraphNodeFunction:
+		return "([" + label + "])"
+	case GraphNodePolicy:
+		return ">" + label + "]"
+	default:
+		return "[" + label + "]"
+	}
+}
+
+func mermaidQuote(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "#quot;") + `"`
+}
+
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
@@ -0,0 +1,146 @@
+package goschema_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+)
+
+const graphFixtureSource = `package entities
+
+//migrator:schema:function name="current_tenant_id" returns="TEXT" language="sql" volatility="STABLE" body="SELECT current_setting('app.tenant_id')"
+//migrator:schema:function name="unused_helper" returns="INTEGER" language="sql" body="SELECT 1"
+type Functions struct{}
+
+//migrator:schema:table name="tenants"
+type Tenant struct {
+	//migrator:schema:field name="id" type="TEXT" primary="true"
+	ID string
+}
+
+//migrator:schema:table name="users"
+//migrator:schema:rls:enable table="users"
+//migrator:schema:rls:policy name="users_tenant_isolation" table="users" for="ALL" to="app_user" using="tenant_id = current_tenant_id()"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="tenant_id" type="TEXT" not_null="true" foreign="tenants(id)" foreign_key_name="fk_users_tenant"
+	TenantID string
+
+	//migrator:schema:field name="status" type="ENUM" enum="active,disabled" not_null="true"
+	Status string
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="author_id" type="INTEGER" not_null="true"
+	AuthorID int64
+
+	//migrator:schema:field name="parent_id" type="INTEGER" foreign="posts(id)"
+	ParentID int64
+
+	//migrator:schema:constraint name="fk_posts_author" type="FOREIGN KEY" columns="author_id" foreign_table="users" foreign_column="id"
+	_ int
+}
+`
+
+func parseGraphFixture(c *qt.C) *goschema.Database {
+	fsys := fstest.MapFS{"entities/models.go": {Data: []byte(graphFixtureSource)}}
+	db, err := goschema.ParseFS(fsys, "entities")
+	c.Assert(err, qt.IsNil)
+	return db
+}
+
+func TestBuildDependencyGraph_Nodes(t *testing.T) {
+	c := qt.New(t)
+
+	graph := goschema.BuildDependencyGraph(parseGraphFixture(c))
+
+	c.Assert(graph.Nodes, qt.DeepEquals, []goschema.GraphNode{
+		{ID: "table:posts", Kind: goschema.GraphNodeTable, Name: "posts"},
+		{ID: "table:tenants", Kind: goschema.GraphNodeTable, Name: "tenants"},
+		{ID: "table:users", Kind: goschema.GraphNodeTable, Name: "users"},
+		{ID: "enum:enum_user_status", Kind: goschema.GraphNodeEnum, Name: "enum_user_status"},
+		{ID: "function:current_tenant_id", Kind: goschema.GraphNodeFunction, Name: "current_tenant_id"},
+		{ID: "function:unused_helper", Kind: goschema.GraphNodeFunction, Name: "unused_helper"},
+		{ID: "policy:users.users_tenant_isolation", Kind: goschema.GraphNodePolicy, Name: "users.users_tenant_isolation"},
+	})
+}
+
+func TestBuildDependencyGraph_Edges(t *testing.T) {
+	c := qt.New(t)
+
+	graph := goschema.BuildDependencyGraph(parseGraphFixture(c))
+
+	c.Assert(graph.Edges, qt.DeepEquals, []goschema.GraphEdge{
+		{From: "policy:users.users_tenant_isolation", To: "function:current_tenant_id", Kind: goschema.GraphEdgePolicyFunction},
+		{From: "policy:users.users_tenant_isolation", To: "table:users", Kind: goschema.GraphEdgePolicyTable},
+		{From: "table:posts", To: "table:posts", Kind: goschema.GraphEdgeForeignKey, Label: "parent_id"},
+		{From: "table:posts", To: "table:users", Kind: goschema.GraphEdgeForeignKey, Label: "fk_posts_author"},
+		{From: "table:users", To: "enum:enum_user_status", Kind: goschema.GraphEdgeEnumType, Label: "status"},
+		{From: "table:users", To: "table:tenants", Kind: goschema.GraphEdgeForeignKey, Label: "fk_users_tenant"},
+	})
+}
+
+func TestBuildDependencyGraph_IgnoresUndeclaredReferences(t *testing.T) {
+	c := qt.New(t)
+
+	graph := goschema.BuildDependencyGraph(&goschema.Database{
+		Tables: []goschema.Table{{StructName: "Order", Name: "orders"}},
+		Fields: []goschema.Field{{StructName: "Order", Name: "customer_id", Type: "INTEGER", Foreign: "customers(id)"}},
+	})
+
+	c.Assert(graph.Nodes, qt.HasLen, 1)
+	c.Assert(graph.Edges, qt.HasLen, 0)
+}
+
+func TestBuildDependencyGraph_NilDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	graph := goschema.BuildDependencyGraph(nil)
+
+	c.Assert(graph.Nodes, qt.HasLen, 0)
+	c.Assert(graph.Edges, qt.HasLen, 0)
+}
+
+func TestGraphToDOT(t *testing.T) {
+	c := qt.New(t)
+
+	dot := goschema.BuildDependencyGraph(parseGraphFixture(c)).ToDOT()
+
+	c.Assert(dot, qt.Contains, "digraph ptah_dependencies {\n")
+	c.Assert(dot, qt.Contains, `  "table:users" [label="users", shape=box];`)
+	c.Assert(dot, qt.Contains, `  "enum:enum_user_status" [label="enum_user_status", shape=hexagon];`)
+	c.Assert(dot, qt.Contains, `  "policy:users.users_tenant_isolation" [label="users.users_tenant_isolation", shape=note];`)
+	c.Assert(dot, qt.Contains, `  "table:users" -> "table:tenants" [label="foreign_key: fk_users_tenant"];`)
+	c.Assert(dot, qt.Contains, `  "policy:users.users_tenant_isolation" -> "function:current_tenant_id" [label="policy_function"];`)
+}
+
+func TestGraphToMermaid(t *testing.T) {
+	c := qt.New(t)
+
+	mermaid := goschema.BuildDependencyGraph(parseGraphFixture(c)).ToMermaid()
+
+	c.Assert(mermaid, qt.Equals, `flowchart LR
+  n0["table: posts"]
+  n1["table: tenants"]
+  n2["table: users"]
+  n3{{"enum: enum_user_status"}}
+  n4(["function: current_tenant_id"])
+  n5(["function: unused_helper"])
+  n6>"policy: users.users_tenant_isolation"]
+  n6 -->|"policy_function"| n4
+  n6 -->|"policy_table"| n2
+  n0 -->|"foreign_key: parent_id"| n0
+  n0 -->|"foreign_key: fk_posts_author"| n2
+  n2 -->|"enum_type: status"| n3
+  n2 -->|"foreign_key: fk_users_tenant"| n1
+`)
+}
@@ -88,3 +88,159 @@ type User struct {
 	c.Assert(idx.Granularity, qt.Equals, 0)
 	c.Assert(idx.Unique, qt.IsTrue)
 }
+
+// TestParseIndexAnnotation_Expression checks that expr= produces a single
+// expression key part while a plain fields= index on the same table keeps
+// its column list and no structured parts.
+func TestParseIndexAnnotation_Expression(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="BIGINT" primary="true"
+	ID int64
+
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email"
+	//migrator:schema:index name="idx_users_email_lower" expr="lower(email)" unique="true"
+	Email string
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Indexes, qt.HasLen, 2)
+
+	plain := db.Indexes[0]
+	c.Assert(plain.Name, qt.Equals, "idx_users_email")
+	c.Assert(plain.Fields, qt.DeepEquals, []string{"email"})
+	c.Assert(plain.Parts, qt.HasLen, 0)
+
+	expr := db.Indexes[1]
+	c.Assert(expr.Name, qt.Equals, "idx_users_email_lower")
+	c.Assert(expr.Fields, qt.DeepEquals, []string{"lower(email)"})
+	c.Assert(expr.Parts, qt.DeepEquals, []goschema.IndexPart{{Expr: "lower(email)"}})
+	c.Assert(expr.Unique, qt.IsTrue)
+}
+
+// TestParseIndexAnnotation_ExpressionWithFieldsRejected verifies that an
+// index cannot mix expr= with a column list.
+func TestParseIndexAnnotation_ExpressionWithFieldsRejected(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email_lower" fields="email" expr="lower(email)"
+	Email string
+}
+`
+	c := qt.New(t)
+	_, err := goschema.ParseSource("fixture.go", src)
+	var parseErr *ptaherr.ParseError
+	c.Assert(err, qt.ErrorAs, &parseErr)
+	c.Assert(parseErr.Attribute, qt.Equals, "expr")
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+}
+
+// TestParseIndexAnnotation_StorageParams checks that storage= is split into
+// lower-cased key=value storage parameters and that a malformed entry is a
+// parse error.
+func TestParseIndexAnnotation_StorageParams(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email" storage="FILLFACTOR=70, deduplicate_items=off"
+	Email string
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Indexes, qt.HasLen, 1)
+	c.Assert(db.Indexes[0].StorageParams, qt.DeepEquals, map[string]string{"fillfactor": "70", "deduplicate_items": "off"})
+}
+
+func TestParseIndexAnnotation_MalformedStorageParamsRejected(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email" storage="fillfactor"
+	Email string
+}
+`
+	c := qt.New(t)
+	_, err := goschema.ParseSource("fixture.go", src)
+	var parseErr *ptaherr.ParseError
+	c.Assert(err, qt.ErrorAs, &parseErr)
+	c.Assert(parseErr.Attribute, qt.Equals, "storage")
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+}
+
+func TestParseIndexAnnotation_Visible(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email" visible="false"
+	Email string
+
+	//migrator:schema:field name="name" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_name" fields="name"
+	Name string
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Indexes, qt.HasLen, 2)
+	c.Assert(db.Indexes[0].Visible, qt.IsNotNil)
+	c.Assert(*db.Indexes[0].Visible, qt.IsFalse)
+	c.Assert(db.Indexes[1].Visible, qt.IsNil)
+}
+
+func TestParseIndexAnnotation_PerColumnOperatorClasses(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="products"
+type Product struct {
+	//migrator:schema:field name="tenant" type="TEXT"
+	Tenant string
+
+	//migrator:schema:field name="name" type="TEXT"
+	Name string
+
+	//migrator:schema:index name="idx_products_search" fields="tenant,name" ops=", gin_trgm_ops" type="GIN"
+	//migrator:schema:index name="idx_products_name" fields="name" ops="gin_trgm_ops" type="GIN"
+	_ int
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Indexes, qt.HasLen, 2)
+	c.Assert(db.Indexes[0].Operator, qt.Equals, "")
+	c.Assert(db.Indexes[0].Fields, qt.DeepEquals, []string{"tenant", "name"})
+	c.Assert(db.Indexes[0].Parts, qt.DeepEquals, []goschema.IndexPart{{Name: "tenant"}, {Name: "name", Operator: "gin_trgm_ops"}})
+	c.Assert(db.Indexes[1].Operator, qt.Equals, "gin_trgm_ops")
+	c.Assert(db.Indexes[1].Parts, qt.IsNil)
+}
+
+func TestParseIndexAnnotation_OperatorClassCountMismatchRejected(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="products"
+type Product struct {
+	//migrator:schema:field name="name" type="TEXT"
+	//migrator:schema:index name="idx_products_name" fields="name" ops="text_ops,gin_trgm_ops"
+	Name string
+}
+`
+	c := qt.New(t)
+	_, err := goschema.ParseSource("fixture.go", src)
+	var parseErr *ptaherr.ParseError
+	c.Assert(err, qt.ErrorAs, &parseErr)
+	c.Assert(parseErr.Attribute, qt.Equals, "ops")
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+}
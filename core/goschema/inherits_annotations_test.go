@@ -0,0 +1,30 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseTableInheritsAnnotation(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="cities"
+type City struct {
+	//migrator:schema:field name="name" type="TEXT"
+	Name string
+}
+
+//migrator:schema:table name="capitals" inherits="cities, geo.places"
+type Capital struct {
+	//migrator:schema:field name="state" type="CHAR(2)"
+	State string
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Tables, qt.HasLen, 2)
+	c.Assert(db.Tables[0].Inherits, qt.IsNil)
+	c.Assert(db.Tables[1].Name, qt.Equals, "capitals")
+	c.Assert(db.Tables[1].Inherits, qt.DeepEquals, []string{"cities", "geo.places"})
+}
@@ -0,0 +1,318 @@
+package goschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/core/platform"
+)
+
+// LintSeverity is the urgency of a schema lint finding.
+type LintSeverity string
+
+const (
+	// LintWarning marks a pattern that deserves review but does not stop the
+	// schema from being applied.
+	LintWarning LintSeverity = "warning"
+	// LintError marks a pattern the target database rejects or that breaks
+	// the generated DDL.
+	LintError LintSeverity = "error"
+)
+
+// LintRule names one check Lint runs.
+type LintRule string
+
+const (
+	// LintNoPrimaryKey flags a table without a primary key.
+	LintNoPrimaryKey LintRule = "no_primary_key"
+	// LintUnindexedForeignKey flags foreign key columns that are not the
+	// leading columns of any index, primary key or unique constraint.
+	// MySQL and MariaDB create that index automatically, so the rule only
+	// runs on other dialects.
+	LintUnindexedForeignKey LintRule = "unindexed_foreign_key"
+	// LintVarcharWithoutLength flags VARCHAR columns without a length on
+	// MySQL and MariaDB, which reject them.
+	LintVarcharWithoutLength LintRule = "varchar_without_length"
+	// LintNullableWithoutDefault flags nullable columns without a default.
+	// Making such a column NOT NULL later needs a backfill first.
+	LintNullableWithoutDefault LintRule = "nullable_without_default"
+	// LintEnumWithoutNativeType flags PostgreSQL columns that list enum
+	// values but are not typed with a native enum, so the values are not
+	// enforced.
+	LintEnumWithoutNativeType LintRule = "enum_without_native_type"
+	// LintReservedIdentifier flags table and column names that are reserved
+	// SQL keywords and must be quoted in every hand-written statement.
+	LintReservedIdentifier LintRule = "reserved_identifier"
+)
+
+// LintFinding is one risky pattern Lint found in a schema.
+type LintFinding struct {
+	Rule     LintRule     `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	// Table is the table the finding belongs to.
+	Table string `json:"table"`
+	// Column is the column name, or the comma-separated columns of a
+	// composite foreign key. Empty for table-level findings.
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (f LintFinding) String() string {
+	target := f.Table
+	if f.Column != "" {
+		target += "." + f.Column
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", f.Severity, target, f.Rule, f.Message)
+}
+
+// lintReservedWords lists keywords reserved by PostgreSQL or MySQL. ptah
+// quotes identifiers in the DDL it generates, but CHECK expressions, view
+// bodies, triggers and application queries name columns in raw SQL.
+var lintReservedWords = map[string]bool{
+	"all": true, "alter": true, "analyze": true, "and": true, "any": true,
+	"array": true, "as": true, "asc": true, "between": true, "both": true,
+	"by": true, "case": true, "cast": true, "check": true, "collate": true,
+	"column": true, "constraint": true, "create": true, "cross": true,
+	"current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "default": true, "delete": true, "desc": true,
+	"distinct": true, "drop": true, "else": true, "end": true, "except": true,
+	"exists": true, "false": true, "fetch": true, "for": true, "foreign": true,
+	"from": true, "grant": true, "group": true, "having": true, "in": true,
+	"index": true, "inner": true, "insert": true, "intersect": true,
+	"interval": true, "into": true, "is": true, "join": true, "key": true,
+	"keys": true, "leading": true, "left": true, "like": true, "limit": true,
+	"natural": true, "not": true, "null": true, "offset": true, "on": true,
+	"or": true, "order": true, "outer": true, "primary": true, "range": true,
+	"read": true, "references": true, "right": true, "row": true, "rows": true,
+	"select": true, "session_user": true, "set": true, "show": true,
+	"table": true, "then": true, "to": true, "trailing": true, "true": true,
+	"union": true, "unique": true, "update": true, "usage": true, "user": true,
+	"using": true, "values": true, "when": true, "where": true, "window": true,
+	"with": true,
+}
+
+// Lint checks db for patterns that tend to cause trouble in production when
+// it is deployed to dialect: tables without a primary key, foreign keys
+// without a supporting index, VARCHAR without a length on MySQL and MariaDB,
+// nullable columns without a default, PostgreSQL enum values without a
+// native enum type, and identifiers that are reserved keywords. It only reads
+// db, which should be a finalized parse result such as ParseDir returns.
+//
+// Findings are ordered by table, column and rule; the result is empty when
+// nothing was found. Callers gating a build usually fail on LintError and
+// report LintWarning.
+func Lint(db *Database, dialect string) []LintFinding {
+	if db == nil {
+		return nil
+	}
+	dialect = platform.NormalizeDialect(dialect)
+	var findings []LintFinding
+	for i := range db.Tables {
+		table := &db.Tables[i]
+		fields := lintTableFields(db, table)
+		findings = append(findings, lintTable(db, table, fields, dialect)...)
+		for _, field := range fields {
+			findings = append(findings, lintField(db, table, field, dialect)...)
+		}
+	}
+	slices.SortStableFunc(findings, func(a, b LintFinding) int {
+		if c := strings.Compare(a.Table, b.Table); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Column, b.Column); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.Rule), string(b.Rule))
+	})
+	return findings
+}
+
+func lintTable(db *Database, table *Table, fields []Field, dialect string) []LintFinding {
+	var findings []LintFinding
+	name := table.QualifiedName()
+	if lintReservedWords[strings.ToLower(table.Name)] {
+		findings = append(findings, LintFinding{
+			Rule:     LintReservedIdentifier,
+			Severity: LintWarning,
+			Table:    name,
+			Message:  fmt.Sprintf("table name %q is a reserved SQL keyword; every hand-written statement must quote it", table.Name),
+		})
+	}
+	keys := lintKeyColumns(db, table, fields)
+	if dialect != platform.ClickHouse && len(lintPrimaryKey(db, table, fields)) == 0 {
+		findings = append(findings, LintFinding{
+			Rule:     LintNoPrimaryKey,
+			Severity: LintWarning,
+			Table:    name,
+			Message:  "table has no primary key; rows cannot be addressed reliably and logical replication cannot stream updates or deletes",
+		})
+	}
+	if dialect == platform.MySQL || dialect == platform.MariaDB || dialect == platform.ClickHouse {
+		return findings
+	}
+	for _, columns := range lintForeignKeyColumns(db, table, fields) {
+		if lintColumnsCovered(columns, keys) {
+			continue
+		}
+		findings = append(findings, LintFinding{
+			Rule:     LintUnindexedForeignKey,
+			Severity: LintWarning,
+			Table:    name,
+			Column:   strings.Join(columns, ","),
+			Message:  "foreign key has no index with these leading columns; deleting or re-keying a referenced row scans the whole table",
+		})
+	}
+	return findings
+}
+
+func lintField(db *Database, table *Table, field Field, dialect string) []LintFinding {
+	var findings []LintFinding
+	name := table.QualifiedName()
+	fieldType := strings.TrimSpace(field.Type)
+	if override := field.Overrides[dialect]["type"]; override != "" {
+		fieldType = strings.TrimSpace(override)
+	}
+	if lintReservedWords[strings.ToLower(field.Name)] {
+		findings = append(findings, LintFinding{
+			Rule:     LintReservedIdentifier,
+			Severity: LintWarning,
+			Table:    name,
+			Column:   field.Name,
+			Message:  fmt.Sprintf("column name %q is a reserved SQL keyword; CHECK expressions, views, triggers and queries must quote it", field.Name),
+		})
+	}
+	if (dialect == platform.MySQL || dialect == platform.MariaDB) &&
+		(strings.EqualFold(fieldType, "VARCHAR") || strings.EqualFold(fieldType, "CHARACTER VARYING")) {
+		findings = append(findings, LintFinding{
+			Rule:     LintVarcharWithoutLength,
+			Severity: LintError,
+			Table:    name,
+			Column:   field.Name,
+			Message:  fmt.Sprintf("%s rejects VARCHAR without a length; declare VARCHAR(n)", dialect),
+		})
+	}
+	if field.Nullable && !field.Primary && field.Foreign == "" && !field.DefaultSet &&
+		field.Default == "" && field.DefaultExpr == "" && field.GeneratedExpression == "" {
+		findings = append(findings, LintFinding{
+			Rule:     LintNullableWithoutDefault,
+			Severity: LintWarning,
+			Table:    name,
+			Column:   field.Name,
+			Message:  "nullable column has no default; making it NOT NULL later needs a backfill of the existing NULL rows first",
+		})
+	}
+	if platform.IsPostgresFamily(dialect) && len(field.Enum) > 0 && !lintIsEnumType(db, fieldType) {
+		findings = append(findings, LintFinding{
+			Rule:     LintEnumWithoutNativeType,
+			Severity: LintWarning,
+			Table:    name,
+			Column:   field.Name,
+			Message:  fmt.Sprintf("enum values are not enforced because %s is not a native enum type; declare type=\"ENUM\" or use a //migrator:schema:enum type", fieldType),
+		})
+	}
+	return findings
+}
+
+// lintTableFields returns the columns declared on table's struct.
+func lintTableFields(db *Database, table *Table) []Field {
+	var fields []Field
+	for _, field := range db.Fields {
+		if field.StructName == table.StructName {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// lintPrimaryKey returns the primary key columns of table, from the table
+// attribute, primary fields or a PRIMARY KEY constraint.
+func lintPrimaryKey(db *Database, table *Table, fields []Field) []string {
+	if columns := tablePrimaryKeyColumns(*table, fields); len(columns) > 0 {
+		return columns
+	}
+	for _, constraint := range db.Constraints {
+		if strings.EqualFold(constraint.Type, "PRIMARY KEY") && resolveTableReference(db.Tables, constraint.StructName, constraint.Table) == table {
+			return constraint.Columns
+		}
+	}
+	return nil
+}
+
+// lintKeyColumns returns the column lists of every index, primary key and
+// unique constraint on table that can serve a foreign key lookup. Partial
+// indexes are left out because they do not cover every row.
+func lintKeyColumns(db *Database, table *Table, fields []Field) [][]string {
+	var keys [][]string
+	if columns := lintPrimaryKey(db, table, fields); len(columns) > 0 {
+		keys = append(keys, columns)
+	}
+	for _, field := range fields {
+		if field.Unique {
+			keys = append(keys, []string{field.Name})
+		}
+	}
+	for _, index := range db.Indexes {
+		if index.Condition == "" && resolveTableReference(db.Tables, index.StructName, index.TableName) == table {
+			keys = append(keys, index.Fields)
+		}
+	}
+	for _, constraint := range db.Constraints {
+		if strings.EqualFold(constraint.Type, "UNIQUE") && resolveTableReference(db.Tables, constraint.StructName, constraint.Table) == table {
+			keys = append(keys, constraint.Columns)
+		}
+	}
+	return keys
+}
+
+// lintForeignKeyColumns returns the local columns of every foreign key on
+// table.
+func lintForeignKeyColumns(db *Database, table *Table, fields []Field) [][]string {
+	var foreignKeys [][]string
+	for _, field := range fields {
+		if field.Foreign != "" {
+			foreignKeys = append(foreignKeys, []string{field.Name})
+		}
+	}
+	for _, constraint := range db.Constraints {
+		if strings.EqualFold(constraint.Type, "FOREIGN KEY") && len(constraint.Columns) > 0 &&
+			resolveTableReference(db.Tables, constraint.StructName, constraint.Table) == table {
+			foreignKeys = append(foreignKeys, constraint.Columns)
+		}
+	}
+	return foreignKeys
+}
+
+// lintColumnsCovered reports whether some key in keys starts with columns, in
+// any order.
+func lintColumnsCovered(columns []string, keys [][]string) bool {
+	for _, key := range keys {
+		if len(key) < len(columns) {
+			continue
+		}
+		covered := true
+		for _, column := range columns {
+			if !slices.ContainsFunc(key[:len(columns)], func(keyColumn string) bool {
+				return strings.EqualFold(strings.TrimSpace(keyColumn), strings.TrimSpace(column))
+			}) {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+// lintIsEnumType reports whether fieldType names an enum declared in db.
+func lintIsEnumType(db *Database, fieldType string) bool {
+	_, unqualified, found := strings.Cut(fieldType, ".")
+	for _, enum := range db.Enums {
+		if strings.EqualFold(enum.Name, fieldType) || (found && strings.EqualFold(enum.Name, unqualified)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,312 @@
+package goschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		source  string
+		want    []goschema.LintFinding
+	}{
+		{
+			name:    "clean schema",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="email" type="VARCHAR(255)" not_null="true"
+	Email string
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true" foreign="users(id)"
+	UserID int64
+
+	//migrator:schema:index name="idx_posts_user_id" fields="user_id"
+	_ int
+}
+`,
+		},
+		{
+			name:    "table without primary key",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="events"
+type Event struct {
+	//migrator:schema:field name="payload" type="TEXT" not_null="true"
+	Payload string
+}
+`,
+			want: []goschema.LintFinding{{
+				Rule:     goschema.LintNoPrimaryKey,
+				Severity: goschema.LintWarning,
+				Table:    "events",
+				Message:  "table has no primary key; rows cannot be addressed reliably and logical replication cannot stream updates or deletes",
+			}},
+		},
+		{
+			name:    "composite primary key",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="user_roles" primary_key="user_id,role_id"
+type UserRole struct {
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true"
+	UserID int64
+
+	//migrator:schema:field name="role_id" type="INTEGER" not_null="true"
+	RoleID int64
+}
+`,
+		},
+		{
+			name:    "foreign key without index on postgres",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true" foreign="users(id)"
+	UserID int64
+}
+`,
+			want: []goschema.LintFinding{{
+				Rule:     goschema.LintUnindexedForeignKey,
+				Severity: goschema.LintWarning,
+				Table:    "posts",
+				Column:   "user_id",
+				Message:  "foreign key has no index with these leading columns; deleting or re-keying a referenced row scans the whole table",
+			}},
+		},
+		{
+			name:    "foreign key covered by the leading primary key column",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+
+//migrator:schema:table name="user_roles" primary_key="user_id,role"
+type UserRole struct {
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true" foreign="users(id)"
+	UserID int64
+
+	//migrator:schema:field name="role" type="TEXT" not_null="true"
+	Role string
+}
+`,
+		},
+		{
+			name:    "foreign key on the second index column is not covered",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+
+//migrator:schema:table name="user_roles" primary_key="role,user_id"
+type UserRole struct {
+	//migrator:schema:field name="role" type="TEXT" not_null="true"
+	Role string
+
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true" foreign="users(id)"
+	UserID int64
+}
+`,
+			want: []goschema.LintFinding{{
+				Rule:     goschema.LintUnindexedForeignKey,
+				Severity: goschema.LintWarning,
+				Table:    "user_roles",
+				Column:   "user_id",
+				Message:  "foreign key has no index with these leading columns; deleting or re-keying a referenced row scans the whole table",
+			}},
+		},
+		{
+			name:    "mysql indexes foreign keys itself but rejects VARCHAR without a length",
+			dialect: "mysql",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true" foreign="users(id)"
+	UserID int64
+
+	//migrator:schema:field name="title" type="TEXT" not_null="true" platform.mysql.type="VARCHAR"
+	Title string
+}
+`,
+			want: []goschema.LintFinding{{
+				Rule:     goschema.LintVarcharWithoutLength,
+				Severity: goschema.LintError,
+				Table:    "posts",
+				Column:   "title",
+				Message:  "mysql rejects VARCHAR without a length; declare VARCHAR(n)",
+			}},
+		},
+		{
+			name:    "nullable column without default",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="nickname" type="TEXT"
+	Nickname string
+
+	//migrator:schema:field name="status" type="TEXT" default="active"
+	Status string
+}
+`,
+			want: []goschema.LintFinding{{
+				Rule:     goschema.LintNullableWithoutDefault,
+				Severity: goschema.LintWarning,
+				Table:    "users",
+				Column:   "nickname",
+				Message:  "nullable column has no default; making it NOT NULL later needs a backfill of the existing NULL rows first",
+			}},
+		},
+		{
+			name:    "enum values without a native enum type on postgres",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="status" type="ENUM" enum="active,inactive" not_null="true"
+	Status string
+
+	//migrator:schema:field name="role" type="VARCHAR(20)" enum="admin,member" not_null="true"
+	Role string
+}
+`,
+			want: []goschema.LintFinding{{
+				Rule:     goschema.LintEnumWithoutNativeType,
+				Severity: goschema.LintWarning,
+				Table:    "users",
+				Column:   "role",
+				Message:  `enum values are not enforced because VARCHAR(20) is not a native enum type; declare type="ENUM" or use a //migrator:schema:enum type`,
+			}},
+		},
+		{
+			name:    "reserved keyword identifiers",
+			dialect: "postgres",
+			source: `package entities
+
+//migrator:schema:table name="order"
+type Order struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="select" type="TEXT" not_null="true"
+	Select string
+}
+`,
+			want: []goschema.LintFinding{
+				{
+					Rule:     goschema.LintReservedIdentifier,
+					Severity: goschema.LintWarning,
+					Table:    "order",
+					Message:  `table name "order" is a reserved SQL keyword; every hand-written statement must quote it`,
+				},
+				{
+					Rule:     goschema.LintReservedIdentifier,
+					Severity: goschema.LintWarning,
+					Table:    "order",
+					Column:   "select",
+					Message:  `column name "select" is a reserved SQL keyword; CHECK expressions, views, triggers and queries must quote it`,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			database, err := goschema.ParseSources(map[string][]byte{"entities.go": []byte(tt.source)})
+			c.Assert(err, qt.IsNil)
+
+			c.Assert(goschema.Lint(database, tt.dialect), qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestLint_NilDatabase(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(goschema.Lint(nil, "postgres"), qt.IsNil)
+}
+
+func TestLintFinding_String(t *testing.T) {
+	tests := []struct {
+		name    string
+		finding goschema.LintFinding
+		want    string
+	}{
+		{
+			name: "table finding",
+			finding: goschema.LintFinding{
+				Rule: goschema.LintNoPrimaryKey, Severity: goschema.LintWarning, Table: "events", Message: "no key",
+			},
+			want: "warning: events [no_primary_key] no key",
+		},
+		{
+			name: "column finding",
+			finding: goschema.LintFinding{
+				Rule: goschema.LintVarcharWithoutLength, Severity: goschema.LintError, Table: "posts", Column: "title", Message: "no length",
+			},
+			want: "error: posts.title [varchar_without_length] no length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(tt.finding.String(), qt.Equals, tt.want)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package goschema
+
+import (
+	"fmt"
+	"go/build"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// ParseOptions selects the files ParseDirWithOptions reads.
+type ParseOptions struct {
+	// BuildTags are the build tags satisfied when evaluating //go:build
+	// constraints, in addition to the host GOOS and GOARCH. Files whose
+	// constraints are not satisfied, including GOOS and GOARCH file name
+	// suffixes such as _windows.go, are skipped.
+	BuildTags []string
+	// ExcludeGlobs skips files whose slash-separated path relative to the root
+	// directory, or whose base name, matches one of the patterns. Patterns use
+	// path.Match syntax, and a ** segment matches any number of directories,
+	// so "testdata/**" skips everything under testdata.
+	ExcludeGlobs []string
+	// Recursive also parses the subdirectories of the root directory.
+	Recursive bool
+}
+
+// ParseDirWithOptions parses the Go files in rootDir like ParseDir, but honors
+// build constraints and skips the files opts excludes. Unlike ParseDir it only
+// descends into subdirectories when opts.Recursive is set.
+//
+// Use it when entity files have build-tagged variants: without a build tag
+// selection ParseDir merges every variant of a struct into one schema.
+//
+// Example:
+//
+//	result, err := ParseDirWithOptions("./internal/entities", ParseOptions{
+//		BuildTags:    []string{"postgres"},
+//		ExcludeGlobs: []string{"testdata/**", "*_fixture.go"},
+//		Recursive:    true,
+//	})
+func ParseDirWithOptions(rootDir string, opts ParseOptions) (*Database, error) {
+	return ParseFSWithOptions(os.DirFS(rootDir), ".", opts)
+}
+
+// ParseFSWithOptions is ParseDirWithOptions for a directory within fsys.
+func ParseFSWithOptions(fsys fs.FS, rootDir string, opts ParseOptions) (*Database, error) {
+	for _, pattern := range opts.ExcludeGlobs {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude glob %q: %w", pattern, err)
+		}
+	}
+	buildContext := build.Default
+	buildContext.BuildTags = append(slices.Clone(build.Default.BuildTags), opts.BuildTags...)
+	buildContext.JoinPath = path.Join
+	buildContext.OpenFile = func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name)
+	}
+
+	return parseFS(fsys, rootDir, func(filePath string, d fs.DirEntry) (bool, error) {
+		if d.IsDir() {
+			return filePath == rootDir || opts.Recursive, nil
+		}
+		if excludedByGlob(opts.ExcludeGlobs, relativeFSPath(rootDir, filePath)) {
+			return false, nil
+		}
+		return buildContext.MatchFile(path.Dir(filePath), path.Base(filePath))
+	})
+}
+
+// relativeFSPath returns filePath relative to rootDir within an fs.FS.
+func relativeFSPath(rootDir, filePath string) string {
+	if rootDir == "." {
+		return filePath
+	}
+	return strings.TrimPrefix(filePath, rootDir+"/")
+}
+
+// excludedByGlob reports whether relPath or its base name matches one of
+// globs.
+func excludedByGlob(globs []string, relPath string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, path.Base(relPath)); ok {
+			return true
+		}
+		if matchGlobSegments(strings.Split(glob, "/"), strings.Split(relPath, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlobSegments matches slash-separated segments, letting a ** segment
+// match any number of path segments.
+func matchGlobSegments(pattern, value []string) bool {
+	if len(pattern) == 0 {
+		return len(value) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(value); i++ {
+			if matchGlobSegments(pattern[1:], value[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(value) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], value[0]); err != nil || !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], value[1:])
+}
@@ -0,0 +1,145 @@
+package goschema_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+)
+
+// buildTaggedEntities defines the same struct twice, once per build tag, with
+// a different payload column type.
+var buildTaggedEntities = fstest.MapFS{
+	"entities/user_postgres.go": &fstest.MapFile{Data: []byte(`//go:build postgres
+
+package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="payload" type="JSONB" not_null="true"
+	Payload string
+}
+`)},
+	"entities/user_mysql.go": &fstest.MapFile{Data: []byte(`//go:build mysql
+
+package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INT AUTO_INCREMENT" primary="true"
+	ID int64
+
+	//migrator:schema:field name="payload" type="JSON" not_null="true"
+	Payload string
+}
+`)},
+	"entities/post.go": &fstest.MapFile{Data: []byte(`package entities
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+`)},
+	"entities/post_fixture.go": &fstest.MapFile{Data: []byte(`package entities
+
+//migrator:schema:table name="fixture_posts"
+type FixturePost struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+`)},
+	"entities/audit/event.go": &fstest.MapFile{Data: []byte(`package audit
+
+//migrator:schema:table name="audit_events"
+type Event struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+`)},
+}
+
+func TestParseFSWithOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       goschema.ParseOptions
+		wantTables []string
+		wantFields map[string]string
+	}{
+		{
+			name:       "postgres build tag",
+			opts:       goschema.ParseOptions{BuildTags: []string{"postgres"}},
+			wantTables: []string{"fixture_posts", "posts", "users"},
+			wantFields: map[string]string{"fixture_posts.id": "SERIAL", "posts.id": "SERIAL", "users.id": "SERIAL", "users.payload": "JSONB"},
+		},
+		{
+			name:       "mysql build tag",
+			opts:       goschema.ParseOptions{BuildTags: []string{"mysql"}},
+			wantTables: []string{"fixture_posts", "posts", "users"},
+			wantFields: map[string]string{"fixture_posts.id": "SERIAL", "posts.id": "SERIAL", "users.id": "INT AUTO_INCREMENT", "users.payload": "JSON"},
+		},
+		{
+			name:       "no build tag skips both variants",
+			opts:       goschema.ParseOptions{},
+			wantTables: []string{"fixture_posts", "posts"},
+			wantFields: map[string]string{"fixture_posts.id": "SERIAL", "posts.id": "SERIAL"},
+		},
+		{
+			name:       "exclude glob on the base name",
+			opts:       goschema.ParseOptions{BuildTags: []string{"postgres"}, ExcludeGlobs: []string{"*_fixture.go"}},
+			wantTables: []string{"posts", "users"},
+			wantFields: map[string]string{"posts.id": "SERIAL", "users.id": "SERIAL", "users.payload": "JSONB"},
+		},
+		{
+			name:       "recursive",
+			opts:       goschema.ParseOptions{Recursive: true, ExcludeGlobs: []string{"post*.go"}},
+			wantTables: []string{"audit_events"},
+			wantFields: map[string]string{"audit_events.id": "SERIAL"},
+		},
+		{
+			name:       "recursive with a directory glob",
+			opts:       goschema.ParseOptions{Recursive: true, ExcludeGlobs: []string{"audit/**", "*_fixture.go"}},
+			wantTables: []string{"posts"},
+			wantFields: map[string]string{"posts.id": "SERIAL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			database, err := goschema.ParseFSWithOptions(buildTaggedEntities, "entities", tt.opts)
+			c.Assert(err, qt.IsNil)
+
+			var tables []string
+			for _, table := range database.Tables {
+				tables = append(tables, table.Name)
+			}
+			c.Assert(tables, qt.ContentEquals, tt.wantTables)
+			c.Assert(fieldTypesByTable(database), qt.DeepEquals, tt.wantFields)
+		})
+	}
+}
+
+func TestParseFSWithOptions_InvalidExcludeGlob(t *testing.T) {
+	c := qt.New(t)
+	_, err := goschema.ParseFSWithOptions(buildTaggedEntities, "entities", goschema.ParseOptions{ExcludeGlobs: []string{"[unclosed"}})
+	c.Assert(err, qt.ErrorMatches, `invalid exclude glob "\[unclosed": syntax error in pattern`)
+}
+
+// fieldTypesByTable returns the type of every field keyed by table.column.
+func fieldTypesByTable(database *goschema.Database) map[string]string {
+	tableByStruct := map[string]string{}
+	for _, table := range database.Tables {
+		tableByStruct[table.StructName] = table.Name
+	}
+	byTable := map[string]string{}
+	for _, field := range database.Fields {
+		byTable[tableByStruct[field.StructName]+"."+field.Name] = field.Type
+	}
+	return byTable
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/stokaro/ptah/core/goschema/internal/parseutils"
 	"github.com/stokaro/ptah/core/ptaherr"
+	"github.com/stokaro/ptah/core/sqlutil"
 	"github.com/stokaro/ptah/internal/annotationmeta"
 )
 
@@ -92,6 +93,19 @@ func (s *schemaParseState) parseFieldComment(
 	); err != nil {
 		return err
 	}
+	// A field declares one local column, so its reference must name one
+	// column too; composite keys need a table-level foreign key constraint.
+	if columns := foreignReferenceColumnCount(kv["foreign"]); columns > 1 {
+		return &ptaherr.ParseError{
+			File:      s.filename,
+			Line:      s.annotationContext(comment, "//migrator:schema:field", location).line,
+			Directive: "migrator:schema:field",
+			Attribute: "foreign",
+			Err:       ptaherr.ErrInvalidAttributeValue,
+			Message: fmt.Sprintf("//migrator:schema:field at %s references %d columns in %q but declares one column; use a //migrator:schema:constraint foreign key for a composite key",
+				location, columns, kv["foreign"]),
+		}
+	}
 
 	for _, name := range field.Names {
 		enumRaw := kv["enum"]
@@ -130,6 +144,7 @@ func (s *schemaParseState) parseFieldComment(
 			identityGeneration = "BY_DEFAULT"
 		}
 		_, defaultSet := kv["default"]
+		onUpdate, updateExpression := splitFieldOnUpdate(kv["on_update"])
 		s.schemaFields = append(s.schemaFields, Field{
 			StructName:          structName,
 			FieldName:           name.Name,
@@ -150,12 +165,14 @@ func (s *schemaParseState) parseFieldComment(
 			Foreign:             kv["foreign"],
 			ForeignKeyName:      kv["foreign_key_name"],
 			OnDelete:            kv["on_delete"],
-			OnUpdate:            kv["on_update"],
+			OnUpdate:            onUpdate,
+			UpdateExpression:    updateExpression,
 			Enum:                enum,
 			Check:               kv["check"],
 			CheckName:           kv["check_name"],
 			GeneratedExpression: kv["generated"],
 			GeneratedKind:       generatedColumnKind(kv),
+			TypeCast:            kv["type_cast"],
 			Comment:             kv["comment"],
 			Overrides:           parseutils.ParsePlatformSpecific(kv),
 		})
@@ -163,6 +180,30 @@ func (s *schemaParseState) parseFieldComment(
 	return nil
 }
 
+// foreignReferenceColumnCount returns the number of referenced columns in a
+// "table(col1,col2)" reference, or 0 when it names none.
+func foreignReferenceColumnCount(foreign string) int {
+	_, columns, ok := strings.Cut(foreign, "(")
+	if !ok {
+		return 0
+	}
+	return len(strings.Split(strings.TrimSuffix(strings.TrimSpace(columns), ")"), ","))
+}
+
+// splitFieldOnUpdate tells the two meanings of a field's on_update attribute
+// apart: a timestamp expression such as CURRENT_TIMESTAMP(6) is the
+// MySQL/MariaDB column ON UPDATE clause, anything else is the foreign key
+// ON UPDATE action.
+func splitFieldOnUpdate(value string) (foreignKeyAction, updateExpression string) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	for _, prefix := range []string{"CURRENT_TIMESTAMP", "NOW(", "LOCALTIMESTAMP"} {
+		if strings.HasPrefix(upper, prefix) {
+			return "", strings.TrimSpace(value)
+		}
+	}
+	return value, ""
+}
+
 func generatedColumnKind(kv map[string]string) string {
 	if strings.TrimSpace(kv["generated"]) == "" {
 		return ""
@@ -183,6 +224,25 @@ func hasIdentitySettings(kv map[string]string) bool {
 	return kv["identity_start"] != "" || kv["identity_increment"] != "" || kv["identity_options"] != ""
 }
 
+// embeddedTypeName returns the name of the type behind an embedded field
+// expression: BaseID, *BaseID, and the generic instantiations Base[T] and
+// *Base[K, V] all resolve to the declared type name. It returns "" for
+// expressions that do not name a type.
+func embeddedTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedTypeName(t.X)
+	case *ast.IndexExpr:
+		return embeddedTypeName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedTypeName(t.X)
+	default:
+		return ""
+	}
+}
+
 func (s *schemaParseState) parseEmbeddedComment(comment *ast.Comment, field *ast.Field, structName string) error {
 	kv := parseutils.ParseKeyValueComment(comment.Text)
 	if err := validateAttributes(
@@ -193,19 +253,7 @@ func (s *schemaParseState) parseEmbeddedComment(comment *ast.Comment, field *ast
 	}
 
 	// Handle embedded fields - get the field type name
-	var fieldTypeName string
-	if field.Type != nil {
-		switch t := field.Type.(type) {
-		case *ast.Ident:
-			// Value embedded field: BaseID
-			fieldTypeName = t.Name
-		case *ast.StarExpr:
-			// Pointer embedded field: *BaseID
-			if ident, ok := t.X.(*ast.Ident); ok {
-				fieldTypeName = ident.Name
-			}
-		}
-	}
+	fieldTypeName := embeddedTypeName(field.Type)
 
 	s.embeddedFields = append(s.embeddedFields, EmbeddedField{
 		StructName:       structName,
@@ -220,6 +268,7 @@ func (s *schemaParseState) parseEmbeddedComment(comment *ast.Comment, field *ast
 		OnDelete:         kv["on_delete"],
 		OnUpdate:         kv["on_update"],
 		Comment:          kv["comment"],
+		DefaultExpr:      kv["default_expr"],
 		EmbeddedTypeName: fieldTypeName,
 		Overrides:        parseutils.ParsePlatformSpecific(kv),
 	})
@@ -247,6 +296,45 @@ func (s *schemaParseState) parseIndexComment(comment *ast.Comment, structName st
 		fields[i] = strings.TrimSpace(fields[i])
 	}
 
+	// "expr=" declares an expression index. The expression is kept verbatim
+	// as the single key part; Fields mirrors it for callers that only read
+	// the legacy column list.
+	var parts []IndexPart
+	if expr := strings.TrimSpace(kv["expr"]); expr != "" {
+		if strings.TrimSpace(fieldsRaw) != "" {
+			return &ptaherr.ParseError{
+				File:      s.filename,
+				Line:      s.annotationContext(comment, "//migrator:schema:index", structName).line,
+				Directive: "migrator:schema:index",
+				Attribute: "expr",
+				Err:       ptaherr.ErrInvalidAttributeValue,
+				Message:   fmt.Sprintf("//migrator:schema:index at %s cannot combine expr with fields", structName),
+			}
+		}
+		parts = []IndexPart{{Expr: expr}}
+		fields = []string{expr}
+	}
+
+	// "ops=" with a comma-separated list assigns one operator class per key
+	// part; a single value keeps applying to every part.
+	operator := kv["ops"]
+	if strings.Contains(operator, ",") {
+		opClasses := strings.Split(operator, ",")
+		if len(opClasses) != len(fields) {
+			return &ptaherr.ParseError{
+				File:      s.filename,
+				Line:      s.annotationContext(comment, "//migrator:schema:index", structName).line,
+				Directive: "migrator:schema:index",
+				Attribute: "ops",
+				Err:       ptaherr.ErrInvalidAttributeValue,
+				Message: fmt.Sprintf("//migrator:schema:index at %s declares %d operator classes for %d fields",
+					structName, len(opClasses), len(fields)),
+			}
+		}
+		parts = indexPartsWithOperators(parts, fields, opClasses)
+		operator = ""
+	}
+
 	// Determine target table name - use 'table' attribute if specified, otherwise leave empty for later resolution
 	tableName := kv["table"]
 
@@ -270,22 +358,53 @@ func (s *schemaParseState) parseIndexComment(comment *ast.Comment, structName st
 		granularity = n
 	}
 
+	storageParams, err := parseStorageParams(kv["storage"])
+	if err != nil {
+		return &ptaherr.ParseError{
+			File:      s.filename,
+			Line:      s.annotationContext(comment, "//migrator:schema:index", structName).line,
+			Directive: "migrator:schema:index",
+			Attribute: "storage",
+			Err:       ptaherr.ErrInvalidAttributeValue,
+			Message:   fmt.Sprintf("invalid storage %q on //migrator:schema:index at %s: %v", kv["storage"], structName, err),
+		}
+	}
+
 	s.schemaIndexes = append(s.schemaIndexes, Index{
 		StructName:    structName,
 		Name:          kv["name"],
 		Fields:        fields,
+		Parts:         parts,
 		Unique:        kv["unique"] == "true",
 		Comment:       kv["comment"],
-		Type:          kv["type"],                                  // PG: GIN/GIST/BTREE/HASH; CH: minmax/set(N)/bloom_filter/...
+		Type:          kv["type"],                                  // PG: GIN/GIST/BTREE/HASH; MySQL: FULLTEXT/SPATIAL/BTREE/HASH; CH: minmax/...
 		Condition:     firstNonEmpty(kv["where"], kv["condition"]), // PG/SQLite: WHERE clause for partial indexes
-		Operator:      kv["ops"],                                   // PG only: operator class (gin_trgm_ops, etc.)
-		NullsDistinct: parseBoolPtr(kv["nulls_distinct"]),
-		TableName:     tableName,   // Target table name
-		Granularity:   granularity, // CH only: GRANULARITY n for data-skipping indexes
+		Operator:      operator,                                    // PG only: operator class (gin_trgm_ops, etc.)
+		NullsDistinct: parseNullsDistinct(kv),
+		StorageParams: storageParams,               // PG only: WITH (fillfactor=70, ...)
+		Visible:       parseBoolPtr(kv["visible"]), // MySQL/MariaDB: INVISIBLE / IGNORED
+		TableName:     tableName,                   // Target table name
+		Granularity:   granularity,                 // CH only: GRANULARITY n for data-skipping indexes
 	})
 	return nil
 }
 
+// indexPartsWithOperators sets the operator class of each key part, building
+// column parts from fields when the index has none yet. An empty entry keeps
+// the default operator class for that part.
+func indexPartsWithOperators(parts []IndexPart, fields, opClasses []string) []IndexPart {
+	if len(parts) == 0 {
+		parts = make([]IndexPart, 0, len(fields))
+		for _, field := range fields {
+			parts = append(parts, IndexPart{Name: field})
+		}
+	}
+	for i := range parts {
+		parts[i].Operator = strings.TrimSpace(opClasses[i])
+	}
+	return parts
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if value != "" {
@@ -307,6 +426,55 @@ func (s *schemaParseState) parseConstraintComment(comment *ast.Comment, structNa
 	return nil
 }
 
+// parseExcludeComment parses //migrator:schema:exclude into an EXCLUDE
+// Constraint. Expressions and operators are paired positionally into the
+// "expr WITH op, ..." element list used by //migrator:schema:constraint.
+func (s *schemaParseState) parseExcludeComment(comment *ast.Comment, structName string) error {
+	kv := parseutils.ParseKeyValueComment(comment.Text)
+	ctx := s.annotationContext(comment, "//migrator:schema:exclude", structName)
+	if err := validateAttributes(kv, ctx); err != nil {
+		return err
+	}
+	if err := requireAttributes(kv, ctx); err != nil {
+		return err
+	}
+
+	expressions := splitTopLevelCommaList(kv["expressions"])
+	operators := splitCommaList(kv["operators"])
+	if len(expressions) != len(operators) {
+		return &ptaherr.ParseError{
+			File:      s.filename,
+			Line:      ctx.line,
+			Directive: "migrator:schema:exclude",
+			Attribute: "operators",
+			Err:       ptaherr.ErrInvalidAttributeValue,
+			Message: fmt.Sprintf("//migrator:schema:exclude %q at %s declares %d expressions but %d operators",
+				kv["name"], ctx.location, len(expressions), len(operators)),
+		}
+	}
+	elements := make([]string, len(expressions))
+	for i := range expressions {
+		elements[i] = expressions[i] + " WITH " + operators[i]
+	}
+
+	method := strings.ToLower(strings.TrimSpace(kv["method"]))
+	if method == "" {
+		method = "gist"
+	}
+
+	s.schemaConstraints = append(s.schemaConstraints, Constraint{
+		StructName:      structName,
+		Name:            kv["name"],
+		Type:            "EXCLUDE",
+		Table:           kv["table"],
+		UsingMethod:     method,
+		ExcludeElements: strings.Join(elements, ", "),
+		WhereCondition:  strings.TrimSpace(kv["where"]),
+		Comment:         kv["comment"],
+	})
+	return nil
+}
+
 func parseConstraintComment(comment *ast.Comment, structName string) Constraint {
 	kv := parseutils.ParseKeyValueComment(comment.Text)
 
@@ -343,7 +511,7 @@ func parseConstraintComment(comment *ast.Comment, structName string) Constraint
 		// UNIQUE/PRIMARY KEY constraint specific fields
 		Columns:        columns, // Column names
 		IncludeColumns: splitCommaList(kv["include"]),
-		NullsDistinct:  parseBoolPtr(kv["nulls_distinct"]),
+		NullsDistinct:  parseNullsDistinct(kv),
 
 		// FOREIGN KEY constraint specific fields
 		ForeignTable:   kv["foreign_table"],  // Referenced table
@@ -352,10 +520,32 @@ func parseConstraintComment(comment *ast.Comment, structName string) Constraint
 		OnDelete:       kv["on_delete"], // ON DELETE action
 		OnUpdate:       kv["on_update"], // ON UPDATE action
 
+		NotValid: kv["not_valid"] == "true", // Two-step NOT VALID + VALIDATE
+
 		Comment: kv["comment"], // Constraint comment
 	}
 }
 
+// parseStorageParams parses a comma-separated list of key=value index storage
+// parameters such as "fillfactor=70, deduplicate_items=off". Keys are
+// lower-cased; an empty value yields nil.
+func parseStorageParams(value string) (map[string]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	params := make(map[string]string)
+	for entry := range strings.SplitSeq(value, ",") {
+		key, val, ok := strings.Cut(entry, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+		if !ok || key == "" || val == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", strings.TrimSpace(entry))
+		}
+		params[key] = val
+	}
+	return params, nil
+}
+
 func parseBoolPtr(value string) *bool {
 	if strings.TrimSpace(value) == "" {
 		return nil
@@ -364,6 +554,21 @@ func parseBoolPtr(value string) *bool {
 	return &parsed
 }
 
+// parseNullsDistinct reads the NULLS [NOT] DISTINCT setting of a unique
+// index or constraint. nulls_not_distinct="true" is the inverse spelling of
+// nulls_distinct="false"; nulls_distinct wins when both are set.
+func parseNullsDistinct(kv map[string]string) *bool {
+	if nullsDistinct := parseBoolPtr(kv["nulls_distinct"]); nullsDistinct != nil {
+		return nullsDistinct
+	}
+	notDistinct := parseBoolPtr(kv["nulls_not_distinct"])
+	if notDistinct == nil {
+		return nil
+	}
+	nullsDistinct := !*notDistinct
+	return &nullsDistinct
+}
+
 func (s *schemaParseState) parseExtensionComment(comment *ast.Comment) error {
 	kv := parseutils.ParseKeyValueComment(comment.Text)
 	if err := validateAttributes(
@@ -412,16 +617,33 @@ func (s *schemaParseState) parseTableComment(comment *ast.Comment, structName st
 	); err != nil {
 		return err
 	}
+	autoIncrement := strings.TrimSpace(kv["auto_increment"])
+	if autoIncrement != "" {
+		if n, err := strconv.ParseInt(autoIncrement, 10, 64); err != nil || n < 1 {
+			return &ptaherr.ParseError{
+				File:      s.filename,
+				Line:      s.annotationContext(comment, "//migrator:schema:table", structName).line,
+				Directive: "migrator:schema:table",
+				Attribute: "auto_increment",
+				Err:       ptaherr.ErrInvalidAttributeValue,
+				Message:   fmt.Sprintf("invalid auto_increment %q on //migrator:schema:table at %s (must be a positive integer)", autoIncrement, structName),
+			}
+		}
+	}
 	s.tableDirectives = append(s.tableDirectives, Table{
-		StructName: structName,
-		Name:       kv["name"],
-		Schema:     kv["schema"],
-		Engine:     kv["engine"],
-		Comment:    kv["comment"],
-		PrimaryKey: splitCSVAttribute(kv["primary_key"]),
-		Checks:     splitCSVAttribute(kv["checks"]),
-		CustomSQL:  kv["custom"],
-		Overrides:  parseutils.ParsePlatformSpecific(kv),
+		StructName:      structName,
+		Name:            kv["name"],
+		Schema:          kv["schema"],
+		Engine:          kv["engine"],
+		AutoIncrement:   autoIncrement,
+		Comment:         kv["comment"],
+		Owner:           kv["owner"],
+		PrimaryKey:      splitCSVAttribute(kv["primary_key"]),
+		Checks:          splitCSVAttribute(kv["checks"]),
+		Inherits:        splitCSVAttribute(kv["inherits"]),
+		ExternalColumns: splitCSVAttribute(kv["external_columns"]),
+		CustomSQL:       kv["custom"],
+		Overrides:       parseutils.ParsePlatformSpecific(kv),
 	})
 	return nil
 }
@@ -446,6 +668,7 @@ type schemaParseState struct {
 	tableNameToStructName map[string]string
 	globalEnumsMap        map[string]Enum
 	embeddedFields        []EmbeddedField
+	typeAliases           map[string]string
 	schemaFields          []Field
 	schemaIndexes         []Index
 	schemaConstraints     []Constraint
@@ -463,7 +686,12 @@ type schemaParseState struct {
 	rlsEnabledTables      []RLSEnabledTable
 	roles                 []Role
 	grants                []Grant
+	seeds                 []Seed
 	schemas               []Schema
+
+	// readFile reads files named relative to the parsed source, such as seed
+	// files. It is nil when the source has no directory to read from.
+	readFile func(name string) ([]byte, error)
 }
 
 type structDeclaration struct {
@@ -546,6 +774,8 @@ func (s *schemaParseState) parseSharedComment(comment *ast.Comment, target schem
 	switch {
 	case strings.HasPrefix(comment.Text, "//migrator:schema:constraint"):
 		return s.parseConstraintComment(comment, target.structName)
+	case strings.HasPrefix(comment.Text, "//migrator:schema:exclude"):
+		return s.parseExcludeComment(comment, target.structName)
 	case strings.HasPrefix(comment.Text, "//migrator:schema:enum"):
 		return s.parseEnumComment(comment)
 	case strings.HasPrefix(comment.Text, "//migrator:schema:extension"):
@@ -563,7 +793,9 @@ func (s *schemaParseState) parseSharedComment(comment *ast.Comment, target schem
 	case strings.HasPrefix(comment.Text, "//migrator:schema:view"):
 		return s.parseViewComment(comment, target.structName)
 	case strings.HasPrefix(comment.Text, "//migrator:schema:matview"):
-		return s.parseMaterializedViewComment(comment, target.structName)
+		return s.parseMaterializedViewComment(comment, "//migrator:schema:matview", target.structName)
+	case strings.HasPrefix(comment.Text, "//migrator:schema:materialized_view"):
+		return s.parseMaterializedViewComment(comment, "//migrator:schema:materialized_view", target.structName)
 	case strings.HasPrefix(comment.Text, "//migrator:schema:trigger"):
 		return s.parseTriggerComment(comment, target.structName)
 	case strings.HasPrefix(comment.Text, "//migrator:schema:rls:policy"):
@@ -574,6 +806,8 @@ func (s *schemaParseState) parseSharedComment(comment *ast.Comment, target schem
 		return s.parseRoleComment(comment, target.structName)
 	case strings.HasPrefix(comment.Text, "//migrator:schema:grant"):
 		return s.parseGrantComment(comment, target.structName)
+	case strings.HasPrefix(comment.Text, "//migrator:schema:seed"):
+		return s.parseSeedComment(comment, target.structName)
 	}
 	return nil
 }
@@ -594,8 +828,9 @@ func (s *schemaParseState) parseEnumComment(comment *ast.Comment) error {
 	}
 
 	s.globalEnumsMap[kv["name"]] = Enum{
-		Name:   kv["name"],
-		Values: splitCommaList(kv["values"]),
+		Name:       kv["name"],
+		Values:     splitCommaList(kv["values"]),
+		RenameFrom: strings.TrimSpace(kv["rename_from"]),
 	}
 	return nil
 }
@@ -644,30 +879,41 @@ func ParseFile(filename string) (Database, error) {
 		}
 	}
 
-	return parseFileAST(filename, fset, f)
+	database, _, err := parseFileAST(filename, fset, f, osRelativeFileReader(filename))
+	return database, err
 }
 
 // ParseSource parses a Go source string and returns the database schema.
-// source can be a string, []byte, or io.Reader.
+// source can be a string, []byte, or io.Reader. Seed files are read relative
+// to the directory of filename.
 func ParseSource(filename string, source any) (Database, error) {
+	database, _, err := parseSource(filename, source, osRelativeFileReader(filename))
+	return database, err
+}
+
+// parseSource parses one Go source. It also returns the type aliases the
+// source declares, so a set of sources can resolve embedded aliases declared
+// in another file.
+func parseSource(filename string, source any, readFile func(string) ([]byte, error)) (Database, map[string]string, error) {
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(fset, filename, source, parser.ParseComments)
 	if err != nil {
 		slog.Error("Failed to parse file", "error", err)
-		return Database{}, &ptaherr.ParseError{
+		return Database{}, nil, &ptaherr.ParseError{
 			File:    filename,
 			Err:     err,
 			Message: fmt.Sprintf("parse Go source %q: %v", filename, err),
 		}
 	}
 
-	return parseFileAST(filename, fset, f)
+	return parseFileAST(filename, fset, f, readFile)
 }
 
-func parseFileAST(filename string, fset *token.FileSet, f *ast.File) (Database, error) {
+func parseFileAST(filename string, fset *token.FileSet, f *ast.File, readFile func(string) ([]byte, error)) (Database, map[string]string, error) {
 	state := newSchemaParseState(filename, fset)
+	state.readFile = readFile
 	if err := state.processFileAST(f); err != nil {
-		return Database{}, err
+		return Database{}, nil, err
 	}
 
 	enums := make([]Enum, 0, len(state.globalEnumsMap))
@@ -706,15 +952,18 @@ func parseFileAST(filename string, fset *token.FileSet, f *ast.File) (Database,
 		RLSEnabledTables:  state.rlsEnabledTables,
 		Roles:             state.roles,
 		Grants:            state.grants,
+		Seeds:             state.seeds,
 		Dependencies:      make(map[string][]string),
 	}
+	resolveEmbeddedTypeAliases(result.EmbeddedFields, state.typeAliases)
 	normalizeTableScopedNames(&result)
 	buildDependencyGraph(&result)
-	return result, nil
+	return result, state.typeAliases, nil
 }
 
 // processFileAST processes the entire AST file.
 func (s *schemaParseState) processFileAST(f *ast.File) error {
+	s.typeAliases = collectTypeAliases(f)
 	structDecls := collectStructDeclarations(f)
 	s.mapTableDirectiveStructNames(structDecls)
 
@@ -727,6 +976,58 @@ func (s *schemaParseState) processFileAST(f *ast.File) error {
 	return s.processAllFileComments(f)
 }
 
+// collectTypeAliases maps every type alias declared in f (type A = B) to the
+// name of the aliased type, so embedding an alias resolves to the struct it
+// stands for. Files without aliases return nil.
+func collectTypeAliases(f *ast.File) map[string]string {
+	var aliases map[string]string
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok || !typeSpec.Assign.IsValid() {
+				continue
+			}
+			target := embeddedTypeName(typeSpec.Type)
+			if target == "" {
+				continue
+			}
+			if aliases == nil {
+				aliases = make(map[string]string)
+			}
+			aliases[typeSpec.Name.Name] = target
+		}
+	}
+	return aliases
+}
+
+// resolveEmbeddedTypeAliases rewrites EmbeddedTypeName values that name a type
+// alias to the aliased type, following alias chains.
+func resolveEmbeddedTypeAliases(embeddedFields []EmbeddedField, aliases map[string]string) {
+	if len(aliases) == 0 {
+		return
+	}
+	for i := range embeddedFields {
+		embeddedFields[i].EmbeddedTypeName = resolveTypeAlias(embeddedFields[i].EmbeddedTypeName, aliases)
+	}
+}
+
+func resolveTypeAlias(name string, aliases map[string]string) string {
+	seen := map[string]bool{}
+	for !seen[name] {
+		target, ok := aliases[name]
+		if !ok {
+			return name
+		}
+		seen[name] = true
+		name = target
+	}
+	return name
+}
+
 func collectStructDeclarations(f *ast.File) []structDeclaration {
 	var structDecls []structDeclaration
 	for _, decl := range f.Decls {
@@ -868,6 +1169,7 @@ func (s *schemaParseState) parseFileScopedRLSPolicyComment(comment *ast.Comment,
 		StructName:          structName,
 		Name:                policyName,
 		Table:               tableName,
+		Schema:              strings.TrimSpace(kv["schema"]),
 		PolicyFor:           kv["for"],
 		ToRoles:             kv["to"],
 		UsingExpression:     kv["using"],
@@ -920,6 +1222,7 @@ func (s *schemaParseState) parseFunctionComment(comment *ast.Comment, structName
 	fn := Function{
 		StructName: structName,
 		Name:       kv["name"],
+		Schema:     kv["schema"],
 		Parameters: kv["params"],
 		Returns:    kv["returns"],
 		Language:   kv["language"],
@@ -1170,17 +1473,17 @@ func (s *schemaParseState) parseViewComment(comment *ast.Comment, structName str
 	return nil
 }
 
-func (s *schemaParseState) parseMaterializedViewComment(comment *ast.Comment, structName string) error {
+func (s *schemaParseState) parseMaterializedViewComment(comment *ast.Comment, directive, structName string) error {
 	kv := parseutils.ParseKeyValueComment(comment.Text)
 	if err := validateAttributes(
 		kv,
-		s.annotationContext(comment, "//migrator:schema:matview", structName),
+		s.annotationContext(comment, directive, structName),
 	); err != nil {
 		return err
 	}
 	if err := requireAttributes(
 		kv,
-		s.annotationContext(comment, "//migrator:schema:matview", structName),
+		s.annotationContext(comment, directive, structName),
 	); err != nil {
 		return err
 	}
@@ -1193,6 +1496,7 @@ func (s *schemaParseState) parseMaterializedViewComment(comment *ast.Comment, st
 		Name:            kv["name"],
 		Body:            kv["body"],
 		RefreshStrategy: strings.ToLower(refreshStrategy),
+		WithNoData:      kv["with_data"] == "false",
 		Comment:         kv["comment"],
 	}
 	matView.Canonicalize()
@@ -1241,6 +1545,7 @@ func (s *schemaParseState) parseRLSPolicyComment(comment *ast.Comment, structNam
 		StructName:          structName,
 		Name:                kv["name"],
 		Table:               kv["table"],
+		Schema:              strings.TrimSpace(kv["schema"]),
 		PolicyFor:           kv["for"],
 		ToRoles:             kv["to"],
 		UsingExpression:     kv["using"],
@@ -1274,21 +1579,52 @@ func (s *schemaParseState) parseRoleComment(comment *ast.Comment, structName str
 	); err != nil {
 		return err
 	}
+	if err := s.validateRolePasswordEnv(comment, structName, kv); err != nil {
+		return err
+	}
 	s.roles = append(s.roles, Role{
 		StructName:  structName,
 		Name:        kv["name"],
 		Login:       kv["login"] == "true",
 		Password:    kv["password"],
+		PasswordEnv: kv["password_env"],
 		Superuser:   kv["superuser"] == "true",
 		CreateDB:    kv["createdb"] == "true" || kv["create_db"] == "true",
 		CreateRole:  kv["createrole"] == "true" || kv["create_role"] == "true",
 		Inherit:     kv["inherit"] != "false", // Default to true unless explicitly set to false
 		Replication: kv["replication"] == "true",
+		MemberOf:    splitCommaList(kv["member_of"]),
 		Comment:     kv["comment"],
 	})
 	return nil
 }
 
+// validateRolePasswordEnv rejects a password_env that is not an environment
+// variable name, and a role declaring both password and password_env.
+func (s *schemaParseState) validateRolePasswordEnv(comment *ast.Comment, structName string, kv map[string]string) error {
+	env, ok := kv["password_env"]
+	if !ok {
+		return nil
+	}
+	var message string
+	switch {
+	case !sqlutil.IsEnvPlaceholderName(env):
+		message = fmt.Sprintf("invalid password_env %q on //migrator:schema:role %q: want an environment variable name", env, kv["name"])
+	case kv["password"] != "":
+		message = fmt.Sprintf("//migrator:schema:role %q sets both password and password_env", kv["name"])
+	default:
+		return nil
+	}
+	return &ptaherr.ParseError{
+		File:      s.filename,
+		Line:      s.annotationContext(comment, "//migrator:schema:role", structName).line,
+		Directive: "migrator:schema:role",
+		Attribute: "password_env",
+		Err:       ptaherr.ErrInvalidAttributeValue,
+		Message:   message,
+	}
+}
+
 func (s *schemaParseState) parseGrantComment(comment *ast.Comment, structName string) error {
 	kv := parseutils.ParseKeyValueComment(comment.Text)
 	if err := validateAttributes(
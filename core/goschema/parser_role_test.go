@@ -6,6 +6,7 @@ import (
 	qt "github.com/frankban/quicktest"
 
 	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/ptaherr"
 )
 
 func TestRoleAnnotationParsing(t *testing.T) {
@@ -190,6 +191,65 @@ type InheritRoles struct {
 	})
 }
 
+func TestRoleAnnotationPasswordEnv(t *testing.T) {
+	c := qt.New(t)
+
+	database, err := goschema.ParseSource("roles.go", `package test
+
+//migrator:schema:role name="app_user" login="true" password_env="APP_USER_PASSWORD"
+type AppRoles struct{}
+`)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(database.Roles, qt.HasLen, 1)
+	c.Assert(database.Roles[0].PasswordEnv, qt.Equals, "APP_USER_PASSWORD")
+	c.Assert(database.Roles[0].Password, qt.Equals, "")
+}
+
+func TestRoleAnnotationMemberOf(t *testing.T) {
+	c := qt.New(t)
+
+	database, err := goschema.ParseSource("roles.go", `package test
+
+//migrator:schema:role name="app_user" login="true" member_of="app_readonly, app_audit"
+type AppRoles struct{}
+`)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(database.Roles, qt.HasLen, 1)
+	c.Assert(database.Roles[0].MemberOf, qt.DeepEquals, []string{"app_readonly", "app_audit"})
+}
+
+func TestRoleAnnotationPasswordEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		wantErr    string
+	}{
+		{
+			name:       "not a variable name",
+			annotation: `//migrator:schema:role name="app_user" password_env="APP-PASSWORD"`,
+			wantErr:    `.*invalid password_env "APP-PASSWORD" on //migrator:schema:role "app_user": want an environment variable name.*`,
+		},
+		{
+			name:       "both password and password_env",
+			annotation: `//migrator:schema:role name="app_user" password="s3cret" password_env="APP_USER_PASSWORD"`,
+			wantErr:    `.*//migrator:schema:role "app_user" sets both password and password_env.*`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, err := goschema.ParseSource("roles.go", "package test\n\n"+tt.annotation+"\ntype AppRoles struct{}\n")
+
+			c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+			c.Assert(err, qt.ErrorMatches, tt.wantErr)
+		})
+	}
+}
+
 func TestGrantAnnotationParsing(t *testing.T) {
 	t.Run("table grant with comma privileges", func(t *testing.T) {
 		c := qt.New(t)
@@ -156,6 +156,28 @@ type User struct {
 	c.Assert(db.Fields[0].IdentityOptions, qt.Equals, "START WITH 10 INCREMENT BY 5 CACHE 3")
 }
 
+func TestParseSource_FieldOnUpdateTimestamp(t *testing.T) {
+	c := qt.New(t)
+
+	db := mustParseSource(c, "schema.go", `
+package test
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="updated_at" type="TIMESTAMP(6)" default_expr="CURRENT_TIMESTAMP(6)" on_update="CURRENT_TIMESTAMP(6)"
+	UpdatedAt string
+	//migrator:schema:field name="author_id" type="INTEGER" foreign="users(id)" on_update="CASCADE"
+	AuthorID int64
+}
+`)
+
+	c.Assert(db.Fields, qt.HasLen, 2)
+	c.Assert(db.Fields[0].UpdateExpression, qt.Equals, "CURRENT_TIMESTAMP(6)")
+	c.Assert(db.Fields[0].OnUpdate, qt.Equals, "")
+	c.Assert(db.Fields[1].UpdateExpression, qt.Equals, "")
+	c.Assert(db.Fields[1].OnUpdate, qt.Equals, "CASCADE")
+}
+
 func TestParseSource_FieldIdentityAttributesRejectInvalidGeneration(t *testing.T) {
 	c := qt.New(t)
 
@@ -287,6 +309,31 @@ type User struct {
 	}})
 }
 
+func TestParseMaterializedViewAnnotation_LongFormWithNoDataAndIndex(t *testing.T) {
+	c := qt.New(t)
+
+	db := mustParseSource(c, "matview.go", `
+package test
+
+//migrator:schema:materialized_view name="user_stats" body="SELECT user_id, COUNT(*) AS cnt FROM orders GROUP BY user_id" with_data="false"
+type UserStats struct {
+	//migrator:schema:index name="idx_user_stats_user" fields="user_id" unique="true"
+	UserID int64
+}
+`)
+
+	c.Assert(db.MaterializedViews, qt.DeepEquals, []goschema.MaterializedView{{
+		StructName:      "UserStats",
+		Name:            "user_stats",
+		Body:            "SELECT user_id, COUNT(*) AS cnt FROM orders GROUP BY user_id",
+		RefreshStrategy: "manual",
+		WithNoData:      true,
+	}})
+	c.Assert(db.Tables, qt.HasLen, 0)
+	c.Assert(db.Indexes, qt.HasLen, 1)
+	c.Assert(db.Indexes[0].StructName, qt.Equals, "UserStats")
+}
+
 func TestParseSchemaObjectAnnotations_RejectsInvalidAttributes(t *testing.T) {
 	c := qt.New(t)
 
@@ -760,6 +807,20 @@ func TestParseFunctionComment(t *testing.T) {
 				Body:       "BEGIN RETURN current_setting('app.current_tenant_id', true); END;",
 			},
 		},
+		{
+			name:    "Function in a schema",
+			comment: `//migrator:schema:function name="get_current_tenant_id" schema="app" returns="TEXT" language="sql" body="SELECT current_setting('app.current_tenant_id')"`,
+			expected: goschema.Function{
+				StructName: "TestStruct",
+				Name:       "get_current_tenant_id",
+				Schema:     "app",
+				Returns:    "text",
+				Language:   "sql",
+				Security:   "INVOKER",
+				Volatility: "VOLATILE",
+				Body:       "SELECT current_setting('app.current_tenant_id')",
+			},
+		},
 		{
 			name:    "Function with comment",
 			comment: `//migrator:schema:function name="test_func" returns="INTEGER" language="sql" comment="Test function for unit tests"`,
@@ -930,6 +991,7 @@ func TestParseRLSEnableComment(t *testing.T) {
 
 func TestParseSource_ConstraintComment(t *testing.T) {
 	nullsDistinct := false
+	nullsDistinctDefault := true
 	tests := []struct {
 		name     string
 		comment  string
@@ -969,6 +1031,17 @@ func TestParseSource_ConstraintComment(t *testing.T) {
 				CheckExpression: "price > 0",
 			},
 		},
+		{
+			name:    "CHECK constraint added NOT VALID",
+			comment: `//migrator:schema:constraint name="positive_price" type="CHECK" check="price > 0" not_valid="true"`,
+			expected: goschema.Constraint{
+				StructName:      "TestStruct",
+				Name:            "positive_price",
+				Type:            "CHECK",
+				CheckExpression: "price > 0",
+				NotValid:        true,
+			},
+		},
 		{
 			name:    "UNIQUE constraint with multiple columns",
 			comment: `//migrator:schema:constraint name="unique_user_email" type="UNIQUE" columns="user_id, email" include="updated_at"`,
@@ -1017,6 +1090,28 @@ func TestParseSource_ConstraintComment(t *testing.T) {
 				NullsDistinct: &nullsDistinct,
 			},
 		},
+		{
+			name:    "UNIQUE constraint with nulls_not_distinct",
+			comment: `//migrator:schema:constraint name="unique_email" type="UNIQUE" columns="email" nulls_not_distinct="true"`,
+			expected: goschema.Constraint{
+				StructName:    "TestStruct",
+				Name:          "unique_email",
+				Type:          "UNIQUE",
+				Columns:       []string{"email"},
+				NullsDistinct: &nullsDistinct,
+			},
+		},
+		{
+			name:    "UNIQUE constraint nulls_distinct wins over nulls_not_distinct",
+			comment: `//migrator:schema:constraint name="unique_email" type="UNIQUE" columns="email" nulls_distinct="true" nulls_not_distinct="true"`,
+			expected: goschema.Constraint{
+				StructName:    "TestStruct",
+				Name:          "unique_email",
+				Type:          "UNIQUE",
+				Columns:       []string{"email"},
+				NullsDistinct: &nullsDistinctDefault,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1038,9 +1133,11 @@ func TestParseSource_ConstraintComment(t *testing.T) {
 			c.Assert(constraint.CheckExpression, qt.Equals, tt.expected.CheckExpression)
 			c.Assert(constraint.Columns, qt.DeepEquals, tt.expected.Columns)
 			c.Assert(constraint.IncludeColumns, qt.DeepEquals, tt.expected.IncludeColumns)
+			c.Assert(constraint.NullsDistinct, qt.DeepEquals, tt.expected.NullsDistinct)
 			c.Assert(constraint.ForeignTable, qt.Equals, tt.expected.ForeignTable)
 			c.Assert(constraint.ForeignColumn, qt.Equals, tt.expected.ForeignColumn)
 			c.Assert(constraint.OnDelete, qt.Equals, tt.expected.OnDelete)
+			c.Assert(constraint.NotValid, qt.Equals, tt.expected.NotValid)
 			c.Assert(constraint.Comment, qt.Equals, tt.expected.Comment)
 		})
 	}
@@ -1062,6 +1159,23 @@ type SchemaObjects struct{}
 	}})
 }
 
+func TestParseSource_EnumRenameFrom(t *testing.T) {
+	c := qt.New(t)
+
+	source := `package test
+
+//migrator:schema:enum name="order_status" values="new,paid" rename_from="order_state"
+type SchemaObjects struct{}
+`
+	db := mustParseSource(c, "enums.go", source)
+
+	c.Assert(db.Enums, qt.DeepEquals, []goschema.Enum{{
+		Name:       "order_status",
+		Values:     []string{"new", "paid"},
+		RenameFrom: "order_state",
+	}})
+}
+
 // TestParseField_UnknownAttributePanics verifies that field annotations
 // containing an unrecognized attribute key cause the parser to panic with a
 // clear message. This is the safety net that surfaces typos like
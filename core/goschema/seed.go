@@ -0,0 +1,172 @@
+package goschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/stokaro/ptah/core/goschema/internal/parseutils"
+	"github.com/stokaro/ptah/core/ptaherr"
+)
+
+// seedNull is the seed value that inserts SQL NULL.
+const seedNull = "NULL"
+
+// parseSeedComment parses //migrator:schema:seed into a Seed. Rows come from
+// the inline rows attribute or, with seed_file, from a JSON or YAML file
+// resolved relative to the Go source file.
+func (s *schemaParseState) parseSeedComment(comment *ast.Comment, structName string) error {
+	kv := parseutils.ParseKeyValueComment(comment.Text)
+	ctx := s.annotationContext(comment, "//migrator:schema:seed", structName)
+	if err := validateAttributes(kv, ctx); err != nil {
+		return err
+	}
+
+	seed := Seed{
+		StructName: structName,
+		Table:      strings.TrimSpace(kv["table"]),
+		Columns:    splitCommaList(kv["columns"]),
+		File:       strings.TrimSpace(kv["seed_file"]),
+		Always:     kv["always"] == "true",
+	}
+	switch {
+	case seed.File != "" && strings.TrimSpace(kv["rows"]) != "":
+		return seedAnnotationError(ctx, "rows", "cannot combine rows with seed_file")
+	case seed.File != "":
+		columns, rows, err := s.loadSeedFile(seed.File, seed.Columns)
+		if err != nil {
+			return seedAnnotationError(ctx, "seed_file", err.Error())
+		}
+		seed.Columns, seed.Rows = columns, rows
+	default:
+		seed.Rows = splitSeedRows(kv["rows"])
+	}
+
+	if len(seed.Columns) == 0 || len(seed.Rows) == 0 {
+		return seedAnnotationError(ctx, "columns", "declares no columns or no rows")
+	}
+	for i, row := range seed.Rows {
+		if len(row) != len(seed.Columns) {
+			return seedAnnotationError(ctx, "rows",
+				fmt.Sprintf("row %d has %d values but %d columns are declared", i+1, len(row), len(seed.Columns)))
+		}
+	}
+	s.seeds = append(s.seeds, seed)
+	return nil
+}
+
+func seedAnnotationError(ctx annotationErrorContext, attribute, problem string) error {
+	return &ptaherr.ParseError{
+		File:      ctx.file,
+		Line:      ctx.line,
+		Directive: "migrator:schema:seed",
+		Attribute: attribute,
+		Err:       ptaherr.ErrInvalidAttributeValue,
+		Message:   fmt.Sprintf("//migrator:schema:seed at %s %s", ctx.location, problem),
+	}
+}
+
+// splitSeedRows splits inline rows on semicolons and each row's values on
+// commas. Values are trimmed; empty rows are skipped.
+func splitSeedRows(value string) [][]string {
+	var rows [][]string
+	for _, row := range strings.Split(value, ";") {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		values := strings.Split(row, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		rows = append(rows, values)
+	}
+	return rows
+}
+
+// loadSeedFile reads seed rows from a JSON or YAML file holding a list of row
+// objects. When the annotation lists no columns, the columns are the sorted
+// keys of all objects. A key missing from an object, or a null value, inserts
+// SQL NULL.
+func (s *schemaParseState) loadSeedFile(name string, columns []string) ([]string, [][]string, error) {
+	if s.readFile == nil {
+		return nil, nil, fmt.Errorf("cannot read seed_file %q: no source directory", name)
+	}
+	data, err := s.readFile(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read seed_file %q: %w", name, err)
+	}
+
+	var objects []map[string]any
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&objects)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &objects)
+	default:
+		return nil, nil, fmt.Errorf("seed_file %q must be a .json, .yaml or .yml file", name)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot decode seed_file %q: %w", name, err)
+	}
+
+	if len(columns) == 0 {
+		for _, object := range objects {
+			for column := range object {
+				if !slices.Contains(columns, column) {
+					columns = append(columns, column)
+				}
+			}
+		}
+		slices.Sort(columns)
+	}
+	rows := make([][]string, 0, len(objects))
+	for _, object := range objects {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = seedFileValue(object[column])
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+func seedFileValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return seedNull
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// osRelativeFileReader reads files named relative to the directory of the Go
+// source file filename.
+func osRelativeFileReader(filename string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(filename), name)
+		}
+		return os.ReadFile(name)
+	}
+}
+
+// fsRelativeFileReader reads files from fsys named relative to the directory
+// of the Go source file filename.
+func fsRelativeFileReader(fsys fs.FS, filename string) func(string) ([]byte, error) {
+	return func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, path.Join(path.Dir(filename), name))
+	}
+}
@@ -0,0 +1,95 @@
+package goschema_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/ptaherr"
+)
+
+func TestParseSeedAnnotation_InlineRows(t *testing.T) {
+	const src = `package fixture
+
+//migrator:schema:table name="roles"
+//migrator:schema:seed columns="id, name" rows="1,admin; 2,user; 3,NULL" always="true"
+type Role struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+	//migrator:schema:field name="name" type="TEXT"
+	Name string
+}
+`
+	c := qt.New(t)
+	db := mustParseSource(c, "fixture.go", src)
+	c.Assert(db.Seeds, qt.DeepEquals, []goschema.Seed{{
+		StructName: "Role",
+		Table:      "roles",
+		Columns:    []string{"id", "name"},
+		Rows:       [][]string{{"1", "admin"}, {"2", "user"}, {"3", "NULL"}},
+		Always:     true,
+	}})
+}
+
+func TestParseSeedAnnotation_SeedFiles(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"models/roles.go": &fstest.MapFile{Data: []byte(`package models
+
+//migrator:schema:table name="roles"
+//migrator:schema:seed seed_file="seeds/roles.json"
+type Role struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+}
+
+//migrator:schema:table name="countries"
+//migrator:schema:seed columns="code,name" seed_file="seeds/countries.yaml"
+type Country struct {
+	//migrator:schema:field name="code" type="TEXT" primary="true"
+	Code string
+}
+`)},
+		"models/seeds/roles.json":     &fstest.MapFile{Data: []byte(`[{"name": "admin", "id": 1}, {"id": 2, "name": "user", "note": null}]`)},
+		"models/seeds/countries.yaml": &fstest.MapFile{Data: []byte("- code: DE\n  name: Germany\n- code: FR\n  name: France\n")},
+	}
+
+	db, err := goschema.ParseFS(fsys, ".")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(db.Seeds, qt.HasLen, 2)
+	c.Assert(db.Seeds[0].Table, qt.Equals, "roles")
+	c.Assert(db.Seeds[0].File, qt.Equals, "seeds/roles.json")
+	c.Assert(db.Seeds[0].Columns, qt.DeepEquals, []string{"id", "name", "note"})
+	c.Assert(db.Seeds[0].Rows, qt.DeepEquals, [][]string{{"1", "admin", "NULL"}, {"2", "user", "NULL"}})
+	c.Assert(db.Seeds[1].Table, qt.Equals, "countries")
+	c.Assert(db.Seeds[1].Rows, qt.DeepEquals, [][]string{{"DE", "Germany"}, {"FR", "France"}})
+}
+
+func TestParseSeedAnnotation_InvalidSeeds(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		attribute  string
+	}{
+		{name: "row width", annotation: `//migrator:schema:seed table="roles" columns="id,name" rows="1,admin;2"`, attribute: "rows"},
+		{name: "no rows", annotation: `//migrator:schema:seed table="roles" columns="id,name"`, attribute: "columns"},
+		{name: "rows and file", annotation: `//migrator:schema:seed table="roles" columns="id" rows="1" seed_file="roles.json"`, attribute: "rows"},
+		{name: "missing file", annotation: `//migrator:schema:seed table="roles" seed_file="missing.json"`, attribute: "seed_file"},
+		{name: "unsupported file", annotation: `//migrator:schema:seed table="roles" seed_file="roles.csv"`, attribute: "seed_file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			_, err := goschema.ParseSource("testdata/does-not-exist/schema.go", "package models\n\n"+tt.annotation+"\ntype Role struct{}\n")
+
+			var parseErr *ptaherr.ParseError
+			c.Assert(err, qt.ErrorAs, &parseErr)
+			c.Assert(parseErr.Directive, qt.Equals, "migrator:schema:seed")
+			c.Assert(parseErr.Attribute, qt.Equals, tt.attribute)
+			c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+		})
+	}
+}
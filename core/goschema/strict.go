@@ -0,0 +1,322 @@
+package goschema
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/core/ptaherr"
+	"github.com/stokaro/ptah/internal/annotationmeta"
+	"github.com/stokaro/ptah/internal/annotationparse"
+)
+
+// embeddedModes lists the mode values //migrator:embedded accepts.
+var embeddedModes = []string{"inline", "json", "relation", "skip"}
+
+// AnnotationError reports one annotation problem found by strict parsing,
+// with the position of the offending directive or attribute.
+type AnnotationError struct {
+	File string
+	// Line and Column are 1-based. Column is a byte offset and zero when only
+	// the line is known.
+	Line      int
+	Column    int
+	Directive string
+	Attribute string
+	// Err is the ptaherr sentinel classifying the problem, such as
+	// ptaherr.ErrUnknownAttribute.
+	Err     error
+	Message string
+}
+
+func (e AnnotationError) Error() string {
+	position := fmt.Sprintf("%s:%d", e.File, e.Line)
+	if e.Column > 0 {
+		position += fmt.Sprintf(":%d", e.Column)
+	}
+	return position + ": " + e.Message
+}
+
+func (e AnnotationError) Unwrap() error {
+	return e.Err
+}
+
+// ParseDirStrict parses rootDir like ParseDir and also reports annotation
+// problems ParseDir ignores or stops at: comments starting with //migrator:
+// that name no directive, misspelled //migrator: prefixes, every unknown or
+// missing attribute on a directive, and unknown //migrator:embedded modes.
+//
+// The database is nil whenever annotation errors are returned; callers should
+// print every error rather than the first one. The error result is reserved
+// for failures that are not about annotations, such as unreadable files or
+// Go syntax errors.
+func ParseDirStrict(rootDir string) (*Database, []AnnotationError, error) {
+	return ParseFSStrict(os.DirFS(rootDir), ".")
+}
+
+// ParseFSStrict is ParseDirStrict for a directory within fsys. File names in
+// the returned errors are relative to fsys.
+func ParseFSStrict(fsys fs.FS, rootDir string) (*Database, []AnnotationError, error) {
+	var annotationErrors []AnnotationError
+	err := fs.WalkDir(fsys, rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isSchemaSourcePath(path) {
+			return nil
+		}
+		source, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		annotationErrors = append(annotationErrors, ValidateAnnotations(path, source)...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	database, err := ParseFS(fsys, rootDir)
+	if err != nil {
+		parseErrors, otherErr := annotationParseErrors(err)
+		if otherErr != nil {
+			return nil, nil, otherErr
+		}
+		annotationErrors = appendNewAnnotationErrors(annotationErrors, parseErrors)
+	}
+	if len(annotationErrors) > 0 {
+		sortAnnotationErrors(annotationErrors)
+		return nil, annotationErrors, nil
+	}
+	return database, nil, nil
+}
+
+// ValidateAnnotations checks the //migrator: comments of one Go source file
+// without parsing the schema. It reports unknown directives, misspelled
+// //migrator: prefixes, a space between // and migrator: (which makes the
+// parser skip the line), unknown and missing attributes, and unknown
+// //migrator:embedded modes. Attribute values are only checked by the schema
+// parser; ParseDirStrict runs both.
+func ValidateAnnotations(filename string, source []byte) []AnnotationError {
+	text := string(source)
+	var annotationErrors []AnnotationError
+	for lineNo, line := range strings.Split(text, "\n") {
+		if annotationError, ok := misspelledAnnotationPrefix(filename, lineNo, line); ok {
+			annotationErrors = append(annotationErrors, annotationError)
+		}
+	}
+	for _, annotation := range annotationparse.Scan(text) {
+		annotationErrors = append(annotationErrors, validateScannedAnnotation(filename, annotation)...)
+	}
+	sortAnnotationErrors(annotationErrors)
+	return annotationErrors
+}
+
+func validateScannedAnnotation(filename string, annotation annotationparse.Annotation) []AnnotationError {
+	at := func(position annotationparse.Position) AnnotationError {
+		return AnnotationError{
+			File:      filename,
+			Line:      position.Line + 1,
+			Column:    position.Character + 1,
+			Directive: annotation.Directive,
+		}
+	}
+	directiveError := at(annotation.DirectiveRange.Start)
+	if !annotation.Known {
+		directiveError.Err = ptaherr.ErrUnknownDirective
+		directiveError.Message = fmt.Sprintf("unknown annotation directive //%s%s",
+			annotation.Directive, suggestion(annotation.Directive, directiveNames(), "//"))
+		return []AnnotationError{directiveError}
+	}
+	if annotation.DirectiveRange.Start.Character != annotation.CommentRange.Start.Character+len("//") {
+		directiveError.Err = ptaherr.ErrUnknownDirective
+		directiveError.Message = fmt.Sprintf("//%s is ignored because of the space after //; write //%s",
+			annotation.Directive, annotation.Directive)
+		return []AnnotationError{directiveError}
+	}
+
+	var annotationErrors []AnnotationError
+	seen := make(map[string]bool, len(annotation.Attributes))
+	for _, attr := range annotation.Attributes {
+		seen[attr.Name] = true
+		attrError := at(attr.Range.Start)
+		attrError.Attribute = attr.Name
+		switch {
+		case !annotationmeta.AllowsAttribute(annotation.Directive, attr.Name):
+			attrError.Err = ptaherr.ErrUnknownAttribute
+			attrError.Message = fmt.Sprintf("unknown attribute %q on //%s%s",
+				attr.Name, annotation.Directive, suggestion(attr.Name, annotationmeta.AttributeNames(annotation.Directive), ""))
+		case annotation.Directive == "migrator:embedded" && attr.Name == "mode" && !slices.Contains(embeddedModes, attr.Value):
+			attrError = at(attr.ValueRange.Start)
+			attrError.Attribute = attr.Name
+			attrError.Err = ptaherr.ErrInvalidAttributeValue
+			attrError.Message = fmt.Sprintf("unknown embedded mode %q on //%s: expected one of %s%s",
+				attr.Value, annotation.Directive, strings.Join(embeddedModes, ", "), suggestion(attr.Value, embeddedModes, ""))
+		default:
+			continue
+		}
+		annotationErrors = append(annotationErrors, attrError)
+	}
+	for _, name := range annotationmeta.RequiredAttributes(annotation.Directive) {
+		if seen[name] {
+			continue
+		}
+		missing := directiveError
+		missing.Attribute = name
+		missing.Err = ptaherr.ErrMissingRequiredAttribute
+		missing.Message = fmt.Sprintf("missing required attribute %q on //%s", name, annotation.Directive)
+		annotationErrors = append(annotationErrors, missing)
+	}
+	return annotationErrors
+}
+
+// misspelledAnnotationPrefix reports a comment such as //migator:schema:field
+// whose prefix is a near miss of migrator: and whose remainder names a known
+// directive. Such comments are otherwise indistinguishable from prose.
+func misspelledAnnotationPrefix(filename string, lineNo int, line string) (AnnotationError, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, "//") {
+		return AnnotationError{}, false
+	}
+	bodyStart := len(line) - len(trimmed) + len("//")
+	body := strings.TrimLeft(line[bodyStart:], " \t")
+	bodyStart = len(line) - len(body)
+	prefix, rest, found := strings.Cut(body, ":")
+	if !found || prefix == "migrator" || !isLetters(prefix) {
+		return AnnotationError{}, false
+	}
+	if !strings.EqualFold(prefix, "migrator") && editDistance(strings.ToLower(prefix), "migrator") > 2 {
+		return AnnotationError{}, false
+	}
+	directive, ok := annotationmeta.MatchCommentDirective("//migrator:" + rest)
+	if !ok {
+		return AnnotationError{}, false
+	}
+	return AnnotationError{
+		File:      filename,
+		Line:      lineNo + 1,
+		Column:    bodyStart + 1,
+		Directive: directive.Name,
+		Err:       ptaherr.ErrUnknownDirective,
+		Message:   fmt.Sprintf("unknown annotation prefix %q; did you mean //%s?", prefix+":", directive.Name),
+	}, true
+}
+
+// annotationParseErrors converts the *ptaherr.ParseError values joined in err
+// into annotation errors. Any other error is returned as is.
+func annotationParseErrors(err error) ([]AnnotationError, error) {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var annotationErrors []AnnotationError
+	for _, err := range errs {
+		var parseErr *ptaherr.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil, err
+		}
+		annotationErrors = append(annotationErrors, AnnotationError{
+			File:      parseErr.File,
+			Line:      parseErr.Line,
+			Directive: parseErr.Directive,
+			Attribute: parseErr.Attribute,
+			Err:       parseErr.Err,
+			Message:   parseErr.Error(),
+		})
+	}
+	return annotationErrors, nil
+}
+
+// appendNewAnnotationErrors appends the parser's errors that ValidateAnnotations
+// has not already reported for the same line and attribute. The parser stops
+// at the first problem of a file, so it mostly adds invalid values.
+func appendNewAnnotationErrors(annotationErrors, parseErrors []AnnotationError) []AnnotationError {
+	type key struct {
+		file      string
+		line      int
+		attribute string
+		err       error
+	}
+	seen := make(map[key]bool, len(annotationErrors))
+	for _, e := range annotationErrors {
+		seen[key{e.File, e.Line, e.Attribute, e.Err}] = true
+	}
+	for _, e := range parseErrors {
+		if !seen[key{e.File, e.Line, e.Attribute, e.Err}] {
+			annotationErrors = append(annotationErrors, e)
+		}
+	}
+	return annotationErrors
+}
+
+func sortAnnotationErrors(annotationErrors []AnnotationError) {
+	slices.SortStableFunc(annotationErrors, func(a, b AnnotationError) int {
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+}
+
+func directiveNames() []string {
+	directives := annotationmeta.Directives()
+	names := make([]string, 0, len(directives))
+	for _, directive := range directives {
+		names = append(names, directive.Name)
+	}
+	return names
+}
+
+// suggestion returns a "; did you mean ...?" hint naming the candidate closest
+// to name, or "" when none is within two edits.
+func suggestion(name string, candidates []string, prefix string) string {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf("; did you mean %s%s?", prefix, best)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func isLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
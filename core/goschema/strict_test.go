@@ -0,0 +1,181 @@
+package goschema_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/ptaherr"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+	}{
+		{
+			name: "valid annotations",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true" platform.mysql.type="INT AUTO_INCREMENT"
+	ID int64
+}
+`,
+		},
+		{
+			name: "misspelled prefix",
+			source: `package entities
+
+//migator:schema:table name="users"
+type User struct{}
+`,
+			want: []string{`user.go:3:3: unknown annotation prefix "migator:"; did you mean //migrator:schema:table?`},
+		},
+		{
+			name: "space after the comment marker",
+			source: `package entities
+
+// migrator:schema:table name="users"
+type User struct{}
+`,
+			want: []string{`user.go:3:4: //migrator:schema:table is ignored because of the space after //; write //migrator:schema:table`},
+		},
+		{
+			name: "unknown directive",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:feild name="id" type="SERIAL"
+	ID int64
+}
+`,
+			want: []string{`user.go:5:4: unknown annotation directive //migrator:schema:feild; did you mean //migrator:schema:field?`},
+		},
+		{
+			name: "every unknown attribute is listed",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" tpye="SERIAL" primay="true"
+	ID int64
+}
+`,
+			want: []string{
+				`user.go:5:36: unknown attribute "tpye" on //migrator:schema:field; did you mean type?`,
+				`user.go:5:50: unknown attribute "primay" on //migrator:schema:field; did you mean primary?`,
+			},
+		},
+		{
+			name: "missing required attribute",
+			source: `package entities
+
+//migrator:schema:enum values="active,inactive"
+type Status string
+`,
+			want: []string{`user.go:3:3: missing required attribute "name" on //migrator:schema:enum`},
+		},
+		{
+			name: "unknown embedded mode",
+			source: `package entities
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:embedded mode="inlined"
+	Timestamps
+}
+`,
+			want: []string{`user.go:5:27: unknown embedded mode "inlined" on //migrator:embedded: expected one of inline, json, relation, skip; did you mean inline?`},
+		},
+		{
+			name: "prose is not an annotation",
+			source: `package entities
+
+// mirror: schema:table is not a directive, and neither is this note:
+// see migrator docs.
+type User struct{}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			var got []string
+			for _, annotationError := range goschema.ValidateAnnotations("user.go", []byte(tt.source)) {
+				got = append(got, annotationError.Error())
+			}
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestParseFSStrict_ReportsEveryProblem(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"models/user.go": &fstest.MapFile{Data: []byte(`package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:field name="email" tpye="TEXT"
+	Email string
+}
+`)},
+		"models/post.go": &fstest.MapFile{Data: []byte(`package models
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true" identity_generation="SOMETIMES"
+	ID int64
+
+	//migrator:schema:feild name="title" type="TEXT"
+	Title string
+}
+`)},
+	}
+
+	database, annotationErrors, err := goschema.ParseFSStrict(fsys, "models")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(database, qt.IsNil)
+	c.Assert(annotationErrors, qt.HasLen, 3)
+	c.Assert(annotationErrors[0].File, qt.Equals, "models/post.go")
+	c.Assert(annotationErrors[0].Line, qt.Equals, 5)
+	c.Assert(annotationErrors[0].Attribute, qt.Equals, "identity_generation")
+	c.Assert(annotationErrors[0], qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+	c.Assert(annotationErrors[1].Line, qt.Equals, 8)
+	c.Assert(annotationErrors[1], qt.ErrorIs, ptaherr.ErrUnknownDirective)
+	c.Assert(annotationErrors[2].File, qt.Equals, "models/user.go")
+	c.Assert(annotationErrors[2].Line, qt.Equals, 8)
+	c.Assert(annotationErrors[2].Attribute, qt.Equals, "tpye")
+	c.Assert(annotationErrors[2], qt.ErrorIs, ptaherr.ErrUnknownAttribute)
+}
+
+func TestParseFSStrict_CleanSchema(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"models/user.go": &fstest.MapFile{Data: []byte(`package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+}
+`)},
+	}
+
+	database, annotationErrors, err := goschema.ParseFSStrict(fsys, "models")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(annotationErrors, qt.HasLen, 0)
+	c.Assert(database.Tables, qt.HasLen, 1)
+}
@@ -89,6 +89,27 @@ type User struct {
 	c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
 }
 
+func TestParseSource_FieldForeignKeyColumnCountMismatch(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := goschema.ParseSource("schema.go", `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="user_id" type="INT" foreign="users(tenant_id,id)"
+	UserID int64
+}
+`)
+
+	var parseErr *ptaherr.ParseError
+	c.Assert(err, qt.ErrorAs, &parseErr)
+	c.Assert(parseErr.File, qt.Equals, "schema.go")
+	c.Assert(parseErr.Line, qt.Equals, 5)
+	c.Assert(parseErr.Attribute, qt.Equals, "foreign")
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrInvalidAttributeValue)
+	c.Assert(err, qt.ErrorMatches, `(?s).*references 2 columns in "users\(tenant_id,id\)" but declares one column.*`)
+}
+
 func TestParseFS_AccumulatesInvalidAttributeValues(t *testing.T) {
 	c := qt.New(t)
 
@@ -6,6 +6,7 @@ package goschema
 import (
 	"fmt"
 	"hash/fnv"
+	"maps"
 	"strings"
 )
 
@@ -49,6 +50,7 @@ type Database struct {
 	RLSEnabledTables           []RLSEnabledTable              // Tables with RLS enabled
 	Roles                      []Role                         // PostgreSQL roles
 	Grants                     []Grant                        // PostgreSQL privilege grants
+	Seeds                      []Seed                         // Rows inserted when their table is created
 	Dependencies               map[string][]string            // table -> list of tables it depends on
 	FunctionDependencies       map[string][]string            // function -> list of functions it depends on
 	SelfReferencingForeignKeys map[string][]SelfReferencingFK // table -> list of self-referencing foreign keys
@@ -83,14 +85,14 @@ type Schema struct {
 //	    Meta UserMeta  // Results in: metadata JSONB column
 //
 //	    //migrator:embedded mode="relation" field="company_id" ref="companies(id)"
-//	    Company Company  // Results in: company_id INTEGER + FK constraint
+//	    Company Company  // Results in: company_id with the type of companies.id + FK constraint
 //	}
 type EmbeddedField struct {
 	StructName       string                       // The struct that contains this embedded field
 	Mode             string                       // inline, json, relation, skip
 	Prefix           string                       // For inline mode - prefix for field names
 	Name             string                       // For json mode - column name
-	Type             string                       // For json mode - column type (JSON/JSONB)
+	Type             string                       // For json mode - column type (JSON/JSONB); for relation mode - foreign key column type
 	Nullable         bool                         // Whether the field can be null
 	Index            bool                         // Whether to create an index
 	Field            string                       // For relation mode - foreign key field name
@@ -98,10 +100,71 @@ type EmbeddedField struct {
 	OnDelete         string                       // For relation mode - ON DELETE action
 	OnUpdate         string                       // For relation mode - ON UPDATE action
 	Comment          string                       // Comment for the field/column
+	DefaultExpr      string                       // For json mode - column default expression (e.g., "'{}'::jsonb")
 	EmbeddedTypeName string                       // The name of the embedded type (e.g., "Timestamps")
 	Overrides        map[string]map[string]string // Platform-specific overrides
 }
 
+// JSONColumn returns the column a json-mode embedded field generates on
+// structName's table. The name defaults to the lowercased embedded type name
+// with a "_data" suffix and the type to JSONB. The default expression and
+// platform overrides declared on the embedded annotation are carried over, so
+// the generator and the schema comparison see the same column.
+func (e EmbeddedField) JSONColumn(structName string) Field {
+	columnName := e.Name
+	if columnName == "" {
+		// Auto-generate column name: "Meta" -> "meta_data"
+		columnName = strings.ToLower(e.EmbeddedTypeName) + "_data"
+	}
+	columnType := e.Type
+	if columnType == "" {
+		columnType = "JSONB" // Default to PostgreSQL JSONB for best performance
+	}
+	return Field{
+		StructName:  structName,
+		FieldName:   e.EmbeddedTypeName,
+		Name:        columnName,
+		Type:        columnType,
+		Nullable:    e.Nullable,
+		DefaultExpr: e.DefaultExpr,
+		Comment:     e.Comment,
+		Overrides:   e.Overrides, // Platform-specific type overrides (JSON vs JSONB vs TEXT)
+	}
+}
+
+// RelationColumnType returns the column type and platform overrides of the
+// foreign key column a relation-mode embedded field generates. Type is set
+// either explicitly with type="..." or, after parsing, from the referenced
+// column. An unresolved ref keeps the legacy guess: VARCHAR(36) when the ref
+// mentions a string or UUID type and INTEGER (INT on MySQL and MariaDB)
+// otherwise. Overrides declared on the embedded annotation win.
+func (e EmbeddedField) RelationColumnType() (columnType string, overrides map[string]map[string]string) {
+	columnType = e.Type
+	if columnType == "" {
+		columnType = "INTEGER"
+		if strings.Contains(e.Ref, "VARCHAR") || strings.Contains(e.Ref, "TEXT") ||
+			strings.Contains(strings.ToLower(e.Ref), "uuid") {
+			columnType = "VARCHAR(36)"
+		}
+	}
+
+	overrides = make(map[string]map[string]string)
+	if strings.EqualFold(columnType, "INTEGER") {
+		// MySQL/MariaDB use INT for SERIAL types, so foreign keys should also use INT
+		overrides["mysql"] = map[string]string{"type": "INT"}
+		overrides["mariadb"] = map[string]string{"type": "INT"}
+	}
+	for platformName, values := range e.Overrides {
+		merged := maps.Clone(overrides[platformName])
+		if merged == nil {
+			merged = make(map[string]string, len(values))
+		}
+		maps.Copy(merged, values)
+		overrides[platformName] = merged
+	}
+	return columnType, overrides
+}
+
 // Field represents a database column/field definition parsed from Go struct field annotations.
 // This is the core building block for table schema generation, containing all the metadata
 // needed to generate appropriate CREATE TABLE column definitions for different database platforms.
@@ -161,6 +224,10 @@ type Field struct {
 	GeneratedExpression string
 	// GeneratedKind stores the generated column kind, such as VIRTUAL or STORED.
 	GeneratedKind string
+	// TypeCast stores the SQL expression that converts the existing column
+	// value to Type when a safe type change copies it into a new column,
+	// for example "price::integer". Empty means a plain cast.
+	TypeCast string
 	// UpdateExpression stores MySQL/MariaDB ON UPDATE expressions such as CURRENT_TIMESTAMP(6).
 	UpdateExpression string
 	// Charset stores the column character set for MySQL-compatible dialects.
@@ -252,7 +319,9 @@ type Index struct {
 	NullsDistinct *bool
 
 	// Type carries the dialect-specific index type. For PostgreSQL this is
-	// GIN/GIST/BTREE/HASH; for ClickHouse data-skipping indexes it is
+	// GIN/GIST/BTREE/HASH; for MySQL and MariaDB it is FULLTEXT/SPATIAL
+	// (rendered as an index prefix) or BTREE/HASH (rendered as USING); for
+	// ClickHouse data-skipping indexes it is
 	// "minmax"/"set(N)"/"bloom_filter(p)"/"tokenbf_v1(...)"/etc.
 	Type string
 	// Parser carries a MySQL FULLTEXT parser name, for example ngram.
@@ -266,6 +335,9 @@ type Index struct {
 	// StorageParams carries PostgreSQL index storage parameters rendered as
 	// WITH (key='value'), for example pages_per_range for BRIN indexes.
 	StorageParams map[string]string
+	// Visible carries MySQL index visibility (MariaDB: IGNORED). Nil means
+	// the attribute was not specified, which is the visible default.
+	Visible *bool
 	// TableName is the cross-table association (overrides StructName-based
 	// resolution when set).
 	TableName string
@@ -329,6 +401,11 @@ type Constraint struct {
 	OnDelete       string   // ON DELETE action
 	OnUpdate       string   // ON UPDATE action
 
+	// NotValid asks the PostgreSQL planner to add this CHECK or FOREIGN KEY
+	// constraint NOT VALID and validate it in a separate, later statement when
+	// it is added to an existing table.
+	NotValid bool
+
 	Comment string // Constraint comment/description
 }
 
@@ -395,6 +472,16 @@ type Extension struct {
 //	    //migrator:schema:field name="role_id" type="INTEGER" foreign="roles(id)"
 //	    RoleID int64
 //	}
+//
+// PostgreSQL table inheritance is declared with the inherits attribute. The
+// child struct declares only its own columns; the parent's columns are
+// inherited by the database:
+//
+//	//migrator:schema:table name="cities_capitals" inherits="cities"
+//	type Capital struct {
+//	    //migrator:schema:field name="state" type="CHAR(2)"
+//	    State string
+//	}
 type Table struct {
 	StructName    string   // Name of the Go struct this table represents
 	Name          string   // Database table name
@@ -406,6 +493,7 @@ type Table struct {
 	Strict        bool     // SQLite STRICT table option
 	WithoutRowID  bool     // SQLite WITHOUT ROWID table option
 	Comment       string   // Table comment/description
+	Owner         string   // PostgreSQL owning role; compared only when set
 	PrimaryKey    []string // Composite primary key column names
 	// PrimaryKeyParts carries dialect-specific metadata for composite primary
 	// key elements, such as MySQL prefix lengths and DESC ordering.
@@ -415,6 +503,8 @@ type Table struct {
 	PrimaryKeyInclude []string
 	Checks            []string                     // Table-level check constraints
 	Partition         *PartitionSpec               // PostgreSQL table partitioning metadata
+	Inherits          []string                     // PostgreSQL parent tables (INHERITS)
+	ExternalColumns   []string                     // Columns managed outside ptah; never dropped or compared
 	CustomSQL         string                       // Custom SQL to append to CREATE TABLE
 	Overrides         map[string]map[string]string // Platform-specific overrides
 }
@@ -497,6 +587,9 @@ func QualifyTableName(schema, table string) string {
 type Enum struct {
 	Name   string   // The generated enum type name (e.g., "enum_user_status")
 	Values []string // The allowed enum values (e.g., ["active", "inactive", "suspended"])
+	// RenameFrom is the previous type name when the enum was renamed. An
+	// existing type of that name is renamed instead of dropped and recreated.
+	RenameFrom string
 }
 
 // Domain represents a PostgreSQL domain type parsed from Go annotations.
@@ -636,6 +729,7 @@ func (r Range) QualifiedName() string {
 type Function struct {
 	StructName string // Name of the Go struct this function is associated with
 	Name       string // Function name (e.g., "set_tenant_context")
+	Schema     string // Optional schema/namespace (PostgreSQL-style)
 	Parameters string // Function parameters (e.g., "tenant_id_param TEXT")
 	Returns    string // Return type (e.g., "VOID", "TEXT")
 	Language   string // Function language (e.g., "plpgsql", "sql")
@@ -645,6 +739,13 @@ type Function struct {
 	Comment    string // Optional comment for documentation
 }
 
+// QualifiedName returns schema.name when Schema is set, or Name otherwise. It
+// is the identity used to match a declared function against an introspected
+// one, so an unqualified function stays in the connection's default schema.
+func (f Function) QualifiedName() string {
+	return QualifyTableName(f.Schema, f.Name)
+}
+
 // Sequence represents a standalone PostgreSQL sequence object parsed from Go
 // annotations.
 //
@@ -755,15 +856,20 @@ type View struct {
 // MaterializedView represents a database materialized view definition parsed
 // from Go annotations.
 //
-// MaterializedView is created by parsing //migrator:schema:matview annotations:
+// MaterializedView is created by parsing //migrator:schema:matview (or the
+// equivalent //migrator:schema:materialized_view) annotations:
 //
 //	//migrator:schema:matview name="user_stats" body="SELECT user_id, COUNT(*) FROM users GROUP BY user_id" refresh_strategy="manual"
 //	type UserStats struct{}
+//
+// Indexes declared with //migrator:schema:index on the same struct target the
+// materialized view and are recreated whenever the view is.
 type MaterializedView struct {
 	StructName      string // Name of the Go struct this materialized view is associated with
 	Name            string // Materialized view name
 	Body            string // SELECT query used as the materialized view body
 	RefreshStrategy string // manual, concurrently, or future scheduled variants
+	WithNoData      bool   // Create the view WITH NO DATA; it stays unpopulated until the first REFRESH
 	Comment         string // Optional comment for documentation
 }
 
@@ -872,6 +978,7 @@ func isIdentifierPart(character byte) bool {
 //     this as either "DEFINER" or "INVOKER".
 //   - Volatility: empty → "VOLATILE"; otherwise uppercased. pg_proc surfaces
 //     this as "IMMUTABLE", "STABLE", or "VOLATILE".
+//   - Schema: trimmed; empty means the connection's default schema.
 //
 // The DB-side read path (internal/dbschema/postgres/reader.go) returns canonical case
 // by construction, so it does not need to call this. The motivating callers
@@ -879,6 +986,7 @@ func isIdentifierPart(character byte) bool {
 // programmatic constructor — test fixtures, downstream API consumers — that
 // builds Function values without going through the parser.
 func (f *Function) Canonicalize() {
+	f.Schema = strings.TrimSpace(f.Schema)
 	f.Language = strings.ToLower(f.Language)
 	if f.Language == "" {
 		f.Language = "plpgsql"
@@ -932,6 +1040,7 @@ type RLSPolicy struct {
 	StructName          string // Name of the Go struct this policy is associated with
 	Name                string // Policy name (e.g., "user_tenant_isolation")
 	Table               string // Target table name (e.g., "users")
+	Schema              string // Optional schema/namespace of Table (PostgreSQL-style)
 	PolicyFor           string // Operations policy applies to (e.g., "ALL", "SELECT")
 	ToRoles             string // Target roles (e.g., "inventario_app", "PUBLIC")
 	UsingExpression     string // USING clause expression for row filtering
@@ -939,6 +1048,26 @@ type RLSPolicy struct {
 	Comment             string // Optional comment for documentation
 }
 
+// QualifiedTable returns schema.table when Schema is set and Table is not
+// already qualified, or Table otherwise.
+func (p RLSPolicy) QualifiedTable() string {
+	if strings.Contains(p.Table, ".") {
+		return strings.TrimSpace(p.Table)
+	}
+	return QualifyTableName(p.Schema, p.Table)
+}
+
+// QualifiedName returns the policy name prefixed with the schema of its
+// table. Policy names are only unique per table, but the diff tracks them by
+// name, so the schema keeps same-named policies in different schemas apart.
+func (p RLSPolicy) QualifiedName() string {
+	table := p.QualifiedTable()
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[:i] + "." + strings.TrimSpace(p.Name)
+	}
+	return strings.TrimSpace(p.Name)
+}
+
 // RLSEnabledTable represents a table that has Row-Level Security enabled.
 //
 // RLS must be enabled on a table before policies can be applied to it.
@@ -984,6 +1113,7 @@ type RLSEnabledTable struct {
 //   - CreateRole: Whether role can create other roles (default: false)
 //   - Inherit: Whether role inherits privileges (default: true)
 //   - Replication: Whether role can initiate replication (default: false)
+//   - MemberOf: Roles granted to this role (GRANT role TO name)
 //   - Comment: Optional comment for documentation
 //
 // Example generated SQL:
@@ -997,16 +1127,18 @@ type RLSEnabledTable struct {
 //	-- Read-only user role
 //	CREATE ROLE readonly_user WITH LOGIN;
 type Role struct {
-	StructName  string // Name of the Go struct this role is associated with
-	Name        string // Role name (e.g., "app_user")
-	Login       bool   // Whether role can login (default: false)
-	Password    string // Encrypted password (optional)
-	Superuser   bool   // Whether role is superuser (default: false)
-	CreateDB    bool   // Whether role can create databases (default: false)
-	CreateRole  bool   // Whether role can create other roles (default: false)
-	Inherit     bool   // Whether role inherits privileges (default: true)
-	Replication bool   // Whether role can initiate replication (default: false)
-	Comment     string // Optional comment for documentation
+	StructName  string   // Name of the Go struct this role is associated with
+	Name        string   // Role name (e.g., "app_user")
+	Login       bool     // Whether role can login (default: false)
+	Password    string   // Encrypted password (optional)
+	PasswordEnv string   // Environment variable holding the password at migration time (optional)
+	Superuser   bool     // Whether role is superuser (default: false)
+	CreateDB    bool     // Whether role can create databases (default: false)
+	CreateRole  bool     // Whether role can create other roles (default: false)
+	Inherit     bool     // Whether role inherits privileges (default: true)
+	Replication bool     // Whether role can initiate replication (default: false)
+	MemberOf    []string // Roles this role is a member of (optional)
+	Comment     string   // Optional comment for documentation
 }
 
 // Grant represents a PostgreSQL privilege grant parsed from Go annotations.
@@ -1055,6 +1187,27 @@ func (g *Grant) Canonicalize() {
 	g.OnSequence = strings.TrimSpace(g.OnSequence)
 }
 
+// Seed represents rows inserted into a table by the migration that creates it,
+// such as the initial contents of a lookup table.
+//
+// Seeds are defined using //migrator:schema:seed annotations, either inline or
+// from a JSON or YAML file of row objects. Inline rows are separated by
+// semicolons and values by commas; the bare value NULL inserts SQL NULL.
+//
+// Example:
+//
+//	//migrator:schema:seed table="roles" columns="id,name" rows="1,admin;2,user"
+//	//migrator:schema:seed table="countries" seed_file="countries.json"
+//	type Role struct{}
+type Seed struct {
+	StructName string     // Name of the Go struct this seed is associated with
+	Table      string     // Target table
+	Columns    []string   // Columns populated by each row
+	Rows       [][]string // Row values in Columns order
+	File       string     // Optional file the rows were loaded from
+	Always     bool       // Whether every migration re-applies the rows, not only the one creating the table
+}
+
 // SelfReferencingFK represents a self-referencing foreign key that needs to be
 // handled separately from regular foreign keys to avoid circular dependencies.
 //
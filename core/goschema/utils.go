@@ -10,6 +10,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+
+	"github.com/stokaro/ptah/core/ptaherr"
 )
 
 // Global regex cache for function dependency analysis
@@ -251,6 +253,12 @@ func normalizeTableScopedNames(r *Database) {
 			trigger.Table = table.QualifiedName()
 		}
 	}
+	for i := range r.Seeds {
+		seed := &r.Seeds[i]
+		if table := resolveTableReference(r.Tables, seed.StructName, seed.Table); table != nil {
+			seed.Table = table.QualifiedName()
+		}
+	}
 	// Views and MaterializedViews: no table-scoped normalization applied here.
 	// Unlike Triggers/Constraints/Grants/Indexes/RLS, which reference a .Table,
 	// Views/MaterializedViews declare a standalone .Name (which may include a
@@ -581,29 +589,7 @@ func processEmbeddedInlineModeRecursive(generatedFields []Field, embedded Embedd
 // processEmbeddedJSONMode handles JSON mode embedded fields by creating a single JSON/JSONB column.
 func processEmbeddedJSONMode(generatedFields []Field, embedded EmbeddedField, structName string) []Field {
 	// JSON MODE: Serialize embedded struct into a single JSON/JSONB column
-	columnName := embedded.Name
-	if columnName == "" {
-		// Auto-generate column name: "Meta" -> "meta_data"
-		columnName = strings.ToLower(embedded.EmbeddedTypeName) + "_data"
-	}
-
-	columnType := embedded.Type
-	if columnType == "" {
-		columnType = "JSONB" // Default to PostgreSQL JSONB for best performance
-	}
-
-	// Create the JSON field
-	generatedFields = append(generatedFields, Field{
-		StructName: structName,
-		FieldName:  embedded.EmbeddedTypeName,
-		Name:       columnName,
-		Type:       columnType,
-		Nullable:   embedded.Nullable,
-		Comment:    embedded.Comment,
-		Overrides:  embedded.Overrides,
-	})
-
-	return generatedFields
+	return append(generatedFields, embedded.JSONColumn(structName))
 }
 
 // processEmbeddedRelationMode handles relation mode embedded fields by creating foreign key fields.
@@ -614,31 +600,17 @@ func processEmbeddedRelationMode(generatedFields []Field, embedded EmbeddedField
 		return generatedFields
 	}
 
-	// Intelligent type inference based on reference pattern
-	refType := "INTEGER" // Default assumption: numeric primary key
-	if strings.Contains(embedded.Ref, "VARCHAR") || strings.Contains(embedded.Ref, "TEXT") ||
-		strings.Contains(strings.ToLower(embedded.Ref), "uuid") {
-		// Reference suggests string-based key (likely UUID)
-		refType = "VARCHAR(36)" // Standard UUID length
-	}
+	refType, overrides := embedded.RelationColumnType()
 
 	// Generate automatic foreign key constraint name following convention
 	foreignKeyName := generateForeignKeyName(structName, embedded.Field)
 
-	// Create platform-specific overrides for MySQL/MariaDB compatibility
-	// MySQL/MariaDB use INT for SERIAL types, so foreign keys should also use INT
-	overrides := make(map[string]map[string]string)
-	if refType == "INTEGER" {
-		overrides["mysql"] = map[string]string{"type": "INT"}
-		overrides["mariadb"] = map[string]string{"type": "INT"}
-	}
-
 	// Create the foreign key field
 	generatedFields = append(generatedFields, Field{
 		StructName:     structName,
 		FieldName:      embedded.EmbeddedTypeName,
 		Name:           embedded.Field,    // e.g., "user_id"
-		Type:           refType,           // INTEGER or VARCHAR(36)
+		Type:           refType,           // type of the referenced column, e.g. BIGINT or UUID
 		Nullable:       embedded.Nullable, // Can the relationship be optional?
 		Foreign:        embedded.Ref,      // e.g., "users(id)"
 		ForeignKeyName: foreignKeyName,    // e.g., "fk_posts_user_id"
@@ -651,6 +623,137 @@ func processEmbeddedRelationMode(generatedFields []Field, embedded EmbeddedField
 	return generatedFields
 }
 
+// resolveEmbeddedRelationTypes gives each relation-mode embedded field without
+// an explicit type="..." the declared type of the column its ref points at, so
+// the generated foreign key column matches a BIGINT or UUID primary key. A ref
+// naming only a table follows that table's primary key. SERIAL types map to
+// their integer base type. Refs to tables or columns outside the parsed
+// schema keep the legacy guess in EmbeddedField.RelationColumnType. A ref to
+// more than one column is an error, since relation mode generates a single
+// column.
+func resolveEmbeddedRelationTypes(r *Database) error {
+	fields := processEmbeddedFields(r.EmbeddedFields, r.Fields)
+	for i := range r.EmbeddedFields {
+		embedded := &r.EmbeddedFields[i]
+		if embedded.Mode != "relation" || embedded.Field == "" || embedded.Ref == "" || embedded.Type != "" {
+			continue
+		}
+		refTable, refColumns := splitEmbeddedRelationRef(embedded.Ref)
+		if len(refColumns) > 1 {
+			return embeddedRelationRefError(*embedded, fmt.Sprintf("names %d columns", len(refColumns)))
+		}
+		table := findEmbeddedRelationTable(r.Tables, *embedded, refTable)
+		if table == nil {
+			continue
+		}
+		if len(refColumns) == 0 {
+			refColumns = tablePrimaryKeyColumns(*table, fields)
+			if len(refColumns) > 1 {
+				return embeddedRelationRefError(*embedded, fmt.Sprintf("points at the composite primary key of %s", table.Name))
+			}
+		}
+		if len(refColumns) == 0 {
+			continue
+		}
+		for _, field := range fields {
+			if field.StructName != table.StructName || field.Name != refColumns[0] {
+				continue
+			}
+			embedded.Type = relationBaseType(field.Type)
+			if embedded.Overrides == nil {
+				embedded.Overrides = make(map[string]map[string]string)
+			}
+			for platformName, values := range field.Overrides {
+				if values["type"] == "" || embedded.Overrides[platformName]["type"] != "" {
+					continue
+				}
+				platformOverrides := maps.Clone(embedded.Overrides[platformName])
+				if platformOverrides == nil {
+					platformOverrides = make(map[string]string, 1)
+				}
+				platformOverrides["type"] = relationBaseType(values["type"])
+				embedded.Overrides[platformName] = platformOverrides
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// splitEmbeddedRelationRef splits a relation ref such as "users(id)" into the
+// table name and its column list; a bare "users" has no columns.
+func splitEmbeddedRelationRef(ref string) (table string, columns []string) {
+	table, rest, found := strings.Cut(ref, "(")
+	table = strings.TrimSpace(table)
+	if !found {
+		return table, nil
+	}
+	rest, _, _ = strings.Cut(rest, ")")
+	for column := range strings.SplitSeq(rest, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return table, columns
+}
+
+func findEmbeddedRelationTable(tables []Table, embedded EmbeddedField, refTable string) *Table {
+	current := Table{}
+	if table := findTableByStructName(tables, embedded.StructName); table != nil {
+		current = *table
+	}
+	qualified := resolveReferenceTableName(tables, current, refTable)
+	for i := range tables {
+		if tables[i].QualifiedName() == qualified {
+			return &tables[i]
+		}
+	}
+	return nil
+}
+
+// tablePrimaryKeyColumns returns the table-level primary key, or the fields
+// of the table's struct marked primary.
+func tablePrimaryKeyColumns(table Table, fields []Field) []string {
+	if len(table.PrimaryKey) > 0 {
+		return table.PrimaryKey
+	}
+	var columns []string
+	for _, field := range fields {
+		if field.StructName == table.StructName && field.Primary {
+			columns = append(columns, field.Name)
+		}
+	}
+	return columns
+}
+
+// relationBaseType returns the type a column referencing a column of
+// fieldType must have: the integer base type for SERIAL types, and fieldType
+// itself otherwise.
+func relationBaseType(fieldType string) string {
+	switch strings.ToUpper(strings.TrimSpace(fieldType)) {
+	case "SMALLSERIAL", "SERIAL2":
+		return "SMALLINT"
+	case "SERIAL", "SERIAL4":
+		return "INTEGER"
+	case "BIGSERIAL", "SERIAL8":
+		return "BIGINT"
+	default:
+		return fieldType
+	}
+}
+
+func embeddedRelationRefError(embedded EmbeddedField, problem string) error {
+	return &ptaherr.ParseError{
+		Directive: "migrator:embedded",
+		Attribute: "ref",
+		Err:       ptaherr.ErrInvalidAttributeValue,
+		Message: fmt.Sprintf(
+			"invalid ref %q on //migrator:embedded at %s: it %s, but relation mode generates the single column %s; declare a composite foreign key with //migrator:schema:constraint instead",
+			embedded.Ref, embedded.StructName, problem, embedded.Field,
+		),
+	}
+}
+
 // buildFunctionDependencies analyzes function body content to identify function-to-function dependencies.
 //
 // This method examines function bodies to identify calls to other functions and builds
@@ -850,6 +953,7 @@ func isFunctionInSorted(function Function, sorted []Function) bool {
 //   - Grants: Deduplicated by role + privileges + grant option + (table or schema) target
 //   - Roles: Deduplicated by role name
 //   - Schemas: Deduplicated by schema name
+//   - Seeds: Deduplicated by table + columns + rows
 //
 // All 16 Database slice collections are now covered (previously only a subset
 // of appended collections were deduplicated, and the five dropped by ParseFS
@@ -982,6 +1086,7 @@ func deduplicateSchemaObjects(r *Database) {
 	r.Constraints = deduplicateConstraints(r.Constraints)
 	r.Grants = deduplicateGrants(r.Grants)
 	r.Roles = deduplicateRoles(r.Roles)
+	r.Seeds = deduplicateSeeds(r.Seeds)
 }
 
 func deduplicateSchemas(schemas []Schema) []Schema {
@@ -1082,6 +1187,25 @@ func deduplicateGrants(grants []Grant) []Grant {
 	return deduplicated
 }
 
+// deduplicateSeeds drops seeds that insert the same rows into the same table,
+// which happens when one file is parsed more than once.
+func deduplicateSeeds(seeds []Seed) []Seed {
+	seen := make(map[string]bool)
+	deduplicated := make([]Seed, 0, len(seeds))
+	for _, seed := range seeds {
+		var key strings.Builder
+		key.WriteString(seed.Table + "|" + strings.Join(seed.Columns, ","))
+		for _, row := range seed.Rows {
+			key.WriteString("|" + strings.Join(row, ","))
+		}
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			deduplicated = append(deduplicated, seed)
+		}
+	}
+	return deduplicated
+}
+
 func validateDuplicateSchemaObjectDefinitions(r *Database) error {
 	if err := validateDuplicateSchemas(r.Schemas); err != nil {
 		return err
@@ -1193,11 +1317,13 @@ func validateDuplicateRoles(roles []Role) error {
 		signature := strings.Join([]string{
 			strconv.FormatBool(role.Login),
 			role.Password,
+			role.PasswordEnv,
 			strconv.FormatBool(role.Superuser),
 			strconv.FormatBool(role.CreateDB),
 			strconv.FormatBool(role.CreateRole),
 			strconv.FormatBool(role.Inherit),
 			strconv.FormatBool(role.Replication),
+			strings.Join(slices.Sorted(slices.Values(role.MemberOf)), ","),
 		}, "\x00")
 		if previous, ok := seen[role.Name]; ok && previous != signature {
 			return fmt.Errorf("conflicting role %q definitions", role.Name)
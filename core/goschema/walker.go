@@ -1,10 +1,11 @@
 package goschema
 
 import (
-	"bufio"
 	"errors"
 	"io/fs"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 )
 
@@ -24,6 +25,9 @@ import (
 //   - Dependency analysis based on foreign key relationships
 //   - Topological sorting to determine proper table creation order
 //
+// Build constraints are ignored, so every build-tagged variant of a file is
+// parsed; use ParseDirWithOptions to select build tags or exclude files.
+//
 // Parameters:
 //   - rootDir: The root directory to start parsing from (e.g., "./entities", "./models")
 //
@@ -76,33 +80,14 @@ func ParseDir(rootDir string) (*Database, error) {
 //		return fmt.Errorf("failed to render schema: %w", err)
 //	}
 func ParseFS(fsys fs.FS, rootDir string) (*Database, error) {
-	result := &Database{
-		Schemas:                    []Schema{},
-		Tables:                     []Table{},
-		Fields:                     []Field{},
-		Indexes:                    []Index{},
-		Constraints:                []Constraint{},
-		Enums:                      []Enum{},
-		EmbeddedFields:             []EmbeddedField{},
-		Extensions:                 []Extension{},
-		Functions:                  []Function{},
-		Sequences:                  []Sequence{},
-		Domains:                    []Domain{},
-		CompositeTypes:             []CompositeType{},
-		Ranges:                     []Range{},
-		Views:                      []View{},
-		MaterializedViews:          []MaterializedView{},
-		Triggers:                   []Trigger{},
-		RLSPolicies:                []RLSPolicy{},
-		RLSEnabledTables:           []RLSEnabledTable{},
-		Roles:                      []Role{},
-		Grants:                     []Grant{},
-		Dependencies:               make(map[string][]string),
-		FunctionDependencies:       make(map[string][]string),
-		SelfReferencingForeignKeys: make(map[string][]SelfReferencingFK),
-	}
+	return parseFS(fsys, rootDir, nil)
+}
 
-	var parseErrors []error
+// parseFS parses the Go sources under rootDir that include accepts. include
+// sees every directory and every schema source file; rejecting a directory
+// skips it. A nil include accepts everything.
+func parseFS(fsys fs.FS, rootDir string, include func(path string, d fs.DirEntry) (bool, error)) (*Database, error) {
+	set := newSourceSet()
 
 	// Walk through all directories recursively
 	err := fs.WalkDir(fsys, rootDir, func(path string, d fs.DirEntry, err error) error {
@@ -110,67 +95,172 @@ func ParseFS(fsys fs.FS, rootDir string) (*Database, error) {
 			return err
 		}
 
-		// Skip non-Go files
-		if !strings.HasSuffix(path, ".go") {
+		if !d.IsDir() && !isSchemaSourcePath(path) {
 			return nil
 		}
-
-		// Skip test files
-		if strings.HasSuffix(path, "_test.go") {
-			return nil
+		if include != nil {
+			ok, err := include(path, d)
+			if err != nil {
+				return err
+			}
+			if !ok && d.IsDir() {
+				return fs.SkipDir
+			}
+			if !ok {
+				return nil
+			}
 		}
-
-		// Skip vendor directories (handle both Unix and Windows path separators)
-		if strings.Contains(path, "vendor/") || strings.Contains(path, "vendor\\") {
+		if d.IsDir() {
 			return nil
 		}
 
-		database, err := parseDatabaseFile(fsys, path)
+		source, err := fs.ReadFile(fsys, path)
 		if err != nil {
-			parseErrors = append(parseErrors, err)
+			set.errs = append(set.errs, err)
 			return nil
 		}
-
-		// Add to result
-		result.Schemas = append(result.Schemas, database.Schemas...)
-		result.EmbeddedFields = append(result.EmbeddedFields, database.EmbeddedFields...)
-		result.Fields = append(result.Fields, database.Fields...)
-		result.Indexes = append(result.Indexes, database.Indexes...)
-		result.Tables = append(result.Tables, database.Tables...)
-		result.Enums = append(result.Enums, database.Enums...)
-		result.Extensions = append(result.Extensions, database.Extensions...)
-		result.Functions = append(result.Functions, database.Functions...)
-		result.Sequences = append(result.Sequences, database.Sequences...)
-		result.Domains = append(result.Domains, database.Domains...)
-		result.CompositeTypes = append(result.CompositeTypes, database.CompositeTypes...)
-		result.Ranges = append(result.Ranges, database.Ranges...)
-		result.RLSPolicies = append(result.RLSPolicies, database.RLSPolicies...)
-		result.RLSEnabledTables = append(result.RLSEnabledTables, database.RLSEnabledTables...)
-		result.Roles = append(result.Roles, database.Roles...)
-		result.Constraints = append(result.Constraints, database.Constraints...)
-		result.Views = append(result.Views, database.Views...)
-		result.MaterializedViews = append(result.MaterializedViews, database.MaterializedViews...)
-		result.Triggers = append(result.Triggers, database.Triggers...)
-		result.Grants = append(result.Grants, database.Grants...)
-
+		set.add(path, source, fsRelativeFileReader(fsys, path))
 		return nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
-	if err := errors.Join(parseErrors...); err != nil {
+	return set.finalize()
+}
+
+// ParseSources parses a set of in-memory Go sources, keyed by file name, as if
+// they were the files of one directory passed to ParseDir. Embedded fields,
+// foreign keys, and duplicate definitions are resolved across the whole set,
+// so a struct may embed a type declared in another source.
+//
+// Sources are parsed in file-name order. Every source is parsed even when an
+// earlier one fails; the errors are joined.
+//
+// Example:
+//
+//	result, err := ParseSources(map[string][]byte{
+//		"base.go": []byte(baseSource),
+//		"user.go": []byte(userSource),
+//	})
+func ParseSources(sources map[string][]byte) (*Database, error) {
+	set := newSourceSet()
+	for _, filename := range slices.Sorted(maps.Keys(sources)) {
+		set.add(filename, sources[filename], osRelativeFileReader(filename))
+	}
+	return set.finalize()
+}
+
+// sourceSet merges the files of one ParseFS or ParseSources call. Every file
+// goes through parseSource, the parser behind ParseSource, so the entry points
+// cannot diverge. The type aliases declared across the files are kept here
+// rather than on the Database, since they only matter until embedded fields
+// are resolved.
+type sourceSet struct {
+	result      *Database
+	typeAliases map[string]string
+	errs        []error
+}
+
+func newSourceSet() *sourceSet {
+	return &sourceSet{result: newParseResult(), typeAliases: make(map[string]string)}
+}
+
+// add parses one file into the set. A failing file is recorded and the
+// remaining files are still parsed.
+func (s *sourceSet) add(filename string, source any, readFile func(string) ([]byte, error)) {
+	database, aliases, err := parseSource(filename, source, readFile)
+	if err != nil {
+		s.errs = append(s.errs, err)
+		return
+	}
+	appendParsedDatabase(s.result, database)
+	maps.Copy(s.typeAliases, aliases)
+}
+
+// finalize returns the merged schema, or the joined errors of every file that
+// failed to parse.
+func (s *sourceSet) finalize() (*Database, error) {
+	if err := errors.Join(s.errs...); err != nil {
 		return nil, err
 	}
+	return finalizeParseResult(s.result, s.typeAliases)
+}
 
+func newParseResult() *Database {
+	return &Database{
+		Schemas:                    []Schema{},
+		Tables:                     []Table{},
+		Fields:                     []Field{},
+		Indexes:                    []Index{},
+		Constraints:                []Constraint{},
+		Enums:                      []Enum{},
+		EmbeddedFields:             []EmbeddedField{},
+		Extensions:                 []Extension{},
+		Functions:                  []Function{},
+		Sequences:                  []Sequence{},
+		Domains:                    []Domain{},
+		CompositeTypes:             []CompositeType{},
+		Ranges:                     []Range{},
+		Views:                      []View{},
+		MaterializedViews:          []MaterializedView{},
+		Triggers:                   []Trigger{},
+		RLSPolicies:                []RLSPolicy{},
+		RLSEnabledTables:           []RLSEnabledTable{},
+		Roles:                      []Role{},
+		Grants:                     []Grant{},
+		Seeds:                      []Seed{},
+		Dependencies:               make(map[string][]string),
+		FunctionDependencies:       make(map[string][]string),
+		SelfReferencingForeignKeys: make(map[string][]SelfReferencingFK),
+	}
+}
+
+// appendParsedDatabase adds the entities parsed from one file to result.
+func appendParsedDatabase(result *Database, database Database) {
+	result.Schemas = append(result.Schemas, database.Schemas...)
+	result.EmbeddedFields = append(result.EmbeddedFields, database.EmbeddedFields...)
+	result.Fields = append(result.Fields, database.Fields...)
+	result.Indexes = append(result.Indexes, database.Indexes...)
+	result.Tables = append(result.Tables, database.Tables...)
+	result.Enums = append(result.Enums, database.Enums...)
+	result.Extensions = append(result.Extensions, database.Extensions...)
+	result.Functions = append(result.Functions, database.Functions...)
+	result.Sequences = append(result.Sequences, database.Sequences...)
+	result.Domains = append(result.Domains, database.Domains...)
+	result.CompositeTypes = append(result.CompositeTypes, database.CompositeTypes...)
+	result.Ranges = append(result.Ranges, database.Ranges...)
+	result.RLSPolicies = append(result.RLSPolicies, database.RLSPolicies...)
+	result.RLSEnabledTables = append(result.RLSEnabledTables, database.RLSEnabledTables...)
+	result.Roles = append(result.Roles, database.Roles...)
+	result.Constraints = append(result.Constraints, database.Constraints...)
+	result.Views = append(result.Views, database.Views...)
+	result.MaterializedViews = append(result.MaterializedViews, database.MaterializedViews...)
+	result.Triggers = append(result.Triggers, database.Triggers...)
+	result.Grants = append(result.Grants, database.Grants...)
+	result.Seeds = append(result.Seeds, database.Seeds...)
+}
+
+// finalizeParseResult resolves the merged per-file results into one schema:
+// duplicates, embedded fields, and dependency ordering. typeAliases holds the
+// aliases declared across all files.
+func finalizeParseResult(result *Database, typeAliases map[string]string) (*Database, error) {
 	if err := validateDuplicateSchemaObjectDefinitions(result); err != nil {
 		return nil, err
 	}
 
+	// Embedded aliases may name a type declared in another file
+	resolveEmbeddedTypeAliases(result.EmbeddedFields, typeAliases)
+
 	// deduplicate entities (same table/field defined in multiple files)
 	Deduplicate(result)
 	normalizeTableScopedNames(result)
 
+	// Relation columns take the type of the column they reference
+	if err := resolveEmbeddedRelationTypes(result); err != nil {
+		return nil, err
+	}
+
 	// Process embedded fields BEFORE building dependency graph
 	// This ensures that foreign keys from embedded fields are included in dependency analysis
 	result.Fields = processEmbeddedFields(result.EmbeddedFields, result.Fields)
@@ -187,12 +277,13 @@ func ParseFS(fsys fs.FS, rootDir string) (*Database, error) {
 	return result, nil
 }
 
-func parseDatabaseFile(fsys fs.FS, path string) (Database, error) {
-	file, err := fsys.Open(path)
-	if err != nil {
-		return Database{}, err
+// isSchemaSourcePath reports whether ParseFS reads the file at path: Go
+// sources outside vendor directories, without tests.
+func isSchemaSourcePath(path string) bool {
+	// Skip non-Go files and test files
+	if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+		return false
 	}
-	defer file.Close()
-
-	return ParseSource(path, bufio.NewReader(file))
+	// Skip vendor directories (handle both Unix and Windows path separators)
+	return !strings.Contains(path, "vendor/") && !strings.Contains(path, "vendor\\")
 }
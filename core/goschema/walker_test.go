@@ -1665,3 +1665,164 @@ type Model%d struct {
 		}
 	}
 }
+
+const parseSourcesBaseSource = `package models
+
+type Timestamps struct {
+	//migrator:schema:field name="created_at" type="TIMESTAMP" not_null="true"
+	CreatedAt string
+}
+
+type Audit = Timestamps
+
+type Owned[T any] struct {
+	//migrator:schema:field name="owner_id" type="INTEGER" foreign="owners(id)"
+	OwnerID T
+}
+`
+
+const parseSourcesUserSource = `package models
+
+//migrator:schema:table name="owners"
+type Owner struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int
+}
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int
+
+	//migrator:embedded mode="inline"
+	Audit
+
+	//migrator:embedded mode="inline"
+	*Owned[int]
+}
+
+//migrator:schema:table name="boxes"
+type Box[T any] struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID T
+}
+`
+
+func fieldNamesOf(db *goschema.Database, structName string) []string {
+	var names []string
+	for _, field := range db.Fields {
+		if field.StructName == structName {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+func tableNamesOf(db *goschema.Database) []string {
+	names := make([]string, 0, len(db.Tables))
+	for _, table := range db.Tables {
+		names = append(names, table.Name)
+	}
+	return names
+}
+
+func TestParseSources_ResolvesEmbeddedAliasesAndGenericsAcrossSources(t *testing.T) {
+	c := qt.New(t)
+
+	result, err := goschema.ParseSources(map[string][]byte{
+		"base.go": []byte(parseSourcesBaseSource),
+		"user.go": []byte(parseSourcesUserSource),
+	})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(fieldNamesOf(result, "User"), qt.DeepEquals, []string{"id", "created_at", "owner_id"})
+	c.Assert(fieldNamesOf(result, "Box"), qt.DeepEquals, []string{"id"})
+	c.Assert(tableNamesOf(result), qt.DeepEquals, []string{"boxes", "owners", "users"})
+	c.Assert(result.Dependencies["users"], qt.DeepEquals, []string{"owners"})
+}
+
+func TestParseSources_MatchesParseFS(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"base.go": {Data: []byte(parseSourcesBaseSource)},
+		"user.go": {Data: []byte(parseSourcesUserSource)},
+	}
+
+	fromFS, err := goschema.ParseFS(fsys, ".")
+	c.Assert(err, qt.IsNil)
+	fromSources, err := goschema.ParseSources(map[string][]byte{
+		"base.go": []byte(parseSourcesBaseSource),
+		"user.go": []byte(parseSourcesUserSource),
+	})
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(fromSources.Tables, qt.DeepEquals, fromFS.Tables)
+	c.Assert(fromSources.Fields, qt.DeepEquals, fromFS.Fields)
+	c.Assert(fromSources.Dependencies, qt.DeepEquals, fromFS.Dependencies)
+}
+
+func TestParseSource_ResolvesAliasesAndGenericsInOneFile(t *testing.T) {
+	c := qt.New(t)
+
+	result, err := goschema.ParseSource("models.go", parseSourcesBaseSource+`
+//migrator:schema:table name="events"
+type Event struct {
+	//migrator:embedded mode="inline"
+	Audit
+
+	//migrator:embedded mode="json" name="owner" type="JSONB"
+	Owned[string]
+}
+`)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(result.EmbeddedFields, qt.HasLen, 2)
+	c.Assert(result.EmbeddedFields[0].EmbeddedTypeName, qt.Equals, "Timestamps")
+	c.Assert(result.EmbeddedFields[1].EmbeddedTypeName, qt.Equals, "Owned")
+}
+
+func TestParseSources_JoinsErrorsFromEverySource(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := goschema.ParseSources(map[string][]byte{
+		"a.go": []byte("package models\n\ntype A struct {"),
+		"b.go": []byte("package models\n\ntype B struct {"),
+	})
+
+	c.Assert(err, qt.ErrorMatches, `(?s)parse Go source "a\.go".*parse Go source "b\.go".*`)
+}
+
+func TestParseSources_MatchesParseDirForFixtureEntities(t *testing.T) {
+	for _, fixture := range []string{"005-field-type-change", "013-embedded-fields"} {
+		t.Run(fixture, func(t *testing.T) {
+			c := qt.New(t)
+			dir := filepath.Join("..", "..", "integration", "fixtures", "entities", fixture)
+
+			fromDir, err := goschema.ParseDir(dir)
+			c.Assert(err, qt.IsNil)
+			fromSources, err := goschema.ParseSources(readGoSources(c, dir))
+			c.Assert(err, qt.IsNil)
+
+			c.Assert(fromSources.Tables, qt.DeepEquals, fromDir.Tables)
+			c.Assert(fromSources.Fields, qt.DeepEquals, fromDir.Fields)
+			c.Assert(fromSources.EmbeddedFields, qt.DeepEquals, fromDir.EmbeddedFields)
+			c.Assert(fromSources.Indexes, qt.DeepEquals, fromDir.Indexes)
+			c.Assert(fromSources.Enums, qt.DeepEquals, fromDir.Enums)
+			c.Assert(fromSources.Constraints, qt.DeepEquals, fromDir.Constraints)
+			c.Assert(fromSources.Dependencies, qt.DeepEquals, fromDir.Dependencies)
+		})
+	}
+}
+
+// readGoSources loads the Go files of dir into the map ParseSources takes.
+func readGoSources(c *qt.C, dir string) map[string][]byte {
+	entries, err := os.ReadDir(dir)
+	c.Assert(err, qt.IsNil)
+	sources := make(map[string][]byte, len(entries))
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		c.Assert(err, qt.IsNil)
+		sources[entry.Name()] = data
+	}
+	return sources
+}
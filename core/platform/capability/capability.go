@@ -148,6 +148,42 @@ const (
 	// package, but the flag lets callers avoid assuming PostgreSQL lock
 	// functions exist on every PostgreSQL-wire engine.
 	AdvisoryLocks Capability = "advisory_locks"
+
+	// NotValidConstraints marks support for adding a CHECK or FOREIGN KEY
+	// constraint with NOT VALID and validating it later with
+	// ALTER TABLE ... VALIDATE CONSTRAINT. Validation takes a weaker lock than
+	// the original ADD CONSTRAINT scan, and PostgreSQL 12+ lets SET NOT NULL
+	// skip its full-table scan when a validated IS NOT NULL check exists.
+	NotValidConstraints Capability = "not_valid_constraints"
+
+	// Functions marks support for the PostgreSQL-style stored functions Ptah
+	// manages from //migrator:schema:function annotations (CREATE OR REPLACE
+	// FUNCTION ... LANGUAGE plpgsql/sql). Other dialects have their own
+	// routine syntax that Ptah does not model, so planners skip these objects.
+	Functions Capability = "functions"
+
+	// Extensions marks support for PostgreSQL extensions
+	// (CREATE EXTENSION / DROP EXTENSION).
+	Extensions Capability = "extensions"
+
+	// CommentOn marks support for the standalone COMMENT ON TABLE/COLUMN
+	// statements PostgreSQL uses for table and column comments. MySQL
+	// carries comments inline and Spanner's PostgreSQL interface rejects
+	// the statement, so planners without it leave comments out.
+	CommentOn Capability = "comment_on"
+
+	// UniqueNullsNotDistinct marks support for UNIQUE NULLS NOT DISTINCT on
+	// constraints and unique indexes, which makes NULLs compare equal for
+	// uniqueness. PostgreSQL added it in 15; MySQL, MariaDB, and CockroachDB
+	// have no equivalent, so planners reject it instead of emitting SQL the
+	// target cannot parse.
+	UniqueNullsNotDistinct Capability = "unique_nulls_not_distinct"
+
+	// BuiltinRandomUUID marks a built-in gen_random_uuid() function.
+	// PostgreSQL added it to core in 13; older servers get it from the
+	// pgcrypto extension, so planners for targets without it require the
+	// schema to declare pgcrypto before a default may call it.
+	BuiltinRandomUUID Capability = "builtin_random_uuid"
 )
 
 // spec documents a registry entry and its implication edges.
@@ -212,6 +248,24 @@ var registry = map[Capability]spec{
 	AdvisoryLocks: {
 		doc: "PostgreSQL advisory lock functions",
 	},
+	NotValidConstraints: {
+		doc: "ADD CONSTRAINT ... NOT VALID followed by VALIDATE CONSTRAINT (PostgreSQL, CockroachDB, YugabyteDB)",
+	},
+	Functions: {
+		doc: "PostgreSQL-style stored functions (CREATE OR REPLACE FUNCTION)",
+	},
+	Extensions: {
+		doc: "PostgreSQL extensions (CREATE EXTENSION)",
+	},
+	CommentOn: {
+		doc: "COMMENT ON TABLE/COLUMN statements (PostgreSQL, CockroachDB, YugabyteDB)",
+	},
+	UniqueNullsNotDistinct: {
+		doc: "UNIQUE NULLS NOT DISTINCT on constraints and unique indexes (PostgreSQL 15+)",
+	},
+	BuiltinRandomUUID: {
+		doc: "gen_random_uuid() without the pgcrypto extension (PostgreSQL 13+, CockroachDB, YugabyteDB)",
+	},
 }
 
 // mutexGroups lists capability groups in which AT MOST ONE member may be
@@ -333,6 +387,12 @@ func MySQL80() Capabilities {
 		Sequences:                      false,
 		XMLType:                        false,
 		AdvisoryLocks:                  false,
+		NotValidConstraints:            false,
+		Functions:                      false,
+		Extensions:                     false,
+		CommentOn:                      false,
+		UniqueNullsNotDistinct:         false,
+		BuiltinRandomUUID:              false,
 	}
 }
 
@@ -373,13 +433,19 @@ func MariaDB1011() Capabilities {
 		Sequences:                      true,
 		XMLType:                        false,
 		AdvisoryLocks:                  false,
+		NotValidConstraints:            false,
+		Functions:                      false,
+		Extensions:                     false,
+		CommentOn:                      false,
+		UniqueNullsNotDistinct:         false,
+		BuiltinRandomUUID:              false,
 	}
 }
 
 // MariaDBLegacy is the conservative preset for MariaDB before 10.2 (EOL
 // lines): no generic DROP CONSTRAINT, no enforced CHECK constraints, and no
 // IF EXISTS guards are assumed (a floor, deliberately below what late 10.1
-// releases could do). ForServerVersion maps pre-10.2 version strings here so
+// releases could do), and no sequences (CREATE SEQUENCE arrived in 10.3). ForServerVersion maps pre-10.2 version strings here so
 // a modern preset is never over-promised to an old server.
 func MariaDBLegacy() Capabilities {
 	return MariaDB1011().
@@ -387,10 +453,11 @@ func MariaDBLegacy() Capabilities {
 		With(DropConstraintIfExists, false).
 		With(DropIndexIfExists, false).
 		With(CheckConstraintsEnforced, false).
-		With(CreateOrReplaceTrigger, false)
+		With(CreateOrReplaceTrigger, false).
+		With(Sequences, false)
 }
 
-// Postgres16 is the preset for PostgreSQL 14–16.
+// Postgres16 is the preset for PostgreSQL 15–16.
 func Postgres16() Capabilities {
 	return Capabilities{
 		DropConstraintGeneric:          true,
@@ -409,6 +476,12 @@ func Postgres16() Capabilities {
 		Sequences:                      true,
 		XMLType:                        true,
 		AdvisoryLocks:                  true,
+		NotValidConstraints:            true,
+		Functions:                      true,
+		Extensions:                     true,
+		CommentOn:                      true,
+		UniqueNullsNotDistinct:         true,
+		BuiltinRandomUUID:              true,
 	}
 }
 
@@ -417,10 +490,23 @@ func Postgres17() Capabilities {
 	return Postgres16().With(AlterGeneratedColumnExpression, true)
 }
 
-// Postgres13 is the preset for PostgreSQL 12–13: identical to Postgres16
+// Postgres14 is the preset for PostgreSQL 14: identical to Postgres16 except
+// UNIQUE NULLS NOT DISTINCT, which arrived in PostgreSQL 15.
+func Postgres14() Capabilities {
+	return Postgres16().With(UniqueNullsNotDistinct, false)
+}
+
+// Postgres13 is the preset for PostgreSQL 13: identical to Postgres14
 // except CREATE OR REPLACE TRIGGER, which arrived in PostgreSQL 14.
 func Postgres13() Capabilities {
-	return Postgres16().With(CreateOrReplaceTrigger, false)
+	return Postgres14().With(CreateOrReplaceTrigger, false)
+}
+
+// Postgres12 is the preset for PostgreSQL 12 and older: identical to
+// Postgres13 except the built-in gen_random_uuid(), which needs the pgcrypto
+// extension before PostgreSQL 13.
+func Postgres12() Capabilities {
+	return Postgres13().With(BuiltinRandomUUID, false)
 }
 
 // ClickHouse24 is the preset for the ClickHouse 24.x line. It is deliberately
@@ -445,6 +531,12 @@ func ClickHouse24() Capabilities {
 		Sequences:                      false,
 		XMLType:                        false,
 		AdvisoryLocks:                  false,
+		NotValidConstraints:            false,
+		Functions:                      false,
+		Extensions:                     false,
+		CommentOn:                      false,
+		UniqueNullsNotDistinct:         false,
+		BuiltinRandomUUID:              false,
 	}
 }
 
@@ -470,6 +562,12 @@ func SQLite3() Capabilities {
 		Sequences:                      false,
 		XMLType:                        false,
 		AdvisoryLocks:                  false,
+		NotValidConstraints:            false,
+		Functions:                      false,
+		Extensions:                     false,
+		CommentOn:                      false,
+		UniqueNullsNotDistinct:         false,
+		BuiltinRandomUUID:              false,
 	}
 }
 
@@ -496,14 +594,20 @@ func SQLServer2022() Capabilities {
 		Sequences:                      false,
 		XMLType:                        true,
 		AdvisoryLocks:                  false,
+		NotValidConstraints:            false,
+		Functions:                      false,
+		Extensions:                     false,
+		CommentOn:                      false,
+		UniqueNullsNotDistinct:         false,
+		BuiltinRandomUUID:              false,
 	}
 }
 
 // CockroachDB23 is the preset for CockroachDB's PostgreSQL-compatible surface.
 // CockroachDB runs schema changes online by design, so PostgreSQL's
 // CONCURRENTLY keyword is not a meaningful or portable emission target. It
-// also lacks PostgreSQL's SERIAL/sequence surface, XML type, and advisory-lock
-// functions in Ptah's portable subset.
+// also lacks PostgreSQL's SERIAL/sequence surface, XML type, advisory-lock
+// functions, and UNIQUE NULLS NOT DISTINCT in Ptah's portable subset.
 func CockroachDB23() Capabilities {
 	return Postgres16().
 		With(CreateIndexConcurrently, false).
@@ -511,7 +615,8 @@ func CockroachDB23() Capabilities {
 		With(AdvisoryLocks, false).
 		With(RowLevelSecurity, false).
 		With(RoleManagement, false).
-		With(Sequences, false)
+		With(Sequences, false).
+		With(UniqueNullsNotDistinct, false)
 }
 
 // YugabyteDB25 is the preset for YugabyteDB YSQL. It stays close to
@@ -528,7 +633,8 @@ func YugabyteDB25() Capabilities {
 // SpannerPostgres is the conservative preset for Cloud Spanner's PostgreSQL
 // interface. Spanner's SQL surface is sufficiently different that Ptah only
 // routes the simplest PostgreSQL-family statements through this preset; enums,
-// sequences, RLS, advisory locks, XML, and foreign keys are disabled.
+// sequences, RLS, functions, extensions, advisory locks, XML, foreign keys, and
+// NOT VALID constraints are disabled.
 func SpannerPostgres() Capabilities {
 	return Postgres16().
 		With(DropConstraintGeneric, false).
@@ -541,9 +647,14 @@ func SpannerPostgres() Capabilities {
 		With(RowLevelSecurity, false).
 		With(RoleManagement, false).
 		With(ForeignKeys, false).
+		With(Functions, false).
+		With(Extensions, false).
 		With(Sequences, false).
 		With(XMLType, false).
-		With(AdvisoryLocks, false)
+		With(AdvisoryLocks, false).
+		With(NotValidConstraints, false).
+		With(CommentOn, false).
+		With(UniqueNullsNotDistinct, false)
 }
 
 // ForDialect returns the default preset for a dialect name (normalized via
@@ -630,10 +741,16 @@ func ForServerVersionResult(dialect, version string) (Capabilities, bool) {
 		if v.major >= 17 {
 			return Postgres17(), true
 		}
-		if v.major >= 14 {
+		if v.major >= 15 {
 			return Postgres16(), true
 		}
-		return Postgres13(), true
+		if v.major == 14 {
+			return Postgres14(), true
+		}
+		if v.major == 13 {
+			return Postgres13(), true
+		}
+		return Postgres12(), true
 	default:
 		return ForDialect(dialect), false
 	}
@@ -643,7 +760,8 @@ func ForServerVersionResult(dialect, version string) (Capabilities, bool) {
 // MariaDB servers speaking the MySQL protocol prepend a fake "5.5.5-"
 // replication-compatibility prefix ("5.5.5-10.11.6-MariaDB"); that prefix is
 // stripped before parsing so the REAL version decides. 10.2+ gets the modern
-// preset (generic DROP CONSTRAINT, enforced CHECKs, IF EXISTS guards);
+// preset (generic DROP CONSTRAINT, enforced CHECKs, IF EXISTS guards), minus
+// sequences on 10.2 since CREATE SEQUENCE arrived in 10.3;
 // anything older — or an unparseable string — degrades to MariaDBLegacy /
 // the modern preset respectively.
 func mariaDBForVersion(version string) Capabilities {
@@ -652,6 +770,9 @@ func mariaDBForVersion(version string) Capabilities {
 	if !ok {
 		return MariaDB1011()
 	}
+	if v.major == 10 && v.minor == 2 {
+		return MariaDB1011().With(Sequences, false)
+	}
 	if v.major > 10 || (v.major == 10 && v.minor >= 2) {
 		return MariaDB1011()
 	}
@@ -95,7 +95,9 @@ func TestPresets_AllValid_AndCoverEveryRegisteredCapability(t *testing.T) {
 		"MariaDBLegacy": capability.MariaDBLegacy(),
 		"Postgres17":    capability.Postgres17(),
 		"Postgres16":    capability.Postgres16(),
+		"Postgres14":    capability.Postgres14(),
 		"Postgres13":    capability.Postgres13(),
+		"Postgres12":    capability.Postgres12(),
 		"ClickHouse24":  capability.ClickHouse24(),
 		"SQLite3":       capability.SQLite3(),
 		"CockroachDB23": capability.CockroachDB23(),
@@ -130,14 +132,26 @@ func TestPresets_KeyDifferences(t *testing.T) {
 	c.Assert(capability.MySQL8016().Has(capability.CheckConstraintsEnforced), qt.IsTrue)
 	c.Assert(capability.MySQLLegacy().Has(capability.CheckConstraintsEnforced), qt.IsFalse)
 
-	// Postgres version presets gate CREATE OR REPLACE TRIGGER (PG 14+) and
-	// generated-column SET EXPRESSION (PG 17+).
+	// Postgres version presets gate CREATE OR REPLACE TRIGGER (PG 14+),
+	// UNIQUE NULLS NOT DISTINCT (PG 15+), and generated-column SET
+	// EXPRESSION (PG 17+).
 	c.Assert(capability.Postgres17().Has(capability.AlterGeneratedColumnExpression), qt.IsTrue)
 	c.Assert(capability.Postgres16().Has(capability.AlterGeneratedColumnExpression), qt.IsFalse)
 	c.Assert(capability.Postgres16().Has(capability.CreateOrReplaceTrigger), qt.IsTrue)
 	c.Assert(capability.Postgres13().Has(capability.CreateOrReplaceTrigger), qt.IsFalse)
 	c.Assert(capability.Postgres13().Has(capability.AlterGeneratedColumnExpression), qt.IsFalse)
 	c.Assert(capability.Postgres13().Has(capability.CreateIndexConcurrently), qt.IsTrue)
+	c.Assert(capability.Postgres16().Has(capability.UniqueNullsNotDistinct), qt.IsTrue)
+	c.Assert(capability.Postgres14().Has(capability.UniqueNullsNotDistinct), qt.IsFalse)
+	c.Assert(capability.Postgres14().Has(capability.CreateOrReplaceTrigger), qt.IsTrue)
+	c.Assert(capability.Postgres13().Has(capability.UniqueNullsNotDistinct), qt.IsFalse)
+	c.Assert(capability.Postgres13().Has(capability.BuiltinRandomUUID), qt.IsTrue)
+	c.Assert(capability.Postgres12().Has(capability.BuiltinRandomUUID), qt.IsFalse)
+	c.Assert(capability.Postgres12().Has(capability.Extensions), qt.IsTrue)
+	c.Assert(capability.CockroachDB23().Has(capability.BuiltinRandomUUID), qt.IsTrue)
+	c.Assert(capability.MySQL80().Has(capability.BuiltinRandomUUID), qt.IsFalse)
+	c.Assert(capability.CockroachDB23().Has(capability.UniqueNullsNotDistinct), qt.IsFalse)
+	c.Assert(capability.MySQL80().Has(capability.UniqueNullsNotDistinct), qt.IsFalse)
 	c.Assert(capability.Postgres16().Has(capability.RoleManagement), qt.IsTrue)
 
 	// Enum modeling is mutually exclusive and dialect-appropriate.
@@ -187,6 +201,8 @@ func TestPresets_KeyDifferences(t *testing.T) {
 	c.Assert(spanner.Has(capability.Sequences), qt.IsFalse)
 	c.Assert(spanner.Has(capability.XMLType), qt.IsFalse)
 	c.Assert(spanner.Has(capability.RoleManagement), qt.IsFalse)
+	c.Assert(spanner.Has(capability.Functions), qt.IsFalse)
+	c.Assert(spanner.Has(capability.Extensions), qt.IsFalse)
 
 	sqlServer := capability.SQLServer2022()
 	c.Assert(sqlServer.Has(capability.DropConstraintGeneric), qt.IsTrue)
@@ -197,6 +213,21 @@ func TestPresets_KeyDifferences(t *testing.T) {
 	c.Assert(sqlServer.Has(capability.EnumInlineColumn), qt.IsFalse)
 	c.Assert(sqlServer.Has(capability.EnumCustomType), qt.IsFalse)
 	c.Assert(sqlServer.Has(capability.RowLevelSecurity), qt.IsFalse)
+
+	// PostgreSQL-style functions and extensions exist only in the PostgreSQL
+	// family.
+	c.Assert(capability.Postgres16().Has(capability.Functions), qt.IsTrue)
+	c.Assert(capability.Postgres16().Has(capability.Extensions), qt.IsTrue)
+	c.Assert(capability.MySQL80().Has(capability.Functions), qt.IsFalse)
+	c.Assert(capability.MariaDB1011().Has(capability.Extensions), qt.IsFalse)
+	c.Assert(sqlServer.Has(capability.Functions), qt.IsFalse)
+
+	// Standalone COMMENT ON statements are a PostgreSQL-family feature that
+	// Spanner's PostgreSQL interface does not accept.
+	c.Assert(capability.Postgres16().Has(capability.CommentOn), qt.IsTrue)
+	c.Assert(capability.CockroachDB23().Has(capability.CommentOn), qt.IsTrue)
+	c.Assert(spanner.Has(capability.CommentOn), qt.IsFalse)
+	c.Assert(capability.MySQL80().Has(capability.CommentOn), qt.IsFalse)
 }
 
 func TestCapabilities_With_DoesNotMutateReceiver(t *testing.T) {
@@ -277,6 +308,8 @@ func TestForServerVersion(t *testing.T) {
 		{"mariadb over mysql protocol prefix", "mysql", "5.5.5-10.11.6-MariaDB", capability.DropConstraintIfExists, true},
 		{"mariadb 10.2 exact boundary", "mariadb", "10.2.44-MariaDB", capability.DropConstraintIfExists, true},
 		{"mariadb 11.x line", "mariadb", "11.4.2-MariaDB", capability.DropConstraintIfExists, true},
+		{"mariadb 10.3 has sequences", "mariadb", "10.3.39-MariaDB", capability.Sequences, true},
+		{"mariadb 10.2 predates sequences", "mariadb", "10.2.44-MariaDB", capability.Sequences, false},
 		{"mariadb pre-10.2 degrades to the legacy floor", "mariadb", "10.1.48-MariaDB", capability.DropConstraintIfExists, false},
 		{"mariadb pre-10.2 over mysql protocol prefix", "mysql", "5.5.5-10.1.48-MariaDB", capability.CheckConstraintsEnforced, false},
 		{"mariadb unparseable stays on the modern preset", "mariadb", "MariaDB something", capability.DropConstraintIfExists, true},
@@ -284,8 +317,13 @@ func TestForServerVersion(t *testing.T) {
 		{"postgres 16 banner", "postgres", "PostgreSQL 16.3 (Debian 16.3-1.pgdg120+1)", capability.CreateOrReplaceTrigger, true},
 		{"postgres 16 lacks generated expression alter", "postgres", "PostgreSQL 16.3", capability.AlterGeneratedColumnExpression, false},
 		{"postgres 14 exact boundary", "postgres", "PostgreSQL 14.0", capability.CreateOrReplaceTrigger, true},
+		{"postgres 14 lacks nulls not distinct", "postgres", "PostgreSQL 14.11", capability.UniqueNullsNotDistinct, false},
+		{"postgres 15 exact boundary", "postgres", "PostgreSQL 15.0", capability.UniqueNullsNotDistinct, true},
 		{"postgres 13 plain", "postgres", "13.14", capability.CreateOrReplaceTrigger, false},
 		{"postgres 13 still concurrent-capable", "postgres", "13.14", capability.CreateIndexConcurrently, true},
+		{"postgres 13 has built-in gen_random_uuid", "postgres", "PostgreSQL 13.0", capability.BuiltinRandomUUID, true},
+		{"postgres 12 needs pgcrypto for gen_random_uuid", "postgres", "PostgreSQL 12.19", capability.BuiltinRandomUUID, false},
+		{"postgres 12 keeps concurrent indexes", "postgres", "PostgreSQL 12.19", capability.CreateIndexConcurrently, true},
 		{"cockroach banner disables concurrent indexes", "postgres", "CockroachDB CCL v23.2.5 (x86_64-pc-linux-gnu)", capability.CreateIndexConcurrently, false},
 		{"cockroach banner disables XML", "postgres", "CockroachDB CCL v23.2.5 (x86_64-pc-linux-gnu)", capability.XMLType, false},
 		{"yugabytedb banner disables concurrent indexes", "postgres", "PostgreSQL 11.2-YB-2.25.1.0-b0 on x86_64-pc-linux-gnu, compiled by clang", capability.CreateIndexConcurrently, false},
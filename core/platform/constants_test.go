@@ -56,3 +56,22 @@ func TestIsPostgresFamily(t *testing.T) {
 		c.Assert(platform.IsPostgresFamily(dialect), qt.IsFalse, qt.Commentf("dialect %q", dialect))
 	}
 }
+
+func TestEnumStrategyFor(t *testing.T) {
+	c := qt.New(t)
+
+	tests := map[string]platform.EnumStrategy{
+		"postgres":    platform.EnumStrategyNativeType,
+		"cockroachdb": platform.EnumStrategyNativeType,
+		"clickhouse":  platform.EnumStrategyNativeType,
+		"":            platform.EnumStrategyNativeType,
+		"mysql":       platform.EnumStrategyInlineEnum,
+		"mariadb":     platform.EnumStrategyInlineEnum,
+		"sqlite3":     platform.EnumStrategyCheckConstraint,
+		"mssql":       platform.EnumStrategyCheckConstraint,
+	}
+
+	for dialect, expected := range tests {
+		c.Assert(platform.EnumStrategyFor(dialect), qt.Equals, expected, qt.Commentf("dialect %q", dialect))
+	}
+}
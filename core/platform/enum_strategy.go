@@ -0,0 +1,36 @@
+package platform
+
+// EnumStrategy names how a dialect models a column whose values are limited to
+// a fixed enum value list, and therefore which DDL an enum value change maps to.
+type EnumStrategy string
+
+const (
+	// EnumStrategyNativeType declares the enum once as a standalone named type
+	// (PostgreSQL CREATE TYPE ... AS ENUM) that columns reference. Value
+	// changes are ALTER TYPE statements.
+	EnumStrategyNativeType EnumStrategy = "native_type"
+
+	// EnumStrategyInlineEnum spells the values into the column type itself
+	// (MySQL/MariaDB ENUM('a', 'b')). Value changes are column modifications.
+	EnumStrategyInlineEnum EnumStrategy = "inline_enum"
+
+	// EnumStrategyCheckConstraint stores the value in a text column guarded by
+	// CHECK (column IN ('a', 'b')). Value changes replace the CHECK constraint,
+	// which on SQLite means a table rebuild.
+	EnumStrategyCheckConstraint EnumStrategy = "check_constraint"
+)
+
+// EnumStrategyFor returns the enum strategy for a dialect name (normalized via
+// NormalizeDialect). PostgreSQL-family and unknown dialects keep the native
+// type model, as does ClickHouse: its Enum8/Enum16 columns are declared through
+// platform.clickhouse.type overrides rather than derived from enum values.
+func EnumStrategyFor(dialect string) EnumStrategy {
+	switch NormalizeDialect(dialect) {
+	case MySQL, MariaDB:
+		return EnumStrategyInlineEnum
+	case SQLite, SQLServer:
+		return EnumStrategyCheckConstraint
+	default:
+		return EnumStrategyNativeType
+	}
+}
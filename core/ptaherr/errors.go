@@ -12,6 +12,10 @@ var (
 	// database dialect.
 	ErrUnsupportedDialect = errors.New("unsupported database dialect")
 
+	// ErrUnknownDirective marks comments that look like Go annotation
+	// directives but name none Ptah recognizes, so the parser ignores them.
+	ErrUnknownDirective = errors.New("unknown annotation directive")
+
 	// ErrUnknownAttribute marks Go annotation directives containing an
 	// attribute Ptah does not recognize.
 	ErrUnknownAttribute = errors.New("unknown annotation attribute")
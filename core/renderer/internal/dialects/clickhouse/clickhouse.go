@@ -652,6 +652,15 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 				r.w.WriteLinef("-- CLICKHOUSE: column %q %s", op.Column.Name, mapping.notice)
 			}
 			r.w.WriteLinef("ALTER TABLE %s MODIFY COLUMN %s %s;", node.Name, op.Column.Name, mapping.mapped)
+		case *ast.AlterColumnDefaultOperation:
+			switch {
+			case op.Column.Default == nil:
+				r.w.WriteLinef("ALTER TABLE %s MODIFY COLUMN %s REMOVE DEFAULT;", node.Name, op.Column.Name)
+			case op.Column.Default.Expression != "":
+				r.w.WriteLinef("ALTER TABLE %s MODIFY COLUMN %s DEFAULT %s;", node.Name, op.Column.Name, op.Column.Default.Expression)
+			default:
+				r.w.WriteLinef("ALTER TABLE %s MODIFY COLUMN %s DEFAULT %s;", node.Name, op.Column.Name, escapeStringLiteral(op.Column.Default.Value))
+			}
 		case *ast.AddConstraintOperation:
 			if op.Constraint.Type != ast.CheckConstraint {
 				r.notSupported(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT (non-CHECK)", node.Name), op.Constraint.Name)
@@ -681,6 +690,12 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 			} else {
 				r.w.WriteLinef("ALTER TABLE %s MODIFY TTL %s;", node.Name, op.Expression)
 			}
+		case *ast.InheritOperation:
+			r.notSupported("PostgreSQL table inheritance", node.Name)
+		case *ast.SetAutoIncrementOperation:
+			r.notSupported("MySQL table AUTO_INCREMENT", node.Name)
+		case *ast.AlterIndexVisibilityOperation:
+			r.notSupported("MySQL index visibility", node.Name)
 		default:
 			return fmt.Errorf("clickhouse: unknown ALTER TABLE operation %T", op)
 		}
@@ -6,6 +6,7 @@ import (
 	qt "github.com/frankban/quicktest"
 
 	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/platform/capability"
 	"github.com/stokaro/ptah/core/renderer"
 )
 
@@ -101,3 +102,60 @@ func TestMariaDBRenderer_EscapesEmbeddedBackticks(t *testing.T) {
 	c.Assert(sql, qt.Contains, "CREATE TABLE `tenant``data` (")
 	c.Assert(sql, qt.Contains, "`order``key` int")
 }
+
+func TestMariaDBRenderer_Sequences(t *testing.T) {
+	tests := []struct {
+		name string
+		node ast.Node
+		want string
+	}{
+		{
+			name: "create drops AS type and OWNED BY",
+			node: ast.NewCreateSequence("order_numbers").
+				SetIfNotExists().
+				SetAs("integer").
+				SetStart(1000).
+				SetIncrement(5).
+				SetMaxValue(99999).
+				SetCache(20).
+				SetCycle(true).
+				SetOwnedBy("orders.number"),
+			want: "CREATE SEQUENCE IF NOT EXISTS `order_numbers` START WITH 1000 INCREMENT BY 5 MAXVALUE 99999 CACHE 20 CYCLE;\n",
+		},
+		{
+			name: "alter emits only set options",
+			node: ast.NewAlterSequence("order_numbers").SetIncrement(10).SetCycle(false),
+			want: "ALTER SEQUENCE `order_numbers` INCREMENT BY 10 NOCYCLE;\n",
+		},
+		{
+			name: "alter with only OWNED BY renders nothing",
+			node: ast.NewAlterSequence("order_numbers").SetOwnedBy("orders.number"),
+			want: "",
+		},
+		{
+			name: "drop omits CASCADE",
+			node: ast.NewDropSequence("order_numbers").SetSchema("shop").SetIfExists().SetCascade(),
+			want: "DROP SEQUENCE IF EXISTS `shop`.`order_numbers`;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			sql, err := renderer.RenderSQL("mariadb", tt.node)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestMariaDBRenderer_SequencesSkippedWithoutCapability(t *testing.T) {
+	c := qt.New(t)
+
+	sql, err := renderer.RenderSQLWithCapabilities("mariadb", capability.MariaDBLegacy(), ast.NewCreateSequence("order_numbers"))
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Equals, "-- CREATE SEQUENCE order_numbers not supported in mariadb\n")
+}
@@ -133,6 +133,10 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 				return fmt.Errorf("render modified column %s: %w", op.Column.Name, err)
 			}
 			r.w.WriteLinef("ALTER TABLE %s ALTER COLUMN %s;", escapeQualifiedIdentifier(node.Name), line)
+		case *ast.AlterColumnDefaultOperation:
+			// A SQL Server default is a named constraint; changing it needs
+			// the constraint name, which the column alone does not carry.
+			r.notSupported("ALTER COLUMN SET/DROP DEFAULT on column", node.Name+"."+op.Column.Name)
 		case *ast.RenameColumnOperation:
 			r.w.WriteLinef("EXEC sp_rename %s, %s, 'COLUMN';",
 				escapeStringLiteral(node.Name+"."+op.OldName),
@@ -145,6 +149,12 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 			)
 		case *ast.AddSkippingIndexOperation, *ast.ModifyTTLOperation:
 			r.notSupported("ClickHouse table option", node.Name)
+		case *ast.InheritOperation:
+			r.notSupported("PostgreSQL table inheritance", node.Name)
+		case *ast.SetAutoIncrementOperation:
+			r.notSupported("MySQL table AUTO_INCREMENT", node.Name)
+		case *ast.AlterIndexVisibilityOperation:
+			r.notSupported("MySQL index visibility", node.Name)
 		default:
 			return unsupportedFeaturef("unsupported alter table operation %T", operation)
 		}
@@ -149,6 +149,26 @@ func TestMySQLRenderer_FulltextIndexParser(t *testing.T) {
 	c.Assert(sql, qt.Contains, "CREATE FULLTEXT INDEX `idx_users_bio` ON `users` (`bio`) /*!50100 WITH PARSER `ngram` */;")
 }
 
+func TestMySQLRenderer_IndexTypes(t *testing.T) {
+	c := qt.New(t)
+
+	spatial := ast.NewIndex("idx_places_location", "places", "location")
+	spatial.Type = "spatial"
+	hash := ast.NewIndex("idx_places_code", "places", "code")
+	hash.Type = "hash"
+	btree := ast.NewIndex("idx_places_name", "places", "name")
+	btree.Type = "BTREE"
+	gin := ast.NewIndex("idx_places_tags", "places", "tags")
+	gin.Type = "gin"
+
+	sql := renderMySQL(t, spatial, hash, btree, gin)
+
+	c.Assert(sql, qt.Contains, "CREATE SPATIAL INDEX `idx_places_location` ON `places` (`location`);")
+	c.Assert(sql, qt.Contains, "CREATE INDEX `idx_places_code` ON `places` (`code`) USING HASH;")
+	c.Assert(sql, qt.Contains, "CREATE INDEX `idx_places_name` ON `places` (`name`) USING BTREE;")
+	c.Assert(sql, qt.Contains, "CREATE INDEX `idx_places_tags` ON `places` (`tags`);")
+}
+
 func TestMySQLRenderer_EscapesReservedIdentifiers(t *testing.T) {
 	c := qt.New(t)
 
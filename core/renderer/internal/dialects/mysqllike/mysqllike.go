@@ -475,11 +475,39 @@ func (r *Renderer) VisitIndex(node *ast.IndexNode) error {
 		columnSpec += fmt.Sprintf(" /*!50100 WITH PARSER %s */", escapeIdentifier(node.Parser))
 	}
 	parts = append(parts, columnSpec)
+	if method := mysqlIndexMethod(node.Type); method != "" {
+		parts = append(parts, "USING", method)
+	}
+	if node.Invisible {
+		parts = append(parts, r.indexVisibilityKeyword(false))
+	}
+	if node.Online {
+		parts = append(parts, "ALGORITHM=INPLACE", "LOCK=NONE")
+	}
 
 	r.w.WriteLinef("%s;", strings.Join(parts, " "))
 	return nil
 }
 
+// onlineDDLClause is the ALTER TABLE online DDL hint: an in-place change
+// that allows concurrent reads and writes.
+const onlineDDLClause = "ALGORITHM=INPLACE, LOCK=NONE"
+
+// indexVisibilityKeyword returns the index visibility option: MySQL spells
+// it VISIBLE/INVISIBLE, MariaDB NOT IGNORED/IGNORED.
+func (r *Renderer) indexVisibilityKeyword(visible bool) string {
+	switch {
+	case r.dialect == "mariadb" && visible:
+		return "NOT IGNORED"
+	case r.dialect == "mariadb":
+		return "IGNORED"
+	case visible:
+		return "VISIBLE"
+	default:
+		return "INVISIBLE"
+	}
+}
+
 func renderIndexParts(parts []ast.IndexPart) []string {
 	specs := make([]string, 0, len(parts))
 	for _, part := range parts {
@@ -508,6 +536,19 @@ func mysqlIndexPrefixType(indexType string) string {
 	}
 }
 
+// mysqlIndexMethod returns the USING clause value for BTREE and HASH index
+// types. FULLTEXT and SPATIAL are rendered as an index prefix instead, and
+// types of other dialects are not rendered at all.
+func mysqlIndexMethod(indexType string) string {
+	normalized := strings.ToUpper(strings.TrimSpace(indexType))
+	switch normalized {
+	case "BTREE", "HASH":
+		return normalized
+	default:
+		return ""
+	}
+}
+
 // VisitEnum renders enum handling for MariaDB (inline ENUM types like MySQL)
 func (r *Renderer) VisitEnum(node *ast.EnumNode) error {
 	// MariaDB doesn't have separate enum types like PostgreSQL
@@ -725,6 +766,25 @@ func (r *Renderer) appendColumnTail(parts []string, column *ast.ColumnNode) []st
 	return parts
 }
 
+// columnDefaultAction renders the ALTER COLUMN action that sets column's
+// default, or drops it when there is none. The ALTER COLUMN form accepts a
+// literal or a parenthesized expression (MySQL 8.0.13+, MariaDB 10.2+), and
+// unlike MODIFY COLUMN it keeps every other column attribute as it is.
+func (r *Renderer) columnDefaultAction(column *ast.ColumnNode) string {
+	switch {
+	case column.Default == nil:
+		return "DROP DEFAULT"
+	case column.Default.HasLiteral():
+		return "SET DEFAULT " + r.renderDefaultLiteral(column)
+	default:
+		expression := strings.TrimSpace(column.Default.Expression)
+		if !strings.HasPrefix(expression, "(") || !strings.HasSuffix(expression, ")") {
+			expression = "(" + expression + ")"
+		}
+		return "SET DEFAULT " + expression
+	}
+}
+
 func (r *Renderer) rendersNamedColumnCheckAsTableConstraint(column *ast.ColumnNode) bool {
 	return r.dialect == "mariadb" && column.Check != "" && column.CheckName != ""
 }
@@ -918,6 +978,9 @@ func (r *Renderer) visitAlterTableWithEnums(node *ast.AlterTableNode, enums map[
 			}
 			// Remove the leading spaces from column rendering for ALTER
 			line = strings.TrimPrefix(line, "  ")
+			if op.Online {
+				line += ", " + onlineDDLClause
+			}
 			r.w.WriteLinef("ALTER TABLE %s ADD COLUMN %s;", escapeQualifiedIdentifier(node.Name), line)
 
 		case *ast.AddConstraintOperation:
@@ -951,6 +1014,9 @@ func (r *Renderer) visitAlterTableWithEnums(node *ast.AlterTableNode, enums map[
 			line = strings.TrimPrefix(line, "  ")
 			r.w.WriteLinef("ALTER TABLE %s MODIFY COLUMN %s;", escapeQualifiedIdentifier(node.Name), line)
 
+		case *ast.AlterColumnDefaultOperation:
+			r.w.WriteLinef("ALTER TABLE %s ALTER COLUMN %s %s;", escapeQualifiedIdentifier(node.Name), escapeIdentifier(op.Column.Name), r.columnDefaultAction(op.Column))
+
 		case *ast.RenameColumnOperation:
 			// MySQL 8.0+ and MariaDB 10.5.2+ both support the canonical
 			// `ALTER TABLE x RENAME COLUMN old TO new` form. The runtime
@@ -971,6 +1037,16 @@ func (r *Renderer) visitAlterTableWithEnums(node *ast.AlterTableNode, enums map[
 			// Table TTL (row expiration) is a ClickHouse-only feature.
 			r.w.WriteLinef("-- %s: table TTL is ClickHouse-specific; ignored.", r.dialectUpper)
 
+		case *ast.InheritOperation:
+			// Table inheritance is a PostgreSQL-only feature.
+			r.w.WriteLinef("-- %s: table inheritance is PostgreSQL-specific; ignored.", r.dialectUpper)
+
+		case *ast.SetAutoIncrementOperation:
+			r.w.WriteLinef("ALTER TABLE %s AUTO_INCREMENT = %d;", escapeQualifiedIdentifier(node.Name), op.Value)
+
+		case *ast.AlterIndexVisibilityOperation:
+			r.w.WriteLinef("ALTER TABLE %s ALTER INDEX %s %s;", escapeQualifiedIdentifier(node.Name), escapeIdentifier(op.Index), r.indexVisibilityKeyword(op.Visible))
+
 		default:
 			return fmt.Errorf("unknown alter operation type: %T", operation)
 		}
@@ -1045,37 +1121,114 @@ func (r *Renderer) VisitDropFunction(node *ast.DropFunctionNode) error {
 	return fmt.Errorf("DROP FUNCTION is not supported in %s (PostgreSQL-specific feature)", r.dialectUpper)
 }
 
-// VisitCreateSequence renders CREATE SEQUENCE for MySQL-like databases (no-op).
-// Standalone sequences are a PostgreSQL-specific object.
+// VisitCreateSequence renders CREATE SEQUENCE for targets with sequence
+// support (MariaDB 10.3+) and a skip comment elsewhere: MySQL has no sequence
+// objects. MariaDB before 11.5 accepts neither AS <type> nor OWNED BY, so both
+// are left out; its sequences are always BIGINT.
 func (r *Renderer) VisitCreateSequence(node *ast.CreateSequenceNode) error {
+	if !r.caps.Has(capability.Sequences) {
+		r.writeUnsupportedSequence("CREATE", node.Name, node.Comment)
+		return nil
+	}
 	if node.Comment != "" {
-		r.w.WriteLinef("-- CREATE SEQUENCE %s not supported in %s: %s", node.Name, r.dialect, node.Comment)
-	} else {
-		r.w.WriteLinef("-- CREATE SEQUENCE %s not supported in %s", node.Name, r.dialect)
+		r.w.WriteLinef("-- %s", node.Comment)
 	}
+	parts := []string{"CREATE SEQUENCE"}
+	if node.IfNotExists {
+		parts = append(parts, "IF NOT EXISTS")
+	}
+	parts = append(parts, sequenceIdentifier(node.Name, node.Schema))
+	var cycle *bool
+	if node.Cycle {
+		cycle = &node.Cycle
+	}
+	parts = append(parts, sequenceOptions(node.Start, node.Increment, node.MinValue, node.MaxValue, node.Cache, cycle)...)
+	r.w.WriteLinef("%s;", strings.Join(parts, " "))
 	return nil
 }
 
-// VisitAlterSequence renders ALTER SEQUENCE for MySQL-like databases (no-op).
+// VisitAlterSequence renders ALTER SEQUENCE for targets with sequence
+// support. Only the set options are emitted; a node with no options MariaDB
+// can express renders nothing.
 func (r *Renderer) VisitAlterSequence(node *ast.AlterSequenceNode) error {
+	if !r.caps.Has(capability.Sequences) {
+		r.writeUnsupportedSequence("ALTER", node.Name, node.Comment)
+		return nil
+	}
+	options := sequenceOptions(node.Start, node.Increment, node.MinValue, node.MaxValue, node.Cache, node.Cycle)
+	if len(options) == 0 {
+		return nil
+	}
 	if node.Comment != "" {
-		r.w.WriteLinef("-- ALTER SEQUENCE %s not supported in %s: %s", node.Name, r.dialect, node.Comment)
-	} else {
-		r.w.WriteLinef("-- ALTER SEQUENCE %s not supported in %s", node.Name, r.dialect)
+		r.w.WriteLinef("-- %s", node.Comment)
 	}
+	r.w.WriteLinef("ALTER SEQUENCE %s %s;", sequenceIdentifier(node.Name, node.Schema), strings.Join(options, " "))
 	return nil
 }
 
-// VisitDropSequence renders DROP SEQUENCE for MySQL-like databases (no-op).
+// VisitDropSequence renders DROP SEQUENCE for targets with sequence support.
+// CASCADE is dropped: MariaDB has no dependency tracking for sequences.
 func (r *Renderer) VisitDropSequence(node *ast.DropSequenceNode) error {
+	if !r.caps.Has(capability.Sequences) {
+		r.writeUnsupportedSequence("DROP", node.Name, node.Comment)
+		return nil
+	}
 	if node.Comment != "" {
-		r.w.WriteLinef("-- DROP SEQUENCE %s not supported in %s: %s", node.Name, r.dialect, node.Comment)
-	} else {
-		r.w.WriteLinef("-- DROP SEQUENCE %s not supported in %s", node.Name, r.dialect)
+		r.w.WriteLinef("-- %s", node.Comment)
 	}
+	parts := []string{"DROP SEQUENCE"}
+	if node.IfExists {
+		parts = append(parts, "IF EXISTS")
+	}
+	parts = append(parts, sequenceIdentifier(node.Name, node.Schema))
+	r.w.WriteLinef("%s;", strings.Join(parts, " "))
 	return nil
 }
 
+func (r *Renderer) writeUnsupportedSequence(verb, name, comment string) {
+	if comment != "" {
+		r.w.WriteLinef("-- %s SEQUENCE %s not supported in %s: %s", verb, name, r.dialect, comment)
+	} else {
+		r.w.WriteLinef("-- %s SEQUENCE %s not supported in %s", verb, name, r.dialect)
+	}
+}
+
+func sequenceIdentifier(name, schema string) string {
+	if schema != "" {
+		return escapeIdentifier(schema) + "." + escapeIdentifier(name)
+	}
+	return escapeIdentifier(name)
+}
+
+// sequenceOptions renders the MariaDB sequence option clauses in declaration
+// order. A nil cycle leaves the cycle option unchanged.
+func sequenceOptions(start, increment, minValue, maxValue, cache *int64, cycle *bool) []string {
+	var options []string
+	if start != nil {
+		options = append(options, fmt.Sprintf("START WITH %d", *start))
+	}
+	if increment != nil {
+		options = append(options, fmt.Sprintf("INCREMENT BY %d", *increment))
+	}
+	if minValue != nil {
+		options = append(options, fmt.Sprintf("MINVALUE %d", *minValue))
+	}
+	if maxValue != nil {
+		options = append(options, fmt.Sprintf("MAXVALUE %d", *maxValue))
+	}
+	if cache != nil {
+		options = append(options, fmt.Sprintf("CACHE %d", *cache))
+	}
+	if cycle != nil {
+		if *cycle {
+			options = append(options, "CYCLE")
+		} else {
+			options = append(options, "NOCYCLE")
+		}
+	}
+	return options
+}
+
 // VisitDropPolicy returns an error since RLS policies are not supported in MySQL
 func (r *Renderer) VisitDropPolicy(node *ast.DropPolicyNode) error {
 	return fmt.Errorf("DROP POLICY is not supported in %s (PostgreSQL-specific feature)", r.dialectUpper)
@@ -142,3 +142,28 @@ func TestPostgres_AlterTable_SetGeneratedExpressionUnsupported(t *testing.T) {
 	c.Assert(renderer.Output(), qt.Contains, `ALTER COLUMN SET EXPRESSION requires PostgreSQL 17+`)
 	c.Assert(renderer.Output(), qt.Not(qt.Contains), `SET EXPRESSION AS`)
 }
+
+func TestPostgres_AlterTable_Inherit(t *testing.T) {
+	c := qt.New(t)
+	alter := &ast.AlterTableNode{
+		Name: "capitals",
+		Operations: []ast.AlterOperation{
+			&ast.InheritOperation{Parent: "cities"},
+			&ast.InheritOperation{Parent: "geo.places", NoInherit: true},
+		},
+	}
+	out := legacyPostgresSQL(renderPG(t, alter))
+	c.Assert(out, qt.Contains, "ALTER TABLE capitals INHERIT cities;")
+	c.Assert(out, qt.Contains, "ALTER TABLE capitals NO INHERIT geo.places;")
+}
+
+func TestPostgres_CreateTable_Inherits(t *testing.T) {
+	c := qt.New(t)
+	table := ast.NewCreateTable("capitals").
+		AddColumn(ast.NewColumn("state", "CHAR(2)"))
+	table.Inherits = []string{"cities", "geo.places"}
+
+	out := legacyPostgresSQL(renderPG(t, table))
+
+	c.Assert(out, qt.Contains, "CREATE TABLE capitals (\n  state CHAR(2)\n) INHERITS (cities, geo.places);")
+}
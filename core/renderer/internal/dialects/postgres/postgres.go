@@ -11,6 +11,7 @@ import (
 	"github.com/stokaro/ptah/core/platform/capability"
 	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/core/renderer/internal/dialects/internal/bufwriter"
+	"github.com/stokaro/ptah/core/sqlutil"
 )
 
 // Renderer provides PostgreSQL-specific SQL rendering
@@ -371,6 +372,16 @@ func (r *Renderer) VisitCreateTable(node *ast.CreateTableNode) error {
 
 	r.w.Write(")")
 
+	if len(node.Inherits) > 0 {
+		parents := make([]string, 0, len(node.Inherits))
+		for _, parent := range node.Inherits {
+			parents = append(parents, r.escapeQualifiedIdentifier(parent))
+		}
+		r.w.Write(" INHERITS (")
+		r.w.Write(strings.Join(parents, ", "))
+		r.w.Write(")")
+	}
+
 	if node.Partition != nil {
 		partition, err := r.renderPartition(node.Partition)
 		if err != nil {
@@ -522,7 +533,12 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 			}
 			// Remove the leading spaces from constraint rendering for ALTER
 			constraintLine = strings.TrimPrefix(constraintLine, "  ")
+			if rendersNotValid(op.Constraint) {
+				constraintLine += " NOT VALID"
+			}
 			r.w.WriteLinef("ALTER TABLE %s ADD %s;", r.escapeQualifiedIdentifier(node.Name), constraintLine)
+		case *ast.ValidateConstraintOperation:
+			r.w.WriteLinef("ALTER TABLE %s VALIDATE CONSTRAINT %s;", r.escapeQualifiedIdentifier(node.Name), r.escapeIdentifier(op.ConstraintName))
 		case *ast.DropConstraintOperation:
 			dropSQL := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT", r.escapeQualifiedIdentifier(node.Name))
 			if op.IfExists {
@@ -538,7 +554,9 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 			r.w.WriteLinef("%s;", dropSQL)
 		case *ast.ModifyColumnOperation:
 			// PostgreSQL uses different syntax for modifying columns
-			r.renderPostgreSQLModifyColumn(node.Name, op.Column)
+			r.renderPostgreSQLModifyColumn(node.Name, op.Column, op.SkipNullBackfill)
+		case *ast.AlterColumnDefaultOperation:
+			r.renderPostgreSQLColumnDefault(node.Name, op.Column)
 		case *ast.AlterGeneratedColumnExpressionOperation:
 			if !r.capabilities().Has(capability.AlterGeneratedColumnExpression) {
 				r.w.WriteLinef(
@@ -571,6 +589,18 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 		case *ast.ModifyTTLOperation:
 			// Table TTL (row expiration) is a ClickHouse-only feature.
 			r.w.WriteLinef("-- %s: table TTL is ClickHouse-specific; ignored.", r.dialectUpper)
+		case *ast.InheritOperation:
+			keyword := "INHERIT"
+			if op.NoInherit {
+				keyword = "NO INHERIT"
+			}
+			r.w.WriteLinef("ALTER TABLE %s %s %s;", r.escapeQualifiedIdentifier(node.Name), keyword, r.escapeQualifiedIdentifier(op.Parent))
+		case *ast.SetAutoIncrementOperation:
+			// The table AUTO_INCREMENT counter is a MySQL-family option.
+			r.w.WriteLinef("-- %s: table AUTO_INCREMENT is MySQL-specific; ignored.", r.dialectUpper)
+		case *ast.AlterIndexVisibilityOperation:
+			// Invisible indexes are a MySQL-family feature.
+			r.w.WriteLinef("-- %s: index visibility is MySQL-specific; ignored.", r.dialectUpper)
 		default:
 			return fmt.Errorf("unknown alter operation type: %T", operation)
 		}
@@ -581,6 +611,13 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 	return nil
 }
 
+// rendersNotValid reports whether an added constraint carries the NOT VALID
+// suffix. PostgreSQL accepts it only for CHECK and FOREIGN KEY constraints.
+func rendersNotValid(constraint *ast.ConstraintNode) bool {
+	return constraint.NotValid &&
+		(constraint.Type == ast.CheckConstraint || constraint.Type == ast.ForeignKeyConstraint)
+}
+
 func (r *Renderer) VisitColumn(node *ast.ColumnNode) error {
 	// This is typically called from within other visitors
 	// The actual rendering is done by RenderColumn
@@ -594,6 +631,11 @@ func (r *Renderer) VisitConstraint(node *ast.ConstraintNode) error {
 }
 
 func (r *Renderer) VisitIndex(node *ast.IndexNode) error {
+	if node.Invisible {
+		// PostgreSQL has no invisible indexes; the index is created visible.
+		r.w.WriteLinef("-- %s: invisible index %q is MySQL-specific; created visible.", r.dialectUpper, node.Name)
+	}
+
 	var parts []string
 
 	parts = append(parts, "CREATE")
@@ -1075,8 +1117,9 @@ func (r *Renderer) renderTableOptions(options map[string]string) string {
 	return strings.Join(parts, " ")
 }
 
-// renderPostgreSQLModifyColumn renders PostgreSQL-specific column modifications
-func (r *Renderer) renderPostgreSQLModifyColumn(tableName string, column *ast.ColumnNode) {
+// renderPostgreSQLModifyColumn renders PostgreSQL-specific column modifications.
+// skipNullBackfill omits the NULL backfill block ahead of SET NOT NULL.
+func (r *Renderer) renderPostgreSQLModifyColumn(tableName string, column *ast.ColumnNode, skipNullBackfill bool) {
 	// PostgreSQL requires separate ALTER statements for different column properties
 
 	// Process the column type with enum support
@@ -1110,12 +1153,21 @@ func (r *Renderer) renderPostgreSQLModifyColumn(tableName string, column *ast.Co
 	if column.Nullable {
 		r.w.WriteLinef("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", r.escapeQualifiedIdentifier(tableName), r.escapeIdentifier(column.Name))
 	} else {
-		r.updateNullValuesBeforeNotNull(tableName, column)
+		if !skipNullBackfill {
+			r.updateNullValuesBeforeNotNull(tableName, column)
+		}
 		r.w.WriteLinef("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", r.escapeQualifiedIdentifier(tableName), r.escapeIdentifier(column.Name))
 	}
 
-	// Change default value
+	r.renderPostgreSQLColumnDefault(tableName, column)
+}
+
+// renderPostgreSQLColumnDefault renders SET DEFAULT or DROP DEFAULT for
+// column. An identity column has no DEFAULT to drop, and PostgreSQL rejects
+// DROP DEFAULT on one.
+func (r *Renderer) renderPostgreSQLColumnDefault(tableName string, column *ast.ColumnNode) {
 	switch {
+	case column.Default == nil && column.IdentityGeneration != "":
 	case column.Default == nil:
 		r.w.WriteLinef("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", r.escapeQualifiedIdentifier(tableName), r.escapeIdentifier(column.Name))
 	case column.Default.HasLiteral():
@@ -1527,6 +1579,9 @@ func (r *Renderer) VisitCreateMaterializedView(node *ast.CreateMaterializedViewN
 
 	r.w.WriteLinef("CREATE MATERIALIZED VIEW %s AS", r.escapeQualifiedIdentifier(node.Name))
 	r.w.WriteLine(strings.TrimSpace(node.Body))
+	if node.WithNoData {
+		r.w.WriteLine("WITH NO DATA")
+	}
 	r.w.WriteLine(";")
 	return nil
 }
@@ -1756,7 +1811,10 @@ func (r *Renderer) VisitCreateRole(node *ast.CreateRoleNode) error {
 		attributes = append(attributes, "NOLOGIN")
 	}
 
-	if node.Password != "" {
+	switch {
+	case node.PasswordEnv != "":
+		attributes = append(attributes, r.envPassword(node.PasswordEnv))
+	case node.Password != "":
 		// Validate password appears to be encrypted
 		if !looksEncrypted(node.Password) {
 			// Add a comment warning about potential plaintext password
@@ -1947,6 +2005,10 @@ func (r *Renderer) renderRoleOperation(roleName string, operation ast.RoleOperat
 
 	switch op := operation.(type) {
 	case *ast.SetPasswordOperation:
+		if op.PasswordEnv != "" {
+			parts = append(parts, r.envPassword(op.PasswordEnv))
+			break
+		}
 		// Validate password appears to be encrypted
 		if !looksEncrypted(op.Password) {
 			// Add a comment warning about potential plaintext password
@@ -2004,6 +2066,14 @@ func (r *Renderer) renderRoleOperation(roleName string, operation ast.RoleOperat
 	return nil
 }
 
+// envPassword writes a comment naming the environment variable and returns
+// the PASSWORD clause with its placeholder, which the migrator expands when
+// the statement runs.
+func (r *Renderer) envPassword(name string) string {
+	r.w.WriteLinef("-- Password is read from the %s environment variable when the migration runs", name)
+	return "PASSWORD " + sqlutil.EnvPlaceholder(name)
+}
+
 // looksEncrypted checks if a password appears to be encrypted/hashed
 // This is a heuristic check to help detect potential plaintext passwords
 func looksEncrypted(password string) bool {
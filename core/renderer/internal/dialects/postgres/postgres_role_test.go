@@ -36,6 +36,21 @@ func TestPostgreSQLRenderer_VisitCreateRole(t *testing.T) {
 		c.Assert(sql, qt.Contains, "NOSUPERUSER NOCREATEDB NOCREATEROLE INHERIT NOREPLICATION")
 	})
 
+	t.Run("role with password from the environment", func(t *testing.T) {
+		c := qt.New(t)
+		renderer := postgres.New()
+
+		role := ast.NewCreateRole("app_user").
+			SetLogin(true).
+			SetPasswordEnv("APP_USER_PASSWORD")
+		sql, err := renderer.Render(role)
+
+		c.Assert(err, qt.IsNil)
+		lines := strings.Split(strings.TrimSpace(legacyPostgresSQL(sql)), "\n")
+		c.Assert(lines[0], qt.Equals, "-- Password is read from the APP_USER_PASSWORD environment variable when the migration runs")
+		c.Assert(lines[1], qt.Contains, "CREATE ROLE app_user WITH LOGIN PASSWORD :'APP_USER_PASSWORD' NOSUPERUSER")
+	})
+
 	t.Run("superuser role", func(t *testing.T) {
 		c := qt.New(t)
 		renderer := postgres.New()
@@ -177,6 +192,19 @@ func TestPostgreSQLRenderer_VisitAlterRole(t *testing.T) {
 		c.Assert(lines[6], qt.Equals, "ALTER ROLE test_role REPLICATION;")
 	})
 
+	t.Run("alter role password from the environment", func(t *testing.T) {
+		c := qt.New(t)
+		renderer := postgres.New()
+
+		alterRole := ast.NewAlterRole("app_user").
+			AddOperation(ast.NewSetPasswordFromEnvOperation("APP_USER_PASSWORD"))
+		sql, err := renderer.Render(alterRole)
+
+		c.Assert(err, qt.IsNil)
+		c.Assert(legacyPostgresSQL(sql), qt.Equals, "-- Password is read from the APP_USER_PASSWORD environment variable when the migration runs\n"+
+			"ALTER ROLE app_user PASSWORD :'APP_USER_PASSWORD';\n")
+	})
+
 	t.Run("alter role with comment", func(t *testing.T) {
 		c := qt.New(t)
 		renderer := postgres.New()
@@ -35,6 +35,19 @@ func TestPostgreSQLRenderer_ViewsMaterializedViewsAndTriggers(t *testing.T) {
 	c.Assert(legacyPostgresSQL(sql), qt.Contains, "CREATE OR REPLACE TRIGGER set_updated_at BEFORE UPDATE ON users FOR EACH ROW EXECUTE FUNCTION ptah_trigger_set_updated_at();")
 }
 
+func TestPostgreSQLRenderer_MaterializedViewWithNoData(t *testing.T) {
+	c := qt.New(t)
+
+	sql, err := renderer.RenderSQL("postgres",
+		ast.NewCreateMaterializedView("user_stats").
+			SetBody("SELECT id, COUNT(*) FROM users GROUP BY id").
+			SetWithNoData(true),
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, "SELECT id, COUNT(*) FROM users GROUP BY id\nWITH NO DATA\n;")
+}
+
 func TestPostgreSQLRenderer_DropTriggerUsesConfiguredFunctionName(t *testing.T) {
 	c := qt.New(t)
 
@@ -116,7 +116,7 @@ func (r *Renderer) VisitAlterTable(node *ast.AlterTableNode) error {
 			)
 		case *ast.RenameTableOperation:
 			r.w.WriteLinef("ALTER TABLE %s RENAME TO %s;", escapeQualifiedIdentifier(node.Name), escapeIdentifier(op.NewName))
-		case *ast.DropColumnOperation, *ast.ModifyColumnOperation, *ast.DropConstraintOperation, *ast.AddConstraintOperation:
+		case *ast.DropColumnOperation, *ast.ModifyColumnOperation, *ast.AlterColumnDefaultOperation, *ast.DropConstraintOperation, *ast.AddConstraintOperation:
 			return unsupportedFeaturef("%T requires a table rebuild plan", operation)
 		default:
 			return unsupportedFeaturef("unsupported alter table operation %T", operation)
@@ -0,0 +1,99 @@
+package sqlutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stokaro/ptah/internal/lexer"
+)
+
+var envPlaceholderNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvPlaceholder returns the placeholder that stands for the value of the
+// environment variable name in a generated migration: :'NAME', the psql
+// syntax for a variable interpolated as a quoted literal. The migrator
+// expands it when the statement runs, so the value is never written to the
+// migration file. Unexpanded, the placeholder is a syntax error, so a file
+// run by a tool that does not expand it fails instead of storing the name.
+func EnvPlaceholder(name string) string {
+	return ":'" + name + "'"
+}
+
+// IsEnvPlaceholderName reports whether name can be used in an EnvPlaceholder:
+// letters, digits, and underscores, not starting with a digit.
+func IsEnvPlaceholderName(name string) bool {
+	return envPlaceholderNameRe.MatchString(name)
+}
+
+// HasEnvPlaceholders reports whether sql contains an EnvPlaceholder outside
+// string literals and comments.
+func HasEnvPlaceholders(sql string) bool {
+	found := false
+	scanEnvPlaceholders(sql, func(int, int, string) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// ExpandEnvPlaceholders replaces every EnvPlaceholder in sql with the value
+// lookup returns for its name, written as a single-quoted string literal.
+// Placeholders inside string literals and comments are left alone. A name
+// lookup does not know is an error; the error names the variable but never
+// includes a value.
+func ExpandEnvPlaceholders(sql string, lookup func(name string) (string, bool)) (string, error) {
+	var b strings.Builder
+	last := 0
+	var err error
+	scanEnvPlaceholders(sql, func(start, end int, name string) bool {
+		value, ok := lookup(name)
+		if !ok {
+			err = fmt.Errorf("environment variable %s referenced by placeholder %s is not set", name, EnvPlaceholder(name))
+			return false
+		}
+		if strings.ContainsRune(value, 0) {
+			err = fmt.Errorf("environment variable %s referenced by placeholder %s contains a NUL byte", name, EnvPlaceholder(name))
+			return false
+		}
+		b.WriteString(sql[last:start])
+		b.WriteString("'" + strings.ReplaceAll(value, "'", "''") + "'")
+		last = end
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	if last == 0 {
+		return sql, nil
+	}
+	b.WriteString(sql[last:])
+	return b.String(), nil
+}
+
+// scanEnvPlaceholders calls fn with the byte range and variable name of
+// every placeholder in sql, in order, until fn returns false. A placeholder
+// is a lone colon immediately followed by a single-quoted variable name.
+func scanEnvPlaceholders(sql string, fn func(start, end int, name string) bool) {
+	lexr := lexer.NewLexer(sql)
+	var colon lexer.Token
+	colonEnd := -1
+	for {
+		tok := lexr.NextToken()
+		if tok.Type == lexer.TokenEOF {
+			return
+		}
+		if tok.Type == lexer.TokenString && tok.Start == colonEnd && (colon.Start == 0 || sql[colon.Start-1] != ':') {
+			name, quoted := strings.CutPrefix(tok.Value, "'")
+			name, closed := strings.CutSuffix(name, "'")
+			if quoted && closed && IsEnvPlaceholderName(name) && !fn(colon.Start, tok.End, name) {
+				return
+			}
+		}
+		colonEnd = -1
+		if tok.Type == lexer.TokenOperator && tok.Value == ":" {
+			colon = tok
+			colonEnd = tok.End
+		}
+	}
+}
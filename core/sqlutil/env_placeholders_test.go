@@ -0,0 +1,122 @@
+package sqlutil_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/sqlutil"
+)
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+func TestExpandEnvPlaceholders(t *testing.T) {
+	env := map[string]string{
+		"APP_USER_PASSWORD": "s3cret",
+		"QUOTED":            "it's",
+	}
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "role password",
+			sql:  `ALTER ROLE "app_user" WITH PASSWORD :'APP_USER_PASSWORD';`,
+			want: `ALTER ROLE "app_user" WITH PASSWORD 's3cret';`,
+		},
+		{
+			name: "quotes in the value are doubled",
+			sql:  `CREATE ROLE "app_user" WITH PASSWORD :'QUOTED';`,
+			want: `CREATE ROLE "app_user" WITH PASSWORD 'it''s';`,
+		},
+		{
+			name: "placeholder inside a string literal is kept",
+			sql:  `SELECT ':''APP_USER_PASSWORD''';`,
+			want: `SELECT ':''APP_USER_PASSWORD''';`,
+		},
+		{
+			name: "placeholder inside a comment is kept",
+			sql:  "-- PASSWORD :'APP_USER_PASSWORD'\nSELECT 1;",
+			want: "-- PASSWORD :'APP_USER_PASSWORD'\nSELECT 1;",
+		},
+		{
+			name: "cast is not a placeholder",
+			sql:  `SELECT 'x'::text, x::'APP_USER_PASSWORD';`,
+			want: `SELECT 'x'::text, x::'APP_USER_PASSWORD';`,
+		},
+		{
+			name: "no placeholders",
+			sql:  `SELECT 1;`,
+			want: `SELECT 1;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			got, err := sqlutil.ExpandEnvPlaceholders(tt.sql, lookupFrom(env))
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.Equals, tt.want)
+			c.Assert(sqlutil.HasEnvPlaceholders(tt.sql), qt.Equals, tt.sql != tt.want)
+		})
+	}
+}
+
+func TestExpandEnvPlaceholders_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unset variable",
+			env:     map[string]string{},
+			wantErr: `environment variable APP_USER_PASSWORD referenced by placeholder :'APP_USER_PASSWORD' is not set`,
+		},
+		{
+			name:    "NUL byte",
+			env:     map[string]string{"APP_USER_PASSWORD": "bad\x00value"},
+			wantErr: `environment variable APP_USER_PASSWORD referenced by placeholder :'APP_USER_PASSWORD' contains a NUL byte`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, err := sqlutil.ExpandEnvPlaceholders(`ALTER ROLE "app_user" WITH PASSWORD :'APP_USER_PASSWORD';`, lookupFrom(tt.env))
+
+			c.Assert(err, qt.ErrorMatches, tt.wantErr)
+			c.Assert(err.Error(), qt.Not(qt.Contains), "bad")
+		})
+	}
+}
+
+func TestIsEnvPlaceholderName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "APP_USER_PASSWORD", want: true},
+		{name: "_x1", want: true},
+		{name: "1X", want: false},
+		{name: "APP-PASSWORD", want: false},
+		{name: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(sqlutil.IsEnvPlaceholderName(tt.name), qt.Equals, tt.want)
+			c.Assert(sqlutil.EnvPlaceholder("X"), qt.Equals, `:'X'`)
+		})
+	}
+}
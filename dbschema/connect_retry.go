@@ -0,0 +1,99 @@
+package dbschema
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ConnectOptions controls how ConnectToDatabaseWithOptions connects.
+type ConnectOptions struct {
+	// Retries is how many more times the initial ping is tried when it fails
+	// with a transient error: the connection is refused or reset, the server
+	// has too many connections, or it is still starting up. Other errors fail
+	// at once. The caller's context bounds all attempts and waits together.
+	Retries int
+	// RetryBackoff is the wait before the first retry. It doubles after every
+	// attempt. Zero uses 100ms.
+	RetryBackoff time.Duration
+}
+
+// defaultConnectRetryBackoff is the wait before the first retry when
+// ConnectOptions leaves RetryBackoff unset, so retries never spin against a
+// server that is still starting up.
+const defaultConnectRetryBackoff = 100 * time.Millisecond
+
+// pingWithRetry pings db, retrying transient failures as configured by opts.
+// It returns the last ping error together with the number of attempts made.
+func pingWithRetry(ctx context.Context, db *sql.DB, opts ConnectOptions) (int, error) {
+	wait := opts.RetryBackoff
+	if wait <= 0 {
+		wait = defaultConnectRetryBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil || attempt > opts.Retries || !isTransientConnectError(err) {
+			return attempt, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+// transientSQLStates are the PostgreSQL error codes a fresh server reports
+// while it cannot yet accept the connection.
+var transientSQLStates = []string{
+	"53300", // too_many_connections
+	"57P03", // cannot_connect_now: the database system is starting up
+}
+
+// transientMySQLErrors are the MySQL and MariaDB error numbers for the same
+// situation.
+var transientMySQLErrors = []uint16{
+	1040, // ER_CON_COUNT_ERROR: too many connections
+	1203, // ER_TOO_MANY_USER_CONNECTIONS
+}
+
+// transientMessages catches drivers that report the same conditions without
+// a typed error.
+var transientMessages = []string{
+	"connection refused",
+	"connection reset",
+	"too many connections",
+	"the database system is starting up",
+}
+
+// isTransientConnectError reports whether a failed ping is worth retrying.
+func isTransientConnectError(err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return slices.Contains(transientSQLStates, pgErr.Code)
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return slices.Contains(transientMySQLErrors, mysqlErr.Number)
+	}
+	message := strings.ToLower(err.Error())
+	for _, transient := range transientMessages {
+		if strings.Contains(message, transient) {
+			return true
+		}
+	}
+	return false
+}
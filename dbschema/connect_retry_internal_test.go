@@ -0,0 +1,41 @@
+package dbschema
+
+// White-box testing required: the transient error classification decides
+// which driver errors are retried, and real servers cannot be made to return
+// each of them on demand.
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsTransientConnectError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "connection refused", err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), want: true},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "postgres too many connections", err: &pgconn.PgError{Code: "53300"}, want: true},
+		{name: "postgres starting up", err: &pgconn.PgError{Code: "57P03"}, want: true},
+		{name: "postgres auth failure", err: &pgconn.PgError{Code: "28P01"}, want: false},
+		{name: "mysql too many connections", err: &mysql.MySQLError{Number: 1040}, want: true},
+		{name: "mysql access denied", err: &mysql.MySQLError{Number: 1045}, want: false},
+		{name: "untyped refused message", err: errors.New("unable to open tcp connection: connection refused"), want: true},
+		{name: "unknown database", err: errors.New(`database "missing" does not exist`), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(isTransientConnectError(tt.err), qt.Equals, tt.want)
+		})
+	}
+}
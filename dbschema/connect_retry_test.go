@@ -0,0 +1,64 @@
+package dbschema_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+)
+
+// refusedPostgresURL returns a PostgreSQL URL for a local port nothing
+// listens on, so every connection attempt is refused at once.
+func refusedPostgresURL(c *qt.C) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, qt.IsNil)
+	addr := ln.Addr().String()
+	c.Assert(ln.Close(), qt.IsNil)
+	return fmt.Sprintf("postgres://user:pass@%s/testdb?sslmode=disable", addr)
+}
+
+func TestConnectToDatabase_RetriesRefusedConnections(t *testing.T) {
+	c := qt.New(t)
+
+	conn, err := dbschema.ConnectToDatabaseWithOptions(context.Background(), refusedPostgresURL(c),
+		dbschema.ConnectOptions{Retries: 2, RetryBackoff: time.Millisecond})
+
+	c.Assert(conn, qt.IsNil)
+	c.Assert(err, qt.ErrorMatches, `failed to ping database after 3 attempts: .*`)
+}
+
+func TestConnectToDatabase_ZeroBackoffWaitsBetweenRetries(t *testing.T) {
+	c := qt.New(t)
+
+	start := time.Now()
+	conn, err := dbschema.ConnectToDatabaseWithOptions(context.Background(), refusedPostgresURL(c),
+		dbschema.ConnectOptions{Retries: 2})
+	elapsed := time.Since(start)
+
+	c.Assert(conn, qt.IsNil)
+	c.Assert(err, qt.ErrorMatches, `failed to ping database after 3 attempts: .*`)
+	c.Assert(elapsed >= 300*time.Millisecond, qt.IsTrue,
+		qt.Commentf("two retries with zero backoff took %s, want the 100ms and 200ms waits", elapsed))
+}
+
+func TestConnectToDatabase_RetriesStopAtContextDeadline(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := dbschema.ConnectToDatabaseWithOptions(ctx, refusedPostgresURL(c),
+		dbschema.ConnectOptions{Retries: 5, RetryBackoff: time.Hour})
+	elapsed := time.Since(start)
+
+	c.Assert(conn, qt.IsNil)
+	c.Assert(err, qt.ErrorIs, context.DeadlineExceeded)
+	c.Assert(err, qt.ErrorMatches, `(?s)failed to ping database: .*connection refused.*`)
+	c.Assert(elapsed < 5*time.Second, qt.IsTrue,
+		qt.Commentf("ConnectToDatabase took %s with a 200ms deadline, want <5s", elapsed))
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	_ "github.com/jackc/pgx/v5/stdlib"  // PostgreSQL driver
@@ -14,6 +15,7 @@ import (
 
 	"github.com/stokaro/ptah/core/platform"
 	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/internal/dbschema/clickhouse"
 	"github.com/stokaro/ptah/internal/dbschema/mssql"
@@ -22,6 +24,47 @@ import (
 	"github.com/stokaro/ptah/internal/dbschema/sqlite"
 )
 
+// ErrUnsupportedDialect is matched by the error ConnectToDatabase returns
+// when the URL scheme names no database it can connect to. It is the same
+// sentinel as ptaherr.ErrUnsupportedDialect, so callers may match either.
+var ErrUnsupportedDialect = ptaherr.ErrUnsupportedDialect
+
+// UnsupportedDialectError reports a database URL scheme ConnectToDatabase
+// cannot classify. It unwraps to ErrUnsupportedDialect.
+type UnsupportedDialectError struct {
+	Scheme    string
+	Supported []string
+}
+
+func (e *UnsupportedDialectError) Error() string {
+	return fmt.Sprintf("%s %q (supported: %s)", ErrUnsupportedDialect, e.Scheme, strings.Join(e.Supported, ", "))
+}
+
+func (e *UnsupportedDialectError) Unwrap() error {
+	return ErrUnsupportedDialect
+}
+
+// supportedDialects lists, sorted, the canonical dialects
+// databaseDriverConfig can open. Keep it in sync with that switch.
+var supportedDialects = []string{
+	platform.ClickHouse,
+	platform.CockroachDB,
+	platform.MariaDB,
+	platform.MySQL,
+	platform.Postgres,
+	platform.Spanner,
+	platform.SQLite,
+	platform.SQLServer,
+	platform.YugabyteDB,
+}
+
+// SupportedDialects returns the canonical dialect names ConnectToDatabase
+// accepts as URL schemes, sorted. Aliases such as postgresql or mssql
+// normalize to one of these.
+func SupportedDialects() []string {
+	return slices.Clone(supportedDialects)
+}
+
 // ConnectToDatabase creates a database connection from a URL.
 //
 // The provided context governs the initial Ping used to verify the connection
@@ -31,6 +74,13 @@ import (
 // affect the lifetime of the returned *DatabaseConnection; callers are
 // responsible for closing it.
 func ConnectToDatabase(ctx context.Context, dbURL string) (*DatabaseConnection, error) {
+	return ConnectToDatabaseWithOptions(ctx, dbURL, ConnectOptions{})
+}
+
+// ConnectToDatabaseWithOptions is ConnectToDatabase with connect options.
+// ConnectOptions.Retries retries transient ping failures, such as a server
+// that is still starting up, within the context deadline.
+func ConnectToDatabaseWithOptions(ctx context.Context, dbURL string, opts ConnectOptions) (*DatabaseConnection, error) {
 	// Handle MySQL URLs specially since they have a different format
 	var parsedURL *url.URL
 	var err error
@@ -56,11 +106,10 @@ func ConnectToDatabase(ctx context.Context, dbURL string) (*DatabaseConnection,
 	// Determine the dialect
 	rawDialect := strings.ToLower(parsedURL.Scheme)
 	dialect := platform.NormalizeDialect(rawDialect)
-	if dialect == "" {
-		return nil, fmt.Errorf("unsupported database dialect: %s", rawDialect)
-	}
-
 	dialectProtocol, connectionString := databaseDriverConfig(dialect, dbURL)
+	if dialectProtocol == "" {
+		return nil, &UnsupportedDialectError{Scheme: rawDialect, Supported: SupportedDialects()}
+	}
 
 	db, err := sql.Open(dialectProtocol, connectionString)
 	if err != nil {
@@ -72,8 +121,11 @@ func ConnectToDatabase(ctx context.Context, dbURL string) (*DatabaseConnection,
 
 	// Test the connection — honor the caller-supplied context so a stuck or
 	// slow host cannot block ConnectToDatabase indefinitely.
-	if err := db.PingContext(ctx); err != nil {
+	if attempts, err := pingWithRetry(ctx, db, opts); err != nil {
 		_ = db.Close()
+		if attempts > 1 {
+			return nil, fmt.Errorf("failed to ping database after %d attempts: %w", attempts, err)
+		}
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -101,7 +153,7 @@ func ConnectToDatabase(ctx context.Context, dbURL string) (*DatabaseConnection,
 		reader = postgres.NewPostgreSQLReaderWithCapabilities(db, info.Schema, info.Capabilities)
 		writer = postgres.NewPostgreSQLWriter(db, info.Schema)
 	case "mysql":
-		reader = mysql.NewMySQLReader(db, info.Schema)
+		reader = mysql.NewMySQLReaderWithCapabilities(db, info.Schema, info.Capabilities)
 		writer = mysql.NewMySQLWriter(db, info.Schema)
 	case "clickhouse":
 		reader = clickhouse.NewClickHouseReader(db, info.Schema)
@@ -117,12 +169,7 @@ func ConnectToDatabase(ctx context.Context, dbURL string) (*DatabaseConnection,
 		return nil, fmt.Errorf("no schema reader available for dialect: %s", dialect)
 	}
 
-	return &DatabaseConnection{
-		db:     db,
-		info:   info,
-		reader: reader,
-		writer: writer,
-	}, nil
+	return NewConnection(db, info, reader, writer), nil
 }
 
 func databaseDriverConfig(dialect, dbURL string) (driverName, dataSourceName string) {
@@ -151,19 +198,86 @@ type DatabaseConnection struct {
 	executor types.SchemaExecutor
 }
 
+// SchemaReader is the schema-introspection surface of a connection: its
+// metadata and its schema reader. Code that only reads schemas should accept
+// it instead of *DatabaseConnection so tests can substitute a fake.
+type SchemaReader interface {
+	Info() types.DBInfo
+	Reader() types.SchemaReader
+}
+
+// SchemaWriter is the schema-change surface of a connection.
+type SchemaWriter interface {
+	Writer() types.SchemaExecutor
+	SchemaWriter() types.SchemaWriter
+}
+
+// Querier runs ad hoc SQL on a connection.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+var (
+	_ SchemaReader = (*DatabaseConnection)(nil)
+	_ SchemaWriter = (*DatabaseConnection)(nil)
+	_ Querier      = (*DatabaseConnection)(nil)
+)
+
+// NewConnection assembles a connection from an open database handle, its
+// metadata and the schema reader and writer to use. ConnectToDatabase is the
+// usual way to obtain a connection; NewConnection serves callers that wrap an
+// existing handle or substitute their own reader and writer, such as the
+// dbschematest fake. Closing the connection closes db.
+func NewConnection(db *sql.DB, info types.DBInfo, reader types.SchemaReader, writer types.SchemaWriter) *DatabaseConnection {
+	return &DatabaseConnection{
+		db:     db,
+		info:   info,
+		reader: reader,
+		writer: writer,
+	}
+}
+
 type schemaScopedReader interface {
 	SetSchemas([]string)
 }
 
+type systemRelationsReader interface {
+	SetIncludeSystemRelations(bool)
+}
+
+// ReadOptions controls what ReadSchemaWithOptions introspects.
+type ReadOptions struct {
+	// Schemas is the schema allow-list applied when the dialect reader
+	// supports schema scoping.
+	Schemas []string
+
+	// IncludeSystemRelations keeps temporary tables and relations in system
+	// schemas (PostgreSQL pg_catalog, pg_toast and pg_temp_*; MySQL mysql,
+	// performance_schema and sys) that readers skip by default.
+	IncludeSystemRelations bool
+}
+
 // ReadSchemaWithSchemas reads a database schema, applying a schema allow-list
 // when the underlying dialect reader supports schema scoping.
-func ReadSchemaWithSchemas(conn *DatabaseConnection, schemas []string) (*types.DBSchema, error) {
+func ReadSchemaWithSchemas(conn SchemaReader, schemas []string) (*types.DBSchema, error) {
+	return ReadSchemaWithOptions(conn, ReadOptions{Schemas: schemas})
+}
+
+// ReadSchemaWithOptions reads a database schema with the given options. The
+// reader is restored to its defaults afterwards.
+func ReadSchemaWithOptions(conn SchemaReader, opts ReadOptions) (*types.DBSchema, error) {
 	reader := conn.Reader()
 	scoped, ok := reader.(schemaScopedReader)
 	if ok {
-		scoped.SetSchemas(schemas)
+		scoped.SetSchemas(opts.Schemas)
 		defer scoped.SetSchemas(nil)
 	}
+	if system, ok := reader.(systemRelationsReader); ok && opts.IncludeSystemRelations {
+		system.SetIncludeSystemRelations(true)
+		defer system.SetIncludeSystemRelations(false)
+	}
 	return reader.ReadSchema()
 }
 
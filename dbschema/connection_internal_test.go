@@ -431,3 +431,13 @@ func TestRemovePostgresPoolParams_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestSupportedDialectsHaveDrivers(t *testing.T) {
+	for _, dialect := range SupportedDialects() {
+		t.Run(dialect, func(t *testing.T) {
+			c := qt.New(t)
+			driverName, _ := databaseDriverConfig(dialect, dialect+"://localhost/db")
+			c.Assert(driverName, qt.Not(qt.Equals), "")
+		})
+	}
+}
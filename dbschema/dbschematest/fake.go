@@ -0,0 +1,152 @@
+// Package dbschematest provides a fake dbschema connection for unit tests
+// that should not need a real database.
+package dbschematest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/dbschema/dbtest"
+)
+
+// FakeConnection is an in-memory connection that serves a fixed schema and
+// records the SQL executed through it instead of running it.
+//
+// It embeds a *dbschema.DatabaseConnection, so it satisfies
+// dbschema.SchemaReader, dbschema.SchemaWriter and dbschema.Querier, and
+// fake.DatabaseConnection can be passed where the concrete type is required.
+// Queries return no rows, so QueryRowContext(...).Scan reports sql.ErrNoRows.
+type FakeConnection struct {
+	*dbschema.DatabaseConnection
+	log *sqlLog
+}
+
+// NewFakeConnection returns a fake connection for dialect whose reader serves
+// schema. A nil schema reads as an empty one. Statements executed through the
+// writer, its transactions and ExecContext are recorded in order; dry-run
+// writes are not. The connection is closed when t finishes.
+func NewFakeConnection(t testing.TB, dialect string, schema *types.DBSchema) *FakeConnection {
+	t.Helper()
+	if schema == nil {
+		schema = &types.DBSchema{}
+	}
+	log := &sqlLog{}
+	db := dbtest.OpenWithExec(t,
+		func(string, []driver.NamedValue) (dbtest.QueryResult, error) {
+			return dbtest.QueryResult{}, nil
+		},
+		func(query string, _ []driver.NamedValue) (driver.Result, error) {
+			log.record(query)
+			return driver.RowsAffected(0), nil
+		},
+	)
+	dialect = platform.NormalizeDialect(dialect)
+	info := types.DBInfo{
+		Dialect:      dialect,
+		Capabilities: capability.ForDialect(dialect),
+	}
+	conn := dbschema.NewConnection(db.SQL, info, &fakeReader{schema: schema}, &fakeWriter{log: log})
+	return &FakeConnection{DatabaseConnection: conn, log: log}
+}
+
+// ExecutedSQL returns the recorded statements in execution order.
+func (f *FakeConnection) ExecutedSQL() []string {
+	return f.log.statements()
+}
+
+type sqlLog struct {
+	mu       sync.Mutex
+	executed []string
+}
+
+func (l *sqlLog) record(query string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.executed = append(l.executed, query)
+}
+
+func (l *sqlLog) statements() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.executed)
+}
+
+type fakeReader struct {
+	schema *types.DBSchema
+}
+
+// ReadSchema returns a shallow copy, so callers that reassign top-level
+// fields do not change what later reads see.
+func (r *fakeReader) ReadSchema() (*types.DBSchema, error) {
+	schema := *r.schema
+	return &schema, nil
+}
+
+type fakeWriter struct {
+	log    *sqlLog
+	mu     sync.Mutex
+	dryRun bool
+}
+
+func (w *fakeWriter) ExecuteSQL(_ context.Context, query string, _ ...any) error {
+	if !w.IsDryRun() {
+		w.log.record(query)
+	}
+	return nil
+}
+
+func (w *fakeWriter) IsDryRun() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dryRun
+}
+
+func (w *fakeWriter) SetDryRun(dryRun bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.dryRun = dryRun
+}
+
+func (w *fakeWriter) DropAllTables() error {
+	return nil
+}
+
+func (w *fakeWriter) BeginTransaction(ctx context.Context) (types.SchemaTransaction, error) {
+	return w.BeginTransactionWithOptions(ctx, nil)
+}
+
+func (w *fakeWriter) BeginTransactionWithOptions(context.Context, *sql.TxOptions) (types.SchemaTransaction, error) {
+	return &fakeTransaction{writer: w, dryRun: w.IsDryRun()}, nil
+}
+
+type fakeTransaction struct {
+	writer *fakeWriter
+	dryRun bool
+}
+
+func (tx *fakeTransaction) ExecuteSQL(_ context.Context, query string, _ ...any) error {
+	if !tx.dryRun {
+		tx.writer.log.record(query)
+	}
+	return nil
+}
+
+func (tx *fakeTransaction) IsDryRun() bool {
+	return tx.dryRun
+}
+
+func (tx *fakeTransaction) Commit() error {
+	return nil
+}
+
+func (tx *fakeTransaction) Rollback() error {
+	return nil
+}
@@ -0,0 +1,73 @@
+package dbschematest_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/dbschema/types"
+)
+
+func TestFakeConnection_ServesSchema(t *testing.T) {
+	c := qt.New(t)
+	schema := &types.DBSchema{Tables: []types.DBTable{{Name: "users", Type: "TABLE"}}}
+	fake := dbschematest.NewFakeConnection(t, "postgresql", schema)
+
+	var reader dbschema.SchemaReader = fake
+	got, err := dbschema.ReadSchemaWithSchemas(reader, []string{"public"})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(got.Tables, qt.DeepEquals, schema.Tables)
+	c.Assert(reader.Info().Dialect, qt.Equals, platform.Postgres)
+	c.Assert(reader.Info().Capabilities, qt.DeepEquals, capability.ForDialect(platform.Postgres))
+}
+
+func TestFakeConnection_NilSchemaReadsEmpty(t *testing.T) {
+	c := qt.New(t)
+	fake := dbschematest.NewFakeConnection(t, platform.SQLite, nil)
+
+	got, err := fake.Reader().ReadSchema()
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.DeepEquals, &types.DBSchema{})
+}
+
+func TestFakeConnection_RecordsExecutedSQL(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	fake := dbschematest.NewFakeConnection(t, platform.MySQL, nil)
+
+	var writer dbschema.SchemaWriter = fake
+	var querier dbschema.Querier = fake
+	c.Assert(writer.Writer().ExecuteSQL(ctx, "CREATE TABLE a (id INT)"), qt.IsNil)
+	tx, err := writer.SchemaWriter().BeginTransaction(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tx.ExecuteSQL(ctx, "CREATE TABLE b (id INT)"), qt.IsNil)
+	c.Assert(tx.Commit(), qt.IsNil)
+	_, err = querier.ExecContext(ctx, "DROP TABLE a")
+	c.Assert(err, qt.IsNil)
+	writer.SchemaWriter().SetDryRun(true)
+	c.Assert(writer.Writer().ExecuteSQL(ctx, "DROP TABLE b"), qt.IsNil)
+
+	c.Assert(fake.ExecutedSQL(), qt.DeepEquals, []string{
+		"CREATE TABLE a (id INT)",
+		"CREATE TABLE b (id INT)",
+		"DROP TABLE a",
+	})
+}
+
+func TestFakeConnection_QueriesReturnNoRows(t *testing.T) {
+	c := qt.New(t)
+	fake := dbschematest.NewFakeConnection(t, platform.Postgres, nil)
+
+	var count int
+	err := fake.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM users").Scan(&count)
+
+	c.Assert(err, qt.ErrorIs, sql.ErrNoRows)
+}
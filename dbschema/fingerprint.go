@@ -0,0 +1,25 @@
+package dbschema
+
+import (
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/convert/dbschematogo"
+)
+
+// Fingerprint returns a stable SHA-256 hex digest of an introspected schema.
+// The schema is first converted into the goschema form that introspection
+// and down migrations use, and then hashed with goschema.Fingerprint, so the
+// result can be stored to detect drift between reads and compared with the
+// fingerprint of a declared Go schema.
+//
+// Equal fingerprints mean the two schemas have the same normalized contents;
+// a collision is astronomically unlikely, but the schema diff stays
+// authoritative. A declared schema that spells types or defaults differently
+// from what the database reports fingerprints differently even when the diff
+// is empty, so a mismatch only means the full comparison has to run.
+func Fingerprint(s *types.DBSchema) string {
+	if s == nil {
+		return goschema.Fingerprint(nil)
+	}
+	return goschema.Fingerprint(dbschematogo.ConvertDBSchemaToGoSchema(s))
+}
@@ -0,0 +1,66 @@
+package dbschema_test
+
+import (
+	"slices"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/convert/dbschematogo"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func fingerprintFixture() *types.DBSchema {
+	emailLength := 255
+	return &types.DBSchema{
+		Tables: []types.DBTable{
+			{
+				Name: "users", Schema: "public", Type: "BASE TABLE",
+				Columns: []types.DBColumn{
+					{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+					{Name: "email", DataType: "character varying", UDTName: "varchar", IsNullable: "NO", CharacterMaxLength: &emailLength},
+				},
+			},
+			{
+				Name: "posts", Schema: "public", Type: "BASE TABLE",
+				Columns: []types.DBColumn{
+					{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+				},
+			},
+		},
+		Indexes: []types.DBIndex{
+			{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}, IsUnique: true},
+			{Name: "idx_posts_id", TableName: "posts", Columns: []string{"id"}},
+		},
+	}
+}
+
+func TestFingerprintIgnoresIntrospectionOrder(t *testing.T) {
+	c := qt.New(t)
+	reordered := fingerprintFixture()
+	slices.Reverse(reordered.Tables)
+	slices.Reverse(reordered.Indexes)
+
+	c.Assert(dbschema.Fingerprint(reordered), qt.Equals, dbschema.Fingerprint(fingerprintFixture()))
+}
+
+func TestFingerprintDetectsDrift(t *testing.T) {
+	c := qt.New(t)
+	drifted := fingerprintFixture()
+	drifted.Tables[0].Columns[1].IsNullable = "YES"
+
+	c.Assert(dbschema.Fingerprint(drifted), qt.Not(qt.Equals), dbschema.Fingerprint(fingerprintFixture()))
+}
+
+func TestEqualFingerprintsMeanEmptyDiff(t *testing.T) {
+	c := qt.New(t)
+	live := fingerprintFixture()
+	generated := dbschematogo.ConvertDBSchemaToGoSchema(fingerprintFixture())
+
+	c.Assert(goschema.Fingerprint(generated), qt.Equals, dbschema.Fingerprint(live))
+	c.Assert(schemadiff.CompareWithDialect(generated, live, platform.Postgres).HasChanges(), qt.IsFalse)
+}
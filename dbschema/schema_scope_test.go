@@ -11,6 +11,11 @@ import (
 
 type scopedReaderStub struct {
 	scopes [][]string
+	system []bool
+}
+
+func (r *scopedReaderStub) SetIncludeSystemRelations(include bool) {
+	r.system = append(r.system, include)
 }
 
 func (r *scopedReaderStub) SetSchemas(schemas []string) {
@@ -36,3 +41,28 @@ func TestReadSchemaWithSchemas_ResetsScopedReader(t *testing.T) {
 		nil,
 	})
 }
+
+func TestReadSchemaWithOptions_IncludesAndResetsSystemRelations(t *testing.T) {
+	c := qt.New(t)
+
+	reader := &scopedReaderStub{}
+	conn := &DatabaseConnection{reader: reader}
+
+	_, err := ReadSchemaWithOptions(conn, ReadOptions{Schemas: []string{"public"}, IncludeSystemRelations: true})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(reader.system, qt.DeepEquals, []bool{true, false})
+	c.Assert(reader.scopes, qt.DeepEquals, [][]string{{"public"}, nil})
+}
+
+func TestReadSchemaWithSchemas_KeepsSystemRelationsExcluded(t *testing.T) {
+	c := qt.New(t)
+
+	reader := &scopedReaderStub{}
+	conn := &DatabaseConnection{reader: reader}
+
+	_, err := ReadSchemaWithSchemas(conn, nil)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(reader.system, qt.IsNil)
+}
@@ -0,0 +1,67 @@
+//go:build integration
+
+package dbschema_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/stokaro/ptah/dbschema"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+)
+
+func TestReadSchemaSkipsOtherSessionTempTables_Integration(t *testing.T) {
+	dbURL := requirePostgresURL(t)
+	c := qt.New(t)
+	ctx := context.Background()
+
+	conn, err := dbschema.ConnectToDatabase(ctx, dbURL)
+	c.Assert(err, qt.IsNil)
+	defer dbschema.CloseAndWarn(conn)
+
+	// A second session owns the temporary table, as a concurrent application
+	// connection would while ptah introspects the database.
+	other, err := sql.Open("pgx", dbURL)
+	c.Assert(err, qt.IsNil)
+	defer other.Close()
+	session, err := other.Conn(ctx)
+	c.Assert(err, qt.IsNil)
+	defer session.Close()
+	_, err = session.ExecContext(ctx, "CREATE TEMPORARY TABLE ptah_session_scratch (id integer)")
+	c.Assert(err, qt.IsNil)
+	var tempSchema string
+	c.Assert(session.QueryRowContext(ctx, "SELECT nspname FROM pg_namespace WHERE oid = pg_my_temp_schema()").Scan(&tempSchema), qt.IsNil)
+
+	schema, err := dbschema.ReadSchemaWithSchemas(conn, []string{"public", tempSchema})
+	c.Assert(err, qt.IsNil)
+	c.Assert(integrationTableNames(schema), qt.Not(qt.Contains), "ptah_session_scratch")
+
+	schema, err = dbschema.ReadSchemaWithOptions(conn, dbschema.ReadOptions{
+		Schemas:                []string{"public", tempSchema},
+		IncludeSystemRelations: true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(integrationTableNames(schema), qt.Contains, "ptah_session_scratch")
+}
+
+func requirePostgresURL(t *testing.T) string {
+	t.Helper()
+	dbURL := os.Getenv("POSTGRES_URL")
+	if dbURL == "" {
+		t.Skip("set POSTGRES_URL to run PostgreSQL schema integration tests")
+	}
+	return dbURL
+}
+
+func integrationTableNames(schema *dbschematypes.DBSchema) []string {
+	names := make([]string, 0, len(schema.Tables))
+	for _, table := range schema.Tables {
+		names = append(names, table.Name)
+	}
+	return names
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"database/sql"
 	"strings"
 
 	"github.com/stokaro/ptah/core/platform/capability"
@@ -42,11 +43,19 @@ type DBTable struct {
 	Schema        string     `json:"schema,omitempty"`
 	Type          string     `json:"type"` // TABLE, VIEW, etc.
 	Comment       string     `json:"comment"`
+	Owner         string     `json:"owner,omitempty"` // Owning role (PostgreSQL)
 	Columns       []DBColumn `json:"columns"`
 	EstimatedRows int64      `json:"estimated_rows,omitempty"` // Best-effort planner estimate from database statistics
 	RLSEnabled    bool       `json:"rls_enabled"`              // Whether RLS is enabled on this table (PostgreSQL)
 	Strict        bool       `json:"strict,omitempty"`         // SQLite STRICT table option
 	WithoutRowID  bool       `json:"without_rowid,omitempty"`  // SQLite WITHOUT ROWID table option
+	// AutoIncrement is the next MySQL/MariaDB AUTO_INCREMENT counter value
+	// from information_schema.TABLES. Zero when the table has no counter.
+	AutoIncrement int64 `json:"auto_increment,omitempty"`
+	// Inherits lists the PostgreSQL parent tables of an INHERITS child in
+	// declaration order. Parents in the child's schema are unqualified.
+	// Declarative partitions are not reported here.
+	Inherits []string `json:"inherits,omitempty"`
 }
 
 // QualifiedName returns schema.table when Schema is set, or Name otherwise.
@@ -93,6 +102,25 @@ type DBColumn struct {
 	// GeneratedKind names the generated-column kind, for example STORED,
 	// VIRTUAL, MATERIALIZED, ALIAS, or EPHEMERAL. Empty for plain columns.
 	GeneratedKind string `json:"generated_kind,omitempty"`
+	// Inherited reports a PostgreSQL column that exists only because the
+	// table inherits it from a parent (pg_attribute.attislocal is false).
+	// Such columns belong to the parent and are not compared on the child.
+	Inherited bool `json:"inherited,omitempty"`
+	// IdentityGeneration is ALWAYS or BY_DEFAULT for a PostgreSQL identity
+	// column and empty otherwise. Identity columns also set IsAutoIncrement.
+	IdentityGeneration string `json:"identity_generation,omitempty"`
+	// IdentityStart and IdentityIncrement are the START WITH and INCREMENT BY
+	// options of the sequence owned by a PostgreSQL identity column. Empty
+	// for other columns.
+	IdentityStart     string `json:"identity_start,omitempty"`
+	IdentityIncrement string `json:"identity_increment,omitempty"`
+	// Comment holds the column comment. Only the PostgreSQL reader reports
+	// it today (col_description); other readers leave it empty.
+	Comment string `json:"comment,omitempty"`
+	// UpdateExpression holds the MySQL/MariaDB ON UPDATE expression, such as
+	// CURRENT_TIMESTAMP(6), read from information_schema.columns.EXTRA.
+	// Empty for other dialects.
+	UpdateExpression string `json:"update_expression,omitempty"`
 }
 
 // DBEnum represents a database enum type (PostgreSQL)
@@ -143,10 +171,10 @@ func (r DBRange) QualifiedName() string { return QualifyTableName(r.Schema, r.Na
 // DBIndex represents a database index.
 //
 // Most fields are dialect-neutral. The Type/Expression/Granularity trio is
-// populated only by the ClickHouse reader for data-skipping indexes; other
-// readers leave them at their zero values so the diff layer does not start
-// emitting spurious type/granularity changes for PostgreSQL or MySQL
-// indexes.
+// populated by the ClickHouse reader for data-skipping indexes, and the MySQL
+// and MariaDB readers set Type for non-BTREE indexes; other readers leave
+// them at their zero values so the diff layer does not start emitting
+// spurious type/granularity changes for PostgreSQL indexes.
 type DBIndex struct {
 	Name       string   `json:"name"`
 	TableName  string   `json:"table_name"`
@@ -161,11 +189,22 @@ type DBIndex struct {
 	// NullsDistinct carries PostgreSQL UNIQUE INDEX NULLS [NOT] DISTINCT
 	// state. Nil means the clause was not present in the definition.
 	NullsDistinct *bool `json:"nulls_distinct,omitempty"`
-
-	// Type is the ClickHouse data-skipping-index type. One of
-	// "minmax" / "set(N)" / "bloom_filter" / "bloom_filter(p)" /
-	// "tokenbf_v1(...)" / "ngrambf_v1(...)" etc. Empty on non-ClickHouse
-	// readers.
+	// StorageParams carries PostgreSQL index storage parameters read from
+	// pg_class.reloptions, for example fillfactor. Nil when none are set.
+	StorageParams map[string]string `json:"storage_params,omitempty"`
+	// Invisible reports a MySQL INVISIBLE (MariaDB IGNORED) index that the
+	// optimizer does not use.
+	Invisible bool `json:"invisible,omitempty"`
+	// OperatorClasses carries the PostgreSQL operator class of each key
+	// column, in key order. Nil on other dialects.
+	OperatorClasses []DBIndexOperatorClass `json:"operator_classes,omitempty"`
+
+	// Type is the index type when it is not the dialect default. The
+	// ClickHouse reader reports the data-skipping-index type ("minmax" /
+	// "set(N)" / "bloom_filter" / "bloom_filter(p)" / "tokenbf_v1(...)" /
+	// "ngrambf_v1(...)" etc.). The MySQL and MariaDB readers report
+	// "HASH", "FULLTEXT" or "SPATIAL" and leave BTREE indexes empty. Other
+	// readers leave it empty.
 	Type string `json:"type,omitempty"`
 	// Expression is the full ClickHouse skipping-index expression
 	// (column reference, function call, tuple, etc.). The reader also writes
@@ -178,6 +217,14 @@ type DBIndex struct {
 	Granularity int `json:"granularity,omitempty"`
 }
 
+// DBIndexOperatorClass describes the operator class of one index key column.
+type DBIndexOperatorClass struct {
+	Name string `json:"name"`
+	// Default reports whether Name is the default operator class for the
+	// column type and access method, which pg_get_indexdef omits.
+	Default bool `json:"default,omitempty"`
+}
+
 // QualifiedTableName returns schema.table when Schema is set, or TableName otherwise.
 func (i DBIndex) QualifiedTableName() string {
 	return QualifyTableName(i.Schema, i.TableName)
@@ -319,6 +366,14 @@ type SchemaWriter interface {
 	SetDryRun(dryRun bool)
 }
 
+// SchemaTransactionBeginner is implemented by schema writers that can begin a
+// transaction with database/sql options such as an isolation level.
+// BeginTransaction is equivalent to BeginTransactionWithOptions with nil
+// options.
+type SchemaTransactionBeginner interface {
+	BeginTransactionWithOptions(ctx context.Context, opts *sql.TxOptions) (SchemaTransaction, error)
+}
+
 // SchemaTransaction executes schema changes inside a database transaction.
 //
 // It deliberately owns transaction lifecycle instead of storing the active
@@ -333,14 +388,36 @@ type SchemaTransaction interface {
 
 // DBFunction represents a PostgreSQL custom function read from the database
 type DBFunction struct {
-	Name       string `json:"name"`       // Function name
-	Parameters string `json:"parameters"` // Function parameters (e.g., "tenant_id_param TEXT")
-	Returns    string `json:"returns"`    // Return type (e.g., "VOID", "TEXT")
-	Language   string `json:"language"`   // Function language (e.g., "plpgsql", "sql")
-	Security   string `json:"security"`   // Security context (e.g., "DEFINER", "INVOKER")
-	Volatility string `json:"volatility"` // Function volatility (e.g., "STABLE", "IMMUTABLE", "VOLATILE")
-	Body       string `json:"body"`       // Function body/implementation
-	Comment    string `json:"comment"`    // Function comment/description
+	Name       string `json:"name"`             // Function name
+	Schema     string `json:"schema,omitempty"` // Schema where the function is defined, empty for the default schema
+	Parameters string `json:"parameters"`       // Function parameters (e.g., "tenant_id_param TEXT")
+	Returns    string `json:"returns"`          // Return type (e.g., "VOID", "TEXT")
+	Language   string `json:"language"`         // Function language (e.g., "plpgsql", "sql")
+	Security   string `json:"security"`         // Security context (e.g., "DEFINER", "INVOKER")
+	Volatility string `json:"volatility"`       // Function volatility (e.g., "STABLE", "IMMUTABLE", "VOLATILE")
+	Body       string `json:"body"`             // Function body/implementation
+	Comment    string `json:"comment"`          // Function comment/description
+
+	// Triggers lists the triggers that execute this function. PostgreSQL
+	// refuses to drop the function while any of them exists.
+	Triggers []DBFunctionTrigger `json:"triggers,omitempty"`
+}
+
+// QualifiedName returns schema.function when Schema is set, or Name otherwise.
+func (f DBFunction) QualifiedName() string {
+	return QualifyTableName(f.Schema, f.Name)
+}
+
+// DBFunctionTrigger identifies a trigger that executes a DBFunction.
+type DBFunctionTrigger struct {
+	Name   string `json:"name"`             // Trigger name
+	Schema string `json:"schema,omitempty"` // Schema of the trigger's table, empty for the default schema
+	Table  string `json:"table"`            // Table the trigger is defined on
+}
+
+// QualifiedTable returns schema.table when Schema is set, or Table otherwise.
+func (t DBFunctionTrigger) QualifiedTable() string {
+	return QualifyTableName(t.Schema, t.Table)
 }
 
 // DBView represents a database view read from the database.
@@ -399,6 +476,15 @@ type DBRLSPolicy struct {
 	Comment             string `json:"comment"`               // Policy comment/description
 }
 
+// QualifiedName returns the policy name prefixed with the schema of its
+// table when the table is schema-qualified, or Name otherwise.
+func (p DBRLSPolicy) QualifiedName() string {
+	if i := strings.LastIndex(p.Table, "."); i >= 0 {
+		return p.Table[:i] + "." + p.Name
+	}
+	return p.Name
+}
+
 // DBRole represents a PostgreSQL role read from the database
 type DBRole struct {
 	Name        string `json:"name"`         // Role name
@@ -410,6 +496,12 @@ type DBRole struct {
 	Replication bool   `json:"replication"`  // Whether role can initiate replication
 	HasPassword bool   `json:"has_password"` // Whether role has a password set
 	Comment     string `json:"comment"`      // Role comment/description
+	// MemberOf lists the roles granted to this role (pg_auth_members),
+	// sorted by name.
+	MemberOf []string `json:"member_of,omitempty"`
+	// PasswordHash is the stored password verifier (rolpassword), empty when
+	// the reader may not see pg_authid. It is never serialized.
+	PasswordHash string `json:"-"`
 }
 
 // DBGrant represents a PostgreSQL privilege grant read from the database.
@@ -1,12 +1,15 @@
 package reusable_components_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"testing/fstest"
 
 	qt "github.com/frankban/quicktest"
 
 	"github.com/stokaro/ptah/atlascompat"
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/ast"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform/capability"
@@ -136,3 +139,45 @@ func TestCapabilitiesRender(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(sql, qt.Contains, "GENERATED BY DEFAULT AS IDENTITY")
 }
+
+// lowercaseName is a custom comparator enforcing lowercase column names.
+func lowercaseName(field goschema.Field, column dbschematypes.DBColumn) (string, string, bool) {
+	want := strings.ToLower(field.Name)
+	return column.Name, want, column.Name != want
+}
+
+// renameToLowercase emits the rename a lowercaseName change calls for.
+func renameToLowercase(change planner.CustomChange) ([]string, error) {
+	return []string{fmt.Sprintf(`ALTER TABLE %s RENAME COLUMN "%s" TO "%s"`, change.Table, change.Before, change.After)}, nil
+}
+
+func TestCustomComparatorAndStatementGenerator(t *testing.T) {
+	c := qt.New(t)
+	desired := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{
+			{Name: "id", Type: "INTEGER", Primary: true, StructName: "User"},
+			{Name: "Email", Type: "TEXT", StructName: "User"},
+		},
+	}
+	live := &dbschematypes.DBSchema{Tables: []dbschematypes.DBTable{{
+		Name: "users",
+		Columns: []dbschematypes.DBColumn{
+			{Name: "id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "Email", DataType: "text", IsNullable: "NO"},
+		},
+	}}}
+
+	opts := config.WithCustomComparator("lowercase_name", lowercaseName)
+	opts.Dialect = "postgres"
+	diff := schemadiff.CompareWithOptions(desired, live, opts)
+	c.Assert(diff.TablesModified[0].ColumnsModified[0].Changes, qt.DeepEquals, map[string]string{
+		"custom:lowercase_name": "Email -> email",
+	})
+
+	planOpts := planner.Options{}.WithCustomStatementGenerator("lowercase_name", planner.CustomStatementsLast, renameToLowercase)
+	statements, err := planner.GenerateSchemaDiffSQLStatementsWithOptions(diff, desired, "postgres", planOpts)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(statements, qt.DeepEquals, []string{`ALTER TABLE users RENAME COLUMN "Email" TO "email"`})
+}
@@ -7,6 +7,7 @@ type SchemaMarker struct{}
 type ExtensionsMarker struct{}
 
 //migrator:schema:table name="users"
+//migrator:schema:seed columns="email,name" rows="admin@example.com,Admin"
 //migrator:schema:constraint name="users_email_check" type="CHECK" check="email <> ''" comment="Email must not be empty"
 type User struct {
 	//migrator:schema:field name="id" type="SERIAL" primary="true"
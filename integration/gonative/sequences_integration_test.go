@@ -8,6 +8,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/internal/dbschema/postgres"
 )
 
@@ -75,3 +76,49 @@ func TestPostgreSQLSequenceIntrospectionClassificationIntegration(t *testing.T)
 	c.Assert(hasSerial, qt.IsFalse, qt.Commentf("SERIAL backing sequence must be excluded"))
 	c.Assert(hasIdent, qt.IsFalse, qt.Commentf("identity backing sequence must be excluded"))
 }
+
+// TestPostgreSQLIdentitySequenceOptionsIntegration verifies that the START WITH
+// and INCREMENT BY options of the sequence owned by an identity column are
+// introspected, so a declared non-default increment round-trips without drift.
+func TestPostgreSQLIdentitySequenceOptionsIntegration(t *testing.T) {
+	dsn := skipIfNoPostgreSQL(t)
+	c := qt.New(t)
+
+	db, err := sql.Open("pgx", dsn)
+	c.Assert(err, qt.IsNil)
+	defer db.Close()
+
+	cleanup := func() { _, _ = db.Exec(`DROP TABLE IF EXISTS seq_ident_opts CASCADE`) }
+	cleanup()
+	defer cleanup()
+
+	_, err = db.Exec(`CREATE TABLE seq_ident_opts (
+		id bigint GENERATED ALWAYS AS IDENTITY (START WITH 1000 INCREMENT BY 5) PRIMARY KEY,
+		plain_id bigint GENERATED BY DEFAULT AS IDENTITY
+	)`)
+	c.Assert(err, qt.IsNil)
+
+	reader := postgres.NewPostgreSQLReader(db, "public")
+	live, err := reader.ReadSchema()
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(identityOptionsByColumn(live, "seq_ident_opts"), qt.DeepEquals, map[string][2]string{
+		"id":       {"1000", "5"},
+		"plain_id": {"1", "1"},
+	})
+}
+
+// identityOptionsByColumn returns the identity start and increment of every
+// column of the named table.
+func identityOptionsByColumn(live *types.DBSchema, tableName string) map[string][2]string {
+	options := map[string][2]string{}
+	for _, table := range live.Tables {
+		if table.Name != tableName {
+			continue
+		}
+		for _, column := range table.Columns {
+			options[column.Name] = [2]string{column.IdentityStart, column.IdentityIncrement}
+		}
+	}
+	return options
+}
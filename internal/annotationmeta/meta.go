@@ -264,10 +264,11 @@ var directives = []Directive{
 			attr("foreign", "Foreign key reference in table(column) form.", valueString, false, false),
 			attr("foreign_key_name", "Explicit foreign key constraint name.", valueString, false, false),
 			attr("on_delete", "Foreign key ON DELETE action.", valueString, false, false),
-			attr("on_update", "Foreign key ON UPDATE action.", valueString, false, false),
+			attr("on_update", "Foreign key ON UPDATE action, or a timestamp expression such as CURRENT_TIMESTAMP for the MySQL/MariaDB column ON UPDATE clause.", valueString, false, false),
 			attr("enum", "Comma-separated enum values.", valueList, false, false),
 			attr("check", "Column CHECK expression.", valueSQL, false, false),
 			attr("check_name", "Explicit CHECK constraint name.", valueString, false, false),
+			attr("type_cast", "SQL expression converting the old column value when a safe type change copies it into a new column.", valueSQL, false, false),
 			attr("comment", "Column comment.", valueString, false, false),
 		},
 	},
@@ -280,7 +281,7 @@ var directives = []Directive{
 			attr("mode", "Embedding mode: inline, json, or relation.", valueString, false, false),
 			attr("prefix", "Column prefix for inline embedded fields.", valueString, false, false),
 			attr("name", "Column name for json embedding.", valueString, false, false),
-			attr("type", "Column type for json embedding.", valueString, false, false),
+			attr("type", "Column type for json embedding, or the foreign key column type for relation embedding (defaults to the referenced column type).", valueString, false, false),
 			attr("nullable", "Marks generated embedded columns nullable.", valueBoolean, false, true),
 			attr("not_null", "Compatibility flag accepted on embedded annotations.", valueBoolean, false, true),
 			attr("index", "Requests an index for generated relation columns.", valueBoolean, false, true),
@@ -289,6 +290,7 @@ var directives = []Directive{
 			attr("on_delete", "Generated foreign key ON DELETE action.", valueString, false, false),
 			attr("on_update", "Generated foreign key ON UPDATE action.", valueString, false, false),
 			attr("comment", "Generated column comment.", valueString, false, false),
+			attr("default_expr", "Default expression for the json embedding column.", valueSQL, false, false),
 		},
 	},
 	{
@@ -300,15 +302,19 @@ var directives = []Directive{
 			attr("name", "Index name.", valueString, false, false),
 			attr("fields", "Comma-separated Go field or column names.", valueList, false, false),
 			alias("columns", "fields", "Legacy synonym for fields.", valueList, false),
+			attr("expr", "Index key expression, for example lower(email).", valueSQL, false, false),
 			attr("unique", "Creates a unique index.", valueBoolean, false, true),
 			attr("comment", "Index comment.", valueString, false, false),
-			attr("type", "Index type or method.", valueString, false, false),
+			attr("type", "Index type or method: a PostgreSQL access method (btree, hash, gin, gist, ...), a ClickHouse skipping-index type, or fulltext, spatial, btree, or hash on MySQL and MariaDB.", valueString, false, false),
 			attr("condition", "Partial index condition.", valueSQL, false, false),
 			alias("where", "condition", "Atlas-style partial index condition alias.", valueSQL, false),
-			attr("ops", "PostgreSQL operator class.", valueString, false, false),
+			attr("ops", "PostgreSQL operator class, or a comma-separated list with one class per field.", valueString, false, false),
 			attr("table", "Explicit target table.", valueString, false, false),
 			attr("granularity", "ClickHouse data-skipping index granularity.", valueString, false, false),
 			attr("nulls_distinct", "Controls NULLS DISTINCT behavior where supported.", valueBoolean, false, false),
+			attr("nulls_not_distinct", "PostgreSQL 15+ UNIQUE NULLS NOT DISTINCT; true is the same as nulls_distinct=false.", valueBoolean, false, false),
+			attr("storage", "Comma-separated PostgreSQL index storage parameters, for example fillfactor=70.", valueList, false, false),
+			attr("visible", "MySQL/MariaDB index visibility; false creates an INVISIBLE (MariaDB: IGNORED) index.", valueBoolean, false, false),
 		},
 	},
 	{
@@ -320,9 +326,13 @@ var directives = []Directive{
 			attr("name", "Table name.", valueString, false, false),
 			attr("schema", "Database schema name.", valueString, false, false),
 			attr("engine", "MySQL/MariaDB table engine shortcut.", valueString, false, false),
+			attr("auto_increment", "MySQL/MariaDB AUTO_INCREMENT start value for the table counter.", valueString, false, false),
 			attr("comment", "Table comment.", valueString, false, false),
+			attr("owner", "PostgreSQL role that must own the table; ownership is compared only when set.", valueString, false, false),
 			attr("primary_key", "Comma-separated primary key columns.", valueList, false, false),
 			attr("checks", "Comma-separated table-level check expressions.", valueList, false, false),
+			attr("inherits", "Comma-separated PostgreSQL parent tables (INHERITS).", valueList, false, false),
+			attr("external_columns", "Comma-separated columns managed outside ptah; never dropped or compared.", valueList, false, false),
 			attr("custom", "Raw custom CREATE TABLE SQL.", valueSQL, false, false),
 		},
 	},
@@ -351,11 +361,27 @@ var directives = []Directive{
 			attr("columns", "Comma-separated local columns.", valueList, false, false),
 			attr("include", "Comma-separated PostgreSQL INCLUDE columns for covering UNIQUE constraints.", valueList, false, false),
 			attr("nulls_distinct", "Controls NULLS DISTINCT behavior where supported.", valueBoolean, false, false),
+			attr("nulls_not_distinct", "PostgreSQL 15+ UNIQUE NULLS NOT DISTINCT; true is the same as nulls_distinct=false.", valueBoolean, false, false),
 			attr("foreign_table", "Referenced table for FOREIGN KEY constraints.", valueString, false, false),
 			attr("foreign_column", "Single referenced column for FOREIGN KEY constraints.", valueString, false, false),
 			attr("foreign_columns", "Comma-separated referenced columns for composite FOREIGN KEY constraints.", valueList, false, false),
 			attr("on_delete", "Foreign key ON DELETE action.", valueString, false, false),
 			attr("on_update", "Foreign key ON UPDATE action.", valueString, false, false),
+			attr("not_valid", "Adds a CHECK or FOREIGN KEY constraint NOT VALID and validates it in a later statement (PostgreSQL family).", valueBoolean, false, false),
+			attr("comment", "Constraint comment.", valueString, false, false),
+		},
+	},
+	{
+		Name:        "migrator:schema:exclude",
+		Description: "Declares a PostgreSQL EXCLUDE constraint.",
+		Scopes:      []Scope{ScopeStruct, ScopeField},
+		Attributes: []Attribute{
+			attr("name", "Constraint name.", valueString, true, false),
+			attr("table", "Explicit target table.", valueString, false, false),
+			attr("method", "Index method backing the constraint; defaults to gist.", valueString, false, false),
+			attr("expressions", "Comma-separated columns or parenthesized expressions.", valueSQL, true, false),
+			attr("operators", "Comma-separated exclusion operators, one per expression.", valueList, true, false),
+			attr("where", "Optional predicate limiting the constraint to matching rows.", valueSQL, false, false),
 			attr("comment", "Constraint comment.", valueString, false, false),
 		},
 	},
@@ -366,6 +392,7 @@ var directives = []Directive{
 		Attributes: []Attribute{
 			attr("name", "Enum type name.", valueString, true, false),
 			attr("values", "Comma-separated enum values.", valueList, true, false),
+			attr("rename_from", "Previous enum type name; an existing type of that name is renamed instead of dropped and recreated (PostgreSQL).", valueString, false, false),
 		},
 	},
 	{
@@ -385,6 +412,7 @@ var directives = []Directive{
 		Scopes:      []Scope{ScopeStruct},
 		Attributes: []Attribute{
 			attr("name", "Function name.", valueString, false, false),
+			attr("schema", "Target schema/namespace.", valueString, false, false),
 			attr("params", "Function parameter list.", valueString, false, false),
 			attr("returns", "Return type.", valueString, false, false),
 			attr("language", "Function language.", valueString, false, false),
@@ -471,12 +499,14 @@ var directives = []Directive{
 		Description:   "Declares a materialized view.",
 		Scopes:        []Scope{ScopeStruct},
 		AllowPlatform: true,
-		Attributes: []Attribute{
-			attr("name", "Materialized view name.", valueString, true, false),
-			attr("body", "Materialized view SELECT body.", valueSQL, true, false),
-			attr("refresh_strategy", "Refresh strategy; defaults to manual.", valueString, false, false),
-			attr("comment", "Materialized view comment.", valueString, false, false),
-		},
+		Attributes:    materializedViewAttributes(),
+	},
+	{
+		Name:          "migrator:schema:materialized_view",
+		Description:   "Declares a materialized view; equivalent to migrator:schema:matview.",
+		Scopes:        []Scope{ScopeStruct},
+		AllowPlatform: true,
+		Attributes:    materializedViewAttributes(),
 	},
 	{
 		Name:          "migrator:schema:trigger",
@@ -500,6 +530,7 @@ var directives = []Directive{
 		Attributes: []Attribute{
 			attr("name", "Policy name.", valueString, false, false),
 			attr("table", "Target table.", valueString, false, false),
+			attr("schema", "Schema/namespace of the target table.", valueString, false, false),
 			attr("for", "Policy command, such as ALL or SELECT.", valueString, false, false),
 			attr("to", "Comma-separated roles.", valueList, false, false),
 			attr("using", "USING expression.", valueSQL, false, false),
@@ -524,6 +555,7 @@ var directives = []Directive{
 			attr("name", "Role name.", valueString, false, false),
 			attr("login", "Creates the role with LOGIN.", valueBoolean, false, false),
 			attr("password", "Role password.", valueString, false, false),
+			attr("password_env", "Environment variable the migrator reads the role password from when the migration runs.", valueString, false, false),
 			attr("superuser", "Creates the role as SUPERUSER.", valueBoolean, false, false),
 			attr("createdb", "Allows database creation.", valueBoolean, false, false),
 			alias("create_db", "createdb", "Alias for createdb.", valueBoolean, false),
@@ -531,6 +563,7 @@ var directives = []Directive{
 			alias("create_role", "createrole", "Alias for createrole.", valueBoolean, false),
 			attr("inherit", "Controls role inheritance; defaults to true.", valueBoolean, false, false),
 			attr("replication", "Allows replication.", valueBoolean, false, false),
+			attr("member_of", "Comma-separated roles granted to this role (GRANT role TO name).", valueList, false, false),
 			attr("comment", "Role comment.", valueString, false, false),
 		},
 	},
@@ -550,6 +583,18 @@ var directives = []Directive{
 			attr("comment", "Grant comment.", valueString, false, false),
 		},
 	},
+	{
+		Name:        "migrator:schema:seed",
+		Description: "Declares rows inserted into a table when the migration creates it.",
+		Scopes:      []Scope{ScopeStruct},
+		Attributes: []Attribute{
+			attr("table", "Target table; defaults to the annotated struct's table.", valueString, false, false),
+			attr("columns", "Comma-separated columns populated by each row.", valueList, false, false),
+			attr("rows", "Semicolon-separated rows of comma-separated values; NULL inserts SQL NULL.", valueString, false, false),
+			attr("seed_file", "JSON or YAML file of row objects, relative to the Go source file.", valueString, false, false),
+			attr("always", "Inserts the rows in every generated migration, not only the one creating the table.", valueBoolean, false, false),
+		},
+	},
 }
 
 func attr(name, description, value string, required, boolean bool) Attribute {
@@ -567,3 +612,13 @@ func alias(name, aliasFor, description, value string, boolean bool) Attribute {
 	a.AliasFor = aliasFor
 	return a
 }
+
+func materializedViewAttributes() []Attribute {
+	return []Attribute{
+		attr("name", "Materialized view name.", valueString, true, false),
+		attr("body", "Materialized view SELECT body.", valueSQL, true, false),
+		attr("refresh_strategy", "Refresh strategy; defaults to manual.", valueString, false, false),
+		attr("with_data", "Populate the view on creation; false creates it WITH NO DATA. Defaults to true.", valueBoolean, false, true),
+		attr("comment", "Materialized view comment.", valueString, false, false),
+	}
+}
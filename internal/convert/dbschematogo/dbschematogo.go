@@ -2,6 +2,8 @@ package dbschematogo
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/stokaro/ptah/core/goschema"
@@ -109,23 +111,33 @@ func convertTablesAndFields(
 			PrimaryKey:   primaryKeysByTable[dbTable.QualifiedName()],
 			Strict:       dbTable.Strict,
 			WithoutRowID: dbTable.WithoutRowID,
+			Inherits:     dbTable.Inherits,
 		}
 		database.Tables = append(database.Tables, table)
 
-		// Convert columns to fields
+		// Convert columns to fields. Columns inherited from a parent table
+		// belong to the parent's struct, not the child's.
 		for _, dbColumn := range dbTable.Columns {
+			if dbColumn.Inherited {
+				continue
+			}
 			field := goschema.Field{
-				StructName:    structName,
-				FieldName:     generateFieldName(dbColumn.Name),
-				Name:          dbColumn.Name,
-				Type:          goSchemaFieldType(dbColumn),
-				Nullable:      dbColumn.IsNullable == "YES",
-				Primary:       dbColumn.IsPrimaryKey && !compositePKColumns[dbTable.QualifiedName()][dbColumn.Name],
-				AutoInc:       dbColumn.IsAutoIncrement,
-				Unique:        dbColumn.IsUnique,
-				Charset:       dbColumn.Charset,
-				Collate:       dbColumn.Collate,
-				GeneratedKind: dbColumn.GeneratedKind,
+				StructName:         structName,
+				FieldName:          generateFieldName(dbColumn.Name),
+				Name:               dbColumn.Name,
+				Type:               goSchemaFieldType(dbColumn),
+				Nullable:           dbColumn.IsNullable == "YES",
+				Primary:            dbColumn.IsPrimaryKey && !compositePKColumns[dbTable.QualifiedName()][dbColumn.Name],
+				AutoInc:            dbColumn.IsAutoIncrement,
+				Unique:             dbColumn.IsUnique,
+				Charset:            dbColumn.Charset,
+				Collate:            dbColumn.Collate,
+				GeneratedKind:      dbColumn.GeneratedKind,
+				UpdateExpression:   dbColumn.UpdateExpression,
+				Comment:            dbColumn.Comment,
+				IdentityGeneration: dbColumn.IdentityGeneration,
+				IdentityStart:      nonDefaultIdentityOption(dbColumn.IdentityStart),
+				IdentityIncrement:  nonDefaultIdentityOption(dbColumn.IdentityIncrement),
 			}
 			if dbColumn.GeneratedExpression != nil {
 				field.GeneratedExpression = *dbColumn.GeneratedExpression
@@ -182,17 +194,45 @@ func convertIndexes(dbSchema *dbschematypes.DBSchema, tableStructNames map[strin
 			Name:          dbIndex.Name,
 			TableName:     dbIndex.QualifiedTableName(),
 			Fields:        dbIndex.Columns,
+			Parts:         indexPartsWithOperatorClasses(dbIndex),
 			Unique:        dbIndex.IsUnique,
 			Condition:     dbIndex.Condition,
 			NullsDistinct: cloneBoolPtr(dbIndex.NullsDistinct),
+			StorageParams: maps.Clone(dbIndex.StorageParams),
 			Type:          dbIndex.Type,
 			Granularity:   dbIndex.Granularity,
 		}
+		if dbIndex.Invisible {
+			visible := false
+			index.Visible = &visible
+		}
 		indexes = append(indexes, index)
 	}
 	return indexes
 }
 
+// indexPartsWithOperatorClasses returns the index key parts when any of them
+// uses a non-default PostgreSQL operator class, so a recreated index keeps
+// it. It returns nil otherwise, leaving Fields as the key list.
+func indexPartsWithOperatorClasses(dbIndex dbschematypes.DBIndex) []goschema.IndexPart {
+	if len(dbIndex.OperatorClasses) != len(dbIndex.Columns) ||
+		!slices.ContainsFunc(dbIndex.OperatorClasses, func(opClass dbschematypes.DBIndexOperatorClass) bool { return !opClass.Default }) {
+		return nil
+	}
+	parts := make([]goschema.IndexPart, 0, len(dbIndex.Columns))
+	for i, column := range dbIndex.Columns {
+		part := goschema.IndexPart{Name: column}
+		if strings.ContainsAny(column, "()") {
+			part = goschema.IndexPart{Expr: column}
+		}
+		if opClass := dbIndex.OperatorClasses[i]; !opClass.Default {
+			part.Operator = opClass.Name
+		}
+		parts = append(parts, part)
+	}
+	return parts
+}
+
 func convertExtensions(database *goschema.Database, dbExtensions []dbschematypes.DBExtension) {
 	for _, dbExtension := range dbExtensions {
 		extension := goschema.Extension{
@@ -235,6 +275,7 @@ func convertFunctions(database *goschema.Database, dbFunctions []dbschematypes.D
 		function := goschema.Function{
 			StructName: "", // Functions are not associated with specific structs in DB schema
 			Name:       dbFunction.Name,
+			Schema:     dbFunction.Schema,
 			Parameters: dbFunction.Parameters,
 			Returns:    dbFunction.Returns,
 			Language:   dbFunction.Language,
@@ -348,6 +389,7 @@ func convertRoles(database *goschema.Database, dbRoles []dbschematypes.DBRole) {
 			CreateRole:  dbRole.CreateRole,
 			Inherit:     dbRole.Inherit,
 			Replication: dbRole.Replication,
+			MemberOf:    slices.Clone(dbRole.MemberOf),
 			Comment:     dbRole.Comment,
 		}
 		database.Roles = append(database.Roles, role)
@@ -502,6 +544,16 @@ func firstString(values []string) string {
 	return values[0]
 }
 
+// nonDefaultIdentityOption drops an identity START WITH or INCREMENT BY
+// value of 1, the PostgreSQL default, so plain identity columns do not carry
+// the options into the converted schema.
+func nonDefaultIdentityOption(value string) string {
+	if value == "1" {
+		return ""
+	}
+	return value
+}
+
 func goSchemaFieldType(dbColumn dbschematypes.DBColumn) string {
 	if strings.EqualFold(dbColumn.DataType, "USER-DEFINED") && dbColumn.UDTName != "" {
 		return dbColumn.UDTName
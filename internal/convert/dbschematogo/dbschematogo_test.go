@@ -212,6 +212,29 @@ func TestConvertDBSchemaToGoSchema_GeneratedColumns(t *testing.T) {
 	c.Assert(result.Fields[0].GeneratedKind, qt.Equals, "STORED")
 }
 
+func TestConvertDBSchemaToGoSchema_InheritedTableSkipsInheritedColumns(t *testing.T) {
+	c := qt.New(t)
+	dbSchema := &types.DBSchema{
+		Tables: []types.DBTable{
+			{
+				Name:     "capitals",
+				Inherits: []string{"cities"},
+				Columns: []types.DBColumn{
+					{Name: "name", DataType: "text", Inherited: true},
+					{Name: "state", DataType: "text"},
+				},
+			},
+		},
+	}
+
+	result := dbschematogo.ConvertDBSchemaToGoSchema(dbSchema)
+
+	c.Assert(result.Tables, qt.HasLen, 1)
+	c.Assert(result.Tables[0].Inherits, qt.DeepEquals, []string{"cities"})
+	c.Assert(result.Fields, qt.HasLen, 1)
+	c.Assert(result.Fields[0].Name, qt.Equals, "state")
+}
+
 func TestConvertDBSchemaToGoSchema_PostgresUserDefinedColumnUsesUDTName(t *testing.T) {
 	c := qt.New(t)
 	dbSchema := &types.DBSchema{
@@ -580,3 +603,36 @@ func TestConvertDBSchemaToGoSchema_ExtensionDefaultValues(t *testing.T) {
 	c.Assert(ext.Version, qt.Equals, "1.0")
 	c.Assert(ext.Comment, qt.Equals, "") // Should be empty string when nil
 }
+
+func TestConvertDBSchemaToGoSchema_IndexOperatorClasses(t *testing.T) {
+	c := qt.New(t)
+	dbSchema := &types.DBSchema{
+		Tables: []types.DBTable{{Name: "products"}},
+		Indexes: []types.DBIndex{
+			{
+				Name:      "idx_products_search",
+				TableName: "products",
+				Columns:   []string{"tenant", "lower(name)"},
+				OperatorClasses: []types.DBIndexOperatorClass{
+					{Name: "text_ops", Default: true},
+					{Name: "gin_trgm_ops"},
+				},
+			},
+			{
+				Name:            "idx_products_tenant",
+				TableName:       "products",
+				Columns:         []string{"tenant"},
+				OperatorClasses: []types.DBIndexOperatorClass{{Name: "text_ops", Default: true}},
+			},
+		},
+	}
+
+	result := dbschematogo.ConvertDBSchemaToGoSchema(dbSchema)
+
+	c.Assert(result.Indexes, qt.HasLen, 2)
+	c.Assert(result.Indexes[0].Parts, qt.DeepEquals, []goschema.IndexPart{
+		{Name: "tenant"},
+		{Expr: "lower(name)", Operator: "gin_trgm_ops"},
+	})
+	c.Assert(result.Indexes[1].Parts, qt.IsNil)
+}
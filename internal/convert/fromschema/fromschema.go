@@ -56,6 +56,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/stokaro/ptah/core/ast"
 	"github.com/stokaro/ptah/core/goschema"
@@ -111,6 +112,12 @@ func isPostgreSQLPlatform(targetPlatform string) bool {
 }
 
 func applyPlatformOverrides(field goschema.Field, targetPlatform string) goschema.Field {
+	field = overriddenField(field, targetPlatform)
+	field.DefaultExpr = PlatformDefaultExpr(field.DefaultExpr, field.Type, targetPlatform)
+	return field
+}
+
+func overriddenField(field goschema.Field, targetPlatform string) goschema.Field {
 	fieldType := platformFieldType(field.Type, targetPlatform)
 	checkConstraint := field.Check
 	checkName := field.CheckName
@@ -203,10 +210,37 @@ func applyPlatformOverrides(field goschema.Field, targetPlatform string) goschem
 	)
 }
 
+// PlatformDefaultExpr returns the default expression targetPlatform accepts
+// for a column of fieldType. MySQL and MariaDB have no gen_random_uuid() or
+// uuid_generate_v4(); they generate a UUID with UUID() as a parenthesized
+// default expression, packed to 16 bytes for a BINARY(16) column (MySQL's
+// UUID_TO_BIN, which MariaDB lacks). Other expressions and platforms are
+// returned unchanged.
+func PlatformDefaultExpr(expr, fieldType, targetPlatform string) string {
+	if targetPlatform != platform.MySQL && targetPlatform != platform.MariaDB {
+		return expr
+	}
+	switch strings.ToLower(strings.TrimSpace(expr)) {
+	case "gen_random_uuid()", "uuid_generate_v4()":
+	default:
+		return expr
+	}
+	switch {
+	case !strings.EqualFold(strings.ReplaceAll(fieldType, " ", ""), "BINARY(16)"):
+		return "(UUID())"
+	case targetPlatform == platform.MariaDB:
+		return "(UNHEX(REPLACE(UUID(), '-', '')))"
+	default:
+		return "(UUID_TO_BIN(UUID()))"
+	}
+}
+
 func platformFieldType(fieldType, targetPlatform string) string {
 	switch targetPlatform {
-	case platform.MySQL, platform.MariaDB:
-		return mysqlFamilyFieldType(fieldType)
+	case platform.MySQL:
+		return mysqlFieldType(fieldType)
+	case platform.MariaDB:
+		return mariaDBFieldType(fieldType)
 	case platform.SQLServer:
 		return sqlServerFieldType(fieldType)
 	default:
@@ -225,6 +259,25 @@ func mysqlFamilyFieldType(fieldType string) string {
 	}
 }
 
+// mysqlFieldType maps UUID to CHAR(36), the canonical text form UUID()
+// returns; MySQL has no native UUID type. A platform.mysql.type="BINARY(16)"
+// override stores the compact binary form instead.
+func mysqlFieldType(fieldType string) string {
+	if strings.EqualFold(fieldType, "UUID") {
+		return "CHAR(36)"
+	}
+	return mysqlFamilyFieldType(fieldType)
+}
+
+// mariaDBFieldType maps PostgreSQL's JSONB to JSON, which MariaDB stores as
+// LONGTEXT with a json_valid CHECK; MySQL has a native JSON type instead.
+func mariaDBFieldType(fieldType string) string {
+	if strings.EqualFold(fieldType, "JSONB") {
+		return "JSON"
+	}
+	return mysqlFamilyFieldType(fieldType)
+}
+
 func sqlServerFieldType(fieldType string) string {
 	switch fieldType {
 	case "SERIAL":
@@ -275,7 +328,7 @@ func handleEnumTypes(field goschema.Field, enums []goschema.Enum, targetPlatform
 	// Validate enum field
 	validateEnumField(field, enums)
 
-	if targetPlatform != "mysql" && targetPlatform != "mariadb" && targetPlatform != "sqlite" && targetPlatform != "sqlserver" {
+	if platform.EnumStrategyFor(targetPlatform) == platform.EnumStrategyNativeType {
 		return field
 	}
 
@@ -296,19 +349,17 @@ func applyInlineEnumModel(field goschema.Field, enum goschema.Enum, targetPlatfo
 	}
 
 	newField := field
-	switch targetPlatform {
-	case "mysql", "mariadb":
+	switch platform.EnumStrategyFor(targetPlatform) {
+	case platform.EnumStrategyInlineEnum:
 		newField.Type = fmt.Sprintf("ENUM(%s)", strings.Join(quotedValues, ", "))
-	case "sqlite":
+	case platform.EnumStrategyCheckConstraint:
+		column := field.Name
 		newField.Type = "TEXT"
-		enumCheck := fmt.Sprintf("%s IN (%s)", field.Name, strings.Join(quotedValues, ", "))
-		if field.Check != "" {
-			enumCheck = fmt.Sprintf("(%s) AND %s", field.Check, enumCheck)
+		if platform.NormalizeDialect(targetPlatform) == platform.SQLServer {
+			column = sqlServerBracketIdentifier(field.Name)
+			newField.Type = "NVARCHAR(255)"
 		}
-		newField.Check = enumCheck
-	case "sqlserver":
-		newField.Type = "NVARCHAR(255)"
-		enumCheck := fmt.Sprintf("%s IN (%s)", sqlServerBracketIdentifier(field.Name), strings.Join(quotedValues, ", "))
+		enumCheck := fmt.Sprintf("%s IN (%s)", column, strings.Join(quotedValues, ", "))
 		if field.Check != "" {
 			enumCheck = fmt.Sprintf("(%s) AND %s", field.Check, enumCheck)
 		}
@@ -317,13 +368,28 @@ func applyInlineEnumModel(field goschema.Field, enum goschema.Enum, targetPlatfo
 	return newField
 }
 
-func emitsStandaloneEnumDefinitions(targetPlatform string) bool {
-	switch platform.NormalizeDialect(targetPlatform) {
-	case platform.MySQL, platform.MariaDB, platform.SQLite, platform.SQLServer:
+// isPostgreSQLEnumDefault reports whether field declares a literal default on
+// a column typed as one of the native PostgreSQL enums.
+func isPostgreSQLEnumDefault(field goschema.Field, enums []goschema.Enum, targetPlatform string) bool {
+	if !isPostgreSQLPlatform(targetPlatform) || (!field.DefaultSet && field.Default == "") {
 		return false
-	default:
-		return true
 	}
+	return slices.ContainsFunc(enums, func(enum goschema.Enum) bool { return enum.Name == field.Type })
+}
+
+// postgresEnumDefaultExpression casts the literal default of an enum column to
+// the enum type, e.g. 'active'::enum_user_status. Some setups reject an
+// uncast literal, and the cast form is what PostgreSQL reads back.
+func postgresEnumDefaultExpression(field goschema.Field) string {
+	value := field.Default
+	if len(value) < 2 || !strings.HasPrefix(value, "'") || !strings.HasSuffix(value, "'") {
+		value = escapeSQLStringLiteral(value)
+	}
+	return value + "::" + field.Type
+}
+
+func emitsStandaloneEnumDefinitions(targetPlatform string) bool {
+	return platform.EnumStrategyFor(targetPlatform) == platform.EnumStrategyNativeType
 }
 
 // FromField converts a goschema.Field to an ast.ColumnNode with comprehensive attribute mapping.
@@ -436,6 +502,8 @@ func FromField(field goschema.Field, enums []goschema.Enum, targetPlatform strin
 
 	// Set default values (using potentially overridden values)
 	switch {
+	case isPostgreSQLEnumDefault(field, enums, targetPlatform):
+		column.SetDefaultExpression(postgresEnumDefaultExpression(field))
 	case field.DefaultSet || field.Default != "":
 		column.SetDefault(field.Default)
 	case field.DefaultExpr != "":
@@ -523,7 +591,10 @@ func FromFieldWithoutForeignKeys(field goschema.Field, enums []goschema.Enum, ta
 	}
 
 	// Set default value (using potentially overridden value)
-	if field.DefaultSet || field.Default != "" {
+	switch {
+	case isPostgreSQLEnumDefault(field, enums, targetPlatform):
+		column.SetDefaultExpression(postgresEnumDefaultExpression(field))
+	case field.DefaultSet || field.Default != "":
 		column.SetDefault(field.Default)
 	}
 
@@ -762,6 +833,7 @@ func fromTableWithFieldConverter(
 		}
 	}
 	createTable.Partition = toASTPartition(newTable.Partition)
+	createTable.Inherits = slices.Clone(newTable.Inherits)
 
 	// Add columns for fields that belong to this table
 	tableLevelPK := tableNeedsPrimaryKeyConstraint(newTable)
@@ -988,11 +1060,12 @@ func FromIndex(index goschema.Index) *ast.IndexNode {
 
 	indexNode := ast.NewIndex(index.Name, tableName, indexFields(index)...)
 	if len(index.Parts) > 0 {
-		indexNode.SetParts(toASTIndexParts(index.Parts))
+		indexNode.SetParts(ToASTIndexParts(index.Parts))
 	}
 	indexNode.IncludeColumns = index.IncludeColumns
 	indexNode.NullsDistinct = cloneBoolPtr(index.NullsDistinct)
 	indexNode.StorageParams = maps.Clone(index.StorageParams)
+	indexNode.Invisible = index.Visible != nil && !*index.Visible
 
 	// Set unique constraint
 	if index.Unique {
@@ -1222,7 +1295,7 @@ func FromRange(rangeType goschema.Range) *ast.CreateTypeNode {
 //
 // Returns a fully configured *ast.CreateFunctionNode ready for SQL generation.
 func FromFunction(function goschema.Function) *ast.CreateFunctionNode {
-	functionNode := ast.NewCreateFunction(function.Name).
+	functionNode := ast.NewCreateFunction(function.QualifiedName()).
 		SetParameters(function.Parameters).
 		SetReturns(function.Returns).
 		SetLanguage(function.Language).
@@ -1310,6 +1383,7 @@ func FromMaterializedView(view goschema.MaterializedView) *ast.CreateMaterialize
 	viewNode := ast.NewCreateMaterializedView(view.Name).
 		SetBody(view.Body).
 		SetRefreshStrategy(view.RefreshStrategy).
+		SetWithNoData(view.WithNoData).
 		SetComment(view.Comment)
 	return viewNode
 }
@@ -1412,7 +1486,7 @@ func FromTrigger(trigger goschema.Trigger) *ast.CreateTriggerNode {
 //
 // Returns a fully configured *ast.CreatePolicyNode ready for SQL generation.
 func FromRLSPolicy(policy goschema.RLSPolicy) *ast.CreatePolicyNode {
-	policyNode := ast.NewCreatePolicy(policy.Name, policy.Table).
+	policyNode := ast.NewCreatePolicy(policy.Name, policy.QualifiedTable()).
 		SetPolicyFor(policy.PolicyFor).
 		SetToRoles(policy.ToRoles).
 		SetUsingExpression(policy.UsingExpression).
@@ -1422,6 +1496,83 @@ func FromRLSPolicy(policy goschema.RLSPolicy) *ast.CreatePolicyNode {
 	return policyNode
 }
 
+// QualifyPolicyFunctionCalls returns policy with calls to schema-qualified
+// functions in its USING and WITH CHECK expressions qualified, so the
+// policy does not depend on the search_path of the migrating connection.
+func QualifyPolicyFunctionCalls(policy goschema.RLSPolicy, functions []goschema.Function) goschema.RLSPolicy {
+	policy.UsingExpression = QualifyFunctionCalls(policy.UsingExpression, functions)
+	policy.WithCheckExpression = QualifyFunctionCalls(policy.WithCheckExpression, functions)
+	return policy
+}
+
+// QualifyFunctionCalls prefixes unqualified calls to functions declared with
+// a schema by that schema. Already-qualified calls, string literals, and
+// quoted identifiers are left untouched; functions in the default schema are
+// never qualified.
+func QualifyFunctionCalls(expression string, functions []goschema.Function) string {
+	for _, function := range functions {
+		if function.Schema == "" || function.Name == "" {
+			continue
+		}
+		expression = qualifyFunctionCall(expression, strings.TrimSpace(function.Schema), strings.TrimSpace(function.Name))
+	}
+	return expression
+}
+
+func qualifyFunctionCall(expression, schema, name string) string {
+	var out strings.Builder
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		if c == '\'' || c == '"' {
+			end := closingQuote(expression, i)
+			out.WriteString(expression[i:end])
+			i = end
+			continue
+		}
+		if !isIdentifierPart(c) {
+			out.WriteByte(c)
+			i++
+			continue
+		}
+		end := i
+		for end < len(expression) && isIdentifierPart(expression[end]) {
+			end++
+		}
+		next := end
+		for next < len(expression) && unicode.IsSpace(rune(expression[next])) {
+			next++
+		}
+		call := next < len(expression) && expression[next] == '('
+		if call && strings.EqualFold(expression[i:end], name) && (i == 0 || expression[i-1] != '.') {
+			out.WriteString(schema + ".")
+		}
+		out.WriteString(expression[i:end])
+		i = end
+	}
+	return out.String()
+}
+
+// closingQuote returns the index just past the quoted section starting at
+// start, treating a doubled quote character as an escaped one.
+func closingQuote(expression string, start int) int {
+	quote := expression[start]
+	for i := start + 1; i < len(expression); i++ {
+		if expression[i] != quote {
+			continue
+		}
+		if i+1 < len(expression) && expression[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(expression)
+}
+
+func isIdentifierPart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
 // FromRLSEnabledTable converts a goschema.RLSEnabledTable to an ast.AlterTableEnableRLSNode.
 //
 // This function creates a PostgreSQL ALTER TABLE ENABLE ROW LEVEL SECURITY statement
@@ -1458,6 +1609,7 @@ func FromRole(role goschema.Role) *ast.CreateRoleNode {
 	roleNode := ast.NewCreateRole(role.Name).
 		SetLogin(role.Login).
 		SetPassword(role.Password).
+		SetPasswordEnv(role.PasswordEnv).
 		SetSuperuser(role.Superuser).
 		SetCreateDB(role.CreateDB).
 		SetCreateRole(role.CreateRole).
@@ -1631,8 +1783,9 @@ func FromDatabase(database goschema.Database, targetPlatform string) *ast.Statem
 
 	// 6. Add unique indexes before foreign keys. PostgreSQL accepts a unique
 	// index as the referenced key for a foreign key, so it must exist before
-	// the FK constraint is added.
-	appendUniqueIndexStatements(statements, database.Tables, database.Indexes)
+	// the FK constraint is added. Indexes on materialized views wait for the
+	// final index pass, which runs after the views are created.
+	appendUniqueIndexStatements(statements, database)
 
 	// 7. Add foreign key constraints after all tables and unique indexes exist.
 	if !isSQLiteTarget(targetPlatform) {
@@ -1648,7 +1801,7 @@ func FromDatabase(database goschema.Database, targetPlatform string) *ast.Statem
 	}
 
 	// 9. Add non-unique indexes last.
-	appendNonUniqueIndexStatements(statements, database.Tables, database.Indexes)
+	appendNonUniqueIndexStatements(statements, database)
 
 	return statements
 }
@@ -1674,26 +1827,25 @@ func appendTableStatements(
 	}
 }
 
-func appendUniqueIndexStatements(statements *ast.StatementList, tables []goschema.Table, indexes []goschema.Index) {
-	appendMatchingIndexStatements(statements, tables, indexes, func(index goschema.Index) bool {
-		return index.Unique
+func appendUniqueIndexStatements(statements *ast.StatementList, database goschema.Database) {
+	appendMatchingIndexStatements(statements, database, func(index goschema.Index) bool {
+		return index.Unique && !isMaterializedViewIndex(index, database.MaterializedViews)
 	})
 }
 
-func appendNonUniqueIndexStatements(statements *ast.StatementList, tables []goschema.Table, indexes []goschema.Index) {
-	appendMatchingIndexStatements(statements, tables, indexes, func(index goschema.Index) bool {
-		return !index.Unique
+func appendNonUniqueIndexStatements(statements *ast.StatementList, database goschema.Database) {
+	appendMatchingIndexStatements(statements, database, func(index goschema.Index) bool {
+		return !index.Unique || isMaterializedViewIndex(index, database.MaterializedViews)
 	})
 }
 
 func appendMatchingIndexStatements(
 	statements *ast.StatementList,
-	tables []goschema.Table,
-	indexes []goschema.Index,
+	database goschema.Database,
 	matches func(goschema.Index) bool,
 ) {
-	structToTableMap := createStructToTableMap(tables)
-	for _, index := range indexes {
+	structToTableMap := createStructToTableMap(database)
+	for _, index := range database.Indexes {
 		if !matches(index) {
 			continue
 		}
@@ -1702,6 +1854,34 @@ func appendMatchingIndexStatements(
 	}
 }
 
+// MaterializedViewIndexes returns the indexes declared on view: indexes whose
+// table is the view's name, or that are declared on the view's struct.
+func MaterializedViewIndexes(indexes []goschema.Index, view goschema.MaterializedView) []goschema.Index {
+	var result []goschema.Index
+	for _, index := range indexes {
+		if indexTargetsMaterializedView(index, view) {
+			result = append(result, index)
+		}
+	}
+	return result
+}
+
+func isMaterializedViewIndex(index goschema.Index, views []goschema.MaterializedView) bool {
+	for _, view := range views {
+		if indexTargetsMaterializedView(index, view) {
+			return true
+		}
+	}
+	return false
+}
+
+func indexTargetsMaterializedView(index goschema.Index, view goschema.MaterializedView) bool {
+	if index.TableName != "" {
+		return index.TableName == view.Name
+	}
+	return view.StructName != "" && index.StructName == view.StructName
+}
+
 func appendPostgreSQLPreIndexFeatureStatements(statements *ast.StatementList, database goschema.Database) {
 	for _, role := range database.Roles {
 		statements.Statements = append(statements.Statements, FromRole(role))
@@ -1729,7 +1909,7 @@ func appendPostgreSQLPostForeignKeyFeatureStatements(statements *ast.StatementLi
 		statements.Statements = append(statements.Statements, FromRLSEnabledTable(rlsEnabled))
 	}
 	for _, rlsPolicy := range database.RLSPolicies {
-		statements.Statements = append(statements.Statements, FromRLSPolicy(rlsPolicy))
+		statements.Statements = append(statements.Statements, FromRLSPolicy(QualifyPolicyFunctionCalls(rlsPolicy, database.Functions)))
 	}
 	for _, grant := range database.Grants {
 		statements.Statements = append(statements.Statements, FromGrant(grant))
@@ -1776,10 +1956,16 @@ func appendSchemaStatements(statements *ast.StatementList, schemas []goschema.Sc
 }
 
 // createStructToTableMap creates a mapping from struct names to table names.
-// This is used to resolve the correct table names for indexes.
-func createStructToTableMap(tables []goschema.Table) map[string]string {
+// This is used to resolve the correct table names for indexes, including
+// indexes declared on a materialized view's struct.
+func createStructToTableMap(database goschema.Database) map[string]string {
 	structToTableMap := make(map[string]string)
-	for _, table := range tables {
+	for _, view := range database.MaterializedViews {
+		if view.StructName != "" {
+			structToTableMap[view.StructName] = view.Name
+		}
+	}
+	for _, table := range database.Tables {
 		structToTableMap[table.StructName] = table.QualifiedName()
 	}
 	return structToTableMap
@@ -1802,11 +1988,12 @@ func FromIndexWithTableMapping(index goschema.Index, structToTableMap map[string
 
 	indexNode := ast.NewIndex(index.Name, tableName, indexFields(index)...)
 	if len(index.Parts) > 0 {
-		indexNode.SetParts(toASTIndexParts(index.Parts))
+		indexNode.SetParts(ToASTIndexParts(index.Parts))
 	}
 	indexNode.IncludeColumns = index.IncludeColumns
 	indexNode.NullsDistinct = cloneBoolPtr(index.NullsDistinct)
 	indexNode.StorageParams = maps.Clone(index.StorageParams)
+	indexNode.Invisible = index.Visible != nil && !*index.Visible
 
 	// Set unique constraint
 	if index.Unique {
@@ -1853,7 +2040,9 @@ func cloneBoolPtr(value *bool) *bool {
 	return &clone
 }
 
-func toASTIndexParts(parts []goschema.IndexPart) []ast.IndexPart {
+// ToASTIndexParts converts parsed index key parts (columns, expressions,
+// ordering, prefixes) into their AST representation.
+func ToASTIndexParts(parts []goschema.IndexPart) []ast.IndexPart {
 	astParts := make([]ast.IndexPart, 0, len(parts))
 	for _, part := range parts {
 		astParts = append(astParts, ast.IndexPart{
@@ -1884,13 +2073,15 @@ func indexFields(index goschema.Index) []string {
 
 // ParseForeignKeyReference parses a foreign key reference string into an ast.ForeignKeyRef.
 //
-// The foreign key reference string should be in the format "table(column)" or just "table"
-// (which defaults to referencing the "id" column).
+// The foreign key reference string should be in the format "table(column)",
+// "table(col1,col2)" for a composite key, or just "table" (which defaults to
+// referencing the "id" column).
 //
 // Examples:
 //   - "users(id)" -> references users.id
 //   - "users" -> references users.id (default)
 //   - "categories(slug)" -> references categories.slug
+//   - "users(tenant_id,id)" -> references users (tenant_id, id)
 //
 // Returns nil if the reference string is malformed.
 func ParseForeignKeyReference(foreign string) *ast.ForeignKeyRef {
@@ -1913,11 +2104,24 @@ func ParseForeignKeyReference(foreign string) *ast.ForeignKeyRef {
 		if !strings.HasSuffix(columnPart, ")") {
 			return nil
 		}
-		column := strings.TrimSuffix(columnPart, ")")
+		columns := strings.Split(strings.TrimSuffix(columnPart, ")"), ",")
+		for i, column := range columns {
+			columns[i] = strings.TrimSpace(column)
+			if columns[i] == "" {
+				return nil
+			}
+		}
+		if len(columns) > 1 {
+			return &ast.ForeignKeyRef{
+				Table:   table,
+				Column:  columns[0],
+				Columns: columns,
+			}
+		}
 
 		return &ast.ForeignKeyRef{
 			Table:  table,
-			Column: column,
+			Column: columns[0],
 		}
 	}
 
@@ -2107,29 +2311,7 @@ func processEmbeddedInlineModeRecursive(generatedFields []goschema.Field, embedd
 
 func processEmbeddedJSONMode(generatedFields []goschema.Field, embedded goschema.EmbeddedField, structName string) []goschema.Field {
 	// JSON MODE: Serialize embedded struct into a single JSON/JSONB column
-	columnName := embedded.Name
-	if columnName == "" {
-		// Auto-generate column name: "Meta" -> "meta_data"
-		columnName = strings.ToLower(embedded.EmbeddedTypeName) + "_data"
-	}
-
-	columnType := embedded.Type
-	if columnType == "" {
-		columnType = "JSONB" // Default to PostgreSQL JSONB for best performance
-	}
-
-	// Create the JSON column field
-	generatedFields = append(generatedFields, goschema.Field{
-		StructName: structName,
-		FieldName:  embedded.EmbeddedTypeName,
-		Name:       columnName,
-		Type:       columnType,
-		Nullable:   embedded.Nullable,
-		Comment:    embedded.Comment,
-		Overrides:  embedded.Overrides, // Platform-specific type overrides (JSON vs JSONB vs TEXT)
-	})
-
-	return generatedFields
+	return append(generatedFields, embedded.JSONColumn(structName))
 }
 
 func processEmbeddedRelationMode(generatedFields []goschema.Field, embedded goschema.EmbeddedField, structName string) []goschema.Field {
@@ -2139,31 +2321,17 @@ func processEmbeddedRelationMode(generatedFields []goschema.Field, embedded gosc
 		return generatedFields
 	}
 
-	// Intelligent type inference based on reference pattern
-	refType := "INTEGER" // Default assumption: numeric primary key
-	if strings.Contains(embedded.Ref, "VARCHAR") || strings.Contains(embedded.Ref, "TEXT") ||
-		strings.Contains(strings.ToLower(embedded.Ref), "uuid") {
-		// Reference suggests string-based key (likely UUID)
-		refType = "VARCHAR(36)" // Standard UUID length
-	}
+	refType, overrides := embedded.RelationColumnType()
 
 	// Generate automatic foreign key constraint name following convention
 	foreignKeyName := GenerateForeignKeyName(structName, embedded.Field)
 
-	// Create platform-specific overrides for MySQL/MariaDB compatibility
-	// MySQL/MariaDB use INT for SERIAL types, so foreign keys should also use INT
-	overrides := make(map[string]map[string]string)
-	if refType == "INTEGER" {
-		overrides["mysql"] = map[string]string{"type": "INT"}
-		overrides["mariadb"] = map[string]string{"type": "INT"}
-	}
-
 	// Create the foreign key field
 	generatedFields = append(generatedFields, goschema.Field{
 		StructName:     structName,
 		FieldName:      embedded.EmbeddedTypeName,
 		Name:           embedded.Field,    // e.g., "user_id"
-		Type:           refType,           // INTEGER or VARCHAR(36)
+		Type:           refType,           // type of the referenced column, e.g. BIGINT or UUID
 		Nullable:       embedded.Nullable, // Can the relationship be optional?
 		Foreign:        embedded.Ref,      // e.g., "users(id)"
 		ForeignKeyName: foreignKeyName,    // e.g., "fk_posts_user_id"
@@ -146,6 +146,15 @@ func indexStatementIndexByName(statements *ast.StatementList, name string) int {
 	return -1
 }
 
+func materializedViewStatementIndex(statements *ast.StatementList) int {
+	for i, stmt := range statements.Statements {
+		if _, ok := stmt.(*ast.CreateMaterializedViewNode); ok {
+			return i
+		}
+	}
+	return -1
+}
+
 func foreignKeyAlterStatementIndexByName(statements *ast.StatementList, constraintName string) int {
 	for i, stmt := range statements.Statements {
 		alter, ok := stmt.(*ast.AlterTableNode)
@@ -401,6 +410,62 @@ func TestFromField_DefaultValues(t *testing.T) {
 	}
 }
 
+func TestFromField_PostgreSQLEnumDefaultIsCast(t *testing.T) {
+	enums := []goschema.Enum{{Name: "enum_user_status", Values: []string{"active", "it's"}}}
+	tests := []struct {
+		name           string
+		field          goschema.Field
+		targetPlatform string
+		wantValue      string
+		wantExpression string
+	}{
+		{
+			name:           "postgres enum default",
+			field:          goschema.Field{Name: "status", Type: "enum_user_status", Default: "active"},
+			targetPlatform: "postgres",
+			wantExpression: "'active'::enum_user_status",
+		},
+		{
+			name:           "postgres quoted enum default",
+			field:          goschema.Field{Name: "status", Type: "enum_user_status", Default: "'active'"},
+			targetPlatform: "postgres",
+			wantExpression: "'active'::enum_user_status",
+		},
+		{
+			name:           "postgres enum default with quote",
+			field:          goschema.Field{Name: "status", Type: "enum_user_status", Default: "it's"},
+			targetPlatform: "postgres",
+			wantExpression: "'it''s'::enum_user_status",
+		},
+		{
+			name:           "postgres non-enum default",
+			field:          goschema.Field{Name: "status", Type: "TEXT", Default: "active"},
+			targetPlatform: "postgres",
+			wantValue:      "active",
+		},
+		{
+			name:           "mysql inline enum default",
+			field:          goschema.Field{Name: "status", Type: "enum_user_status", Default: "active"},
+			targetPlatform: "mysql",
+			wantValue:      "active",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := qt.New(t)
+			for _, column := range []*ast.ColumnNode{
+				fromschema.FromField(test.field, enums, test.targetPlatform),
+				fromschema.FromFieldWithoutForeignKeys(test.field, enums, test.targetPlatform),
+			} {
+				c.Assert(column.Default, qt.IsNotNil)
+				c.Assert(column.Default.Value, qt.Equals, test.wantValue)
+				c.Assert(column.Default.Expression, qt.Equals, test.wantExpression)
+			}
+		})
+	}
+}
+
 func TestFromField_ForeignKeys(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -544,6 +609,24 @@ func TestFromField_EnumConversion(t *testing.T) {
 			targetPlatform: "mariadb",
 			expectedType:   "ENUM('active', 'inactive', 'suspended')",
 		},
+		{
+			name: "MariaDB maps JSONB to JSON",
+			field: goschema.Field{
+				Name: "payload",
+				Type: "JSONB",
+			},
+			targetPlatform: "mariadb",
+			expectedType:   "JSON",
+		},
+		{
+			name: "MySQL leaves JSONB unchanged",
+			field: goschema.Field{
+				Name: "payload",
+				Type: "JSONB",
+			},
+			targetPlatform: "mysql",
+			expectedType:   "JSONB",
+		},
 		{
 			name: "SQLite converts enum to text with check",
 			field: goschema.Field{
@@ -1083,6 +1166,31 @@ func TestFromDatabase_UniqueIndexesPrecedeForeignKeys(t *testing.T) {
 	c.Assert(foreignKey < nonUniqueIndex, qt.IsTrue)
 }
 
+func TestFromDatabase_MaterializedViewIndexesFollowTheView(t *testing.T) {
+	c := qt.New(t)
+
+	db := goschema.Database{
+		Tables: []goschema.Table{{StructName: "Order", Name: "orders"}},
+		Fields: []goschema.Field{{StructName: "Order", Name: "user_id", Type: "INTEGER"}},
+		MaterializedViews: []goschema.MaterializedView{{
+			StructName: "UserStats",
+			Name:       "user_stats",
+			Body:       "SELECT user_id, COUNT(*) AS cnt FROM orders GROUP BY user_id",
+		}},
+		Indexes: []goschema.Index{
+			{StructName: "UserStats", Name: "idx_user_stats_user", Fields: []string{"user_id"}, Unique: true},
+		},
+	}
+
+	result := fromschema.FromDatabase(db, "postgres")
+
+	viewIndex := materializedViewStatementIndex(result)
+	uniqueIndex := indexStatementIndexByName(result, "idx_user_stats_user")
+	c.Assert(viewIndex, qt.Not(qt.Equals), -1)
+	c.Assert(uniqueIndex > viewIndex, qt.IsTrue)
+	c.Assert(result.Statements[uniqueIndex].(*ast.IndexNode).Table, qt.Equals, "user_stats")
+}
+
 func TestFromDatabase_SQLiteForeignKeysAreInline(t *testing.T) {
 	c := qt.New(t)
 
@@ -1396,6 +1504,30 @@ func TestFromConstraint_UniqueNullsNotDistinct(t *testing.T) {
 	c.Assert(*node.NullsDistinct, qt.IsFalse)
 }
 
+func TestParseForeignKeyReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		foreign string
+		want    *ast.ForeignKeyRef
+	}{
+		{name: "table only", foreign: "users", want: &ast.ForeignKeyRef{Table: "users", Column: "id"}},
+		{name: "single column", foreign: "users(email)", want: &ast.ForeignKeyRef{Table: "users", Column: "email"}},
+		{
+			name:    "composite columns",
+			foreign: "users(tenant_id, id)",
+			want:    &ast.ForeignKeyRef{Table: "users", Column: "tenant_id", Columns: []string{"tenant_id", "id"}},
+		},
+		{name: "empty column", foreign: "users(tenant_id,)", want: nil},
+		{name: "empty", foreign: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(fromschema.ParseForeignKeyReference(tt.foreign), qt.DeepEquals, tt.want)
+		})
+	}
+}
+
 func TestFromEnum_BasicEnum(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -2374,6 +2506,39 @@ func TestFromDatabase_EmbeddedFields_JsonModeDoesNotDuplicateAlreadyProcessedFie
 	c.Assert(countColumns(table, "metadata"), qt.Equals, 1)
 }
 
+func TestFromDatabase_EmbeddedFields_JsonModeCarriesDefaultExpression(t *testing.T) {
+	c := qt.New(t)
+	database := goschema.Database{
+		Tables: []goschema.Table{{
+			StructName: "Profile",
+			Name:       "profiles",
+		}},
+		Fields: []goschema.Field{{
+			StructName: "Profile",
+			Name:       "id",
+			Type:       "SERIAL",
+			Primary:    true,
+		}},
+		EmbeddedFields: []goschema.EmbeddedField{{
+			StructName:       "Profile",
+			Mode:             "json",
+			Name:             "settings",
+			Nullable:         true,
+			DefaultExpr:      "'{}'::jsonb",
+			EmbeddedTypeName: "Settings",
+		}},
+	}
+
+	statements := fromschema.FromDatabase(database, "postgres")
+	table := tableStatementByName(statements, "profiles")
+	c.Assert(table, qt.IsNotNil)
+	c.Assert(table.Columns, qt.HasLen, 2)
+	c.Assert(table.Columns[1].Name, qt.Equals, "settings")
+	c.Assert(table.Columns[1].Nullable, qt.IsTrue)
+	c.Assert(table.Columns[1].Default, qt.IsNotNil)
+	c.Assert(table.Columns[1].Default.Expression, qt.Equals, "'{}'::jsonb")
+}
+
 func TestFromDatabase_EmbeddedFields_RelationMode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,85 @@
+package fromschema_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/internal/convert/fromschema"
+)
+
+func TestFromField_UUIDPrimaryKeyDefaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		overrides      map[string]map[string]string
+		defaultExpr    string
+		targetPlatform string
+		wantType       string
+		wantDefault    string
+	}{
+		{
+			name:           "postgres keeps the native type and generator",
+			defaultExpr:    "gen_random_uuid()",
+			targetPlatform: "postgres",
+			wantType:       "UUID",
+			wantDefault:    "gen_random_uuid()",
+		},
+		{
+			name:           "mysql stores text uuids",
+			defaultExpr:    "gen_random_uuid()",
+			targetPlatform: "mysql",
+			wantType:       "CHAR(36)",
+			wantDefault:    "(UUID())",
+		},
+		{
+			name:           "mysql binary strategy",
+			overrides:      map[string]map[string]string{"mysql": {"type": "BINARY(16)"}},
+			defaultExpr:    "uuid_generate_v4()",
+			targetPlatform: "mysql",
+			wantType:       "BINARY(16)",
+			wantDefault:    "(UUID_TO_BIN(UUID()))",
+		},
+		{
+			name:           "mariadb keeps its native uuid type",
+			defaultExpr:    "gen_random_uuid()",
+			targetPlatform: "mariadb",
+			wantType:       "UUID",
+			wantDefault:    "(UUID())",
+		},
+		{
+			name:           "mariadb binary strategy without uuid_to_bin",
+			overrides:      map[string]map[string]string{"mariadb": {"type": "BINARY(16)"}},
+			defaultExpr:    "gen_random_uuid()",
+			targetPlatform: "mariadb",
+			wantType:       "BINARY(16)",
+			wantDefault:    "(UNHEX(REPLACE(UUID(), '-', '')))",
+		},
+		{
+			name:           "explicit platform default expression wins",
+			overrides:      map[string]map[string]string{"mysql": {"default_expr": "(UUID_TO_BIN(UUID(), 1))"}},
+			defaultExpr:    "gen_random_uuid()",
+			targetPlatform: "mysql",
+			wantType:       "CHAR(36)",
+			wantDefault:    "(UUID_TO_BIN(UUID(), 1))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			column := fromschema.FromField(goschema.Field{
+				Name:        "id",
+				Type:        "UUID",
+				Primary:     true,
+				DefaultExpr: tt.defaultExpr,
+				Overrides:   tt.overrides,
+			}, nil, tt.targetPlatform)
+
+			c.Assert(column.Type, qt.Equals, tt.wantType)
+			c.Assert(column.Default, qt.IsNotNil)
+			c.Assert(column.Default.Expression, qt.Equals, tt.wantDefault)
+		})
+	}
+}
@@ -288,6 +288,7 @@ func (ctx *renderContext) writeGlobalObjects(w *sourceWriter) {
 			attr{name: "name", value: view.Name, set: true},
 			attr{name: "body", value: view.Body, set: true},
 			attr{name: "refresh_strategy", value: view.RefreshStrategy, set: view.RefreshStrategy != ""},
+			attr{name: "with_data", value: "false", set: view.WithNoData},
 			attr{name: "comment", value: view.Comment, set: view.Comment != ""},
 		))
 	}
@@ -371,10 +372,14 @@ func tableAnnotation(table goschema.Table) string {
 		attr{name: "name", value: table.Name, set: true},
 		attr{name: "schema", value: table.Schema, set: table.Schema != ""},
 		attr{name: "engine", value: table.Engine, set: table.Engine != ""},
+		attr{name: "auto_increment", value: table.AutoIncrement, set: table.AutoIncrement != ""},
 		attr{name: "charset", value: table.Charset, set: table.Charset != ""},
 		attr{name: "collate", value: table.Collate, set: table.Collate != ""},
 		attr{name: "primary_key", value: strings.Join(table.PrimaryKey, ","), set: len(table.PrimaryKey) > 0},
+		attr{name: "inherits", value: strings.Join(table.Inherits, ","), set: len(table.Inherits) > 0},
+		attr{name: "external_columns", value: strings.Join(table.ExternalColumns, ","), set: len(table.ExternalColumns) > 0},
 		attr{name: "comment", value: table.Comment, set: table.Comment != ""},
+		attr{name: "owner", value: table.Owner, set: table.Owner != ""},
 	)
 }
 
@@ -397,10 +402,12 @@ func fieldAttrs(field goschema.Field) []attr {
 		{name: "foreign_key_name", value: field.ForeignKeyName, set: field.ForeignKeyName != ""},
 		{name: "on_delete", value: field.OnDelete, set: field.OnDelete != ""},
 		{name: "on_update", value: field.OnUpdate, set: field.OnUpdate != ""},
+		{name: "on_update", value: field.UpdateExpression, set: field.UpdateExpression != "" && field.OnUpdate == ""},
 		{name: "check", value: field.Check, set: field.Check != ""},
 		{name: "check_name", value: field.CheckName, set: field.CheckName != ""},
 		{name: "generated", value: field.GeneratedExpression, set: field.GeneratedExpression != ""},
 		{name: "generated_kind", value: field.GeneratedKind, set: field.GeneratedKind != ""},
+		{name: "type_cast", value: field.TypeCast, set: field.TypeCast != ""},
 		{name: "comment", value: field.Comment, set: field.Comment != ""},
 	}
 }
@@ -412,13 +419,47 @@ func indexAttrs(index goschema.Index) []attr {
 		{name: "unique", value: strconv.FormatBool(index.Unique), set: index.Unique},
 		{name: "type", value: index.Type, set: index.Type != ""},
 		{name: "condition", value: index.Condition, set: index.Condition != ""},
-		{name: "ops", value: index.Operator, set: index.Operator != ""},
+		{name: "ops", value: indexOpsValue(index), set: indexOpsValue(index) != ""},
 		{name: "table", value: index.TableName, set: index.TableName != ""},
 		{name: "granularity", value: strconv.Itoa(index.Granularity), set: index.Granularity > 0},
+		{name: "storage", value: storageParamsValue(index.StorageParams), set: len(index.StorageParams) > 0},
+		{name: "visible", value: "false", set: index.Visible != nil && !*index.Visible},
 		{name: "comment", value: index.Comment, set: index.Comment != ""},
 	}
 }
 
+// indexOpsValue formats the index operator classes as the ops attribute
+// expects: the index-wide class, or one entry per key part when the parts
+// carry their own.
+func indexOpsValue(index goschema.Index) string {
+	if index.Operator != "" {
+		return index.Operator
+	}
+	opClasses := make([]string, 0, len(index.Parts))
+	for _, part := range index.Parts {
+		opClasses = append(opClasses, part.Operator)
+	}
+	if strings.Trim(strings.Join(opClasses, ","), ",") == "" {
+		return ""
+	}
+	return strings.Join(opClasses, ",")
+}
+
+// storageParamsValue formats index storage parameters as the sorted
+// key=value list the storage attribute accepts.
+func storageParamsValue(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]string, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, key+"="+params[key])
+	}
+	return strings.Join(entries, ",")
+}
+
 func constraintAnnotation(constraint goschema.Constraint) string {
 	return annotation("migrator:schema:constraint",
 		attr{name: "name", value: constraint.Name, set: true},
@@ -480,11 +521,13 @@ func roleAnnotation(role goschema.Role) string {
 	return annotation("migrator:schema:role",
 		attr{name: "name", value: role.Name, set: true},
 		attr{name: "login", value: strconv.FormatBool(role.Login), set: role.Login},
+		attr{name: "password_env", value: role.PasswordEnv, set: role.PasswordEnv != ""},
 		attr{name: "superuser", value: strconv.FormatBool(role.Superuser), set: role.Superuser},
 		attr{name: "create_db", value: strconv.FormatBool(role.CreateDB), set: role.CreateDB},
 		attr{name: "create_role", value: strconv.FormatBool(role.CreateRole), set: role.CreateRole},
 		attr{name: "inherit", value: strconv.FormatBool(role.Inherit), set: !role.Inherit},
 		attr{name: "replication", value: strconv.FormatBool(role.Replication), set: role.Replication},
+		attr{name: "member_of", value: strings.Join(role.MemberOf, ","), set: len(role.MemberOf) > 0},
 		attr{name: "comment", value: role.Comment, set: role.Comment != ""},
 	)
 }
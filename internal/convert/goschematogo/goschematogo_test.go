@@ -39,12 +39,13 @@ func TestRenderPerTableFilesRoundTripThroughParser(t *testing.T) {
 				Default:    "'active'",
 			},
 			{
-				StructName:  "OrderItem",
-				FieldName:   "CreatedAt",
-				Name:        "created_at",
-				Type:        "TIMESTAMPTZ",
-				Nullable:    false,
-				DefaultExpr: "now()",
+				StructName:       "OrderItem",
+				FieldName:        "CreatedAt",
+				Name:             "created_at",
+				Type:             "TIMESTAMPTZ",
+				Nullable:         false,
+				DefaultExpr:      "now()",
+				UpdateExpression: "CURRENT_TIMESTAMP",
 			},
 		},
 		Enums: []goschema.Enum{{
@@ -133,6 +134,8 @@ func TestRenderPerTableFilesRoundTripThroughParser(t *testing.T) {
 	c.Assert(parsed.Tables[0].Name, qt.Equals, "order_items")
 	c.Assert(parsed.Tables[0].PrimaryKey, qt.DeepEquals, []string{"tenant_id", "order_id"})
 	c.Assert(parsed.Fields, qt.HasLen, 3)
+	c.Assert(parsed.Fields[2].UpdateExpression, qt.Equals, "CURRENT_TIMESTAMP")
+	c.Assert(parsed.Fields[2].OnUpdate, qt.Equals, "")
 	c.Assert(parsed.Indexes, qt.HasLen, 1)
 	c.Assert(parsed.Indexes[0].Condition, qt.Equals, "status <> 'inactive'")
 	c.Assert(parsed.Constraints, qt.DeepEquals, db.Constraints)
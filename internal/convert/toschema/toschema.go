@@ -43,6 +43,7 @@ package toschema
 
 import (
 	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -248,12 +249,16 @@ func ToTable(table *ast.CreateTableNode, sourcePlatform string) goschema.Table {
 		Name:       table.Name,
 		Comment:    table.Comment,
 		Partition:  toSchemaPartition(table.Partition),
+		Inherits:   slices.Clone(table.Inherits),
 	}
 
 	// Extract ENGINE option if present
 	if engine, exists := table.Options["ENGINE"]; exists {
 		tableSchema.Engine = engine
 	}
+	if autoIncrement, exists := table.Options["AUTO_INCREMENT"]; exists {
+		tableSchema.AutoIncrement = autoIncrement
+	}
 	if strict, exists := table.Options["STRICT"]; exists {
 		tableSchema.Strict, _ = strconv.ParseBool(strict)
 	}
@@ -391,6 +396,7 @@ func ToIndex(index *ast.IndexNode) goschema.Index {
 		IncludeColumns: index.IncludeColumns,
 		NullsDistinct:  cloneBoolPtr(index.NullsDistinct),
 		StorageParams:  maps.Clone(index.StorageParams),
+		Visible:        indexVisible(index.Invisible),
 		TableName:      index.Table,
 	}
 }
@@ -635,6 +641,16 @@ func ToConstraint(constraint *ast.ConstraintNode, structName, tableName string)
 	}
 }
 
+// indexVisible maps an invisible AST index to an explicit visible=false;
+// visible indexes keep the unspecified default.
+func indexVisible(invisible bool) *bool {
+	if !invisible {
+		return nil
+	}
+	visible := false
+	return &visible
+}
+
 func cloneBoolPtr(value *bool) *bool {
 	if value == nil {
 		return nil
@@ -65,6 +65,12 @@ func (w *Writer) ExecuteSQL(ctx context.Context, sqlExpr string, args ...any) er
 }
 
 func (w *Writer) BeginTransaction(ctx context.Context) (types.SchemaTransaction, error) {
+	return w.BeginTransactionWithOptions(ctx, nil)
+}
+
+// BeginTransactionWithOptions starts a transaction with opts, such as an
+// isolation level, and returns a transaction-scoped writer.
+func (w *Writer) BeginTransactionWithOptions(ctx context.Context, opts *sql.TxOptions) (types.SchemaTransaction, error) {
 	if w.dryRun {
 		slog.Info("[DRY RUN] Would begin transaction")
 		return &transactionWriter{dryRun: true}, nil
@@ -72,7 +78,7 @@ func (w *Writer) BeginTransaction(ctx context.Context) (types.SchemaTransaction,
 	if w.db == nil {
 		return nil, fmt.Errorf("no database connection")
 	}
-	tx, err := w.db.BeginTx(ctx, nil)
+	tx, err := w.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
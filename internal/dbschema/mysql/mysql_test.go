@@ -12,6 +12,7 @@ import (
 	qt "github.com/frankban/quicktest"
 	mysqldriver "github.com/go-sql-driver/mysql"
 
+	"github.com/stokaro/ptah/core/platform/capability"
 	"github.com/stokaro/ptah/core/sqlutil"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/internal/dbschema/dbtest"
@@ -158,6 +159,27 @@ func TestMySQLReader_ReadSchema_NoConnection(t *testing.T) {
 	// This is expected behavior - the reader requires a valid database connection
 }
 
+func TestMySQLReaderSkipsInternalSchemas(t *testing.T) {
+	for _, dbName := range []string{"mysql", "performance_schema", "sys", "information_schema"} {
+		t.Run(dbName, func(t *testing.T) {
+			c := qt.New(t)
+			db := dbtest.Open(t, func(_ string, _ []driver.NamedValue) (dbtest.QueryResult, error) {
+				return dbtest.QueryResult{
+					Columns: []string{"DATABASE()"},
+					Rows:    [][]driver.Value{{dbName}},
+				}, nil
+			})
+			reader := NewMySQLReader(db.SQL, dbName)
+
+			schema, err := reader.ReadSchema()
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(schema.Tables, qt.HasLen, 0)
+			c.Assert(db.QueryCount(), qt.Equals, 1)
+		})
+	}
+}
+
 func TestMySQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 	c := qt.New(t)
 
@@ -166,10 +188,12 @@ func TestMySQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 	for i := range 50 {
 		tableName := fmt.Sprintf("table_%02d", i)
 		comment := ""
+		autoIncrement := int64(0)
 		if i == 0 {
 			comment = "customer accounts"
+			autoIncrement = 1000
 		}
-		tableRows = append(tableRows, []driver.Value{tableName, "BASE TABLE", comment})
+		tableRows = append(tableRows, []driver.Value{tableName, "BASE TABLE", comment, autoIncrement})
 		columnRows = append(columnRows,
 			[]driver.Value{tableName, "id", "int", "int", "NO", nil, nil, int64(10), int64(0), int64(1), nil, nil, "auto_increment", nil},
 			[]driver.Value{tableName, "email", "varchar", "varchar(255)", "NO", nil, int64(255), nil, nil, int64(2), "utf8mb4", "utf8mb4_0900_ai_ci", "", nil},
@@ -201,7 +225,7 @@ func TestMySQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 			}, nil
 		case strings.Contains(query, "FROM information_schema.TABLES"):
 			return dbtest.QueryResult{
-				Columns: []string{"TABLE_NAME", "TABLE_TYPE", "TABLE_COMMENT"},
+				Columns: []string{"TABLE_NAME", "TABLE_TYPE", "TABLE_COMMENT", "AUTO_INCREMENT"},
 				Rows:    tableRows,
 			}, nil
 		default:
@@ -217,6 +241,7 @@ func TestMySQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 	c.Assert(tables, qt.HasLen, 50)
 	c.Assert(tables[0].Name, qt.Equals, "table_00")
 	c.Assert(tables[0].Comment, qt.Equals, "customer accounts")
+	c.Assert(tables[0].AutoIncrement, qt.Equals, int64(1000))
 	c.Assert(tables[0].Columns, qt.HasLen, 3)
 
 	id := tables[0].Columns[0]
@@ -240,6 +265,161 @@ func TestMySQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 	c.Assert(*emailLC.GeneratedExpression, qt.Equals, "lower(`email`)")
 }
 
+// mariaDBSequenceQueries answers the catalog listing and the per-sequence
+// option read for a single order_numbers sequence.
+func mariaDBSequenceQueries(query string, _ []driver.NamedValue) (dbtest.QueryResult, error) {
+	switch {
+	case strings.Contains(query, "TABLE_TYPE = 'SEQUENCE'"):
+		return dbtest.QueryResult{
+			Columns: []string{"TABLE_NAME", "TABLE_COMMENT"},
+			Rows:    [][]driver.Value{{"order_numbers", "invoice numbering"}},
+		}, nil
+	case strings.Contains(query, "FROM `order_numbers`"):
+		return dbtest.QueryResult{
+			Columns: []string{"start_value", "minimum_value", "maximum_value", "increment", "cache_size", "cycle_option"},
+			Rows:    [][]driver.Value{{int64(1000), int64(1), int64(99999), int64(5), int64(20), int64(1)}},
+		}, nil
+	default:
+		return dbtest.QueryResult{}, fmt.Errorf("unexpected query: %s", query)
+	}
+}
+
+func TestMySQLReaderReadSequences(t *testing.T) {
+	c := qt.New(t)
+
+	db := dbtest.Open(t, mariaDBSequenceQueries)
+	reader := NewMySQLReaderWithCapabilities(db.SQL, "app", capability.MariaDB1011())
+
+	sequences, err := reader.readSequences("app")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sequences, qt.HasLen, 1)
+	sequence := sequences[0]
+	c.Assert(sequence.Name, qt.Equals, "order_numbers")
+	c.Assert(sequence.Comment, qt.Equals, "invoice numbering")
+	c.Assert(sequence.DataType, qt.Equals, "bigint")
+	c.Assert(*sequence.Start, qt.Equals, int64(1000))
+	c.Assert(*sequence.MinValue, qt.Equals, int64(1))
+	c.Assert(*sequence.MaxValue, qt.Equals, int64(99999))
+	c.Assert(*sequence.Increment, qt.Equals, int64(5))
+	c.Assert(*sequence.Cache, qt.Equals, int64(20))
+	c.Assert(sequence.Cycle, qt.IsTrue)
+}
+
+func TestMySQLReaderReadIndexesIncludesFunctionalKeyParts(t *testing.T) {
+	c := qt.New(t)
+
+	// readIndexes probes for STATISTICS.EXPRESSION and the visibility column
+	// first and then reads the index rows, so the fake answers the three
+	// queries in order.
+	results := []dbtest.QueryResult{
+		{Columns: []string{"COUNT(*)"}, Rows: [][]driver.Value{{int64(1)}}},
+		{Columns: []string{"COLUMN_NAME"}},
+		{
+			Columns: []string{"INDEX_NAME", "TABLE_NAME", "COLUMNS", "NON_UNIQUE", "INDEX_TYPE", "VISIBLE"},
+			Rows: [][]driver.Value{
+				{"idx_users_email", "users", "email", int64(1), "BTREE", int64(1)},
+				{"idx_users_tenant_email", "users", "tenant_id\x1f(lower(`email`))", int64(0), "BTREE", int64(1)},
+			},
+		},
+	}
+	var queries []string
+	db := dbtest.Open(t, func(query string, _ []driver.NamedValue) (dbtest.QueryResult, error) {
+		queries = append(queries, query)
+		return results[len(queries)-1], nil
+	})
+	reader := NewMySQLReader(db.SQL, "app")
+
+	indexes, err := reader.readIndexes("app")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(queries, qt.HasLen, 3)
+	c.Assert(queries[0], qt.Contains, "COLUMN_NAME = 'EXPRESSION'")
+	c.Assert(queries[2], qt.Contains, "COALESCE(s.COLUMN_NAME, CONCAT('(', s.EXPRESSION, ')'))")
+	c.Assert(queries[2], qt.Contains, "MIN(1) as VISIBLE")
+	c.Assert(indexes, qt.HasLen, 2)
+	c.Assert(indexes[0].Columns, qt.DeepEquals, []string{"email"})
+	c.Assert(indexes[1].Columns, qt.DeepEquals, []string{"tenant_id", "(lower(`email`))"})
+	c.Assert(indexes[1].IsUnique, qt.IsTrue)
+	c.Assert(indexes[1].Definition, qt.Equals, "BTREE INDEX idx_users_tenant_email ON users (tenant_id, (lower(`email`)))")
+}
+
+func TestMySQLReaderReadIndexesReportsIndexType(t *testing.T) {
+	c := qt.New(t)
+
+	results := []dbtest.QueryResult{
+		{Columns: []string{"COUNT(*)"}, Rows: [][]driver.Value{{int64(1)}}},
+		{Columns: []string{"COLUMN_NAME"}},
+		{
+			Columns: []string{"INDEX_NAME", "TABLE_NAME", "COLUMNS", "NON_UNIQUE", "INDEX_TYPE", "VISIBLE"},
+			Rows: [][]driver.Value{
+				{"ft_posts_body", "posts", "body", int64(1), "FULLTEXT", int64(1)},
+				{"idx_posts_author", "posts", "author_id", int64(1), "BTREE", int64(1)},
+				{"idx_posts_slug", "posts", "slug", int64(1), "HASH", int64(1)},
+				{"sp_posts_location", "posts", "location", int64(1), "SPATIAL", int64(1)},
+			},
+		},
+	}
+	var queries []string
+	db := dbtest.Open(t, func(query string, _ []driver.NamedValue) (dbtest.QueryResult, error) {
+		queries = append(queries, query)
+		return results[len(queries)-1], nil
+	})
+	reader := NewMySQLReader(db.SQL, "app")
+
+	indexes, err := reader.readIndexes("app")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(indexes, qt.HasLen, 4)
+	c.Assert(indexes[0].Type, qt.Equals, "FULLTEXT")
+	c.Assert(indexes[1].Type, qt.Equals, "")
+	c.Assert(indexes[2].Type, qt.Equals, "HASH")
+	c.Assert(indexes[3].Type, qt.Equals, "SPATIAL")
+}
+
+func TestMySQLReaderReadIndexesReportsVisibility(t *testing.T) {
+	tests := []struct {
+		name          string
+		probeColumn   string
+		wantPredicate string
+	}{
+		{name: "mysql", probeColumn: "IS_VISIBLE", wantPredicate: "s.IS_VISIBLE = 'NO'"},
+		{name: "mariadb", probeColumn: "IGNORED", wantPredicate: "s.IGNORED = 'YES'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			results := []dbtest.QueryResult{
+				{Columns: []string{"COUNT(*)"}, Rows: [][]driver.Value{{int64(0)}}},
+				{Columns: []string{"COLUMN_NAME"}, Rows: [][]driver.Value{{tt.probeColumn}}},
+				{
+					Columns: []string{"INDEX_NAME", "TABLE_NAME", "COLUMNS", "NON_UNIQUE", "INDEX_TYPE", "VISIBLE"},
+					Rows: [][]driver.Value{
+						{"idx_users_email", "users", "email", int64(1), "BTREE", int64(1)},
+						{"idx_users_legacy", "users", "legacy_id", int64(1), "BTREE", int64(0)},
+					},
+				},
+			}
+			var queries []string
+			db := dbtest.Open(t, func(query string, _ []driver.NamedValue) (dbtest.QueryResult, error) {
+				queries = append(queries, query)
+				return results[len(queries)-1], nil
+			})
+			reader := NewMySQLReader(db.SQL, "app")
+
+			indexes, err := reader.readIndexes("app")
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(queries, qt.HasLen, 3)
+			c.Assert(queries[2], qt.Contains, tt.wantPredicate)
+			c.Assert(indexes, qt.HasLen, 2)
+			c.Assert(indexes[0].Invisible, qt.IsFalse)
+			c.Assert(indexes[1].Invisible, qt.IsTrue)
+		})
+	}
+}
+
 func TestEnhanceTablesWithPrimaryKeys(t *testing.T) {
 	c := qt.New(t)
 
@@ -291,6 +471,30 @@ func TestApplyMySQLColumnMetadataKeepsGeneratedExpressionWithoutExtra(t *testing
 	c.Assert(*col.ColumnDefault, qt.Equals, "default")
 }
 
+func TestApplyMySQLColumnMetadataReadsOnUpdateFromExtra(t *testing.T) {
+	tests := []struct {
+		name     string
+		extra    string
+		expected string
+	}{
+		{"mysql", "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)"},
+		{"mariadb", "on update current_timestamp()", "current_timestamp()"},
+		{"auto increment", "auto_increment", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			var col types.DBColumn
+			applyMySQLColumnMetadata(&col, sql.NullString{}, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{},
+				sql.NullString{}, sql.NullString{}, sql.NullString{String: tt.extra, Valid: true}, sql.NullString{})
+
+			c.Assert(col.UpdateExpression, qt.Equals, tt.expected)
+		})
+	}
+}
+
 func TestNormalizeMySQLColumnDefaultQuotesCatalogStringLiterals(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -682,3 +886,54 @@ func TestQuoteIdent(t *testing.T) {
 		})
 	}
 }
+
+func TestMySQLReaderReadConstraintsAddsCatalogOnlyChecks(t *testing.T) {
+	c := qt.New(t)
+
+	// readConstraints reads the CHECK clauses first and then the
+	// TABLE_CONSTRAINTS rows. The MariaDB column-level check "price" is only
+	// listed in CHECK_CONSTRAINTS.
+	results := []dbtest.QueryResult{
+		{
+			Columns: []string{"CONSTRAINT_NAME", "TABLE_NAME", "CHECK_CLAUSE"},
+			Rows: [][]driver.Value{
+				{"price", "products", "`price` > 0"},
+				{"products_stock_check", "products", "`stock` >= 0"},
+				{"version_check", "schema_migrations", "`version` > 0"},
+			},
+		},
+		{
+			Columns: []string{"CONSTRAINT_NAME", "TABLE_NAME", "CONSTRAINT_TYPE", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "DELETE_RULE", "UPDATE_RULE"},
+			Rows: [][]driver.Value{
+				{"PRIMARY", "products", "PRIMARY KEY", "id", "", "", "", ""},
+				{"products_stock_check", "products", "CHECK", "", "", "", "", ""},
+			},
+		},
+	}
+	var queries []string
+	db := dbtest.Open(t, func(query string, _ []driver.NamedValue) (dbtest.QueryResult, error) {
+		queries = append(queries, query)
+		return results[len(queries)-1], nil
+	})
+	reader := NewMySQLReader(db.SQL, "app")
+
+	constraints, err := reader.readConstraints("app")
+
+	c.Assert(err, qt.IsNil)
+	checks := map[string]string{}
+	for _, constraint := range constraints {
+		checks[constraint.Name] = constraint.Type + " " + stringValue(constraint.CheckClause)
+	}
+	c.Assert(checks, qt.DeepEquals, map[string]string{
+		"PRIMARY":              "PRIMARY KEY ",
+		"price":                "CHECK `price` > 0",
+		"products_stock_check": "CHECK `stock` >= 0",
+	})
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	mysqldriver "github.com/go-sql-driver/mysql"
 
+	"github.com/stokaro/ptah/core/platform/capability"
 	"github.com/stokaro/ptah/dbschema/types"
 )
 
@@ -15,6 +18,9 @@ import (
 type Reader struct {
 	db     *sql.DB
 	schema string
+	caps   capability.Capabilities
+
+	includeSystem bool
 }
 
 type checkConstraintClauses struct {
@@ -24,13 +30,37 @@ type checkConstraintClauses struct {
 
 // NewMySQLReader creates a new MySQL schema reader
 func NewMySQLReader(db *sql.DB, schema string) *Reader {
+	return NewMySQLReaderWithCapabilities(db, schema, capability.MySQL80())
+}
+
+// NewMySQLReaderWithCapabilities creates a MySQL-family schema reader whose
+// MariaDB-only catalog reads (sequences) are gated by target capabilities.
+func NewMySQLReaderWithCapabilities(db *sql.DB, schema string, caps capability.Capabilities) *Reader {
 	if schema == "" {
 		schema = "information_schema"
 	}
 	return &Reader{
 		db:     db,
 		schema: schema,
+		caps:   caps,
+	}
+}
+
+// SetIncludeSystemRelations controls whether the internal schemas (mysql,
+// performance_schema, sys, information_schema) and MariaDB temporary tables
+// are introspected. They are skipped by default.
+func (r *Reader) SetIncludeSystemRelations(include bool) {
+	r.includeSystem = include
+}
+
+// isSystemSchema reports whether dbName is one of the server's internal
+// schemas, which never hold application tables.
+func isSystemSchema(dbName string) bool {
+	switch strings.ToLower(dbName) {
+	case "mysql", "performance_schema", "sys", "information_schema":
+		return true
 	}
+	return false
 }
 
 // ReadSchema reads the complete schema from MySQL/MariaDB
@@ -43,6 +73,9 @@ func (r *Reader) ReadSchema() (*types.DBSchema, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database name: %w", err)
 	}
+	if !r.includeSystem && isSystemSchema(dbName) {
+		return schema, nil
+	}
 
 	// Read tables
 	tables, err := r.readTables(dbName)
@@ -84,6 +117,14 @@ func (r *Reader) ReadSchema() (*types.DBSchema, error) {
 	}
 	schema.Triggers = triggers
 
+	if r.caps.Has(capability.Sequences) {
+		sequences, err := r.readSequences(dbName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sequences: %w", err)
+		}
+		schema.Sequences = sequences
+	}
+
 	// Reconcile per-column flags after all catalog metadata is loaded.
 	// information_schema.KEY_COLUMN_USAGE carries primary-key membership, and
 	// information_schema.STATISTICS (NON_UNIQUE) is authoritative for unique
@@ -105,14 +146,14 @@ func (r *Reader) readTables(dbName string) ([]types.DBTable, error) {
 	}
 
 	query := `
-		SELECT TABLE_NAME, TABLE_TYPE, COALESCE(TABLE_COMMENT, '')
+		SELECT TABLE_NAME, TABLE_TYPE, COALESCE(TABLE_COMMENT, ''), COALESCE(AUTO_INCREMENT, 0)
 		FROM information_schema.TABLES
 		WHERE TABLE_SCHEMA = ?
-		AND TABLE_TYPE = 'BASE TABLE'
+		AND (TABLE_TYPE = 'BASE TABLE' OR (? AND TABLE_TYPE = 'TEMPORARY'))
 		AND TABLE_NAME NOT IN ('schema_migrations')
 		ORDER BY TABLE_NAME`
 
-	rows, err := r.db.Query(query, dbName)
+	rows, err := r.db.Query(query, dbName, r.includeSystem)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +162,7 @@ func (r *Reader) readTables(dbName string) ([]types.DBTable, error) {
 	var tables []types.DBTable
 	for rows.Next() {
 		var table types.DBTable
-		if err := rows.Scan(&table.Name, &table.Type, &table.Comment); err != nil {
+		if err := rows.Scan(&table.Name, &table.Type, &table.Comment, &table.AutoIncrement); err != nil {
 			return nil, err
 		}
 		table.Columns = columnsByTable[table.Name]
@@ -255,6 +296,7 @@ func applyMySQLColumnMetadata(
 		case strings.Contains(extraValue, "virtual generated"):
 			col.GeneratedKind = "VIRTUAL"
 		}
+		col.UpdateExpression = mysqlUpdateExpression(extra.String)
 	}
 	if generatedExpression.Valid && generatedExpression.String != "" {
 		expression := generatedExpression.String
@@ -262,6 +304,18 @@ func applyMySQLColumnMetadata(
 	}
 }
 
+// mysqlUpdateExpression returns the ON UPDATE expression from an EXTRA value.
+// MySQL reports "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(6)" and
+// MariaDB "on update current_timestamp()"; the expression always comes last.
+func mysqlUpdateExpression(extra string) string {
+	const marker = "on update "
+	idx := strings.Index(strings.ToLower(extra), marker)
+	if idx < 0 {
+		return ""
+	}
+	return strings.TrimSpace(extra[idx+len(marker):])
+}
+
 func normalizeMySQLColumnDefault(col *types.DBColumn, defaultValue string) string {
 	value := strings.TrimSpace(defaultValue)
 	if value == "" || isQuotedMySQLDefault(value) || !mysqlDefaultNeedsLiteralQuotes(col, value) {
@@ -398,6 +452,50 @@ func (r *Reader) readViews(dbName string) ([]types.DBView, error) {
 	return views, nil
 }
 
+// readSequences reads MariaDB sequences. information_schema lists them as
+// TABLE_TYPE 'SEQUENCE' but exposes no options, so each sequence's single
+// row is selected directly. MariaDB sequences are always BIGINT.
+func (r *Reader) readSequences(dbName string) ([]types.DBSequence, error) {
+	rows, err := r.db.Query(`
+		SELECT TABLE_NAME, COALESCE(TABLE_COMMENT, '')
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'SEQUENCE'
+		ORDER BY TABLE_NAME`, dbName)
+	if err != nil {
+		return nil, err
+	}
+	var sequences []types.DBSequence
+	for rows.Next() {
+		sequence := types.DBSequence{DataType: "bigint"}
+		if err := rows.Scan(&sequence.Name, &sequence.Comment); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		sequences = append(sequences, sequence)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range sequences {
+		sequence := &sequences[i]
+		var start, minValue, maxValue, increment, cache int64
+		query := "SELECT start_value, minimum_value, maximum_value, increment, cache_size, cycle_option FROM " + quoteIdent(sequence.Name)
+		if err := r.db.QueryRow(query).Scan(&start, &minValue, &maxValue, &increment, &cache, &sequence.Cycle); err != nil {
+			return nil, fmt.Errorf("sequence %s: %w", sequence.Name, err)
+		}
+		sequence.Start = &start
+		sequence.MinValue = &minValue
+		sequence.MaxValue = &maxValue
+		sequence.Increment = &increment
+		sequence.Cache = &cache
+	}
+	return sequences, nil
+}
+
 func (r *Reader) readTriggers(dbName string) ([]types.DBTrigger, error) {
 	query := `
 		SELECT
@@ -481,15 +579,35 @@ func (r *Reader) readEnums(dbName string) ([]types.DBEnum, error) {
 	return enums, nil
 }
 
+// indexKeySeparator joins index key parts in GROUP_CONCAT. Functional key
+// parts may contain commas, so the ASCII unit separator is used instead.
+const indexKeySeparator = "\x1f"
+
 // readIndexes reads all indexes
 func (r *Reader) readIndexes(dbName string) ([]types.DBIndex, error) {
+	// MySQL 8.0.13+ reports functional key parts with a NULL COLUMN_NAME and
+	// the expression in STATISTICS.EXPRESSION. MariaDB and older MySQL
+	// releases have no such column, so probe for it before referencing it.
+	keyPart := "s.COLUMN_NAME"
+	hasExpression, err := r.statisticsHasExpressionColumn()
+	if err != nil {
+		return nil, err
+	}
+	if hasExpression {
+		keyPart = "COALESCE(s.COLUMN_NAME, CONCAT('(', s.EXPRESSION, ')'))"
+	}
+	visible, err := r.statisticsVisibleExpr()
+	if err != nil {
+		return nil, err
+	}
 	query := `
 		SELECT
 			s.INDEX_NAME,
 			s.TABLE_NAME,
-			GROUP_CONCAT(s.COLUMN_NAME ORDER BY s.SEQ_IN_INDEX) as COLUMNS,
+			GROUP_CONCAT(` + keyPart + ` ORDER BY s.SEQ_IN_INDEX SEPARATOR '` + indexKeySeparator + `') as COLUMNS,
 			s.NON_UNIQUE,
-			s.INDEX_TYPE
+			s.INDEX_TYPE,
+			MIN(` + visible + `) as VISIBLE
 		FROM information_schema.STATISTICS s
 		WHERE s.TABLE_SCHEMA = ?
 		AND s.TABLE_NAME NOT IN ('schema_migrations')
@@ -508,16 +626,19 @@ func (r *Reader) readIndexes(dbName string) ([]types.DBIndex, error) {
 		var columnsStr string
 		var nonUnique int
 		var indexType string
+		var visible int
 
-		err := rows.Scan(&index.Name, &index.TableName, &columnsStr, &nonUnique, &indexType)
+		err := rows.Scan(&index.Name, &index.TableName, &columnsStr, &nonUnique, &indexType, &visible)
 		if err != nil {
 			return nil, err
 		}
 
-		index.Columns = strings.Split(columnsStr, ",")
+		index.Columns = strings.Split(columnsStr, indexKeySeparator)
 		index.IsUnique = nonUnique == 0
 		index.IsPrimary = index.Name == "PRIMARY"
-		index.Definition = fmt.Sprintf("%s INDEX %s ON %s (%s)", indexType, index.Name, index.TableName, columnsStr)
+		index.Type = mysqlIndexType(indexType)
+		index.Invisible = visible == 0
+		index.Definition = fmt.Sprintf("%s INDEX %s ON %s (%s)", indexType, index.Name, index.TableName, strings.Join(index.Columns, ", "))
 
 		indexes = append(indexes, index)
 	}
@@ -525,6 +646,67 @@ func (r *Reader) readIndexes(dbName string) ([]types.DBIndex, error) {
 	return indexes, nil
 }
 
+// mysqlIndexType maps STATISTICS.INDEX_TYPE onto DBIndex.Type. BTREE is the
+// default access method and is reported as an empty type so that ordinary
+// indexes read back the same way they are declared; HASH, FULLTEXT and
+// SPATIAL are kept in upper case.
+func mysqlIndexType(indexType string) string {
+	indexType = strings.ToUpper(strings.TrimSpace(indexType))
+	if indexType == "BTREE" {
+		return ""
+	}
+	return indexType
+}
+
+func (r *Reader) statisticsHasExpressionColumn() (bool, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = 'information_schema'
+		AND TABLE_NAME = 'STATISTICS'
+		AND COLUMN_NAME = 'EXPRESSION'`).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("probe information_schema.STATISTICS.EXPRESSION: %w", err)
+	}
+	return count > 0, nil
+}
+
+// statisticsVisibleExpr returns a per-key-part expression that is 1 for a
+// visible index and 0 otherwise. MySQL 8.0 reports STATISTICS.IS_VISIBLE,
+// MariaDB 10.6+ reports STATISTICS.IGNORED, and older servers have neither
+// column, so every index reads as visible there.
+func (r *Reader) statisticsVisibleExpr() (string, error) {
+	rows, err := r.db.Query(`
+		SELECT COLUMN_NAME
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = 'information_schema'
+		AND TABLE_NAME = 'STATISTICS'
+		AND COLUMN_NAME IN ('IS_VISIBLE', 'IGNORED')`)
+	if err != nil {
+		return "", fmt.Errorf("probe information_schema.STATISTICS visibility: %w", err)
+	}
+	defer rows.Close()
+
+	expr := "1"
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return "", fmt.Errorf("probe information_schema.STATISTICS visibility: %w", err)
+		}
+		switch strings.ToUpper(column) {
+		case "IS_VISIBLE":
+			expr = "CASE WHEN s.IS_VISIBLE = 'NO' THEN 0 ELSE 1 END"
+		case "IGNORED":
+			expr = "CASE WHEN s.IGNORED = 'YES' THEN 0 ELSE 1 END"
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("probe information_schema.STATISTICS visibility: %w", err)
+	}
+	return expr, nil
+}
+
 // readConstraints reads all constraints
 func (r *Reader) readConstraints(dbName string) ([]types.DBConstraint, error) {
 	checkClauses, err := r.readCheckConstraintClauses(dbName)
@@ -616,6 +798,7 @@ func (r *Reader) readConstraints(dbName string) ([]types.DBConstraint, error) {
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	addCatalogOnlyCheckConstraints(constraintMap, checkClauses)
 
 	// Convert map to slice
 	var constraints []types.DBConstraint
@@ -657,6 +840,24 @@ func newConstraint(name, tableName, constraintType string, refs constraintRefs,
 	return constraint
 }
 
+// addCatalogOnlyCheckConstraints adds the CHECK constraints listed in
+// information_schema.CHECK_CONSTRAINTS but missing from TABLE_CONSTRAINTS,
+// such as MariaDB column-level checks (named after their column) on servers
+// that leave them out of TABLE_CONSTRAINTS. MySQL's CHECK_CONSTRAINTS has no
+// TABLE_NAME, so there the table is unknown and nothing is added.
+func addCatalogOnlyCheckConstraints(constraintMap map[string]*types.DBConstraint, checkClauses checkConstraintClauses) {
+	for _, key := range slices.Sorted(maps.Keys(checkClauses.byTableName)) {
+		if _, exists := constraintMap[key]; exists {
+			continue
+		}
+		tableName, constraintName, _ := strings.Cut(key, ".")
+		if tableName == "schema_migrations" {
+			continue
+		}
+		constraintMap[key] = newConstraint(constraintName, tableName, "CHECK", constraintRefs{}, checkClauses)
+	}
+}
+
 func (c checkConstraintClauses) forConstraint(tableName, constraintName string) string {
 	if checkClause := c.byTableName[tableName+"."+constraintName]; checkClause != "" {
 		return checkClause
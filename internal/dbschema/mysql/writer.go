@@ -65,6 +65,12 @@ func (w *Writer) ExecuteSQL(ctx context.Context, sqlExpr string, args ...any) er
 // BeginTransaction starts a transaction and returns a transaction-scoped
 // writer. The parent writer keeps no active transaction state.
 func (w *Writer) BeginTransaction(ctx context.Context) (types.SchemaTransaction, error) {
+	return w.BeginTransactionWithOptions(ctx, nil)
+}
+
+// BeginTransactionWithOptions starts a transaction with opts, such as an
+// isolation level, and returns a transaction-scoped writer.
+func (w *Writer) BeginTransactionWithOptions(ctx context.Context, opts *sql.TxOptions) (types.SchemaTransaction, error) {
 	if w.dryRun {
 		slog.Info("[DRY RUN] Would begin transaction")
 		return &transactionWriter{schema: w.schema, dryRun: true}, nil
@@ -73,7 +79,7 @@ func (w *Writer) BeginTransaction(ctx context.Context) (types.SchemaTransaction,
 		return nil, fmt.Errorf("no database connection")
 	}
 
-	tx, err := w.db.BeginTx(ctx, nil)
+	tx, err := w.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
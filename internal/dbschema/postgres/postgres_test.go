@@ -180,6 +180,45 @@ func TestParsePostgresIndexColumnsJSONPreservesExpressionCommas(t *testing.T) {
 	c.Assert(columns, qt.DeepEquals, []string{"concat(first_name, last_name)", "tenant_id"})
 }
 
+func TestParsePostgresStorageParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{name: "no options", value: "[]", expected: nil},
+		{name: "fillfactor", value: `["fillfactor=70"]`, expected: map[string]string{"fillfactor": "70"}},
+		{
+			name:     "several options",
+			value:    `["fillfactor=90","deduplicate_items=off"]`,
+			expected: map[string]string{"fillfactor": "90", "deduplicate_items": "off"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			params, err := parsePostgresStorageParams(test.value)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(params, qt.DeepEquals, test.expected)
+		})
+	}
+}
+
+func TestParsePostgresIndexOperatorClasses(t *testing.T) {
+	c := qt.New(t)
+
+	opClasses, err := parsePostgresIndexOperatorClasses(`[{"name":"text_ops","default":true},{"name":"gin_trgm_ops","default":false}]`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(opClasses, qt.DeepEquals, []types.DBIndexOperatorClass{{Name: "text_ops", Default: true}, {Name: "gin_trgm_ops"}})
+
+	opClasses, err = parsePostgresIndexOperatorClasses("[]")
+	c.Assert(err, qt.IsNil)
+	c.Assert(opClasses, qt.IsNil)
+}
+
 func TestPostgreSQLReader_ReadSchema_NoConnection(t *testing.T) {
 	c := qt.New(t)
 
@@ -200,10 +239,10 @@ func TestPostgreSQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 	columnRows := make([][]driver.Value, 0, 100)
 	for i := range 50 {
 		tableName := fmt.Sprintf("table_%02d", i)
-		tableRows = append(tableRows, []driver.Value{"public", tableName, "BASE TABLE", "", int64(0), false})
+		tableRows = append(tableRows, []driver.Value{"public", tableName, "BASE TABLE", "", "app_owner", int64(0), false, ""})
 		columnRows = append(columnRows,
-			[]driver.Value{tableName, "id", "integer", "int4", "NO", nil, nil, nil, nil, int64(1), "", ""},
-			[]driver.Value{tableName, "name", "character varying", "varchar", "NO", nil, int64(255), nil, nil, int64(2), "", ""},
+			[]driver.Value{tableName, "id", "integer", "int4", "NO", nil, nil, nil, nil, int64(1), "", "", false, "", "BY DEFAULT", "1000", "5"},
+			[]driver.Value{tableName, "name", "character varying", "varchar", "NO", nil, int64(255), nil, nil, int64(2), "", "", false, "display name", "", "", ""},
 		)
 	}
 
@@ -224,6 +263,11 @@ func TestPostgreSQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 					"ordinal_position",
 					"generated_kind",
 					"generated_expression",
+					"inherited",
+					"column_comment",
+					"identity_generation",
+					"identity_start",
+					"identity_increment",
 				},
 				Rows: columnRows,
 			}, nil
@@ -234,8 +278,10 @@ func TestPostgreSQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 					"table_name",
 					"table_type",
 					"table_comment",
+					"table_owner",
 					"estimated_rows",
 					"rls_enabled",
+					"inherits",
 				},
 				Rows: tableRows,
 			}, nil
@@ -251,9 +297,81 @@ func TestPostgreSQLReaderReadTablesUsesBulkColumnQuery(t *testing.T) {
 	c.Assert(db.QueryCount(), qt.Equals, 2)
 	c.Assert(tables, qt.HasLen, 50)
 	c.Assert(tables[0].Name, qt.Equals, "table_00")
+	c.Assert(tables[0].Owner, qt.Equals, "app_owner")
 	c.Assert(tables[0].Columns, qt.HasLen, 2)
 	c.Assert(tables[0].Columns[1].CharacterMaxLength, qt.IsNotNil)
 	c.Assert(*tables[0].Columns[1].CharacterMaxLength, qt.Equals, 255)
+	c.Assert(tables[0].Columns[1].Comment, qt.Equals, "display name")
+	c.Assert(tables[0].Columns[0].IdentityGeneration, qt.Equals, "BY_DEFAULT")
+	c.Assert(tables[0].Columns[0].IsAutoIncrement, qt.IsTrue)
+	c.Assert(tables[0].Columns[0].IdentityStart, qt.Equals, "1000")
+	c.Assert(tables[0].Columns[0].IdentityIncrement, qt.Equals, "5")
+	c.Assert(tables[0].Columns[1].IdentityIncrement, qt.Equals, "")
+}
+
+func TestPostgreSQLReaderInheritedParents(t *testing.T) {
+	tests := []struct {
+		name     string
+		schemas  []string
+		inherits string
+		expected []string
+	}{
+		{name: "no parents", inherits: ""},
+		{name: "default schema parent", inherits: "public.cities", expected: []string{"cities"}},
+		{name: "other schema parent", inherits: "public.cities,geo.regions", expected: []string{"cities", "geo.regions"}},
+		{name: "scoped reader", schemas: []string{"public", "geo"}, inherits: "geo.regions,public.cities", expected: []string{"geo.regions", "cities"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			reader := NewPostgreSQLReader(nil, "public")
+			reader.SetSchemas(tt.schemas)
+
+			c.Assert(reader.inheritedParents(tt.inherits), qt.DeepEquals, tt.expected)
+		})
+	}
+}
+
+func TestPostgreSQLReaderSkipsSystemSchemas(t *testing.T) {
+	c := qt.New(t)
+	reader := NewPostgreSQLReader(nil, "public")
+	reader.SetSchemas([]string{"public", "pg_temp_3", "pg_toast_temp_3", "pg_toast", "pg_catalog", "information_schema", "billing"})
+
+	c.Assert(reader.schemasToRead(), qt.DeepEquals, []string{"public", "billing"})
+
+	reader.SetIncludeSystemRelations(true)
+
+	c.Assert(reader.schemasToRead(), qt.HasLen, 7)
+}
+
+func TestPostgreSQLReaderTablesQueryFiltersTemporaryRelations(t *testing.T) {
+	tests := []struct {
+		name          string
+		includeSystem bool
+	}{
+		{name: "default", includeSystem: false},
+		{name: "include system relations", includeSystem: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			var tablesArgs []driver.NamedValue
+			db := dbtest.Open(t, func(_ string, args []driver.NamedValue) (dbtest.QueryResult, error) {
+				tablesArgs = append(tablesArgs, args...)
+				return dbtest.QueryResult{}, nil
+			})
+			reader := NewPostgreSQLReader(db.SQL, "public")
+			reader.SetIncludeSystemRelations(tt.includeSystem)
+
+			_, err := reader.readTablesForSchema("public")
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(tablesArgs[len(tablesArgs)-1].Value, qt.Equals, tt.includeSystem)
+		})
+	}
 }
 
 func TestPostgreSQLReaderReadSchemasSkipsMissingScopedSchema(t *testing.T) {
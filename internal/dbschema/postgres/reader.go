@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/stokaro/ptah/core/platform/capability"
@@ -18,6 +19,8 @@ type Reader struct {
 	schemas []string
 	scoped  bool
 	caps    capability.Capabilities
+
+	includeSystem bool
 }
 
 // NewPostgreSQLReader creates a new PostgreSQL schema reader
@@ -45,8 +48,30 @@ func (r *Reader) SetSchemas(schemas []string) {
 	r.scoped = len(schemas) > 0
 }
 
+// SetIncludeSystemRelations controls whether temporary tables and relations
+// in system schemas (pg_catalog, information_schema, pg_toast, pg_temp_*) are
+// introspected. They are skipped by default.
+func (r *Reader) SetIncludeSystemRelations(include bool) {
+	r.includeSystem = include
+}
+
 func (r *Reader) schemasToRead() []string {
-	return normalizeSchemas(r.schemas, r.schema)
+	schemas := normalizeSchemas(r.schemas, r.schema)
+	if r.includeSystem {
+		return schemas
+	}
+	return slices.DeleteFunc(schemas, isSystemSchema)
+}
+
+// isSystemSchema reports whether schemaName is a PostgreSQL catalog, TOAST or
+// per-session temporary schema. Temporary schemas of other sessions are
+// visible in pg_namespace and must never be diffed against the Go schema.
+func isSystemSchema(schemaName string) bool {
+	switch schemaName {
+	case "pg_catalog", "information_schema", "pg_toast":
+		return true
+	}
+	return strings.HasPrefix(schemaName, "pg_temp_") || strings.HasPrefix(schemaName, "pg_toast_temp_")
 }
 
 func normalizeSchemas(schemas []string, fallback string) []string {
@@ -249,22 +274,36 @@ func (r *Reader) readTablesForSchema(schemaName string) ([]types.DBTable, error)
 		return nil, fmt.Errorf("failed to read columns for schema %s: %w", schemaName, err)
 	}
 
-	// Read tables, excluding system tables like schema_migrations
+	// Read tables, excluding system tables like schema_migrations and, unless
+	// system relations are included, temporary and non-table relations
 	tablesQuery := `
 		SELECT table_schema, table_name, table_type,
 		       COALESCE(obj_description(c.oid), '') as table_comment,
+		       COALESCE(pg_get_userbyid(c.relowner)::text, '') AS table_owner,
 		       COALESCE(GREATEST(c.reltuples::bigint, st.n_live_tup, 0), 0) AS estimated_rows,
-		       COALESCE(c.relrowsecurity, false) AS rls_enabled
+		       COALESCE(c.relrowsecurity, false) AS rls_enabled,
+		       COALESCE((
+		           SELECT string_agg(pn.nspname || '.' || p.relname, ',' ORDER BY i.inhseqno)
+		           FROM pg_inherits i
+		           JOIN pg_class p ON p.oid = i.inhparent
+		           JOIN pg_namespace pn ON pn.oid = p.relnamespace
+		           WHERE i.inhrelid = c.oid AND NOT c.relispartition
+		       ), '') AS inherits
 			FROM information_schema.tables t
 			LEFT JOIN pg_namespace n ON n.nspname = t.table_schema
 			LEFT JOIN pg_class c ON c.relname = t.table_name AND c.relnamespace = n.oid
 			LEFT JOIN pg_stat_all_tables st ON st.relid = c.oid
 			WHERE t.table_schema = $1
-			AND t.table_type = 'BASE TABLE'
+			AND (t.table_type = 'BASE TABLE' OR ($2 AND t.table_type = 'LOCAL TEMPORARY'))
+			AND ($2 OR (c.relkind IN ('r', 'p') AND c.relpersistence <> 't'))
 			AND t.table_name NOT IN ('schema_migrations')
+			AND NOT EXISTS (
+				SELECT 1 FROM pg_matviews mv
+				WHERE mv.schemaname = t.table_schema AND mv.matviewname = t.table_name
+			)
 			ORDER BY table_schema, table_name`
 
-	rows, err := r.db.Query(tablesQuery, schemaName)
+	rows, err := r.db.Query(tablesQuery, schemaName, r.includeSystem)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -273,10 +312,12 @@ func (r *Reader) readTablesForSchema(schemaName string) ([]types.DBTable, error)
 	var tables []types.DBTable
 	for rows.Next() {
 		var table types.DBTable
-		err := rows.Scan(&table.Schema, &table.Name, &table.Type, &table.Comment, &table.EstimatedRows, &table.RLSEnabled)
+		var inherits string
+		err := rows.Scan(&table.Schema, &table.Name, &table.Type, &table.Comment, &table.Owner, &table.EstimatedRows, &table.RLSEnabled, &inherits)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan table: %w", err)
 		}
+		table.Inherits = r.inheritedParents(inherits)
 		table.Schema = r.outputSchema(table.Schema)
 		table.Columns = columnsByTable[table.Name]
 
@@ -286,6 +327,27 @@ func (r *Reader) readTablesForSchema(schemaName string) ([]types.DBTable, error)
 	return tables, nil
 }
 
+// inheritedParents converts the comma-separated schema.table list of INHERITS
+// parents into DBTable.Inherits. Parents are qualified the same way tables
+// are: unqualified in the default schema, schema-qualified elsewhere.
+// Declarative partitions are filtered out by the query (relispartition),
+// since they also appear in pg_inherits.
+func (r *Reader) inheritedParents(inherits string) []string {
+	if inherits == "" {
+		return nil
+	}
+	var parents []string
+	for _, parent := range strings.Split(inherits, ",") {
+		parentSchema, parentName, _ := strings.Cut(parent, ".")
+		schema := r.outputSchema(parentSchema)
+		if !r.scoped && parentSchema != r.schema {
+			schema = parentSchema
+		}
+		parents = append(parents, types.QualifyTableName(schema, parentName))
+	}
+	return parents
+}
+
 // readColumnsForSchema reads all columns in a schema in one catalog query and
 // groups them by table name.
 func (r *Reader) readColumnsForSchema(schemaName string) (map[string][]types.DBColumn, error) {
@@ -302,7 +364,12 @@ func (r *Reader) readColumnsForSchema(schemaName string) (map[string][]types.DBC
 			numeric_scale,
 			ordinal_position,
 			COALESCE(a.attgenerated, '') AS generated_kind,
-			COALESCE(CASE WHEN a.attgenerated <> '' THEN pg_get_expr(ad.adbin, ad.adrelid) ELSE '' END, '') AS generated_expression
+			COALESCE(CASE WHEN a.attgenerated <> '' THEN pg_get_expr(ad.adbin, ad.adrelid) ELSE '' END, '') AS generated_expression,
+			COALESCE(NOT a.attislocal, false) AS inherited,
+			COALESCE(col_description(cls.oid, a.attnum), '') AS column_comment,
+			COALESCE(col.identity_generation, '') AS identity_generation,
+			COALESCE(col.identity_start, '') AS identity_start,
+			COALESCE(col.identity_increment, '') AS identity_increment
 		FROM information_schema.columns col
 		JOIN pg_namespace n ON n.nspname = col.table_schema
 		JOIN pg_class cls ON cls.relname = col.table_name AND cls.relnamespace = n.oid
@@ -325,6 +392,9 @@ func (r *Reader) readColumnsForSchema(schemaName string) (map[string][]types.DBC
 		var col types.DBColumn
 		var generatedKind string
 		var generatedExpression string
+		var identityGeneration string
+		var identityStart string
+		var identityIncrement string
 		var tableName string
 		err := rows.Scan(
 			&tableName,
@@ -339,6 +409,11 @@ func (r *Reader) readColumnsForSchema(schemaName string) (map[string][]types.DBC
 			&col.OrdinalPosition,
 			&generatedKind,
 			&generatedExpression,
+			&col.Inherited,
+			&col.Comment,
+			&identityGeneration,
+			&identityStart,
+			&identityIncrement,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan column: %w", err)
@@ -348,12 +423,20 @@ func (r *Reader) readColumnsForSchema(schemaName string) (map[string][]types.DBC
 			col.GeneratedKind = postgresGeneratedKind(generatedKind)
 		}
 
-		// Detect auto increment (SERIAL types)
+		// Detect auto increment (SERIAL types and identity columns)
 		if col.ColumnDefault != nil {
 			defaultVal := *col.ColumnDefault
 			col.IsAutoIncrement = strings.Contains(defaultVal, "nextval(") &&
 				strings.Contains(defaultVal, "_seq")
 		}
+		if identityGeneration != "" {
+			// information_schema spells the mode "BY DEFAULT"; the schema model
+			// uses BY_DEFAULT.
+			col.IdentityGeneration = strings.ReplaceAll(identityGeneration, " ", "_")
+			col.IdentityStart = identityStart
+			col.IdentityIncrement = identityIncrement
+			col.IsAutoIncrement = true
+		}
 
 		columnsByTable[tableName] = append(columnsByTable[tableName], col)
 	}
@@ -640,7 +723,14 @@ func (r *Reader) readIndexesForSchema(schemaName string) ([]types.DBIndex, error
 				FROM unnest(ix.indkey) WITH ORDINALITY AS keys(attnum, ordinality)
 				WHERE keys.ordinality <= ix.indnkeyatts
 			), '[]') as index_columns,
+			COALESCE((
+				SELECT json_agg(json_build_object('name', opc.opcname, 'default', opc.opcdefault) ORDER BY keys.ordinality)::text
+				FROM unnest(ix.indclass::oid[]) WITH ORDINALITY AS keys(opclass, ordinality)
+				JOIN pg_opclass opc ON opc.oid = keys.opclass
+				WHERE keys.ordinality <= ix.indnkeyatts
+			), '[]') as index_opclasses,
 			COALESCE(pg_get_expr(ix.indpred, ix.indrelid), '') as predicate,
+			COALESCE(array_to_json(i.reloptions)::text, '[]') as storage_params,
 			ix.indisprimary,
 			ix.indisunique
 		FROM pg_index ix
@@ -659,9 +749,9 @@ func (r *Reader) readIndexesForSchema(schemaName string) ([]types.DBIndex, error
 
 	var indexes []types.DBIndex
 	for rows.Next() {
-		var schemaName, tableName, indexName, indexDef, indexColumns, predicate string
+		var schemaName, tableName, indexName, indexDef, indexColumns, opClasses, predicate, storageParams string
 		var isPrimary, isUnique bool
-		err := rows.Scan(&schemaName, &tableName, &indexName, &indexDef, &indexColumns, &predicate, &isPrimary, &isUnique)
+		err := rows.Scan(&schemaName, &tableName, &indexName, &indexDef, &indexColumns, &opClasses, &predicate, &storageParams, &isPrimary, &isUnique)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan index: %w", err)
 		}
@@ -682,6 +772,14 @@ func (r *Reader) readIndexesForSchema(schemaName string) ([]types.DBIndex, error
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse index columns for %s: %w", indexName, err)
 		}
+		index.StorageParams, err = parsePostgresStorageParams(storageParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse storage parameters for %s: %w", indexName, err)
+		}
+		index.OperatorClasses, err = parsePostgresIndexOperatorClasses(opClasses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse operator classes for %s: %w", indexName, err)
+		}
 
 		indexes = append(indexes, index)
 	}
@@ -700,6 +798,42 @@ func parsePostgresIndexColumns(value, indexDef string) ([]string, error) {
 	return columns, nil
 }
 
+// parsePostgresIndexOperatorClasses decodes the JSON array of per-key
+// operator classes built from pg_index.indclass. It returns nil when the
+// index has no key columns.
+func parsePostgresIndexOperatorClasses(value string) ([]types.DBIndexOperatorClass, error) {
+	var opClasses []types.DBIndexOperatorClass
+	if err := json.Unmarshal([]byte(value), &opClasses); err != nil {
+		return nil, err
+	}
+	if len(opClasses) == 0 {
+		return nil, nil
+	}
+	return opClasses, nil
+}
+
+// parsePostgresStorageParams decodes a JSON array of pg_class.reloptions
+// entries ("fillfactor=70") into a map. It returns nil when no parameters are
+// set.
+func parsePostgresStorageParams(value string) (map[string]string, error) {
+	var options []string
+	if err := json.Unmarshal([]byte(value), &options); err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, nil
+	}
+	params := make(map[string]string, len(options))
+	for _, option := range options {
+		key, val, ok := strings.Cut(option, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed storage parameter %q", option)
+		}
+		params[key] = val
+	}
+	return params, nil
+}
+
 func extractPostgresIndexColumns(indexDef string) []string {
 	start := strings.Index(indexDef, "(")
 	if start == -1 {
@@ -805,6 +939,18 @@ func (r *Reader) readBasicConstraintsForSchema(schemaName string) ([]types.DBCon
 			AND tc.table_schema = cc.constraint_schema
 		WHERE tc.table_schema = $1
 		AND tc.table_name NOT IN ('schema_migrations')
+		-- Constraints a child table inherits from an INHERITS parent belong
+		-- to the parent and are managed there.
+		AND NOT EXISTS (
+			SELECT 1
+			FROM pg_constraint ic
+			JOIN pg_class ic_table ON ic_table.oid = ic.conrelid
+			JOIN pg_namespace ic_schema ON ic_schema.oid = ic_table.relnamespace
+			WHERE ic_schema.nspname = tc.table_schema
+			AND ic_table.relname = tc.table_name
+			AND ic.conname = tc.constraint_name
+			AND NOT ic.conislocal
+		)
 		GROUP BY
 			tc.table_schema,
 			tc.table_name,
@@ -956,6 +1102,7 @@ func (r *Reader) readPostgreSQLConstraintsForSchema(schemaName string) ([]types.
 		JOIN pg_class cl ON c.conrelid = cl.oid
 		JOIN pg_namespace n ON cl.relnamespace = n.oid
 		WHERE c.contype IN ('x')  -- 'x' = exclusion constraint (add more types as needed)
+		AND c.conislocal
 		AND n.nspname = $1
 		AND cl.relname NOT IN ('schema_migrations')
 		ORDER BY cl.relname, c.conname`
@@ -1501,17 +1648,19 @@ func (r *Reader) readMaterializedViews() ([]types.DBMatView, error) {
 }
 
 func (r *Reader) readMaterializedViewsForSchema(schemaName string) ([]types.DBMatView, error) {
+	// pg_matviews is the authoritative list of materialized views; the table
+	// query excludes the same names so a view is never also read as a table.
 	viewsQuery := `
 		SELECT
-			n.nspname AS schema_name,
-			c.relname AS view_name,
+			mv.schemaname AS schema_name,
+			mv.matviewname AS view_name,
 			pg_get_viewdef(c.oid, true) AS view_definition,
 			COALESCE(obj_description(c.oid, 'pg_class'), '') AS comment
-		FROM pg_class c
-		JOIN pg_namespace n ON n.oid = c.relnamespace
-		WHERE n.nspname = $1
-		AND c.relkind = 'm'
-		ORDER BY c.relname`
+		FROM pg_matviews mv
+		JOIN pg_namespace n ON n.nspname = mv.schemaname
+		JOIN pg_class c ON c.relnamespace = n.oid AND c.relname = mv.matviewname
+		WHERE mv.schemaname = $1
+		ORDER BY mv.matviewname`
 
 	rows, err := r.db.Query(viewsQuery, schemaName)
 	if err != nil {
@@ -1615,7 +1764,16 @@ func (r *Reader) readFunctionsForSchema(schemaName string) ([]types.DBFunction,
 				WHEN 'v' THEN 'VOLATILE'
 			END AS volatility,
 			p.prosrc AS body,
-			COALESCE(obj_description(p.oid, 'pg_proc'), '') AS comment
+			COALESCE(obj_description(p.oid, 'pg_proc'), '') AS comment,
+			COALESCE((
+				SELECT json_agg(json_build_object('schema', tn.nspname, 'table', tc.relname, 'name', t.tgname)
+					ORDER BY tn.nspname, tc.relname, t.tgname)::text
+				FROM pg_trigger t
+				JOIN pg_class tc ON tc.oid = t.tgrelid
+				JOIN pg_namespace tn ON tn.oid = tc.relnamespace
+				WHERE t.tgfoid = p.oid
+				AND NOT t.tgisinternal
+			), '[]') AS triggers
 		FROM pg_proc p
 		JOIN pg_namespace n ON n.oid = p.pronamespace
 		JOIN pg_language l ON l.oid = p.prolang
@@ -1641,6 +1799,7 @@ func (r *Reader) readFunctionsForSchema(schemaName string) ([]types.DBFunction,
 	var functions []types.DBFunction
 	for rows.Next() {
 		var fn types.DBFunction
+		var triggers string
 		err := rows.Scan(
 			&fn.Name,
 			&fn.Parameters,
@@ -1650,10 +1809,16 @@ func (r *Reader) readFunctionsForSchema(schemaName string) ([]types.DBFunction,
 			&fn.Volatility,
 			&fn.Body,
 			&fn.Comment,
+			&triggers,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan function: %w", err)
 		}
+		fn.Schema = r.outputSchema(schemaName)
+		fn.Triggers, err = r.parseFunctionTriggers(triggers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse triggers of function %s: %w", fn.Name, err)
+		}
 
 		functions = append(functions, fn)
 	}
@@ -1661,6 +1826,22 @@ func (r *Reader) readFunctionsForSchema(schemaName string) ([]types.DBFunction,
 	return functions, nil
 }
 
+// parseFunctionTriggers decodes the JSON array of triggers executing a
+// function. It returns nil when no trigger uses the function.
+func (r *Reader) parseFunctionTriggers(value string) ([]types.DBFunctionTrigger, error) {
+	var triggers []types.DBFunctionTrigger
+	if err := json.Unmarshal([]byte(value), &triggers); err != nil {
+		return nil, err
+	}
+	if len(triggers) == 0 {
+		return nil, nil
+	}
+	for i := range triggers {
+		triggers[i].Schema = r.outputSchema(triggers[i].Schema)
+	}
+	return triggers, nil
+}
+
 // readRLSPolicies reads all PostgreSQL RLS policies from the database
 func (r *Reader) readRLSPolicies() ([]types.DBRLSPolicy, error) {
 	var policies []types.DBRLSPolicy
@@ -1745,7 +1926,14 @@ func (r *Reader) readRoles() ([]types.DBRole, error) {
 			r.rolinherit AS inherit,
 			r.rolreplication AS replication,
 			COALESCE(a.rolpassword IS NOT NULL AND a.rolpassword != '', false) AS has_password,
-			COALESCE(shobj_description(r.oid, 'pg_authid'), '') AS comment
+			COALESCE(shobj_description(r.oid, 'pg_authid'), '') AS comment,
+			COALESCE(a.rolpassword, '') AS password_hash,
+			COALESCE((
+				SELECT string_agg(g.rolname, ',' ORDER BY g.rolname)
+				FROM pg_auth_members m
+				JOIN pg_roles g ON g.oid = m.roleid
+				WHERE m.member = r.oid
+			), '') AS member_of
 		FROM pg_roles r
 		LEFT JOIN pg_authid a ON r.oid = a.oid
 		WHERE r.rolname NOT LIKE 'pg_%'  -- Exclude system roles
@@ -1761,6 +1949,7 @@ func (r *Reader) readRoles() ([]types.DBRole, error) {
 	var roles []types.DBRole
 	for rows.Next() {
 		var role types.DBRole
+		var memberOf string
 		err := rows.Scan(
 			&role.Name,
 			&role.Login,
@@ -1771,10 +1960,15 @@ func (r *Reader) readRoles() ([]types.DBRole, error) {
 			&role.Replication,
 			&role.HasPassword,
 			&role.Comment,
+			&role.PasswordHash,
+			&memberOf,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan role: %w", err)
 		}
+		if memberOf != "" {
+			role.MemberOf = strings.Split(memberOf, ",")
+		}
 
 		roles = append(roles, role)
 	}
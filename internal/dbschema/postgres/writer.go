@@ -113,6 +113,12 @@ func (w *PostgreSQLWriter) ExecuteSQL(ctx context.Context, sqlExpr string, args
 // BeginTransaction starts a transaction and returns a transaction-scoped
 // writer. The parent writer keeps no active transaction state.
 func (w *PostgreSQLWriter) BeginTransaction(ctx context.Context) (types.SchemaTransaction, error) {
+	return w.BeginTransactionWithOptions(ctx, nil)
+}
+
+// BeginTransactionWithOptions starts a transaction with opts, such as an
+// isolation level, and returns a transaction-scoped writer.
+func (w *PostgreSQLWriter) BeginTransactionWithOptions(ctx context.Context, opts *sql.TxOptions) (types.SchemaTransaction, error) {
 	if w.dryRun {
 		slog.Info("[DRY RUN] Would begin transaction")
 		return &postgresTransactionWriter{schema: w.schema, dryRun: true}, nil
@@ -121,7 +127,7 @@ func (w *PostgreSQLWriter) BeginTransaction(ctx context.Context) (types.SchemaTr
 		return nil, fmt.Errorf("no database connection")
 	}
 
-	tx, err := w.db.BeginTx(ctx, nil)
+	tx, err := w.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -347,14 +347,16 @@ func TestRoundTripGeneratedSchemaThroughSQLite(t *testing.T) {
 type countingSQLiteDB struct {
 	SQL        *sql.DB
 	queryCount *atomic.Int64
+	isolation  *atomic.Int64
 }
 
 func openCountingMemoryDB(t *testing.T) *countingSQLiteDB {
 	t.Helper()
 
 	queryCount := new(atomic.Int64)
+	isolation := new(atomic.Int64)
 	name := "ptah_sqlite_counting_" + strconv.FormatInt(countingSQLiteDriverID.Add(1), 10)
-	sql.Register(name, &countingSQLiteDriver{queryCount: queryCount})
+	sql.Register(name, &countingSQLiteDriver{queryCount: queryCount, isolation: isolation})
 
 	db, err := sql.Open(name, ":memory:?_pragma=foreign_keys(1)")
 	if err != nil {
@@ -362,17 +364,23 @@ func openCountingMemoryDB(t *testing.T) *countingSQLiteDB {
 	}
 	db.SetMaxOpenConns(1)
 	t.Cleanup(func() { _ = db.Close() })
-	return &countingSQLiteDB{SQL: db, queryCount: queryCount}
+	return &countingSQLiteDB{SQL: db, queryCount: queryCount, isolation: isolation}
 }
 
 func (db *countingSQLiteDB) QueryCount() int {
 	return int(db.queryCount.Load())
 }
 
+// IsolationLevel returns the isolation level of the last transaction begun.
+func (db *countingSQLiteDB) IsolationLevel() sql.IsolationLevel {
+	return sql.IsolationLevel(db.isolation.Load())
+}
+
 var countingSQLiteDriverID atomic.Int64
 
 type countingSQLiteDriver struct {
 	queryCount *atomic.Int64
+	isolation  *atomic.Int64
 }
 
 func (d *countingSQLiteDriver) Open(name string) (driver.Conn, error) {
@@ -380,12 +388,13 @@ func (d *countingSQLiteDriver) Open(name string) (driver.Conn, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &countingSQLiteConn{Conn: conn, queryCount: d.queryCount}, nil
+	return &countingSQLiteConn{Conn: conn, queryCount: d.queryCount, isolation: d.isolation}, nil
 }
 
 type countingSQLiteConn struct {
 	driver.Conn
 	queryCount *atomic.Int64
+	isolation  *atomic.Int64
 }
 
 func (c *countingSQLiteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
@@ -410,9 +419,34 @@ func (c *countingSQLiteConn) BeginTx(ctx context.Context, opts driver.TxOptions)
 	if !ok {
 		return nil, driver.ErrSkip
 	}
+	c.isolation.Store(int64(opts.Isolation))
 	return beginner.BeginTx(ctx, opts)
 }
 
+func TestSQLiteWriterBeginTransactionWithOptionsPassesIsolationLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *sql.TxOptions
+		want sql.IsolationLevel
+	}{
+		{name: "nil options", opts: nil, want: sql.LevelDefault},
+		{name: "serializable", opts: &sql.TxOptions{Isolation: sql.LevelSerializable}, want: sql.LevelSerializable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			db := openCountingMemoryDB(t)
+			writer := sqlite.NewSQLiteWriter(db.SQL, "main")
+
+			tx, err := writer.BeginTransactionWithOptions(context.Background(), tt.opts)
+			c.Assert(err, qt.IsNil)
+			c.Assert(db.IsolationLevel(), qt.Equals, tt.want)
+			c.Assert(tx.Rollback(), qt.IsNil)
+		})
+	}
+}
+
 func TestSQLiteWriterConcurrentTransactions(t *testing.T) {
 	c := qt.New(t)
 
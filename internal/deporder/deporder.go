@@ -155,15 +155,35 @@ func TablesForCreate(schema *goschema.Database, tableNames []string) []goschema.
 // TableDropOrder returns table names in child-before-parent order for DROP
 // TABLE operations. Output names match the caller's input spelling.
 func TableDropOrder(tableNames []string, schema *goschema.Database) []string {
+	return TableDropOrderWithDependencies(tableNames, schema, nil)
+}
+
+// TableDropOrderWithDependencies is TableDropOrder with extra child-to-parent
+// edges keyed by input table names, such as the database foreign keys of
+// tables the schema no longer declares.
+func TableDropOrderWithDependencies(tableNames []string, schema *goschema.Database, extra map[string][]string) []string {
 	ordered := append([]string(nil), tableNames...)
-	if schema == nil || len(ordered) < 2 {
+	if (schema == nil && len(extra) == 0) || len(ordered) < 2 {
 		return ordered
 	}
 
+	var tables []goschema.Table
+	dependencies := make(map[string][]string)
+	if schema != nil {
+		tables = schema.Tables
+		dependencies = GeneratedTableDependencies(schema)
+	}
+	for child, parents := range extra {
+		key := resolveTableKey(tables, child)
+		for _, parent := range parents {
+			dependencies[key] = append(dependencies[key], resolveTableKey(tables, parent))
+		}
+	}
+
 	inputByKey := make(map[string]string, len(ordered))
 	keys := make([]string, 0, len(ordered))
 	for _, tableName := range ordered {
-		key := resolveTableKey(schema.Tables, tableName)
+		key := resolveTableKey(tables, tableName)
 		if _, seen := inputByKey[key]; seen {
 			continue
 		}
@@ -171,7 +191,7 @@ func TableDropOrder(tableNames []string, schema *goschema.Database) []string {
 		keys = append(keys, key)
 	}
 
-	orderedKeys := StableReverseDependencySort(keys, GeneratedTableDependencies(schema))
+	orderedKeys := StableReverseDependencySort(keys, dependencies)
 	result := make([]string, 0, len(orderedKeys))
 	for _, key := range orderedKeys {
 		result = append(result, inputByKey[key])
@@ -179,14 +199,80 @@ func TableDropOrder(tableNames []string, schema *goschema.Database) []string {
 	return result
 }
 
+// CyclicNodes returns the nodes that take part in a dependency cycle of two
+// or more nodes, in caller order. No order of such nodes puts every
+// dependency first. Self-dependencies are ignored.
+func CyclicNodes(nodes []string, dependencies map[string][]string) []string {
+	index := indexNodes(nodes)
+	// Tarjan's strongly connected components.
+	order := make(map[string]int, len(nodes))
+	low := make(map[string]int, len(nodes))
+	onStack := make(map[string]bool, len(nodes))
+	var stack []string
+	cyclic := make(map[string]bool)
+	var visit func(string)
+	visit = func(node string) {
+		order[node] = len(order) + 1
+		low[node] = order[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, dep := range dependencies[node] {
+			if _, ok := index[dep]; !ok || dep == node {
+				continue
+			}
+			if order[dep] == 0 {
+				visit(dep)
+				low[node] = min(low[node], low[dep])
+			} else if onStack[dep] {
+				low[node] = min(low[node], order[dep])
+			}
+		}
+		if low[node] != order[node] {
+			return
+		}
+		start := len(stack) - 1
+		for stack[start] != node {
+			start--
+		}
+		component := stack[start:]
+		stack = stack[:start]
+		for _, member := range component {
+			onStack[member] = false
+			if len(component) > 1 {
+				cyclic[member] = true
+			}
+		}
+	}
+	for _, node := range nodes {
+		if order[node] == 0 {
+			visit(node)
+		}
+	}
+
+	var result []string
+	for _, node := range nodes {
+		if cyclic[node] && !slices.Contains(result, node) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
 // GeneratedTableDependencies returns table dependency edges derived from
-// finalized metadata plus inline field and table-level FK definitions.
+// finalized metadata, PostgreSQL INHERITS parents, and inline field and
+// table-level FK definitions.
 func GeneratedTableDependencies(schema *goschema.Database) map[string][]string {
 	dependencies := make(map[string][]string, len(schema.Tables))
 	for _, table := range schema.Tables {
 		dependencies[table.QualifiedName()] = append([]string(nil), schema.Dependencies[table.QualifiedName()]...)
 	}
 
+	for _, table := range schema.Tables {
+		for _, parent := range table.Inherits {
+			addGeneratedTableDependency(dependencies, schema.Tables, table, parent)
+		}
+	}
+
 	for _, field := range schema.Fields {
 		if field.Foreign == "" {
 			continue
@@ -224,6 +310,7 @@ func GeneratedTableDependencies(schema *goschema.Database) map[string][]string {
 }
 
 // FunctionsForCreate returns target functions in dependency order.
+// functionNames are qualified names as recorded in the schema diff.
 func FunctionsForCreate(schema *goschema.Database, functionNames []string) []goschema.Function {
 	if schema == nil || len(functionNames) == 0 {
 		return nil
@@ -237,7 +324,7 @@ func FunctionsForCreate(schema *goschema.Database, functionNames []string) []gos
 	names := make([]string, 0, len(functionNames))
 	for _, fn := range schema.Functions {
 		functionByName[fn.Name] = fn
-		if _, ok := requested[fn.Name]; ok {
+		if _, ok := requested[fn.QualifiedName()]; ok {
 			names = append(names, fn.Name)
 		}
 	}
@@ -91,6 +91,74 @@ func TestTableDropOrder_DerivesForeignKeyDependencies(t *testing.T) {
 	c.Assert(ordered, qt.DeepEquals, []string{"tasks", "projects", "accounts"})
 }
 
+func TestTableDropOrderWithDependencies_UsesDatabaseForeignKeys(t *testing.T) {
+	c := qt.New(t)
+
+	ordered := deporder.TableDropOrderWithDependencies(
+		[]string{"accounts", "projects", "tasks"},
+		&goschema.Database{},
+		map[string][]string{
+			"tasks":    {"projects"},
+			"projects": {"accounts"},
+		},
+	)
+
+	c.Assert(ordered, qt.DeepEquals, []string{"tasks", "projects", "accounts"})
+}
+
+func TestCyclicNodes(t *testing.T) {
+	tests := []struct {
+		name         string
+		nodes        []string
+		dependencies map[string][]string
+		want         []string
+	}{
+		{
+			name:         "acyclic",
+			nodes:        []string{"accounts", "projects", "tasks"},
+			dependencies: map[string][]string{"tasks": {"projects"}, "projects": {"accounts"}},
+		},
+		{
+			name:         "self reference",
+			nodes:        []string{"employees"},
+			dependencies: map[string][]string{"employees": {"employees"}},
+		},
+		{
+			name:         "two node cycle with a dependent outside it",
+			nodes:        []string{"audit", "orders", "invoices"},
+			dependencies: map[string][]string{"audit": {"orders"}, "orders": {"invoices"}, "invoices": {"orders"}},
+			want:         []string{"orders", "invoices"},
+		},
+		{
+			name:         "edges to unknown nodes are ignored",
+			nodes:        []string{"orders", "invoices"},
+			dependencies: map[string][]string{"orders": {"customers"}, "customers": {"orders"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(deporder.CyclicNodes(tt.nodes, tt.dependencies), qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestTablesForCreate_DerivesInheritsDependencies(t *testing.T) {
+	c := qt.New(t)
+	schema := &goschema.Database{
+		Tables: []goschema.Table{
+			{StructName: "Capital", Name: "capitals", Inherits: []string{"cities"}},
+			{StructName: "City", Name: "cities"},
+		},
+	}
+
+	tables := deporder.TablesForCreate(schema, []string{"capitals", "cities"})
+
+	c.Assert(tableNames(tables), qt.DeepEquals, []string{"cities", "capitals"})
+	c.Assert(deporder.TableDropOrder([]string{"cities", "capitals"}, schema), qt.DeepEquals, []string{"capitals", "cities"})
+}
+
 func TestTablesForCreate_ResolvesUnqualifiedForeignKeyWithinCurrentSchema(t *testing.T) {
 	c := qt.New(t)
 	schema := &goschema.Database{
@@ -0,0 +1,177 @@
+package parser_test
+
+import (
+	"cmp"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/core/sqlutil"
+	"github.com/stokaro/ptah/internal/parser"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestParser_ParseDropStatements_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		nodeType string
+		rendered string
+	}{
+		{
+			name:     "index if exists",
+			sql:      "DROP INDEX IF EXISTS idx_users_email;",
+			nodeType: "*ast.DropIndexNode",
+		},
+		{
+			name:     "qualified index",
+			sql:      "DROP INDEX app.idx_users_email RESTRICT;",
+			nodeType: "*ast.DropIndexNode",
+			rendered: "DROP INDEX app.idx_users_email;",
+		},
+		{
+			name:     "mysql index on table",
+			dialect:  platform.MySQL,
+			sql:      "DROP INDEX idx_users_email ON users;",
+			nodeType: "*ast.DropIndexNode",
+		},
+		{
+			name:     "concurrent index stays raw",
+			sql:      "DROP INDEX CONCURRENTLY IF EXISTS idx_users_email;",
+			nodeType: "*ast.RawSQLNode",
+		},
+		{
+			name:     "type if exists cascade",
+			sql:      "DROP TYPE IF EXISTS user_status CASCADE;",
+			nodeType: "*ast.DropTypeNode",
+		},
+		{
+			name:     "domain",
+			sql:      "DROP DOMAIN IF EXISTS app.email_address;",
+			nodeType: "*ast.DropTypeNode",
+		},
+		{
+			name:     "several types stay raw",
+			sql:      "DROP TYPE user_status, order_status;",
+			nodeType: "*ast.RawSQLNode",
+		},
+		{
+			name:     "function without parameters",
+			sql:      "DROP FUNCTION IF EXISTS audit_fn();",
+			nodeType: "*ast.DropFunctionNode",
+		},
+		{
+			name:     "function with parameters cascade",
+			sql:      "DROP FUNCTION IF EXISTS app.set_tenant(tenant_id TEXT, strict BOOLEAN) CASCADE;",
+			nodeType: "*ast.DropFunctionNode",
+		},
+		{
+			name:     "function without parentheses",
+			sql:      "DROP FUNCTION audit_fn;",
+			nodeType: "*ast.DropFunctionNode",
+			rendered: "DROP FUNCTION audit_fn();",
+		},
+		{
+			name:     "policy if exists",
+			sql:      "DROP POLICY IF EXISTS tenant_isolation ON users;",
+			nodeType: "*ast.DropPolicyNode",
+		},
+		{
+			name:     "view cascade",
+			sql:      "DROP VIEW IF EXISTS active_users CASCADE;",
+			nodeType: "*ast.DropViewNode",
+		},
+		{
+			name:     "materialized view",
+			sql:      "DROP MATERIALIZED VIEW IF EXISTS user_stats CASCADE;",
+			nodeType: "*ast.DropMaterializedViewNode",
+		},
+		{
+			name:     "sequence",
+			sql:      "DROP SEQUENCE IF EXISTS app.order_numbers CASCADE;",
+			nodeType: "*ast.DropSequenceNode",
+		},
+		{
+			name:     "extension",
+			sql:      "DROP EXTENSION IF EXISTS pg_trgm CASCADE;",
+			nodeType: "*ast.DropExtensionNode",
+		},
+		{
+			name:     "role",
+			sql:      "DROP ROLE IF EXISTS app_user;",
+			nodeType: "*ast.DropRoleNode",
+		},
+		{
+			name:     "trigger stays raw",
+			sql:      "DROP TRIGGER IF EXISTS audit_users ON users CASCADE;",
+			nodeType: "*ast.RawSQLNode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			dialect := cmp.Or(tt.dialect, platform.Postgres)
+
+			statements, err := parser.NewParser(tt.sql, parser.WithDialect(dialect)).Parse()
+			c.Assert(err, qt.IsNil)
+			c.Assert(statements.Statements, qt.HasLen, 1)
+			c.Assert(fmt.Sprintf("%T", statements.Statements[0]), qt.Equals, tt.nodeType)
+
+			rendered, err := renderer.RenderSQL(dialect, statements.Statements[0])
+			c.Assert(err, qt.IsNil)
+			c.Assert(legacyRenderedSQL(rendered), qt.Equals, cmp.Or(tt.rendered, tt.sql)+"\n")
+		})
+	}
+}
+
+func TestParser_ParseDropStatements_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr string
+	}{
+		{name: "missing exists", sql: "DROP TYPE IF user_status;", wantErr: "expected EXISTS after DROP TYPE IF: .*"},
+		{name: "missing function name", sql: "DROP FUNCTION IF EXISTS ();", wantErr: "expected function name: .*"},
+		{name: "unterminated parameters", sql: "DROP FUNCTION audit_fn(integer", wantErr: "unterminated function parameters .*"},
+		{name: "materialized without view", sql: "DROP MATERIALIZED TABLE stats;", wantErr: "expected VIEW after DROP MATERIALIZED: .*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, err := parser.NewParser(tt.sql).Parse()
+
+			c.Assert(err, qt.ErrorMatches, tt.wantErr)
+		})
+	}
+}
+
+func TestParser_ParsesPlannerDropStatements(t *testing.T) {
+	c := qt.New(t)
+	diff := &types.SchemaDiff{
+		TablesRemoved:      []string{"legacy_orders"},
+		EnumsRemoved:       []string{"order_status"},
+		IndexesRemoved:     []string{"idx_users_email"},
+		FunctionsRemoved:   []string{"audit_fn"},
+		ViewsRemoved:       []string{"active_users"},
+		RLSPoliciesRemoved: []types.RLSPolicyRef{{PolicyName: "tenant_isolation", TableName: "users"}},
+		TriggersRemoved:    []types.TriggerRef{{TriggerName: "audit_users", TableName: "users"}},
+	}
+	sql, err := planner.GenerateSchemaDiffSQL(diff, &goschema.Database{}, platform.Postgres)
+	c.Assert(err, qt.IsNil)
+
+	statements, err := parser.NewParser(sql, parser.WithDialect(platform.Postgres)).Parse()
+	c.Assert(err, qt.IsNil)
+
+	reparsed, err := renderer.RenderSQL(platform.Postgres, statements.Statements...)
+	c.Assert(err, qt.IsNil)
+	c.Assert(sqlutil.SplitSQLStatements(reparsed), qt.DeepEquals, sqlutil.SplitSQLStatements(sqlutil.StripComments(sql)))
+}
@@ -173,7 +173,7 @@ func (p *Parser) parseCreateStatement() (ast.Node, error) {
 	p.skipWhitespace()
 
 	if p.current.Type != lexer.TokenIdentifier {
-		return nil, fmt.Errorf("expected CREATE target (TABLE, VIEW, FUNCTION, PROCEDURE, PROC, TRIGGER, INDEX, TYPE, DOMAIN, SCHEMA, DATABASE, EXTENSION), got %s at position %d", p.current.Type, p.current.Start)
+		return nil, fmt.Errorf("expected CREATE target (TABLE, VIEW, FUNCTION, PROCEDURE, PROC, TRIGGER, INDEX, TYPE, DOMAIN, SCHEMA, DATABASE, EXTENSION, POLICY, ROLE), got %s at position %d", p.current.Type, p.current.Start)
 	}
 
 	target := strings.ToUpper(p.current.Value)
@@ -219,6 +219,10 @@ func (p *Parser) parseCreateStatement() (ast.Node, error) {
 		return p.parseCreateType()
 	case "DOMAIN":
 		return p.parseCreateDomain()
+	case "POLICY":
+		return p.parseCreatePolicy(statementStart)
+	case "ROLE":
+		return p.parseCreateRole(statementStart)
 	default:
 		return nil, fmt.Errorf("unsupported CREATE target: %s at position %d", target, p.current.Start)
 	}
@@ -1458,6 +1462,12 @@ func (p *Parser) parseCreateTableElements(table *ast.CreateTableNode) error {
 
 func (p *Parser) parseCreateTableSuffix(table *ast.CreateTableNode) error {
 	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("INHERITS") {
+		if err := p.parsePostgreSQLInheritsClause(table); err != nil {
+			return err
+		}
+		p.skipWhitespace()
+	}
 	if p.current.MatchIdentifierValue("PARTITION") {
 		if err := p.parsePostgreSQLPartitionClause(table); err != nil {
 			return err
@@ -1482,6 +1492,33 @@ func (p *Parser) parseCreateTableSuffix(table *ast.CreateTableNode) error {
 	return nil
 }
 
+// parsePostgreSQLInheritsClause parses INHERITS (parent [, ...]).
+func (p *Parser) parsePostgreSQLInheritsClause(table *ast.CreateTableNode) error {
+	if err := p.expect(lexer.TokenIdentifier, "INHERITS"); err != nil {
+		return err
+	}
+	if err := p.expect(lexer.TokenOperator, "("); err != nil {
+		return fmt.Errorf("expected '(' after INHERITS: %w", err)
+	}
+	for {
+		p.skipWhitespace()
+		parent, err := p.parseQualifiedIdentifier("parent table")
+		if err != nil {
+			return err
+		}
+		table.Inherits = append(table.Inherits, parent)
+		p.skipWhitespace()
+		if p.current.MatchOperatorValue(",") {
+			p.advance()
+			continue
+		}
+		if err := p.expect(lexer.TokenOperator, ")"); err != nil {
+			return fmt.Errorf("expected ',' or ')' in INHERITS list: %w", err)
+		}
+		return nil
+	}
+}
+
 func (p *Parser) parsePostgreSQLPartitionClause(table *ast.CreateTableNode) error {
 	if table.Partition != nil {
 		return fmt.Errorf("duplicate PARTITION BY clause at position %d", p.current.Start)
@@ -3445,7 +3482,7 @@ func (p *Parser) parsePostgreSQLWithClause(table *ast.CreateTableNode) error {
 }
 
 // parseAlterStatement parses ALTER TABLE statements.
-func (p *Parser) parseAlterStatement() (*ast.AlterTableNode, error) {
+func (p *Parser) parseAlterStatement() (ast.Node, error) {
 	if err := p.expect(lexer.TokenIdentifier, "ALTER"); err != nil {
 		return nil, err
 	}
@@ -3469,6 +3506,11 @@ func (p *Parser) parseAlterStatement() (*ast.AlterTableNode, error) {
 		return nil, err
 	}
 
+	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("ENABLE") || p.current.MatchIdentifierValue("DISABLE") {
+		return p.parseRowLevelSecurityToggle(tableName)
+	}
+
 	alterNode := &ast.AlterTableNode{
 		Name:       tableName,
 		Operations: make([]ast.AlterOperation, 0),
@@ -3505,7 +3547,11 @@ func (p *Parser) parseAlterStatement() (*ast.AlterTableNode, error) {
 	return alterNode, nil
 }
 
+// parseDropStatement parses the DROP statements the planners emit. Forms
+// the matching Drop node cannot represent, such as several objects in one
+// statement, are kept as raw SQL.
 func (p *Parser) parseDropStatement() (ast.Node, error) {
+	statementStart := p.current.Start
 	if err := p.expect(lexer.TokenIdentifier, "DROP"); err != nil {
 		return nil, err
 	}
@@ -3520,48 +3566,586 @@ func (p *Parser) parseDropStatement() (ast.Node, error) {
 	switch target {
 	case "TABLE":
 		return p.parseDropTable()
+	case "POLICY":
+		return p.parseDropPolicy()
+	case "INDEX":
+		return p.parseDropIndex(statementStart)
+	case "TYPE", "DOMAIN":
+		return p.parseDropType(statementStart, target)
+	case "FUNCTION":
+		return p.parseDropFunction(statementStart)
+	case "VIEW":
+		return p.parseDropView(statementStart)
+	case "MATERIALIZED":
+		return p.parseDropMaterializedView(statementStart)
+	case "SEQUENCE":
+		return p.parseDropSequence(statementStart)
+	case "EXTENSION":
+		return p.parseDropExtension(statementStart)
+	case "ROLE":
+		return p.parseDropRole(statementStart)
+	case "TRIGGER":
+		// DropTriggerNode also drops the Ptah trigger function, which the
+		// planner writes as a separate DROP FUNCTION statement, so reading
+		// the statement back into the node would drop the function twice.
+		return p.collectRawDrop(statementStart, target)
 	default:
 		return nil, fmt.Errorf("unsupported DROP target: %s at position %d", target, p.current.Start)
 	}
 }
 
-func (p *Parser) parseDropTable() (*ast.DropTableNode, error) {
-	if err := p.expect(lexer.TokenIdentifier, "TABLE"); err != nil {
-		return nil, err
+// dropObject is the common tail of a DROP statement: an optional IF EXISTS,
+// one object name, and an optional CASCADE or RESTRICT.
+type dropObject struct {
+	name     string
+	ifExists bool
+	cascade  bool
+}
+
+// parseDropIfExists consumes an optional IF EXISTS after the DROP target.
+func (p *Parser) parseDropIfExists(target string) (bool, error) {
+	p.skipWhitespace()
+	if !p.current.MatchIdentifierValue("IF") {
+		return false, nil
+	}
+	p.advance()
+	if err := p.expect(lexer.TokenIdentifier, "EXISTS"); err != nil {
+		return false, fmt.Errorf("expected EXISTS after DROP %s IF: %w", target, err)
 	}
+	p.skipWhitespace()
+	return true, nil
+}
 
+// parseDropBehavior consumes an optional CASCADE or RESTRICT and reports
+// whether it was CASCADE. RESTRICT is the default and is not kept.
+func (p *Parser) parseDropBehavior() bool {
 	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("CASCADE") {
+		p.advance()
+		return true
+	}
+	if p.current.MatchIdentifierValue("RESTRICT") {
+		p.advance()
+	}
+	return false
+}
 
-	ifExists := false
-	if p.current.MatchIdentifierValue("IF") {
+// parseDropObject parses the tail of DROP target after the target keyword.
+// It reports false when the statement lists more than one object, leaving
+// the parser on the comma.
+func (p *Parser) parseDropObject(target string) (dropObject, bool, error) {
+	ifExists, err := p.parseDropIfExists(target)
+	if err != nil {
+		return dropObject{}, false, err
+	}
+	name, err := p.parseQualifiedIdentifier(strings.ToLower(target) + " name")
+	if err != nil {
+		return dropObject{}, false, err
+	}
+	p.skipWhitespace()
+	if p.current.MatchOperatorValue(",") {
+		return dropObject{}, false, nil
+	}
+	return dropObject{name: name, ifExists: ifExists, cascade: p.parseDropBehavior()}, true, nil
+}
+
+// collectRawDrop keeps the rest of a DROP statement as raw SQL.
+func (p *Parser) collectRawDrop(statementStart int, target string) (ast.Node, error) {
+	sql, err := p.collectRawStatement(statementStart, "DROP "+target+" statement")
+	if err != nil {
+		return nil, err
+	}
+	return ast.NewRawSQL(sql), nil
+}
+
+// parseDropIndex parses DROP INDEX [IF EXISTS] name [ON table]. The ON
+// clause is the MySQL form. CONCURRENTLY and CASCADE have no place in
+// DropIndexNode and keep the statement raw.
+func (p *Parser) parseDropIndex(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "INDEX"); err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("CONCURRENTLY") {
+		return p.collectRawDrop(statementStart, "INDEX")
+	}
+	ifExists, err := p.parseDropIfExists("INDEX")
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.parseQualifiedIdentifier("index name")
+	if err != nil {
+		return nil, err
+	}
+	dropIndex := ast.NewDropIndex(name)
+	if ifExists {
+		dropIndex.SetIfExists()
+	}
+	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("ON") {
 		p.advance()
 		p.skipWhitespace()
-		if err := p.expect(lexer.TokenIdentifier, "EXISTS"); err != nil {
-			return nil, fmt.Errorf("expected EXISTS after DROP TABLE IF: %w", err)
+		table, err := p.parseQualifiedIdentifier("index table name")
+		if err != nil {
+			return nil, err
 		}
+		dropIndex.SetTable(table)
 		p.skipWhitespace()
-		ifExists = true
 	}
+	if p.current.MatchOperatorValue(",") || p.current.MatchIdentifierValue("CASCADE") {
+		return p.collectRawDrop(statementStart, "INDEX")
+	}
+	if p.current.MatchIdentifierValue("RESTRICT") {
+		p.advance()
+	}
+	return dropIndex, nil
+}
 
-	tableNames, err := p.parseDropTableNames()
-	if err != nil {
+// parseDropType parses DROP TYPE and DROP DOMAIN.
+func (p *Parser) parseDropType(statementStart int, target string) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, target); err != nil {
 		return nil, err
 	}
+	object, ok, err := p.parseDropObject(target)
+	if err != nil || !ok {
+		return p.dropResult(statementStart, target, err)
+	}
+	dropType := ast.NewDropType(object.name)
+	if target == "DOMAIN" {
+		dropType.SetDomain()
+	}
+	if object.ifExists {
+		dropType.SetIfExists()
+	}
+	if object.cascade {
+		dropType.SetCascade()
+	}
+	return dropType, nil
+}
 
-	dropTable := ast.NewDropTable(tableNames[0]).SetNames(tableNames)
+// parseDropFunction parses DROP FUNCTION [IF EXISTS] name[(parameters)]
+// [CASCADE | RESTRICT]. The parameter list is kept as raw SQL.
+func (p *Parser) parseDropFunction(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "FUNCTION"); err != nil {
+		return nil, err
+	}
+	ifExists, err := p.parseDropIfExists("FUNCTION")
+	if err != nil {
+		return nil, err
+	}
+	name, err := p.parseQualifiedIdentifier("function name")
+	if err != nil {
+		return nil, err
+	}
+	dropFunction := ast.NewDropFunction(name)
+	p.skipWhitespace()
+	if p.current.MatchOperatorValue("(") {
+		parameters, err := p.collectParenthesizedBody("function parameters")
+		if err != nil {
+			return nil, err
+		}
+		dropFunction.SetParameters(parameters)
+		p.skipWhitespace()
+	}
+	if p.current.MatchOperatorValue(",") {
+		return p.collectRawDrop(statementStart, "FUNCTION")
+	}
 	if ifExists {
-		dropTable.SetIfExists()
+		dropFunction.SetIfExists()
+	}
+	if p.parseDropBehavior() {
+		dropFunction.SetCascade()
 	}
+	return dropFunction, nil
+}
 
+func (p *Parser) parseDropView(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "VIEW"); err != nil {
+		return nil, err
+	}
+	object, ok, err := p.parseDropObject("VIEW")
+	if err != nil || !ok {
+		return p.dropResult(statementStart, "VIEW", err)
+	}
+	dropView := ast.NewDropView(object.name)
+	if object.ifExists {
+		dropView.SetIfExists()
+	}
+	if object.cascade {
+		dropView.SetCascade()
+	}
+	return dropView, nil
+}
+
+func (p *Parser) parseDropMaterializedView(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "MATERIALIZED"); err != nil {
+		return nil, err
+	}
+	if err := p.expect(lexer.TokenIdentifier, "VIEW"); err != nil {
+		return nil, fmt.Errorf("expected VIEW after DROP MATERIALIZED: %w", err)
+	}
+	object, ok, err := p.parseDropObject("MATERIALIZED VIEW")
+	if err != nil || !ok {
+		return p.dropResult(statementStart, "MATERIALIZED VIEW", err)
+	}
+	dropView := ast.NewDropMaterializedView(object.name)
+	if object.ifExists {
+		dropView.SetIfExists()
+	}
+	if object.cascade {
+		dropView.SetCascade()
+	}
+	return dropView, nil
+}
+
+func (p *Parser) parseDropSequence(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "SEQUENCE"); err != nil {
+		return nil, err
+	}
+	object, ok, err := p.parseDropObject("SEQUENCE")
+	if err != nil || !ok {
+		return p.dropResult(statementStart, "SEQUENCE", err)
+	}
+	dropSequence := ast.NewDropSequence(object.name)
+	if object.ifExists {
+		dropSequence.SetIfExists()
+	}
+	if object.cascade {
+		dropSequence.SetCascade()
+	}
+	return dropSequence, nil
+}
+
+func (p *Parser) parseDropExtension(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "EXTENSION"); err != nil {
+		return nil, err
+	}
+	object, ok, err := p.parseDropObject("EXTENSION")
+	if err != nil || !ok {
+		return p.dropResult(statementStart, "EXTENSION", err)
+	}
+	dropExtension := ast.NewDropExtension(object.name)
+	if object.ifExists {
+		dropExtension.SetIfExists()
+	}
+	if object.cascade {
+		dropExtension.SetCascade()
+	}
+	return dropExtension, nil
+}
+
+// parseDropRole parses DROP ROLE [IF EXISTS] name. PostgreSQL has no
+// CASCADE for roles.
+func (p *Parser) parseDropRole(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "ROLE"); err != nil {
+		return nil, err
+	}
+	object, ok, err := p.parseDropObject("ROLE")
+	if err != nil || !ok {
+		return p.dropResult(statementStart, "ROLE", err)
+	}
+	dropRole := ast.NewDropRole(object.name)
+	if object.ifExists {
+		dropRole.SetIfExists()
+	}
+	return dropRole, nil
+}
+
+// dropResult returns err when parseDropObject failed, and otherwise the
+// statement as raw SQL because it lists several objects.
+func (p *Parser) dropResult(statementStart int, target string, err error) (ast.Node, error) {
+	if err != nil {
+		return nil, err
+	}
+	return p.collectRawDrop(statementStart, target)
+}
+
+// parseRowLevelSecurityToggle parses the ENABLE/DISABLE ROW LEVEL SECURITY
+// tail of an ALTER TABLE statement.
+func (p *Parser) parseRowLevelSecurityToggle(tableName string) (ast.Node, error) {
+	enable := p.current.MatchIdentifierValue("ENABLE")
+	action := strings.ToUpper(p.current.Value)
+	p.advance()
+	for _, keyword := range []string{"ROW", "LEVEL", "SECURITY"} {
+		if err := p.expect(lexer.TokenIdentifier, keyword); err != nil {
+			return nil, fmt.Errorf("expected ROW LEVEL SECURITY after %s: %w", action, err)
+		}
+	}
+	if enable {
+		return ast.NewAlterTableEnableRLS(tableName), nil
+	}
+	return ast.NewAlterTableDisableRLS(tableName), nil
+}
+
+// parseCreatePolicy parses a PostgreSQL CREATE POLICY statement. USING and
+// WITH CHECK expressions are kept as raw SQL. RESTRICTIVE policies have no
+// AST representation and are returned as raw SQL.
+func (p *Parser) parseCreatePolicy(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "POLICY"); err != nil {
+		return nil, err
+	}
 	p.skipWhitespace()
-	if p.current.MatchIdentifierValue("CASCADE") {
-		dropTable.SetCascade()
+
+	policyName, err := p.expectIdentifier()
+	if err != nil {
+		return nil, fmt.Errorf("expected policy name: %w", err)
+	}
+	if err := p.expect(lexer.TokenIdentifier, "ON"); err != nil {
+		return nil, fmt.Errorf("expected ON after policy name: %w", err)
+	}
+	p.skipWhitespace()
+
+	tableName, err := p.parseQualifiedIdentifier("policy table name")
+	if err != nil {
+		return nil, err
+	}
+	policy := ast.NewCreatePolicy(policyName, tableName)
+
+	for {
+		p.skipWhitespace()
+		if p.isAtEnd() || p.current.Type == lexer.TokenSemicolon {
+			return policy, nil
+		}
+		keyword := strings.ToUpper(p.current.Value)
+		switch {
+		case p.current.Type != lexer.TokenIdentifier:
+			return nil, fmt.Errorf("unexpected %s in CREATE POLICY at position %d", p.current.Type, p.current.Start)
+		case keyword == "AS":
+			p.advance()
+			p.skipWhitespace()
+			if p.current.MatchIdentifierValue("RESTRICTIVE") {
+				return p.collectRawCreatePolicy(statementStart)
+			}
+			if err := p.expect(lexer.TokenIdentifier, "PERMISSIVE"); err != nil {
+				return nil, fmt.Errorf("expected PERMISSIVE or RESTRICTIVE after AS: %w", err)
+			}
+		case keyword == "FOR":
+			p.advance()
+			p.skipWhitespace()
+			command, err := p.expectIdentifier()
+			if err != nil {
+				return nil, fmt.Errorf("expected policy command after FOR: %w", err)
+			}
+			policy.SetPolicyFor(strings.ToUpper(command))
+		case keyword == "TO":
+			p.advance()
+			roles, err := p.parsePolicyRoles()
+			if err != nil {
+				return nil, err
+			}
+			policy.SetToRoles(roles)
+		case keyword == "USING":
+			p.advance()
+			expression, err := p.parseRawParenthesized("USING expression")
+			if err != nil {
+				return nil, err
+			}
+			policy.SetUsingExpression(expression)
+		case keyword == "WITH":
+			p.advance()
+			if err := p.expect(lexer.TokenIdentifier, "CHECK"); err != nil {
+				return nil, fmt.Errorf("expected CHECK after WITH in CREATE POLICY: %w", err)
+			}
+			expression, err := p.parseRawParenthesized("WITH CHECK expression")
+			if err != nil {
+				return nil, err
+			}
+			policy.SetWithCheckExpression(expression)
+		default:
+			return nil, fmt.Errorf("unsupported CREATE POLICY clause: %s at position %d", keyword, p.current.Start)
+		}
+	}
+}
+
+func (p *Parser) collectRawCreatePolicy(statementStart int) (ast.Node, error) {
+	sql, err := p.collectRawStatement(statementStart, "CREATE POLICY statement")
+	if err != nil {
+		return nil, err
+	}
+	return ast.NewRawSQL(sql), nil
+}
+
+// parsePolicyRoles parses the comma-separated role list of a TO clause.
+func (p *Parser) parsePolicyRoles() (string, error) {
+	var roles []string
+	for {
+		p.skipWhitespace()
+		role, err := p.expectIdentifier()
+		if err != nil {
+			return "", fmt.Errorf("expected role name in TO clause: %w", err)
+		}
+		roles = append(roles, role)
+		p.skipWhitespace()
+		if !p.current.MatchOperatorValue(",") {
+			return strings.Join(roles, ", "), nil
+		}
 		p.advance()
-		return dropTable, nil
 	}
-	if p.current.MatchIdentifierValue("RESTRICT") {
+}
+
+// parseRawParenthesized consumes a parenthesized expression and returns the
+// source text between the outer parentheses.
+func (p *Parser) parseRawParenthesized(label string) (string, error) {
+	if err := p.expect(lexer.TokenOperator, "("); err != nil {
+		return "", fmt.Errorf("expected '(' for %s: %w", label, err)
+	}
+	start := p.previous.End
+	depth := 1
+	for !p.isAtEnd() {
+		if p.current.MatchOperatorValue("(") {
+			depth++
+		}
+		if p.current.MatchOperatorValue(")") {
+			depth--
+			if depth == 0 {
+				expression := p.rawStatementFragment(start, p.current.Start)
+				p.advance()
+				return expression, nil
+			}
+		}
+		p.advance()
+	}
+	return "", fmt.Errorf("unterminated %s at position %d", label, p.current.Start)
+}
+
+// parseCreateRole parses a PostgreSQL CREATE ROLE statement with the
+// attributes CreateRoleNode models. Statements using other role options
+// (CONNECTION LIMIT, VALID UNTIL, IN ROLE, ...) are returned as raw SQL.
+func (p *Parser) parseCreateRole(statementStart int) (ast.Node, error) {
+	if err := p.expect(lexer.TokenIdentifier, "ROLE"); err != nil {
+		return nil, err
+	}
+	p.skipWhitespace()
+
+	roleName, err := p.expectIdentifier()
+	if err != nil {
+		return nil, fmt.Errorf("expected role name: %w", err)
+	}
+	role := ast.NewCreateRole(roleName)
+
+	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("WITH") {
 		p.advance()
 	}
+	for {
+		p.skipWhitespace()
+		if p.isAtEnd() || p.current.Type == lexer.TokenSemicolon {
+			return role, nil
+		}
+		if p.current.Type != lexer.TokenIdentifier {
+			return nil, fmt.Errorf("unexpected %s in CREATE ROLE at position %d", p.current.Type, p.current.Start)
+		}
+		attribute := strings.ToUpper(p.current.Value)
+		if attribute == "PASSWORD" {
+			p.advance()
+			password, err := p.parseRolePassword()
+			if err != nil {
+				return nil, err
+			}
+			role.SetPassword(password)
+			continue
+		}
+		if !applyRoleAttribute(role, attribute) {
+			sql, err := p.collectRawStatement(statementStart, "CREATE ROLE statement")
+			if err != nil {
+				return nil, err
+			}
+			return ast.NewRawSQL(sql), nil
+		}
+		p.advance()
+	}
+}
+
+func (p *Parser) parseRolePassword() (string, error) {
+	p.skipWhitespace()
+	if p.current.MatchIdentifierValue("NULL") {
+		p.advance()
+		return "", nil
+	}
+	if p.current.Type != lexer.TokenString || isDoubleQuotedIdentifierToken(p.current) {
+		return "", fmt.Errorf("expected password string literal, got %s at position %d", p.current.Type, p.current.Start)
+	}
+	password := strings.TrimSuffix(strings.TrimPrefix(p.current.Value, "'"), "'")
+	p.advance()
+	return strings.ReplaceAll(password, "''", "'"), nil
+}
+
+// applyRoleAttribute sets a boolean CREATE ROLE attribute and reports whether
+// the keyword was one CreateRoleNode models.
+func applyRoleAttribute(role *ast.CreateRoleNode, attribute string) bool {
+	switch attribute {
+	case "LOGIN", "NOLOGIN":
+		role.SetLogin(attribute == "LOGIN")
+	case "SUPERUSER", "NOSUPERUSER":
+		role.SetSuperuser(attribute == "SUPERUSER")
+	case "CREATEDB", "NOCREATEDB":
+		role.SetCreateDB(attribute == "CREATEDB")
+	case "CREATEROLE", "NOCREATEROLE":
+		role.SetCreateRole(attribute == "CREATEROLE")
+	case "INHERIT", "NOINHERIT":
+		role.SetInherit(attribute == "INHERIT")
+	case "REPLICATION", "NOREPLICATION":
+		role.SetReplication(attribute == "REPLICATION")
+	default:
+		return false
+	}
+	return true
+}
+
+// parseDropPolicy parses DROP POLICY [IF EXISTS] name ON table, which the
+// PostgreSQL planner emits ahead of each CREATE POLICY.
+func (p *Parser) parseDropPolicy() (*ast.DropPolicyNode, error) {
+	if err := p.expect(lexer.TokenIdentifier, "POLICY"); err != nil {
+		return nil, err
+	}
+	ifExists, err := p.parseDropIfExists("POLICY")
+	if err != nil {
+		return nil, err
+	}
+
+	policyName, err := p.expectIdentifier()
+	if err != nil {
+		return nil, fmt.Errorf("expected policy name: %w", err)
+	}
+	if err := p.expect(lexer.TokenIdentifier, "ON"); err != nil {
+		return nil, fmt.Errorf("expected ON after policy name: %w", err)
+	}
+	p.skipWhitespace()
+	tableName, err := p.parseQualifiedIdentifier("policy table name")
+	if err != nil {
+		return nil, err
+	}
+
+	dropPolicy := ast.NewDropPolicy(policyName, tableName)
+	if ifExists {
+		dropPolicy.SetIfExists()
+	}
+	p.parseDropBehavior()
+	return dropPolicy, nil
+}
+
+func (p *Parser) parseDropTable() (*ast.DropTableNode, error) {
+	if err := p.expect(lexer.TokenIdentifier, "TABLE"); err != nil {
+		return nil, err
+	}
+
+	ifExists, err := p.parseDropIfExists("TABLE")
+	if err != nil {
+		return nil, err
+	}
+
+	tableNames, err := p.parseDropTableNames()
+	if err != nil {
+		return nil, err
+	}
+
+	dropTable := ast.NewDropTable(tableNames[0]).SetNames(tableNames)
+	if ifExists {
+		dropTable.SetIfExists()
+	}
+	if p.parseDropBehavior() {
+		dropTable.SetCascade()
+	}
 	return dropTable, nil
 }
 
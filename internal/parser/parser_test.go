@@ -122,6 +122,21 @@ func TestParser_ParseCreateTable_RejectsDuplicatePartitionBy(t *testing.T) {
 	c.Assert(err, qt.ErrorMatches, `.*duplicate PARTITION BY clause.*`)
 }
 
+func TestParser_ParseCreateTable_PostgreSQLInherits(t *testing.T) {
+	c := qt.New(t)
+
+	sql := `CREATE TABLE capitals (state char(2)) INHERITS (cities, geo.places);`
+	p := parser.NewParser(sql)
+
+	statements, err := p.Parse()
+	c.Assert(err, qt.IsNil)
+	c.Assert(statements.Statements, qt.HasLen, 1)
+	createTable, ok := statements.Statements[0].(*ast.CreateTableNode)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(createTable.Inherits, qt.DeepEquals, []string{"cities", "geo.places"})
+	c.Assert(createTable.Columns, qt.HasLen, 1)
+}
+
 func TestParser_ParseCreateIndexConcurrently(t *testing.T) {
 	c := qt.New(t)
 
@@ -2664,8 +2679,8 @@ func TestParser_ParseDropTable(t *testing.T) {
 func TestParser_ParseDropTableRejectsUnsupportedTargets(t *testing.T) {
 	c := qt.New(t)
 
-	_, err := parser.NewParser("DROP VIEW users;").Parse()
-	c.Assert(err, qt.ErrorMatches, "unsupported DROP target: VIEW at position 5")
+	_, err := parser.NewParser("DROP SERVER users;").Parse()
+	c.Assert(err, qt.ErrorMatches, "unsupported DROP target: SERVER at position 5")
 }
 
 func TestParser_ParseCreateIndex(t *testing.T) {
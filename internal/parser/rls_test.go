@@ -0,0 +1,172 @@
+package parser_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/renderer"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/parser"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const rlsRoundTripSource = `package models
+
+//migrator:schema:role name="app_user" login="true" password="SCRAM-SHA-256$4096:abc" comment="Application role"
+//migrator:schema:role name="app_admin" createrole="true" inherit="false"
+//migrator:schema:table name="users"
+//migrator:schema:rls:enable table="users"
+//migrator:schema:rls:policy name="tenant_isolation" table="users" for="ALL" to="app_user, app_admin" using="tenant_id = current_setting('app.tenant')" with_check="tenant_id = current_setting('app.tenant')"
+//migrator:schema:rls:policy name="public_read" table="users" for="SELECT" to="PUBLIC" using="(deleted_at IS NULL) AND (tenant_id <> '')"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="tenant_id" type="TEXT" not_null="true"
+	TenantID string
+
+	//migrator:schema:field name="deleted_at" type="TIMESTAMPTZ"
+	DeletedAt string
+}
+`
+
+// plannedRLSSQL returns the SQL the PostgreSQL planner emits to create the
+// users table of rlsRoundTripSource with its roles, RLS enablement and
+// policies.
+func plannedRLSSQL(c *qt.C) string {
+	generated, err := goschema.ParseSource("models.go", rlsRoundTripSource)
+	c.Assert(err, qt.IsNil)
+	diff := schemadiff.CompareWithDialect(&generated, &dbtypes.DBSchema{}, platform.Postgres)
+	sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+	c.Assert(err, qt.IsNil)
+	return sql
+}
+
+func withoutCommentLines(sql string) string {
+	var lines []string
+	for line := range strings.SplitSeq(sql, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func rlsStatements(statements []ast.Node) ([]*ast.CreateRoleNode, []*ast.CreatePolicyNode, []*ast.AlterTableEnableRLSNode) {
+	var roles []*ast.CreateRoleNode
+	var policies []*ast.CreatePolicyNode
+	var enabled []*ast.AlterTableEnableRLSNode
+	for _, statement := range statements {
+		switch node := statement.(type) {
+		case *ast.CreateRoleNode:
+			roles = append(roles, node)
+		case *ast.CreatePolicyNode:
+			policies = append(policies, node)
+		case *ast.AlterTableEnableRLSNode:
+			enabled = append(enabled, node)
+		}
+	}
+	return roles, policies, enabled
+}
+
+func TestParser_RoundTripsPlannedRLSStatements(t *testing.T) {
+	c := qt.New(t)
+	sql := plannedRLSSQL(c)
+
+	statements, err := parser.NewParser(sql, parser.WithDialect(platform.Postgres)).Parse()
+	c.Assert(err, qt.IsNil)
+	rendered, err := renderer.RenderSQL(platform.Postgres, statements.Statements...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(withoutCommentLines(rendered), qt.Equals, withoutCommentLines(sql))
+}
+
+func TestParser_ParsePlannedRLSStatements(t *testing.T) {
+	c := qt.New(t)
+
+	statements, err := parser.NewParser(plannedRLSSQL(c), parser.WithDialect(platform.Postgres)).Parse()
+	c.Assert(err, qt.IsNil)
+
+	roles, policies, enabled := rlsStatements(statements.Statements)
+
+	c.Assert(roles, qt.HasLen, 2)
+	c.Assert(roles[0].Name, qt.Equals, `"app_admin"`)
+	c.Assert(roles[0].CreateRole, qt.IsTrue)
+	c.Assert(roles[0].Inherit, qt.IsFalse)
+	c.Assert(roles[1].Name, qt.Equals, `"app_user"`)
+	c.Assert(roles[1].Login, qt.IsTrue)
+	c.Assert(roles[1].Password, qt.Equals, "SCRAM-SHA-256$4096:abc")
+	c.Assert(enabled, qt.HasLen, 1)
+	c.Assert(enabled[0].Table, qt.Equals, `"users"`)
+	c.Assert(policies, qt.HasLen, 2)
+	c.Assert(policies[0].PolicyFor, qt.Equals, "SELECT")
+	c.Assert(policies[0].ToRoles, qt.Equals, "PUBLIC")
+	c.Assert(policies[0].UsingExpression, qt.Equals, "(deleted_at IS NULL) AND (tenant_id <> '')")
+	c.Assert(policies[1].PolicyFor, qt.Equals, "ALL")
+	c.Assert(policies[1].ToRoles, qt.Equals, `"app_user", "app_admin"`)
+	c.Assert(policies[1].UsingExpression, qt.Equals, "tenant_id = current_setting('app.tenant')")
+	c.Assert(policies[1].WithCheckExpression, qt.Equals, "tenant_id = current_setting('app.tenant')")
+}
+
+func TestParser_ParseRowLevelSecurityToggles(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		rendered string
+	}{
+		{name: "enable", sql: "ALTER TABLE app.users ENABLE ROW LEVEL SECURITY;", rendered: "ALTER TABLE \"app\".\"users\" ENABLE ROW LEVEL SECURITY;\n"},
+		{name: "disable", sql: "ALTER TABLE ONLY users DISABLE ROW LEVEL SECURITY;", rendered: "ALTER TABLE \"users\" DISABLE ROW LEVEL SECURITY;\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			statements, err := parser.NewParser(tt.sql).Parse()
+			c.Assert(err, qt.IsNil)
+			c.Assert(statements.Statements, qt.HasLen, 1)
+			rendered, err := renderer.RenderSQL(platform.Postgres, statements.Statements[0])
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(rendered, qt.Equals, tt.rendered)
+		})
+	}
+}
+
+func TestParser_ParseUnmodeledPolicyAndRoleOptionsAsRawSQL(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{name: "restrictive policy", sql: "CREATE POLICY p ON users AS RESTRICTIVE FOR SELECT USING (true);"},
+		{name: "role connection limit", sql: "CREATE ROLE reporting LOGIN CONNECTION LIMIT 5;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			statements, err := parser.NewParser(tt.sql).Parse()
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(statements.Statements, qt.HasLen, 1)
+			raw, ok := statements.Statements[0].(*ast.RawSQLNode)
+			c.Assert(ok, qt.IsTrue)
+			c.Assert(raw.SQL, qt.Equals, tt.sql)
+		})
+	}
+}
+
+func TestParser_ParseRowLevelSecurityToggleRequiresKeywords(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := parser.NewParser("ALTER TABLE users ENABLE TRIGGER audit;").Parse()
+
+	c.Assert(err, qt.ErrorMatches, "expected ROW LEVEL SECURITY after ENABLE: .*")
+}
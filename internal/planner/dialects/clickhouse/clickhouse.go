@@ -60,7 +60,7 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 		return result, nil
 	}
 
-	if len(diff.EnumsAdded)+len(diff.EnumsRemoved)+len(diff.EnumsModified) > 0 {
+	if len(diff.EnumsAdded)+len(diff.EnumsRemoved)+len(diff.EnumsModified)+len(diff.EnumsRenamed) > 0 {
 		result = append(result, ast.NewComment("CLICKHOUSE: enum changes are ignored; declare ClickHouse Enum8/Enum16 columns inline via platform.clickhouse.type"))
 	}
 
@@ -194,6 +194,9 @@ func (p *Planner) addNewIndexes(result []ast.Node, diff *types.SchemaDiff, gener
 func (p *Planner) removeIndexes(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
 	if len(diff.IndexesRemovedWithTables) > 0 {
 		for _, info := range diff.IndexesRemovedWithTables {
+			if diff.IndexDroppedWithTable(info) {
+				continue
+			}
 			result = append(result, ast.NewDropIndex(info.Name).SetTable(info.TableName).SetIfExists())
 		}
 		return result
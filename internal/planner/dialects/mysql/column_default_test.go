@@ -0,0 +1,79 @@
+package mysql_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/mysql"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func columnDefaultDiff(changes map[string]string) *types.SchemaDiff {
+	return &types.SchemaDiff{TablesModified: []types.TableDiff{{
+		TableName:       "users",
+		ColumnsModified: []types.ColumnDiff{{ColumnName: "status", Changes: changes}},
+	}}}
+}
+
+func columnDefaultSchema(field goschema.Field) *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+		Fields: []goschema.Field{field},
+	}
+}
+
+func TestPlanner_DefaultOnlyChangeAltersDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes map[string]string
+		field   goschema.Field
+		want    string
+	}{
+		{
+			name:    "set literal default",
+			changes: map[string]string{"default": "'pending' -> 'active'"},
+			field:   goschema.Field{StructName: "User", Name: "status", Type: "VARCHAR(32)", Default: "active"},
+			want:    "ALTER TABLE users ALTER COLUMN status SET DEFAULT 'active';",
+		},
+		{
+			name:    "set expression default",
+			changes: map[string]string{"default_expr": " -> CURRENT_TIMESTAMP"},
+			field:   goschema.Field{StructName: "User", Name: "status", Type: "TIMESTAMP", DefaultExpr: "CURRENT_TIMESTAMP"},
+			want:    "ALTER TABLE users ALTER COLUMN status SET DEFAULT (CURRENT_TIMESTAMP);",
+		},
+		{
+			name:    "drop default",
+			changes: map[string]string{"default": "'active' -> "},
+			field:   goschema.Field{StructName: "User", Name: "status", Type: "VARCHAR(32)"},
+			want:    "ALTER TABLE users ALTER COLUMN status DROP DEFAULT;",
+		},
+	}
+
+	for _, tt := range tests {
+		for _, dialect := range mysqlFamilyDialects {
+			t.Run(tt.name+"/"+dialect, func(t *testing.T) {
+				c := qt.New(t)
+
+				sql := renderMySQLFamily(c, dialect, columnDefaultDiff(tt.changes), columnDefaultSchema(tt.field))
+
+				c.Assert(sql, qt.Contains, tt.want)
+				c.Assert(sql, qt.Not(qt.Contains), "MODIFY COLUMN")
+			})
+		}
+	}
+}
+
+func TestPlanner_DefaultOnlyChangeKeepsModifyForSQLServer(t *testing.T) {
+	c := qt.New(t)
+	diff := columnDefaultDiff(map[string]string{"default": "'pending' -> 'active'"})
+	schema := columnDefaultSchema(goschema.Field{StructName: "User", Name: "status", Type: "VARCHAR(32)", Default: "active"})
+
+	nodes := mysql.NewForDialect("sqlserver", nil).GenerateMigrationAST(diff, schema)
+	sql, err := renderer.RenderSQL("sqlserver", nodes...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Not(qt.Contains), "SET DEFAULT")
+}
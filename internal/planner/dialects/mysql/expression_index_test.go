@@ -0,0 +1,56 @@
+package mysql_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/mysql"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_ExpressionIndexAlongsidePlainIndex(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{IndexesAdded: []string{"idx_users_email", "idx_users_email_lower"}}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+		Indexes: []goschema.Index{
+			{Name: "idx_users_email", StructName: "User", Fields: []string{"email"}},
+			{Name: "idx_users_email_lower", StructName: "User", Fields: []string{"lower(email)"}, Parts: []goschema.IndexPart{{Expr: "lower(email)"}}},
+		},
+	}
+
+	nodes := mysql.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("mysql", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Equals, "CREATE INDEX idx_users_email ON users (email);\n"+
+		"CREATE INDEX idx_users_email_lower ON users ((lower(email)));\n")
+}
+
+func TestPlanner_IndexTypesAndParser(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{IndexesAdded: []string{"idx_posts_body", "idx_posts_location", "idx_posts_slug"}}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Post", Name: "posts"}},
+		Indexes: []goschema.Index{
+			{Name: "idx_posts_body", StructName: "Post", Fields: []string{"body"}, Type: "fulltext", Parser: "ngram"},
+			{Name: "idx_posts_location", StructName: "Post", Fields: []string{"location"}, Type: "spatial"},
+			{Name: "idx_posts_slug", StructName: "Post", Fields: []string{"slug"}, Type: "hash"},
+		},
+	}
+
+	nodes := mysql.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("mysql", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Equals, "CREATE FULLTEXT INDEX idx_posts_body ON posts (body) /*!50100 WITH PARSER ngram */;\n"+
+		"CREATE SPATIAL INDEX idx_posts_location ON posts (location);\n"+
+		"CREATE INDEX idx_posts_slug ON posts (slug) USING HASH;\n")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/stokaro/ptah/core/ast"
@@ -68,6 +69,9 @@ type Planner struct {
 	// defaults to mysql so the zero value and New stay backwards-identical for
 	// the MySQL-family planner.
 	dialect string
+	// onlineDDL marks eligible ADD COLUMN and CREATE INDEX operations for
+	// ALGORITHM=INPLACE, LOCK=NONE.
+	onlineDDL bool
 }
 
 // New returns a planner configured with the current MySQL line preset
@@ -111,6 +115,49 @@ func (p *Planner) capabilities() capability.Capabilities {
 	return p.caps
 }
 
+// WithOnlineDDL returns a copy of the planner that requests MySQL online DDL
+// (ALGORITHM=INPLACE, LOCK=NONE) for added columns and indexes the server can
+// change in place without blocking writes. Operations that need a table copy
+// or a shared lock are planned without the hint. The receiver is not
+// modified, and targets outside the MySQL family ignore the option.
+func (p *Planner) WithOnlineDDL() *Planner {
+	cp := *p
+	cp.onlineDDL = true
+	return &cp
+}
+
+// onlineDDLEnabled reports whether online DDL hints apply to the target.
+func (p *Planner) onlineDDLEnabled() bool {
+	if !p.onlineDDL {
+		return false
+	}
+	switch p.targetDialect() {
+	case platform.MySQL, platform.MariaDB:
+		return true
+	default:
+		return false
+	}
+}
+
+// onlineAddColumn reports whether column can be added with ALGORITHM=INPLACE,
+// LOCK=NONE. AUTO_INCREMENT columns need a shared lock, and STORED generated
+// columns and primary key columns need a table copy.
+func onlineAddColumn(column *ast.ColumnNode) bool {
+	return !column.AutoInc && !column.Primary &&
+		!strings.EqualFold(strings.TrimSpace(column.GeneratedKind), "STORED")
+}
+
+// onlineIndex reports whether an index of indexType can be built with
+// ALGORITHM=INPLACE, LOCK=NONE. FULLTEXT and SPATIAL builds take a shared lock.
+func onlineIndex(indexType string) bool {
+	switch strings.ToUpper(strings.TrimSpace(indexType)) {
+	case "FULLTEXT", "SPATIAL":
+		return false
+	default:
+		return true
+	}
+}
+
 func (p *Planner) targetDialect() string {
 	if p.dialect == "" {
 		return DialectName
@@ -212,6 +259,9 @@ func (p *Planner) addSelfReferencingForeignKeys(result []ast.Node, generated *go
 	}
 
 	for _, selfRefFK := range selfRefFKs {
+		if declaredAsForeignKeyConstraint(generated, table, selfRefFK) {
+			continue
+		}
 		fkRef := fromschema.ParseForeignKeyReference(selfRefFK.Foreign)
 		if fkRef != nil {
 			fkRef.OnDelete = selfRefFK.OnDelete
@@ -223,6 +273,25 @@ func (p *Planner) addSelfReferencingForeignKeys(result []ast.Node, generated *go
 	return result
 }
 
+// declaredAsForeignKeyConstraint reports whether a self-referencing foreign
+// key was recorded from a table-level FOREIGN KEY constraint annotation. Those
+// are already emitted by the constraint path with their full column lists, so
+// re-emitting them here would duplicate the constraint.
+func declaredAsForeignKeyConstraint(generated *goschema.Database, table goschema.Table, fk goschema.SelfReferencingFK) bool {
+	if fk.ForeignKeyName == "" {
+		return false
+	}
+	for _, constraint := range generated.Constraints {
+		if constraint.Name != fk.ForeignKeyName || !strings.EqualFold(constraint.Type, "FOREIGN KEY") {
+			continue
+		}
+		if constraint.StructName == table.StructName || constraint.Table == table.Name || constraint.Table == table.QualifiedName() {
+			return true
+		}
+	}
+	return false
+}
+
 // isRegularForeignKeyField checks if a field is a regular foreign key field for the given table.
 //
 // A field-level foreign= annotation is a foreign key whether or not an explicit
@@ -283,7 +352,10 @@ func (p *Planner) addNewTableColumns(result []ast.Node, tableDiff *types.TableDi
 			columnNode := fromschema.FromField(*targetField, generated.Enums, p.targetDialect())
 
 			// Create operations list starting with ADD COLUMN
-			operations := []ast.AlterOperation{&ast.AddColumnOperation{Column: columnNode}}
+			operations := []ast.AlterOperation{&ast.AddColumnOperation{
+				Column: columnNode,
+				Online: p.onlineDDLEnabled() && onlineAddColumn(columnNode),
+			}}
 
 			// If the column has a foreign key, add a separate ADD CONSTRAINT operation
 			if targetField.Foreign != "" {
@@ -336,7 +408,8 @@ func (p *Planner) modifyExistingColumns(result []ast.Node, diff *types.SchemaDif
 
 		var targetField *goschema.Field
 		var targetStructName string
-		if targetTable := findGeneratedTable(generated.Tables, tableDiff.TableName); targetTable != nil {
+		targetTable := findGeneratedTable(generated.Tables, tableDiff.TableName)
+		if targetTable != nil {
 			targetStructName = targetTable.StructName
 			for _, field := range generated.Fields {
 				if field.StructName == targetStructName && field.Name == colDiff.ColumnName {
@@ -357,8 +430,32 @@ func (p *Planner) modifyExistingColumns(result []ast.Node, diff *types.SchemaDif
 		if suppressColumnPrimary {
 			field.Primary = false
 		}
+		if inTablePrimaryKey(targetTable, field.Name) {
+			// Primary key columns are NOT NULL whatever the field declares.
+			field.Nullable = false
+		}
 		columnNode := fromschema.FromField(field, generated.Enums, p.targetDialect())
 
+		if generatedKindFlip(colDiff) {
+			result = p.recreateGeneratedColumn(result, diff, generated, targetTable, tableDiff.TableName, colDiff, columnNode)
+			continue
+		}
+
+		if p.targetDialect() != platform.SQLServer && changesOnly(colDiff, "default", "default_expr") {
+			// ALTER COLUMN SET/DROP DEFAULT touches only the default; MODIFY
+			// COLUMN would restate the whole definition and drop any
+			// attribute the annotation does not carry.
+			result = append(result, &ast.AlterTableNode{
+				Name:       tableDiff.TableName,
+				Operations: []ast.AlterOperation{&ast.AlterColumnDefaultOperation{Column: columnNode}},
+			}, modifyColumnComment(tableDiff.TableName, colDiff))
+			continue
+		}
+
+		if warning := p.typeChangeWarning(tableDiff.TableName, colDiff, columnNode.Type); warning != nil {
+			result = append(result, warning)
+		}
+
 		// Generate ALTER COLUMN statements using AST
 		alterNode := &ast.AlterTableNode{
 			Name: tableDiff.TableName,
@@ -369,19 +466,120 @@ func (p *Planner) modifyExistingColumns(result []ast.Node, diff *types.SchemaDif
 				HasPreviousNullable: colDiff.Changes["nullable"] != "",
 			}},
 		}
-		result = append(result, alterNode)
+		result = append(result, alterNode, modifyColumnComment(tableDiff.TableName, colDiff))
+	}
+	return result, nil
+}
 
-		// Add a comment showing what changes are being made. Iterate the
-		// changes in sorted key order so migration output is deterministic
-		// (issue #59).
-		changesList := make([]string, 0, len(colDiff.Changes))
-		for _, changeType := range slices.Sorted(maps.Keys(colDiff.Changes)) {
-			changesList = append(changesList, fmt.Sprintf("%s: %s", changeType, colDiff.Changes[changeType]))
+// changesOnly reports whether every change in a column diff is one of keys.
+func changesOnly(colDiff types.ColumnDiff, keys ...string) bool {
+	for key := range colDiff.Changes {
+		if !slices.Contains(keys, key) {
+			return false
 		}
-		astCommentNode := ast.NewComment(fmt.Sprintf("Modify column %s.%s: %s", tableDiff.TableName, colDiff.ColumnName, strings.Join(changesList, ", ")))
-		result = append(result, astCommentNode)
 	}
-	return result, nil
+	return len(colDiff.Changes) > 0
+}
+
+// modifyColumnComment describes a column's changes. It iterates the changes
+// in sorted key order so migration output is deterministic (issue #59).
+func modifyColumnComment(tableName string, colDiff types.ColumnDiff) *ast.CommentNode {
+	changesList := make([]string, 0, len(colDiff.Changes))
+	for _, changeType := range slices.Sorted(maps.Keys(colDiff.Changes)) {
+		changesList = append(changesList, fmt.Sprintf("%s: %s", changeType, colDiff.Changes[changeType]))
+	}
+	return ast.NewComment(fmt.Sprintf("Modify column %s.%s: %s", tableName, colDiff.ColumnName, strings.Join(changesList, ", ")))
+}
+
+// generatedKindFlip reports whether a generated-column change switches the
+// column between STORED and VIRTUAL. MySQL and MariaDB reject that change in
+// MODIFY COLUMN, so the column has to be recreated.
+func generatedKindFlip(colDiff types.ColumnDiff) bool {
+	before, after, ok := strings.Cut(colDiff.Changes["generated"], " -> ")
+	if !ok {
+		return false
+	}
+	beforeKind, _, _ := strings.Cut(strings.TrimSpace(before), " ")
+	afterKind, _, _ := strings.Cut(strings.TrimSpace(after), " ")
+	return isStoredOrVirtual(beforeKind) && isStoredOrVirtual(afterKind) && beforeKind != afterKind
+}
+
+func isStoredOrVirtual(kind string) bool {
+	return kind == "STORED" || kind == "VIRTUAL"
+}
+
+// recreateGeneratedColumn drops a generated column and adds it back with its
+// target definition. The values are derived from the expression, so no data
+// is lost, but the column moves to the end of the table. DROP COLUMN drops
+// the single-column indexes on the column and narrows the composite ones, so
+// the declared indexes covering it are dropped first and created again once
+// the column is back.
+func (p *Planner) recreateGeneratedColumn(
+	result []ast.Node,
+	diff *types.SchemaDiff,
+	generated *goschema.Database,
+	table *goschema.Table,
+	tableName string,
+	colDiff types.ColumnDiff,
+	columnNode *ast.ColumnNode,
+) []ast.Node {
+	indexes := p.generatedColumnIndexes(diff, generated, table, colDiff.ColumnName)
+	result = append(result, ast.NewComment(fmt.Sprintf("Recreate generated column %s.%s: MySQL-family databases cannot change STORED/VIRTUAL in place",
+		tableName, colDiff.ColumnName)))
+	for _, idx := range indexes {
+		if len(indexFieldNames(idx)) > 1 {
+			result = append(result, p.dropIndexNode(types.IndexRemovalInfo{Name: idx.Name, TableName: tableName}))
+		}
+	}
+	result = append(result,
+		&ast.AlterTableNode{
+			Name:       tableName,
+			Operations: []ast.AlterOperation{&ast.DropColumnOperation{ColumnName: colDiff.ColumnName}},
+		},
+		&ast.AlterTableNode{
+			Name:       tableName,
+			Operations: []ast.AlterOperation{&ast.AddColumnOperation{Column: columnNode}},
+		},
+	)
+	for _, idx := range indexes {
+		result = append(result, p.indexNode(idx, generated))
+	}
+	return append(result, ast.NewComment(fmt.Sprintf("Modify column %s.%s: generated: %s", tableName, colDiff.ColumnName, colDiff.Changes["generated"])))
+}
+
+// generatedColumnIndexes returns the declared indexes of table that cover
+// column by name. Indexes the diff adds are skipped: addNewIndexes creates
+// them.
+func (p *Planner) generatedColumnIndexes(diff *types.SchemaDiff, generated *goschema.Database, table *goschema.Table, column string) []goschema.Index {
+	if table == nil {
+		return nil
+	}
+	var indexes []goschema.Index
+	for _, idx := range generated.Indexes {
+		indexTable := p.indexTableName(idx, generated)
+		if indexTable != table.Name && indexTable != table.QualifiedName() || slices.Contains(diff.IndexesAdded, idx.Name) {
+			continue
+		}
+		if slices.ContainsFunc(indexFieldNames(idx), func(field string) bool { return strings.EqualFold(field, column) }) {
+			indexes = append(indexes, idx)
+		}
+	}
+	return indexes
+}
+
+// indexFieldNames returns the column names of idx's key parts. Expression
+// parts have no name and are left out.
+func indexFieldNames(idx goschema.Index) []string {
+	if len(idx.Parts) == 0 {
+		return idx.Fields
+	}
+	names := make([]string, 0, len(idx.Parts))
+	for _, part := range idx.Parts {
+		if part.Name != "" {
+			names = append(names, part.Name)
+		}
+	}
+	return names
 }
 
 func findGeneratedTable(tables []goschema.Table, tableName string) *goschema.Table {
@@ -423,6 +621,20 @@ func (p *Planner) validateColumnModification(tableName string, colDiff types.Col
 	}
 }
 
+// inTablePrimaryKey reports whether column belongs to the table-level primary
+// key of table.
+func inTablePrimaryKey(table *goschema.Table, column string) bool {
+	if table == nil {
+		return false
+	}
+	if slices.Contains(table.PrimaryKey, column) {
+		return true
+	}
+	return slices.ContainsFunc(table.PrimaryKeyParts, func(part goschema.PrimaryKeyPart) bool {
+		return part.Name == column
+	})
+}
+
 func primaryKeyColumnChangeOwnedByTableConstraint(diff *types.SchemaDiff, tableName, columnName string) bool {
 	for _, info := range diff.ConstraintsAddedWithTables {
 		if strings.EqualFold(info.Type, "PRIMARY KEY") &&
@@ -479,28 +691,47 @@ func (p *Planner) modifyExistingTables(result []ast.Node, diff *types.SchemaDiff
 			return result, err
 		}
 
+		// Drop removed indexes that cover a removed column first: MySQL
+		// drops or shrinks them with the column, and a later DROP INDEX
+		// would then fail.
+		for _, indexInfo := range removedIndexesOnRemovedColumns(diff, tableDiff) {
+			result = append(result, p.dropIndexNode(indexInfo))
+		}
+
 		// Remove columns (dangerous!)
 		result, err = p.removeColumns(result, &tableDiff)
 		if err != nil {
 			return result, err
 		}
+
+		result = appendAutoIncrementChange(result, tableDiff)
 	}
 	return result, nil
 }
 
+// appendAutoIncrementChange raises the table AUTO_INCREMENT counter recorded
+// in tableDiff.AutoIncrement ("old -> new") to its new value.
+func appendAutoIncrementChange(result []ast.Node, tableDiff types.TableDiff) []ast.Node {
+	if tableDiff.AutoIncrement == "" {
+		return result
+	}
+	_, after, _ := strings.Cut(tableDiff.AutoIncrement, " -> ")
+	value, err := strconv.ParseInt(strings.TrimSpace(after), 10, 64)
+	if err != nil {
+		return append(result, ast.NewComment(fmt.Sprintf("ERROR: invalid AUTO_INCREMENT change for %s: %s", tableDiff.TableName, tableDiff.AutoIncrement)))
+	}
+	return append(result, &ast.AlterTableNode{
+		Name:       tableDiff.TableName,
+		Operations: []ast.AlterOperation{&ast.SetAutoIncrementOperation{Value: value}},
+	})
+}
+
 func (p *Planner) addNewIndexes(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	for _, indexName := range diff.IndexesAdded {
 		// Find the index definition
 		for _, idx := range generated.Indexes {
 			if idx.Name == indexName {
-				indexNode := ast.NewIndex(idx.Name, p.indexTableName(idx, generated), idx.Fields...)
-				if idx.Unique {
-					indexNode.Unique = true
-				}
-				if idx.Comment != "" {
-					indexNode.Comment = idx.Comment
-				}
-				result = append(result, indexNode)
+				result = append(result, p.indexNode(idx, generated))
 				break
 			}
 		}
@@ -508,6 +739,25 @@ func (p *Planner) addNewIndexes(result []ast.Node, diff *types.SchemaDiff, gener
 	return result
 }
 
+// indexNode builds the CREATE INDEX node for a declared index.
+func (p *Planner) indexNode(idx goschema.Index, generated *goschema.Database) *ast.IndexNode {
+	indexNode := ast.NewIndex(idx.Name, p.indexTableName(idx, generated), idx.Fields...)
+	if len(idx.Parts) > 0 {
+		indexNode.SetParts(fromschema.ToASTIndexParts(idx.Parts))
+	}
+	if idx.Unique {
+		indexNode.Unique = true
+	}
+	indexNode.Type = idx.Type
+	indexNode.Parser = idx.Parser
+	indexNode.Invisible = idx.Visible != nil && !*idx.Visible
+	indexNode.Online = p.onlineDDLEnabled() && onlineIndex(idx.Type)
+	if idx.Comment != "" {
+		indexNode.Comment = idx.Comment
+	}
+	return indexNode
+}
+
 func (p *Planner) indexTableName(index goschema.Index, generated *goschema.Database) string {
 	if index.TableName != "" {
 		return index.TableName
@@ -520,6 +770,18 @@ func (p *Planner) indexTableName(index goschema.Index, generated *goschema.Datab
 	return index.StructName
 }
 
+// changeIndexVisibility toggles INVISIBLE (MariaDB: IGNORED) in place for
+// indexes whose definition is unchanged, avoiding a drop and rebuild.
+func (p *Planner) changeIndexVisibility(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, change := range diff.IndexesVisibilityChanged {
+		result = append(result, &ast.AlterTableNode{
+			Name:       change.TableName,
+			Operations: []ast.AlterOperation{&ast.AlterIndexVisibilityOperation{Index: change.Name, Visible: change.Visible}},
+		})
+	}
+	return result
+}
+
 func (p *Planner) removeIndexes(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
 	// The IF EXISTS guard on DROP INDEX is capability-gated INTENT (issue
 	// #226): MariaDB accepts it, MySQL has no such form. The renderer
@@ -531,13 +793,19 @@ func (p *Planner) removeIndexes(result []ast.Node, diff *types.SchemaDiff) []ast
 
 	// Use the detailed removal info if available (includes table names for MySQL/MariaDB)
 	if len(diff.IndexesRemovedWithTables) > 0 {
+		droppedWithColumns := make(map[string]bool)
+		for _, tableDiff := range diff.TablesModified {
+			for _, indexInfo := range removedIndexesOnRemovedColumns(diff, tableDiff) {
+				droppedWithColumns[indexInfo.TableName+"."+indexInfo.Name] = true
+			}
+		}
 		for _, indexInfo := range diff.IndexesRemovedWithTables {
-			dropIndexNode := ast.NewDropIndex(indexInfo.Name).
-				SetTable(indexInfo.TableName)
-			if guarded {
-				dropIndexNode.SetIfExists()
+			// DROP TABLE removes the table's indexes; a separate DROP INDEX
+			// fails while a foreign key of the dropped table still needs it.
+			if droppedWithColumns[indexInfo.TableName+"."+indexInfo.Name] || diff.IndexDroppedWithTable(indexInfo) {
+				continue
 			}
-			result = append(result, dropIndexNode)
+			result = append(result, p.dropIndexNode(indexInfo))
 		}
 	} else {
 		// Use name-only diff input when the caller did not populate
@@ -553,8 +821,40 @@ func (p *Planner) removeIndexes(result []ast.Node, diff *types.SchemaDiff) []ast
 	return result
 }
 
+func (p *Planner) dropIndexNode(indexInfo types.IndexRemovalInfo) *ast.DropIndexNode {
+	dropIndexNode := ast.NewDropIndex(indexInfo.Name).
+		SetTable(indexInfo.TableName)
+	if p.capabilities().Has(capability.DropIndexIfExists) {
+		dropIndexNode.SetIfExists()
+	}
+	return dropIndexNode
+}
+
+// removedIndexesOnRemovedColumns returns the removed indexes of tableDiff's
+// table that cover one of the columns tableDiff removes. Removals without
+// column metadata are left to removeIndexes.
+func removedIndexesOnRemovedColumns(diff *types.SchemaDiff, tableDiff types.TableDiff) []types.IndexRemovalInfo {
+	if len(tableDiff.ColumnsRemoved) == 0 {
+		return nil
+	}
+	var indexes []types.IndexRemovalInfo
+	for _, indexInfo := range diff.IndexesRemovedWithTables {
+		if indexInfo.TableName != tableDiff.TableName {
+			continue
+		}
+		if slices.ContainsFunc(indexInfo.Columns, func(column string) bool {
+			return slices.ContainsFunc(tableDiff.ColumnsRemoved, func(removed string) bool {
+				return strings.EqualFold(removed, column)
+			})
+		}) {
+			indexes = append(indexes, indexInfo)
+		}
+	}
+	return indexes
+}
+
 func (p *Planner) removeTables(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
-	for _, tableName := range deporder.TableDropOrder(diff.TablesRemoved, generated) {
+	for _, tableName := range deporder.TableDropOrderWithDependencies(diff.TablesRemoved, generated, diff.TableDropDependencies) {
 		dropTableNode := ast.NewDropTable(tableName).
 			SetIfExists().
 			SetCascade().
@@ -583,7 +883,8 @@ func (p *Planner) handleEnumRemovals(result []ast.Node, diff *types.SchemaDiff)
 //
 // The SQL statements are generated in a specific order to avoid dependency conflicts:
 //  1. Create new tables (MySQL handles enums inline, no separate enum creation needed)
-//  2. Modify existing tables (add/modify/remove columns)
+//  2. Modify existing tables (add/modify/remove columns); removed indexes
+//     covering a removed column are dropped before the column
 //  3. Add new indexes
 //  4. Remove constraints
 //  5. Remove indexes (safe operations)
@@ -654,16 +955,28 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	if err := p.rejectUniqueIncludeConstraints(diff, generated); err != nil {
 		return nil, err
 	}
+	if err := p.rejectUniqueNullsNotDistinct(diff, generated); err != nil {
+		return nil, err
+	}
 
 	// Note: MySQL doesn't use separate enum types like PostgreSQL
 	// Enums are handled inline in column definitions, so we skip enum creation steps
 
+	// 0. Delete removed seed rows first, while every table and column they
+	// were written against still exists
+	result = p.removeSeedRows(result, diff)
+
 	// 1. Add enum change warnings (MySQL limitations)
 	result = p.addEnumChangeWarnings(result, diff)
 
 	// 2. Handle enum modifications (MySQL limitations)
 	result = p.handleEnumModifications(result, diff)
 
+	// 2.5. Add and modify sequences (MariaDB) before tables so column
+	// defaults can draw from them
+	result = p.addNewSequences(result, diff, generated)
+	result = p.modifyExistingSequences(result, diff, generated)
+
 	// 3. Add new tables
 	result = p.addNewTables(result, diff, generated)
 
@@ -683,8 +996,9 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	result = p.addNewTriggers(result, diff, generated)
 	result = p.modifyExistingTriggers(result, diff, generated)
 
-	// 5. Add new indexes
+	// 5. Add new indexes, then toggle the visibility of unchanged ones
 	result = p.addNewIndexes(result, diff, generated)
+	result = p.changeIndexVisibility(result, diff)
 
 	// 5.5. Add new constraints (must be done after tables and columns exist)
 	result = p.addNewConstraints(result, diff, generated)
@@ -693,6 +1007,9 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	// unique indexes and constraints have been created.
 	result = p.addForeignKeyConstraintsForNewTables(result, diff, generated)
 
+	// 5.7. Insert seed rows once tables, columns, and constraints exist
+	result = p.addSeedRows(result, diff)
+
 	// 6. Remove constraints before indexes. MySQL-family servers keep the
 	// backing index after DROP FOREIGN KEY when the index was auto-created, so
 	// rollback plans may need to drop both. The FK must go first.
@@ -708,12 +1025,94 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	// 7. Remove tables (dangerous!)
 	result = p.removeTables(result, diff, generated)
 
+	// 7.5. Remove sequences once no table draws from them
+	result = p.removeSequences(result, diff)
+
 	// 8. Handle enum removals (MySQL-specific warnings)
 	result = p.handleEnumRemovals(result, diff)
 
 	return result, nil
 }
 
+// addSeedRows inserts the rows of every added seed. INSERT IGNORE lets an
+// always="true" seed be re-applied to a table that already holds its rows.
+// SQL Server has no equivalent statement, so its seeds become comments.
+func (p *Planner) addSeedRows(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, seed := range diff.SeedsAdded {
+		if p.targetDialect() == platform.SQLServer {
+			result = append(result, ast.NewComment(fmt.Sprintf("WARNING: seed rows for table %s are not generated for SQL Server; insert them manually.", seed.Table)))
+			continue
+		}
+		values := make([]string, 0, len(seed.Rows))
+		for _, row := range seed.Rows {
+			literals := make([]string, len(row))
+			for i, value := range row {
+				literals[i] = mysqlSeedLiteral(value)
+			}
+			values = append(values, "("+strings.Join(literals, ", ")+")")
+		}
+		columns := make([]string, len(seed.Columns))
+		for i, column := range seed.Columns {
+			columns[i] = quoteMySQLIdentifier(column)
+		}
+		result = append(result,
+			ast.NewComment(fmt.Sprintf("Seed rows for table: %s", seed.Table)),
+			ast.NewRawSQL(fmt.Sprintf("INSERT IGNORE INTO %s (%s) VALUES %s",
+				quoteMySQLIdentifierPath(seed.Table), strings.Join(columns, ", "), strings.Join(values, ", "))),
+		)
+	}
+	return result
+}
+
+// removeSeedRows deletes exactly the rows each removed seed inserted. Seeds of
+// tables this plan drops are left to DROP TABLE.
+func (p *Planner) removeSeedRows(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	if p.targetDialect() == platform.SQLServer {
+		return result
+	}
+	for _, seed := range diff.SeedsRemoved {
+		if slices.Contains(diff.TablesRemoved, seed.Table) {
+			continue
+		}
+		result = append(result, ast.NewComment(fmt.Sprintf("Remove seed rows from table: %s", seed.Table)))
+		for _, row := range seed.Rows {
+			conditions := make([]string, len(row))
+			for i, value := range row {
+				conditions[i] = quoteMySQLIdentifier(seed.Columns[i]) + " = " + mysqlSeedLiteral(value)
+				if value == "NULL" {
+					conditions[i] = quoteMySQLIdentifier(seed.Columns[i]) + " IS NULL"
+				}
+			}
+			result = append(result, ast.NewRawSQL(fmt.Sprintf("DELETE FROM %s WHERE %s",
+				quoteMySQLIdentifierPath(seed.Table), strings.Join(conditions, " AND "))))
+		}
+	}
+	return result
+}
+
+// mysqlSeedLiteral quotes a seed value as a string literal, escaping
+// backslashes as well as quotes because MySQL treats backslash as an escape
+// character by default.
+func mysqlSeedLiteral(value string) string {
+	if value == "NULL" {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func quoteMySQLIdentifierPath(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quoteMySQLIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func quoteMySQLIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
 func (p *Planner) rejectUniqueIncludeConstraints(diff *types.SchemaDiff, generated *goschema.Database) error {
 	if diff != nil {
 		for _, add := range diff.ConstraintsAddedWithTables {
@@ -748,6 +1147,50 @@ func (p *Planner) uniqueIncludeUnsupportedError(constraintName string) error {
 	}
 }
 
+// rejectUniqueNullsNotDistinct fails the plan when a unique constraint or
+// index asks for NULLS NOT DISTINCT, which MySQL, MariaDB, and SQL Server
+// cannot express, instead of silently creating a NULLS DISTINCT one.
+func (p *Planner) rejectUniqueNullsNotDistinct(diff *types.SchemaDiff, generated *goschema.Database) error {
+	if p.capabilities().Has(capability.UniqueNullsNotDistinct) {
+		return nil
+	}
+	if diff != nil {
+		for _, add := range diff.ConstraintsAddedWithTables {
+			if add.NullsDistinct != nil && !*add.NullsDistinct {
+				return p.uniqueNullsNotDistinctUnsupportedError("constraint", add.Name)
+			}
+		}
+	}
+	if generated == nil {
+		return nil
+	}
+	for _, constraint := range generated.Constraints {
+		if constraint.NullsDistinct != nil && !*constraint.NullsDistinct {
+			return p.uniqueNullsNotDistinctUnsupportedError("constraint", constraint.Name)
+		}
+	}
+	for _, index := range generated.Indexes {
+		if index.NullsDistinct != nil && !*index.NullsDistinct {
+			return p.uniqueNullsNotDistinctUnsupportedError("index", index.Name)
+		}
+	}
+	return nil
+}
+
+func (p *Planner) uniqueNullsNotDistinctUnsupportedError(kind, name string) error {
+	return &ptaherr.CapabilityError{
+		Dialect: p.targetDialect(),
+		Feature: "unique nulls not distinct",
+		Err:     ptaherr.ErrUnsupportedFeature,
+		Message: fmt.Sprintf(
+			"%s does not support UNIQUE NULLS NOT DISTINCT; remove nulls_not_distinct from %s %s or target PostgreSQL 15+",
+			p.enumDialectLabel(),
+			kind,
+			name,
+		),
+	}
+}
+
 func (p *Planner) rejectMaterializedViews(diff *types.SchemaDiff) error {
 	if len(diff.MaterializedViewsAdded) == 0 &&
 		len(diff.MaterializedViewsModified) == 0 &&
@@ -834,6 +1277,102 @@ func findTrigger(triggers []goschema.Trigger, tableName, triggerName string) *go
 	return nil
 }
 
+// addNewSequences emits CREATE SEQUENCE for newly added sequences on targets
+// with sequence objects (MariaDB 10.3+). It runs before table creation so a
+// column DEFAULT (NEXT VALUE FOR ...) can draw from the sequence. MariaDB has
+// no OWNED BY before 11.5, so the association is dropped.
+func (p *Planner) addNewSequences(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
+	if !p.capabilities().Has(capability.Sequences) {
+		return result
+	}
+	for _, name := range diff.SequencesAdded {
+		sequence := findSequence(generated.Sequences, name)
+		if sequence == nil {
+			continue
+		}
+		sequenceNode := fromschema.FromSequence(*sequence)
+		sequenceNode.OwnedBy = ""
+		result = append(result, sequenceNode)
+	}
+	return result
+}
+
+// modifyExistingSequences emits ALTER SEQUENCE carrying only the changed
+// options the target can express.
+func (p *Planner) modifyExistingSequences(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
+	if !p.capabilities().Has(capability.Sequences) {
+		return result
+	}
+	for _, sequenceDiff := range diff.SequencesModified {
+		sequence := findSequence(generated.Sequences, sequenceDiff.SequenceName)
+		if sequence == nil {
+			continue
+		}
+		node := alterSequenceFromDiff(*sequence, sequenceDiff.Changes)
+		changed := strings.Join(slices.Sorted(maps.Keys(sequenceDiff.Changes)), ", ")
+		node.SetComment(fmt.Sprintf("Modify sequence %s: %s", sequenceDiff.SequenceName, changed))
+		result = append(result, node)
+	}
+	return result
+}
+
+// alterSequenceFromDiff builds an ALTER SEQUENCE node from the target
+// definition for the options the diff reports as changed. AS <type> and
+// OWNED BY have no MariaDB spelling and are never set.
+func alterSequenceFromDiff(target goschema.Sequence, changes map[string]string) *ast.AlterSequenceNode {
+	node := ast.NewAlterSequence(target.Name)
+	if target.Schema != "" {
+		node.SetSchema(target.Schema)
+	}
+	if _, ok := changes["start"]; ok && target.Start != nil {
+		node.SetStart(*target.Start)
+	}
+	if _, ok := changes["increment"]; ok && target.Increment != nil {
+		node.SetIncrement(*target.Increment)
+	}
+	if _, ok := changes["minvalue"]; ok && target.MinValue != nil {
+		node.SetMinValue(*target.MinValue)
+	}
+	if _, ok := changes["maxvalue"]; ok && target.MaxValue != nil {
+		node.SetMaxValue(*target.MaxValue)
+	}
+	if _, ok := changes["cache"]; ok && target.Cache != nil {
+		node.SetCache(*target.Cache)
+	}
+	if _, ok := changes["cycle"]; ok {
+		node.SetCycle(target.Cycle)
+	}
+	return node
+}
+
+// removeSequences emits DROP SEQUENCE for sequences no longer in the target
+// schema, after table removal so no column default still draws from them.
+func (p *Planner) removeSequences(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	if !p.capabilities().Has(capability.Sequences) {
+		return result
+	}
+	for _, name := range diff.SequencesRemoved {
+		dropSequence := ast.NewDropSequence(name).
+			SetIfExists().
+			SetComment("WARNING: Ensure no column default still draws from this sequence")
+		if idx := strings.LastIndex(name, "."); idx >= 0 {
+			dropSequence.Name = name[idx+1:]
+			dropSequence.SetSchema(name[:idx])
+		}
+		result = append(result, dropSequence)
+	}
+	return result
+}
+
+func findSequence(sequences []goschema.Sequence, name string) *goschema.Sequence {
+	for i := range sequences {
+		if sequences[i].QualifiedName() == name {
+			return &sequences[i]
+		}
+	}
+	return nil
+}
+
 // addNewConstraints adds new table-level constraints via ALTER TABLE statements.
 //
 // This method processes constraints defined through Go struct annotations and creates
@@ -1575,3 +2114,32 @@ func previousColumnNullable(change string) bool {
 	before, _, ok := strings.Cut(change, " -> ")
 	return ok && strings.TrimSpace(before) == "true"
 }
+
+// typeChangeWarning returns the comment flagging a column type change that can
+// reject or lose existing data, or nil when the change is widening or
+// unclassified. Narrowing changes suggest a query to check the data first.
+func (p *Planner) typeChangeWarning(tableName string, colDiff types.ColumnDiff, newType string) *ast.CommentNode {
+	switch colDiff.TypeChangeKind {
+	case types.TypeChangeNarrowing:
+		check := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", colDiff.ColumnName, colDiff.ColumnName, tableName)
+		if isStringType(newType) {
+			length := "CHAR_LENGTH"
+			if p.targetDialect() == platform.SQLServer {
+				length = "LEN"
+			}
+			check = fmt.Sprintf("SELECT MAX(%s(%s)) FROM %s", length, colDiff.ColumnName, tableName)
+		}
+		return ast.NewComment(fmt.Sprintf("WARNING: narrowing type change on %s.%s (%s) can reject existing values; check the data first: %s",
+			tableName, colDiff.ColumnName, colDiff.Changes["type"], check))
+	case types.TypeChangeIncompatible:
+		return ast.NewComment(fmt.Sprintf("WARNING: incompatible type change on %s.%s (%s); existing values may fail to convert",
+			tableName, colDiff.ColumnName, colDiff.Changes["type"]))
+	default:
+		return nil
+	}
+}
+
+func isStringType(typeName string) bool {
+	upper := strings.ToUpper(typeName)
+	return strings.Contains(upper, "CHAR") || strings.Contains(upper, "TEXT")
+}
@@ -0,0 +1,57 @@
+package mysql_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func onUpdateSchema() *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Post", Name: "posts"}},
+		Fields: []goschema.Field{{
+			StructName:       "Post",
+			Name:             "updated_at",
+			Type:             "TIMESTAMP",
+			DefaultExpr:      "CURRENT_TIMESTAMP",
+			UpdateExpression: "CURRENT_TIMESTAMP",
+		}},
+	}
+}
+
+func TestPlanner_ModifyColumnKeepsOnUpdate(t *testing.T) {
+	for _, dialect := range mysqlFamilyDialects {
+		t.Run(dialect, func(t *testing.T) {
+			c := qt.New(t)
+			diff := &types.SchemaDiff{TablesModified: []types.TableDiff{{
+				TableName:       "posts",
+				ColumnsModified: []types.ColumnDiff{{ColumnName: "updated_at", Changes: map[string]string{"nullable": "false -> true"}}},
+			}}}
+
+			sql := renderMySQLFamily(c, dialect, diff, onUpdateSchema())
+
+			c.Assert(sql, qt.Contains, "MODIFY COLUMN updated_at TIMESTAMP")
+			c.Assert(sql, qt.Contains, "ON UPDATE CURRENT_TIMESTAMP")
+		})
+	}
+}
+
+func TestPlanner_AddColumnKeepsOnUpdate(t *testing.T) {
+	for _, dialect := range mysqlFamilyDialects {
+		t.Run(dialect, func(t *testing.T) {
+			c := qt.New(t)
+			diff := &types.SchemaDiff{TablesModified: []types.TableDiff{{
+				TableName:    "posts",
+				ColumnsAdded: []string{"updated_at"},
+			}}}
+
+			sql := renderMySQLFamily(c, dialect, diff, onUpdateSchema())
+
+			c.Assert(sql, qt.Contains, "ADD COLUMN updated_at TIMESTAMP")
+			c.Assert(sql, qt.Contains, "ON UPDATE CURRENT_TIMESTAMP")
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package mysql_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_ColumnJoiningPrimaryKeyIsModifiedNotNull(t *testing.T) {
+	for _, dialect := range mysqlFamilyDialects {
+		t.Run(dialect, func(t *testing.T) {
+			c := qt.New(t)
+			diff := &types.SchemaDiff{TablesModified: []types.TableDiff{{
+				TableName:       "memberships",
+				ColumnsModified: []types.ColumnDiff{{ColumnName: "user_id", Changes: map[string]string{"nullable": "true -> false"}}},
+			}}}
+			generated := &goschema.Database{
+				Tables: []goschema.Table{{StructName: "Membership", Name: "memberships", PrimaryKey: []string{"org_id", "user_id"}}},
+				Fields: []goschema.Field{
+					{StructName: "Membership", Name: "org_id", Type: "INTEGER"},
+					{StructName: "Membership", Name: "user_id", Type: "INTEGER", Nullable: true},
+				},
+			}
+
+			sql := renderMySQLFamily(c, dialect, diff, generated)
+
+			c.Assert(sql, qt.Contains, "MODIFY COLUMN user_id INTEGER NOT NULL")
+		})
+	}
+}
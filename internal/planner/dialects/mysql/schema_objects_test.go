@@ -1,11 +1,13 @@
 package mysql_test
 
 import (
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
 
 	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform/capability"
 	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/core/renderer"
 	"github.com/stokaro/ptah/internal/planner/dialects/mysql"
@@ -75,6 +77,54 @@ func TestPlanner_GenerateMigrationASTChecked_RejectsUniqueIncludeColumns(t *test
 	c.Assert(err, qt.ErrorMatches, "MySQL-family does not support PostgreSQL INCLUDE columns on UNIQUE constraints.*")
 }
 
+func TestPlanner_GenerateMigrationASTChecked_RejectsUniqueNullsNotDistinct(t *testing.T) {
+	nullsDistinct := false
+	tests := []struct {
+		name      string
+		generated *goschema.Database
+		wantError string
+	}{
+		{
+			name: "constraint",
+			generated: &goschema.Database{
+				Constraints: []goschema.Constraint{{
+					StructName:    "User",
+					Name:          "users_email_key",
+					Type:          "UNIQUE",
+					Table:         "users",
+					Columns:       []string{"email"},
+					NullsDistinct: &nullsDistinct,
+				}},
+			},
+			wantError: "MySQL-family does not support UNIQUE NULLS NOT DISTINCT; remove nulls_not_distinct from constraint users_email_key.*",
+		},
+		{
+			name: "unique index",
+			generated: &goschema.Database{
+				Indexes: []goschema.Index{{
+					StructName:    "User",
+					Name:          "idx_users_email",
+					Fields:        []string{"email"},
+					Unique:        true,
+					NullsDistinct: &nullsDistinct,
+				}},
+			},
+			wantError: "MySQL-family does not support UNIQUE NULLS NOT DISTINCT; remove nulls_not_distinct from index idx_users_email.*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, err := mysql.New().GenerateMigrationASTChecked(&difftypes.SchemaDiff{}, tt.generated)
+
+			c.Assert(err, qt.ErrorIs, ptaherr.ErrUnsupportedFeature)
+			c.Assert(err, qt.ErrorMatches, tt.wantError)
+		})
+	}
+}
+
 func TestPlanner_GenerateSchemaDiffSQLStatements_CompoundTriggerBody(t *testing.T) {
 	c := qt.New(t)
 
@@ -132,3 +182,71 @@ func TestPlanner_GenerateMigrationAST_RejectsMaterializedViews(t *testing.T) {
 		_ = planner.GenerateMigrationAST(diff, generated)
 	}, qt.Not(qt.PanicMatches), ".*")
 }
+
+func mariaDBSequenceFixture() (*goschema.Database, *difftypes.SchemaDiff) {
+	start, increment := int64(1000), int64(5)
+	generated := &goschema.Database{
+		Sequences: []goschema.Sequence{{
+			Name:      "order_numbers",
+			AsType:    "integer",
+			Start:     &start,
+			Increment: &increment,
+			OwnedBy:   "orders.number",
+		}},
+		Tables: []goschema.Table{{StructName: "Order", Name: "orders"}},
+		Fields: []goschema.Field{{
+			StructName:  "Order",
+			Name:        "number",
+			Type:        "BIGINT",
+			DefaultExpr: "NEXT VALUE FOR order_numbers",
+		}},
+	}
+	diff := &difftypes.SchemaDiff{
+		TablesAdded:      []string{"orders"},
+		SequencesAdded:   []string{"order_numbers"},
+		SequencesRemoved: []string{"legacy_numbers"},
+	}
+	return generated, diff
+}
+
+func TestPlanner_GenerateMigrationAST_MariaDBSequences(t *testing.T) {
+	c := qt.New(t)
+	generated, diff := mariaDBSequenceFixture()
+
+	nodes := mysql.NewForDialect("mariadb", capability.MariaDB1011()).GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("mariadb", nodes...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, "CREATE SEQUENCE `order_numbers` START WITH 1000 INCREMENT BY 5;")
+	c.Assert(sql, qt.Contains, "DROP SEQUENCE IF EXISTS `legacy_numbers`;")
+	c.Assert(sql, qt.Not(qt.Contains), "OWNED BY")
+	create := strings.Index(sql, "CREATE SEQUENCE")
+	table := strings.Index(sql, "CREATE TABLE `orders`")
+	c.Assert(table > create, qt.IsTrue, qt.Commentf("sequence must exist before the table that draws from it:\n%s", sql))
+}
+
+func TestPlanner_GenerateMigrationAST_MariaDBSequenceModified(t *testing.T) {
+	c := qt.New(t)
+	generated, _ := mariaDBSequenceFixture()
+	diff := &difftypes.SchemaDiff{SequencesModified: []difftypes.SequenceDiff{{
+		SequenceName: "order_numbers",
+		Changes:      map[string]string{"increment": "1 -> 5", "as": "bigint -> integer"},
+	}}}
+
+	nodes := mysql.NewForDialect("mariadb", capability.MariaDB1011()).GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("mariadb", nodes...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Equals, "-- Modify sequence order_numbers: as, increment\nALTER SEQUENCE `order_numbers` INCREMENT BY 5;\n")
+}
+
+func TestPlanner_GenerateMigrationAST_MySQLSkipsSequences(t *testing.T) {
+	c := qt.New(t)
+	generated, diff := mariaDBSequenceFixture()
+
+	nodes := mysql.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("mysql", nodes...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Not(qt.Contains), "SEQUENCE")
+}
@@ -0,0 +1,42 @@
+package mysql_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/mysql"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_SeedRows(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		TablesAdded: []string{"roles"},
+		SeedsAdded: []types.SeedData{{
+			Table:   "roles",
+			Columns: []string{"id", "name"},
+			Rows:    [][]string{{"1", `a\b`}, {"2", "NULL"}},
+		}},
+		SeedsRemoved: []types.SeedData{{
+			Table:   "groups",
+			Columns: []string{"id"},
+			Rows:    [][]string{{"7"}},
+		}},
+	}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Role", Name: "roles"}},
+		Fields: []goschema.Field{{StructName: "Role", Name: "id", Type: "INT", Primary: true}},
+	}
+
+	nodes := mysql.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("mysql", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Contains, `INSERT IGNORE INTO roles (id, name) VALUES ('1', 'a\\b'), ('2', NULL);`)
+	c.Assert(sql, qt.Contains, "DELETE FROM groups WHERE id = '7';")
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/core/renderer"
 	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
 	"github.com/stokaro/ptah/migration/schemadiff/types"
@@ -66,3 +67,150 @@ func TestPlanner_CapabilityGatesRLSAndRoleManagement(t *testing.T) {
 	c.Assert(sql, qt.Not(qt.Contains), "CREATE POLICY")
 	c.Assert(sql, qt.Not(qt.Contains), "DROP POLICY")
 }
+
+func TestPlanner_UniqueNullsNotDistinctRendering(t *testing.T) {
+	notDistinct := false
+	distinct := true
+	tests := []struct {
+		name          string
+		nullsDistinct *bool
+		want          string
+	}{
+		{name: "default", nullsDistinct: nil, want: `ALTER TABLE "users" ADD CONSTRAINT "users_email_key" UNIQUE ("email");`},
+		{name: "explicit nulls distinct", nullsDistinct: &distinct, want: `ALTER TABLE "users" ADD CONSTRAINT "users_email_key" UNIQUE NULLS DISTINCT ("email");`},
+		{name: "nulls not distinct", nullsDistinct: &notDistinct, want: `ALTER TABLE "users" ADD CONSTRAINT "users_email_key" UNIQUE NULLS NOT DISTINCT ("email");`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			diff := &types.SchemaDiff{
+				ConstraintsAdded: []string{"users_email_key"},
+				ConstraintsAddedWithTables: []types.ConstraintAdditionInfo{{
+					Name:          "users_email_key",
+					TableName:     "users",
+					Type:          "UNIQUE",
+					Columns:       []string{"email"},
+					NullsDistinct: tt.nullsDistinct,
+				}},
+			}
+			generated := &goschema.Database{
+				Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+				Constraints: []goschema.Constraint{{
+					StructName:    "User",
+					Name:          "users_email_key",
+					Type:          "UNIQUE",
+					Table:         "users",
+					Columns:       []string{"email"},
+					NullsDistinct: tt.nullsDistinct,
+				}},
+			}
+
+			nodes, err := postgres.NewWithCapabilities(capability.Postgres16()).GenerateMigrationASTChecked(diff, generated)
+			c.Assert(err, qt.IsNil)
+			sql, err := renderer.RenderSQLWithCapabilities("postgres", capability.Postgres16(), nodes...)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Contains, tt.want)
+		})
+	}
+}
+
+func TestPlanner_UniqueNullsNotDistinctRejectedWithoutCapability(t *testing.T) {
+	notDistinct := false
+	tests := []struct {
+		name      string
+		caps      capability.Capabilities
+		generated *goschema.Database
+		wantError string
+	}{
+		{
+			name: "postgres 14 constraint",
+			caps: capability.Postgres14(),
+			generated: &goschema.Database{
+				Constraints: []goschema.Constraint{{
+					Name:          "users_email_key",
+					Type:          "UNIQUE",
+					Table:         "users",
+					Columns:       []string{"email"},
+					NullsDistinct: &notDistinct,
+				}},
+			},
+			wantError: ".*remove nulls_not_distinct from constraint users_email_key.*",
+		},
+		{
+			name: "cockroachdb index",
+			caps: capability.CockroachDB23(),
+			generated: &goschema.Database{
+				Indexes: []goschema.Index{{
+					Name:          "idx_users_email",
+					StructName:    "User",
+					Fields:        []string{"email"},
+					Unique:        true,
+					NullsDistinct: &notDistinct,
+				}},
+			},
+			wantError: ".*remove nulls_not_distinct from index idx_users_email.*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, err := postgres.NewWithCapabilities(tt.caps).GenerateMigrationASTChecked(&types.SchemaDiff{}, tt.generated)
+
+			c.Assert(err, qt.ErrorIs, ptaherr.ErrUnsupportedFeature)
+			c.Assert(err, qt.ErrorMatches, tt.wantError)
+		})
+	}
+}
+
+func uuidDefaultSchema(extensions ...goschema.Extension) *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{{Name: "accounts", StructName: "Account"}},
+		Fields: []goschema.Field{{
+			StructName:  "Account",
+			Name:        "id",
+			Type:        "UUID",
+			Primary:     true,
+			DefaultExpr: "gen_random_uuid()",
+		}},
+		Extensions: extensions,
+	}
+}
+
+func TestPlanner_RandomUUIDDefaultRequiresPgcryptoBeforePostgres13(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := postgres.NewWithCapabilities(capability.Postgres12()).GenerateMigrationASTChecked(&types.SchemaDiff{}, uuidDefaultSchema())
+
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrUnsupportedFeature)
+	c.Assert(err, qt.ErrorMatches, ".*declare the pgcrypto extension for the default of field Account.id.*")
+}
+
+func TestPlanner_RandomUUIDDefaultWithPgcryptoCreatesExtension(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		ExtensionsAdded: []string{"pgcrypto"},
+		TablesAdded:     []string{"accounts"},
+	}
+	generated := uuidDefaultSchema(goschema.Extension{Name: "pgcrypto", IfNotExists: true})
+
+	nodes, err := postgres.NewWithCapabilities(capability.Postgres12()).GenerateMigrationASTChecked(diff, generated)
+	c.Assert(err, qt.IsNil)
+	sql, err := renderer.RenderSQLWithCapabilities("postgres", capability.Postgres12(), nodes...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, `CREATE EXTENSION IF NOT EXISTS "pgcrypto"`)
+	c.Assert(sql, qt.Contains, "DEFAULT gen_random_uuid()")
+}
+
+func TestPlanner_RandomUUIDDefaultBuiltinFromPostgres13(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := postgres.NewWithCapabilities(capability.Postgres13()).GenerateMigrationASTChecked(&types.SchemaDiff{}, uuidDefaultSchema())
+
+	c.Assert(err, qt.IsNil)
+}
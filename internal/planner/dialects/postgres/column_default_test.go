@@ -0,0 +1,89 @@
+package postgres_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func columnDefaultDiff(changes map[string]string) *types.SchemaDiff {
+	return &types.SchemaDiff{TablesModified: []types.TableDiff{{
+		TableName:       "users",
+		ColumnsModified: []types.ColumnDiff{{ColumnName: "status", Changes: changes}},
+	}}}
+}
+
+func TestPlanner_DefaultOnlyChangeAltersDefault(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes map[string]string
+		field   goschema.Field
+		want    string
+	}{
+		{
+			name:    "set literal default",
+			changes: map[string]string{"default": "'pending' -> 'active'"},
+			field:   goschema.Field{StructName: "User", Name: "status", Type: "TEXT", Default: "active"},
+			want: "-- Add/modify columns for table: users --\n" +
+				"-- ALTER statements: --\n" +
+				"ALTER TABLE \"users\" ALTER COLUMN \"status\" SET DEFAULT 'active';\n" +
+				"\n" +
+				"-- Modify column users.status: default: 'pending' -> 'active' --\n",
+		},
+		{
+			name:    "set expression default",
+			changes: map[string]string{"default_expr": " -> now()"},
+			field:   goschema.Field{StructName: "User", Name: "status", Type: "TIMESTAMP", DefaultExpr: "now()"},
+			want: "-- Add/modify columns for table: users --\n" +
+				"-- ALTER statements: --\n" +
+				"ALTER TABLE \"users\" ALTER COLUMN \"status\" SET DEFAULT now();\n" +
+				"\n" +
+				"-- Modify column users.status: default_expr:  -> now() --\n",
+		},
+		{
+			name:    "drop default",
+			changes: map[string]string{"default": "'active' -> "},
+			field:   goschema.Field{StructName: "User", Name: "status", Type: "TEXT"},
+			want: "-- Add/modify columns for table: users --\n" +
+				"-- ALTER statements: --\n" +
+				"ALTER TABLE \"users\" ALTER COLUMN \"status\" DROP DEFAULT;\n" +
+				"\n" +
+				"-- Modify column users.status: default: 'active' ->  --\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated := &goschema.Database{
+				Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+				Fields: []goschema.Field{tt.field},
+			}
+
+			sql, err := renderer.RenderSQL("postgres", postgres.New().GenerateMigrationAST(columnDefaultDiff(tt.changes), generated)...)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestPlanner_DefaultWithTypeChangeKeepsFullAlter(t *testing.T) {
+	c := qt.New(t)
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+		Fields: []goschema.Field{{StructName: "User", Name: "status", Type: "VARCHAR(32)", Default: "active"}},
+	}
+	diff := columnDefaultDiff(map[string]string{"default": "'pending' -> 'active'", "type": "text -> varchar"})
+
+	sql, err := renderer.RenderSQL("postgres", postgres.New().GenerateMigrationAST(diff, generated)...)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"users\" ALTER COLUMN \"status\" TYPE VARCHAR(32);")
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"users\" ALTER COLUMN \"status\" SET DEFAULT 'active';")
+}
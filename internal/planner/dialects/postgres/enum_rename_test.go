@@ -0,0 +1,37 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_EnumRenameUsesAlterType(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		EnumsRenamed:  []types.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+		EnumsModified: []types.EnumDiff{{EnumName: "order_status", ValuesAdded: []string{"refunded"}}},
+	}
+	generated := &goschema.Database{
+		Enums: []goschema.Enum{{Name: "order_status", Values: []string{"new", "paid", "refunded"}, RenameFrom: "order_state"}},
+	}
+
+	nodes, err := postgres.New().GenerateMigrationASTChecked(diff, generated)
+	c.Assert(err, qt.IsNil)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+
+	rename := strings.Index(sql, `ALTER TYPE "order_state" RENAME TO "order_status";`)
+	addValue := strings.Index(sql, `ALTER TYPE "order_status" ADD VALUE 'refunded';`)
+	c.Assert(rename >= 0, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(addValue > rename, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(sql, qt.Not(qt.Contains), "DROP TYPE")
+	c.Assert(sql, qt.Not(qt.Contains), "CREATE TYPE")
+}
@@ -0,0 +1,33 @@
+package postgres_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_ExpressionIndexAlongsidePlainIndex(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{IndexesAdded: []string{"idx_users_email", "idx_users_email_lower"}}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+		Indexes: []goschema.Index{
+			{Name: "idx_users_email", StructName: "User", Fields: []string{"email"}},
+			{Name: "idx_users_email_lower", StructName: "User", Fields: []string{"lower(email)"}, Parts: []goschema.IndexPart{{Expr: "lower(email)"}}},
+		},
+	}
+
+	nodes := postgres.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Equals, "CREATE INDEX IF NOT EXISTS idx_users_email ON users (email);\n"+
+		"CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users ((lower(email)));\n")
+}
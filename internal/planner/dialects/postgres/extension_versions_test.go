@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func renderExtensionUpdate(c *qt.C, current, target string) string {
+	diff := &types.SchemaDiff{
+		ExtensionsModified: []types.ExtensionDiff{
+			{ExtensionName: "pg_trgm", CurrentVersion: current, TargetVersion: target},
+		},
+	}
+	generated := &goschema.Database{
+		Extensions: []goschema.Extension{{Name: "pg_trgm", Version: target}},
+	}
+
+	nodes, err := postgres.New().GenerateMigrationASTChecked(diff, generated)
+	c.Assert(err, qt.IsNil)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+	return sql
+}
+
+func TestPlanner_ExtensionVersionUpgrade(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderExtensionUpdate(c, "1.5", "1.6")
+
+	c.Assert(sql, qt.Contains, `ALTER EXTENSION "pg_trgm" UPDATE TO '1.6';`)
+	c.Assert(sql, qt.Not(qt.Contains), "WARNING")
+}
+
+func TestPlanner_ExtensionVersionComparesNumerically(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderExtensionUpdate(c, "1.9", "1.10")
+
+	c.Assert(sql, qt.Contains, `ALTER EXTENSION "pg_trgm" UPDATE TO '1.10';`)
+	c.Assert(sql, qt.Not(qt.Contains), "WARNING")
+}
+
+func TestPlanner_ExtensionVersionDowngradeWarns(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderExtensionUpdate(c, "1.6", "1.5")
+
+	c.Assert(sql, qt.Contains, "WARNING: Downgrading extension 'pg_trgm' from 1.6 to 1.5")
+	c.Assert(sql, qt.Contains, `ALTER EXTENSION "pg_trgm" UPDATE TO '1.5';`)
+}
+
+func TestPlanner_ExtensionVersionUnchangedEmitsNothing(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{
+		Extensions: []goschema.Extension{{Name: "pg_trgm", Version: "1.6"}},
+	}
+
+	nodes, err := postgres.New().GenerateMigrationASTChecked(&types.SchemaDiff{}, generated)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(nodes, qt.HasLen, 0)
+}
@@ -0,0 +1,86 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_RemoveFunction_WithTriggerDependents(t *testing.T) {
+	auditTrigger := types.TriggerRef{TriggerName: "orders_audit", TableName: "orders"}
+
+	tests := []struct {
+		name        string
+		diff        *types.SchemaDiff
+		contains    []string
+		notContains []string
+	}{
+		{
+			name: "trigger kept by the diff",
+			diff: &types.SchemaDiff{
+				FunctionsRemoved: []string{"audit_fn"},
+				FunctionTriggers: map[string][]types.TriggerRef{"audit_fn": {auditTrigger}},
+			},
+			contains: []string{
+				"-- MANUAL: function audit_fn is still executed by trigger orders_audit ON orders; drop or repoint those triggers, then DROP FUNCTION audit_fn",
+			},
+			notContains: []string{"DROP FUNCTION IF EXISTS"},
+		},
+		{
+			name: "trigger removed by the diff",
+			diff: &types.SchemaDiff{
+				FunctionsRemoved: []string{"audit_fn"},
+				FunctionTriggers: map[string][]types.TriggerRef{"audit_fn": {auditTrigger}},
+				TriggersRemoved:  []types.TriggerRef{auditTrigger},
+			},
+			contains:    []string{"DROP TRIGGER IF EXISTS orders_audit ON orders", "DROP FUNCTION IF EXISTS audit_fn();"},
+			notContains: []string{"MANUAL"},
+		},
+		{
+			name: "trigger table removed by the diff",
+			diff: &types.SchemaDiff{
+				FunctionsRemoved: []string{"audit_fn"},
+				FunctionTriggers: map[string][]types.TriggerRef{"audit_fn": {auditTrigger}},
+				TablesRemoved:    []string{"orders"},
+			},
+			contains:    []string{"DROP TABLE IF EXISTS orders", "DROP FUNCTION IF EXISTS audit_fn();"},
+			notContains: []string{"MANUAL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			nodes := postgres.New().GenerateMigrationAST(tt.diff, &goschema.Database{})
+			sql, err := renderer.RenderSQL("postgres", nodes...)
+
+			c.Assert(err, qt.IsNil)
+			sql = legacyRenderedSQL(sql)
+			for _, want := range tt.contains {
+				c.Assert(sql, qt.Contains, want)
+			}
+			for _, unwanted := range tt.notContains {
+				c.Assert(sql, qt.Not(qt.Contains), unwanted)
+			}
+			assertOrdered(c, sql, tt.contains)
+		})
+	}
+}
+
+// assertOrdered checks that the fragments appear in sql in the given order.
+func assertOrdered(c *qt.C, sql string, fragments []string) {
+	c.Helper()
+	position := 0
+	for _, fragment := range fragments {
+		index := strings.Index(sql[position:], fragment)
+		c.Assert(index >= 0, qt.IsTrue, qt.Commentf("%q not found after offset %d in:\n%s", fragment, position, sql))
+		position += index + len(fragment)
+	}
+}
@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_CreatesInheritedTableAfterParent(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{TablesAdded: []string{"capitals", "cities"}}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{
+			{StructName: "Capital", Name: "capitals", Inherits: []string{"cities"}},
+			{StructName: "City", Name: "cities"},
+		},
+		Fields: []goschema.Field{
+			{StructName: "City", Name: "name", Type: "TEXT", Nullable: true},
+			{StructName: "Capital", Name: "state", Type: "TEXT", Nullable: true},
+		},
+	}
+
+	nodes := postgres.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Contains, "CREATE TABLE capitals (\n  state TEXT\n) INHERITS (cities);")
+	c.Assert(strings.Index(sql, "CREATE TABLE cities"), qt.Not(qt.Equals), -1)
+	c.Assert(strings.Index(sql, "CREATE TABLE cities") < strings.Index(sql, "CREATE TABLE capitals"), qt.IsTrue)
+}
+
+func TestPlanner_AlterTableInheritance(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		TablesRemoved: []string{"cities"},
+		TablesModified: []types.TableDiff{{
+			TableName:       "capitals",
+			ColumnsAdded:    []string{"population"},
+			InheritsAdded:   []string{"towns"},
+			InheritsRemoved: []string{"cities"},
+		}},
+	}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Capital", Name: "capitals", Inherits: []string{"towns"}}},
+		Fields: []goschema.Field{{StructName: "Capital", Name: "population", Type: "INTEGER", Nullable: true}},
+	}
+
+	nodes := postgres.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	addColumn := strings.Index(sql, "ALTER TABLE capitals ADD COLUMN population INTEGER;")
+	inherit := strings.Index(sql, "ALTER TABLE capitals INHERIT towns;")
+	noInherit := strings.Index(sql, "ALTER TABLE capitals NO INHERIT cities;")
+	dropParent := strings.Index(sql, "DROP TABLE IF EXISTS cities CASCADE;")
+	c.Assert([]int{addColumn, inherit, noInherit, dropParent}, qt.Not(qt.Contains), -1, qt.Commentf("got:\n%s", sql))
+	c.Assert(addColumn < inherit, qt.IsTrue, qt.Commentf("INHERIT must follow the columns it needs; got:\n%s", sql))
+	c.Assert(noInherit < dropParent, qt.IsTrue, qt.Commentf("NO INHERIT must precede DROP ... CASCADE of the parent; got:\n%s", sql))
+}
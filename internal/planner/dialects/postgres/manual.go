@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// ManualColumnChanges describes the changes in colDiff that no ALTER
+// statement can apply in place on a target with caps, e.g. "serial ->
+// integer" or "generated STORED (a) -> plain". The planner writes each one
+// as a MANUAL comment instead of SQL. The result is empty when the whole
+// column diff can be planned.
+func ManualColumnChanges(colDiff types.ColumnDiff, caps capability.Capabilities) []string {
+	var changes []string
+	if change := colDiff.Changes["serial"]; change != "" {
+		changes = append(changes, change)
+	}
+	if change, ok := manualGeneratedChange(colDiff, caps); ok {
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// manualGeneratedChange reports a "generated" change PostgreSQL cannot make
+// with ALTER COLUMN SET EXPRESSION: turning a generated column into a plain
+// one or back, switching between STORED and VIRTUAL, or any expression
+// change before PostgreSQL 17.
+func manualGeneratedChange(colDiff types.ColumnDiff, caps capability.Capabilities) (string, bool) {
+	change, ok := colDiff.Changes["generated"]
+	if !ok {
+		return "", false
+	}
+	before, after, _ := strings.Cut(change, " -> ")
+	beforeKind, beforeExpr := splitGeneratedChangeSide(before)
+	afterKind, afterExpr := splitGeneratedChangeSide(after)
+	switch {
+	case afterExpr == "":
+		return fmt.Sprintf("generated %s (%s) -> plain", beforeKind, beforeExpr), true
+	case beforeExpr == "":
+		return fmt.Sprintf("plain -> generated %s (%s)", afterKind, afterExpr), true
+	case beforeKind != afterKind:
+		return fmt.Sprintf("generated %s (%s) -> %s (%s)", beforeKind, beforeExpr, afterKind, afterExpr), true
+	case !caps.Has(capability.AlterGeneratedColumnExpression):
+		return fmt.Sprintf("generated expression (%s) -> (%s) needs PostgreSQL 17+", beforeExpr, afterExpr), true
+	default:
+		return "", false
+	}
+}
+
+// splitGeneratedChangeSide splits one side of a "generated" change, written
+// as "KIND expression", into its kind and expression.
+func splitGeneratedChangeSide(side string) (kind, expression string) {
+	side = strings.TrimSpace(side)
+	kind, expression, _ = strings.Cut(side, " ")
+	if kind != "STORED" && kind != "VIRTUAL" {
+		return "", side
+	}
+	return kind, strings.TrimSpace(expression)
+}
+
+// manualChangeComment is the comment written in place of a change
+// ManualColumnChanges reports.
+func manualChangeComment(tableName, columnName, change string) string {
+	return fmt.Sprintf("MANUAL: %s.%s %s; ptah cannot alter this automatically", tableName, columnName, change)
+}
+
+// manualColumnChanges writes a MANUAL comment for each change in colDiff
+// that ManualColumnChanges reports and returns colDiff without those
+// changes, so only the plannable remainder is turned into SQL.
+func (p *Planner) manualColumnChanges(result []ast.Node, tableName string, colDiff types.ColumnDiff) ([]ast.Node, types.ColumnDiff) {
+	changes := ManualColumnChanges(colDiff, p.capabilities())
+	if len(changes) == 0 {
+		return result, colDiff
+	}
+	for _, change := range changes {
+		result = append(result, ast.NewComment(manualChangeComment(tableName, colDiff.ColumnName, change)))
+	}
+	remaining := maps.Clone(colDiff.Changes)
+	delete(remaining, "serial")
+	if _, ok := manualGeneratedChange(colDiff, p.capabilities()); ok {
+		delete(remaining, "generated")
+	}
+	colDiff.Changes = remaining
+	return result, colDiff
+}
+
+// manualFunctionDropComment is the comment written in place of dropping
+// functionName while triggers still execute it.
+func manualFunctionDropComment(functionName string, triggers []types.TriggerRef) string {
+	names := make([]string, 0, len(triggers))
+	for _, trigger := range triggers {
+		names = append(names, trigger.TriggerName+" ON "+trigger.TableName)
+	}
+	return fmt.Sprintf("MANUAL: function %s is still executed by trigger %s; drop or repoint those triggers, then DROP FUNCTION %s",
+		functionName, strings.Join(names, ", "), functionName)
+}
@@ -0,0 +1,111 @@
+package postgres_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func checkConstraintSchema(notValid bool) *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Product", Name: "products"}},
+		Fields: []goschema.Field{{StructName: "Product", Name: "price", Type: "INTEGER"}},
+		Constraints: []goschema.Constraint{{
+			StructName:      "Product",
+			Name:            "positive_price",
+			Type:            "CHECK",
+			Table:           "products",
+			CheckExpression: "price > 0",
+			NotValid:        notValid,
+		}},
+	}
+}
+
+func renderConstraintPlan(c *qt.C, p *postgres.Planner, diff *types.SchemaDiff, generated *goschema.Database) string {
+	sql, err := renderer.RenderSQL("postgres", p.GenerateMigrationAST(diff, generated)...)
+	c.Assert(err, qt.IsNil)
+	return sql
+}
+
+func TestPlanner_TwoStepConstraintValidationDefersCheckValidation(t *testing.T) {
+	c := qt.New(t)
+	diff := &types.SchemaDiff{ConstraintsAdded: []string{"positive_price"}}
+
+	sql := renderConstraintPlan(c, postgres.New().WithTwoStepConstraintValidation(), diff, checkConstraintSchema(false))
+
+	c.Assert(sql, qt.Equals, "-- ALTER statements: --\n"+
+		"ALTER TABLE \"products\" ADD CONSTRAINT \"positive_price\" CHECK (price > 0) NOT VALID;\n"+
+		"\n"+
+		"-- ALTER statements: --\n"+
+		"ALTER TABLE \"products\" VALIDATE CONSTRAINT \"positive_price\";\n"+
+		"\n")
+}
+
+func TestPlanner_TwoStepConstraintValidationDefersForeignKeyValidation(t *testing.T) {
+	c := qt.New(t)
+	diff := &types.SchemaDiff{
+		ConstraintsAdded: []string{"fk_orders_user"},
+		ConstraintsAddedWithTables: []types.ConstraintAdditionInfo{{
+			Name:           "fk_orders_user",
+			TableName:      "orders",
+			Type:           "FOREIGN KEY",
+			Columns:        []string{"user_id"},
+			ForeignTable:   "users",
+			ForeignColumns: []string{"id"},
+		}},
+	}
+	generated := &goschema.Database{Tables: []goschema.Table{{StructName: "Order", Name: "orders"}}}
+
+	sql := renderConstraintPlan(c, postgres.New().WithTwoStepConstraintValidation(), diff, generated)
+
+	c.Assert(sql, qt.Contains, "FOREIGN KEY (\"user_id\") REFERENCES \"users\"(\"id\") NOT VALID;\n")
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"orders\" VALIDATE CONSTRAINT \"fk_orders_user\";\n")
+}
+
+func TestPlanner_DeclaredNotValidConstraintIsDeferredWithoutOption(t *testing.T) {
+	c := qt.New(t)
+	diff := &types.SchemaDiff{ConstraintsAdded: []string{"positive_price"}}
+
+	sql := renderConstraintPlan(c, postgres.New(), diff, checkConstraintSchema(true))
+
+	c.Assert(sql, qt.Contains, "CHECK (price > 0) NOT VALID;\n")
+	c.Assert(sql, qt.Contains, "VALIDATE CONSTRAINT \"positive_price\";\n")
+}
+
+func TestPlanner_ConstraintsValidateImmediatelyByDefault(t *testing.T) {
+	c := qt.New(t)
+	diff := &types.SchemaDiff{ConstraintsAdded: []string{"positive_price"}}
+
+	sql := renderConstraintPlan(c, postgres.New(), diff, checkConstraintSchema(false))
+
+	c.Assert(sql, qt.Contains, "CHECK (price > 0);\n")
+	c.Assert(sql, qt.Not(qt.Contains), "VALID")
+}
+
+func TestPlanner_TwoStepConstraintValidationSkipsNewTables(t *testing.T) {
+	c := qt.New(t)
+	generated := checkConstraintSchema(false)
+	diff := &types.SchemaDiff{TablesAdded: []string{"products"}, ConstraintsAdded: []string{"positive_price"}}
+
+	sql := renderConstraintPlan(c, postgres.New().WithTwoStepConstraintValidation(), diff, generated)
+
+	c.Assert(sql, qt.Contains, "CHECK (price > 0);\n")
+	c.Assert(sql, qt.Not(qt.Contains), "VALID")
+}
+
+func TestPlanner_TwoStepConstraintValidationRequiresCapability(t *testing.T) {
+	c := qt.New(t)
+	caps := capability.Postgres16().With(capability.NotValidConstraints, false)
+	diff := &types.SchemaDiff{ConstraintsAdded: []string{"positive_price"}}
+
+	sql := renderConstraintPlan(c, postgres.NewWithCapabilities(caps).WithTwoStepConstraintValidation(), diff, checkConstraintSchema(true))
+
+	c.Assert(sql, qt.Contains, "CHECK (price > 0);\n")
+	c.Assert(sql, qt.Not(qt.Contains), "VALID")
+}
@@ -1,14 +1,17 @@
 package postgres
 
 import (
+	"cmp"
 	"fmt"
 	"maps"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/stokaro/ptah/core/ast"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/internal/convert/fromschema"
 	"github.com/stokaro/ptah/internal/deporder"
 	"github.com/stokaro/ptah/migration/diffpolicy"
@@ -64,8 +67,8 @@ type Planner struct {
 	// (capability.Postgres17) via the capabilities accessor, so a bare
 	// &Planner{} behaves exactly like New(). Version presets live in the
 	// capability package — capability.Postgres17 for PostgreSQL 17+,
-	// capability.Postgres16 for PostgreSQL 14–16,
-	// capability.Postgres13 for 12–13.
+	// capability.Postgres16 for PostgreSQL 15–16, capability.Postgres14 for
+	// 14, capability.Postgres13 for 13, capability.Postgres12 for 12 and older.
 	caps capability.Capabilities
 	// concurrentIndexes requests CREATE INDEX CONCURRENTLY for new indexes.
 	// It is a POLICY choice (concurrent builds cannot run inside a
@@ -83,6 +86,24 @@ type Planner struct {
 	// skip lists destructive change kinds this planner omits from the plan,
 	// emitting a clearly-marked comment in their place. See diffpolicy.
 	skip diffpolicy.SkipSet
+	// safeNotNull makes nullable -> NOT NULL column changes emit an explicit
+	// backfill of existing NULLs from the column default (and a NOT VALID
+	// CHECK validated ahead of SET NOT NULL when the target supports it)
+	// instead of relying on the renderer's implicit type-based backfill.
+	safeNotNull bool
+	// safeTypeChange plans incompatible column type changes as a copy into a
+	// temporary column of the new type that then replaces the old column,
+	// instead of ALTER COLUMN ... TYPE.
+	safeTypeChange bool
+	// safeVolatileDefault adds a column whose default is volatile without
+	// the default, then sets it and backfills existing rows, instead of
+	// ADD COLUMN ... DEFAULT rewriting the table.
+	safeVolatileDefault bool
+	// twoStepConstraintValidation adds every CHECK and FOREIGN KEY constraint
+	// on an existing table NOT VALID and validates it at the end of the plan.
+	// Constraints declared with not_valid="true" get the same treatment even
+	// when this is off.
+	twoStepConstraintValidation bool
 }
 
 // New returns a planner configured with the current PostgreSQL line preset
@@ -92,7 +113,7 @@ func New() *Planner {
 }
 
 // NewWithCapabilities returns a planner for a specific capability set — e.g.
-// capability.Postgres13() for a PostgreSQL 12/13 target, or a set resolved
+// capability.Postgres13() for a PostgreSQL 13 target, or a set resolved
 // from a live server via capability.ForServerVersion. The set is expected to
 // be valid (capability.Capabilities.Validate); presets always are. The set is
 // cloned, so later mutations by the caller cannot affect the planner. A nil
@@ -159,6 +180,56 @@ func (p *Planner) WithSkipChangeKinds(kinds ...diffpolicy.ChangeKind) *Planner {
 	return &cp
 }
 
+// WithSafeNotNull returns a copy of the planner that plans nullable -> NOT NULL
+// column changes as an explicit sequence: existing NULLs are backfilled from the
+// target column default, a NOT VALID IS NOT NULL check is added and validated
+// when the target has capability.NotValidConstraints (so PostgreSQL 12+ can
+// skip the SET NOT NULL scan), and the temporary check is dropped afterwards.
+// Columns without a default get a warning comment instead of a backfill, and
+// SET NOT NULL fails if NULLs remain. The receiver is not modified.
+func (p *Planner) WithSafeNotNull() *Planner {
+	cp := *p
+	cp.safeNotNull = true
+	return &cp
+}
+
+// WithSafeTypeChange returns a copy of the planner that plans column type
+// changes the diff classifies as incompatible (types.TypeChangeIncompatible)
+// as expand/migrate/contract: a temporary column of the new type is added,
+// filled from the field's type_cast expression (a plain cast by default),
+// the old column is dropped and the temporary column is renamed in its
+// place. Widening and narrowing changes keep the in-place ALTER COLUMN. The
+// receiver is not modified.
+func (p *Planner) WithSafeTypeChange() *Planner {
+	cp := *p
+	cp.safeTypeChange = true
+	return &cp
+}
+
+// WithSafeVolatileDefault returns a copy of the planner that adds a column
+// whose default calls a volatile function (see IsVolatileDefault) in steps:
+// ADD COLUMN without the default, SET DEFAULT for new rows, an UPDATE that
+// backfills existing rows, and SET NOT NULL when the column is NOT NULL.
+// Constant and stable defaults keep the single ADD COLUMN, which PostgreSQL
+// 11+ applies without a rewrite. The receiver is not modified.
+func (p *Planner) WithSafeVolatileDefault() *Planner {
+	cp := *p
+	cp.safeVolatileDefault = true
+	return &cp
+}
+
+// WithTwoStepConstraintValidation returns a copy of the planner that adds CHECK
+// and FOREIGN KEY constraints on existing tables as ADD CONSTRAINT ... NOT VALID
+// and appends a matching VALIDATE CONSTRAINT at the end of the plan, so the
+// exclusive lock is not held while existing rows are scanned. It only takes
+// effect when the target has capability.NotValidConstraints. The receiver is
+// not modified.
+func (p *Planner) WithTwoStepConstraintValidation() *Planner {
+	cp := *p
+	cp.twoStepConstraintValidation = true
+	return &cp
+}
+
 func (p *Planner) usesConcurrentIndex(indexName string) bool {
 	if p.concurrentIndexes {
 		return true
@@ -167,6 +238,17 @@ func (p *Planner) usesConcurrentIndex(indexName string) bool {
 	return ok
 }
 
+// renameEnums renames enum types in place. Columns keep referencing the type,
+// so no data is rewritten; value changes follow in modifyExistingEnums under
+// the new name.
+func (p *Planner) renameEnums(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, rename := range diff.EnumsRenamed {
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("ALTER TYPE %s RENAME TO %s",
+			quotePostgresIdentifierPath(rename.OldName), quotePostgresIdentifier(rename.NewName))))
+	}
+	return result
+}
+
 func (p *Planner) addNewEnums(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	for _, enumName := range diff.EnumsAdded {
 		for _, enum := range generated.Enums {
@@ -288,7 +370,7 @@ func postgresEnumValueRemovalSQL(enumName string, values []string, usages []post
 			quotePostgresIdentifier(usage.Column),
 			enumIdent,
 		)
-		if defaultSQL, ok := postgresDefaultSQL(usage); ok {
+		if defaultSQL, ok := postgresDefaultSQL(usage, enumIdent); ok {
 			fmt.Fprintf(&sql, "ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;\n",
 				quotePostgresIdentifierPath(usage.Table),
 				quotePostgresIdentifier(usage.Column),
@@ -319,12 +401,14 @@ func postgresEnumValueList(values []string) string {
 	return strings.Join(quoted, ", ")
 }
 
-func postgresDefaultSQL(usage postgresEnumColumnUsage) (string, bool) {
+// postgresDefaultSQL returns the default to restore on an enum column after
+// the enum is recreated; a literal default is cast to the enum type.
+func postgresDefaultSQL(usage postgresEnumColumnUsage, enumIdent string) (string, bool) {
 	if usage.DefaultExpr != "" {
 		return usage.DefaultExpr, true
 	}
 	if usage.DefaultSet || usage.Default != "" {
-		return postgresDefaultLiteral(usage.Default), true
+		return postgresDefaultLiteral(usage.Default) + "::" + enumIdent, true
 	}
 	return "", false
 }
@@ -393,11 +477,59 @@ func (p *Planner) createTablesWithoutForeignKeys(result []ast.Node, generated *g
 			column.ForeignKey = nil
 		}
 		result = append(result, astNode)
+		result = p.addCreatedTableComments(result, astNode)
+		if table.Owner != "" {
+			result = append(result, alterTableOwner(astNode.Name, table.Owner))
+		}
 	}
 
 	return result
 }
 
+// addCreatedTableComments follows a CREATE TABLE with COMMENT ON TABLE and
+// COMMENT ON COLUMN statements, in column declaration order. The renderer
+// only carries the table comment in a header line, so without these
+// statements the comments never reach the database.
+func (p *Planner) addCreatedTableComments(result []ast.Node, table *ast.CreateTableNode) []ast.Node {
+	if !p.capabilities().Has(capability.CommentOn) {
+		return result
+	}
+	if table.Comment != "" {
+		result = append(result, commentOnTable(table.Name, table.Comment))
+	}
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			result = append(result, commentOnColumn(table.Name, column.Name, column.Comment))
+		}
+	}
+	return result
+}
+
+// alterTableOwner transfers a table to owner.
+func alterTableOwner(tableName, owner string) ast.Node {
+	return ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s OWNER TO %s",
+		quotePostgresIdentifierPath(tableName), quotePostgresIdentifier(owner)))
+}
+
+// commentOnTable sets a table comment; an empty comment removes it.
+func commentOnTable(tableName, comment string) ast.Node {
+	return ast.NewRawSQL(fmt.Sprintf("COMMENT ON TABLE %s IS %s",
+		quotePostgresIdentifierPath(tableName), postgresCommentLiteral(comment)))
+}
+
+// commentOnColumn sets a column comment; an empty comment removes it.
+func commentOnColumn(tableName, columnName, comment string) ast.Node {
+	return ast.NewRawSQL(fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s",
+		quotePostgresIdentifierPath(tableName), quotePostgresIdentifier(columnName), postgresCommentLiteral(comment)))
+}
+
+func postgresCommentLiteral(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return quotePostgresLiteral(comment)
+}
+
 // addForeignKeyConstraints adds foreign key constraints via ALTER TABLE statements
 func (p *Planner) addForeignKeyConstraints(result []ast.Node, generated *goschema.Database, tables []goschema.Table) []ast.Node {
 	for _, table := range tables {
@@ -464,6 +596,9 @@ func (p *Planner) addSelfReferencingForeignKeys(result []ast.Node, generated *go
 	}
 
 	for _, selfRefFK := range selfRefFKs {
+		if declaredAsForeignKeyConstraint(generated, table, selfRefFK) {
+			continue
+		}
 		fkRef := fromschema.ParseForeignKeyReference(selfRefFK.Foreign)
 		if fkRef != nil {
 			qualifyForeignKeyRef(generated, table, fkRef)
@@ -487,6 +622,25 @@ func selfReferencingForeignKeyName(tableName string, fk goschema.SelfReferencing
 	return fromschema.GenerateForeignKeyName(tableName, fk.FieldName)
 }
 
+// declaredAsForeignKeyConstraint reports whether a self-referencing foreign
+// key was recorded from a table-level FOREIGN KEY constraint annotation. Those
+// are already emitted by the constraint path with their full column lists, so
+// re-emitting them here would duplicate the constraint.
+func declaredAsForeignKeyConstraint(generated *goschema.Database, table goschema.Table, fk goschema.SelfReferencingFK) bool {
+	if fk.ForeignKeyName == "" {
+		return false
+	}
+	for _, constraint := range generated.Constraints {
+		if constraint.Name != fk.ForeignKeyName || !strings.EqualFold(constraint.Type, "FOREIGN KEY") {
+			continue
+		}
+		if constraint.StructName == table.StructName || constraint.Table == table.Name || constraint.Table == table.QualifiedName() {
+			return true
+		}
+	}
+	return false
+}
+
 // isRegularForeignKeyField checks if a field is a regular foreign key field for the given table.
 //
 // A field-level foreign= annotation is a foreign key regardless of whether the
@@ -546,16 +700,23 @@ func (p *Planner) addNewTableColumns(result []ast.Node, tableDiff types.TableDif
 		if targetField != nil {
 			columnNode := fromschema.FromFieldWithoutForeignKeys(*targetField, generated.Enums, "postgres")
 
-			// Only add the column - foreign key constraints will be added separately
-			// to ensure proper dependency ordering (columns must exist before FK constraints)
-			operations := []ast.AlterOperation{&ast.AddColumnOperation{Column: columnNode}}
+			if p.safeVolatileDefault && hasVolatileDefault(columnNode, generated.Functions) {
+				result = p.addColumnWithVolatileDefault(result, tableDiff.TableName, columnNode)
+			} else {
+				// Only add the column - foreign key constraints will be added separately
+				// to ensure proper dependency ordering (columns must exist before FK constraints)
+				operations := []ast.AlterOperation{&ast.AddColumnOperation{Column: columnNode}}
 
-			// Generate ALTER TABLE statement with only the ADD COLUMN operation
-			alterNode := &ast.AlterTableNode{
-				Name:       tableDiff.TableName,
-				Operations: operations,
+				// Generate ALTER TABLE statement with only the ADD COLUMN operation
+				alterNode := &ast.AlterTableNode{
+					Name:       tableDiff.TableName,
+					Operations: operations,
+				}
+				result = append(result, alterNode)
+			}
+			if columnNode.Comment != "" && p.capabilities().Has(capability.CommentOn) {
+				result = append(result, commentOnColumn(tableDiff.TableName, columnNode.Name, columnNode.Comment))
 			}
-			result = append(result, alterNode)
 		}
 	}
 	return result
@@ -618,8 +779,9 @@ func (p *Planner) addForeignKeyConstraintsForNewColumns(result []ast.Node, table
 	return result
 }
 
-func (p *Planner) modifyExistingTableColumns(result []ast.Node, tableDiff types.TableDiff, generated *goschema.Database) []ast.Node {
+func (p *Planner) modifyExistingTableColumns(result []ast.Node, diff *types.SchemaDiff, tableDiff types.TableDiff, generated *goschema.Database) []ast.Node {
 	allFields := fromschema.ProcessEmbeddedFields(generated.EmbeddedFields, generated.Fields)
+	table := findGeneratedTableByDiffName(generated, tableDiff.TableName)
 	for _, colDiff := range tableDiff.ColumnsModified {
 		// Find the target field definition for this column
 		// We need to find the struct name that corresponds to this table name
@@ -627,7 +789,7 @@ func (p *Planner) modifyExistingTableColumns(result []ast.Node, tableDiff types.
 		var targetStructName string
 
 		// First, find the struct name for this table
-		if table := findGeneratedTableByDiffName(generated, tableDiff.TableName); table != nil {
+		if table != nil {
 			targetStructName = table.StructName
 		}
 
@@ -645,13 +807,85 @@ func (p *Planner) modifyExistingTableColumns(result []ast.Node, tableDiff types.
 			continue
 		}
 
+		// A column leaving a primary key that is dropped later in the plan
+		// keeps NOT NULL until then; relaxReleasedPrimaryKeyColumns drops it.
+		releasesNotNull := releasesPrimaryKeyNotNull(diff, tableDiff.TableName, colDiff)
+		if releasesNotNull {
+			colDiff.Changes = maps.Clone(colDiff.Changes)
+			delete(colDiff.Changes, "nullable")
+		}
+
+		result, colDiff = p.manualColumnChanges(result, tableDiff.TableName, colDiff)
+		if len(colDiff.Changes) == 0 {
+			continue
+		}
+
 		// Create a column definition with the target field properties
 		columnNode := fromschema.FromField(*targetField, generated.Enums, "postgres")
+		if releasesNotNull || inTablePrimaryKey(table, colDiff.ColumnName) {
+			// Primary key columns are NOT NULL whatever the field declares.
+			columnNode.Nullable = false
+		}
+		var commentNode ast.Node
+		if _, ok := colDiff.Changes["comment"]; ok && p.capabilities().Has(capability.CommentOn) {
+			commentNode = commentOnColumn(tableDiff.TableName, columnNode.Name, columnNode.Comment)
+		}
+		identityNodes := identityChangeNodes(tableDiff.TableName, colDiff, columnNode)
+		if changesOnly(colDiff, "comment", "identity", "identity_start", "identity_increment") {
+			// COMMENT ON and the identity ALTERs leave the rest of the column
+			// definition alone; a full ALTER COLUMN here would restate the
+			// type and could rewrite the table.
+			result = append(result, identityNodes...)
+			if commentNode != nil {
+				result = append(result, commentNode)
+			}
+			continue
+		}
 		if isGeneratedColumnChange(colDiff) {
 			result = p.modifyGeneratedColumnExpression(result, tableDiff.TableName, colDiff, columnNode)
+			if commentNode != nil {
+				result = append(result, commentNode)
+			}
+			continue
+		}
+		if changesOnly(colDiff, "default", "default_expr", "comment") {
+			// Only the default changed: SET/DROP DEFAULT is a catalog-only
+			// change, where a full ALTER COLUMN would restate the type.
+			result = append(result, &ast.AlterTableNode{
+				Name:       tableDiff.TableName,
+				Operations: []ast.AlterOperation{&ast.AlterColumnDefaultOperation{Column: columnNode}},
+			})
+			if commentNode != nil {
+				result = append(result, commentNode)
+			}
+			result = append(result, modifyColumnComment(tableDiff.TableName, colDiff))
+			continue
+		}
+
+		if p.safeTypeChange && colDiff.TypeChangeKind == types.TypeChangeIncompatible {
+			keys := swappedColumnKeys(diff, generated, allFields, table, colDiff.ColumnName)
+			result = p.swapColumnType(result, tableDiff.TableName, colDiff, columnNode, targetField.TypeCast, keys)
+			result = append(result, modifyColumnComment(tableDiff.TableName, colDiff))
 			continue
 		}
 
+		safeNotNull := p.safeNotNull && addsNotNull(colDiff)
+		var notNullCheck string
+		if safeNotNull {
+			result, notNullCheck = p.prepareSafeNotNull(result, tableDiff.TableName, columnNode)
+		}
+
+		if warning := typeChangeWarning(tableDiff.TableName, colDiff, columnNode.Type); warning != nil {
+			result = append(result, warning)
+		}
+
+		// An identity must be dropped before the column can take a plain
+		// DEFAULT again; every other identity change follows the ALTER.
+		dropsIdentity := strings.HasSuffix(colDiff.Changes["identity"], " -> NONE")
+		if dropsIdentity {
+			result = append(result, identityNodes...)
+		}
+
 		// Generate ALTER COLUMN statements using AST
 		alterNode := &ast.AlterTableNode{
 			Name: tableDiff.TableName,
@@ -660,44 +894,356 @@ func (p *Planner) modifyExistingTableColumns(result []ast.Node, tableDiff types.
 				PreviousType:        previousColumnType(colDiff.Changes["type"]),
 				PreviousNullable:    previousColumnNullable(colDiff.Changes["nullable"]),
 				HasPreviousNullable: colDiff.Changes["nullable"] != "",
+				SkipNullBackfill:    safeNotNull,
 			}},
 		}
+		if notNullCheck != "" {
+			// Validating the check, setting NOT NULL and dropping the check
+			// stay one node, led by the validation, so the generator can move
+			// them into a transaction after the one that added the check.
+			alterNode.Operations = []ast.AlterOperation{
+				&ast.ValidateConstraintOperation{ConstraintName: notNullCheck},
+				alterNode.Operations[0],
+				&ast.DropConstraintOperation{ConstraintName: notNullCheck},
+			}
+		}
 		result = append(result, alterNode)
-
-		// Add a comment showing what changes are being made. Iterate the
-		// changes in sorted key order so migration output is deterministic
-		// (issue #59).
-		changesList := make([]string, 0, len(colDiff.Changes))
-		for _, changeType := range slices.Sorted(maps.Keys(colDiff.Changes)) {
-			changesList = append(changesList, fmt.Sprintf("%s: %s", changeType, colDiff.Changes[changeType]))
+		if !dropsIdentity {
+			result = append(result, identityNodes...)
 		}
-		astCommentNode := ast.NewComment(fmt.Sprintf("Modify column %s.%s: %s", tableDiff.TableName, colDiff.ColumnName, strings.Join(changesList, ", ")))
-		result = append(result, astCommentNode)
+		if commentNode != nil {
+			result = append(result, commentNode)
+		}
+
+		result = append(result, modifyColumnComment(tableDiff.TableName, colDiff))
 	}
 	return result
 }
 
-func (p *Planner) modifyGeneratedColumnExpression(
+// modifyColumnComment describes a column's changes. It iterates the changes
+// in sorted key order so migration output is deterministic (issue #59).
+func modifyColumnComment(tableName string, colDiff types.ColumnDiff) *ast.CommentNode {
+	changesList := make([]string, 0, len(colDiff.Changes))
+	for _, changeType := range slices.Sorted(maps.Keys(colDiff.Changes)) {
+		changesList = append(changesList, fmt.Sprintf("%s: %s", changeType, colDiff.Changes[changeType]))
+	}
+	return ast.NewComment(fmt.Sprintf("Modify column %s.%s: %s", tableName, colDiff.ColumnName, strings.Join(changesList, ", ")))
+}
+
+// changesOnly reports whether every change in a column diff is one of keys.
+func changesOnly(colDiff types.ColumnDiff, keys ...string) bool {
+	for key := range colDiff.Changes {
+		if !slices.Contains(keys, key) {
+			return false
+		}
+	}
+	return len(colDiff.Changes) > 0
+}
+
+// identityChangeNodes converts an "identity" change ("old -> new", with
+// NONE for a plain column) into ALTER COLUMN statements. Switching modes is
+// SET GENERATED. Turning a column into an identity column drops its old
+// DEFAULT (the SERIAL nextval) and restarts the new identity sequence past
+// the existing values. Dropping an identity restores the target column's
+// DEFAULT, which for a former SERIAL column is its nextval expression.
+// Changed START WITH and INCREMENT BY options of an identity column that
+// stays one are set on the same ALTER COLUMN.
+func identityChangeNodes(tableName string, colDiff types.ColumnDiff, column *ast.ColumnNode) []ast.Node {
+	table := quotePostgresIdentifierPath(tableName)
+	columnName := quotePostgresIdentifier(column.Name)
+	alterColumn := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s ", table, columnName)
+	options := identityOptionChanges(colDiff)
+	before, after, ok := strings.Cut(colDiff.Changes["identity"], " -> ")
+	if !ok {
+		if options == "" {
+			return nil
+		}
+		return []ast.Node{ast.NewRawSQL(alterColumn + options)}
+	}
+	switch {
+	case after == "NONE":
+		nodes := []ast.Node{ast.NewRawSQL(alterColumn + "DROP IDENTITY IF EXISTS")}
+		switch {
+		case column.Default == nil:
+		case column.Default.HasLiteral():
+			nodes = append(nodes, ast.NewRawSQL(alterColumn+"SET DEFAULT "+quotePostgresLiteral(column.Default.Value)))
+		case column.Default.Expression != "":
+			nodes = append(nodes, ast.NewRawSQL(alterColumn+"SET DEFAULT "+column.Default.Expression))
+		}
+		return nodes
+	case before == "NONE":
+		return []ast.Node{
+			ast.NewRawSQL(alterColumn + "DROP DEFAULT"),
+			ast.NewRawSQL(alterColumn + "ADD " + postgresIdentityClause(column)),
+			ast.NewRawSQL(fmt.Sprintf(
+				"DO $$\nBEGIN\n    EXECUTE format('ALTER TABLE %%s ALTER COLUMN %%s RESTART WITH %%s', %s, %s, (SELECT COALESCE(MAX(%s), 0) + 1 FROM %s));\nEND\n$$",
+				quotePostgresLiteral(table), quotePostgresLiteral(columnName), columnName, table)),
+			ast.NewComment(fmt.Sprintf("Any SERIAL sequence previously backing %s.%s is left in place for rollback; drop it once the migration is final", tableName, column.Name)),
+		}
+	default:
+		return []ast.Node{ast.NewRawSQL(strings.TrimSpace(alterColumn + "SET GENERATED " + postgresIdentityGeneration(after) + " " + options))}
+	}
+}
+
+// identityOptionChanges renders the changed identity sequence options as
+// SET clauses. SET START WITH only changes the value a later RESTART goes
+// back to; the sequence keeps counting from its current value.
+func identityOptionChanges(colDiff types.ColumnDiff) string {
+	var clauses []string
+	if _, start, ok := strings.Cut(colDiff.Changes["identity_start"], " -> "); ok {
+		clauses = append(clauses, "SET START WITH "+strings.TrimSpace(start))
+	}
+	if _, increment, ok := strings.Cut(colDiff.Changes["identity_increment"], " -> "); ok {
+		clauses = append(clauses, "SET INCREMENT BY "+strings.TrimSpace(increment))
+	}
+	return strings.Join(clauses, " ")
+}
+
+// postgresIdentityClause renders GENERATED ... AS IDENTITY with the column's
+// sequence options.
+func postgresIdentityClause(column *ast.ColumnNode) string {
+	clause := fmt.Sprintf("GENERATED %s AS IDENTITY", postgresIdentityGeneration(column.IdentityGeneration))
+	if column.IdentityOptions != "" {
+		return clause + " (" + column.IdentityOptions + ")"
+	}
+	var options []string
+	if column.IdentityStart != "" {
+		options = append(options, "START WITH "+column.IdentityStart)
+	}
+	if column.IdentityIncrement != "" {
+		options = append(options, "INCREMENT BY "+column.IdentityIncrement)
+	}
+	if len(options) == 0 {
+		return clause
+	}
+	return clause + " (" + strings.Join(options, " ") + ")"
+}
+
+func postgresIdentityGeneration(generation string) string {
+	return strings.ReplaceAll(strings.ToUpper(generation), "_", " ")
+}
+
+// addsNotNull reports whether a column diff turns a nullable column NOT NULL.
+func addsNotNull(colDiff types.ColumnDiff) bool {
+	before, after, ok := strings.Cut(colDiff.Changes["nullable"], " -> ")
+	return ok && strings.TrimSpace(before) == "true" && strings.TrimSpace(after) == "false"
+}
+
+// prepareSafeNotNull emits the statements that must run before SET NOT NULL
+// on a populated column: a backfill of existing NULLs from the column default
+// followed, when the target supports it, by a NOT VALID IS NOT NULL check. It
+// returns the name of the temporary check, or "" when none was added; the
+// caller validates the check before SET NOT NULL so that statement can skip
+// its own full-table scan, and drops it afterwards. Without a default it emits
+// a warning comment only; the caller keeps SET NOT NULL so the migration fails
+// on remaining NULLs instead of inventing values.
+func (p *Planner) prepareSafeNotNull(result []ast.Node, tableName string, column *ast.ColumnNode) ([]ast.Node, string) {
+	table := quotePostgresIdentifierPath(tableName)
+	col := quotePostgresIdentifier(column.Name)
+
+	var defaultSQL string
+	switch {
+	case column.Default == nil:
+	case column.Default.Expression != "":
+		defaultSQL = column.Default.Expression
+	case column.Default.HasLiteral():
+		defaultSQL = postgresDefaultLiteral(column.Default.Value)
+	}
+	if defaultSQL == "" {
+		return append(result, ast.NewComment(fmt.Sprintf(
+			"WARNING: Column %s.%s becomes NOT NULL but has no default; backfill existing NULL values manually before applying this migration.",
+			tableName,
+			column.Name,
+		))), ""
+	}
+
+	result = append(result, ast.NewRawSQL(fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL", table, col, defaultSQL, col)))
+	if !p.capabilities().Has(capability.NotValidConstraints) {
+		return result, ""
+	}
+	checkName := postgresBaseName(tableName) + "_" + column.Name + "_not_null"
+	result = append(result, &ast.AlterTableNode{Name: tableName, Operations: []ast.AlterOperation{&ast.AddConstraintOperation{
+		Constraint: &ast.ConstraintNode{
+			Type:       ast.CheckConstraint,
+			Name:       checkName,
+			Expression: col + " IS NOT NULL",
+			NotValid:   true,
+		},
+	}}})
+	return result, checkName
+}
+
+// hasVolatileDefault reports whether column is added with a volatile default
+// expression that addColumnWithVolatileDefault can split off. Primary key
+// columns are left alone: they must be NOT NULL when added.
+func hasVolatileDefault(column *ast.ColumnNode, functions []goschema.Function) bool {
+	return column.Default != nil &&
+		column.Default.Expression != "" &&
+		!column.Primary &&
+		IsVolatileDefault(column.Default.Expression, functions)
+}
+
+// addColumnWithVolatileDefault adds column nullable and without its volatile
+// default, so ADD COLUMN stays a catalog-only change, then sets the default
+// for new rows and backfills existing rows from it. A NOT NULL column gets
+// the same backfill and NOT NULL sequence prepareSafeNotNull plans. Setting
+// the default before the backfill leaves no NULLs behind for rows inserted
+// while the backfill runs in batches.
+func (p *Planner) addColumnWithVolatileDefault(result []ast.Node, tableName string, column *ast.ColumnNode) []ast.Node {
+	table := quotePostgresIdentifierPath(tableName)
+	col := quotePostgresIdentifier(column.Name)
+	added := *column
+	added.Nullable = true
+	added.Default = nil
+
+	result = append(result,
+		ast.NewComment(fmt.Sprintf("Volatile default on %s.%s (%s): add the column without it, then set the default and backfill existing rows",
+			tableName, column.Name, column.Default.Expression)),
+		&ast.AlterTableNode{Name: tableName, Operations: []ast.AlterOperation{&ast.AddColumnOperation{Column: &added}}},
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", table, col, column.Default.Expression)),
+		ast.NewComment(fmt.Sprintf("WARNING: The backfill of %s.%s updates every existing row in one statement; on a large table, run it in batches sized for the workload (for example by primary key range) before applying this migration.",
+			tableName, column.Name)),
+	)
+	if column.Nullable {
+		return append(result, ast.NewRawSQL(fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s IS NULL", table, col, column.Default.Expression, col)))
+	}
+	result, notNullCheck := p.prepareSafeNotNull(result, tableName, column)
+	if notNullCheck == "" {
+		return append(result, ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", table, col)))
+	}
+	return append(result,
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", table, quotePostgresIdentifier(notNullCheck))),
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", table, col)),
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", table, quotePostgresIdentifier(notNullCheck))),
+	)
+}
+
+// swapColumnType changes a column's type through a temporary column: the
+// new column is added nullable, filled from castExpr (or a plain cast of the
+// old column), and renamed over the dropped old column. NOT NULL, the default
+// and the comment of the target column are restored afterwards, and keys
+// recreate the primary key, unique constraints and indexes that the old
+// column took with it. The old column is dropped without CASCADE, so
+// views and foreign keys that depend on it stop the migration instead of
+// disappearing silently; CHECK, EXCLUDE and foreign key constraints on the
+// column itself are dropped with it.
+func (p *Planner) swapColumnType(
 	result []ast.Node,
 	tableName string,
 	colDiff types.ColumnDiff,
-	columnNode *ast.ColumnNode,
+	column *ast.ColumnNode,
+	castExpr string,
+	keys []ast.Node,
 ) []ast.Node {
-	if columnNode == nil || strings.TrimSpace(columnNode.GeneratedExpression) == "" {
-		return append(result, ast.NewComment(fmt.Sprintf(
-			"WARNING: Generated column %s.%s changed, but the target schema is not a generated column; manual migration required.",
-			tableName,
-			colDiff.ColumnName,
-		)))
+	table := quotePostgresIdentifierPath(tableName)
+	col := quotePostgresIdentifier(column.Name)
+	tempName := column.Name + "__ptah_new"
+	temp := quotePostgresIdentifier(tempName)
+	if strings.TrimSpace(castExpr) == "" {
+		castExpr = col + "::" + column.Type
+	}
+
+	result = append(result,
+		ast.NewComment(fmt.Sprintf("Safe type change on %s.%s (%s): copy into %s, then swap it in and recreate its keys and indexes",
+			tableName, column.Name, colDiff.Changes["type"], tempName)),
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, temp, column.Type)),
+		ast.NewRawSQL(fmt.Sprintf("UPDATE %s SET %s = %s", table, temp, castExpr)),
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, col)),
+		ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", table, temp, col)),
+	)
+	if !column.Nullable {
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", table, col)))
+	}
+	switch {
+	case column.Default == nil:
+	case column.Default.Expression != "":
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", table, col, column.Default.Expression)))
+	case column.Default.HasLiteral():
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", table, col, postgresDefaultLiteral(column.Default.Value))))
+	}
+	if column.Comment != "" && p.capabilities().Has(capability.CommentOn) {
+		result = append(result, commentOnColumn(tableName, column.Name, column.Comment))
+	}
+	return append(result, keys...)
+}
+
+// swappedColumnKeys returns the nodes that recreate the primary key, unique
+// constraints and indexes declared on table that cover column: dropping the
+// column drops them too. Unique constraints and indexes the diff adds are
+// left to the regular constraint and index steps.
+func swappedColumnKeys(diff *types.SchemaDiff, generated *goschema.Database, fields []goschema.Field, table *goschema.Table, column string) []ast.Node {
+	if table == nil {
+		return nil
 	}
-	if !p.capabilities().Has(capability.AlterGeneratedColumnExpression) {
-		return append(result, ast.NewComment(fmt.Sprintf(
-			"WARNING: Generated column %s.%s changed, but ALTER COLUMN SET EXPRESSION requires PostgreSQL 17+; manual migration required.",
-			tableName,
-			colDiff.ColumnName,
-		)))
+	tableName := table.QualifiedName()
+	addConstraint := func(constraint *ast.ConstraintNode) ast.Node {
+		return &ast.AlterTableNode{
+			Name:       tableName,
+			Operations: []ast.AlterOperation{&ast.AddConstraintOperation{Constraint: constraint}},
+		}
 	}
 
+	var keys []ast.Node
+	if primaryKey := tablePrimaryKeyColumns(table, fields); slices.Contains(primaryKey, column) {
+		constraint := ast.NewPrimaryKeyConstraint(primaryKey...)
+		constraint.IncludeColumns = table.PrimaryKeyInclude
+		keys = append(keys, addConstraint(constraint))
+	}
+	for _, field := range fields {
+		if field.StructName == table.StructName && field.Name == column && field.Unique {
+			keys = append(keys, addConstraint(ast.NewUniqueConstraint(table.Name+"_"+column+"_key", column)))
+		}
+	}
+	for _, constraint := range generated.Constraints {
+		if constraint.StructName != table.StructName || !strings.EqualFold(constraint.Type, "UNIQUE") ||
+			!slices.Contains(constraint.Columns, column) || slices.Contains(diff.ConstraintsAdded, constraint.Name) {
+			continue
+		}
+		keys = append(keys, addConstraint(fromschema.FromConstraint(constraint)))
+	}
+	structToTable := map[string]string{table.StructName: tableName}
+	for _, idx := range generated.Indexes {
+		if idx.StructName != table.StructName || slices.Contains(diff.IndexesAdded, idx.Name) {
+			continue
+		}
+		covers := slices.Contains(idx.Fields, column) || slices.ContainsFunc(idx.Parts, func(part goschema.IndexPart) bool {
+			return part.Name == column
+		})
+		if covers {
+			keys = append(keys, fromschema.FromIndexWithTableMapping(idx, structToTable))
+		}
+	}
+	return keys
+}
+
+// tablePrimaryKeyColumns returns the primary key columns of table: the
+// table-level key when one is declared, otherwise the primary fields.
+func tablePrimaryKeyColumns(table *goschema.Table, fields []goschema.Field) []string {
+	if len(table.PrimaryKey) > 0 {
+		return table.PrimaryKey
+	}
+	if len(table.PrimaryKeyParts) > 0 {
+		columns := make([]string, 0, len(table.PrimaryKeyParts))
+		for _, part := range table.PrimaryKeyParts {
+			columns = append(columns, part.Name)
+		}
+		return columns
+	}
+	var columns []string
+	for _, field := range fields {
+		if field.StructName == table.StructName && field.Primary {
+			columns = append(columns, field.Name)
+		}
+	}
+	return columns
+}
+
+func (p *Planner) modifyGeneratedColumnExpression(
+	result []ast.Node,
+	tableName string,
+	colDiff types.ColumnDiff,
+	columnNode *ast.ColumnNode,
+) []ast.Node {
 	alterNode := &ast.AlterTableNode{
 		Name: tableName,
 		Operations: []ast.AlterOperation{
@@ -732,6 +1278,51 @@ func findGeneratedTableByDiffName(generated *goschema.Database, tableName string
 	return nil
 }
 
+// inTablePrimaryKey reports whether column belongs to the table-level primary
+// key of table.
+func inTablePrimaryKey(table *goschema.Table, column string) bool {
+	if table == nil {
+		return false
+	}
+	if slices.Contains(table.PrimaryKey, column) {
+		return true
+	}
+	return slices.ContainsFunc(table.PrimaryKeyParts, func(part goschema.PrimaryKeyPart) bool {
+		return part.Name == column
+	})
+}
+
+// releasesPrimaryKeyNotNull reports whether colDiff makes a column nullable
+// on a table whose primary key constraint this diff drops. PostgreSQL rejects
+// DROP NOT NULL on a primary key column, so such a column may have been a key
+// member and its DROP NOT NULL must follow the constraint drop.
+func releasesPrimaryKeyNotNull(diff *types.SchemaDiff, tableName string, colDiff types.ColumnDiff) bool {
+	if colDiff.Changes["nullable"] != "false -> true" {
+		return false
+	}
+	return slices.ContainsFunc(diff.ConstraintsRemovedWithTables, func(info types.ConstraintRemovalInfo) bool {
+		return strings.EqualFold(info.Type, "PRIMARY KEY") && info.TableName == tableName
+	})
+}
+
+// relaxReleasedPrimaryKeyColumns drops NOT NULL from the columns whose
+// nullability change modifyExistingTableColumns held back until their table's
+// old primary key constraint was dropped.
+func (p *Planner) relaxReleasedPrimaryKeyColumns(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, tableDiff := range diff.TablesModified {
+		for _, colDiff := range tableDiff.ColumnsModified {
+			if !releasesPrimaryKeyNotNull(diff, tableDiff.TableName, colDiff) {
+				continue
+			}
+			result = append(result,
+				ast.NewComment(fmt.Sprintf("Modify column %s.%s: nullable: %s", tableDiff.TableName, colDiff.ColumnName, colDiff.Changes["nullable"])),
+				ast.NewRawSQL(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL",
+					quotePostgresIdentifierPath(tableDiff.TableName), quotePostgresIdentifier(colDiff.ColumnName))))
+		}
+	}
+	return result
+}
+
 func previousColumnType(change string) string {
 	before, _, ok := strings.Cut(change, " -> ")
 	if !ok {
@@ -745,6 +1336,31 @@ func previousColumnNullable(change string) bool {
 	return ok && strings.TrimSpace(before) == "true"
 }
 
+// typeChangeWarning returns the comment flagging a column type change that can
+// reject or lose existing data, or nil when the change is widening or
+// unclassified. Narrowing changes suggest a query to check the data first.
+func typeChangeWarning(tableName string, colDiff types.ColumnDiff, newType string) *ast.CommentNode {
+	switch colDiff.TypeChangeKind {
+	case types.TypeChangeNarrowing:
+		check := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", colDiff.ColumnName, colDiff.ColumnName, tableName)
+		if isStringType(newType) {
+			check = fmt.Sprintf("SELECT MAX(LENGTH(%s)) FROM %s", colDiff.ColumnName, tableName)
+		}
+		return ast.NewComment(fmt.Sprintf("WARNING: narrowing type change on %s.%s (%s) can reject existing values; check the data first: %s",
+			tableName, colDiff.ColumnName, colDiff.Changes["type"], check))
+	case types.TypeChangeIncompatible:
+		return ast.NewComment(fmt.Sprintf("WARNING: incompatible type change on %s.%s (%s); existing values may fail to convert",
+			tableName, colDiff.ColumnName, colDiff.Changes["type"]))
+	default:
+		return nil
+	}
+}
+
+func isStringType(typeName string) bool {
+	upper := strings.ToUpper(typeName)
+	return strings.Contains(upper, "CHAR") || strings.Contains(upper, "TEXT")
+}
+
 func (p *Planner) removeTableColumnsFromDiff(result []ast.Node, tableDiff types.TableDiff) []ast.Node {
 	for _, colName := range tableDiff.ColumnsRemoved {
 		// Generate DROP COLUMN statement using AST with CASCADE to handle dependencies
@@ -765,7 +1381,7 @@ func (p *Planner) removeTableColumnsFromDiff(result []ast.Node, tableDiff types.
 
 func (p *Planner) addAndModifyTableColumns(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	for _, tableDiff := range diff.TablesModified {
-		if len(tableDiff.ColumnsAdded) > 0 || len(tableDiff.ColumnsModified) > 0 {
+		if len(tableDiff.ColumnsAdded) > 0 || len(tableDiff.ColumnsModified) > 0 || tableDiff.Comment != "" {
 			// Track the initial length to see if any actual operations were added
 			initialLength := len(result)
 
@@ -773,7 +1389,10 @@ func (p *Planner) addAndModifyTableColumns(result []ast.Node, diff *types.Schema
 			result = p.addNewTableColumns(result, tableDiff, generated)
 
 			// Modify existing columns
-			result = p.modifyExistingTableColumns(result, tableDiff, generated)
+			result = p.modifyExistingTableColumns(result, diff, tableDiff, generated)
+
+			// Set the table comment after the column changes
+			result = p.modifyTableComment(result, tableDiff, generated)
 
 			// Only add the comment if actual operations were performed
 			if len(result) > initialLength {
@@ -791,6 +1410,23 @@ func (p *Planner) addAndModifyTableColumns(result []ast.Node, diff *types.Schema
 	return result
 }
 
+// modifyTableComment sets the generated table's comment, or removes it when
+// the table no longer declares one.
+func (p *Planner) modifyTableComment(result []ast.Node, tableDiff types.TableDiff, generated *goschema.Database) []ast.Node {
+	if tableDiff.Comment == "" || !p.capabilities().Has(capability.CommentOn) {
+		return result
+	}
+	table := findGeneratedTableByDiffName(generated, tableDiff.TableName)
+	if table == nil {
+		return result
+	}
+	comment := table.Comment
+	if override, ok := table.Overrides[DialectName]["comment"]; ok {
+		comment = override
+	}
+	return append(result, commentOnTable(tableDiff.TableName, comment))
+}
+
 // addForeignKeyConstraintsForModifiedTables adds foreign key constraints for all newly added columns
 // across all modified tables. This ensures that all columns exist before any foreign key constraints
 // are created, preventing dependency ordering issues.
@@ -819,6 +1455,103 @@ func (p *Planner) addForeignKeyConstraintsForModifiedTables(result []ast.Node, d
 	return result
 }
 
+// modifyTableOwners emits ALTER TABLE ... OWNER TO for existing tables whose
+// owner changed. tableDiff.Owner holds "old -> new".
+func (p *Planner) modifyTableOwners(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, tableDiff := range diff.TablesModified {
+		_, owner, ok := strings.Cut(tableDiff.Owner, " -> ")
+		if !ok || owner == "" {
+			continue
+		}
+		result = append(result, alterTableOwner(tableDiff.TableName, owner))
+	}
+	return result
+}
+
+// addTableInheritance emits ALTER TABLE ... INHERIT for parents that existing
+// tables gained.
+func (p *Planner) addTableInheritance(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	return appendInheritOperations(result, diff, false)
+}
+
+// removeTableInheritance emits ALTER TABLE ... NO INHERIT for parents that
+// existing tables lost.
+func (p *Planner) removeTableInheritance(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	return appendInheritOperations(result, diff, true)
+}
+
+func appendInheritOperations(result []ast.Node, diff *types.SchemaDiff, noInherit bool) []ast.Node {
+	for _, tableDiff := range diff.TablesModified {
+		parents := tableDiff.InheritsAdded
+		if noInherit {
+			parents = tableDiff.InheritsRemoved
+		}
+		for _, parent := range parents {
+			result = append(result, &ast.AlterTableNode{
+				Name:       tableDiff.TableName,
+				Operations: []ast.AlterOperation{&ast.InheritOperation{Parent: parent, NoInherit: noInherit}},
+			})
+		}
+	}
+	return result
+}
+
+// addSeedRows inserts the rows of every added seed. ON CONFLICT DO NOTHING
+// lets an always="true" seed be re-applied to a table that already holds its
+// rows.
+func (p *Planner) addSeedRows(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, seed := range diff.SeedsAdded {
+		values := make([]string, 0, len(seed.Rows))
+		for _, row := range seed.Rows {
+			literals := make([]string, len(row))
+			for i, value := range row {
+				literals[i] = postgresSeedLiteral(value)
+			}
+			values = append(values, "("+strings.Join(literals, ", ")+")")
+		}
+		columns := make([]string, len(seed.Columns))
+		for i, column := range seed.Columns {
+			columns[i] = quotePostgresIdentifier(column)
+		}
+		result = append(result,
+			ast.NewComment(fmt.Sprintf("Seed rows for table: %s", seed.Table)),
+			ast.NewRawSQL(fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT DO NOTHING",
+				quotePostgresIdentifierPath(seed.Table), strings.Join(columns, ", "), strings.Join(values, ", "))),
+		)
+	}
+	return result
+}
+
+// removeSeedRows deletes exactly the rows each removed seed inserted. Seeds of
+// tables this plan drops are left to DROP TABLE.
+func (p *Planner) removeSeedRows(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, seed := range diff.SeedsRemoved {
+		if slices.Contains(diff.TablesRemoved, seed.Table) {
+			continue
+		}
+		result = append(result, ast.NewComment(fmt.Sprintf("Remove seed rows from table: %s", seed.Table)))
+		for _, row := range seed.Rows {
+			conditions := make([]string, len(row))
+			for i, value := range row {
+				conditions[i] = quotePostgresIdentifier(seed.Columns[i]) + " = " + postgresSeedLiteral(value)
+				if value == "NULL" {
+					conditions[i] = quotePostgresIdentifier(seed.Columns[i]) + " IS NULL"
+				}
+			}
+			result = append(result, ast.NewRawSQL(fmt.Sprintf("DELETE FROM %s WHERE %s",
+				quotePostgresIdentifierPath(seed.Table), strings.Join(conditions, " AND "))))
+		}
+	}
+	return result
+}
+
+func postgresSeedLiteral(value string) string {
+	if value == "NULL" {
+		return value
+	}
+	return quotePostgresLiteral(value)
+}
+
 func (p *Planner) removeTableColumns(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
 	for _, tableDiff := range diff.TablesModified {
 		if len(tableDiff.ColumnsRemoved) > 0 {
@@ -835,6 +1568,11 @@ func (p *Planner) removeTableColumns(result []ast.Node, diff *types.SchemaDiff)
 func (p *Planner) addNewIndexes(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	// Create a mapping from struct names to table names for proper index table resolution
 	structToTableMap := make(map[string]string)
+	for _, view := range generated.MaterializedViews {
+		if view.StructName != "" {
+			structToTableMap[view.StructName] = view.Name
+		}
+	}
 	for _, table := range generated.Tables {
 		structToTableMap[table.StructName] = table.QualifiedName()
 	}
@@ -877,7 +1615,7 @@ func (p *Planner) removeIndexes(result []ast.Node, diff *types.SchemaDiff) []ast
 	guarded := p.capabilities().Has(capability.DropIndexIfExists)
 	replacementIndexes := stringSet(diff.IndexesAdded)
 	for _, indexName := range diff.IndexesRemoved {
-		if _, replaced := replacementIndexes[indexName]; replaced {
+		if _, replaced := replacementIndexes[indexName]; replaced || diff.IndexNameDroppedWithTables(indexName) {
 			continue
 		}
 		dropIndexNode := ast.NewDropIndex(indexName)
@@ -908,7 +1646,7 @@ func appendSkipComments(result []ast.Node, skipped []diffpolicy.SkippedChange) [
 }
 
 func (p *Planner) removeTables(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
-	for _, tableName := range deporder.TableDropOrder(diff.TablesRemoved, generated) {
+	for _, tableName := range deporder.TableDropOrderWithDependencies(diff.TablesRemoved, generated, diff.TableDropDependencies) {
 		dropTableNode := ast.NewDropTable(tableName).
 			SetIfExists().
 			SetCascade().
@@ -1030,6 +1768,83 @@ func findRange(ranges []goschema.Range, name string) *goschema.Range {
 	return nil
 }
 
+// rejectUniqueNullsNotDistinct fails the plan when a unique constraint or
+// index asks for NULLS NOT DISTINCT on a target without it (PostgreSQL
+// before 15, CockroachDB, Spanner), which would otherwise reject the
+// generated SQL.
+func (p *Planner) rejectUniqueNullsNotDistinct(diff *types.SchemaDiff, generated *goschema.Database) error {
+	if p.capabilities().Has(capability.UniqueNullsNotDistinct) {
+		return nil
+	}
+	unsupported := func(kind, name string) error {
+		return &ptaherr.CapabilityError{
+			Dialect: DialectName,
+			Feature: "unique nulls not distinct",
+			Err:     ptaherr.ErrUnsupportedFeature,
+			Message: fmt.Sprintf(
+				"the target does not support UNIQUE NULLS NOT DISTINCT (PostgreSQL 15+); remove nulls_not_distinct from %s %s or raise the target version",
+				kind, name,
+			),
+		}
+	}
+	if diff != nil {
+		for _, add := range diff.ConstraintsAddedWithTables {
+			if add.NullsDistinct != nil && !*add.NullsDistinct {
+				return unsupported("constraint", add.Name)
+			}
+		}
+	}
+	if generated == nil {
+		return nil
+	}
+	for _, constraint := range generated.Constraints {
+		if constraint.NullsDistinct != nil && !*constraint.NullsDistinct {
+			return unsupported("constraint", constraint.Name)
+		}
+	}
+	for _, index := range generated.Indexes {
+		if index.NullsDistinct != nil && !*index.NullsDistinct {
+			return unsupported("index", index.Name)
+		}
+	}
+	return nil
+}
+
+// requireRandomUUIDProvider fails the plan when a column default calls
+// gen_random_uuid() on a target without it built in (PostgreSQL before 13)
+// and the schema does not declare the pgcrypto extension that provides it
+// there. With pgcrypto declared, the extensions diff creates it ahead of the
+// tables that use it.
+func (p *Planner) requireRandomUUIDProvider(generated *goschema.Database) error {
+	if generated == nil || p.capabilities().Has(capability.BuiltinRandomUUID) {
+		return nil
+	}
+	for _, extension := range generated.Extensions {
+		if strings.EqualFold(extension.Name, "pgcrypto") {
+			return nil
+		}
+	}
+	for _, field := range generated.Fields {
+		expr := field.DefaultExpr
+		if override, ok := field.Overrides[DialectName]["default_expr"]; ok {
+			expr = override
+		}
+		if !strings.Contains(strings.ToLower(expr), "gen_random_uuid(") {
+			continue
+		}
+		return &ptaherr.CapabilityError{
+			Dialect: DialectName,
+			Feature: "gen_random_uuid",
+			Err:     ptaherr.ErrUnsupportedFeature,
+			Message: fmt.Sprintf(
+				"the target has no built-in gen_random_uuid() (PostgreSQL 13+); declare the pgcrypto extension for the default of field %s.%s or raise the target version",
+				field.StructName, field.Name,
+			),
+		}
+	}
+	return nil
+}
+
 // GenerateMigrationAST generates PostgreSQL-specific migration AST statements from schema differences.
 //
 // This method transforms the schema differences captured in the SchemaDiff into executable
@@ -1115,6 +1930,13 @@ func (p *Planner) GenerateMigrationAST(diff *types.SchemaDiff, generated *gosche
 func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated *goschema.Database) ([]ast.Node, error) {
 	var result []ast.Node
 
+	if err := p.rejectUniqueNullsNotDistinct(diff, generated); err != nil {
+		return nil, err
+	}
+	if err := p.requireRandomUUIDProvider(generated); err != nil {
+		return nil, err
+	}
+
 	// Apply the diff policy first so skipped destructive changes never reach the
 	// per-object emission below (and so a skipped DROP never trips the coarse
 	// destructive gate downstream). The omissions are surfaced as comments.
@@ -1124,8 +1946,15 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 		result = appendSkipComments(result, skipped)
 	}
 
-	// 0. Add new extensions first (PostgreSQL extensions should be created before other objects)
-	result = p.addNewExtensions(result, diff, generated)
+	// 0. Delete removed seed rows first, while every table and column they
+	// were written against still exists
+	result = p.removeSeedRows(result, diff)
+
+	// 0.1. Add new extensions (PostgreSQL extensions should be created before other objects)
+	if p.capabilities().Has(capability.Extensions) {
+		result = p.addNewExtensions(result, diff, generated)
+		result = p.updateExtensions(result, diff)
+	}
 
 	// 1. Add new roles (roles may be referenced by RLS policies and functions)
 	if p.capabilities().Has(capability.RoleManagement) {
@@ -1133,18 +1962,24 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	}
 
 	// 2. Add new functions (functions may be used by RLS policies)
-	result = p.addNewFunctions(result, diff, generated)
+	if p.capabilities().Has(capability.Functions) {
+		result = p.addNewFunctions(result, diff, generated)
+	}
 
 	// 2b. Modify existing function definitions (body, volatility, security, language).
 	// PostgreSQL CREATE OR REPLACE FUNCTION updates the live definition in place
 	// without affecting policies or triggers that reference the function.
-	result = p.modifyExistingFunctions(result, diff, generated)
+	if p.capabilities().Has(capability.Functions) {
+		result = p.modifyExistingFunctions(result, diff, generated)
+	}
 
 	// 2c. Add new sequences before tables, since a table column may draw its
 	// DEFAULT from a sequence. OWNED BY is applied later, after tables exist.
 	result = p.addNewSequences(result, diff, generated)
 
-	// 3. Add new enums (PostgreSQL requires enum types to exist before tables use them)
+	// 3. Rename enums declared with rename_from, then add new enums
+	// (PostgreSQL requires enum types to exist before tables use them)
+	result = p.renameEnums(result, diff)
 	result = p.addNewEnums(result, diff, generated)
 
 	// 3c. Recreate changed user-defined types (drop then create), then create
@@ -1161,6 +1996,13 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	// 6. Add and modify table columns (must be done before creating RLS policies that depend on columns)
 	result = p.addAndModifyTableColumns(result, diff, generated)
 
+	// 6.1. Attach existing tables to new INHERITS parents (the child must
+	// already have every parent column, so this follows column changes)
+	result = p.addTableInheritance(result, diff)
+
+	// 6.2. Transfer existing tables to their annotated owner
+	result = p.modifyTableOwners(result, diff)
+
 	// 6.5. Add foreign key constraints for newly added columns (must be done after all columns exist)
 	result = p.addForeignKeyConstraintsForModifiedTables(result, diff, generated)
 
@@ -1179,12 +2021,14 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	// 7. Modify existing roles (must be done before RLS policies that reference them)
 	if p.capabilities().Has(capability.RoleManagement) {
 		result = p.modifyExistingRoles(result, diff, generated)
+		result = p.grantRoleMemberships(result, diff)
 	}
 
 	// 7.5. Revoke removed grants before adding replacement grants.
 	if p.capabilities().Has(capability.RoleManagement) {
 		result = p.removeGrants(result, diff)
 		result = p.revokeGrantOptions(result, diff)
+		result = p.revokeRoleMemberships(result, diff)
 	}
 
 	// 8. Enable RLS on tables (must be done after table creation and modification)
@@ -1213,6 +2057,9 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	// unique indexes and constraints have been created.
 	result = p.addForeignKeyConstraintsForNewTables(result, diff, generated)
 
+	// 10.7. Insert seed rows once tables, columns, and constraints exist
+	result = p.addSeedRows(result, diff)
+
 	// 11. Remove indexes (safe operations)
 	result = p.removeIndexes(result, diff)
 
@@ -1226,12 +2073,21 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 		result = p.disableRLSOnTables(result, diff)
 	}
 
+	// 11.5. Detach tables from removed INHERITS parents (must be done before
+	// dropping columns they inherited and before DROP TABLE ... CASCADE on a
+	// removed parent would take the child with it)
+	result = p.removeTableInheritance(result, diff)
+
 	// 12. Remove table columns (must be done after removing RLS policies that depend on columns)
 	result = p.removeTableColumns(result, diff)
 
 	// 12.5. Remove constraints (must be done before removing tables)
 	result = p.removeConstraints(result, diff)
 
+	// 12.55. Drop NOT NULL from columns that left the primary key, now that
+	// the old key constraint is gone
+	result = p.relaxReleasedPrimaryKeyColumns(result, diff)
+
 	// 12.6. Remove triggers and view-like objects before dropping tables/functions they depend on.
 	result = p.removeTriggers(result, diff)
 	result = p.removeMaterializedViews(result, diff)
@@ -1244,7 +2100,9 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	result = p.removeSequences(result, diff)
 
 	// 13. Remove functions (must be done after removing policies that might use them)
-	result = p.removeFunctions(result, diff)
+	if p.capabilities().Has(capability.Functions) {
+		result = p.removeFunctions(result, diff)
+	}
 
 	// 14. Remove roles (must be done after removing functions and policies that depend on them)
 	if p.capabilities().Has(capability.RoleManagement) {
@@ -1256,11 +2114,77 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	result = p.removeEnums(result, diff)
 
 	// 16. Remove extensions (dangerous!)
-	result = p.removeExtensions(result, diff)
+	if p.capabilities().Has(capability.Extensions) {
+		result = p.removeExtensions(result, diff)
+	}
+
+	// 17. Validate constraints added NOT VALID above, once every other change
+	// has been applied.
+	result = p.deferConstraintValidation(result, diff, generated)
 
 	return result, nil
 }
 
+// deferConstraintValidation marks CHECK and FOREIGN KEY constraints added to
+// existing tables NOT VALID and appends one VALIDATE CONSTRAINT per constraint
+// to the end of the plan. It applies to every such constraint under
+// WithTwoStepConstraintValidation, and otherwise only to constraints declared
+// with not_valid="true". Tables created by this plan are empty, so their
+// constraints are left alone.
+func (p *Planner) deferConstraintValidation(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
+	if !p.capabilities().Has(capability.NotValidConstraints) {
+		return result
+	}
+	declared := make(map[string]struct{})
+	for _, constraint := range generated.Constraints {
+		if constraint.NotValid {
+			declared[constraint.Name] = struct{}{}
+		}
+	}
+	if !p.twoStepConstraintValidation && len(declared) == 0 {
+		return result
+	}
+	createdTables := make(map[string]struct{}, len(diff.TablesAdded))
+	for _, table := range deporder.TablesForCreate(generated, diff.TablesAdded) {
+		createdTables[table.QualifiedName()] = struct{}{}
+	}
+
+	var validations []ast.Node
+	for _, node := range result {
+		alter, ok := node.(*ast.AlterTableNode)
+		if !ok {
+			continue
+		}
+		if _, created := createdTables[alter.Name]; created {
+			continue
+		}
+		for _, operation := range alter.Operations {
+			add, ok := operation.(*ast.AddConstraintOperation)
+			if !ok || !validationCanBeDeferred(add.Constraint) {
+				continue
+			}
+			if _, ok := declared[add.Constraint.Name]; !ok && !p.twoStepConstraintValidation {
+				continue
+			}
+			add.Constraint.NotValid = true
+			validations = append(validations, &ast.AlterTableNode{
+				Name:       alter.Name,
+				Operations: []ast.AlterOperation{&ast.ValidateConstraintOperation{ConstraintName: add.Constraint.Name}},
+			})
+		}
+	}
+	return append(result, validations...)
+}
+
+// validationCanBeDeferred reports whether constraint can be added NOT VALID and
+// validated by name later.
+func validationCanBeDeferred(constraint *ast.ConstraintNode) bool {
+	if constraint == nil || constraint.Name == "" {
+		return false
+	}
+	return constraint.Type == ast.CheckConstraint || constraint.Type == ast.ForeignKeyConstraint
+}
+
 func (p *Planner) addNewRoles(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	for _, roleName := range diff.RolesAdded {
 		// Find the role definition
@@ -1392,7 +2316,11 @@ func (p *Planner) addReplicationOperation(alterRoleNode *ast.AlterRoleNode, chan
 func (p *Planner) addPasswordOperation(alterRoleNode *ast.AlterRoleNode, changeValue string, targetRole *goschema.Role) {
 	if changeValue == "password_update_required" {
 		// Use the target role to get the new password
-		if targetRole != nil && targetRole.Password != "" {
+		switch {
+		case targetRole == nil:
+		case targetRole.PasswordEnv != "":
+			alterRoleNode.AddOperation(ast.NewSetPasswordFromEnvOperation(targetRole.PasswordEnv))
+		case targetRole.Password != "":
 			alterRoleNode.AddOperation(ast.NewSetPasswordOperation(targetRole.Password))
 		}
 	}
@@ -1408,6 +2336,22 @@ func (p *Planner) removeRoles(result []ast.Node, diff *types.SchemaDiff) []ast.N
 	return result
 }
 
+func (p *Planner) grantRoleMemberships(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, membership := range diff.RoleMembershipsAdded {
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("GRANT %s TO %s",
+			quotePostgresIdentifier(membership.Role), quotePostgresIdentifier(membership.Member))))
+	}
+	return result
+}
+
+func (p *Planner) revokeRoleMemberships(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, membership := range diff.RoleMembershipsRemoved {
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("REVOKE %s FROM %s",
+			quotePostgresIdentifier(membership.Role), quotePostgresIdentifier(membership.Member))))
+	}
+	return result
+}
+
 func (p *Planner) addNewGrants(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
 	for _, grant := range diff.GrantsAdded {
 		node := ast.NewGrantPrivilege(grant.Role, grant.ObjectType, grant.ObjectName, []string{grant.Privilege}).
@@ -1453,6 +2397,52 @@ func (p *Planner) addNewExtensions(result []ast.Node, diff *types.SchemaDiff, ge
 	return result
 }
 
+// updateExtensions moves installed extensions to the version the schema pins.
+// PostgreSQL only follows the update scripts an extension ships, and few ship
+// downgrade paths, so a move to a lower version carries a warning.
+func (p *Planner) updateExtensions(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
+	for _, extension := range diff.ExtensionsModified {
+		if compareExtensionVersions(extension.TargetVersion, extension.CurrentVersion) < 0 {
+			result = append(result,
+				ast.NewComment(fmt.Sprintf("WARNING: Downgrading extension '%s' from %s to %s; PostgreSQL fails unless the extension ships a downgrade script",
+					extension.ExtensionName, extension.CurrentVersion, extension.TargetVersion)),
+			)
+		}
+		result = append(result, ast.NewRawSQL(fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s",
+			quotePostgresIdentifier(extension.ExtensionName), quotePostgresLiteral(extension.TargetVersion))))
+	}
+	return result
+}
+
+// compareExtensionVersions orders dotted extension versions such as 1.5 and
+// 1.10 numerically, falling back to text order for non-numeric parts. A
+// missing trailing part counts as 0, so 1.0 equals 1.
+func compareExtensionVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := range max(len(aParts), len(bParts)) {
+		aPart, bPart := "0", "0"
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+		aNum, aErr := strconv.Atoi(aPart)
+		bNum, bErr := strconv.Atoi(bPart)
+		if aErr == nil && bErr == nil {
+			if c := cmp.Compare(aNum, bNum); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := strings.Compare(aPart, bPart); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
 func (p *Planner) removeExtensions(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
 	// Generate DROP EXTENSION statements with comprehensive safety warnings
 	// Extension removal is potentially dangerous and requires careful consideration
@@ -1496,7 +2486,7 @@ func (p *Planner) modifyExistingFunctions(result []ast.Node, diff *types.SchemaD
 		// not tell us the new body/attributes).
 		var target *goschema.Function
 		for i := range generated.Functions {
-			if generated.Functions[i].Name == fnDiff.FunctionName {
+			if generated.Functions[i].QualifiedName() == fnDiff.FunctionName {
 				target = &generated.Functions[i]
 				break
 			}
@@ -1506,7 +2496,7 @@ func (p *Planner) modifyExistingFunctions(result []ast.Node, diff *types.SchemaD
 		}
 
 		functionNode := fromschema.FromFunction(*target)
-		functionNode.SetComment(fmt.Sprintf("Modify function %s: %s", target.Name, summarizeFunctionChanges(fnDiff)))
+		functionNode.SetComment(fmt.Sprintf("Modify function %s: %s", target.QualifiedName(), summarizeFunctionChanges(fnDiff)))
 		result = append(result, functionNode)
 	}
 	return result
@@ -1518,8 +2508,15 @@ func summarizeFunctionChanges(fnDiff types.FunctionDiff) string {
 	return strings.Join(slices.Sorted(maps.Keys(fnDiff.Changes)), ", ")
 }
 
+// removeFunctions drops removed functions. A function still executed by a
+// trigger the diff keeps cannot be dropped, so it gets a MANUAL comment
+// naming those triggers instead of a DROP FUNCTION that would fail.
 func (p *Planner) removeFunctions(result []ast.Node, diff *types.SchemaDiff) []ast.Node {
 	for _, functionName := range diff.FunctionsRemoved {
+		if triggers := diff.BlockingFunctionTriggers(functionName); len(triggers) > 0 {
+			result = append(result, ast.NewComment(manualFunctionDropComment(functionName, triggers)))
+			continue
+		}
 		dropFunctionNode := ast.NewDropFunction(functionName).
 			SetIfExists().
 			SetComment("WARNING: Ensure no other objects depend on this function")
@@ -1698,10 +2695,18 @@ func (p *Planner) removeViews(result []ast.Node, diff *types.SchemaDiff) []ast.N
 }
 
 func (p *Planner) modifyExistingMaterializedViews(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
+	addedIndexes := stringSet(diff.IndexesAdded)
 	for _, viewDiff := range diff.MaterializedViewsModified {
 		if view := findMaterializedView(generated.MaterializedViews, viewDiff.ViewName); view != nil {
 			result = append(result, ast.NewDropMaterializedView(view.Name).SetIfExists().SetCascade())
 			result = append(result, fromschema.FromMaterializedView(*view))
+			// DROP ... CASCADE took the view's indexes with it; recreate the
+			// declared ones the diff does not already add.
+			for _, index := range fromschema.MaterializedViewIndexes(generated.Indexes, *view) {
+				if _, added := addedIndexes[index.Name]; !added {
+					result = append(result, fromschema.FromIndexWithTableMapping(index, map[string]string{view.StructName: view.Name}))
+				}
+			}
 		}
 	}
 	return result
@@ -1772,7 +2777,7 @@ func findTrigger(triggers []goschema.Trigger, tableName, triggerName string) *go
 
 func findRLSPolicy(policies []goschema.RLSPolicy, tableName, policyName string) *goschema.RLSPolicy {
 	for i := range policies {
-		if policies[i].Table == tableName && policies[i].Name == policyName {
+		if policies[i].QualifiedTable() == tableName && policies[i].Name == policyName {
 			return &policies[i]
 		}
 	}
@@ -1783,7 +2788,7 @@ func (p *Planner) enableRLSOnTables(result []ast.Node, diff *types.SchemaDiff, g
 	// Create a set of tables that need RLS enabled
 	tablesNeedingRLS := make(map[string]bool)
 	for _, policy := range generated.RLSPolicies {
-		tablesNeedingRLS[policy.Table] = true
+		tablesNeedingRLS[policy.QualifiedTable()] = true
 	}
 
 	// Enable RLS on tables that have policies but don't have RLS enabled yet.
@@ -1822,8 +2827,8 @@ func (p *Planner) addNewRLSPolicies(result []ast.Node, diff *types.SchemaDiff, g
 	for _, policyName := range diff.RLSPoliciesAdded {
 		// Find the policy definition
 		for _, policy := range generated.RLSPolicies {
-			if policy.Name == policyName {
-				policyNode := fromschema.FromRLSPolicy(policy)
+			if policy.QualifiedName() == policyName {
+				policyNode := fromschema.FromRLSPolicy(fromschema.QualifyPolicyFunctionCalls(policy, generated.Functions))
 				// Set Replace flag to handle conflicts gracefully during migrations
 				policyNode.Replace = true
 				result = append(result, policyNode)
@@ -1837,7 +2842,7 @@ func (p *Planner) addNewRLSPolicies(result []ast.Node, diff *types.SchemaDiff, g
 func (p *Planner) modifyExistingRLSPolicies(result []ast.Node, diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	for _, policyDiff := range diff.RLSPoliciesModified {
 		if policy := findRLSPolicy(generated.RLSPolicies, policyDiff.TableName, policyDiff.PolicyName); policy != nil {
-			policyNode := fromschema.FromRLSPolicy(*policy).SetReplace()
+			policyNode := fromschema.FromRLSPolicy(fromschema.QualifyPolicyFunctionCalls(*policy, generated.Functions)).SetReplace()
 			policyNode.SetComment(fmt.Sprintf("Modify RLS policy %s on table %s: %s",
 				policyDiff.PolicyName,
 				policyDiff.TableName,
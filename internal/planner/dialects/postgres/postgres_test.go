@@ -161,7 +161,7 @@ func TestPlanner_GenerateMigrationSQL_EnumsModified(t *testing.T) {
 					strings.Contains(rawNode.SQL, `ALTER TYPE "user_status" RENAME TO "user_status__ptah_old";`) &&
 					strings.Contains(rawNode.SQL, `CREATE TYPE "user_status" AS ENUM ('active', 'suspended');`) &&
 					strings.Contains(rawNode.SQL, `ALTER TABLE "users" ALTER COLUMN "status" TYPE "user_status" USING "status"::text::"user_status";`) &&
-					strings.Contains(rawNode.SQL, `ALTER TABLE "users" ALTER COLUMN "status" SET DEFAULT 'active';`) &&
+					strings.Contains(rawNode.SQL, `ALTER TABLE "users" ALTER COLUMN "status" SET DEFAULT 'active'::"user_status";`) &&
 					strings.Contains(rawNode.SQL, `DROP TYPE "user_status__ptah_old";`)
 			},
 		},
@@ -844,7 +844,7 @@ func TestPlanner_GeneratedColumnExpressionChangeOnPostgres16RequiresManualMigrat
 	nodes := postgres.NewWithCapabilities(capability.Postgres16()).GenerateMigrationAST(diff, generated)
 	sql, err := renderer.RenderSQLWithCapabilities("postgres", capability.Postgres16(), nodes...)
 	c.Assert(err, qt.IsNil)
-	c.Assert(sql, qt.Contains, "WARNING: Generated column users.slug changed, but ALTER COLUMN SET EXPRESSION requires PostgreSQL 17+; manual migration required.")
+	c.Assert(sql, qt.Contains, "-- MANUAL: users.slug generated expression (upper(name)) -> (lower(name)) needs PostgreSQL 17+; ptah cannot alter this automatically")
 	c.Assert(sql, qt.Not(qt.Contains), `DROP COLUMN "slug"`)
 	c.Assert(sql, qt.Not(qt.Contains), `ADD COLUMN "slug"`)
 	c.Assert(sql, qt.Not(qt.Contains), "SET EXPRESSION AS")
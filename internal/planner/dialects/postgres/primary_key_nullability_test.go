@@ -0,0 +1,70 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// primaryKeyChangeDiff replaces the memberships primary key with one on
+// columns while user_id changes nullability.
+func primaryKeyChangeDiff(columns []string, nullable string) *types.SchemaDiff {
+	return &types.SchemaDiff{
+		TablesModified: []types.TableDiff{{
+			TableName:       "memberships",
+			ColumnsModified: []types.ColumnDiff{{ColumnName: "user_id", Changes: map[string]string{"nullable": nullable}}},
+		}},
+		ConstraintsAdded:             []string{"memberships_pkey"},
+		ConstraintsRemoved:           []string{"memberships_pkey"},
+		ConstraintsAddedWithTables:   []types.ConstraintAdditionInfo{{Name: "memberships_pkey", TableName: "memberships", Type: "PRIMARY KEY", Columns: columns}},
+		ConstraintsRemovedWithTables: []types.ConstraintRemovalInfo{{Name: "memberships_pkey", TableName: "memberships", Type: "PRIMARY KEY"}},
+	}
+}
+
+func primaryKeyMembershipSchema(primaryKey ...string) *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Membership", Name: "memberships", PrimaryKey: primaryKey}},
+		Fields: []goschema.Field{
+			{StructName: "Membership", Name: "org_id", Type: "INTEGER"},
+			{StructName: "Membership", Name: "user_id", Type: "INTEGER", Nullable: true},
+		},
+	}
+}
+
+func TestPlanner_ColumnJoiningPrimaryKeyIsSetNotNull(t *testing.T) {
+	c := qt.New(t)
+	diff := primaryKeyChangeDiff([]string{"org_id", "user_id"}, "true -> false")
+
+	nodes, err := postgres.New().GenerateMigrationASTChecked(diff, primaryKeyMembershipSchema("org_id", "user_id"))
+	c.Assert(err, qt.IsNil)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+
+	setNotNull := strings.Index(sql, `ALTER COLUMN "user_id" SET NOT NULL;`)
+	addKey := strings.Index(sql, `ADD PRIMARY KEY ("org_id", "user_id");`)
+	c.Assert(setNotNull >= 0, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(addKey > setNotNull, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(sql, qt.Not(qt.Contains), "DROP NOT NULL")
+}
+
+func TestPlanner_ColumnLeavingPrimaryKeyDropsNotNullAfterTheKey(t *testing.T) {
+	c := qt.New(t)
+	diff := primaryKeyChangeDiff([]string{"org_id"}, "false -> true")
+
+	nodes, err := postgres.New().GenerateMigrationASTChecked(diff, primaryKeyMembershipSchema("org_id"))
+	c.Assert(err, qt.IsNil)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+
+	dropKey := strings.Index(sql, `DROP CONSTRAINT IF EXISTS "memberships_pkey";`)
+	dropNotNull := strings.Index(sql, `ALTER TABLE "memberships" ALTER COLUMN "user_id" DROP NOT NULL;`)
+	c.Assert(dropKey >= 0, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(dropNotNull > dropKey, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(strings.Count(sql, "DROP NOT NULL"), qt.Equals, 1, qt.Commentf("sql: %s", sql))
+}
@@ -0,0 +1,37 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_RoleMembershipsGrantAndRevoke(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		RolesAdded:             []string{"app_user"},
+		RoleMembershipsAdded:   []types.RoleMembership{{Role: "app_readonly", Member: "app_user"}},
+		RoleMembershipsRemoved: []types.RoleMembership{{Role: "dba_granted", Member: "app_user"}},
+	}
+	generated := &goschema.Database{
+		Roles: []goschema.Role{{Name: "app_user", Login: true, MemberOf: []string{"app_readonly"}}},
+	}
+
+	nodes, err := postgres.New().GenerateMigrationASTChecked(diff, generated)
+	c.Assert(err, qt.IsNil)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+
+	createRole := strings.Index(sql, "CREATE ROLE")
+	grant := strings.Index(sql, `GRANT "app_readonly" TO "app_user";`)
+	c.Assert(createRole >= 0, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(grant > createRole, qt.IsTrue, qt.Commentf("sql: %s", sql))
+	c.Assert(sql, qt.Contains, `REVOKE "dba_granted" FROM "app_user";`)
+}
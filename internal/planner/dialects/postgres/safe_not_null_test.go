@@ -0,0 +1,88 @@
+package postgres_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func notNullDiff() *types.SchemaDiff {
+	return &types.SchemaDiff{TablesModified: []types.TableDiff{{
+		TableName: "users",
+		ColumnsModified: []types.ColumnDiff{{
+			ColumnName: "status",
+			Changes:    map[string]string{"nullable": "true -> false"},
+		}},
+	}}}
+}
+
+func notNullSchema(def string) *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+		Fields: []goschema.Field{{StructName: "User", Name: "status", Type: "TEXT", Nullable: false, Default: def}},
+	}
+}
+
+func renderSafeNotNull(c *qt.C, p *postgres.Planner, generated *goschema.Database) string {
+	sql, err := renderer.RenderSQL("postgres", p.GenerateMigrationAST(notNullDiff(), generated)...)
+	c.Assert(err, qt.IsNil)
+	return sql
+}
+
+func TestPlanner_SafeNotNullBackfillsAndValidatesCheck(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderSafeNotNull(c, postgres.New().WithSafeNotNull(), notNullSchema("active"))
+
+	c.Assert(sql, qt.Equals, "-- Add/modify columns for table: users --\n"+
+		"UPDATE \"users\" SET \"status\" = 'active' WHERE \"status\" IS NULL;\n"+
+		"-- ALTER statements: --\n"+
+		"ALTER TABLE \"users\" ADD CONSTRAINT \"users_status_not_null\" CHECK (\"status\" IS NOT NULL) NOT VALID;\n\n"+
+		"-- ALTER statements: --\n"+
+		"ALTER TABLE \"users\" VALIDATE CONSTRAINT \"users_status_not_null\";\n"+
+		"ALTER TABLE \"users\" ALTER COLUMN \"status\" TYPE TEXT;\n"+
+		"ALTER TABLE \"users\" ALTER COLUMN \"status\" SET NOT NULL;\n"+
+		"ALTER TABLE \"users\" ALTER COLUMN \"status\" SET DEFAULT 'active';\n"+
+		"ALTER TABLE \"users\" DROP CONSTRAINT \"users_status_not_null\";\n"+
+		"\n"+
+		"-- Modify column users.status: nullable: true -> false --\n")
+}
+
+func TestPlanner_SafeNotNullWithoutNotValidCapabilitySkipsCheck(t *testing.T) {
+	c := qt.New(t)
+
+	caps := capability.Postgres16().With(capability.NotValidConstraints, false)
+	sql := renderSafeNotNull(c, postgres.NewWithCapabilities(caps).WithSafeNotNull(), notNullSchema("active"))
+
+	c.Assert(sql, qt.Contains, "UPDATE \"users\" SET \"status\" = 'active' WHERE \"status\" IS NULL;\n")
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"users\" ALTER COLUMN \"status\" SET NOT NULL;\n")
+	c.Assert(sql, qt.Not(qt.Contains), "NOT VALID")
+	c.Assert(sql, qt.Not(qt.Contains), "users_status_not_null")
+}
+
+func TestPlanner_SafeNotNullWithoutDefaultWarnsInsteadOfBackfilling(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderSafeNotNull(c, postgres.New().WithSafeNotNull(), notNullSchema(""))
+
+	c.Assert(sql, qt.Contains, "-- WARNING: Column users.status becomes NOT NULL but has no default; backfill existing NULL values manually before applying this migration. --\n")
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"users\" ALTER COLUMN \"status\" SET NOT NULL;\n")
+	c.Assert(sql, qt.Not(qt.Contains), "UPDATE")
+	c.Assert(sql, qt.Not(qt.Contains), "NOT VALID")
+}
+
+func TestPlanner_NotNullWithoutSafeNotNullKeepsImplicitBackfill(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderSafeNotNull(c, postgres.New(), notNullSchema(""))
+
+	c.Assert(sql, qt.Contains, "UPDATE \"users\" SET \"status\" = '' WHERE \"status\" IS NULL;")
+	c.Assert(sql, qt.Not(qt.Contains), "WARNING")
+	c.Assert(sql, qt.Not(qt.Contains), "NOT VALID")
+}
@@ -0,0 +1,152 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func typeChangeDiff(change string, kind types.TypeChangeKind) *types.SchemaDiff {
+	return &types.SchemaDiff{TablesModified: []types.TableDiff{{
+		TableName: "orders",
+		ColumnsModified: []types.ColumnDiff{{
+			ColumnName:     "quantity",
+			Changes:        map[string]string{"type": change},
+			TypeChangeKind: kind,
+		}},
+	}}}
+}
+
+func typeChangeSchema(field goschema.Field) *goschema.Database {
+	field.StructName = "Order"
+	field.Name = "quantity"
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Order", Name: "orders"}},
+		Fields: []goschema.Field{field},
+	}
+}
+
+func renderTypeChange(c *qt.C, p *postgres.Planner, diff *types.SchemaDiff, generated *goschema.Database) string {
+	sql, err := renderer.RenderSQL("postgres", p.GenerateMigrationAST(diff, generated)...)
+	c.Assert(err, qt.IsNil)
+	return sql
+}
+
+func TestPlanner_SafeTypeChangeSwapsThroughTemporaryColumn(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderTypeChange(c, postgres.New().WithSafeTypeChange(),
+		typeChangeDiff("text -> INTEGER", types.TypeChangeIncompatible),
+		typeChangeSchema(goschema.Field{Type: "INTEGER", TypeCast: "NULLIF(quantity, '')::integer", Default: "0", DefaultSet: true}))
+
+	c.Assert(sql, qt.Equals, "-- Add/modify columns for table: orders --\n"+
+		"-- Safe type change on orders.quantity (text -> INTEGER): copy into quantity__ptah_new, then swap it in and recreate its keys and indexes --\n"+
+		"ALTER TABLE \"orders\" ADD COLUMN \"quantity__ptah_new\" INTEGER;\n"+
+		"UPDATE \"orders\" SET \"quantity__ptah_new\" = NULLIF(quantity, '')::integer;\n"+
+		"ALTER TABLE \"orders\" DROP COLUMN \"quantity\";\n"+
+		"ALTER TABLE \"orders\" RENAME COLUMN \"quantity__ptah_new\" TO \"quantity\";\n"+
+		"ALTER TABLE \"orders\" ALTER COLUMN \"quantity\" SET NOT NULL;\n"+
+		"ALTER TABLE \"orders\" ALTER COLUMN \"quantity\" SET DEFAULT '0';\n"+
+		"-- Modify column orders.quantity: type: text -> INTEGER --\n")
+}
+
+func TestPlanner_SafeTypeChangeRecreatesKeysAndIndexes(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     goschema.Field
+		generated func(*goschema.Database)
+		want      string
+	}{
+		{
+			name:  "single-column index",
+			field: goschema.Field{Type: "INTEGER", Nullable: true},
+			generated: func(db *goschema.Database) {
+				db.Indexes = []goschema.Index{{Name: "idx_orders_quantity", StructName: "Order", Fields: []string{"quantity"}}}
+			},
+			want: "CREATE INDEX IF NOT EXISTS \"idx_orders_quantity\" ON \"orders\" (\"quantity\");\n",
+		},
+		{
+			name:  "composite index",
+			field: goschema.Field{Type: "INTEGER", Nullable: true},
+			generated: func(db *goschema.Database) {
+				db.Indexes = []goschema.Index{{Name: "idx_orders_sku_quantity", StructName: "Order", Fields: []string{"sku", "quantity"}}}
+			},
+			want: "CREATE INDEX IF NOT EXISTS \"idx_orders_sku_quantity\" ON \"orders\" (\"sku\", \"quantity\");\n",
+		},
+		{
+			name:  "field unique",
+			field: goschema.Field{Type: "INTEGER", Nullable: true, Unique: true},
+			want:  "ALTER TABLE \"orders\" ADD CONSTRAINT \"orders_quantity_key\" UNIQUE (\"quantity\");\n",
+		},
+		{
+			name:  "table unique constraint",
+			field: goschema.Field{Type: "INTEGER", Nullable: true},
+			generated: func(db *goschema.Database) {
+				db.Constraints = []goschema.Constraint{{Name: "uq_orders_sku_quantity", StructName: "Order", Type: "UNIQUE", Columns: []string{"sku", "quantity"}}}
+			},
+			want: "ALTER TABLE \"orders\" ADD CONSTRAINT \"uq_orders_sku_quantity\" UNIQUE (\"sku\", \"quantity\");\n",
+		},
+		{
+			name:  "primary key",
+			field: goschema.Field{Type: "INTEGER", Primary: true},
+			want:  "ALTER TABLE \"orders\" ADD PRIMARY KEY (\"quantity\");\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated := typeChangeSchema(tt.field)
+			if tt.generated != nil {
+				tt.generated(generated)
+			}
+
+			sql := renderTypeChange(c, postgres.New().WithSafeTypeChange(),
+				typeChangeDiff("text -> INTEGER", types.TypeChangeIncompatible), generated)
+
+			rename := strings.Index(sql, "RENAME COLUMN \"quantity__ptah_new\" TO \"quantity\"")
+			recreate := strings.Index(sql, tt.want)
+			c.Assert(rename, qt.Not(qt.Equals), -1, qt.Commentf("%s", sql))
+			c.Assert(recreate > rename, qt.IsTrue, qt.Commentf("%s", sql))
+		})
+	}
+}
+
+func TestPlanner_SafeTypeChangeDefaultsToPlainCast(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderTypeChange(c, postgres.New().WithSafeTypeChange(),
+		typeChangeDiff("text -> INTEGER", types.TypeChangeIncompatible),
+		typeChangeSchema(goschema.Field{Type: "INTEGER", Nullable: true}))
+
+	c.Assert(sql, qt.Contains, "UPDATE \"orders\" SET \"quantity__ptah_new\" = \"quantity\"::INTEGER;\n")
+	c.Assert(sql, qt.Not(qt.Contains), "SET NOT NULL")
+}
+
+func TestPlanner_SafeTypeChangeKeepsInPlaceAlterForWidening(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderTypeChange(c, postgres.New().WithSafeTypeChange(),
+		typeChangeDiff("integer -> BIGINT", types.TypeChangeWidening),
+		typeChangeSchema(goschema.Field{Type: "BIGINT", Nullable: true}))
+
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"orders\" ALTER COLUMN \"quantity\" TYPE BIGINT;\n")
+	c.Assert(sql, qt.Not(qt.Contains), "__ptah_new")
+}
+
+func TestPlanner_IncompatibleTypeChangeWithoutSafeTypeChangeAltersInPlace(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderTypeChange(c, postgres.New(),
+		typeChangeDiff("text -> INTEGER", types.TypeChangeIncompatible),
+		typeChangeSchema(goschema.Field{Type: "INTEGER", Nullable: true}))
+
+	c.Assert(sql, qt.Contains, "-- WARNING: incompatible type change on orders.quantity (text -> INTEGER); existing values may fail to convert --\n")
+	c.Assert(sql, qt.Not(qt.Contains), "__ptah_new")
+}
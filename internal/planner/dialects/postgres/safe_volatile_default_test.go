@@ -0,0 +1,127 @@
+package postgres_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func addedColumnDiff() *types.SchemaDiff {
+	return &types.SchemaDiff{TablesModified: []types.TableDiff{{
+		TableName:    "orders",
+		ColumnsAdded: []string{"token"},
+	}}}
+}
+
+func addedColumnSchema(field goschema.Field) *goschema.Database {
+	field.StructName = "Order"
+	field.Name = "token"
+	return &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Order", Name: "orders"}},
+		Fields: []goschema.Field{field},
+	}
+}
+
+func TestIsVolatileDefault(t *testing.T) {
+	functions := []goschema.Function{
+		{Name: "next_code", Volatility: "VOLATILE"},
+		{Name: "tenant_prefix", Schema: "app", Volatility: "STABLE"},
+		{Name: "make_slug"},
+	}
+	tests := []struct {
+		name string
+		expr string
+		want bool
+	}{
+		{name: "uuid", expr: "gen_random_uuid()", want: true},
+		{name: "contrib uuid", expr: "uuid_generate_v4()", want: true},
+		{name: "schema-qualified uuid", expr: "public.uuid_generate_v4()", want: true},
+		{name: "clock", expr: "CLOCK_TIMESTAMP()", want: true},
+		{name: "random inside an expression", expr: "floor(random() * 100)::int", want: true},
+		{name: "sequence", expr: "nextval('orders_seq'::regclass)", want: true},
+		{name: "declared volatile function", expr: "next_code()", want: true},
+		{name: "declared function without volatility", expr: "make_slug('x')", want: true},
+		{name: "quoted declared function", expr: `"next_code"()`, want: true},
+		{name: "declared stable function", expr: "app.tenant_prefix()", want: false},
+		{name: "now is stable", expr: "now()", want: false},
+		{name: "current timestamp", expr: "CURRENT_TIMESTAMP", want: false},
+		{name: "constant", expr: "'pending'::text", want: false},
+		{name: "function name inside a literal", expr: "'random()'", want: false},
+		{name: "stable builtins", expr: "lower(coalesce(current_setting('app.tenant'), ''))", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt.Assert(t, postgres.IsVolatileDefault(tt.expr, functions), qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestPlanner_SafeVolatileDefaultSplitsAddColumn(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderTypeChange(c, postgres.New().WithSafeVolatileDefault(), addedColumnDiff(),
+		addedColumnSchema(goschema.Field{Type: "UUID", DefaultExpr: "gen_random_uuid()"}))
+
+	c.Assert(sql, qt.Equals, "-- Add/modify columns for table: orders --\n"+
+		"-- Volatile default on orders.token (gen_random_uuid()): add the column without it, then set the default and backfill existing rows --\n"+
+		"-- ALTER statements: --\n"+
+		"ALTER TABLE \"orders\" ADD COLUMN \"token\" UUID;\n\n"+
+		"ALTER TABLE \"orders\" ALTER COLUMN \"token\" SET DEFAULT gen_random_uuid();\n"+
+		"-- WARNING: The backfill of orders.token updates every existing row in one statement; on a large table, run it in batches sized for the workload (for example by primary key range) before applying this migration. --\n"+
+		"UPDATE \"orders\" SET \"token\" = gen_random_uuid() WHERE \"token\" IS NULL;\n"+
+		"-- ALTER statements: --\n"+
+		"ALTER TABLE \"orders\" ADD CONSTRAINT \"orders_token_not_null\" CHECK (\"token\" IS NOT NULL) NOT VALID;\n\n"+
+		"ALTER TABLE \"orders\" VALIDATE CONSTRAINT \"orders_token_not_null\";\n"+
+		"ALTER TABLE \"orders\" ALTER COLUMN \"token\" SET NOT NULL;\n"+
+		"ALTER TABLE \"orders\" DROP CONSTRAINT \"orders_token_not_null\";\n")
+}
+
+func TestPlanner_SafeVolatileDefaultNullableColumnSkipsNotNull(t *testing.T) {
+	c := qt.New(t)
+
+	sql := renderTypeChange(c, postgres.New().WithSafeVolatileDefault(), addedColumnDiff(),
+		addedColumnSchema(goschema.Field{Type: "TIMESTAMPTZ", Nullable: true, DefaultExpr: "clock_timestamp()"}))
+
+	c.Assert(sql, qt.Contains, "ALTER TABLE \"orders\" ADD COLUMN \"token\" TIMESTAMPTZ;\n\n"+
+		"ALTER TABLE \"orders\" ALTER COLUMN \"token\" SET DEFAULT clock_timestamp();\n")
+	c.Assert(sql, qt.Contains, "UPDATE \"orders\" SET \"token\" = clock_timestamp() WHERE \"token\" IS NULL;\n")
+	c.Assert(sql, qt.Not(qt.Contains), "NOT NULL;")
+}
+
+func TestPlanner_SafeVolatileDefaultKeepsSingleAddColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		planner *postgres.Planner
+		field   goschema.Field
+		want    string
+	}{
+		{
+			name:    "stable default",
+			planner: postgres.New().WithSafeVolatileDefault(),
+			field:   goschema.Field{Type: "TIMESTAMPTZ", DefaultExpr: "now()"},
+			want:    "ALTER TABLE \"orders\" ADD COLUMN \"token\" TIMESTAMPTZ NOT NULL DEFAULT now();\n",
+		},
+		{
+			name:    "option off",
+			planner: postgres.New(),
+			field:   goschema.Field{Type: "UUID", DefaultExpr: "gen_random_uuid()"},
+			want:    "ALTER TABLE \"orders\" ADD COLUMN \"token\" UUID NOT NULL DEFAULT gen_random_uuid();\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			sql := renderTypeChange(c, tt.planner, addedColumnDiff(), addedColumnSchema(tt.field))
+
+			c.Assert(sql, qt.Contains, tt.want)
+			c.Assert(sql, qt.Not(qt.Contains), "UPDATE")
+		})
+	}
+}
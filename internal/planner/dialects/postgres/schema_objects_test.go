@@ -111,6 +111,54 @@ func TestPlanner_GenerateMigrationAST_MaterializedViewRefreshStrategyDoesNotAuto
 	c.Assert(sql, qt.Not(qt.Contains), "REFRESH MATERIALIZED VIEW CONCURRENTLY")
 }
 
+func userStatsWithIndexSchema() *goschema.Database {
+	return &goschema.Database{
+		MaterializedViews: []goschema.MaterializedView{{
+			StructName: "UserStats",
+			Name:       "user_stats",
+			Body:       "SELECT user_id, COUNT(*) AS cnt FROM orders GROUP BY user_id",
+		}},
+		Indexes: []goschema.Index{{
+			StructName: "UserStats",
+			Name:       "idx_user_stats_user",
+			Fields:     []string{"user_id"},
+			Unique:     true,
+		}},
+	}
+}
+
+func TestPlanner_GenerateMigrationAST_AddedMaterializedViewIndexFollowsView(t *testing.T) {
+	c := qt.New(t)
+	diff := &difftypes.SchemaDiff{
+		MaterializedViewsAdded: []string{"user_stats"},
+		IndexesAdded:           []string{"idx_user_stats_user"},
+	}
+
+	sql, err := renderer.RenderSQL("postgres", postgres.New().GenerateMigrationAST(diff, userStatsWithIndexSchema())...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	createView := strings.Index(sql, "CREATE MATERIALIZED VIEW user_stats AS")
+	createIndex := strings.Index(sql, "CREATE UNIQUE INDEX IF NOT EXISTS idx_user_stats_user ON user_stats (user_id);")
+	c.Assert(createView >= 0, qt.IsTrue)
+	c.Assert(createIndex > createView, qt.IsTrue, qt.Commentf("sql:\n%s", sql))
+}
+
+func TestPlanner_GenerateMigrationAST_ModifiedMaterializedViewRecreatesIndexes(t *testing.T) {
+	c := qt.New(t)
+	diff := &difftypes.SchemaDiff{
+		MaterializedViewsModified: []difftypes.MaterializedViewDiff{{ViewName: "user_stats", Changes: map[string]string{"body": "old -> new"}}},
+	}
+
+	sql, err := renderer.RenderSQL("postgres", postgres.New().GenerateMigrationAST(diff, userStatsWithIndexSchema())...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Contains, "DROP MATERIALIZED VIEW IF EXISTS user_stats CASCADE;")
+	c.Assert(strings.Count(sql, "CREATE UNIQUE INDEX IF NOT EXISTS idx_user_stats_user ON user_stats (user_id);"), qt.Equals, 1)
+	c.Assert(strings.Index(sql, "CREATE UNIQUE INDEX") > strings.Index(sql, "CREATE MATERIALIZED VIEW"), qt.IsTrue)
+}
+
 func TestPlanner_GenerateMigrationAST_OrdersFunctionsByDependencies(t *testing.T) {
 	c := qt.New(t)
 	planner := postgres.New()
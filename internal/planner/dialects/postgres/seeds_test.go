@@ -0,0 +1,68 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestPlanner_InsertsSeedRowsAfterTableCreation(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		TablesAdded: []string{"roles"},
+		SeedsAdded: []types.SeedData{{
+			Table:   "roles",
+			Columns: []string{"id", "name"},
+			Rows:    [][]string{{"1", "admin"}, {"2", "o'brien"}, {"3", "NULL"}},
+		}},
+	}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Role", Name: "roles"}},
+		Fields: []goschema.Field{
+			{StructName: "Role", Name: "id", Type: "INTEGER", Primary: true},
+			{StructName: "Role", Name: "name", Type: "TEXT", Nullable: true},
+		},
+	}
+
+	nodes := postgres.New().GenerateMigrationAST(diff, generated)
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	insert := `INSERT INTO roles (id, name) VALUES ('1', 'admin'), ('2', 'o''brien'), ('3', NULL) ON CONFLICT DO NOTHING;`
+	c.Assert(sql, qt.Contains, insert)
+	c.Assert(strings.Index(sql, "CREATE TABLE roles") < strings.Index(sql, insert), qt.IsTrue)
+}
+
+func TestPlanner_DeletesRemovedSeedRowsFirst(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{
+		TablesRemoved: []string{"countries"},
+		TablesModified: []types.TableDiff{{
+			TableName:      "roles",
+			ColumnsRemoved: []string{"label"},
+		}},
+		SeedsRemoved: []types.SeedData{
+			{Table: "roles", Columns: []string{"id", "label"}, Rows: [][]string{{"1", "admin"}, {"2", "NULL"}}},
+			{Table: "countries", Columns: []string{"code"}, Rows: [][]string{{"DE"}}},
+		},
+	}
+
+	nodes := postgres.New().GenerateMigrationAST(diff, &goschema.Database{})
+	sql, err := renderer.RenderSQL("postgres", nodes...)
+	c.Assert(err, qt.IsNil)
+	sql = legacyRenderedSQL(sql)
+
+	c.Assert(sql, qt.Contains, "DELETE FROM roles WHERE id = '1' AND label = 'admin';")
+	c.Assert(sql, qt.Contains, "DELETE FROM roles WHERE id = '2' AND label IS NULL;")
+	c.Assert(sql, qt.Not(qt.Contains), "DELETE FROM countries")
+	c.Assert(strings.Index(sql, "DELETE FROM roles") < strings.Index(sql, "DROP COLUMN label"), qt.IsTrue)
+}
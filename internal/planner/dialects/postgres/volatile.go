@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/stokaro/ptah/core/goschema"
+)
+
+// volatileBuiltinFunctions lists the PostgreSQL built-in and contrib
+// functions marked VOLATILE that show up in column defaults. Stable functions
+// such as now() and CURRENT_TIMESTAMP are evaluated once per statement, so
+// PostgreSQL 11+ stores them as a fast default like any constant.
+var volatileBuiltinFunctions = map[string]struct{}{
+	"clock_timestamp":    {},
+	"currval":            {},
+	"gen_random_uuid":    {},
+	"lastval":            {},
+	"nextval":            {},
+	"random":             {},
+	"random_normal":      {},
+	"setval":             {},
+	"timeofday":          {},
+	"uuid_generate_v1":   {},
+	"uuid_generate_v1mc": {},
+	"uuid_generate_v4":   {},
+	"uuidv4":             {},
+	"uuidv7":             {},
+}
+
+var (
+	sqlStringLiteralRE = regexp.MustCompile(`'(?:[^']|'')*'`)
+	functionCallRE     = regexp.MustCompile(`([a-z_][a-z0-9_$]*(?:\s*\.\s*[a-z_][a-z0-9_$]*)?)\s*\(`)
+)
+
+// IsVolatileDefault reports whether the default expression expr calls a
+// volatile function, which makes ADD COLUMN ... DEFAULT evaluate it for, and
+// rewrite, every existing row. Constant and stable defaults are stored once
+// in the catalog instead.
+//
+// Functions declared in functions are classified by their volatility, where
+// an unset volatility means VOLATILE as in CREATE FUNCTION. Other calls are
+// classified by the built-in list; unknown functions are assumed not
+// volatile.
+func IsVolatileDefault(expr string, functions []goschema.Function) bool {
+	normalized := strings.ReplaceAll(strings.ToLower(sqlStringLiteralRE.ReplaceAllString(expr, "''")), `"`, "")
+	for _, match := range functionCallRE.FindAllStringSubmatch(normalized, -1) {
+		name := strings.Join(strings.Fields(match[1]), "")
+		if declared, ok := declaredFunction(functions, name); ok {
+			if volatility := strings.ToUpper(strings.TrimSpace(declared.Volatility)); volatility == "" || volatility == "VOLATILE" {
+				return true
+			}
+			continue
+		}
+		if _, ok := volatileBuiltinFunctions[name[strings.LastIndex(name, ".")+1:]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredFunction finds the function called as name, which is either bare
+// or schema-qualified and already lower-cased.
+func declaredFunction(functions []goschema.Function, name string) (goschema.Function, bool) {
+	for _, function := range functions {
+		if strings.EqualFold(function.QualifiedName(), name) || strings.EqualFold(function.Name, name) {
+			return function, true
+		}
+	}
+	return goschema.Function{}, false
+}
@@ -11,17 +11,32 @@ import (
 	"github.com/stokaro/ptah/core/platform"
 	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/internal/convert/fromschema"
+	"github.com/stokaro/ptah/internal/deporder"
 	"github.com/stokaro/ptah/migration/schemadiff/types"
 )
 
 const DialectName = platform.SQLite
 
-type Planner struct{}
+type Planner struct {
+	cascadeCyclicTableDrops bool
+}
 
 func New() *Planner {
 	return &Planner{}
 }
 
+// WithCascadeCyclicTableDrops returns a copy of the planner that drops removed
+// tables whose foreign keys reference each other after PRAGMA
+// defer_foreign_keys = ON, which postpones the checks to the end of the
+// migration transaction, when the tables are gone. SQLite has no DROP TABLE
+// ... CASCADE. Without it such a plan is rejected. The receiver is not
+// modified.
+func (p *Planner) WithCascadeCyclicTableDrops() *Planner {
+	cp := *p
+	cp.cascadeCyclicTableDrops = true
+	return &cp
+}
+
 func (p *Planner) GenerateMigrationAST(diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
 	nodes, _ := p.GenerateMigrationASTChecked(diff, generated)
 	return nodes
@@ -34,7 +49,7 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	if generated == nil {
 		generated = &goschema.Database{}
 	}
-	if err := rejectUnsupportedChanges(diff); err != nil {
+	if err := rejectUnsupportedChanges(diff, generated); err != nil {
 		return nil, err
 	}
 	if err := validateAddedColumns(diff, generated); err != nil {
@@ -60,12 +75,15 @@ func (p *Planner) GenerateMigrationASTChecked(diff *types.SchemaDiff, generated
 	result = append(result, p.removeIndexes(diff)...)
 	result = append(result, p.removeTriggers(diff)...)
 	result = append(result, p.removeViews(diff)...)
-	result = append(result, p.removeTables(diff)...)
-	return result, nil
+	removedTables, err := p.removeTables(diff, generated)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, removedTables...), nil
 }
 
-func rejectUnsupportedChanges(diff *types.SchemaDiff) error {
-	if err := rejectUnsupportedTableChanges(diff); err != nil {
+func rejectUnsupportedChanges(diff *types.SchemaDiff, generated *goschema.Database) error {
+	if err := rejectUnsupportedTableChanges(diff, generated); err != nil {
 		return err
 	}
 	if err := rejectUnsupportedSchemaObjects(diff); err != nil {
@@ -77,7 +95,7 @@ func rejectUnsupportedChanges(diff *types.SchemaDiff) error {
 	return nil
 }
 
-func rejectUnsupportedTableChanges(diff *types.SchemaDiff) error {
+func rejectUnsupportedTableChanges(diff *types.SchemaDiff, generated *goschema.Database) error {
 	for _, table := range diff.TablesModified {
 		switch {
 		case len(table.ColumnsModified) > 0:
@@ -89,7 +107,7 @@ func rejectUnsupportedTableChanges(diff *types.SchemaDiff) error {
 			return unsupportedFeaturef("changing constraints on table %s requires a table rebuild plan", table.TableName)
 		}
 	}
-	if len(diff.ConstraintsAdded) > 0 || len(diff.ConstraintsRemoved) > 0 {
+	if _, ok := checkConstraintRebuildTables(diff, generated); !ok {
 		return unsupportedFeaturef("changing constraints on existing tables requires a table rebuild plan")
 	}
 	if len(diff.EnumsModified) > 0 || len(diff.EnumsRemoved) > 0 {
@@ -102,7 +120,7 @@ func rejectUnsupportedSchemaObjects(diff *types.SchemaDiff) error {
 	if len(diff.MaterializedViewsAdded) > 0 || len(diff.MaterializedViewsModified) > 0 || len(diff.MaterializedViewsRemoved) > 0 {
 		return unsupportedFeaturef("materialized views are not supported")
 	}
-	if len(diff.ExtensionsAdded) > 0 || len(diff.ExtensionsRemoved) > 0 {
+	if len(diff.ExtensionsAdded) > 0 || len(diff.ExtensionsRemoved) > 0 || len(diff.ExtensionsModified) > 0 {
 		return unsupportedFeaturef("extensions are not supported")
 	}
 	if len(diff.FunctionsAdded) > 0 || len(diff.FunctionsModified) > 0 || len(diff.FunctionsRemoved) > 0 {
@@ -125,6 +143,7 @@ func rejectUnsupportedAccessControl(diff *types.SchemaDiff) error {
 		return unsupportedFeaturef("row-level security is not supported")
 	}
 	if len(diff.RolesAdded) > 0 || len(diff.RolesModified) > 0 || len(diff.RolesRemoved) > 0 ||
+		len(diff.RoleMembershipsAdded) > 0 || len(diff.RoleMembershipsRemoved) > 0 ||
 		len(diff.GrantsAdded) > 0 || len(diff.GrantsRemoved) > 0 ||
 		len(diff.GrantOptionsAdded) > 0 || len(diff.GrantOptionsRevoked) > 0 {
 		return unsupportedFeaturef("roles and grants are not supported")
@@ -196,7 +215,7 @@ func (p *Planner) modifyTables(diff *types.SchemaDiff, generated *goschema.Datab
 	var result []ast.Node
 	for _, tableDiff := range diff.TablesModified {
 		if len(tableDiff.ColumnsRemoved) > 0 {
-			nodes, err := p.rebuildTableWithoutColumns(tableDiff, diff, generated)
+			nodes, err := p.rebuildTable(tableDiff.TableName, "remove unsupported columns from", diff, generated)
 			if err != nil {
 				return nil, err
 			}
@@ -212,17 +231,168 @@ func (p *Planner) modifyTables(diff *types.SchemaDiff, generated *goschema.Datab
 			}
 		}
 	}
+
+	// SQLite cannot alter a CHECK constraint in place, so CHECK changes --
+	// including the CHECK (column IN (...)) that models an enum column under
+	// platform.EnumStrategyCheckConstraint -- rebuild the table. Tables that
+	// were already rebuilt above picked up the new constraints with it.
+	tables, _ := checkConstraintRebuildTables(diff, generated)
+	for _, tableName := range tables {
+		if tableDiffRemovesColumns(diff, tableName) {
+			continue
+		}
+		nodes, err := p.rebuildTable(tableName, "change CHECK constraints on", diff, generated)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, nodes...)
+	}
 	return result, nil
 }
 
-func (p *Planner) rebuildTableWithoutColumns(
-	tableDiff types.TableDiff,
+// checkConstraintRebuildTables returns, in diff order, the tables touched by
+// the top-level constraint changes when every one of them is a CHECK
+// constraint. ok is false when any change is not a CHECK on a known table,
+// which SQLite has no rebuild plan for.
+func checkConstraintRebuildTables(diff *types.SchemaDiff, generated *goschema.Database) (tables []string, ok bool) {
+	seen := make(map[string]bool)
+	add := func(tableName string) {
+		if !seen[tableName] {
+			seen[tableName] = true
+			tables = append(tables, tableName)
+		}
+	}
+	for _, name := range diff.ConstraintsAdded {
+		if constraintOnAddedTable(diff, generated, name) {
+			// New tables are created with their constraints inline.
+			continue
+		}
+		tableName := generatedCheckConstraintTable(generated, name)
+		if tableName == "" {
+			return nil, false
+		}
+		add(tableName)
+	}
+	for _, name := range diff.ConstraintsRemoved {
+		if constraintOnRemovedTable(diff, name) {
+			// Dropping the table drops its constraints.
+			continue
+		}
+		tableName := removedCheckConstraintTable(diff, name)
+		if tableName == "" {
+			return nil, false
+		}
+		if !slices.Contains(diff.TablesRemoved, tableName) {
+			add(tableName)
+		}
+	}
+	return tables, true
+}
+
+// constraintOnAddedTable reports whether the declared constraint name belongs
+// to a table this plan creates.
+func constraintOnAddedTable(diff *types.SchemaDiff, generated *goschema.Database, name string) bool {
+	if slices.ContainsFunc(diff.ConstraintsAddedWithTables, func(added types.ConstraintAdditionInfo) bool {
+		return added.Name == name && slices.Contains(diff.TablesAdded, added.TableName)
+	}) {
+		return true
+	}
+	for _, constraint := range generated.Constraints {
+		if constraint.Name != name {
+			continue
+		}
+		for _, table := range generated.Tables {
+			if constraintBelongsToTable(constraint, table) &&
+				(slices.Contains(diff.TablesAdded, table.Name) || slices.Contains(diff.TablesAdded, table.QualifiedName())) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generatedCheckConstraintTable resolves the table of a CHECK constraint in the
+// generated schema: either a declared CHECK constraint or the inline CHECK of a
+// field (an explicit check= or an enum column), named the way SQLite's reader
+// names unnamed inline checks. It returns "" when name is not such a CHECK.
+func generatedCheckConstraintTable(generated *goschema.Database, name string) string {
+	for _, constraint := range generated.Constraints {
+		if constraint.Name != name || !strings.EqualFold(constraint.Type, "CHECK") {
+			continue
+		}
+		if constraint.Table != "" {
+			return constraint.Table
+		}
+		if table := findTableByStruct(generated.Tables, constraint.StructName); table != nil {
+			return table.QualifiedName()
+		}
+	}
+	for _, field := range generated.Fields {
+		if field.Check == "" && len(field.Enum) == 0 {
+			continue
+		}
+		table := findTableByStruct(generated.Tables, field.StructName)
+		if table == nil {
+			continue
+		}
+		checkName := field.CheckName
+		if checkName == "" {
+			checkName = table.Name + "_" + field.Name + "_check"
+		}
+		if checkName == name {
+			return table.QualifiedName()
+		}
+	}
+	return ""
+}
+
+// constraintOnRemovedTable reports whether the removed constraint name
+// belongs to a table this plan drops.
+func constraintOnRemovedTable(diff *types.SchemaDiff, name string) bool {
+	return slices.ContainsFunc(diff.ConstraintsRemovedWithTables, func(removed types.ConstraintRemovalInfo) bool {
+		return removed.Name == name && slices.Contains(diff.TablesRemoved, removed.TableName)
+	})
+}
+
+func removedCheckConstraintTable(diff *types.SchemaDiff, name string) string {
+	for _, removed := range diff.ConstraintsRemovedWithTables {
+		if removed.Name == name && strings.EqualFold(removed.Type, "CHECK") {
+			return removed.TableName
+		}
+	}
+	return ""
+}
+
+func tableDiffRemovesColumns(diff *types.SchemaDiff, tableName string) bool {
+	for _, tableDiff := range diff.TablesModified {
+		if tableDiff.TableName == tableName && len(tableDiff.ColumnsRemoved) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func findTableByStruct(tables []goschema.Table, structName string) *goschema.Table {
+	for i := range tables {
+		if tables[i].StructName == structName {
+			return &tables[i]
+		}
+	}
+	return nil
+}
+
+// rebuildTable recreates tableName from its generated definition under a
+// temporary name, copies the retained columns across, and swaps it in place.
+// reason completes the "SQLite table rebuild to <reason> <table>" comment.
+func (p *Planner) rebuildTable(
+	tableName string,
+	reason string,
 	diff *types.SchemaDiff,
 	generated *goschema.Database,
 ) ([]ast.Node, error) {
-	table := findTable(generated.Tables, tableDiff.TableName)
+	table := findTable(generated.Tables, tableName)
 	if table == nil {
-		return nil, unsupportedFeaturef("rebuilding table %s requires the retained table definition", tableDiff.TableName)
+		return nil, unsupportedFeaturef("rebuilding table %s requires the retained table definition", tableName)
 	}
 	if err := validateRebuildTablePreconditions(*table, diff, generated); err != nil {
 		return nil, err
@@ -242,7 +412,7 @@ func (p *Planner) rebuildTableWithoutColumns(
 	}
 
 	nodes := []ast.Node{
-		ast.NewComment("SQLite table rebuild to remove unsupported columns from " + table.QualifiedName()),
+		ast.NewComment("SQLite table rebuild to " + reason + " " + table.QualifiedName()),
 		createNode,
 		ast.NewRawSQL("INSERT INTO " + quoteQualifiedIdentifier(createNode.Name) +
 			" (" + quoteIdentifierList(columns) + ") SELECT " + quoteIdentifierList(columns) +
@@ -505,25 +675,38 @@ func structToTableMap(tables []goschema.Table) map[string]string {
 }
 
 func (p *Planner) removeIndexes(diff *types.SchemaDiff) []ast.Node {
-	var result []ast.Node
-	for _, info := range diff.IndexesRemovedWithTables {
-		result = append(result, ast.NewDropIndex(info.Name).SetIfExists())
-	}
-	if len(result) > 0 {
+	if len(diff.IndexesRemovedWithTables) > 0 {
+		var result []ast.Node
+		for _, info := range diff.IndexesRemovedWithTables {
+			if diff.IndexDroppedWithTable(info) {
+				continue
+			}
+			result = append(result, ast.NewDropIndex(info.Name).SetIfExists())
+		}
 		return result
 	}
+	var result []ast.Node
 	for _, name := range diff.IndexesRemoved {
 		result = append(result, ast.NewDropIndex(name).SetIfExists())
 	}
 	return result
 }
 
-func (p *Planner) removeTables(diff *types.SchemaDiff) []ast.Node {
-	result := make([]ast.Node, 0, len(diff.TablesRemoved))
-	for _, tableName := range diff.TablesRemoved {
+// removeTables drops referencing tables before the tables they reference.
+// With foreign keys enabled SQLite empties a dropped table first, which fails
+// while another table still points at its rows.
+func (p *Planner) removeTables(diff *types.SchemaDiff, generated *goschema.Database) ([]ast.Node, error) {
+	result := make([]ast.Node, 0, len(diff.TablesRemoved)+1)
+	if cyclic := deporder.CyclicNodes(diff.TablesRemoved, diff.TableDropDependencies); len(cyclic) > 0 {
+		if !p.cascadeCyclicTableDrops {
+			return nil, unsupportedFeaturef("tables %s reference each other through foreign keys, so no drop order satisfies them; enable cascading cyclic table drops to defer the checks", strings.Join(cyclic, ", "))
+		}
+		result = append(result, ast.NewRawSQL("PRAGMA defer_foreign_keys = ON;"))
+	}
+	for _, tableName := range deporder.TableDropOrderWithDependencies(diff.TablesRemoved, generated, diff.TableDropDependencies) {
 		result = append(result, ast.NewDropTable(tableName).SetIfExists().SetComment("WARNING: This will delete all data!"))
 	}
-	return result
+	return result, nil
 }
 
 func (p *Planner) addViews(diff *types.SchemaDiff, generated *goschema.Database) []ast.Node {
@@ -133,6 +133,53 @@ func TestPlannerRebuildsTableWhenDroppingColumn(t *testing.T) {
 	c.Assert(sql, qt.Not(qt.Contains), "DROP COLUMN")
 }
 
+func TestPlannerRebuildsTableWhenEnumCheckValuesChange(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{
+			{Name: "id", Type: "INTEGER", StructName: "User", Primary: true},
+			{Name: "status", Type: "enum_user_status", StructName: "User", Enum: []string{"active", "inactive", "banned"}},
+		},
+		Enums: []goschema.Enum{{Name: "enum_user_status", Values: []string{"active", "inactive", "banned"}}},
+	}
+	diff := &types.SchemaDiff{
+		ConstraintsAdded:   []string{"users_status_check"},
+		ConstraintsRemoved: []string{"users_status_check"},
+		ConstraintsRemovedWithTables: []types.ConstraintRemovalInfo{
+			{Name: "users_status_check", TableName: "users", Type: "CHECK"},
+		},
+	}
+
+	sql, err := planner.GenerateSchemaDiffSQL(diff, generated, platform.SQLite)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, "-- SQLite table rebuild to change CHECK constraints on users")
+	c.Assert(sql, qt.Contains, `CREATE TABLE "__ptah_rebuild_users"`)
+	c.Assert(sql, qt.Contains, "CHECK (status IN ('active', 'inactive', 'banned'))")
+	c.Assert(sql, qt.Contains, `INSERT INTO "__ptah_rebuild_users" ("id", "status") SELECT "id", "status" FROM "users";`)
+	c.Assert(sql, qt.Contains, `ALTER TABLE "__ptah_rebuild_users" RENAME TO "users";`)
+}
+
+func TestPlannerRejectsNonCheckConstraintChangesOnExistingTables(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{{Name: "email", Type: "TEXT", StructName: "User"}},
+		Constraints: []goschema.Constraint{
+			{Name: "users_email_key", Type: "UNIQUE", StructName: "User", Columns: []string{"email"}},
+		},
+	}
+	diff := &types.SchemaDiff{ConstraintsAdded: []string{"users_email_key"}}
+
+	_, err := planner.GenerateSchemaDiffAST(diff, generated, platform.SQLite)
+
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrUnsupportedFeature)
+	c.Assert(err, qt.ErrorMatches, "sqlite: changing constraints on existing tables requires a table rebuild plan")
+}
+
 func TestPlannerRejectsUnsafeTableRebuildPreconditions(t *testing.T) {
 	c := qt.New(t)
 
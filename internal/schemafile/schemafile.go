@@ -3,9 +3,11 @@ package schemafile
 
 import (
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/stokaro/ptah/core/goschema"
@@ -205,17 +207,20 @@ func toDBColumn(field goschema.Field, ordinal int) dbschematypes.DBColumn {
 		nullable = "YES"
 	}
 	column := dbschematypes.DBColumn{
-		Name:            field.Name,
-		DataType:        field.Type,
-		ColumnType:      field.Type,
-		IsNullable:      nullable,
-		OrdinalPosition: ordinal,
-		IsAutoIncrement: field.AutoInc || field.IdentityGeneration != "",
-		IsPrimaryKey:    field.Primary,
-		IsUnique:        field.Unique,
-		Charset:         field.Charset,
-		Collate:         field.Collate,
-		GeneratedKind:   field.GeneratedKind,
+		Name:               field.Name,
+		DataType:           field.Type,
+		ColumnType:         field.Type,
+		IsNullable:         nullable,
+		OrdinalPosition:    ordinal,
+		IsAutoIncrement:    field.AutoInc || field.IdentityGeneration != "",
+		IsPrimaryKey:       field.Primary,
+		IsUnique:           field.Unique,
+		Charset:            field.Charset,
+		Collate:            field.Collate,
+		GeneratedKind:      field.GeneratedKind,
+		UpdateExpression:   field.UpdateExpression,
+		Comment:            field.Comment,
+		IdentityGeneration: field.IdentityGeneration,
 	}
 	if field.DefaultSet {
 		column.ColumnDefault = new(field.Default)
@@ -274,6 +279,8 @@ func toDBIndexes(indexes []goschema.Index, tables map[string]goschema.Table) []d
 			IsUnique:      index.Unique,
 			Condition:     index.Condition,
 			NullsDistinct: index.NullsDistinct,
+			StorageParams: maps.Clone(index.StorageParams),
+			Invisible:     index.Visible != nil && !*index.Visible,
 			Type:          index.Type,
 			Granularity:   index.Granularity,
 		})
@@ -434,6 +441,7 @@ func toDBFunctions(functions []goschema.Function) []dbschematypes.DBFunction {
 		function.Canonicalize()
 		out = append(out, dbschematypes.DBFunction{
 			Name:       function.Name,
+			Schema:     function.Schema,
 			Parameters: function.Parameters,
 			Returns:    function.Returns,
 			Language:   function.Language,
@@ -501,7 +509,7 @@ func toDBRLSPolicies(policies []goschema.RLSPolicy) []dbschematypes.DBRLSPolicy
 	for _, policy := range policies {
 		out = append(out, dbschematypes.DBRLSPolicy{
 			Name:                policy.Name,
-			Table:               policy.Table,
+			Table:               policy.QualifiedTable(),
 			PolicyFor:           policy.PolicyFor,
 			ToRoles:             policy.ToRoles,
 			UsingExpression:     policy.UsingExpression,
@@ -523,6 +531,7 @@ func toDBRoles(roles []goschema.Role) []dbschematypes.DBRole {
 			CreateRole:  role.CreateRole,
 			Inherit:     role.Inherit,
 			Replication: role.Replication,
+			MemberOf:    slices.Sorted(slices.Values(role.MemberOf)),
 			HasPassword: role.Password != "",
 			Comment:     role.Comment,
 		})
@@ -45,6 +45,7 @@ table "users" {
   column "email" {
     null = false
     type = varchar(255)
+    comment = "Login address"
   }
   primary_key {
     columns = [column.id]
@@ -68,6 +69,7 @@ table "users" {
 	c.Assert(got.Tables[0].Columns[0].IsPrimaryKey, qt.IsTrue)
 	c.Assert(got.Tables[0].Columns[1].Name, qt.Equals, "email")
 	c.Assert(got.Tables[0].Columns[1].IsNullable, qt.Equals, "NO")
+	c.Assert(got.Tables[0].Columns[1].Comment, qt.Equals, "Login address")
 	c.Assert(got.Indexes, qt.HasLen, 1)
 	c.Assert(got.Indexes[0].Name, qt.Equals, "idx_users_email")
 	c.Assert(got.Indexes[0].IsUnique, qt.IsTrue)
@@ -105,6 +105,9 @@ func FilterGeneratedWithDefaultSchema(
 	filtered.Grants = keep(db.Grants, func(grant goschema.Grant) bool {
 		return grantAllowed(allowed, keptTables, grant, defaultSchema)
 	})
+	filtered.Seeds = keep(db.Seeds, func(seed goschema.Seed) bool {
+		return tableReferenceAllowed(keptTables, seed.Table)
+	})
 	filtered.Enums = keepReferencedGeneratedEnums(db.Enums, filtered.Fields)
 	filtered.Dependencies = filterDependencies(db.Dependencies, keptTables)
 	filtered.FunctionDependencies = filterNamedDependencies(db.FunctionDependencies, allowed, defaultSchema)
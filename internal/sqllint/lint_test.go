@@ -85,8 +85,8 @@ func TestLintSource_UnsupportedParserErrorIsExplicit(t *testing.T) {
 	c := qt.New(t)
 
 	findings, err := LintSource(Source{
-		Name: "policy.sql",
-		SQL:  "CREATE POLICY p ON users USING (true);",
+		Name: "sequence.sql",
+		SQL:  "CREATE SEQUENCE order_numbers START 1000;",
 	}, Options{Dialect: platform.Postgres})
 
 	c.Assert(err, qt.IsNil)
@@ -183,7 +183,7 @@ func TestLintSource_UnsupportedStatementDoesNotMaskLaterDDL(t *testing.T) {
 
 	findings, err := LintSource(Source{
 		Name: "mixed.sql",
-		SQL: `CREATE POLICY p ON users USING (true);
+		SQL: `CREATE SEQUENCE order_numbers START 1000;
 CREATE TABLE audit_log (message TEXT NOT NULL);`,
 	}, Options{Dialect: platform.Postgres})
 
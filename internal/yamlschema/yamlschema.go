@@ -233,11 +233,13 @@ type roleSpec struct {
 	Name        stringScalar `yaml:"name"`
 	Login       bool         `yaml:"login"`
 	Password    stringScalar `yaml:"password"`
+	PasswordEnv stringScalar `yaml:"password_env"`
 	Superuser   bool         `yaml:"superuser"`
 	CreateDB    bool         `yaml:"create_db"`
 	CreateRole  bool         `yaml:"create_role"`
 	Inherit     *bool        `yaml:"inherit"`
 	Replication bool         `yaml:"replication"`
+	MemberOf    stringList   `yaml:"member_of"`
 	Comment     stringScalar `yaml:"comment"`
 }
 
@@ -734,11 +736,13 @@ func (d document) addRoles(db *goschema.Database) {
 			Name:        valueOrDefault(spec.Name, key),
 			Login:       spec.Login,
 			Password:    string(spec.Password),
+			PasswordEnv: string(spec.PasswordEnv),
 			Superuser:   spec.Superuser,
 			CreateDB:    spec.CreateDB,
 			CreateRole:  spec.CreateRole,
 			Inherit:     inherit,
 			Replication: spec.Replication,
+			MemberOf:    cleanStrings(spec.MemberOf),
 			Comment:     string(spec.Comment),
 		})
 	}
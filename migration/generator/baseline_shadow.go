@@ -130,7 +130,7 @@ func resetBaselineShadowSchemas(ctx context.Context, conn *dbschema.DatabaseConn
 	return nil
 }
 
-func dropBaselineShadowMetadata(ctx context.Context, conn *dbschema.DatabaseConnection, tableIdentifier string) error {
+func dropBaselineShadowMetadata(ctx context.Context, conn dbschema.Querier, tableIdentifier string) error {
 	_, err := conn.ExecContext(ctx, "DROP TABLE IF EXISTS "+tableIdentifier)
 	if err != nil {
 		return fmt.Errorf("baseline shadow check failed: drop metadata table: %w", err)
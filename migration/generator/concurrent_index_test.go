@@ -23,7 +23,7 @@ func TestPlanGeneratedMigrationSpecs_ConcurrentIndexForPopulatedPostgresTable(t
 		postgresInfo(capability.Postgres16()),
 		100,
 		"add_user_email_index",
-		DiffPolicy{},
+		planOptions{},
 	)
 
 	c.Assert(err, qt.IsNil)
@@ -72,7 +72,7 @@ func TestPlanGeneratedMigrationSpecs_ConcurrentIndexRequiresPopulatedCapablePost
 		t.Run(tt.name, func(t *testing.T) {
 			c := qt.New(t)
 
-			specs, _, err := planGeneratedMigrationSpecs(indexOnlyDiff(), indexOnlyGeneratedSchema(), tt.dbSchema, tt.info, 100, "add_index", DiffPolicy{})
+			specs, _, err := planGeneratedMigrationSpecs(indexOnlyDiff(), indexOnlyGeneratedSchema(), tt.dbSchema, tt.info, 100, "add_index", planOptions{})
 
 			c.Assert(err, qt.IsNil)
 			c.Assert(specs, qt.HasLen, 1)
@@ -106,7 +106,7 @@ func TestPlanGeneratedMigrationSpecs_SplitsTransactionalAndConcurrentIndex(t *te
 		postgresInfo(capability.Postgres16()),
 		100,
 		"add_posts_and_user_index",
-		DiffPolicy{},
+		planOptions{},
 	)
 
 	c.Assert(err, qt.IsNil)
@@ -144,7 +144,7 @@ func TestPlanGeneratedMigrationSpecs_SplitsPopulatedAndEmptyTableIndexes(t *test
 		{Name: "posts", Type: "BASE TABLE", EstimatedRows: 0},
 	}}
 
-	specs, _, err := planGeneratedMigrationSpecs(diff, generated, dbSchema, postgresInfo(capability.Postgres16()), 100, "add_indexes", DiffPolicy{})
+	specs, _, err := planGeneratedMigrationSpecs(diff, generated, dbSchema, postgresInfo(capability.Postgres16()), 100, "add_indexes", planOptions{})
 
 	c.Assert(err, qt.IsNil)
 	c.Assert(specs, qt.HasLen, 2)
@@ -178,7 +178,7 @@ func TestPlanGeneratedMigrationSpecs_RefusesUnsplitNonTransactionalMix(t *testin
 		Enums: []goschema.Enum{{Name: "status", Values: []string{"active", "archived"}}},
 	}
 
-	specs, _, err := planGeneratedMigrationSpecs(diff, generated, &dbschematypes.DBSchema{}, postgresInfo(capability.Postgres16()), 100, "mixed", DiffPolicy{})
+	specs, _, err := planGeneratedMigrationSpecs(diff, generated, &dbschematypes.DBSchema{}, postgresInfo(capability.Postgres16()), 100, "mixed", planOptions{})
 
 	c.Assert(specs, qt.IsNil)
 	c.Assert(err, qt.ErrorMatches, "generated migration mixes transactional statements with non-transactional statements that cannot be split automatically")
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// dataMigrationMarker opens the data migration block written under
+// GenerateMigrationOptions.ScaffoldDataMigration.
+const dataMigrationMarker = "-- DATA MIGRATION"
+
+// tighteningStatementPattern matches the first line of a statement that
+// removes data or tightens a column: DROP TABLE, DROP COLUMN, SET NOT NULL,
+// and MySQL's MODIFY COLUMN ... NOT NULL. A backfill has to run before it.
+var tighteningStatementPattern = regexp.MustCompile(
+	`(?i)^\s*(DROP\s+TABLE\b|ALTER\s+TABLE\b.*\b(DROP\s+COLUMN|SET\s+NOT\s+NULL|MODIFY\s+.*\bNOT\s+NULL)\b)`)
+
+// withDataMigrationScaffold adds a data migration block to the first
+// generated migration, the one carrying the structural changes. The block
+// goes after the additive statements and before the first statement that
+// drops or tightens a column, so the backfill runs while both the old and
+// the new columns exist. It holds only comments: a TODO for every column
+// that likely needs a backfill in the up file, and a reminder to undo the
+// backfill in the down file.
+func withDataMigrationScaffold(specs []generatedMigrationSpec, diff *types.SchemaDiff, generated *goschema.Database) {
+	if len(specs) == 0 {
+		return
+	}
+	specs[0].UpSQL = insertSQLBlock(specs[0].UpSQL, upDataMigrationBlock(dataMigrationTODOs(diff, generated)))
+	specs[0].DownSQL = insertSQLBlock(specs[0].DownSQL, downDataMigrationBlock)
+}
+
+// dataMigrationTODOs lists the existing-table changes where a backfill is
+// likely needed: columns added NOT NULL, columns changed to NOT NULL, and
+// tables that both add and remove columns, which is how a rename appears.
+func dataMigrationTODOs(diff *types.SchemaDiff, generated *goschema.Database) []string {
+	fields := generatedFieldsByColumn(generated)
+	var todos []string
+	for _, tableDiff := range diff.TablesModified {
+		for _, column := range tableDiff.ColumnsAdded {
+			field, ok := fields[tableDiff.TableName+"."+column]
+			if !ok || field.Nullable || field.Primary {
+				continue
+			}
+			todos = append(todos, fmt.Sprintf("backfill %s.%s (added NOT NULL%s)", tableDiff.TableName, column, defaultNote(field)))
+		}
+		for _, columnDiff := range tableDiff.ColumnsModified {
+			if columnDiff.Changes["nullable"] != "true -> false" {
+				continue
+			}
+			field := fields[tableDiff.TableName+"."+columnDiff.ColumnName]
+			todos = append(todos, fmt.Sprintf("backfill %s.%s (changed to NOT NULL%s)", tableDiff.TableName, columnDiff.ColumnName, defaultNote(field)))
+		}
+		if len(tableDiff.ColumnsAdded) > 0 && len(tableDiff.ColumnsRemoved) > 0 {
+			todos = append(todos, fmt.Sprintf("%s removes %s and adds %s; if this is a rename, copy the data before the old column is dropped",
+				tableDiff.TableName, strings.Join(tableDiff.ColumnsRemoved, ", "), strings.Join(tableDiff.ColumnsAdded, ", ")))
+		}
+	}
+	return todos
+}
+
+// generatedFieldsByColumn indexes the generated fields by qualified
+// table.column name.
+func generatedFieldsByColumn(generated *goschema.Database) map[string]goschema.Field {
+	tables := generatedStructTableMap(generated)
+	fields := make(map[string]goschema.Field, len(generated.Fields))
+	for _, field := range generated.Fields {
+		fields[tables[field.StructName]+"."+field.Name] = field
+	}
+	return fields
+}
+
+func defaultNote(field goschema.Field) string {
+	switch {
+	case field.DefaultExpr != "":
+		return ", default " + field.DefaultExpr
+	case field.DefaultSet:
+		return fmt.Sprintf(", default '%s'", field.Default)
+	default:
+		return ""
+	}
+}
+
+func upDataMigrationBlock(todos []string) string {
+	var b strings.Builder
+	b.WriteString(dataMigrationMarker + "\n")
+	b.WriteString("-- Write the data changes that go with the schema changes above, or\n")
+	b.WriteString("-- delete this block if none are needed.\n")
+	if len(todos) == 0 {
+		b.WriteString("-- TODO: no backfill was detected.\n")
+	}
+	for _, todo := range todos {
+		b.WriteString("-- TODO: " + todo + "\n")
+	}
+	return b.String()
+}
+
+const downDataMigrationBlock = dataMigrationMarker + "\n" +
+	"-- Reverse the data changes made by the up migration, or delete this\n" +
+	"-- block if none are needed.\n" +
+	"-- TODO: undo the up migration backfill.\n"
+
+// insertSQLBlock inserts block into sql before the first tightening
+// statement and the comment lines directly above it, separated by blank
+// lines. Without such a statement block is appended.
+func insertSQLBlock(sql, block string) string {
+	lines := strings.SplitAfter(sql, "\n")
+	at := slices.IndexFunc(lines, tighteningStatementPattern.MatchString)
+	if at < 0 {
+		return appendSQLBlock(sql, block)
+	}
+	for at > 0 && strings.HasPrefix(strings.TrimSpace(lines[at-1]), "--") {
+		at--
+	}
+	head := strings.Join(lines[:at], "")
+	tail := strings.Join(lines[at:], "")
+	if head == "" {
+		return block + "\n" + tail
+	}
+	return appendSQLBlock(strings.TrimRight(head, "\n"), block) + "\n" + tail
+}
+
+// appendSQLBlock appends block to sql, separated by a blank line.
+func appendSQLBlock(sql, block string) string {
+	if sql != "" && !strings.HasSuffix(sql, "\n") {
+		sql += "\n"
+	}
+	return sql + "\n" + block
+}
@@ -0,0 +1,162 @@
+package generator
+
+// White-box testing required: the backfill TODOs cover column changes that
+// SQLite cannot plan without a table rebuild, so they are checked on the
+// unexported scaffold helpers with a PostgreSQL diff and no live server.
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const dataMigrationTODOSchema = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+	//migrator:schema:field name="display_name" type="TEXT" not_null="true"
+	DisplayName string
+	//migrator:schema:field name="status" type="TEXT" not_null="true" default="active"
+	Status string
+	//migrator:schema:field name="bio" type="TEXT"
+	Bio string
+}
+
+//migrator:schema:table name="accounts"
+type Account struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+	//migrator:schema:field name="code" type="TEXT" not_null="true" default_expr="gen_random_uuid()::text"
+	Code string
+}
+`
+
+func TestDataMigrationTODOs(t *testing.T) {
+	tests := []struct {
+		name string
+		live []dbschematypes.DBTable
+		want []string
+	}{
+		{
+			name: "added, tightened, and possibly renamed columns",
+			live: []dbschematypes.DBTable{
+				{Name: "users", Type: "BASE TABLE", Columns: []dbschematypes.DBColumn{
+					{Name: "id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+					{Name: "nickname", DataType: "text", IsNullable: "YES"},
+					{Name: "status", DataType: "text", IsNullable: "YES", ColumnDefault: new("'active'::text")},
+					{Name: "bio", DataType: "text", IsNullable: "YES"},
+				}},
+				{Name: "accounts", Type: "BASE TABLE", Columns: []dbschematypes.DBColumn{
+					{Name: "id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+				}},
+			},
+			want: []string{
+				"backfill accounts.code (added NOT NULL, default gen_random_uuid()::text)",
+				"backfill users.display_name (added NOT NULL)",
+				"backfill users.status (changed to NOT NULL, default 'active')",
+				"users removes nickname and adds display_name; if this is a rename, copy the data before the old column is dropped",
+			},
+		},
+		{
+			name: "new tables need no backfill",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSources(map[string][]byte{"models.go": []byte(dataMigrationTODOSchema)})
+			c.Assert(err, qt.IsNil)
+			diff := schemadiff.CompareWithDialect(generated, &dbschematypes.DBSchema{Tables: tt.live}, platform.Postgres)
+
+			c.Assert(dataMigrationTODOs(diff, generated), qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestWithDataMigrationScaffold(t *testing.T) {
+	c := qt.New(t)
+	specs := []generatedMigrationSpec{
+		{UpSQL: "ALTER TABLE users ADD COLUMN bio TEXT;\n", DownSQL: "ALTER TABLE users DROP COLUMN bio;"},
+		{UpSQL: "CREATE INDEX idx_users_bio ON users (bio);\n", DownSQL: "DROP INDEX idx_users_bio;\n"},
+	}
+	generated, err := goschema.ParseSources(map[string][]byte{"models.go": []byte(dataMigrationTODOSchema)})
+	c.Assert(err, qt.IsNil)
+
+	withDataMigrationScaffold(specs, schemadiff.CompareWithDialect(generated, &dbschematypes.DBSchema{}, platform.Postgres), generated)
+
+	c.Assert(specs[0].UpSQL, qt.Equals, `ALTER TABLE users ADD COLUMN bio TEXT;
+
+-- DATA MIGRATION
+-- Write the data changes that go with the schema changes above, or
+-- delete this block if none are needed.
+-- TODO: no backfill was detected.
+`)
+	c.Assert(specs[0].DownSQL, qt.Equals, `-- DATA MIGRATION
+-- Reverse the data changes made by the up migration, or delete this
+-- block if none are needed.
+-- TODO: undo the up migration backfill.
+
+ALTER TABLE users DROP COLUMN bio;`)
+	c.Assert(specs[1].UpSQL, qt.Equals, "CREATE INDEX idx_users_bio ON users (bio);\n")
+}
+
+func TestInsertSQLBlock(t *testing.T) {
+	const block = "-- DATA MIGRATION\n"
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "before drop column",
+			sql: "-- Add/modify columns for table: users --\n" +
+				"ALTER TABLE \"users\" ADD COLUMN \"display_name\" TEXT;\n" +
+				"-- Remove columns from table: users --\n" +
+				"ALTER TABLE \"users\" DROP COLUMN \"nickname\";\n",
+			want: "-- Add/modify columns for table: users --\n" +
+				"ALTER TABLE \"users\" ADD COLUMN \"display_name\" TEXT;\n" +
+				"\n" + block + "\n" +
+				"-- Remove columns from table: users --\n" +
+				"ALTER TABLE \"users\" DROP COLUMN \"nickname\";\n",
+		},
+		{
+			name: "before set not null",
+			sql: "ALTER TABLE \"users\" ADD COLUMN \"status\" TEXT;\n" +
+				"ALTER TABLE \"users\" ALTER COLUMN \"status\" SET NOT NULL;\n" +
+				"DROP TABLE \"legacy\";\n",
+			want: "ALTER TABLE \"users\" ADD COLUMN \"status\" TEXT;\n" +
+				"\n" + block + "\n" +
+				"ALTER TABLE \"users\" ALTER COLUMN \"status\" SET NOT NULL;\n" +
+				"DROP TABLE \"legacy\";\n",
+		},
+		{
+			name: "before mysql modify not null",
+			sql: "ALTER TABLE `users` ADD COLUMN `status` VARCHAR(20);\n" +
+				"ALTER TABLE `users` MODIFY COLUMN `status` VARCHAR(20) NOT NULL;\n",
+			want: "ALTER TABLE `users` ADD COLUMN `status` VARCHAR(20);\n" +
+				"\n" + block + "\n" +
+				"ALTER TABLE `users` MODIFY COLUMN `status` VARCHAR(20) NOT NULL;\n",
+		},
+		{
+			name: "appended without tightening statements",
+			sql:  "ALTER TABLE \"users\" ADD COLUMN \"bio\" TEXT;\n",
+			want: "ALTER TABLE \"users\" ADD COLUMN \"bio\" TEXT;\n\n" + block,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(insertSQLBlock(tt.sql, block), qt.Equals, tt.want)
+		})
+	}
+}
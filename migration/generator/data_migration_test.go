@@ -0,0 +1,96 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const dataMigrationModel = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+
+	//migrator:schema:field name="status" type="TEXT" not_null="true" default="active"
+	Status string
+
+	//migrator:schema:field name="bio" type="TEXT"
+	Bio string
+}
+`
+
+func TestGenerateMigration_ScaffoldDataMigration(t *testing.T) {
+	tests := []struct {
+		name        string
+		scaffold    bool
+		wantUp      []string
+		wantDown    []string
+		wantMissing []string
+	}{
+		{
+			name:     "scaffold",
+			scaffold: true,
+			wantUp: []string{
+				"\n-- DATA MIGRATION\n",
+				"-- TODO: backfill users.status (added NOT NULL, default 'active')\n",
+			},
+			wantDown:    []string{"\n-- DATA MIGRATION\n", "-- TODO: undo the up migration backfill.\n"},
+			wantMissing: []string{"backfill users.bio", "rename"},
+		},
+		{
+			name:        "no scaffold",
+			wantMissing: []string{"-- DATA MIGRATION", "TODO"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+			tempDir := t.TempDir()
+			modelsDir := filepath.Join(tempDir, "models")
+			migrationsDir := filepath.Join(tempDir, "migrations")
+			c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+			c.Assert(os.MkdirAll(migrationsDir, 0o755), qt.IsNil)
+			c.Assert(os.WriteFile(filepath.Join(modelsDir, "user.go"), []byte(dataMigrationModel), 0o600), qt.IsNil)
+
+			conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(tempDir, "app.db"))
+			c.Assert(err, qt.IsNil)
+			defer dbschema.CloseAndWarn(conn)
+			_, err = conn.ExecContext(ctx, `CREATE TABLE users (id INTEGER PRIMARY KEY)`)
+			c.Assert(err, qt.IsNil)
+
+			files, err := generator.GenerateMigration(ctx, generator.GenerateMigrationOptions{
+				GoEntitiesDir:         modelsDir,
+				DBConn:                conn,
+				MigrationName:         "profile",
+				OutputDir:             migrationsDir,
+				ScaffoldDataMigration: tt.scaffold,
+			})
+			c.Assert(err, qt.IsNil)
+			c.Assert(files.Files, qt.HasLen, 1)
+			upSQL, err := os.ReadFile(files.UpFile)
+			c.Assert(err, qt.IsNil)
+			downSQL, err := os.ReadFile(files.DownFile)
+			c.Assert(err, qt.IsNil)
+
+			for _, want := range tt.wantUp {
+				c.Assert(string(upSQL), qt.Contains, want)
+			}
+			for _, want := range tt.wantDown {
+				c.Assert(string(downSQL), qt.Contains, want)
+			}
+			for _, missing := range tt.wantMissing {
+				c.Assert(string(upSQL), qt.Not(qt.Contains), missing)
+			}
+		})
+	}
+}
@@ -2,7 +2,8 @@ package generator
 
 // White-box testing required: planGeneratedMigrationSpecs is an unexported
 // orchestration helper, so exercising how the diff policy threads into the
-// planner (skipped drops, forced concurrent indexes) means calling it directly.
+// planner (skipped drops, forced concurrent indexes, safe NOT NULL, two-step
+// constraint validation) means calling it directly.
 
 import (
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform/capability"
 	dbschematypes "github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/migration/diffpolicy"
@@ -34,7 +36,7 @@ func TestPlanGeneratedMigrationSpecs_SkipDropTable(t *testing.T) {
 		postgresInfo(capability.Postgres17()),
 		100,
 		"drop_legacy",
-		DiffPolicy{SkipChangeKinds: []diffpolicy.ChangeKind{diffpolicy.DropTable}},
+		planOptions{policy: DiffPolicy{SkipChangeKinds: []diffpolicy.ChangeKind{diffpolicy.DropTable}}},
 	)
 
 	c.Assert(err, qt.IsNil)
@@ -77,7 +79,7 @@ func TestPlanGeneratedMigrationSpecs_SkipDropTableAlsoFiltersDown(t *testing.T)
 		postgresInfo(capability.Postgres17()),
 		100,
 		"drop_legacy",
-		DiffPolicy{SkipChangeKinds: []diffpolicy.ChangeKind{diffpolicy.DropTable}},
+		planOptions{policy: DiffPolicy{SkipChangeKinds: []diffpolicy.ChangeKind{diffpolicy.DropTable}}},
 	)
 
 	c.Assert(err, qt.IsNil)
@@ -116,7 +118,7 @@ func TestPlanGeneratedMigrationSpecs_SkipDropIndexKeepsRedefinition(t *testing.T
 		"rebuild_index",
 		// concurrent_index policy is OFF; only skip: [drop_index] is set, proving
 		// the heuristic alone reaches the buggy path.
-		DiffPolicy{SkipChangeKinds: []diffpolicy.ChangeKind{diffpolicy.DropIndex}},
+		planOptions{policy: DiffPolicy{SkipChangeKinds: []diffpolicy.ChangeKind{diffpolicy.DropIndex}}},
 	)
 
 	c.Assert(err, qt.IsNil)
@@ -149,7 +151,7 @@ func TestPlanGeneratedMigrationSpecs_ConcurrentIndexPolicyForcesConcurrent(t *te
 		postgresInfo(capability.Postgres17()),
 		100,
 		"add_index",
-		DiffPolicy{ConcurrentIndex: true},
+		planOptions{policy: DiffPolicy{ConcurrentIndex: true}},
 	)
 
 	c.Assert(err, qt.IsNil)
@@ -157,3 +159,106 @@ func TestPlanGeneratedMigrationSpecs_ConcurrentIndexPolicyForcesConcurrent(t *te
 	c.Assert(specs[0].NoTransaction, qt.IsTrue)
 	c.Assert(specs[0].UpSQL, qt.Contains, "CREATE INDEX CONCURRENTLY")
 }
+
+func TestPlanGeneratedMigrationSpecs_SafeNotNullEmitsBackfillSequence(t *testing.T) {
+	c := qt.New(t)
+
+	diff := &types.SchemaDiff{TablesModified: []types.TableDiff{{
+		TableName: "users",
+		ColumnsModified: []types.ColumnDiff{{
+			ColumnName: "status",
+			Changes:    map[string]string{"nullable": "true -> false"},
+		}},
+	}}}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "User", Name: "users"}},
+		Fields: []goschema.Field{{StructName: "User", Name: "status", Type: "TEXT", Default: "active"}},
+	}
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		diff,
+		generated,
+		&dbschematypes.DBSchema{},
+		postgresInfo(capability.Postgres17()),
+		100,
+		"status_not_null",
+		planOptions{safeNotNull: true},
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs, qt.HasLen, 2)
+	up := specs[0].UpSQL
+	c.Assert(up, qt.Contains, `UPDATE "users" SET "status" = 'active' WHERE "status" IS NULL;`)
+	c.Assert(up, qt.Contains, `CHECK ("status" IS NOT NULL) NOT VALID;`)
+	c.Assert(up, qt.Not(qt.Contains), "VALIDATE CONSTRAINT")
+	c.Assert(up, qt.Not(qt.Contains), "SET NOT NULL")
+	c.Assert(up, qt.Not(qt.Contains), "DO $$")
+	c.Assert(specs[0].DownSQL, qt.Contains, `ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_status_not_null";`)
+
+	validate := specs[1].UpSQL
+	c.Assert(specs[1].Version, qt.Equals, int64(101))
+	c.Assert(strings.Index(validate, "VALIDATE CONSTRAINT") < strings.Index(validate, "SET NOT NULL"), qt.IsTrue)
+	c.Assert(strings.Index(validate, "SET NOT NULL") < strings.Index(validate, `DROP CONSTRAINT "users_status_not_null";`), qt.IsTrue)
+	c.Assert(specs[1].DownSQL, qt.Contains, `ALTER TABLE "users" ADD CONSTRAINT "users_status_not_null" CHECK ("status" IS NOT NULL) NOT VALID;`)
+}
+
+func checkConstraintAddition() (*types.SchemaDiff, *goschema.Database) {
+	diff := &types.SchemaDiff{ConstraintsAdded: []string{"positive_price"}}
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Product", Name: "products"}},
+		Constraints: []goschema.Constraint{{
+			StructName:      "Product",
+			Name:            "positive_price",
+			Type:            "CHECK",
+			Table:           "products",
+			CheckExpression: "price > 0",
+		}},
+	}
+	return diff, generated
+}
+
+func TestPlanGeneratedMigrationSpecs_TwoStepValidationAppendsValidate(t *testing.T) {
+	c := qt.New(t)
+	diff, generated := checkConstraintAddition()
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		diff,
+		generated,
+		&dbschematypes.DBSchema{},
+		postgresInfo(capability.Postgres17()),
+		100,
+		"positive_price",
+		planOptions{twoStepValidation: true},
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs, qt.HasLen, 1)
+	up := specs[0].UpSQL
+	c.Assert(up, qt.Contains, `CHECK (price > 0) NOT VALID;`)
+	c.Assert(strings.Index(up, "NOT VALID") < strings.Index(up, `VALIDATE CONSTRAINT "positive_price";`), qt.IsTrue)
+	c.Assert(specs[0].DownSQL, qt.Contains, `DROP CONSTRAINT`)
+}
+
+func TestPlanGeneratedMigrationSpecs_SplitValidationGeneratesSecondMigration(t *testing.T) {
+	c := qt.New(t)
+	diff, generated := checkConstraintAddition()
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		diff,
+		generated,
+		&dbschematypes.DBSchema{},
+		postgresInfo(capability.Postgres17()),
+		100,
+		"positive_price",
+		planOptions{twoStepValidation: true, splitValidation: true},
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs, qt.HasLen, 2)
+	c.Assert(specs[0].UpSQL, qt.Contains, `CHECK (price > 0) NOT VALID;`)
+	c.Assert(specs[0].UpSQL, qt.Not(qt.Contains), "VALIDATE CONSTRAINT")
+	c.Assert(specs[1].Version, qt.Equals, int64(101))
+	c.Assert(specs[1].Name, qt.Equals, "positive_price_validate_constraints")
+	c.Assert(specs[1].UpSQL, qt.Contains, `ALTER TABLE "products" VALIDATE CONSTRAINT "positive_price";`)
+	c.Assert(specs[1].DownSQL, qt.Contains, "No rollback operations needed")
+}
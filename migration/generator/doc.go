@@ -16,6 +16,7 @@
 //   - Dynamic migration generation from schema differences
 //   - Automatic up and down migration file creation
 //   - Empty migration skeleton creation for manual SQL
+//   - Go files embedding migration SQL for single-binary deployments
 //   - Timestamped migration versioning
 //   - Dialect-specific SQL generation
 //   - Proper dependency ordering and safety checks
@@ -38,6 +39,7 @@
 //
 //   - GenerateMigrationOptions: Configuration for migration generation
 //   - EmptyMigrationOptions: Configuration for empty migration skeleton creation
+//   - GoMigrationOptions: Configuration for Go files that embed migration SQL
 //   - MigrationFiles: Information about generated migration files
 //
 // # Usage Example
@@ -0,0 +1,93 @@
+//go:build integration
+
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func TestEnumDefaultGenerateRoundTrip_Integration(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := enumDefaultPostgresConnection(c)
+
+	root := c.TempDir()
+	entitiesDir := filepath.Join(root, "entities")
+	migrationsDir := filepath.Join(root, "migrations")
+	c.Assert(os.MkdirAll(entitiesDir, 0755), qt.IsNil)
+	c.Assert(os.MkdirAll(migrationsDir, 0755), qt.IsNil)
+
+	opts := generator.GenerateMigrationOptions{
+		GoEntitiesDir: entitiesDir,
+		DBConn:        conn,
+		MigrationName: "enum_default",
+		OutputDir:     migrationsDir,
+	}
+	for _, defaultValue := range []string{"active", "suspended"} {
+		c.Assert(os.WriteFile(filepath.Join(entitiesDir, "account.go"), []byte(enumDefaultModel(defaultValue)), 0600), qt.IsNil)
+
+		files, err := generator.GenerateMigration(ctx, opts)
+		c.Assert(err, qt.IsNil)
+		c.Assert(files, qt.IsNotNil)
+		upSQL, err := os.ReadFile(files.UpFile)
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(upSQL), qt.Contains, "DEFAULT '"+defaultValue+"'::enum_enumdefaultaccount_status")
+
+		mig, err := migrator.NewFSMigrator(conn, os.DirFS(migrationsDir))
+		c.Assert(err, qt.IsNil)
+		c.Assert(mig.MigrateUp(ctx), qt.IsNil)
+
+		files, err = generator.GenerateMigration(ctx, opts)
+		c.Assert(err, qt.IsNil)
+		c.Assert(files, qt.IsNil, qt.Commentf("regenerating after applying the %s default must be a no-op", defaultValue))
+	}
+}
+
+// enumDefaultPostgresConnection connects to POSTGRES_URL, skipping the test
+// when it is unset or unreachable, and drops the test objects around the test.
+func enumDefaultPostgresConnection(c *qt.C) *dbschema.DatabaseConnection {
+	c.Helper()
+	dbURL := os.Getenv("POSTGRES_URL")
+	if dbURL == "" {
+		c.Skip("skipping PostgreSQL enum default integration: POSTGRES_URL not set")
+	}
+	conn, err := dbschema.ConnectToDatabase(context.Background(), dbURL)
+	if err != nil {
+		c.Skipf("skipping PostgreSQL enum default integration: cannot connect: %v", err)
+	}
+	cleanup := func() {
+		_, _ = conn.Exec("DROP TABLE IF EXISTS ptah_enum_default_accounts CASCADE")
+		_, _ = conn.Exec("DROP TYPE IF EXISTS enum_enumdefaultaccount_status")
+		_, _ = conn.Exec("DROP TABLE IF EXISTS schema_migrations")
+	}
+	cleanup()
+	c.Cleanup(func() {
+		cleanup()
+		_ = conn.Close()
+	})
+	return conn
+}
+
+func enumDefaultModel(defaultValue string) string {
+	return strings.ReplaceAll(`package entities
+
+//migrator:schema:table name="ptah_enum_default_accounts"
+type EnumDefaultAccount struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="status" type="ENUM" enum="active,suspended" not_null="true" default="DEFAULT_VALUE"
+	Status string
+}
+`, "DEFAULT_VALUE", defaultValue)
+}
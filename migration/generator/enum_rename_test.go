@@ -0,0 +1,94 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const enumRenameModel = `package models
+
+//migrator:schema:enum name="account_status" values="active,inactive" rename_from="user_status"
+type SchemaObjects struct{}
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="status" type="account_status" not_null="true" default="active"
+	Status string
+}
+`
+
+func TestGenerateMigration_EnumRenameKeepsColumns(t *testing.T) {
+	c := qt.New(t)
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "user.go"), []byte(enumRenameModel), 0o600), qt.IsNil)
+	fake := dbschematest.NewFakeConnection(t, platform.Postgres, &types.DBSchema{
+		Tables: []types.DBTable{{Name: "users", Type: "BASE TABLE", Columns: []types.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "status", DataType: "USER-DEFINED", UDTName: "user_status", IsNullable: "NO", ColumnDefault: new("'active'::user_status")},
+		}}},
+		Enums: []types.DBEnum{{Name: "user_status", Values: []string{"active", "inactive"}}},
+	})
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        fake.DatabaseConnection,
+		MigrationName: "rename_user_status",
+		OutputDir:     filepath.Join(tempDir, "migrations"),
+	})
+
+	c.Assert(err, qt.IsNil)
+	upSQL, err := os.ReadFile(files.UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TYPE "user_status" RENAME TO "account_status";`)
+	c.Assert(string(upSQL), qt.Not(qt.Contains), "DROP TYPE")
+	c.Assert(string(upSQL), qt.Not(qt.Contains), "ALTER COLUMN")
+	downSQL, err := os.ReadFile(files.DownFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(downSQL), qt.Contains, `ALTER TYPE "account_status" RENAME TO "user_status";`)
+	c.Assert(string(downSQL), qt.Not(qt.Contains), "CREATE TYPE")
+	c.Assert(string(downSQL), qt.Not(qt.Contains), "ALTER COLUMN")
+}
+
+func TestGenerateMigrationDetailed_EnumRenameRemovesValuesWithoutDrop(t *testing.T) {
+	c := qt.New(t)
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "user.go"), []byte(enumRenameModel), 0o600), qt.IsNil)
+	fake := dbschematest.NewFakeConnection(t, platform.Postgres, &types.DBSchema{
+		Tables: []types.DBTable{{Name: "users", Type: "BASE TABLE", Columns: []types.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "status", DataType: "USER-DEFINED", UDTName: "user_status", IsNullable: "NO", ColumnDefault: new("'active'::user_status")},
+		}}},
+		Enums: []types.DBEnum{{Name: "user_status", Values: []string{"active", "inactive", "banned"}}},
+	})
+
+	result, err := generator.GenerateMigrationDetailed(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        fake.DatabaseConnection,
+		MigrationName: "rename_user_status",
+		OutputDir:     filepath.Join(tempDir, "migrations"),
+	})
+
+	c.Assert(err, qt.IsNil)
+	upSQL, err := os.ReadFile(result.Files.UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TYPE "user_status" RENAME TO "account_status";`)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TABLE "users" ALTER COLUMN "status" TYPE "account_status" USING "status"::text::"account_status";`)
+	c.Assert(string(upSQL), qt.Not(qt.Contains), "CASCADE")
+	c.Assert(result.Destructive, qt.IsTrue)
+}
@@ -0,0 +1,42 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+func TestGenerateMigration_FakeConnection(t *testing.T) {
+	c := qt.New(t)
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "setting.go"), []byte(sqliteTableDropModel), 0o600), qt.IsNil)
+	fake := dbschematest.NewFakeConnection(t, platform.Postgres, &types.DBSchema{
+		Tables: []types.DBTable{{Name: "legacy", Type: "TABLE", Columns: []types.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsPrimaryKey: true},
+		}}},
+	})
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        fake,
+		MigrationName: "replace_legacy",
+		OutputDir:     filepath.Join(tempDir, "migrations"),
+	})
+
+	c.Assert(err, qt.IsNil)
+	upSQL, err := os.ReadFile(files.UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, `CREATE TABLE "settings"`)
+	c.Assert(string(upSQL), qt.Contains, `DROP TABLE IF EXISTS "legacy" CASCADE;`)
+	c.Assert(fake.ExecutedSQL(), qt.HasLen, 0)
+}
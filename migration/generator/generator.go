@@ -1,11 +1,13 @@
 package generator
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -18,6 +20,7 @@ import (
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform"
 	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/core/ptaherr"
 	"github.com/stokaro/ptah/core/renderer"
 	"github.com/stokaro/ptah/core/sqlutil"
 	"github.com/stokaro/ptah/dbschema"
@@ -28,6 +31,7 @@ import (
 	"github.com/stokaro/ptah/internal/migratesum"
 	"github.com/stokaro/ptah/internal/pathguard"
 	"github.com/stokaro/ptah/migration/diffpolicy"
+	"github.com/stokaro/ptah/migration/internal/typechange"
 	"github.com/stokaro/ptah/migration/migrator"
 	"github.com/stokaro/ptah/migration/planner"
 	"github.com/stokaro/ptah/migration/safety"
@@ -43,8 +47,10 @@ type GenerateMigrationOptions struct {
 	GoEntitiesFS fs.FS
 	// DatabaseURL is the connection string for the database
 	DatabaseURL string
-	// DBConn is the database connection (optional, if not provided, a new connection will be created)
-	DBConn *dbschema.DatabaseConnection
+	// DBConn is the database connection (optional, if not provided, a new connection will be created).
+	// Only its metadata and schema reader are used, so a *dbschema.DatabaseConnection or a test fake
+	// both work. Leave it nil, not a nil *dbschema.DatabaseConnection, to connect through DatabaseURL.
+	DBConn dbschema.SchemaReader
 	// MigrationName is the name for the migration (optional, defaults to "migration")
 	MigrationName string
 	// OutputDir is the directory where migration files will be saved (always real filesystem)
@@ -76,7 +82,187 @@ type GenerateMigrationOptions struct {
 	// plan (with a comment in its place), so it never trips the CheckDestructive
 	// gate.
 	DiffPolicy DiffPolicy
-}
+	// SafeNotNull plans nullable -> NOT NULL column changes as an explicit
+	// sequence: existing NULLs are backfilled from the column default, then on
+	// targets that support it a NOT VALID check is added. Validating the check,
+	// SET NOT NULL and dropping the check go into a follow-up
+	// _validate_constraints migration, so the validation scan does not run
+	// under the lock taken by ADD CONSTRAINT. Columns without a default get a
+	// warning comment that a manual backfill is required. Currently honored by
+	// the PostgreSQL-family planner.
+	SafeNotNull bool
+	// SafeTypeChange plans column type changes classified as incompatible,
+	// such as TEXT to INTEGER, as expand/migrate/contract: a temporary column
+	// of the new type is added, filled from the field's type_cast expression
+	// (a plain cast when unset), and swapped in for the old column. The down
+	// migration plans the reverse change the same way: another swap when it
+	// is incompatible too, an in-place ALTER COLUMN otherwise. Widening and
+	// narrowing changes keep the in-place ALTER COLUMN. Currently honored by
+	// the PostgreSQL-family planner.
+	SafeTypeChange bool
+	// SafeVolatileDefault plans an added column whose default calls a
+	// volatile function, such as gen_random_uuid() or clock_timestamp(), as
+	// ADD COLUMN without the default, SET DEFAULT, a backfill UPDATE of the
+	// existing rows, and SET NOT NULL when the column is NOT NULL, instead of
+	// an ADD COLUMN ... DEFAULT that rewrites the table. The backfill carries
+	// a warning that large tables need it run in batches. Constant and stable
+	// defaults such as now() keep the single ADD COLUMN. Currently honored by
+	// the PostgreSQL-family planner.
+	SafeVolatileDefault bool
+	// TwoStepConstraintValidation adds CHECK and FOREIGN KEY constraints on
+	// existing tables as ADD CONSTRAINT ... NOT VALID and validates them with
+	// ALTER TABLE ... VALIDATE CONSTRAINT at the end of the up migration, so the
+	// table is not locked while existing rows are checked. Constraints declared
+	// with not_valid="true" get this treatment even when the option is off.
+	// Currently honored by the PostgreSQL-family planner on targets with the
+	// not_valid_constraints capability.
+	TwoStepConstraintValidation bool
+	// SplitValidation moves the VALIDATE CONSTRAINT statements produced by
+	// two-step validation into a second generated migration that runs after
+	// the one adding the constraints.
+	SplitValidation bool
+	// StatementFilter, when set, is called for every planned up and down
+	// operation before rendering and may rewrite or drop it. See
+	// StatementFilter for ordering guarantees.
+	StatementFilter StatementFilter
+	// VersionStrategy selects how generated migrations are versioned. Empty
+	// uses VersionStrategyTimestamp.
+	VersionStrategy VersionStrategy
+	// Version is the migration version to use under VersionStrategyExplicit.
+	// It must be a positive integer and is rejected by the other strategies.
+	Version string
+	// SplitStrategy selects how one diff is divided into migration files.
+	// Empty uses SplitStrategySingleFile. Every generated file is listed in
+	// MigrationFiles.Files in apply order.
+	SplitStrategy SplitStrategy
+	// Clock returns the generation time used for timestamp versions and the
+	// "Generated on" header. Nil uses time.Now; tests inject a fixed clock to
+	// get reproducible files.
+	Clock func() time.Time
+	// TargetDialect plans and renders the migration for another dialect than
+	// the connected database, e.g. MySQL SQL generated against a MariaDB CI
+	// database. The current schema is still read from the connection and
+	// compared with its dialect, so it may not reflect the target exactly; a
+	// warning is logged whenever the two differ. ShadowDatabaseURL, when set,
+	// must point at a database of the target dialect. Empty uses the
+	// connection's dialect.
+	TargetDialect string
+	// StrictDialect fails generation with a *SkippedFeaturesError when the
+	// target dialect cannot express part of the schema (functions, RLS
+	// policies and enablement, extensions, roles, grants) instead of leaving
+	// those objects out. Without it the skipped objects are logged as
+	// warnings and listed in MigrationFiles.SkippedFeatures.
+	StrictDialect bool
+	// Strict fails generation with an *AnnotationErrors listing every
+	// annotation problem in the Go entities (unknown directives, misspelled
+	// //migrator: prefixes, unknown attributes, invalid values) instead of
+	// ignoring the lines the parser does not understand. See
+	// goschema.ParseFSStrict.
+	Strict bool
+	// CustomStatementGenerators turn the column changes recorded by
+	// CompareOptions.CustomComparators into SQL in the up migration. The down
+	// migration calls the same generators with Before and After swapped.
+	CustomStatementGenerators []planner.CustomStatementGenerator
+	// AllowEmptySchema permits generating from Go entities that declare no
+	// tables, or fewer than MinExpectedTables. Without it such a parse fails
+	// with ErrTooFewTables, because a mistyped GoEntitiesDir otherwise yields
+	// an empty schema and a migration that drops every table.
+	AllowEmptySchema bool
+	// MinExpectedTables is the fewest tables the Go entities must declare.
+	// Zero requires at least one table.
+	MinExpectedTables int
+	// MaxDropRatio, when positive, is the largest fraction (0 to 1) of the
+	// database tables a migration may drop. Exceeding it fails with
+	// ErrTooManyTableDrops unless AllowMassDrop is set.
+	MaxDropRatio float64
+	// AllowMassDrop writes migrations that exceed MaxDropRatio, after logging
+	// a warning.
+	AllowMassDrop bool
+	// MySQLOnlineDDL appends ALGORITHM=INPLACE, LOCK=NONE to the ADD COLUMN
+	// and CREATE INDEX statements of MySQL and MariaDB migrations that the
+	// server can run without blocking writes. Operations that need a table
+	// copy or a shared lock, such as AUTO_INCREMENT, STORED generated or
+	// primary key columns and FULLTEXT or SPATIAL indexes, are emitted without
+	// the hint. Other dialects ignore the option.
+	MySQLOnlineDDL bool
+	// CascadeCyclicTableDrops lets a migration drop tables whose database
+	// foreign keys reference each other, which no drop order satisfies.
+	// Removed tables are otherwise dropped referencing tables first. On
+	// SQLite, which has no DROP TABLE ... CASCADE, the drops run with foreign
+	// key checks deferred to the end of the transaction; without the option
+	// such a migration fails to generate. PostgreSQL drops removed tables with
+	// CASCADE and MySQL-family migrations drop their foreign keys first, so
+	// they ignore it.
+	CascadeCyclicTableDrops bool
+	// SessionSettings are written as SET statements at the top of every
+	// generated transactional up and down migration, so generated DDL does
+	// not queue behind long transactions, for example:
+	//
+	//	SessionSettings: map[string]string{
+	//		"lock_timeout":      "5s",
+	//		"statement_timeout": "10min",
+	//	}
+	//
+	// PostgreSQL gets SET LOCAL lock_timeout = '5s', which ends with the
+	// migration transaction; MySQL and MariaDB get SET SESSION with the names
+	// and values translated as described by migrator.SessionSettingStatements,
+	// and the previous values are restored at the end of the file, so a
+	// pooled connection does not keep them. no_transaction files get no
+	// settings, since their statements may run on different connections.
+	// Other dialects reject the option.
+	// Pair it with migrator.Migrator.WithSessionSettings to apply the same
+	// settings to registered Go migrations.
+	SessionSettings map[string]string
+	// ScaffoldDataMigration adds a "-- DATA MIGRATION" block of comments to
+	// the first generated up and down migration, where the backfills of an
+	// expand/migrate/contract change are written by hand. The block follows
+	// the additive statements and precedes the first DROP TABLE, DROP COLUMN,
+	// or NOT NULL tightening. The up block lists
+	// a TODO for every column added or changed to NOT NULL on an existing
+	// table, and for tables that add and remove columns in the same diff,
+	// which may be a rename. No data statements are generated.
+	ScaffoldDataMigration bool
+	// GuardedRollback starts every generated down migration with a
+	// precondition for each object it drops, so a rollback against a schema
+	// where one is missing stops before changing anything. PostgreSQL raises
+	// an exception from a DO block and guards every kind the up migration
+	// adds: tables, columns, indexes, views, materialized views, sequences,
+	// types, constraints, triggers, functions, policies, extensions, and
+	// roles. Other dialects select the object with LIMIT 0 (TOP 0 on SQL
+	// Server) and fail with the server's own error, which only works for
+	// tables and columns, so the other kinds are not guarded there.
+	GuardedRollback bool
+	// PtahVersion is written to the ptah:version header line of every
+	// generated file. Empty uses the ptah module version recorded in the
+	// binary's build information, or "dev" when it is unknown. See
+	// ReadMigrationHeader.
+	PtahVersion string
+	// SourceSHA is the git commit of the Go entities, written to the
+	// ptah:source_sha header line when set.
+	SourceSHA string
+	// ConnectRetries retries connecting to DatabaseURL and ShadowDatabaseURL
+	// this many more times when the server refuses the connection, has too
+	// many connections, or is still starting up. The context passed to
+	// GenerateMigration bounds all attempts. See dbschema.ConnectOptions.
+	ConnectRetries int
+	// ConnectRetryBackoff is the wait before the first connect retry. It
+	// doubles after every attempt. Zero uses 100ms.
+	ConnectRetryBackoff time.Duration
+}
+
+// connectOptions returns the dbschema connect options for opts.
+func connectOptions(opts GenerateMigrationOptions) dbschema.ConnectOptions {
+	return dbschema.ConnectOptions{Retries: opts.ConnectRetries, RetryBackoff: opts.ConnectRetryBackoff}
+}
+
+// ErrTooFewTables is returned by GenerateMigration when the Go entities
+// declare fewer tables than expected and AllowEmptySchema is not set.
+var ErrTooFewTables = errors.New("go entities declare too few tables")
+
+// ErrTooManyTableDrops is returned by GenerateMigration when the migration
+// would drop more than MaxDropRatio of the database tables and AllowMassDrop
+// is not set.
+var ErrTooManyTableDrops = errors.New("migration drops too many tables")
 
 // DiffPolicy is the generator-level view of the project diff policy.
 type DiffPolicy struct {
@@ -91,11 +277,13 @@ type DiffPolicy struct {
 
 // MigrationFilePair represents one generated up/down migration file pair.
 type MigrationFilePair struct {
-	UpFile        string // Path to the up migration file
-	DownFile      string // Path to the down migration file
-	ReportFile    string // Path to the safety report file, when requested
-	Version       int64  // Migration version (timestamp)
-	NoTransaction bool   // Whether the pair is marked with +ptah no_transaction
+	UpFile         string // Path to the up migration file
+	DownFile       string // Path to the down migration file
+	ReportFile     string // Path to the safety report file, when requested
+	Version        int64  // Migration version (timestamp)
+	NoTransaction  bool   // Whether the pair is marked with +ptah no_transaction
+	Statements     int    // Number of SQL statements in the up file
+	DownStatements int    // Number of SQL statements in the down file
 }
 
 // MigrationFiles represents the generated migration files.
@@ -105,6 +293,55 @@ type MigrationFiles struct {
 	ReportFile string              // Path to the first safety report file, when requested
 	Version    int64               // First migration version (timestamp)
 	Files      []MigrationFilePair // All generated migration file pairs, in apply order
+	// SkippedFeatures lists schema objects left out of the migration because
+	// the target dialect cannot express them.
+	SkippedFeatures []planner.SkippedFeature
+	// ManualChanges lists column changes written as MANUAL comments because
+	// no ALTER statement can apply them; they must be migrated by hand.
+	ManualChanges []planner.ManualChange
+}
+
+// SkippedFeaturesError reports, under StrictDialect, the schema objects the
+// target dialect cannot express. It unwraps to ptaherr.ErrUnsupportedFeature.
+type SkippedFeaturesError struct {
+	Dialect  string
+	Features []planner.SkippedFeature
+}
+
+func (e *SkippedFeaturesError) Error() string {
+	features := make([]string, 0, len(e.Features))
+	for _, feature := range e.Features {
+		features = append(features, string(feature.Kind)+" "+feature.Object)
+	}
+	return fmt.Sprintf("%s cannot express %d schema object(s): %s", e.Dialect, len(e.Features), strings.Join(features, ", "))
+}
+
+func (e *SkippedFeaturesError) Unwrap() error {
+	return ptaherr.ErrUnsupportedFeature
+}
+
+// AnnotationErrors reports, under Strict, every annotation problem found in
+// the Go entities. It unwraps to the individual goschema.AnnotationError
+// values.
+type AnnotationErrors struct {
+	Errors []goschema.AnnotationError
+}
+
+func (e *AnnotationErrors) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, fmt.Sprintf("%d annotation error(s) in Go entities:", len(e.Errors)))
+	for _, annotationError := range e.Errors {
+		lines = append(lines, "  "+annotationError.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *AnnotationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, annotationError := range e.Errors {
+		errs = append(errs, annotationError)
+	}
+	return errs
 }
 
 // EmptyMigrationOptions contains options for skeleton migration creation.
@@ -278,7 +515,23 @@ func emptyMigrationSQL(name, generatedAt, direction string) string {
 // indefinitely). The schema-reading and migration-writing work below does not
 // yet propagate the context; future work may thread it through there too.
 // When opts.DBConn is supplied the context is currently unused.
+//
+// It returns nil files when the schema has no changes. Use
+// GenerateMigrationDetailed for statement counts, warnings, and the diff.
 func GenerateMigration(ctx context.Context, opts GenerateMigrationOptions) (*MigrationFiles, error) {
+	result, err := GenerateMigrationDetailed(ctx, opts)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.Files, nil
+}
+
+// GenerateMigrationDetailed generates migration files like GenerateMigration
+// and also reports how many statements were written, the warnings embedded
+// in them, and the schema diff they were planned from, so automation does
+// not have to parse the files. When the schema has no changes, the result
+// has nil Files and the empty diff.
+func GenerateMigrationDetailed(ctx context.Context, opts GenerateMigrationOptions) (*MigrationResult, error) {
 	opts, err := normalizeGenerateMigrationOptions(opts)
 	if err != nil {
 		return nil, err
@@ -304,69 +557,117 @@ func GenerateMigration(ctx context.Context, opts GenerateMigrationOptions) (*Mig
 	}
 
 	// 1. Parse Go entities to get desired schema
-	generated, err := goschema.ParseFS(opts.GoEntitiesFS, entitiesDir)
+	generated, err := parseGoEntities(opts, entitiesDir)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing Go entities: %w", err)
+		return nil, err
+	}
+	if err := checkExpectedTables(opts, entitiesDir, generated); err != nil {
+		return nil, err
 	}
 
 	// 2. Connect to database and read current schema
-	var conn *dbschema.DatabaseConnection
+	var conn dbschema.SchemaReader
 
 	if opts.DBConn != nil {
 		conn = opts.DBConn
 	} else {
-		conn, err = dbschema.ConnectToDatabase(ctx, opts.DatabaseURL)
+		dbConn, err := dbschema.ConnectToDatabaseWithOptions(ctx, opts.DatabaseURL, connectOptions(opts))
 		if err != nil {
 			return nil, fmt.Errorf("error connecting to database: %w", err)
 		}
-		defer dbschema.CloseAndWarn(conn)
+		defer dbschema.CloseAndWarn(dbConn)
+		conn = dbConn
 	}
 
-	dbSchema, err := dbschema.ReadSchemaWithSchemas(conn, opts.Schemas)
+	// Thread the connection dialect into the compare options so dialect-specific
+	// normalization (e.g. MySQL/MariaDB RESTRICT == NO ACTION on foreign keys)
+	// is applied; without it MariaDB would loop drop+add on an unchanged FK.
+	compareOpts := withDialect(opts.CompareOptions, conn.Info().Dialect)
+
+	dbSchema, err := dbschema.ReadSchemaWithOptions(conn, dbschema.ReadOptions{
+		Schemas:                opts.Schemas,
+		IncludeSystemRelations: compareOpts.IncludeSystemRelations,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error reading database schema: %w", err)
 	}
 
 	// 3. Calculate the diff between desired and current schema.
-	// Thread the connection dialect into the compare options so dialect-specific
-	// normalization (e.g. MySQL/MariaDB RESTRICT == NO ACTION on foreign keys)
-	// is applied; without it MariaDB would loop drop+add on an unchanged FK.
-	compareOpts := withDialect(opts.CompareOptions, conn.Info().Dialect)
 	diff := schemadiff.CompareWithOptions(generated, dbSchema, compareOpts)
+	if compareOpts.SkipRolePasswords {
+		generated = withoutRolePasswords(generated)
+	}
 
 	// Check if there are any changes
 	if !diff.HasChanges() {
 		// No changes detected - this is a successful no-op operation
-		return nil, nil
+		return &MigrationResult{Diff: diff}, nil
+	}
+	if err := checkTableDropRatio(opts, diff, dbSchema); err != nil {
+		return nil, err
 	}
 
-	// 4. Generate migration version (timestamp)
-	version := migrator.GetNextMigrationVersion()
-	version = nextAvailableMigrationVersion(opts.OutputDir, version, opts.MigrationName)
-	slog.Debug("Generated migration version", "version", version)
+	splitStrategy, err := ParseSplitStrategy(string(opts.SplitStrategy))
+	if err != nil {
+		return nil, err
+	}
 
-	info := conn.Info()
-	specs, assessments, err := planGeneratedMigrationSpecs(diff, generated, dbSchema, info, version, opts.MigrationName, opts.DiffPolicy)
+	// 4. Generate migration version
+	now := migrationClock(opts)()
+	version, err := resolveMigrationVersion(opts, now)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Generated migration version", "version", version, "strategy", opts.VersionStrategy)
+
+	info := withTargetDialect(conn.Info(), opts.TargetDialect)
+	skipped := planner.SkippedFeatures(diff, info.Dialect, info.Capabilities)
+	if err := checkSkippedFeatures(opts, info.Dialect, skipped); err != nil {
+		return nil, err
+	}
+	manual := planner.ManualChanges(diff, info.Dialect, info.Capabilities)
+	for _, change := range manual {
+		slog.Warn("Column change needs a manual migration", "table", change.Table, "column", change.Column, "change", change.Change)
+	}
+	plan := newPlanOptions(opts, splitStrategy, now)
+	specs, assessments, err := planGeneratedMigrationSpecs(diff, generated, dbSchema, info, version, opts.MigrationName, plan)
 	if err != nil {
 		return nil, err
 	}
 	if len(specs) == 0 {
-		return nil, nil
+		return &MigrationResult{Diff: diff}, nil
+	}
+	if opts.ScaffoldDataMigration {
+		withDataMigrationScaffold(specs, diff, generated)
+	}
+	if err := withSessionSettings(specs, info.Dialect, opts.SessionSettings); err != nil {
+		return nil, err
+	}
+	withHeader(specs, generatedHeader(opts, info.Dialect))
+	if err := ensureMigrationVersionsAvailable(opts.OutputDir, specs); err != nil {
+		return nil, err
 	}
 	if err := checkDestructiveAllowed(opts, assessments); err != nil {
 		return nil, err
 	}
 
 	if opts.ShadowDatabaseURL != "" {
+		// A retargeted plan only knows the target's default capabilities, so
+		// the shadow database is checked for the dialect alone.
+		shadowCaps := info.Capabilities
+		if info.Dialect != conn.Info().Dialect {
+			shadowCaps = nil
+		}
 		if err := verifyShadowMigration(ctx, shadowMigrationOptions{
 			DatabaseURL:   opts.ShadowDatabaseURL,
 			MigrationsDir: opts.OutputDir,
 			Dialect:       info.Dialect,
-			Capabilities:  info.Capabilities,
+			Capabilities:  shadowCaps,
 			Candidates:    shadowCandidatesFromSpecs(specs),
 			Generated:     generated,
 			CompareOpts:   compareOpts,
 			Schemas:       opts.Schemas,
+			ConnectOpts:   connectOptions(opts),
 		}); err != nil {
 			return nil, err
 		}
@@ -377,8 +678,10 @@ func GenerateMigration(ctx context.Context, opts GenerateMigrationOptions) (*Mig
 	if err != nil {
 		return nil, fmt.Errorf("error creating migration files: %w", err)
 	}
+	files.SkippedFeatures = skipped
+	files.ManualChanges = manual
 
-	return files, nil
+	return newMigrationResult(files, specs, diff, skipped, manual), nil
 }
 
 func normalizeGenerateMigrationOptions(opts GenerateMigrationOptions) (GenerateMigrationOptions, error) {
@@ -390,9 +693,34 @@ func normalizeGenerateMigrationOptions(opts GenerateMigrationOptions) (GenerateM
 		return opts, fmt.Errorf("error validating output directory: %w", err)
 	}
 	opts.OutputDir = outputDir
+	if opts.TargetDialect != "" {
+		target := platform.NormalizeDialect(opts.TargetDialect)
+		if target == "" {
+			return opts, fmt.Errorf("unknown target dialect %q", opts.TargetDialect)
+		}
+		opts.TargetDialect = target
+	}
 	return opts, nil
 }
 
+// withTargetDialect retargets info at the normalized dialect target with that
+// dialect's default capabilities. An empty target, or one matching the
+// connection, leaves info unchanged.
+func withTargetDialect(info dbschematypes.DBInfo, target string) dbschematypes.DBInfo {
+	if target == "" || target == platform.NormalizeDialect(info.Dialect) {
+		return info
+	}
+	slog.Warn("Generating migration SQL for a different dialect than the connected database; "+
+		"the current schema was read from the connection and may not match the target exactly",
+		"target_dialect", target,
+		"connection_dialect", info.Dialect,
+	)
+	info.Dialect = target
+	info.Version = ""
+	info.Capabilities = capability.ForDialect(target)
+	return info
+}
+
 // withDialect returns a copy of opts with the Dialect set, allocating a default
 // options value when opts is nil. An explicit Dialect already present on opts is
 // preserved. The comparator consults Dialect only for dialect-specific
@@ -408,6 +736,89 @@ func withDialect(opts *config.CompareOptions, dialect string) *config.CompareOpt
 	return &clone
 }
 
+// withoutRolePasswords returns a copy of generated whose roles carry no
+// password, so CREATE ROLE is planned without a PASSWORD clause.
+func withoutRolePasswords(generated *goschema.Database) *goschema.Database {
+	clone := *generated
+	clone.Roles = slices.Clone(generated.Roles)
+	for i := range clone.Roles {
+		clone.Roles[i].Password = ""
+		clone.Roles[i].PasswordEnv = ""
+	}
+	return &clone
+}
+
+// parseGoEntities parses the Go entities, reporting every annotation problem
+// at once under Strict.
+func parseGoEntities(opts GenerateMigrationOptions, entitiesDir string) (*goschema.Database, error) {
+	if !opts.Strict {
+		generated, err := goschema.ParseFS(opts.GoEntitiesFS, entitiesDir)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Go entities: %w", err)
+		}
+		return generated, nil
+	}
+	generated, annotationErrors, err := goschema.ParseFSStrict(opts.GoEntitiesFS, entitiesDir)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Go entities: %w", err)
+	}
+	if len(annotationErrors) > 0 {
+		return nil, &AnnotationErrors{Errors: annotationErrors}
+	}
+	return generated, nil
+}
+
+// checkSkippedFeatures fails under StrictDialect when planning would leave
+// schema objects out, and otherwise logs one warning per skipped object.
+func checkSkippedFeatures(opts GenerateMigrationOptions, dialect string, skipped []planner.SkippedFeature) error {
+	if len(skipped) == 0 {
+		return nil
+	}
+	if opts.StrictDialect {
+		return &SkippedFeaturesError{Dialect: dialect, Features: skipped}
+	}
+	for _, feature := range skipped {
+		slog.Warn("Skipping schema object the target dialect cannot express",
+			"kind", feature.Kind,
+			"object", feature.Object,
+			"capability", feature.Capability,
+		)
+	}
+	return nil
+}
+
+func checkExpectedTables(opts GenerateMigrationOptions, entitiesDir string, generated *goschema.Database) error {
+	if opts.AllowEmptySchema {
+		return nil
+	}
+	minTables := max(opts.MinExpectedTables, 1)
+	if len(generated.Tables) >= minTables {
+		return nil
+	}
+	return fmt.Errorf("%w: %q declares %d table(s), expected at least %d; check the entities directory or set AllowEmptySchema",
+		ErrTooFewTables, entitiesDir, len(generated.Tables), minTables)
+}
+
+func checkTableDropRatio(opts GenerateMigrationOptions, diff *types.SchemaDiff, dbSchema *dbschematypes.DBSchema) error {
+	if opts.MaxDropRatio <= 0 || len(diff.TablesRemoved) == 0 || len(dbSchema.Tables) == 0 {
+		return nil
+	}
+	ratio := float64(len(diff.TablesRemoved)) / float64(len(dbSchema.Tables))
+	if ratio <= opts.MaxDropRatio {
+		return nil
+	}
+	slog.Warn("Migration drops a large share of the database tables",
+		"dropped", len(diff.TablesRemoved),
+		"tables", len(dbSchema.Tables),
+		"max_drop_ratio", opts.MaxDropRatio,
+	)
+	if opts.AllowMassDrop {
+		return nil
+	}
+	return fmt.Errorf("%w: %d of %d tables (%.0f%%) exceed MaxDropRatio %.0f%%; set AllowMassDrop to write the migration",
+		ErrTooManyTableDrops, len(diff.TablesRemoved), len(dbSchema.Tables), ratio*100, opts.MaxDropRatio*100)
+}
+
 func checkDestructiveAllowed(opts GenerateMigrationOptions, assessments []safety.StatementAssessment) error {
 	if opts.CheckDestructive && safety.HasDestructiveAssessment(assessments) && !opts.AllowDestructive {
 		return fmt.Errorf("destructive migration statements require AllowDestructive")
@@ -422,6 +833,65 @@ type generatedMigrationSpec struct {
 	DownSQL       string
 	Assessments   []safety.StatementAssessment
 	NoTransaction bool
+
+	// validationNodes holds VALIDATE CONSTRAINT statements split out of UpSQL
+	// under SplitValidation, and the SafeNotNull validation groups.
+	validationNodes []ast.Node
+	// validationDownNodes re-add the NOT VALID checks the validation groups
+	// drop, so the validation migration can be rolled back and re-applied.
+	validationDownNodes []ast.Node
+}
+
+// planOptions carries the GenerateMigrationOptions that shape planning from
+// GenerateMigrationDetailed down to the individual migration specs.
+type planOptions struct {
+	policy                  DiffPolicy
+	safeNotNull             bool
+	safeTypeChange          bool
+	safeVolatileDefault     bool
+	statementFilter         StatementFilter
+	twoStepValidation       bool
+	splitValidation         bool
+	splitStrategy           SplitStrategy
+	customStatements        []planner.CustomStatementGenerator
+	mysqlOnlineDDL          bool
+	guardedRollback         bool
+	cascadeCyclicTableDrops bool
+	// generatedAt is the generation time written to migration headers.
+	generatedAt time.Time
+}
+
+func newPlanOptions(opts GenerateMigrationOptions, splitStrategy SplitStrategy, now time.Time) planOptions {
+	return planOptions{
+		policy:                  opts.DiffPolicy,
+		safeNotNull:             opts.SafeNotNull,
+		safeTypeChange:          opts.SafeTypeChange,
+		safeVolatileDefault:     opts.SafeVolatileDefault,
+		statementFilter:         opts.StatementFilter,
+		twoStepValidation:       opts.TwoStepConstraintValidation,
+		splitValidation:         opts.SplitValidation,
+		splitStrategy:           splitStrategy,
+		customStatements:        opts.CustomStatementGenerators,
+		mysqlOnlineDDL:          opts.MySQLOnlineDDL,
+		guardedRollback:         opts.GuardedRollback,
+		cascadeCyclicTableDrops: opts.CascadeCyclicTableDrops,
+		generatedAt:             now,
+	}
+}
+
+// plannerOptions returns the planner options for a plan against caps.
+func (o planOptions) plannerOptions(caps capability.Capabilities, concurrentIndexNames []string) planner.Options {
+	return planner.Options{
+		Capabilities:                caps,
+		ConcurrentIndexNames:        concurrentIndexNames,
+		SafeNotNull:                 o.safeNotNull,
+		SafeTypeChange:              o.safeTypeChange,
+		SafeVolatileDefault:         o.safeVolatileDefault,
+		TwoStepConstraintValidation: o.twoStepValidation,
+		CustomStatementGenerators:   o.customStatements,
+		MySQLOnlineDDL:              o.mysqlOnlineDDL,
+		CascadeCyclicTableDrops:     o.cascadeCyclicTableDrops,
+	}
 }
 
 func planGeneratedMigrationSpecs(
@@ -431,7 +901,27 @@ func planGeneratedMigrationSpecs(
 	info dbschematypes.DBInfo,
 	version int64,
 	migrationName string,
-	policy DiffPolicy,
+	plan planOptions,
+) ([]generatedMigrationSpec, []safety.StatementAssessment, error) {
+	specs, assessments, err := planPolicyMigrationSpecs(diff, generated, dbSchema, info, version, migrationName, plan)
+	if err != nil || !plan.splitValidation && !plan.safeNotNull {
+		return specs, assessments, err
+	}
+	specs, validationAssessments, err := appendValidationSpec(specs, info, migrationName, plan.generatedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return specs, append(assessments, validationAssessments...), nil
+}
+
+func planPolicyMigrationSpecs(
+	diff *types.SchemaDiff,
+	generated *goschema.Database,
+	dbSchema *dbschematypes.DBSchema,
+	info dbschematypes.DBInfo,
+	version int64,
+	migrationName string,
+	plan planOptions,
 ) ([]generatedMigrationSpec, []safety.StatementAssessment, error) {
 	// Apply the diff policy once, up front, BEFORE any concurrent-index split.
 	// The split separates an index redefinition's added and removed entries into
@@ -441,19 +931,45 @@ func planGeneratedMigrationSpecs(
 	// added/removed pair together, and downstream planning runs with an empty
 	// planner-level skip. The omitted changes are surfaced as leading comments.
 	var skipped []diffpolicy.SkippedChange
-	if skipSet := diffpolicy.NewSkipSet(policy.SkipChangeKinds...); !skipSet.Empty() {
+	if skipSet := diffpolicy.NewSkipSet(plan.policy.SkipChangeKinds...); !skipSet.Empty() {
 		diff, skipped = diffpolicy.Apply(diff, skipSet)
 	}
 
-	concurrentIndexNames := concurrentIndexNamesForPolicy(diff, generated, dbSchema, info, policy)
-	plannerOpts := planner.Options{
-		Capabilities:         info.Capabilities,
-		ConcurrentIndexNames: concurrentIndexNames,
+	concurrentIndexNames := concurrentIndexNamesForPolicy(diff, generated, dbSchema, info, plan.policy)
+	var specs []generatedMigrationSpec
+	var allAssessments []safety.StatementAssessment
+	for _, part := range splitMigrationDiff(plan.splitStrategy, diff, generated, dbSchema, info.Dialect) {
+		partSpecs, assessments, err := planDiffMigrationSpecs(part.diff, generated, dbSchema, info, version, migrationName+part.suffix, plan, concurrentIndexNames)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(partSpecs) == 0 {
+			continue
+		}
+		specs = append(specs, partSpecs...)
+		allAssessments = append(allAssessments, assessments...)
+		version = partSpecs[len(partSpecs)-1].Version + 1
 	}
-	upNodes, err := planner.GenerateSchemaDiffASTWithOptions(diff, generated, info.Dialect, plannerOpts)
+	return withSkipComments(specs, skipped), allAssessments, nil
+}
+
+// planDiffMigrationSpecs plans one diff as a migration, or as a transactional
+// migration followed by a concurrent-index migration when the plan mixes both.
+func planDiffMigrationSpecs(
+	diff *types.SchemaDiff,
+	generated *goschema.Database,
+	dbSchema *dbschematypes.DBSchema,
+	info dbschematypes.DBInfo,
+	version int64,
+	migrationName string,
+	plan planOptions,
+	concurrentIndexNames []string,
+) ([]generatedMigrationSpec, []safety.StatementAssessment, error) {
+	upNodes, err := planner.GenerateSchemaDiffASTWithOptions(diff, generated, info.Dialect, plan.plannerOptions(info.Capabilities, concurrentIndexNames))
 	if err != nil {
 		return nil, nil, fmt.Errorf("error generating up migration plan: %w", err)
 	}
+	upNodes = applyStatementFilter(plan.statementFilter, DirectionUp, info.Dialect, upNodes)
 	if len(upNodes) == 0 {
 		return nil, nil, nil
 	}
@@ -467,30 +983,33 @@ func planGeneratedMigrationSpecs(
 			Capabilities: info.Capabilities,
 			Version:      version,
 			Name:         migrationName,
+			Plan:         plan,
+			UpNodes:      upNodes,
 		})
 		if err != nil || spec.UpSQL == "" {
 			return nil, assessments, err
 		}
-		return withSkipComments([]generatedMigrationSpec{spec}, skipped), assessments, nil
+		return []generatedMigrationSpec{spec}, assessments, nil
 	}
 
 	nodeGroups := splitNoTransactionNodes(info.Dialect, upNodes)
 	if len(nodeGroups.transactional) == 0 {
 		spec, assessments, err := buildGeneratedMigrationSpec(generatedMigrationSpecOptions{
-			Diff:                 diff,
-			Generated:            generated,
-			DBSchema:             dbSchema,
-			Dialect:              info.Dialect,
-			Capabilities:         info.Capabilities,
-			Version:              version,
-			Name:                 migrationName,
-			ConcurrentIndexNames: concurrentIndexNames,
-			NoTransaction:        true,
+			Diff:          diff,
+			Generated:     generated,
+			DBSchema:      dbSchema,
+			Dialect:       info.Dialect,
+			Capabilities:  info.Capabilities,
+			Version:       version,
+			Name:          migrationName,
+			NoTransaction: true,
+			Plan:          plan,
+			UpNodes:       upNodes,
 		})
 		if err != nil || spec.UpSQL == "" {
 			return nil, assessments, err
 		}
-		return withSkipComments([]generatedMigrationSpec{spec}, skipped), assessments, nil
+		return []generatedMigrationSpec{spec}, assessments, nil
 	}
 	if !allNoTransactionNodesAreConcurrentIndexes(nodeGroups.noTransaction) {
 		return nil, nil, fmt.Errorf("generated migration mixes transactional statements with non-transactional statements that cannot be split automatically")
@@ -508,6 +1027,8 @@ func planGeneratedMigrationSpecs(
 			Capabilities: info.Capabilities,
 			Version:      version,
 			Name:         migrationName + "_transactional",
+			Plan:         plan,
+			UpNodes:      nodeGroups.transactional,
 		})
 		if err != nil {
 			return nil, nil, err
@@ -520,15 +1041,16 @@ func planGeneratedMigrationSpecs(
 	}
 	if diffGroups.noTransaction.HasChanges() {
 		spec, assessments, err := buildGeneratedMigrationSpec(generatedMigrationSpecOptions{
-			Diff:                 diffGroups.noTransaction,
-			Generated:            generated,
-			DBSchema:             dbSchema,
-			Dialect:              info.Dialect,
-			Capabilities:         info.Capabilities,
-			Version:              version,
-			Name:                 migrationName + "_concurrent_indexes",
-			ConcurrentIndexNames: concurrentIndexNames,
-			NoTransaction:        true,
+			Diff:          diffGroups.noTransaction,
+			Generated:     generated,
+			DBSchema:      dbSchema,
+			Dialect:       info.Dialect,
+			Capabilities:  info.Capabilities,
+			Version:       version,
+			Name:          migrationName + "_concurrent_indexes",
+			NoTransaction: true,
+			Plan:          plan,
+			UpNodes:       nodeGroups.noTransaction,
 		})
 		if err != nil {
 			return nil, nil, err
@@ -538,7 +1060,7 @@ func planGeneratedMigrationSpecs(
 			allAssessments = append(allAssessments, assessments...)
 		}
 	}
-	return withSkipComments(specs, skipped), allAssessments, nil
+	return specs, allAssessments, nil
 }
 
 // withSkipComments prepends the diff-policy omission comments to the first
@@ -560,31 +1082,36 @@ func withSkipComments(specs []generatedMigrationSpec, skipped []diffpolicy.Skipp
 }
 
 type generatedMigrationSpecOptions struct {
-	Diff                 *types.SchemaDiff
-	Generated            *goschema.Database
-	DBSchema             *dbschematypes.DBSchema
-	Dialect              string
-	Capabilities         capability.Capabilities
-	Version              int64
-	Name                 string
-	ConcurrentIndexNames []string
-	NoTransaction        bool
+	Diff          *types.SchemaDiff
+	Generated     *goschema.Database
+	DBSchema      *dbschematypes.DBSchema
+	Dialect       string
+	Capabilities  capability.Capabilities
+	Version       int64
+	Name          string
+	NoTransaction bool
+	Plan          planOptions
+	// UpNodes is the up plan, already passed through the statement filter.
+	UpNodes []ast.Node
 }
 
 func buildGeneratedMigrationSpec(opts generatedMigrationSpecOptions) (generatedMigrationSpec, []safety.StatementAssessment, error) {
-	plannerOpts := planner.Options{
-		Capabilities:         opts.Capabilities,
-		ConcurrentIndexNames: opts.ConcurrentIndexNames,
-	}
-	upNodes, err := planner.GenerateSchemaDiffASTWithOptions(opts.Diff, opts.Generated, opts.Dialect, plannerOpts)
-	if err != nil {
-		return generatedMigrationSpec{}, nil, fmt.Errorf("error generating up migration plan: %w", err)
+	plan := opts.Plan
+	upNodes := opts.UpNodes
+	var validationNodes []ast.Node
+	if plan.splitValidation || plan.safeNotNull {
+		upNodes, validationNodes = splitValidationNodes(upNodes, plan.splitValidation)
 	}
+	notNullChecks := notNullCheckNodes(upNodes, validationNodes)
 	assessments, err := safety.AssessRenderedWithCapabilities(upNodes, opts.Dialect, opts.Capabilities)
 	if err != nil {
 		return generatedMigrationSpec{}, nil, fmt.Errorf("error assessing migration safety: %w", err)
 	}
-	directiveOpts := generatedDirectiveOptions{skipTimeouts: opts.NoTransaction}
+	directiveOpts := generatedDirectiveOptions{
+		skipTimeouts: opts.NoTransaction,
+		version:      opts.Version,
+		generatedAt:  plan.generatedAt,
+	}
 	upSQL, err := renderGeneratedMigrationSQL(upNodes, opts.Dialect, opts.Capabilities, "UP", directiveOpts)
 	if err != nil {
 		return generatedMigrationSpec{}, nil, fmt.Errorf("error generating up migration SQL: %w", err)
@@ -600,24 +1127,170 @@ func buildGeneratedMigrationSpec(opts generatedMigrationSpecOptions) (generatedM
 	// so the down migration reverses only what the up migration actually did: a
 	// skipped destructive change is absent from the diff, so its inverse (e.g. a
 	// CREATE TABLE that would collide with the kept table) is never emitted.
-	downSQL, err := generateDownMigrationSQLWithOptions(opts.Diff, opts.Generated, opts.DBSchema, opts.Dialect, directiveOpts, opts.Capabilities)
+	downSQL, err := generateDownMigrationSQLWithOptions(opts.Diff, opts.Generated, opts.DBSchema, opts.Dialect, directiveOpts, plan.statementFilter, plan.customStatements, plan.safeTypeChange, opts.Capabilities)
 	if err != nil {
 		return generatedMigrationSpec{}, nil, fmt.Errorf("error generating down migration SQL: %w", err)
 	}
+	if len(notNullChecks) > 0 {
+		cleanup, err := dropNotNullChecksSQL(notNullChecks, opts.Dialect, opts.Capabilities)
+		if err != nil {
+			return generatedMigrationSpec{}, nil, fmt.Errorf("error generating down migration SQL: %w", err)
+		}
+		if withCleanup := insertAfterLeadingComments(downSQL, cleanup); withCleanup != downSQL {
+			downSQL = withCleanup
+		} else {
+			downSQL = strings.TrimRight(downSQL, "\n") + "\n\n" + cleanup
+		}
+	}
+	if plan.guardedRollback {
+		downSQL = withRollbackGuards(downSQL, opts.Diff, opts.Dialect)
+	}
 	if opts.NoTransaction {
 		downSQL = withNoTransactionDirective(downSQL)
 	}
 
 	return generatedMigrationSpec{
-		Version:       opts.Version,
-		Name:          opts.Name,
-		UpSQL:         upSQL,
-		DownSQL:       downSQL,
-		Assessments:   assessments,
-		NoTransaction: opts.NoTransaction,
+		Version:             opts.Version,
+		Name:                opts.Name,
+		UpSQL:               upSQL,
+		DownSQL:             downSQL,
+		Assessments:         assessments,
+		NoTransaction:       opts.NoTransaction,
+		validationNodes:     validationNodes,
+		validationDownNodes: notNullChecks,
 	}, assessments, nil
 }
 
+// splitValidationNodes separates validation statements from the rest of the
+// plan. The SafeNotNull validation groups always move: their VALIDATE must not
+// share a transaction with the ADD CONSTRAINT ... NOT VALID that precedes it,
+// or the validation scan runs under that statement's ACCESS EXCLUSIVE lock.
+// With all set, the trailing VALIDATE CONSTRAINT statements the planner
+// appends under two-step constraint validation move as well.
+func splitValidationNodes(nodes []ast.Node, all bool) ([]ast.Node, []ast.Node) {
+	kept := make([]ast.Node, 0, len(nodes))
+	var validations []ast.Node
+	for _, node := range nodes {
+		if isNotNullValidationNode(node) || all && isValidateConstraintNode(node) {
+			validations = append(validations, node)
+			continue
+		}
+		kept = append(kept, node)
+	}
+	return kept, validations
+}
+
+func isValidateConstraintNode(node ast.Node) bool {
+	alter, ok := node.(*ast.AlterTableNode)
+	if !ok || len(alter.Operations) != 1 {
+		return false
+	}
+	_, ok = alter.Operations[0].(*ast.ValidateConstraintOperation)
+	return ok
+}
+
+// isNotNullValidationNode reports whether node is a SafeNotNull validation
+// group: VALIDATE of the temporary check, the column change that sets NOT
+// NULL, and the DROP of the check.
+func isNotNullValidationNode(node ast.Node) bool {
+	alter, ok := node.(*ast.AlterTableNode)
+	if !ok || len(alter.Operations) < 2 {
+		return false
+	}
+	_, ok = alter.Operations[0].(*ast.ValidateConstraintOperation)
+	return ok
+}
+
+// notNullCheckNodes returns the ADD CONSTRAINT nodes in kept that add the
+// temporary checks of the SafeNotNull validation groups in validations.
+func notNullCheckNodes(kept, validations []ast.Node) []ast.Node {
+	groups := make(map[string]bool)
+	for _, node := range validations {
+		if isNotNullValidationNode(node) {
+			alter := node.(*ast.AlterTableNode)
+			groups[alter.Name+"."+alter.Operations[0].(*ast.ValidateConstraintOperation).ConstraintName] = true
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	var checks []ast.Node
+	for _, node := range kept {
+		alter, ok := node.(*ast.AlterTableNode)
+		if !ok || len(alter.Operations) != 1 {
+			continue
+		}
+		if add, ok := alter.Operations[0].(*ast.AddConstraintOperation); ok && groups[alter.Name+"."+add.Constraint.Name] {
+			checks = append(checks, node)
+		}
+	}
+	return checks
+}
+
+// dropNotNullChecksSQL renders DROP CONSTRAINT IF EXISTS for the temporary
+// checks added by checks. The rollback of the migration that added them runs
+// it, since the validation migration that normally drops them may have failed
+// or not run yet.
+func dropNotNullChecksSQL(checks []ast.Node, dialect string, caps capability.Capabilities) (string, error) {
+	drops := make([]ast.Node, 0, len(checks))
+	for _, node := range checks {
+		alter := node.(*ast.AlterTableNode)
+		drops = append(drops, &ast.AlterTableNode{Name: alter.Name, Operations: []ast.AlterOperation{&ast.DropConstraintOperation{
+			ConstraintName: alter.Operations[0].(*ast.AddConstraintOperation).Constraint.Name,
+			IfExists:       true,
+		}}})
+	}
+	return renderer.RenderSQLWithCapabilities(dialect, caps, drops...)
+}
+
+// appendValidationSpec collects the validation statements split out of specs
+// into one follow-up migration versioned after the last spec. VALIDATE has no
+// inverse; rolling back the preceding migration drops the constraints. The
+// SafeNotNull checks the follow-up drops are re-added NOT VALID on rollback so
+// it can be applied again, and the column change it makes is reverted by the
+// rollback of the preceding migration.
+func appendValidationSpec(
+	specs []generatedMigrationSpec,
+	info dbschematypes.DBInfo,
+	migrationName string,
+	generatedAt time.Time,
+) ([]generatedMigrationSpec, []safety.StatementAssessment, error) {
+	var nodes, downNodes []ast.Node
+	for _, spec := range specs {
+		nodes = append(nodes, spec.validationNodes...)
+		downNodes = append(downNodes, spec.validationDownNodes...)
+	}
+	if len(nodes) == 0 {
+		return specs, nil, nil
+	}
+	assessments, err := safety.AssessRenderedWithCapabilities(nodes, info.Dialect, info.Capabilities)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error assessing migration safety: %w", err)
+	}
+	directiveOpts := generatedDirectiveOptions{
+		version:     specs[len(specs)-1].Version + 1,
+		generatedAt: generatedAt,
+	}
+	upSQL, err := renderGeneratedMigrationSQL(nodes, info.Dialect, info.Capabilities, "UP", directiveOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error generating constraint validation SQL: %w", err)
+	}
+	downSQL := emptyDownMigrationSQL(directiveOpts)
+	if len(downNodes) > 0 {
+		downSQL, err = renderGeneratedMigrationSQL(downNodes, info.Dialect, info.Capabilities, "DOWN", directiveOpts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error generating constraint validation rollback SQL: %w", err)
+		}
+	}
+	return append(specs, generatedMigrationSpec{
+		Version:     directiveOpts.version,
+		Name:        migrationName + "_validate_constraints",
+		UpSQL:       upSQL,
+		DownSQL:     downSQL,
+		Assessments: assessments,
+	}), assessments, nil
+}
+
 func renderGeneratedMigrationSQL(
 	nodes []ast.Node,
 	dialect string,
@@ -633,8 +1306,7 @@ func renderGeneratedMigrationSQL(
 	if len(statements) == 0 || !hasActualSQLStatements(statements) {
 		return "", nil
 	}
-	header := fmt.Sprintf("-- Migration generated from schema differences\n-- Generated on: %s\n-- Direction: %s\n\n",
-		time.Now().Format(time.RFC3339), direction)
+	header := generatedMigrationHeader("Migration generated from schema differences", direction, directiveOpts)
 	return withGeneratedTimeoutDirectivesForOptions(header+strings.Join(statements, ";\n")+";", dialect, directiveOpts), nil
 }
 
@@ -782,18 +1454,23 @@ func cloneSchemaDiff(diff *types.SchemaDiff) *types.SchemaDiff {
 	clone := *diff
 	clone.TablesAdded = slices.Clone(diff.TablesAdded)
 	clone.TablesRemoved = slices.Clone(diff.TablesRemoved)
+	clone.TableDropDependencies = maps.Clone(diff.TableDropDependencies)
 	clone.TablesModified = slices.Clone(diff.TablesModified)
 	clone.EnumsAdded = slices.Clone(diff.EnumsAdded)
 	clone.EnumsRemoved = slices.Clone(diff.EnumsRemoved)
 	clone.EnumsModified = slices.Clone(diff.EnumsModified)
+	clone.EnumsRenamed = slices.Clone(diff.EnumsRenamed)
 	clone.IndexesAdded = slices.Clone(diff.IndexesAdded)
 	clone.IndexesRemoved = slices.Clone(diff.IndexesRemoved)
 	clone.IndexesRemovedWithTables = slices.Clone(diff.IndexesRemovedWithTables)
+	clone.IndexesVisibilityChanged = slices.Clone(diff.IndexesVisibilityChanged)
 	clone.ExtensionsAdded = slices.Clone(diff.ExtensionsAdded)
 	clone.ExtensionsRemoved = slices.Clone(diff.ExtensionsRemoved)
+	clone.ExtensionsModified = slices.Clone(diff.ExtensionsModified)
 	clone.FunctionsAdded = slices.Clone(diff.FunctionsAdded)
 	clone.FunctionsRemoved = slices.Clone(diff.FunctionsRemoved)
 	clone.FunctionsModified = slices.Clone(diff.FunctionsModified)
+	clone.FunctionTriggers = maps.Clone(diff.FunctionTriggers)
 	clone.SequencesAdded = slices.Clone(diff.SequencesAdded)
 	clone.SequencesRemoved = slices.Clone(diff.SequencesRemoved)
 	clone.SequencesModified = slices.Clone(diff.SequencesModified)
@@ -822,6 +1499,8 @@ func cloneSchemaDiff(diff *types.SchemaDiff) *types.SchemaDiff {
 	clone.RolesAdded = slices.Clone(diff.RolesAdded)
 	clone.RolesRemoved = slices.Clone(diff.RolesRemoved)
 	clone.RolesModified = slices.Clone(diff.RolesModified)
+	clone.RoleMembershipsAdded = slices.Clone(diff.RoleMembershipsAdded)
+	clone.RoleMembershipsRemoved = slices.Clone(diff.RoleMembershipsRemoved)
 	clone.GrantsAdded = slices.Clone(diff.GrantsAdded)
 	clone.GrantsRemoved = slices.Clone(diff.GrantsRemoved)
 	clone.GrantOptionsAdded = slices.Clone(diff.GrantOptionsAdded)
@@ -830,6 +1509,8 @@ func cloneSchemaDiff(diff *types.SchemaDiff) *types.SchemaDiff {
 	clone.ConstraintsAddedWithTables = slices.Clone(diff.ConstraintsAddedWithTables)
 	clone.ConstraintsRemoved = slices.Clone(diff.ConstraintsRemoved)
 	clone.ConstraintsRemovedWithTables = slices.Clone(diff.ConstraintsRemovedWithTables)
+	clone.SeedsAdded = slices.Clone(diff.SeedsAdded)
+	clone.SeedsRemoved = slices.Clone(diff.SeedsRemoved)
 	return &clone
 }
 
@@ -890,6 +1571,10 @@ func generateUpMigrationSQL(
 
 type generatedDirectiveOptions struct {
 	skipTimeouts bool
+	// version and generatedAt feed the file header; zero values omit the
+	// version line and use the current time.
+	version     int64
+	generatedAt time.Time
 }
 
 func generateUpMigrationSQLWithOptions(
@@ -914,8 +1599,7 @@ func generateUpMigrationSQLWithOptions(
 	}
 
 	// Add header comment
-	header := fmt.Sprintf("-- Migration generated from schema differences\n-- Generated on: %s\n-- Direction: UP\n\n",
-		time.Now().Format(time.RFC3339))
+	header := generatedMigrationHeader("Migration generated from schema differences", "UP", directiveOpts)
 
 	return withGeneratedTimeoutDirectivesForOptions(header+strings.Join(statements, ";\n")+";", dialect, directiveOpts), nil
 }
@@ -928,7 +1612,7 @@ func generateDownMigrationSQL(
 	dialect string,
 	capsOverride ...capability.Capabilities,
 ) (string, error) {
-	return generateDownMigrationSQLWithOptions(diff, generated, dbSchema, dialect, generatedDirectiveOptions{}, capsOverride...)
+	return generateDownMigrationSQLWithOptions(diff, generated, dbSchema, dialect, generatedDirectiveOptions{}, nil, nil, false, capsOverride...)
 }
 
 func generateDownMigrationSQLWithOptions(
@@ -937,6 +1621,9 @@ func generateDownMigrationSQLWithOptions(
 	dbSchema *dbschematypes.DBSchema,
 	dialect string,
 	directiveOpts generatedDirectiveOptions,
+	filter StatementFilter,
+	custom []planner.CustomStatementGenerator,
+	safeTypeChange bool,
 	capsOverride ...capability.Capabilities,
 ) (string, error) {
 	// For down migrations, we need to use the current database schema as the "generated" schema
@@ -955,25 +1642,53 @@ func generateDownMigrationSQLWithOptions(
 	if len(capsOverride) > 0 {
 		caps = capsOverride[0]
 	}
-	statements, err := planner.GenerateSchemaDiffSQLStatementsWithCapabilities(reverseDiff, dbAsGoSchema, dialect, caps)
+	downNodes, err := planner.GenerateSchemaDiffASTWithOptions(reverseDiff, dbAsGoSchema, dialect, planner.Options{
+		Capabilities:              caps,
+		CustomStatementGenerators: custom,
+		SafeTypeChange:            safeTypeChange,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error generating down migration SQL: %w", err)
+	}
+	downNodes = applyStatementFilter(filter, DirectionDown, dialect, downNodes)
+	rawSQL, err := renderer.RenderSQLWithCapabilities(dialect, caps, downNodes...)
 	if err != nil {
 		return "", fmt.Errorf("error generating down migration SQL: %w", err)
 	}
+	statements := sqlutil.SplitSQLStatements(rawSQL)
 
 	if len(statements) == 0 {
-		// If no statements generated, create a simple comment
-		header := fmt.Sprintf("-- Migration rollback\n-- Generated on: %s\n-- Direction: DOWN\n\n-- No rollback operations needed\n",
-			time.Now().Format(time.RFC3339))
-		return header, nil
+		return emptyDownMigrationSQL(directiveOpts), nil
 	}
 
 	// Add header comment
-	header := fmt.Sprintf("-- Migration rollback\n-- Generated on: %s\n-- Direction: DOWN\n\n",
-		time.Now().Format(time.RFC3339))
+	header := generatedMigrationHeader("Migration rollback", "DOWN", directiveOpts)
 
 	return withGeneratedTimeoutDirectivesForOptions(header+strings.Join(statements, ";\n")+";", dialect, directiveOpts), nil
 }
 
+// emptyDownMigrationSQL returns the down migration body used when there is
+// nothing to roll back.
+func emptyDownMigrationSQL(opts generatedDirectiveOptions) string {
+	return generatedMigrationHeader("Migration rollback", "DOWN", opts) + "-- No rollback operations needed\n"
+}
+
+// generatedMigrationHeader renders the comment block that opens a generated
+// migration file. The version line is omitted when no version is known.
+func generatedMigrationHeader(title, direction string, opts generatedDirectiveOptions) string {
+	generatedAt := opts.generatedAt
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+	var header strings.Builder
+	fmt.Fprintf(&header, "-- %s\n", title)
+	if opts.version != 0 {
+		fmt.Fprintf(&header, "-- Version: %d\n", opts.version)
+	}
+	fmt.Fprintf(&header, "-- Generated on: %s\n-- Direction: %s\n\n", generatedAt.Format(time.RFC3339), direction)
+	return header.String()
+}
+
 func withGeneratedTimeoutDirectivesForOptions(sql, dialect string, opts generatedDirectiveOptions) string {
 	if opts.skipTimeouts {
 		return sql
@@ -994,6 +1709,51 @@ func withGeneratedTimeoutDirectives(sql, dialect string) string {
 	return directives + sql
 }
 
+// withSessionSettings scopes settings to every transactional spec's up and
+// down SQL: the setup statements go after the leading comment block so file
+// directives stay in place, and the restore statements go last. Files
+// without statements are left alone, and so are no_transaction files, whose
+// statements may run on different pooled connections.
+func withSessionSettings(specs []generatedMigrationSpec, dialect string, settings map[string]string) error {
+	if len(settings) == 0 {
+		return nil
+	}
+	setup, restore, err := migrator.SessionSettingStatements(dialect, settings)
+	if err != nil {
+		return fmt.Errorf("error rendering session settings: %w", err)
+	}
+	for i := range specs {
+		if specs[i].NoTransaction {
+			continue
+		}
+		specs[i].UpSQL = wrapSessionSettings(specs[i].UpSQL, setup, restore)
+		specs[i].DownSQL = wrapSessionSettings(specs[i].DownSQL, setup, restore)
+	}
+	return nil
+}
+
+func wrapSessionSettings(sql string, setup, restore []string) string {
+	wrapped := insertAfterLeadingComments(sql, strings.Join(setup, ";\n")+";\n\n")
+	if wrapped == sql || len(restore) == 0 {
+		return wrapped
+	}
+	return strings.TrimRight(wrapped, "\n") + "\n\n" + strings.Join(restore, ";\n") + ";\n"
+}
+
+// insertAfterLeadingComments inserts block before the first line of sql that
+// is neither blank nor a comment. SQL made only of comments is returned as is.
+func insertAfterLeadingComments(sql, block string) string {
+	offset := 0
+	for line := range strings.SplitAfterSeq(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			return sql[:offset] + block + sql[offset:]
+		}
+		offset += len(line)
+	}
+	return sql
+}
+
 func containsAlterTable(sql string) bool {
 	stripped := sqlutil.StripComments(sql)
 	return strings.Contains(strings.ToUpper(stripped), "ALTER TABLE")
@@ -1072,6 +1832,31 @@ func indexRemovalSet(indexes []types.IndexRemovalInfo) map[string]bool {
 	return out
 }
 
+// addedIndexRemovals resolves the tables of the indexes an up migration adds,
+// so the down migration drops them with the ON table form MySQL requires and
+// skips those whose table it drops anyway.
+func addedIndexRemovals(names []string, schema *goschema.Database) []types.IndexRemovalInfo {
+	if schema == nil || len(names) == 0 {
+		return nil
+	}
+	tablesByStruct := make(map[string]string, len(schema.Tables))
+	for _, table := range schema.Tables {
+		tablesByStruct[table.StructName] = table.QualifiedName()
+	}
+	var removals []types.IndexRemovalInfo
+	for _, index := range schema.Indexes {
+		if !slices.Contains(names, index.Name) {
+			continue
+		}
+		tableName := cmp.Or(index.TableName, tablesByStruct[index.StructName], index.StructName)
+		removals = append(removals, types.IndexRemovalInfo{Name: index.Name, TableName: tableName, Columns: index.Fields})
+	}
+	slices.SortFunc(removals, func(a, b types.IndexRemovalInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return removals
+}
+
 func indexKey(tableName, indexName string) string {
 	return tableName + "." + indexName
 }
@@ -1100,15 +1885,19 @@ func reverseSchemaDiffWithSchema(diff *types.SchemaDiff, schema *goschema.Databa
 		// Reverse enum operations
 		EnumsAdded:    diff.EnumsRemoved, // Enums to remove become enums to add
 		EnumsRemoved:  diff.EnumsAdded,   // Enums to add become enums to remove
-		EnumsModified: reverseEnumDiffs(diff.EnumsModified),
+		EnumsModified: reverseEnumDiffs(diff.EnumsModified, diff.EnumsRenamed),
+		EnumsRenamed:  reverseEnumRenames(diff.EnumsRenamed),
 
 		// Reverse index operations
-		IndexesAdded:   diff.IndexesRemoved, // Indexes to remove become indexes to add
-		IndexesRemoved: diff.IndexesAdded,   // Indexes to add become indexes to remove
+		IndexesAdded:             diff.IndexesRemoved, // Indexes to remove become indexes to add
+		IndexesRemoved:           diff.IndexesAdded,   // Indexes to add become indexes to remove
+		IndexesRemovedWithTables: addedIndexRemovals(diff.IndexesAdded, schema),
+		IndexesVisibilityChanged: reverseIndexVisibilityChanges(diff.IndexesVisibilityChanged),
 
 		// Reverse extension operations
-		ExtensionsAdded:   diff.ExtensionsRemoved, // Extensions to remove become extensions to add
-		ExtensionsRemoved: diff.ExtensionsAdded,   // Extensions to add become extensions to remove
+		ExtensionsAdded:    diff.ExtensionsRemoved, // Extensions to remove become extensions to add
+		ExtensionsRemoved:  diff.ExtensionsAdded,   // Extensions to add become extensions to remove
+		ExtensionsModified: reverseExtensionDiffs(diff.ExtensionsModified),
 
 		// Reverse function operations
 		FunctionsAdded:    diff.FunctionsRemoved, // Functions to remove become functions to add
@@ -1140,13 +1929,15 @@ func reverseSchemaDiffWithSchema(diff *types.SchemaDiff, schema *goschema.Databa
 		RLSEnabledTablesRemoved: diff.RLSEnabledTablesAdded,   // Tables to enable RLS become tables to disable RLS
 
 		// Reverse role operations
-		RolesAdded:          diff.RolesRemoved, // Roles to remove become roles to add
-		RolesRemoved:        diff.RolesAdded,   // Roles to add become roles to remove
-		RolesModified:       reverseRoleDiffs(diff.RolesModified),
-		GrantsAdded:         diff.GrantsRemoved,       // Grants to remove become grants to add
-		GrantsRemoved:       diff.GrantsAdded,         // Grants to add become grants to revoke
-		GrantOptionsAdded:   diff.GrantOptionsRevoked, // Revoked grant options become grant-option additions
-		GrantOptionsRevoked: diff.GrantOptionsAdded,   // Grant-option additions become grant-option revocations
+		RolesAdded:             diff.RolesRemoved, // Roles to remove become roles to add
+		RolesRemoved:           diff.RolesAdded,   // Roles to add become roles to remove
+		RolesModified:          reverseRoleDiffs(diff.RolesModified),
+		RoleMembershipsAdded:   diff.RoleMembershipsRemoved, // Revoked memberships are granted again
+		RoleMembershipsRemoved: diff.RoleMembershipsAdded,   // Granted memberships are revoked
+		GrantsAdded:            diff.GrantsRemoved,          // Grants to remove become grants to add
+		GrantsRemoved:          diff.GrantsAdded,            // Grants to add become grants to revoke
+		GrantOptionsAdded:      diff.GrantOptionsRevoked,    // Revoked grant options become grant-option additions
+		GrantOptionsRevoked:    diff.GrantOptionsAdded,      // Grant-option additions become grant-option revocations
 
 		// Reverse constraint operations. A modified constraint is expressed by
 		// the comparator as remove + add of the SAME name (e.g. an on_delete
@@ -1167,6 +1958,11 @@ func reverseSchemaDiffWithSchema(diff *types.SchemaDiff, schema *goschema.Databa
 		ConstraintsRemoved:           diff.ConstraintsAdded,
 		ConstraintsRemovedWithTables: reverseConstraintRemovals(diff, schema),
 		ConstraintsAddedWithTables:   reverseConstraintAdditions(diff, dbSchema),
+
+		// Reverse seed operations: rows inserted by the up migration are
+		// deleted again by the down migration.
+		SeedsAdded:   diff.SeedsRemoved,
+		SeedsRemoved: diff.SeedsAdded,
 	}
 }
 
@@ -1542,6 +2338,13 @@ func reverseTableDiffs(tableDiffs []types.TableDiff) []types.TableDiff {
 			ColumnsAdded:    tableDiff.ColumnsRemoved, // Columns to remove become columns to add
 			ColumnsRemoved:  tableDiff.ColumnsAdded,   // Columns to add become columns to remove
 			ColumnsModified: reverseColumnDiffs(tableDiff.ColumnsModified),
+			InheritsAdded:   tableDiff.InheritsRemoved, // Dropped parents are inherited again
+			InheritsRemoved: tableDiff.InheritsAdded,   // Added parents stop being inherited
+			Comment:         reverseChange(tableDiff.Comment),
+			Owner:           reverseChange(tableDiff.Owner),
+			ColumnsExternal: tableDiff.ColumnsExternal,
+			// AutoIncrement is not reversed: the counter is only ever raised,
+			// and rows inserted since keep it from moving back.
 		}
 	}
 	return reversed
@@ -1554,30 +2357,55 @@ func reverseColumnDiffs(columnDiffs []types.ColumnDiff) []types.ColumnDiff {
 		// For column changes, we need to reverse the direction of changes
 		reversedChanges := make(map[string]string)
 		for key, change := range columnDiff.Changes {
-			// Split "old -> new" and reverse to "new -> old"
-			parts := strings.Split(change, " -> ")
-			if len(parts) == 2 {
-				reversedChanges[key] = parts[1] + " -> " + parts[0]
-			} else {
-				// If format is unexpected, keep as is
-				reversedChanges[key] = change
-			}
+			reversedChanges[key] = reverseChange(change)
 		}
 
 		reversed[i] = types.ColumnDiff{
-			ColumnName: columnDiff.ColumnName,
-			Changes:    reversedChanges,
+			ColumnName:     columnDiff.ColumnName,
+			Changes:        reversedChanges,
+			TypeChangeKind: reverseTypeChangeKind(reversedChanges["type"]),
 		}
 	}
 	return reversed
 }
 
-// reverseEnumDiffs reverses enum modifications for down migrations
-func reverseEnumDiffs(enumDiffs []types.EnumDiff) []types.EnumDiff {
+// reverseTypeChangeKind classifies a reversed "type" change so the down
+// migration plans it like the up migration would. It returns "" when the
+// column type does not change.
+func reverseTypeChangeKind(change string) types.TypeChangeKind {
+	before, after, ok := strings.Cut(change, " -> ")
+	if !ok {
+		return ""
+	}
+	return types.TypeChangeKind(typechange.Classify(strings.TrimSpace(before), strings.TrimSpace(after), ""))
+}
+
+// reverseChange turns an "old -> new" change description into
+// "new -> old", keeping values in an unexpected format as they are.
+func reverseChange(change string) string {
+	parts := strings.Split(change, " -> ")
+	if len(parts) != 2 {
+		return change
+	}
+	return parts[1] + " -> " + parts[0]
+}
+
+// reverseEnumDiffs reverses enum modifications for down migrations. The down
+// migration renames enums back before touching their values, so renamed enums
+// are addressed by their old name.
+func reverseEnumDiffs(enumDiffs []types.EnumDiff, renames []types.EnumRename) []types.EnumDiff {
+	oldNames := make(map[string]string, len(renames))
+	for _, rename := range renames {
+		oldNames[rename.NewName] = rename.OldName
+	}
 	reversed := make([]types.EnumDiff, len(enumDiffs))
 	for i, enumDiff := range enumDiffs {
+		name := enumDiff.EnumName
+		if oldName, ok := oldNames[name]; ok {
+			name = oldName
+		}
 		reversed[i] = types.EnumDiff{
-			EnumName:      enumDiff.EnumName,
+			EnumName:      name,
 			ValuesAdded:   enumDiff.ValuesRemoved, // Values to remove become values to add
 			ValuesRemoved: enumDiff.ValuesAdded,   // Values to add become values to remove
 		}
@@ -1585,6 +2413,33 @@ func reverseEnumDiffs(enumDiffs []types.EnumDiff) []types.EnumDiff {
 	return reversed
 }
 
+// reverseEnumRenames swaps each rename so the down migration restores the
+// previous enum type name.
+func reverseEnumRenames(renames []types.EnumRename) []types.EnumRename {
+	if len(renames) == 0 {
+		return nil
+	}
+	reversed := make([]types.EnumRename, len(renames))
+	for i, rename := range renames {
+		reversed[i] = types.EnumRename{OldName: rename.NewName, NewName: rename.OldName}
+	}
+	return reversed
+}
+
+// reverseIndexVisibilityChanges restores the previous visibility of each
+// toggled index for down migrations.
+func reverseIndexVisibilityChanges(changes []types.IndexVisibilityChange) []types.IndexVisibilityChange {
+	if len(changes) == 0 {
+		return nil
+	}
+	reversed := make([]types.IndexVisibilityChange, len(changes))
+	for i, change := range changes {
+		reversed[i] = change
+		reversed[i].Visible = !change.Visible
+	}
+	return reversed
+}
+
 // reverseFunctionDiffs reverses function modifications for down migrations
 func reverseFunctionDiffs(functionDiffs []types.FunctionDiff) []types.FunctionDiff {
 	reversed := make([]types.FunctionDiff, len(functionDiffs))
@@ -1611,6 +2466,25 @@ func reverseFunctionDiffs(functionDiffs []types.FunctionDiff) []types.FunctionDi
 }
 
 // reverseSequenceDiffs reverses sequence modifications for down migrations.
+// reverseExtensionDiffs swaps the current and target versions so the down
+// migration returns each extension to the version it had before the up
+// migration. Extensions installed without a known version are skipped since
+// there is no version to return to.
+func reverseExtensionDiffs(extensionDiffs []types.ExtensionDiff) []types.ExtensionDiff {
+	var reversed []types.ExtensionDiff
+	for _, extensionDiff := range extensionDiffs {
+		if extensionDiff.CurrentVersion == "" {
+			continue
+		}
+		reversed = append(reversed, types.ExtensionDiff{
+			ExtensionName:  extensionDiff.ExtensionName,
+			CurrentVersion: extensionDiff.TargetVersion,
+			TargetVersion:  extensionDiff.CurrentVersion,
+		})
+	}
+	return reversed
+}
+
 func reverseSequenceDiffs(sequenceDiffs []types.SequenceDiff) []types.SequenceDiff {
 	reversed := make([]types.SequenceDiff, len(sequenceDiffs))
 	for i, sequenceDiff := range sequenceDiffs {
@@ -1666,7 +2540,7 @@ func reverseCompositeTypeDiffs(compositeDiffs []types.CompositeTypeDiff) []types
 func convertRLSPolicyRefsToNames(policyRefs []types.RLSPolicyRef) []string {
 	names := make([]string, len(policyRefs))
 	for i, policyRef := range policyRefs {
-		names[i] = policyRef.PolicyName
+		names[i] = goschema.RLSPolicy{Name: policyRef.PolicyName, Table: policyRef.TableName}.QualifiedName()
 	}
 	return names
 }
@@ -1686,24 +2560,22 @@ func convertRLSPolicyNamesToRefsWithSchema(policyNames []string, schema *goschem
 	refs := make([]types.RLSPolicyRef, len(policyNames))
 
 	// Create a lookup map for policy name to table name if schema is provided
-	policyToTable := make(map[string]string)
+	policyByName := make(map[string]goschema.RLSPolicy)
 	if schema != nil {
 		for _, policy := range schema.RLSPolicies {
-			policyToTable[policy.Name] = policy.Table
+			policyByName[policy.QualifiedName()] = policy
 		}
 	}
 
 	for i, policyName := range policyNames {
-		tableName := ""
-		if schema != nil {
-			if table, found := policyToTable[policyName]; found {
-				tableName = table
-			}
-		}
-
 		refs[i] = types.RLSPolicyRef{
 			PolicyName: policyName,
-			TableName:  tableName,
+		}
+		if policy, found := policyByName[policyName]; found {
+			refs[i] = types.RLSPolicyRef{
+				PolicyName: policy.Name,
+				TableName:  policy.QualifiedTable(),
+			}
 		}
 	}
 	return refs
@@ -1810,45 +2682,46 @@ func withNoTransactionDirective(sql string) string {
 	return "-- +ptah " + migrator.DirectiveNoTransaction + "\n" + sql
 }
 
-// createMigrationFiles creates the up and down migration files
+// createMigrationFiles creates the up and down migration files, moving to the
+// next free version when version is already taken.
 func createMigrationFiles(outputDir string, version int64, migrationName, upSQL, downSQL string) (*MigrationFiles, error) {
-	if err := ensureMigrationOutputDir(outputDir); err != nil {
-		return nil, fmt.Errorf("failed to create output directory: %w", err)
-	}
-
 	for {
-		upFileName := migrator.GenerateMigrationFileName(version, migrationName, "up")
-		downFileName := migrator.GenerateMigrationFileName(version, migrationName, "down")
-
-		upFilePath := filepath.Join(outputDir, upFileName)
-		downFilePath := filepath.Join(outputDir, downFileName)
-
-		if err := writeNewMigrationFile(upFilePath, upSQL); err != nil {
-			if errors.Is(err, os.ErrExist) {
-				version++
-				continue
-			}
-			return nil, fmt.Errorf("failed to write up migration file: %w", err)
-		}
-
-		if err := writeNewMigrationFile(downFilePath, downSQL); err != nil {
-			_ = os.Remove(upFilePath)
-			if errors.Is(err, os.ErrExist) {
-				version++
-				continue
-			}
-			return nil, fmt.Errorf("failed to write down migration file: %w", err)
+		pair, err := writeMigrationFilePair(outputDir, version, migrationName, upSQL, downSQL)
+		if errors.Is(err, os.ErrExist) {
+			version++
+			continue
 		}
-
-		pair := MigrationFilePair{
-			UpFile:   upFilePath,
-			DownFile: downFilePath,
-			Version:  version,
+		if err != nil {
+			return nil, err
 		}
 		return migrationFilesFromPairs([]MigrationFilePair{pair}), nil
 	}
 }
 
+// writeMigrationFilePair writes one up/down pair at exactly version. It never
+// overwrites: if either file already exists, nothing is left behind and the
+// returned error wraps os.ErrExist.
+func writeMigrationFilePair(outputDir string, version int64, migrationName, upSQL, downSQL string) (MigrationFilePair, error) {
+	if err := ensureMigrationOutputDir(outputDir); err != nil {
+		return MigrationFilePair{}, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	upFilePath := filepath.Join(outputDir, migrator.GenerateMigrationFileName(version, migrationName, "up"))
+	downFilePath := filepath.Join(outputDir, migrator.GenerateMigrationFileName(version, migrationName, "down"))
+
+	if err := writeNewMigrationFile(upFilePath, upSQL); err != nil {
+		return MigrationFilePair{}, fmt.Errorf("failed to write up migration file: %w", err)
+	}
+	if err := writeNewMigrationFile(downFilePath, downSQL); err != nil {
+		_ = os.Remove(upFilePath)
+		return MigrationFilePair{}, fmt.Errorf("failed to write down migration file: %w", err)
+	}
+	return MigrationFilePair{
+		UpFile:   upFilePath,
+		DownFile: downFilePath,
+		Version:  version,
+	}, nil
+}
+
 func createMigrationFilesFromSpecs(outputDir, reportFormat string, specs []generatedMigrationSpec) (*MigrationFiles, error) {
 	pairs := make([]MigrationFilePair, 0, len(specs))
 	cleanup := func() {
@@ -1861,13 +2734,14 @@ func createMigrationFilesFromSpecs(outputDir, reportFormat string, specs []gener
 		}
 	}
 	for _, spec := range specs {
-		files, err := createMigrationFiles(outputDir, spec.Version, spec.Name, spec.UpSQL, spec.DownSQL)
+		pair, err := writeMigrationFilePair(outputDir, spec.Version, spec.Name, spec.UpSQL, spec.DownSQL)
 		if err != nil {
 			cleanup()
 			return nil, err
 		}
-		pair := files.Files[0]
 		pair.NoTransaction = spec.NoTransaction
+		pair.Statements = countSQLStatements(spec.UpSQL)
+		pair.DownStatements = countSQLStatements(spec.DownSQL)
 		if reportFormat != "" {
 			reportFile, err := createSafetyReportFile(pair.UpFile, reportFormat, spec.Assessments)
 			if err != nil {
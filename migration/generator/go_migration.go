@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stokaro/ptah/internal/pathguard"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+// GoMigrationOptions contains options for GenerateGoMigration.
+type GoMigrationOptions struct {
+	// PackageName is the package clause of the generated file.
+	PackageName string
+	// Version is the migration version. Zero uses the next timestamp
+	// version, like GenerateEmptyMigration.
+	Version int64
+	// MigrationName is the descriptive migration name used in the file name
+	// and as the migration description.
+	MigrationName string
+	// UpSQL and DownSQL are the migration bodies, for example the contents of
+	// a file pair written by GenerateMigration. They are embedded verbatim,
+	// so +ptah directives keep working.
+	UpSQL   string
+	DownSQL string
+	// Provider, when set, names a package-level
+	// *migrator.RegisteredMigrationProvider variable that the generated init
+	// function registers the migration with. Without it the file only
+	// declares the function returning the migration.
+	Provider string
+	// OutputDir is the directory where the Go file will be saved.
+	OutputDir string
+	// AllowedOutputRoot constrains OutputDir when set.
+	AllowedOutputRoot string
+}
+
+// GoMigrationFile describes a Go migration file written by
+// GenerateGoMigration.
+type GoMigrationFile struct {
+	File     string // Path to the generated Go file
+	Version  int64  // Migration version
+	FuncName string // Name of the generated function returning the migration
+}
+
+// GenerateGoMigration writes a gofmt-clean Go file that embeds the up and
+// down SQL of one migration as string constants and builds it with
+// migrator.CreateMigrationFromSQL, so services can compile their migrations
+// into the binary instead of shipping SQL files. The file declares
+//
+//	func Migration<version>() *migrator.Migration
+//
+// and, when Provider is set, an init function registering that migration.
+// The file is never overwritten; an existing file fails with an error
+// wrapping os.ErrExist.
+func GenerateGoMigration(opts GoMigrationOptions) (*GoMigrationFile, error) {
+	name := strings.TrimSpace(opts.MigrationName)
+	if err := validateEmptyMigrationName(name); err != nil {
+		return nil, err
+	}
+	if !token.IsIdentifier(opts.PackageName) || opts.PackageName == "_" {
+		return nil, fmt.Errorf("package name %q is not a valid Go identifier", opts.PackageName)
+	}
+	if opts.Provider != "" && !token.IsIdentifier(opts.Provider) {
+		return nil, fmt.Errorf("provider %q is not a valid Go identifier", opts.Provider)
+	}
+	if opts.Version < 0 {
+		return nil, fmt.Errorf("migration version must be positive, got %d", opts.Version)
+	}
+	if strings.TrimSpace(opts.OutputDir) == "" {
+		return nil, fmt.Errorf("output directory is required")
+	}
+	outputDir, err := pathguard.ResolveWithinRoot(opts.OutputDir, opts.AllowedOutputRoot)
+	if err != nil {
+		return nil, fmt.Errorf("error validating output directory: %w", err)
+	}
+
+	version := opts.Version
+	if version == 0 {
+		version = migrator.GetNextMigrationVersion()
+	}
+	funcName := fmt.Sprintf("Migration%d", version)
+	source, err := renderGoMigration(opts.PackageName, opts.Provider, funcName, version, name, opts.UpSQL, opts.DownSQL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationOutputDir(outputDir); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	path := filepath.Join(outputDir, goMigrationFileName(version, name))
+	if err := writeNewMigrationFile(path, string(source)); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("go migration file %q already exists: %w", path, err)
+		}
+		return nil, fmt.Errorf("failed to write go migration file: %w", err)
+	}
+	return &GoMigrationFile{File: path, Version: version, FuncName: funcName}, nil
+}
+
+// goMigrationFileName returns the Go file name of a migration. The fixed
+// _migration suffix keeps names such as "drop_linux" or "fix_test" from being
+// read as build constraints or test files.
+func goMigrationFileName(version int64, name string) string {
+	return strings.TrimSuffix(migrator.GenerateMigrationFileName(version, name, "up"), ".up.sql") + "_migration.go"
+}
+
+func renderGoMigration(packageName, provider, funcName string, version int64, name, upSQL, downSQL string) ([]byte, error) {
+	upConst := "migration" + strconv.FormatInt(version, 10) + "UpSQL"
+	downConst := "migration" + strconv.FormatInt(version, 10) + "DownSQL"
+
+	var b strings.Builder
+	b.WriteString("// Code generated by ptah. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	b.WriteString("import \"github.com/stokaro/ptah/migration/migrator\"\n\n")
+	fmt.Fprintf(&b, "// %s is the up SQL of migration %d (%s).\n", upConst, version, name)
+	fmt.Fprintf(&b, "const %s = %s\n\n", upConst, goStringLiteral(upSQL))
+	fmt.Fprintf(&b, "// %s is the down SQL of migration %d (%s).\n", downConst, version, name)
+	fmt.Fprintf(&b, "const %s = %s\n\n", downConst, goStringLiteral(downSQL))
+	fmt.Fprintf(&b, "// %s returns migration %d (%s).\n", funcName, version, name)
+	fmt.Fprintf(&b, "func %s() *migrator.Migration {\n", funcName)
+	fmt.Fprintf(&b, "\treturn migrator.CreateMigrationFromSQL(%d, %s, %s, %s)\n}\n", version, strconv.Quote(name), upConst, downConst)
+	if provider != "" {
+		fmt.Fprintf(&b, "\nfunc init() {\n\t%s.Register(%s())\n}\n", provider, funcName)
+	}
+
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format go migration: %w", err)
+	}
+	return source, nil
+}
+
+// goStringLiteral returns sql as a raw string literal, which keeps the SQL
+// readable in the generated file, or as an interpreted literal when sql
+// contains characters a raw literal cannot hold.
+func goStringLiteral(sql string) string {
+	if strings.ContainsAny(sql, "`\r") {
+		return strconv.Quote(sql)
+	}
+	return "`" + sql + "`"
+}
@@ -0,0 +1,202 @@
+package generator_test
+
+import (
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+// goMigrationModule writes a throwaway module that imports this checkout of
+// ptah, with a migrations package declaring Provider and a main package that
+// prints every migration registered with it. It returns the module directory
+// and its migrations directory.
+func goMigrationModule(t *testing.T) (string, string) {
+	t.Helper()
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	qt.Assert(t, err, qt.IsNil)
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	qt.Assert(t, err, qt.IsNil)
+
+	dir := t.TempDir()
+	migrationsDir := filepath.Join(dir, "migrations")
+	qt.Assert(t, os.Mkdir(migrationsDir, 0o755), qt.IsNil)
+	files := map[string]string{
+		"go.mod": "module example.com/service\n\ngo 1.26\n\n" +
+			"require github.com/stokaro/ptah v0.0.0\n\n" +
+			"replace github.com/stokaro/ptah => " + root + "\n",
+		"go.sum": string(goSum),
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/service/migrations"
+)
+
+func main() {
+	for _, m := range migrations.Provider.Migrations() {
+		fmt.Printf("%d %s %q %q\n", m.Version, m.Description, m.UpSQL, m.DownSQL)
+	}
+	fmt.Println(migrations.Migration2().Description)
+}
+`,
+		filepath.Join("migrations", "provider.go"): `package migrations
+
+import "github.com/stokaro/ptah/migration/migrator"
+
+var Provider = migrator.NewRegisteredMigrationProvider()
+`,
+	}
+	for name, content := range files {
+		qt.Assert(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600), qt.IsNil)
+	}
+	return dir, migrationsDir
+}
+
+func TestGenerateGoMigration_CompilesAndRegisters(t *testing.T) {
+	c := qt.New(t)
+	dir, migrationsDir := goMigrationModule(t)
+
+	_, err := generator.GenerateGoMigration(generator.GoMigrationOptions{
+		PackageName:   "migrations",
+		Version:       1,
+		MigrationName: "create_users",
+		UpSQL:         "CREATE TABLE users (id INTEGER PRIMARY KEY);\n",
+		DownSQL:       "DROP TABLE users;\n",
+		Provider:      "Provider",
+		OutputDir:     migrationsDir,
+	})
+	c.Assert(err, qt.IsNil)
+	_, err = generator.GenerateGoMigration(generator.GoMigrationOptions{
+		PackageName:   "migrations",
+		Version:       2,
+		MigrationName: "add_notes",
+		UpSQL:         "-- +ptah no_transaction\nALTER TABLE users ADD COLUMN `notes` TEXT;\n",
+		DownSQL:       "ALTER TABLE users DROP COLUMN notes;\n",
+		OutputDir:     migrationsDir,
+	})
+	c.Assert(err, qt.IsNil)
+
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOPROXY=off")
+	out, err := cmd.CombinedOutput()
+	c.Assert(err, qt.IsNil, qt.Commentf("%s", out))
+
+	c.Assert(string(out), qt.Equals,
+		"1 create_users \"CREATE TABLE users (id INTEGER PRIMARY KEY);\\n\" \"DROP TABLE users;\\n\"\n"+
+			"add_notes\n")
+}
+
+func TestGenerateGoMigration_WritesGofmtCleanFile(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+
+	file, err := generator.GenerateGoMigration(generator.GoMigrationOptions{
+		PackageName:   "migrations",
+		Version:       20240101120000,
+		MigrationName: "Drop Linux",
+		UpSQL:         "CREATE TABLE `quoted` (id INT);\r\n",
+		DownSQL:       "DROP TABLE quoted;\n",
+		Provider:      "Provider",
+		OutputDir:     dir,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(file.Version, qt.Equals, int64(20240101120000))
+	c.Assert(file.FuncName, qt.Equals, "Migration20240101120000")
+	c.Assert(filepath.Base(file.File), qt.Equals, "20240101120000_drop_linux_migration.go")
+
+	content, err := os.ReadFile(file.File)
+	c.Assert(err, qt.IsNil)
+	formatted, err := format.Source(content)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(content), qt.Equals, string(formatted))
+	c.Assert(string(content), qt.Equals, `// Code generated by ptah. DO NOT EDIT.
+
+package migrations
+
+import "github.com/stokaro/ptah/migration/migrator"
+
+// migration20240101120000UpSQL is the up SQL of migration 20240101120000 (Drop Linux).
+const migration20240101120000UpSQL = "CREATE TABLE `+"`quoted`"+` (id INT);\r\n"
+
+// migration20240101120000DownSQL is the down SQL of migration 20240101120000 (Drop Linux).
+const migration20240101120000DownSQL = `+"`DROP TABLE quoted;\n`"+`
+
+// Migration20240101120000 returns migration 20240101120000 (Drop Linux).
+func Migration20240101120000() *migrator.Migration {
+	return migrator.CreateMigrationFromSQL(20240101120000, "Drop Linux", migration20240101120000UpSQL, migration20240101120000DownSQL)
+}
+
+func init() {
+	Provider.Register(Migration20240101120000())
+}
+`)
+}
+
+func TestGenerateGoMigration_RefusesToOverwrite(t *testing.T) {
+	c := qt.New(t)
+	opts := generator.GoMigrationOptions{
+		PackageName:   "migrations",
+		Version:       7,
+		MigrationName: "seed",
+		UpSQL:         "SELECT 1;\n",
+		DownSQL:       "SELECT 2;\n",
+		OutputDir:     t.TempDir(),
+	}
+	first, err := generator.GenerateGoMigration(opts)
+	c.Assert(err, qt.IsNil)
+
+	_, err = generator.GenerateGoMigration(opts)
+
+	c.Assert(err, qt.ErrorIs, os.ErrExist)
+	content, err := os.ReadFile(first.File)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(content), qt.Contains, "SELECT 1;")
+}
+
+func TestGenerateGoMigration_RejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    generator.GoMigrationOptions
+		wantErr string
+	}{
+		{
+			name:    "package name",
+			opts:    generator.GoMigrationOptions{PackageName: "my-migrations", MigrationName: "init", OutputDir: "out"},
+			wantErr: `package name "my-migrations" is not a valid Go identifier`,
+		},
+		{
+			name:    "provider",
+			opts:    generator.GoMigrationOptions{PackageName: "migrations", MigrationName: "init", Provider: "pkg.Provider", OutputDir: "out"},
+			wantErr: `provider "pkg.Provider" is not a valid Go identifier`,
+		},
+		{
+			name:    "migration name",
+			opts:    generator.GoMigrationOptions{PackageName: "migrations", OutputDir: "out"},
+			wantErr: `migration name is required`,
+		},
+		{
+			name:    "version",
+			opts:    generator.GoMigrationOptions{PackageName: "migrations", MigrationName: "init", Version: -1, OutputDir: "out"},
+			wantErr: `migration version must be positive, got -1`,
+		},
+		{
+			name:    "output dir",
+			opts:    generator.GoMigrationOptions{PackageName: "migrations", MigrationName: "init"},
+			wantErr: `output directory is required`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.GenerateGoMigration(tt.opts)
+			qt.Assert(t, err, qt.ErrorMatches, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package generator
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/migration/diffpolicy"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+// ptahModulePath is the module path looked up in the build information for
+// the ptah version written to generated file headers.
+const ptahModulePath = "github.com/stokaro/ptah"
+
+// Header is the structured metadata a generated migration file declares in
+// its `-- ptah:key=value` header lines.
+type Header struct {
+	// PtahVersion is the ptah version that generated the file.
+	PtahVersion string
+	// SourceSHA is the git commit of the Go entities, or "" when it was not
+	// provided.
+	SourceSHA string
+	// Dialect is the dialect the file's SQL targets.
+	Dialect string
+	// OptionsHash is a SHA-256 hex digest of the generation options that
+	// shape the SQL. Files generated with the same options share it.
+	OptionsHash string
+	// Values holds every ptah: header line by key, including keys this
+	// version of ptah does not know.
+	Values map[string]string
+}
+
+// ReadMigrationHeader reads the migration file at path and parses its
+// `-- ptah:key=value` header lines. A file without them, such as one written
+// by hand or by an older ptah, yields a Header with only an empty Values map.
+func ReadMigrationHeader(path string) (Header, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Header{}, fmt.Errorf("error reading migration header: %w", err)
+	}
+	values := migrator.ParseFileMetadata(string(data))
+	return Header{
+		PtahVersion: values[migrator.MetadataPtahVersion],
+		SourceSHA:   values[migrator.MetadataSourceSHA],
+		Dialect:     values[migrator.MetadataDialect],
+		OptionsHash: values[migrator.MetadataOptionsHash],
+		Values:      values,
+	}, nil
+}
+
+// lines renders the header as comment lines in a fixed order. Empty fields
+// are omitted.
+func (h Header) lines() string {
+	var out strings.Builder
+	for _, field := range []struct{ key, value string }{
+		{migrator.MetadataPtahVersion, h.PtahVersion},
+		{migrator.MetadataSourceSHA, h.SourceSHA},
+		{migrator.MetadataDialect, h.Dialect},
+		{migrator.MetadataOptionsHash, h.OptionsHash},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&out, "-- ptah:%s=%s\n", field.key, field.value)
+		}
+	}
+	return out.String()
+}
+
+// generatedHeader returns the header written into files generated with opts
+// for dialect.
+func generatedHeader(opts GenerateMigrationOptions, dialect string) Header {
+	return Header{
+		PtahVersion: cmp.Or(opts.PtahVersion, buildPtahVersion()),
+		SourceSHA:   opts.SourceSHA,
+		Dialect:     dialect,
+		OptionsHash: generationOptionsHash(opts),
+	}
+}
+
+// buildPtahVersion returns the ptah module version recorded in the running
+// binary, or "dev" when it is unknown, as in tests and local builds.
+func buildPtahVersion() string {
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	version := ""
+	if build.Main.Path == ptahModulePath {
+		version = build.Main.Version
+	}
+	for _, dep := range build.Deps {
+		if dep.Path == ptahModulePath {
+			version = dep.Version
+		}
+	}
+	if version == "" || version == "(devel)" {
+		return "dev"
+	}
+	return version
+}
+
+// generationOptions lists the GenerateMigrationOptions that shape the
+// generated SQL. Function-valued options are recorded by presence only.
+type generationOptions struct {
+	TargetDialect               string                  `json:"target_dialect,omitempty"`
+	Schemas                     []string                `json:"schemas,omitempty"`
+	SkipChangeKinds             []diffpolicy.ChangeKind `json:"skip_change_kinds,omitempty"`
+	ConcurrentIndex             bool                    `json:"concurrent_index,omitempty"`
+	SafeNotNull                 bool                    `json:"safe_not_null,omitempty"`
+	SafeTypeChange              bool                    `json:"safe_type_change,omitempty"`
+	SafeVolatileDefault         bool                    `json:"safe_volatile_default,omitempty"`
+	TwoStepConstraintValidation bool                    `json:"two_step_constraint_validation,omitempty"`
+	SplitValidation             bool                    `json:"split_validation,omitempty"`
+	SplitStrategy               SplitStrategy           `json:"split_strategy,omitempty"`
+	StatementFilter             bool                    `json:"statement_filter,omitempty"`
+	CustomStatementGenerators   int                     `json:"custom_statement_generators,omitempty"`
+	MySQLOnlineDDL              bool                    `json:"mysql_online_ddl,omitempty"`
+	CascadeCyclicTableDrops     bool                    `json:"cascade_cyclic_table_drops,omitempty"`
+	SessionSettings             map[string]string       `json:"session_settings,omitempty"`
+	ScaffoldDataMigration       bool                    `json:"scaffold_data_migration,omitempty"`
+	GuardedRollback             bool                    `json:"guarded_rollback,omitempty"`
+}
+
+// generationOptionsHash returns the SHA-256 hex digest of the options in opts
+// that shape the generated SQL.
+func generationOptionsHash(opts GenerateMigrationOptions) string {
+	schemas := slices.Clone(opts.Schemas)
+	slices.Sort(schemas)
+	// Every field is a plain value, so marshaling cannot fail.
+	data, _ := json.Marshal(generationOptions{
+		TargetDialect:               opts.TargetDialect,
+		Schemas:                     schemas,
+		SkipChangeKinds:             opts.DiffPolicy.SkipChangeKinds,
+		ConcurrentIndex:             opts.DiffPolicy.ConcurrentIndex,
+		SafeNotNull:                 opts.SafeNotNull,
+		SafeTypeChange:              opts.SafeTypeChange,
+		SafeVolatileDefault:         opts.SafeVolatileDefault,
+		TwoStepConstraintValidation: opts.TwoStepConstraintValidation,
+		SplitValidation:             opts.SplitValidation,
+		SplitStrategy:               opts.SplitStrategy,
+		StatementFilter:             opts.StatementFilter != nil,
+		CustomStatementGenerators:   len(opts.CustomStatementGenerators),
+		MySQLOnlineDDL:              opts.MySQLOnlineDDL,
+		CascadeCyclicTableDrops:     opts.CascadeCyclicTableDrops,
+		SessionSettings:             opts.SessionSettings,
+		ScaffoldDataMigration:       opts.ScaffoldDataMigration,
+		GuardedRollback:             opts.GuardedRollback,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// withHeader writes header into every spec's up and down SQL at the end of
+// the opening comment block, after the Direction line.
+func withHeader(specs []generatedMigrationSpec, header Header) {
+	lines := header.lines()
+	for i := range specs {
+		specs[i].UpSQL = insertHeaderLines(specs[i].UpSQL, lines)
+		specs[i].DownSQL = insertHeaderLines(specs[i].DownSQL, lines)
+	}
+}
+
+// insertHeaderLines inserts lines before the blank line that closes the
+// opening comment block of sql, or at its end when there is none.
+func insertHeaderLines(sql, lines string) string {
+	if before, after, ok := strings.Cut(sql, "\n\n"); ok {
+		return before + "\n" + lines + "\n" + after
+	}
+	if sql != "" && !strings.HasSuffix(sql, "\n") {
+		sql += "\n"
+	}
+	return sql + lines
+}
@@ -0,0 +1,82 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+func generateHeaderFixture(c *qt.C, opts generator.GenerateMigrationOptions) *generator.MigrationFiles {
+	c.Helper()
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "setting.go"), []byte(sqliteTableDropModel), 0o600), qt.IsNil)
+	opts.GoEntitiesDir = modelsDir
+	opts.DBConn = dbschematest.NewFakeConnection(c.TB, platform.Postgres, nil).DatabaseConnection
+	opts.OutputDir = filepath.Join(tempDir, "migrations")
+	files, err := generator.GenerateMigration(context.Background(), opts)
+	c.Assert(err, qt.IsNil)
+	return files
+}
+
+func TestGenerateMigration_WritesHeaderMetadata(t *testing.T) {
+	c := qt.New(t)
+	files := generateHeaderFixture(c, generator.GenerateMigrationOptions{
+		MigrationName: "settings",
+		PtahVersion:   "v1.2.3",
+		SourceSHA:     "0123abcd",
+	})
+
+	up, err := generator.ReadMigrationHeader(files.UpFile)
+	c.Assert(err, qt.IsNil)
+	down, err := generator.ReadMigrationHeader(files.DownFile)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(up.PtahVersion, qt.Equals, "v1.2.3")
+	c.Assert(up.SourceSHA, qt.Equals, "0123abcd")
+	c.Assert(up.Dialect, qt.Equals, platform.Postgres)
+	c.Assert(up.OptionsHash, qt.HasLen, 64)
+	c.Assert(up.Values, qt.HasLen, 4)
+	c.Assert(down, qt.DeepEquals, up)
+	upSQL, err := os.ReadFile(files.UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, "-- Direction: UP\n-- ptah:version=v1.2.3\n-- ptah:source_sha=0123abcd\n-- ptah:dialect=postgres\n")
+}
+
+func TestGenerateMigration_HeaderOptionsHash(t *testing.T) {
+	c := qt.New(t)
+	readHash := func(opts generator.GenerateMigrationOptions) string {
+		opts.MigrationName = "settings"
+		header, err := generator.ReadMigrationHeader(generateHeaderFixture(c, opts).UpFile)
+		c.Assert(err, qt.IsNil)
+		return header.OptionsHash
+	}
+
+	defaults := readHash(generator.GenerateMigrationOptions{})
+
+	c.Assert(readHash(generator.GenerateMigrationOptions{SourceSHA: "0123abcd"}), qt.Equals, defaults)
+	c.Assert(readHash(generator.GenerateMigrationOptions{SafeNotNull: true}), qt.Not(qt.Equals), defaults)
+	c.Assert(readHash(generator.GenerateMigrationOptions{GuardedRollback: true}), qt.Not(qt.Equals), defaults)
+}
+
+func TestReadMigrationHeader_WithoutMetadata(t *testing.T) {
+	c := qt.New(t)
+	path := filepath.Join(c.TempDir(), "0001_manual.up.sql")
+	c.Assert(os.WriteFile(path, []byte("-- written by hand\nCREATE TABLE users (id INTEGER);\n"), 0o600), qt.IsNil)
+
+	header, err := generator.ReadMigrationHeader(path)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(header, qt.DeepEquals, generator.Header{Values: map[string]string{}})
+
+	_, err = generator.ReadMigrationHeader(filepath.Join(c.TempDir(), "missing.up.sql"))
+	c.Assert(err, qt.ErrorMatches, "error reading migration header: .*")
+}
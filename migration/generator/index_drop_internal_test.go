@@ -0,0 +1,49 @@
+package generator
+
+// White-box testing required: MySQL cannot run in the test environment, so
+// the down migration is checked on the unexported SQL generation helper.
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func TestGenerateMigrationSQL_MySQLIndexDropsKnowTheirTable(t *testing.T) {
+	c := qt.New(t)
+	target := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{
+			{Name: "id", Type: "INT", StructName: "User", Primary: true},
+			{Name: "email", Type: "VARCHAR(255)", StructName: "User", Nullable: true},
+		},
+		Indexes: []goschema.Index{{Name: "idx_users_email", StructName: "User", Fields: []string{"email"}}},
+	}
+	goschema.Finalize(target)
+	database := &dbschematypes.DBSchema{
+		Tables: []dbschematypes.DBTable{
+			{Name: "users", Columns: []dbschematypes.DBColumn{
+				{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", IsNullable: "YES"},
+			}},
+			{Name: "sessions", Columns: []dbschematypes.DBColumn{
+				{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+			}},
+		},
+		Indexes: []dbschematypes.DBIndex{{Name: "idx_sessions_id", TableName: "sessions", Columns: []string{"id"}}},
+	}
+	diff := schemadiff.CompareWithDialect(target, database, "mysql")
+
+	upSQL, err := generateUpMigrationSQL(diff, target, "mysql")
+	c.Assert(err, qt.IsNil)
+	c.Assert(upSQL, qt.Contains, "DROP TABLE IF EXISTS `sessions`;")
+	c.Assert(upSQL, qt.Not(qt.Contains), "idx_sessions_id")
+
+	downSQL, err := generateDownMigrationSQL(diff, target, database, "mysql")
+	c.Assert(err, qt.IsNil)
+	c.Assert(downSQL, qt.Contains, "DROP INDEX `idx_users_email` ON `users`;")
+}
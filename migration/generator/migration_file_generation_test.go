@@ -370,3 +370,56 @@ func TestWithGeneratedTimeoutDirectives_UnsupportedDialect(t *testing.T) {
 	got = withGeneratedTimeoutDirectives(sql, "spanner")
 	c.Assert(got, qt.Equals, sql)
 }
+
+func TestWithSessionSettings(t *testing.T) {
+	c := qt.New(t)
+
+	specs := []generatedMigrationSpec{{
+		UpSQL:   "-- Migration generated from schema differences\n-- Direction: UP\n-- +ptah lock_timeout=3s\n\nALTER TABLE users ADD COLUMN email TEXT;",
+		DownSQL: "-- Migration rollback\n-- Direction: DOWN\n\n-- No rollback operations needed\n",
+	}}
+	err := withSessionSettings(specs, "postgres", map[string]string{"statement_timeout": "10min", "lock_timeout": "5s"})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs[0].UpSQL, qt.Equals, "-- Migration generated from schema differences\n-- Direction: UP\n-- +ptah lock_timeout=3s\n\n"+
+		"SET LOCAL lock_timeout = '5s';\nSET LOCAL statement_timeout = '10min';\n\nALTER TABLE users ADD COLUMN email TEXT;")
+	c.Assert(specs[0].DownSQL, qt.Equals, "-- Migration rollback\n-- Direction: DOWN\n\n-- No rollback operations needed\n")
+}
+
+func TestWithSessionSettings_MySQLRestoresPreviousValues(t *testing.T) {
+	c := qt.New(t)
+
+	specs := []generatedMigrationSpec{{
+		UpSQL:   "-- Direction: UP\n\nALTER TABLE users ADD COLUMN email TEXT;\n",
+		DownSQL: "-- Direction: DOWN\n\nALTER TABLE users DROP COLUMN email;\n",
+	}}
+	err := withSessionSettings(specs, "mysql", map[string]string{"lock_timeout": "5s"})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs[0].UpSQL, qt.Equals, "-- Direction: UP\n\n"+
+		"SET @ptah_file_prev_lock_wait_timeout = @@SESSION.lock_wait_timeout;\nSET SESSION lock_wait_timeout = 5;\n\n"+
+		"ALTER TABLE users ADD COLUMN email TEXT;\n\n"+
+		"SET SESSION lock_wait_timeout = @ptah_file_prev_lock_wait_timeout;\n")
+	c.Assert(specs[0].DownSQL, qt.Contains, "ALTER TABLE users DROP COLUMN email;\n\nSET SESSION lock_wait_timeout = @ptah_file_prev_lock_wait_timeout;\n")
+}
+
+func TestWithSessionSettings_SkipsNoTransactionFiles(t *testing.T) {
+	c := qt.New(t)
+
+	upSQL := "-- +ptah no_transaction\nCREATE INDEX CONCURRENTLY idx_users_email ON users (email);\n"
+	specs := []generatedMigrationSpec{{UpSQL: upSQL, NoTransaction: true}}
+	err := withSessionSettings(specs, "postgres", map[string]string{"lock_timeout": "5s"})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs[0].UpSQL, qt.Equals, upSQL)
+}
+
+func TestWithSessionSettings_UnsupportedDialect(t *testing.T) {
+	c := qt.New(t)
+
+	specs := []generatedMigrationSpec{{UpSQL: "CREATE TABLE t (id INTEGER);"}}
+	err := withSessionSettings(specs, "sqlite", map[string]string{"lock_timeout": "5s"})
+
+	c.Assert(err, qt.ErrorMatches, `error rendering session settings: session settings are not supported for dialect "sqlite"`)
+	c.Assert(specs[0].UpSQL, qt.Equals, "CREATE TABLE t (id INTEGER);")
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/stokaro/ptah/core/sqlutil"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/safety"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// WarningKind classifies a Warning.
+type WarningKind string
+
+const (
+	// WarningDestructive is a statement that drops objects or data.
+	WarningDestructive WarningKind = "destructive"
+	// WarningRisky is a statement the safety rules flag as risky but not
+	// destructive, such as a type change or a blocking index build.
+	WarningRisky WarningKind = "risky"
+	// WarningManualChange is a column change written as a MANUAL comment
+	// because no ALTER statement can apply it.
+	WarningManualChange WarningKind = "manual_change"
+	// WarningSkippedFeature is a schema object left out because the target
+	// dialect cannot express it.
+	WarningSkippedFeature WarningKind = "skipped_feature"
+)
+
+// Warning is one issue a generated migration carries that a reviewer or
+// pipeline should look at.
+type Warning struct {
+	Kind WarningKind `json:"kind"`
+	// Version is the migration the warning belongs to. It is 0 for skipped
+	// features and manual changes, which apply to the whole plan.
+	Version int64  `json:"version,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Message string `json:"message"`
+}
+
+// MigrationResult is the detailed outcome of GenerateMigrationDetailed.
+type MigrationResult struct {
+	// Files lists the written migration files. It is nil when the schema has
+	// no changes.
+	Files *MigrationFiles
+	// Statements and DownStatements count the SQL statements, not comments,
+	// across all up and down migration files.
+	Statements     int
+	DownStatements int
+	// Warnings lists destructive and risky statements, manual changes, and
+	// skipped dialect features, in migration order.
+	Warnings []Warning
+	// Destructive reports whether any up migration drops objects or data.
+	Destructive bool
+	// Diff is the schema difference the migrations were planned from.
+	Diff *types.SchemaDiff
+}
+
+// HasChanges reports whether migration files were written.
+func (r *MigrationResult) HasChanges() bool {
+	return r != nil && r.Files != nil
+}
+
+func newMigrationResult(
+	files *MigrationFiles,
+	specs []generatedMigrationSpec,
+	diff *types.SchemaDiff,
+	skipped []planner.SkippedFeature,
+	manual []planner.ManualChange,
+) *MigrationResult {
+	result := &MigrationResult{Files: files, Diff: diff}
+	if files != nil {
+		for _, pair := range files.Files {
+			result.Statements += pair.Statements
+			result.DownStatements += pair.DownStatements
+		}
+	}
+	for _, spec := range specs {
+		for _, assessment := range spec.Assessments {
+			kind := WarningRisky
+			switch assessment.Severity {
+			case safety.Safe:
+				continue
+			case safety.Destructive:
+				kind = WarningDestructive
+				result.Destructive = true
+			}
+			result.Warnings = append(result.Warnings, Warning{
+				Kind:    kind,
+				Version: spec.Version,
+				Subject: assessment.Subject,
+				Message: assessment.Reason,
+			})
+		}
+	}
+	for _, change := range manual {
+		result.Warnings = append(result.Warnings, Warning{
+			Kind:    WarningManualChange,
+			Subject: change.Table + "." + change.Column,
+			Message: change.String(),
+		})
+	}
+	for _, feature := range skipped {
+		result.Warnings = append(result.Warnings, Warning{
+			Kind:    WarningSkippedFeature,
+			Subject: feature.Object,
+			Message: feature.String(),
+		})
+	}
+	return result
+}
+
+// countSQLStatements counts the statements in sql that are not only comments.
+func countSQLStatements(sql string) int {
+	count := 0
+	for _, statement := range sqlutil.SplitSQLStatements(sql) {
+		if strings.TrimSpace(sqlutil.StripComments(statement)) != "" {
+			count++
+		}
+	}
+	return count
+}
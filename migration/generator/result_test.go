@@ -0,0 +1,68 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+func generateDetailed(c *qt.C, tables []types.DBTable) (*generator.MigrationResult, error) {
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "setting.go"), []byte(sqliteTableDropModel), 0o600), qt.IsNil)
+	fake := dbschematest.NewFakeConnection(c.TB, platform.Postgres, &types.DBSchema{Tables: tables})
+
+	return generator.GenerateMigrationDetailed(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        fake.DatabaseConnection,
+		MigrationName: "replace_legacy",
+		OutputDir:     filepath.Join(tempDir, "migrations"),
+	})
+}
+
+func TestGenerateMigrationDetailed_ReportsCountsAndWarnings(t *testing.T) {
+	c := qt.New(t)
+
+	result, err := generateDetailed(c, []types.DBTable{{Name: "legacy", Type: "TABLE", Columns: []types.DBColumn{
+		{Name: "id", DataType: "integer", UDTName: "int4", IsPrimaryKey: true},
+	}}})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(result.HasChanges(), qt.IsTrue)
+	c.Assert(result.Files.Files, qt.HasLen, 1)
+	c.Assert(result.Statements, qt.Equals, 2)
+	c.Assert(result.Files.Files[0].Statements, qt.Equals, 2)
+	c.Assert(result.DownStatements, qt.Equals, result.Files.Files[0].DownStatements)
+	c.Assert(result.DownStatements > 0, qt.IsTrue)
+	c.Assert(result.Destructive, qt.IsTrue)
+	c.Assert(result.Warnings, qt.HasLen, 1)
+	c.Assert(result.Warnings[0].Kind, qt.Equals, generator.WarningDestructive)
+	c.Assert(result.Warnings[0].Subject, qt.Equals, "legacy")
+	c.Assert(result.Warnings[0].Version, qt.Equals, result.Files.Version)
+	c.Assert(result.Diff.TablesAdded, qt.DeepEquals, []string{"settings"})
+	c.Assert(result.Diff.TablesRemoved, qt.DeepEquals, []string{"legacy"})
+}
+
+func TestGenerateMigrationDetailed_NoChanges(t *testing.T) {
+	c := qt.New(t)
+
+	result, err := generateDetailed(c, []types.DBTable{{Name: "settings", Type: "TABLE", Columns: []types.DBColumn{
+		{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+	}}})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(result.HasChanges(), qt.IsFalse)
+	c.Assert(result.Files, qt.IsNil)
+	c.Assert(result.Diff, qt.IsNotNil)
+	c.Assert(result.Diff.HasChanges(), qt.IsFalse)
+	c.Assert(result.Warnings, qt.HasLen, 0)
+}
@@ -514,6 +514,94 @@ func TestReverseSchemaDiff_TableModifications(t *testing.T) {
 	c.Assert(reversedColumn.Changes["type"], qt.Equals, "VARCHAR(255) -> VARCHAR(100)")
 }
 
+func TestGenerateDownMigrationSQL_RestoresPriorComments(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", `package models
+
+//migrator:schema:table name="docs" comment="Published documents"
+type Doc struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="title" type="TEXT" not_null="true" comment="Display title"
+	Title string
+}
+`)
+	c.Assert(err, qt.IsNil)
+	dbSchema := &dbschematypes.DBSchema{Tables: []dbschematypes.DBTable{{
+		Name: "docs",
+		Type: "BASE TABLE",
+		Columns: []dbschematypes.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "title", DataType: "text", UDTName: "text", IsNullable: "NO", Comment: "Old title"},
+		},
+	}}}
+	diff := schemadiff.CompareWithDialect(&generated, dbSchema, "postgres")
+
+	downSQL, err := generateDownMigrationSQL(diff, &generated, dbSchema, "postgres")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(downSQL, qt.Contains, `COMMENT ON COLUMN "docs"."title" IS 'Old title';`)
+	c.Assert(downSQL, qt.Contains, `COMMENT ON TABLE "docs" IS NULL;`)
+	c.Assert(downSQL, qt.Not(qt.Contains), "ALTER COLUMN")
+}
+
+func TestGenerateDownMigrationSQL_RestoresPriorTableOwner(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", `package models
+
+//migrator:schema:table name="docs" owner="app_owner"
+type Doc struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+}
+`)
+	c.Assert(err, qt.IsNil)
+	dbSchema := &dbschematypes.DBSchema{Tables: []dbschematypes.DBTable{{
+		Name:  "docs",
+		Type:  "BASE TABLE",
+		Owner: "deployer",
+		Columns: []dbschematypes.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+		},
+	}}}
+	diff := schemadiff.CompareWithDialect(&generated, dbSchema, "postgres")
+
+	downSQL, err := generateDownMigrationSQL(diff, &generated, dbSchema, "postgres")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(downSQL, qt.Contains, `ALTER TABLE "docs" OWNER TO "deployer";`)
+}
+
+func TestGenerateDownMigrationSQL_RestoresSerialDefaultAfterIdentity(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="id" type="BIGINT" primary="true" identity_generation="always"
+	ID int64
+}
+`)
+	c.Assert(err, qt.IsNil)
+	serialDefault := "nextval('orders_id_seq'::regclass)"
+	dbSchema := &dbschematypes.DBSchema{Tables: []dbschematypes.DBTable{{
+		Name: "orders",
+		Type: "BASE TABLE",
+		Columns: []dbschematypes.DBColumn{{
+			Name: "id", DataType: "bigint", UDTName: "int8", IsNullable: "NO", IsPrimaryKey: true,
+			IsAutoIncrement: true, ColumnDefault: &serialDefault,
+		}},
+	}}}
+	diff := schemadiff.CompareWithDialect(&generated, dbSchema, "postgres")
+
+	downSQL, err := generateDownMigrationSQL(diff, &generated, dbSchema, "postgres")
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(downSQL, qt.Contains, `ALTER TABLE "orders" ALTER COLUMN "id" DROP IDENTITY IF EXISTS;
+ALTER TABLE "orders" ALTER COLUMN "id" SET DEFAULT nextval('orders_id_seq'::regclass);`)
+}
+
 func TestReverseSchemaDiff_EnumModifications(t *testing.T) {
 	c := qt.New(t)
 
@@ -538,6 +626,24 @@ func TestReverseSchemaDiff_EnumModifications(t *testing.T) {
 	c.Assert(reversedEnum.ValuesRemoved, qt.DeepEquals, []string{"pending", "archived"})
 }
 
+func TestReverseSchemaDiff_EnumRenames(t *testing.T) {
+	c := qt.New(t)
+
+	input := &types.SchemaDiff{
+		EnumsRenamed: []types.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+		EnumsModified: []types.EnumDiff{
+			{EnumName: "order_status", ValuesAdded: []string{"refunded"}},
+		},
+	}
+
+	result := reverseSchemaDiff(input)
+
+	c.Assert(result.EnumsRenamed, qt.DeepEquals, []types.EnumRename{{OldName: "order_status", NewName: "order_state"}})
+	c.Assert(result.EnumsModified, qt.HasLen, 1)
+	c.Assert(result.EnumsModified[0].EnumName, qt.Equals, "order_state")
+	c.Assert(result.EnumsModified[0].ValuesRemoved, qt.DeepEquals, []string{"refunded"})
+}
+
 func TestReverseSchemaDiff_FunctionModifications(t *testing.T) {
 	c := qt.New(t)
 
@@ -628,6 +734,20 @@ func TestReverseSchemaDiff_RoleModifications(t *testing.T) {
 	c.Assert(reversedRole.Changes["password"], qt.Equals, "new_hash -> old_hash")
 }
 
+func TestReverseSchemaDiff_RoleMemberships(t *testing.T) {
+	c := qt.New(t)
+
+	input := &types.SchemaDiff{
+		RoleMembershipsAdded:   []types.RoleMembership{{Role: "app_readonly", Member: "app_user"}},
+		RoleMembershipsRemoved: []types.RoleMembership{{Role: "dba_granted", Member: "app_user"}},
+	}
+
+	result := reverseSchemaDiff(input)
+
+	c.Assert(result.RoleMembershipsAdded, qt.DeepEquals, []types.RoleMembership{{Role: "dba_granted", Member: "app_user"}})
+	c.Assert(result.RoleMembershipsRemoved, qt.DeepEquals, []types.RoleMembership{{Role: "app_readonly", Member: "app_user"}})
+}
+
 func TestConvertRLSPolicyRefsToNames(t *testing.T) {
 	c := qt.New(t)
 
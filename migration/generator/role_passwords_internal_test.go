@@ -0,0 +1,33 @@
+package generator
+
+// White-box testing required: roles need a PostgreSQL server, so the
+// password stripping applied under CompareOptions.SkipRolePasswords is
+// checked on the unexported helper together with the planner output.
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestWithoutRolePasswords(t *testing.T) {
+	c := qt.New(t)
+	generated := &goschema.Database{Roles: []goschema.Role{
+		{Name: "app_user", Login: true, PasswordEnv: "APP_USER_PASSWORD"},
+		{Name: "reporter", Password: "SCRAM-SHA-256$4096:c2FsdA==$a2V5:c2VydmVy"},
+	}}
+
+	stripped := withoutRolePasswords(generated)
+	sql, err := planner.GenerateSchemaDiffSQL(&types.SchemaDiff{RolesAdded: []string{"app_user", "reporter"}}, stripped, platform.Postgres)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, `CREATE ROLE "app_user" WITH LOGIN NOSUPERUSER`)
+	c.Assert(sql, qt.Not(qt.Contains), "PASSWORD")
+	c.Assert(generated.Roles[0].PasswordEnv, qt.Equals, "APP_USER_PASSWORD")
+	c.Assert(generated.Roles[1].Password, qt.Not(qt.Equals), "")
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// Kinds of schema objects other than tables and columns that a down
+// migration drops. The kind names the object in the guard comment.
+const (
+	guardIndex            = "index"
+	guardView             = "view"
+	guardMaterializedView = "materialized view"
+	guardSequence         = "sequence"
+	guardType             = "type"
+	guardConstraint       = "constraint"
+	guardTrigger          = "trigger"
+	guardFunction         = "function"
+	guardPolicy           = "policy"
+	guardExtension        = "extension"
+	guardRole             = "role"
+)
+
+// rollbackGuardObject is an object that a down migration drops and must
+// therefore find in place before it starts. An empty Kind is a table, or a
+// column of one; the other kinds carry their name in Name and, for
+// constraints and triggers, their table in Table.
+type rollbackGuardObject struct {
+	Kind   string
+	Name   string
+	Table  string
+	Column string
+}
+
+// withRollbackGuards writes a precondition for every object the down
+// migration drops ahead of its first statement, so a rollback against an
+// unexpected schema stops before changing anything. Only the PostgreSQL
+// family can check objects other than tables and columns, so the other
+// dialects guard those two kinds alone. Down SQL without statements is left
+// alone.
+func withRollbackGuards(downSQL string, diff *types.SchemaDiff, dialect string) string {
+	var block strings.Builder
+	for _, object := range rollbackGuardObjects(diff) {
+		if object.Kind != "" && !platform.IsPostgresFamily(dialect) {
+			continue
+		}
+		block.WriteString(rollbackGuard(object, dialect))
+		block.WriteString("\n\n")
+	}
+	if block.Len() == 0 {
+		return downSQL
+	}
+	return insertAfterLeadingComments(downSQL, block.String())
+}
+
+// rollbackGuardObjects lists the objects the up migration adds, which are the
+// ones its down migration drops: new tables, new columns of existing tables,
+// and then the other new objects kind by kind.
+func rollbackGuardObjects(diff *types.SchemaDiff) []rollbackGuardObject {
+	var objects []rollbackGuardObject
+	for _, table := range diff.TablesAdded {
+		objects = append(objects, rollbackGuardObject{Table: table})
+	}
+	for _, tableDiff := range diff.TablesModified {
+		for _, column := range tableDiff.ColumnsAdded {
+			objects = append(objects, rollbackGuardObject{Table: tableDiff.TableName, Column: column})
+		}
+	}
+	named := func(kind string, names ...[]string) {
+		for _, list := range names {
+			for _, name := range list {
+				objects = append(objects, rollbackGuardObject{Kind: kind, Name: name})
+			}
+		}
+	}
+	named(guardIndex, diff.IndexesAdded)
+	named(guardView, diff.ViewsAdded)
+	named(guardMaterializedView, diff.MaterializedViewsAdded)
+	named(guardSequence, diff.SequencesAdded)
+	named(guardType, diff.EnumsAdded, diff.DomainsAdded, diff.CompositeTypesAdded, diff.RangesAdded)
+	constraintTables := make(map[string]string, len(diff.ConstraintsAddedWithTables))
+	for _, constraint := range diff.ConstraintsAddedWithTables {
+		constraintTables[constraint.Name] = constraint.TableName
+	}
+	for _, name := range diff.ConstraintsAdded {
+		objects = append(objects, rollbackGuardObject{Kind: guardConstraint, Name: name, Table: constraintTables[name]})
+	}
+	for _, trigger := range diff.TriggersAdded {
+		objects = append(objects, rollbackGuardObject{Kind: guardTrigger, Name: trigger.TriggerName, Table: trigger.TableName})
+	}
+	named(guardFunction, diff.FunctionsAdded)
+	named(guardPolicy, diff.RLSPoliciesAdded)
+	named(guardExtension, diff.ExtensionsAdded)
+	named(guardRole, diff.RolesAdded)
+	return objects
+}
+
+// description names object in the guard comment and exception message.
+func (object rollbackGuardObject) description() string {
+	switch {
+	case object.Kind == "" && object.Column != "":
+		return "column " + object.Table + "." + object.Column
+	case object.Kind == "":
+		return "table " + object.Table
+	case object.Table != "":
+		return object.Kind + " " + object.Table + "." + object.Name
+	default:
+		return object.Kind + " " + object.Name
+	}
+}
+
+// postgresMissing returns the PostgreSQL condition that holds when object
+// does not exist. Schema-qualified names are looked up in that schema,
+// unqualified ones through the search_path.
+func (object rollbackGuardObject) postgresMissing() string {
+	regclass := func(name string) string {
+		return "to_regclass(" + guardLiteral(guardIdentifierPath(name, `"`, `"`)) + ")"
+	}
+	switch object.Kind {
+	case "":
+		if object.Column == "" {
+			return regclass(object.Table) + " IS NULL"
+		}
+		return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM pg_attribute WHERE attrelid = %s AND attname = %s AND NOT attisdropped)",
+			regclass(object.Table), guardLiteral(object.Column))
+	case guardType:
+		return "to_regtype(" + guardLiteral(guardIdentifierPath(object.Name, `"`, `"`)) + ") IS NULL"
+	case guardConstraint:
+		return guardCatalogMissing("pg_constraint", "conname", object.Name, "conrelid", object.Table)
+	case guardTrigger:
+		return guardCatalogMissing("pg_trigger", "tgname", object.Name, "tgrelid", object.Table)
+	case guardPolicy:
+		return guardCatalogMissing("pg_policy", "polname", unqualifiedName(object.Name), "", "")
+	case guardFunction:
+		schema, name, qualified := strings.Cut(object.Name, ".")
+		if !qualified {
+			return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM pg_proc WHERE proname = %s AND pg_function_is_visible(oid))", guardLiteral(object.Name))
+		}
+		return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM pg_proc WHERE proname = %s AND pronamespace = to_regnamespace(%s))",
+			guardLiteral(name), guardLiteral(guardIdentifierPath(schema, `"`, `"`)))
+	case guardExtension:
+		return guardCatalogMissing("pg_extension", "extname", object.Name, "", "")
+	case guardRole:
+		return guardCatalogMissing("pg_roles", "rolname", object.Name, "", "")
+	default:
+		// Indexes, views, materialized views and sequences are relations.
+		return regclass(object.Name) + " IS NULL"
+	}
+}
+
+// guardCatalogMissing returns a NOT EXISTS lookup of name in catalog,
+// restricted to the relation table through relationColumn when both are set.
+func guardCatalogMissing(catalog, nameColumn, name, relationColumn, table string) string {
+	condition := nameColumn + " = " + guardLiteral(name)
+	if relationColumn != "" && table != "" {
+		condition = fmt.Sprintf("%s = to_regclass(%s) AND %s", relationColumn, guardLiteral(guardIdentifierPath(table, `"`, `"`)), condition)
+	}
+	return fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %s WHERE %s)", catalog, condition)
+}
+
+func unqualifiedName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// rollbackGuard renders the precondition for object. PostgreSQL raises a
+// descriptive exception from a DO block. Dialects without anonymous
+// procedural blocks select the object with no rows instead, which fails with
+// the server's own "unknown table" or "unknown column" error.
+func rollbackGuard(object rollbackGuardObject, dialect string) string {
+	description := object.description()
+	comment := fmt.Sprintf("-- Rollback precondition: %s must exist\n", description)
+
+	if platform.IsPostgresFamily(dialect) {
+		return comment + fmt.Sprintf("DO $$\nBEGIN\n    IF %s THEN\n        RAISE EXCEPTION %s;\n    END IF;\nEND $$;",
+			object.postgresMissing(), guardLiteral("rollback precondition failed: "+description+" does not exist"))
+	}
+
+	open, closing := `"`, `"`
+	switch platform.NormalizeDialect(dialect) {
+	case platform.MySQL, platform.MariaDB, platform.ClickHouse:
+		open, closing = "`", "`"
+	case platform.SQLServer:
+		open, closing = "[", "]"
+	}
+	selected := "*"
+	if object.Column != "" {
+		selected = guardIdentifierPath(object.Column, open, closing)
+	}
+	table := guardIdentifierPath(object.Table, open, closing)
+	if platform.NormalizeDialect(dialect) == platform.SQLServer {
+		return comment + fmt.Sprintf("SELECT TOP 0 %s FROM %s;", selected, table)
+	}
+	return comment + fmt.Sprintf("SELECT %s FROM %s LIMIT 0;", selected, table)
+}
+
+// guardIdentifierPath quotes every dot-separated part of name.
+func guardIdentifierPath(name, open, closing string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = open + strings.ReplaceAll(part, closing, closing+closing) + closing
+	}
+	return strings.Join(parts, ".")
+}
+
+func guardLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
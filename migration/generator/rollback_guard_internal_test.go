@@ -0,0 +1,119 @@
+package generator
+
+// White-box testing required: the PostgreSQL and SQL Server guards need a
+// live server to run, so their rendering is checked on the unexported helper.
+
+import (
+	"regexp"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func TestRollbackGuard(t *testing.T) {
+	tests := []struct {
+		name    string
+		object  rollbackGuardObject
+		dialect string
+		want    string
+	}{
+		{
+			name:    "postgres table",
+			object:  rollbackGuardObject{Table: "app.posts"},
+			dialect: platform.Postgres,
+			want: "-- Rollback precondition: table app.posts must exist\n" +
+				"DO $$\nBEGIN\n" +
+				"    IF to_regclass('\"app\".\"posts\"') IS NULL THEN\n" +
+				"        RAISE EXCEPTION 'rollback precondition failed: table app.posts does not exist';\n" +
+				"    END IF;\nEND $$;",
+		},
+		{
+			name:    "postgres column",
+			object:  rollbackGuardObject{Table: "users", Column: "bio"},
+			dialect: platform.Postgres,
+			want: "-- Rollback precondition: column users.bio must exist\n" +
+				"DO $$\nBEGIN\n" +
+				"    IF NOT EXISTS (SELECT 1 FROM pg_attribute WHERE attrelid = to_regclass('\"users\"') AND attname = 'bio' AND NOT attisdropped) THEN\n" +
+				"        RAISE EXCEPTION 'rollback precondition failed: column users.bio does not exist';\n" +
+				"    END IF;\nEND $$;",
+		},
+		{
+			name:    "mysql column",
+			object:  rollbackGuardObject{Table: "users", Column: "bio"},
+			dialect: platform.MySQL,
+			want:    "-- Rollback precondition: column users.bio must exist\nSELECT `bio` FROM `users` LIMIT 0;",
+		},
+		{
+			name:    "sql server table",
+			object:  rollbackGuardObject{Table: "dbo.posts"},
+			dialect: platform.SQLServer,
+			want:    "-- Rollback precondition: table dbo.posts must exist\nSELECT TOP 0 * FROM [dbo].[posts];",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(rollbackGuard(tt.object, tt.dialect), qt.Equals, tt.want)
+		})
+	}
+}
+
+const guardedRollbackPostgresModel = `package models
+
+//migrator:schema:extension name="pg_trgm"
+//migrator:schema:enum name="post_status" values="draft,published"
+//migrator:schema:domain name="email_address" type="TEXT" check="VALUE ~ '@'"
+//migrator:schema:sequence name="post_number_seq" start="1000"
+//migrator:schema:function name="current_reader" returns="TEXT" language="sql" body="SELECT current_user"
+//migrator:schema:role name="blog_reader"
+//migrator:schema:view name="published_posts" body="SELECT id FROM posts"
+//migrator:schema:trigger name="posts_set_updated_at" table="posts" timing="BEFORE" event="UPDATE" for="ROW" body="NEW.updated_at = NOW(); RETURN NEW;"
+//migrator:schema:rls:policy name="posts_reader" table="posts" for="SELECT" to="blog_reader" using="true"
+//migrator:schema:table name="users"
+//migrator:schema:constraint name="users_email_present" type="CHECK" check="email IS NOT NULL"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+	//migrator:schema:field name="email" type="TEXT"
+	//migrator:schema:index name="idx_users_email" fields="email"
+	Email string
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+	//migrator:schema:field name="updated_at" type="TIMESTAMP"
+	UpdatedAt string
+}
+`
+
+func TestWithRollbackGuards_GuardsEveryDrop(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSources(map[string][]byte{"models.go": []byte(guardedRollbackPostgresModel)})
+	c.Assert(err, qt.IsNil)
+	live := &dbschematypes.DBSchema{Tables: []dbschematypes.DBTable{{
+		Name: "users", Type: "BASE TABLE", Columns: []dbschematypes.DBColumn{
+			{Name: "id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+		},
+	}}}
+	diff := schemadiff.CompareWithDialect(generated, live, platform.Postgres)
+
+	downSQL, err := generateDownMigrationSQLWithOptions(diff, generated, live, platform.Postgres, generatedDirectiveOptions{}, nil, nil, false)
+	c.Assert(err, qt.IsNil)
+	guarded := withRollbackGuards(downSQL, diff, platform.Postgres)
+
+	drops := regexp.MustCompile(`(?m)^\s*(?:ALTER TABLE \S+ )?DROP (?:MATERIALIZED VIEW|[A-Z]+)(?: IF EXISTS)? "?([\w.]+?)"?(?:[ ;(]|$)`).
+		FindAllStringSubmatch(downSQL, -1)
+	c.Assert(len(drops) >= 10, qt.IsTrue, qt.Commentf("too few drops in down SQL:\n%s", downSQL))
+	for _, drop := range drops {
+		guard := regexp.MustCompile(`-- Rollback precondition: [a-z ]+ (?:[\w.]+\.)?` + regexp.QuoteMeta(drop[1]) + ` must exist`)
+		c.Assert(guard.MatchString(guarded), qt.IsTrue, qt.Commentf("no guard for %q in:\n%s", drop[0], guarded))
+	}
+}
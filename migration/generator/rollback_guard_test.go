@@ -0,0 +1,114 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+const guardedRollbackModel = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+
+	//migrator:schema:field name="bio" type="TEXT"
+	Bio string
+}
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+}
+`
+
+// generateGuardedRollback generates the migration for guardedRollbackModel
+// against a database holding only users(id) and returns the connection and
+// the generated files.
+func generateGuardedRollback(c *qt.C, guarded bool) (*dbschema.DatabaseConnection, *generator.MigrationFiles) {
+	ctx := context.Background()
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	migrationsDir := filepath.Join(tempDir, "migrations")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.MkdirAll(migrationsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "models.go"), []byte(guardedRollbackModel), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+	_, err = conn.ExecContext(ctx, `CREATE TABLE users (id INTEGER PRIMARY KEY)`)
+	c.Assert(err, qt.IsNil)
+
+	files, err := generator.GenerateMigration(ctx, generator.GenerateMigrationOptions{
+		GoEntitiesDir:   modelsDir,
+		DBConn:          conn,
+		MigrationName:   "profile",
+		OutputDir:       migrationsDir,
+		GuardedRollback: guarded,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Files, qt.HasLen, 1)
+	return conn, files
+}
+
+func runMigrationFile(conn *dbschema.DatabaseConnection, path string) error {
+	return migrator.MigrationFuncFromSQLFilename(filepath.Base(path), os.DirFS(filepath.Dir(path)))(context.Background(), conn)
+}
+
+func TestGenerateMigration_GuardedRollbackPrecedesDrops(t *testing.T) {
+	c := qt.New(t)
+	_, files := generateGuardedRollback(c, true)
+	downSQL, err := os.ReadFile(files.DownFile)
+	c.Assert(err, qt.IsNil)
+
+	// SQLite removes a column by rebuilding the table, so every guard must
+	// precede the first DROP TABLE.
+	firstDrop := strings.Index(string(downSQL), "DROP TABLE")
+	c.Assert(firstDrop >= 0, qt.IsTrue, qt.Commentf("no DROP TABLE in down SQL:\n%s", downSQL))
+	for _, guard := range []string{`SELECT * FROM "posts" LIMIT 0;`, `SELECT "bio" FROM "users" LIMIT 0;`} {
+		guardIndex := strings.Index(string(downSQL), guard)
+		c.Assert(guardIndex >= 0, qt.IsTrue, qt.Commentf("%q not found in down SQL:\n%s", guard, downSQL))
+		c.Assert(guardIndex < firstDrop, qt.IsTrue, qt.Commentf("%q must precede the first DROP TABLE:\n%s", guard, downSQL))
+	}
+}
+
+func TestGenerateMigration_GuardedRollbackStopsOnUnexpectedSchema(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn, files := generateGuardedRollback(c, true)
+
+	err := runMigrationFile(conn, files.DownFile)
+
+	c.Assert(err, qt.ErrorMatches, `(?s).*no such table: posts.*`)
+	var tables int
+	c.Assert(conn.QueryRowContext(ctx, `SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'`).Scan(&tables), qt.IsNil)
+	c.Assert(tables, qt.Equals, 1)
+}
+
+func TestGenerateMigration_GuardedRollbackRunsAfterUp(t *testing.T) {
+	c := qt.New(t)
+	conn, files := generateGuardedRollback(c, true)
+
+	c.Assert(runMigrationFile(conn, files.UpFile), qt.IsNil)
+	c.Assert(runMigrationFile(conn, files.DownFile), qt.IsNil)
+}
+
+func TestGenerateMigration_UnguardedRollbackHasNoPreconditions(t *testing.T) {
+	c := qt.New(t)
+	_, files := generateGuardedRollback(c, false)
+	downSQL, err := os.ReadFile(files.DownFile)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(string(downSQL), qt.Not(qt.Contains), "Rollback precondition")
+}
@@ -0,0 +1,62 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const safeTypeChangeModel = `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="quantity" type="INTEGER" type_cast="NULLIF(quantity, '')::integer"
+	Quantity int
+}
+`
+
+func TestGenerateMigration_SafeTypeChange(t *testing.T) {
+	c := qt.New(t)
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "order.go"), []byte(safeTypeChangeModel), 0o600), qt.IsNil)
+	fake := dbschematest.NewFakeConnection(t, platform.Postgres, &types.DBSchema{
+		Tables: []types.DBTable{{Name: "orders", Type: "BASE TABLE", Columns: []types.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "quantity", DataType: "text", UDTName: "text", IsNullable: "YES"},
+		}}},
+	})
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir:  modelsDir,
+		DBConn:         fake.DatabaseConnection,
+		MigrationName:  "quantity_to_integer",
+		OutputDir:      filepath.Join(tempDir, "migrations"),
+		SafeTypeChange: true,
+	})
+
+	c.Assert(err, qt.IsNil)
+	upSQL, err := os.ReadFile(files.UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TABLE "orders" ADD COLUMN "quantity__ptah_new" INTEGER;
+UPDATE "orders" SET "quantity__ptah_new" = NULLIF(quantity, '')::integer;
+ALTER TABLE "orders" DROP COLUMN "quantity";
+ALTER TABLE "orders" RENAME COLUMN "quantity__ptah_new" TO "quantity";`)
+	c.Assert(string(upSQL), qt.Not(qt.Contains), "TYPE INTEGER")
+	downSQL, err := os.ReadFile(files.DownFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(downSQL), qt.Contains, `ALTER TABLE "orders" ALTER COLUMN "quantity" TYPE text`)
+	c.Assert(string(downSQL), qt.Not(qt.Contains), "__ptah_new")
+}
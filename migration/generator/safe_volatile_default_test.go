@@ -0,0 +1,60 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const safeVolatileDefaultModel = `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="token" type="UUID" not_null="true" default_expr="gen_random_uuid()"
+	Token string
+}
+`
+
+func TestGenerateMigration_SafeVolatileDefault(t *testing.T) {
+	c := qt.New(t)
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "order.go"), []byte(safeVolatileDefaultModel), 0o600), qt.IsNil)
+	fake := dbschematest.NewFakeConnection(t, platform.Postgres, &types.DBSchema{
+		Tables: []types.DBTable{{Name: "orders", Type: "BASE TABLE", Columns: []types.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+		}}},
+	})
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir:       modelsDir,
+		DBConn:              fake.DatabaseConnection,
+		MigrationName:       "add_order_token",
+		OutputDir:           filepath.Join(tempDir, "migrations"),
+		SafeVolatileDefault: true,
+	})
+
+	c.Assert(err, qt.IsNil)
+	upSQL, err := os.ReadFile(files.UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TABLE "orders" ADD COLUMN "token" UUID;`)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TABLE "orders" ALTER COLUMN "token" SET DEFAULT gen_random_uuid();`)
+	c.Assert(string(upSQL), qt.Contains, `UPDATE "orders" SET "token" = gen_random_uuid() WHERE "token" IS NULL;`)
+	c.Assert(string(upSQL), qt.Contains, `ALTER TABLE "orders" ALTER COLUMN "token" SET NOT NULL;`)
+	c.Assert(string(upSQL), qt.Contains, "run it in batches")
+	downSQL, err := os.ReadFile(files.DownFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(downSQL), qt.Contains, `DROP COLUMN`)
+}
@@ -87,6 +87,7 @@ type shadowMigrationOptions struct {
 	Generated     *goschema.Database
 	CompareOpts   *config.CompareOptions
 	Schemas       []string
+	ConnectOpts   dbschema.ConnectOptions
 }
 
 type shadowCandidate struct {
@@ -97,7 +98,7 @@ type shadowCandidate struct {
 }
 
 func verifyShadowMigration(ctx context.Context, opts shadowMigrationOptions) error {
-	conn, err := dbschema.ConnectToDatabase(ctx, opts.DatabaseURL)
+	conn, err := dbschema.ConnectToDatabaseWithOptions(ctx, opts.DatabaseURL, opts.ConnectOpts)
 	if err != nil {
 		return newShadowVerificationError("connect", "connect_error", "connect to shadow database", err)
 	}
@@ -202,7 +203,7 @@ func latestMigrationVersion(migrations []*migrator.Migration) int64 {
 	return latest
 }
 
-func assertShadowSchemaMatches(conn *dbschema.DatabaseConnection, opts shadowMigrationOptions) error {
+func assertShadowSchemaMatches(conn dbschema.SchemaReader, opts shadowMigrationOptions) error {
 	dbSchema, err := dbschema.ReadSchemaWithSchemas(conn, opts.Schemas)
 	if err != nil {
 		return newShadowVerificationError("re-introspect", "re_introspect_error", "re-introspect shadow database", err)
@@ -252,6 +253,26 @@ func collectModifiedTableMismatch(table types.TableDiff) []ShadowMismatch {
 		message := fmt.Sprintf("extra constraint %s.%s", table.TableName, constraintName)
 		return []ShadowMismatch{{Kind: "extra_constraint", Table: table.TableName, Constraint: constraintName, Object: table.TableName + "." + constraintName, Message: message}}
 	}
+	for _, parent := range sortedStrings(table.InheritsAdded) {
+		message := fmt.Sprintf("missing inheritance %s INHERITS %s", table.TableName, parent)
+		return []ShadowMismatch{{Kind: "missing_inheritance", Table: table.TableName, Object: parent, Message: message}}
+	}
+	for _, parent := range sortedStrings(table.InheritsRemoved) {
+		message := fmt.Sprintf("extra inheritance %s INHERITS %s", table.TableName, parent)
+		return []ShadowMismatch{{Kind: "extra_inheritance", Table: table.TableName, Object: parent, Message: message}}
+	}
+	if table.Comment != "" {
+		message := fmt.Sprintf("table comment mismatch %s: %s", table.TableName, table.Comment)
+		return []ShadowMismatch{{Kind: "table_comment_mismatch", Table: table.TableName, Object: table.TableName, Message: message}}
+	}
+	if table.Owner != "" {
+		message := fmt.Sprintf("table owner mismatch %s: %s", table.TableName, table.Owner)
+		return []ShadowMismatch{{Kind: "table_owner_mismatch", Table: table.TableName, Object: table.TableName, Message: message}}
+	}
+	if table.AutoIncrement != "" {
+		message := fmt.Sprintf("table AUTO_INCREMENT mismatch %s: %s", table.TableName, table.AutoIncrement)
+		return []ShadowMismatch{{Kind: "table_auto_increment_mismatch", Table: table.TableName, Object: table.TableName, Message: message}}
+	}
 	return nil
 }
 
@@ -284,9 +305,17 @@ func collectShadowMismatches(diff *types.SchemaDiff) []ShadowMismatch {
 	for _, indexName := range sortedStrings(diff.IndexesAdded) {
 		return []ShadowMismatch{{Kind: "missing_index", Object: indexName, Message: "missing index " + indexName}}
 	}
+	for _, change := range diff.IndexesVisibilityChanged {
+		message := fmt.Sprintf("index %s visibility differs (want visible=%t)", change.Name, change.Visible)
+		return []ShadowMismatch{{Kind: "index_visibility_mismatch", Table: change.TableName, Object: change.Name, Message: message}}
+	}
 	for _, extensionName := range sortedStrings(diff.ExtensionsAdded) {
 		return []ShadowMismatch{{Kind: "missing_extension", Object: extensionName, Message: "missing extension " + extensionName}}
 	}
+	for _, extension := range diff.ExtensionsModified {
+		message := fmt.Sprintf("extension %s version %s differs from %s", extension.ExtensionName, extension.CurrentVersion, extension.TargetVersion)
+		return []ShadowMismatch{{Kind: "extension_version_mismatch", Object: extension.ExtensionName, Message: message}}
+	}
 	for _, functionName := range sortedStrings(diff.FunctionsAdded) {
 		return []ShadowMismatch{{Kind: "missing_function", Object: functionName, Message: "missing function " + functionName}}
 	}
@@ -0,0 +1,419 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/deporder"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// SplitStrategy selects how GenerateMigration divides one schema diff into
+// migration files. Every file gets its own version, numbered in apply order.
+type SplitStrategy string
+
+const (
+	// SplitStrategySingleFile writes the whole diff as one migration. It is the
+	// default.
+	SplitStrategySingleFile SplitStrategy = "single"
+	// SplitStrategyPerTable writes one migration per affected table, ordered so
+	// referenced tables are created before the tables that reference them.
+	// Changes to objects tables depend on (extensions, types, functions,
+	// sequences, roles) go into a leading migration, and changes to objects
+	// that depend on tables (views, triggers, policies, grants) into a
+	// trailing one.
+	SplitStrategyPerTable SplitStrategy = "per-table"
+	// SplitStrategyPerPhase writes the structural changes first, then the
+	// constraints added to tables, then the new indexes.
+	SplitStrategyPerPhase SplitStrategy = "per-phase"
+)
+
+// ParseSplitStrategy parses a split strategy name. Empty selects
+// SplitStrategySingleFile.
+func ParseSplitStrategy(value string) (SplitStrategy, error) {
+	switch strategy := SplitStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case "":
+		return SplitStrategySingleFile, nil
+	case SplitStrategySingleFile, SplitStrategyPerTable, SplitStrategyPerPhase:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unsupported split strategy %q (want single, per-table, or per-phase)", value)
+	}
+}
+
+// migrationDiffPart is one slice of the schema diff planned as its own
+// migration. suffix is appended to the migration name.
+type migrationDiffPart struct {
+	suffix string
+	diff   *types.SchemaDiff
+}
+
+// splitMigrationDiff divides diff according to strategy. Parts are returned in
+// apply order; parts without changes are dropped.
+func splitMigrationDiff(
+	strategy SplitStrategy,
+	diff *types.SchemaDiff,
+	generated *goschema.Database,
+	dbSchema *dbschematypes.DBSchema,
+	dialect string,
+) []migrationDiffPart {
+	var parts []migrationDiffPart
+	switch strategy {
+	case SplitStrategyPerPhase:
+		parts = splitDiffPerPhase(diff, dialect)
+	case SplitStrategyPerTable:
+		parts = splitDiffPerTable(diff, generated, dbSchema)
+	default:
+		return []migrationDiffPart{{diff: diff}}
+	}
+	kept := parts[:0]
+	for _, part := range parts {
+		if part.diff.HasChanges() {
+			kept = append(kept, part)
+		}
+	}
+	return kept
+}
+
+// splitDiffPerPhase moves added constraints and added indexes out of the
+// structural diff. SQLite declares table constraints inside CREATE TABLE and
+// cannot add them afterwards, so there the constraints of new tables stay in
+// the structural phase.
+func splitDiffPerPhase(diff *types.SchemaDiff, dialect string) []migrationDiffPart {
+	structure := cloneSchemaDiff(diff)
+	constraints := emptySchemaDiff()
+	indexes := emptySchemaDiff()
+
+	structure.IndexesAdded = nil
+	structure.IndexesVisibilityChanged = nil
+	indexes.IndexesAdded = slices.Clone(diff.IndexesAdded)
+	indexes.IndexesVisibilityChanged = slices.Clone(diff.IndexesVisibilityChanged)
+
+	inlineTables := map[string]struct{}{}
+	if platform.NormalizeDialect(dialect) == platform.SQLite {
+		inlineTables = stringSet(diff.TablesAdded)
+	}
+	structure.ConstraintsAdded = nil
+	structure.ConstraintsAddedWithTables = nil
+	for _, addition := range diff.ConstraintsAddedWithTables {
+		target := constraints
+		if _, ok := inlineTables[addition.TableName]; ok {
+			target = structure
+		}
+		target.ConstraintsAddedWithTables = append(target.ConstraintsAddedWithTables, addition)
+	}
+	assignBareConstraintAdditions(diff.ConstraintsAdded, constraints, structure, constraints)
+
+	return []migrationDiffPart{
+		{suffix: "_schema", diff: structure},
+		{suffix: "_constraints", diff: constraints},
+		{suffix: "_indexes", diff: indexes},
+	}
+}
+
+// assignBareConstraintAdditions copies each bare constraint name into every
+// part that received one of its table-qualified additions, or into fallback
+// when no part did.
+func assignBareConstraintAdditions(names []string, fallback *types.SchemaDiff, parts ...*types.SchemaDiff) {
+	for _, name := range names {
+		assigned := false
+		for _, part := range parts {
+			if slices.Contains(part.ConstraintsAdded, name) || !hasConstraintAddition(part, name) {
+				continue
+			}
+			part.ConstraintsAdded = append(part.ConstraintsAdded, name)
+			assigned = true
+		}
+		if !assigned && !slices.Contains(fallback.ConstraintsAdded, name) {
+			fallback.ConstraintsAdded = append(fallback.ConstraintsAdded, name)
+		}
+	}
+}
+
+func hasConstraintAddition(diff *types.SchemaDiff, name string) bool {
+	return slices.ContainsFunc(diff.ConstraintsAddedWithTables, func(addition types.ConstraintAdditionInfo) bool {
+		return addition.Name == name
+	})
+}
+
+func hasConstraintRemoval(diff *types.SchemaDiff, name string) bool {
+	return slices.ContainsFunc(diff.ConstraintsRemovedWithTables, func(removal types.ConstraintRemovalInfo) bool {
+		return removal.Name == name
+	})
+}
+
+func hasIndexRemoval(diff *types.SchemaDiff, name string) bool {
+	return slices.ContainsFunc(diff.IndexesRemovedWithTables, func(removal types.IndexRemovalInfo) bool {
+		return removal.Name == name
+	})
+}
+
+// blockingFunctionTriggers keeps only the FunctionTriggers entries that diff
+// leaves in place. The parts that drop the other triggers, or their tables,
+// run before the trailing part that drops the functions.
+func blockingFunctionTriggers(diff *types.SchemaDiff) map[string][]types.TriggerRef {
+	var blocking map[string][]types.TriggerRef
+	for function := range diff.FunctionTriggers {
+		if triggers := diff.BlockingFunctionTriggers(function); len(triggers) > 0 {
+			if blocking == nil {
+				blocking = make(map[string][]types.TriggerRef)
+			}
+			blocking[function] = triggers
+		}
+	}
+	return blocking
+}
+
+// perTableSplit accumulates the per-table parts of a diff.
+type perTableSplit struct {
+	generated *goschema.Database
+	before    *types.SchemaDiff
+	after     *types.SchemaDiff
+	tables    map[string]*types.SchemaDiff
+	keys      []string
+}
+
+// splitDiffPerTable gives every affected table its own part, preceded by the
+// objects tables may depend on and followed by the objects that may depend on
+// tables. Dropping a type, function, or extension waits for the trailing part
+// so tables stop using it first; dropping a view, trigger, policy, or grant
+// happens in the leading part so it is gone before its table changes.
+func splitDiffPerTable(diff *types.SchemaDiff, generated *goschema.Database, dbSchema *dbschematypes.DBSchema) []migrationDiffPart {
+	split := &perTableSplit{
+		generated: generated,
+		before: &types.SchemaDiff{
+			ExtensionsAdded:          slices.Clone(diff.ExtensionsAdded),
+			ExtensionsModified:       slices.Clone(diff.ExtensionsModified),
+			EnumsAdded:               slices.Clone(diff.EnumsAdded),
+			EnumsModified:            slices.Clone(diff.EnumsModified),
+			EnumsRenamed:             slices.Clone(diff.EnumsRenamed),
+			FunctionsAdded:           slices.Clone(diff.FunctionsAdded),
+			FunctionsModified:        slices.Clone(diff.FunctionsModified),
+			SequencesAdded:           slices.Clone(diff.SequencesAdded),
+			SequencesModified:        slices.Clone(diff.SequencesModified),
+			DomainsAdded:             slices.Clone(diff.DomainsAdded),
+			DomainsModified:          slices.Clone(diff.DomainsModified),
+			CompositeTypesAdded:      slices.Clone(diff.CompositeTypesAdded),
+			CompositeTypesModified:   slices.Clone(diff.CompositeTypesModified),
+			RangesAdded:              slices.Clone(diff.RangesAdded),
+			RolesAdded:               slices.Clone(diff.RolesAdded),
+			RolesModified:            slices.Clone(diff.RolesModified),
+			RoleMembershipsAdded:     slices.Clone(diff.RoleMembershipsAdded),
+			RoleMembershipsRemoved:   slices.Clone(diff.RoleMembershipsRemoved),
+			ViewsRemoved:             slices.Clone(diff.ViewsRemoved),
+			MaterializedViewsRemoved: slices.Clone(diff.MaterializedViewsRemoved),
+			TriggersRemoved:          slices.Clone(diff.TriggersRemoved),
+			RLSPoliciesRemoved:       slices.Clone(diff.RLSPoliciesRemoved),
+			RLSEnabledTablesRemoved:  slices.Clone(diff.RLSEnabledTablesRemoved),
+			GrantsRemoved:            slices.Clone(diff.GrantsRemoved),
+			GrantOptionsRevoked:      slices.Clone(diff.GrantOptionsRevoked),
+		},
+		after: &types.SchemaDiff{
+			ExtensionsRemoved:         slices.Clone(diff.ExtensionsRemoved),
+			EnumsRemoved:              slices.Clone(diff.EnumsRemoved),
+			FunctionsRemoved:          slices.Clone(diff.FunctionsRemoved),
+			FunctionTriggers:          blockingFunctionTriggers(diff),
+			SequencesRemoved:          slices.Clone(diff.SequencesRemoved),
+			DomainsRemoved:            slices.Clone(diff.DomainsRemoved),
+			CompositeTypesRemoved:     slices.Clone(diff.CompositeTypesRemoved),
+			RangesRemoved:             slices.Clone(diff.RangesRemoved),
+			RolesRemoved:              slices.Clone(diff.RolesRemoved),
+			ViewsAdded:                slices.Clone(diff.ViewsAdded),
+			ViewsModified:             slices.Clone(diff.ViewsModified),
+			MaterializedViewsAdded:    slices.Clone(diff.MaterializedViewsAdded),
+			MaterializedViewsModified: slices.Clone(diff.MaterializedViewsModified),
+			TriggersAdded:             slices.Clone(diff.TriggersAdded),
+			TriggersModified:          slices.Clone(diff.TriggersModified),
+			RLSPoliciesAdded:          slices.Clone(diff.RLSPoliciesAdded),
+			RLSPoliciesModified:       slices.Clone(diff.RLSPoliciesModified),
+			RLSEnabledTablesAdded:     slices.Clone(diff.RLSEnabledTablesAdded),
+			GrantsAdded:               slices.Clone(diff.GrantsAdded),
+			GrantOptionsAdded:         slices.Clone(diff.GrantOptionsAdded),
+		},
+		tables: make(map[string]*types.SchemaDiff),
+	}
+	split.assignTables(diff)
+	split.assignIndexes(diff)
+	split.assignConstraints(diff)
+
+	parts := []migrationDiffPart{{suffix: "_schema_objects", diff: split.before}}
+	for _, key := range split.orderedTableKeys(dbSchema) {
+		parts = append(parts, migrationDiffPart{suffix: "_" + migrationNameSuffix(key), diff: split.tables[key]})
+	}
+	return append(parts, migrationDiffPart{suffix: "_dependent_objects", diff: split.after})
+}
+
+func (s *perTableSplit) assignTables(diff *types.SchemaDiff) {
+	for _, name := range diff.TablesAdded {
+		part := s.table(name)
+		part.TablesAdded = append(part.TablesAdded, name)
+	}
+	for _, name := range diff.TablesRemoved {
+		part := s.table(name)
+		part.TablesRemoved = append(part.TablesRemoved, name)
+	}
+	for _, tableDiff := range diff.TablesModified {
+		part := s.table(tableDiff.TableName)
+		part.TablesModified = append(part.TablesModified, tableDiff)
+	}
+	for _, seed := range diff.SeedsAdded {
+		part := s.table(seed.Table)
+		part.SeedsAdded = append(part.SeedsAdded, seed)
+	}
+	for _, seed := range diff.SeedsRemoved {
+		part := s.table(seed.Table)
+		part.SeedsRemoved = append(part.SeedsRemoved, seed)
+	}
+}
+
+func (s *perTableSplit) assignIndexes(diff *types.SchemaDiff) {
+	indexTables := make(map[string]string, len(s.generated.Indexes))
+	structToTable := generatedStructTableMap(s.generated)
+	for _, index := range s.generated.Indexes {
+		indexTables[index.Name] = resolveGeneratedIndexTable(index, structToTable)
+	}
+	for _, name := range diff.IndexesAdded {
+		part := s.after
+		if tableName := indexTables[name]; tableName != "" {
+			part = s.table(tableName)
+		}
+		part.IndexesAdded = append(part.IndexesAdded, name)
+	}
+
+	for _, removal := range diff.IndexesRemovedWithTables {
+		part := s.table(removal.TableName)
+		part.IndexesRemovedWithTables = append(part.IndexesRemovedWithTables, removal)
+		if !slices.Contains(part.IndexesRemoved, removal.Name) {
+			part.IndexesRemoved = append(part.IndexesRemoved, removal.Name)
+		}
+	}
+	for _, name := range diff.IndexesRemoved {
+		if !slices.ContainsFunc(s.tableParts(), func(part *types.SchemaDiff) bool { return hasIndexRemoval(part, name) }) {
+			s.before.IndexesRemoved = append(s.before.IndexesRemoved, name)
+		}
+	}
+	for _, change := range diff.IndexesVisibilityChanged {
+		part := s.table(change.TableName)
+		part.IndexesVisibilityChanged = append(part.IndexesVisibilityChanged, change)
+	}
+}
+
+func (s *perTableSplit) assignConstraints(diff *types.SchemaDiff) {
+	for _, addition := range diff.ConstraintsAddedWithTables {
+		part := s.table(addition.TableName)
+		part.ConstraintsAddedWithTables = append(part.ConstraintsAddedWithTables, addition)
+	}
+	for _, removal := range diff.ConstraintsRemovedWithTables {
+		part := s.table(removal.TableName)
+		part.ConstraintsRemovedWithTables = append(part.ConstraintsRemovedWithTables, removal)
+	}
+
+	parts := s.tableParts()
+	assignBareConstraintAdditions(diff.ConstraintsAdded, s.after, parts...)
+	for _, name := range diff.ConstraintsRemoved {
+		assigned := false
+		for _, part := range parts {
+			if hasConstraintRemoval(part, name) && !slices.Contains(part.ConstraintsRemoved, name) {
+				part.ConstraintsRemoved = append(part.ConstraintsRemoved, name)
+				assigned = true
+			}
+		}
+		if !assigned {
+			s.before.ConstraintsRemoved = append(s.before.ConstraintsRemoved, name)
+		}
+	}
+}
+
+// table returns the part for tableName, creating it on first use. Names are
+// resolved against the generated schema so qualified and unqualified
+// spellings of one table share a part.
+func (s *perTableSplit) table(tableName string) *types.SchemaDiff {
+	key := s.tableKey(tableName)
+	part, ok := s.tables[key]
+	if !ok {
+		part = emptySchemaDiff()
+		s.tables[key] = part
+		s.keys = append(s.keys, key)
+	}
+	return part
+}
+
+func (s *perTableSplit) tableKey(tableName string) string {
+	for _, table := range s.generated.Tables {
+		if table.QualifiedName() == tableName || (table.Name == tableName && table.Schema == "") {
+			return table.QualifiedName()
+		}
+	}
+	return tableName
+}
+
+func (s *perTableSplit) tableParts() []*types.SchemaDiff {
+	parts := make([]*types.SchemaDiff, 0, len(s.keys))
+	for _, key := range s.keys {
+		parts = append(parts, s.tables[key])
+	}
+	return parts
+}
+
+// orderedTableKeys orders tables of the target schema parent-first, followed
+// by dropped tables child-first according to the database foreign keys.
+func (s *perTableSplit) orderedTableKeys(dbSchema *dbschematypes.DBSchema) []string {
+	var targetKeys, droppedKeys []string
+	for _, key := range s.keys {
+		if !s.isTargetTable(key) {
+			droppedKeys = append(droppedKeys, key)
+			continue
+		}
+		targetKeys = append(targetKeys, key)
+	}
+	ordered := make([]string, 0, len(s.keys))
+	for _, table := range deporder.TablesForCreate(s.generated, targetKeys) {
+		ordered = append(ordered, table.QualifiedName())
+	}
+	slices.Sort(droppedKeys)
+	return append(ordered, deporder.StableReverseDependencySort(droppedKeys, databaseTableDependencies(dbSchema))...)
+}
+
+func (s *perTableSplit) isTargetTable(key string) bool {
+	return slices.ContainsFunc(s.generated.Tables, func(table goschema.Table) bool {
+		return table.QualifiedName() == key
+	})
+}
+
+// databaseTableDependencies maps each database table to the tables its
+// foreign keys reference.
+func databaseTableDependencies(dbSchema *dbschematypes.DBSchema) map[string][]string {
+	dependencies := make(map[string][]string)
+	if dbSchema == nil {
+		return dependencies
+	}
+	for _, constraint := range dbSchema.Constraints {
+		if constraint.ForeignTable == nil || !strings.EqualFold(constraint.Type, "FOREIGN KEY") {
+			continue
+		}
+		referenced := constraint.QualifiedForeignTableName()
+		table := constraint.QualifiedTableName()
+		if referenced != table {
+			dependencies[table] = append(dependencies[table], referenced)
+		}
+	}
+	return dependencies
+}
+
+// migrationNameSuffix turns a table name into a file-name friendly suffix.
+func migrationNameSuffix(tableName string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, tableName)
+}
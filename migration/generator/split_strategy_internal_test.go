@@ -0,0 +1,152 @@
+package generator
+
+// White-box testing required: the split strategies are applied by the
+// unexported planGeneratedMigrationSpecs, and PostgreSQL-specific phases
+// (constraints added after CREATE TABLE) need a PostgreSQL plan without a
+// live server.
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform/capability"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const splitStrategySchema = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+	//migrator:schema:field name="email" type="TEXT"
+	Email string
+	//migrator:schema:index name="idx_users_email" fields="email"
+	_ int
+}
+
+//migrator:schema:enum name="post_status" values="draft,published"
+//migrator:schema:table name="posts"
+//migrator:schema:constraint name="fk_posts_user" type="FOREIGN KEY" columns="user_id" foreign_table="users" foreign_column="id"
+//migrator:schema:constraint name="chk_posts_title" type="CHECK" check="length(title) > 0"
+type Post struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+	//migrator:schema:field name="user_id" type="INTEGER"
+	UserID int64
+	//migrator:schema:field name="title" type="TEXT"
+	Title string
+	//migrator:schema:field name="status" type="post_status"
+	Status string
+	//migrator:schema:index name="idx_posts_user" fields="user_id"
+	_ int
+}
+`
+
+func planSplitStrategy(c *qt.C, strategy SplitStrategy) []generatedMigrationSpec {
+	generated, err := goschema.ParseSource("models.go", splitStrategySchema)
+	c.Assert(err, qt.IsNil)
+	dbSchema := &dbschematypes.DBSchema{}
+	diff := schemadiff.Compare(&generated, dbSchema)
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		diff,
+		&generated,
+		dbSchema,
+		postgresInfo(capability.Postgres17()),
+		100,
+		"blog",
+		planOptions{splitStrategy: strategy},
+	)
+	c.Assert(err, qt.IsNil)
+	return specs
+}
+
+func specNamesAndVersions(specs []generatedMigrationSpec) map[int64]string {
+	names := make(map[int64]string, len(specs))
+	for _, spec := range specs {
+		names[spec.Version] = spec.Name
+	}
+	return names
+}
+
+func TestPlanGeneratedMigrationSpecs_SingleFileByDefault(t *testing.T) {
+	c := qt.New(t)
+
+	specs := planSplitStrategy(c, "")
+
+	c.Assert(specNamesAndVersions(specs), qt.DeepEquals, map[int64]string{100: "blog"})
+}
+
+func TestPlanGeneratedMigrationSpecs_SplitPerPhase(t *testing.T) {
+	c := qt.New(t)
+
+	specs := planSplitStrategy(c, SplitStrategyPerPhase)
+
+	c.Assert(specNamesAndVersions(specs), qt.DeepEquals, map[int64]string{
+		100: "blog_schema",
+		101: "blog_constraints",
+		102: "blog_indexes",
+	})
+	c.Assert(specs[0].UpSQL, qt.Contains, `CREATE TABLE "posts"`)
+	c.Assert(specs[0].UpSQL, qt.Not(qt.Contains), "ADD CONSTRAINT")
+	c.Assert(specs[0].UpSQL, qt.Not(qt.Contains), "CREATE INDEX")
+	c.Assert(specs[0].UpSQL, qt.Contains, "-- Version: 100\n")
+	c.Assert(specs[1].UpSQL, qt.Contains, `ADD CONSTRAINT "fk_posts_user"`)
+	c.Assert(specs[1].UpSQL, qt.Contains, `ADD CONSTRAINT "chk_posts_title"`)
+	c.Assert(specs[1].UpSQL, qt.Not(qt.Contains), "CREATE TABLE")
+	c.Assert(specs[1].DownSQL, qt.Contains, `DROP CONSTRAINT IF EXISTS "fk_posts_user"`)
+	c.Assert(specs[1].DownSQL, qt.Not(qt.Contains), "DROP TABLE")
+	c.Assert(specs[2].UpSQL, qt.Contains, `"idx_users_email"`)
+	c.Assert(specs[2].UpSQL, qt.Contains, `"idx_posts_user"`)
+	c.Assert(specs[2].UpSQL, qt.Not(qt.Contains), "CREATE TABLE")
+}
+
+func TestPlanGeneratedMigrationSpecs_SplitPerTable(t *testing.T) {
+	c := qt.New(t)
+
+	specs := planSplitStrategy(c, SplitStrategyPerTable)
+
+	// users sorts after posts by name but is referenced by posts, so it is
+	// created first; the enum used by posts precedes both tables.
+	c.Assert(specNamesAndVersions(specs), qt.DeepEquals, map[int64]string{
+		100: "blog_schema_objects",
+		101: "blog_users",
+		102: "blog_posts",
+	})
+	c.Assert(specs[0].UpSQL, qt.Contains, `CREATE TYPE "post_status"`)
+	c.Assert(specs[0].UpSQL, qt.Not(qt.Contains), "CREATE TABLE")
+	c.Assert(specs[1].UpSQL, qt.Contains, `CREATE TABLE "users"`)
+	c.Assert(specs[1].UpSQL, qt.Contains, `"idx_users_email"`)
+	c.Assert(specs[1].UpSQL, qt.Not(qt.Contains), "posts")
+	c.Assert(specs[2].UpSQL, qt.Contains, `CREATE TABLE "posts"`)
+	c.Assert(specs[2].UpSQL, qt.Contains, `ADD CONSTRAINT "fk_posts_user"`)
+	c.Assert(specs[2].UpSQL, qt.Contains, `"idx_posts_user"`)
+	c.Assert(specs[2].DownSQL, qt.Contains, `DROP TABLE IF EXISTS "posts"`)
+	c.Assert(specs[2].DownSQL, qt.Not(qt.Contains), `"users"`)
+}
+
+func TestSplitDiffPerTable_DropsReferencingTablesFirst(t *testing.T) {
+	c := qt.New(t)
+	users := "users"
+	dbSchema := &dbschematypes.DBSchema{
+		Constraints: []dbschematypes.DBConstraint{{
+			Name:         "fk_orders_user",
+			TableName:    "orders",
+			Type:         "FOREIGN KEY",
+			ForeignTable: &users,
+		}},
+	}
+	diff := schemadiff.Compare(&goschema.Database{}, dbSchema)
+	diff.TablesRemoved = []string{"orders", "users"}
+
+	parts := splitMigrationDiff(SplitStrategyPerTable, diff, &goschema.Database{}, dbSchema, "postgres")
+
+	c.Assert(parts, qt.HasLen, 2)
+	c.Assert(parts[0].suffix, qt.Equals, "_orders")
+	c.Assert(parts[1].suffix, qt.Equals, "_users")
+	c.Assert(parts[1].diff.TablesRemoved, qt.DeepEquals, []string{"users"})
+}
@@ -0,0 +1,130 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+const splitStrategyModels = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+	//migrator:schema:field name="email" type="TEXT"
+	Email string
+	//migrator:schema:index name="idx_users_email" fields="email"
+	_ int
+}
+
+//migrator:schema:table name="orders"
+//migrator:schema:constraint name="chk_orders_total" type="CHECK" check="total >= 0"
+type Order struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+	//migrator:schema:field name="user_id" type="INTEGER" foreign="users(id)"
+	UserID int64
+	//migrator:schema:field name="total" type="INTEGER"
+	Total int64
+	//migrator:schema:index name="idx_orders_user" fields="user_id"
+	_ int
+}
+`
+
+func generateSplitMigrations(c *qt.C, strategy generator.SplitStrategy) (*generator.MigrationFiles, *dbschema.DatabaseConnection, string) {
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	migrationsDir := filepath.Join(tempDir, "migrations")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.MkdirAll(migrationsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "models.go"), []byte(splitStrategyModels), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(context.Background(), "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir:   modelsDir,
+		DBConn:          conn,
+		MigrationName:   "shop",
+		OutputDir:       migrationsDir,
+		VersionStrategy: generator.VersionStrategySequential,
+		SplitStrategy:   strategy,
+		Clock:           func() time.Time { return versionStrategyNow },
+	})
+	c.Assert(err, qt.IsNil)
+	return files, conn, migrationsDir
+}
+
+func upFileNames(files *generator.MigrationFiles) []string {
+	names := make([]string, 0, len(files.Files))
+	for _, pair := range files.Files {
+		names = append(names, filepath.Base(pair.UpFile))
+	}
+	return names
+}
+
+func applyAndRevertMigrations(c *qt.C, conn *dbschema.DatabaseConnection, migrationsDir string) {
+	ctx := context.Background()
+	mig, err := migrator.NewFSMigrator(conn, os.DirFS(migrationsDir))
+	c.Assert(err, qt.IsNil)
+	c.Assert(mig.MigrateUp(ctx), qt.IsNil)
+	c.Assert(sqliteSchemaObjectCount(c, conn, "index", "idx_orders_user", "orders"), qt.Equals, 1)
+	c.Assert(mig.MigrateDownTo(ctx, 0), qt.IsNil)
+	c.Assert(sqliteSchemaObjectCount(c, conn, "table", "orders", "orders"), qt.Equals, 0)
+}
+
+func TestGenerateMigration_SplitPerTableOrdersReferencedTablesFirst(t *testing.T) {
+	c := qt.New(t)
+
+	files, conn, migrationsDir := generateSplitMigrations(c, generator.SplitStrategyPerTable)
+
+	c.Assert(upFileNames(files), qt.DeepEquals, []string{
+		"0000000001_shop_users.up.sql",
+		"0000000002_shop_orders.up.sql",
+	})
+	c.Assert(files.Version, qt.Equals, int64(1))
+	c.Assert(readGeneratedFile(c, files.Files[1].UpFile), qt.Contains, `CONSTRAINT "chk_orders_total" CHECK`)
+	c.Assert(readGeneratedFile(c, files.Files[1].UpFile), qt.Contains, "-- Version: 2\n")
+	applyAndRevertMigrations(c, conn, migrationsDir)
+}
+
+func TestGenerateMigration_SplitPerPhaseSeparatesIndexes(t *testing.T) {
+	c := qt.New(t)
+
+	files, conn, migrationsDir := generateSplitMigrations(c, generator.SplitStrategyPerPhase)
+
+	// SQLite keeps the CHECK of a new table inside CREATE TABLE, so there is
+	// no separate constraints phase.
+	c.Assert(upFileNames(files), qt.DeepEquals, []string{
+		"0000000001_shop_schema.up.sql",
+		"0000000002_shop_indexes.up.sql",
+	})
+	c.Assert(readGeneratedFile(c, files.Files[0].UpFile), qt.Not(qt.Contains), "idx_users_email")
+	c.Assert(readGeneratedFile(c, files.Files[1].UpFile), qt.Contains, "idx_users_email")
+	applyAndRevertMigrations(c, conn, migrationsDir)
+}
+
+func TestParseSplitStrategy(t *testing.T) {
+	c := qt.New(t)
+
+	strategy, err := generator.ParseSplitStrategy("")
+	c.Assert(err, qt.IsNil)
+	c.Assert(strategy, qt.Equals, generator.SplitStrategySingleFile)
+
+	strategy, err = generator.ParseSplitStrategy("Per-Table")
+	c.Assert(err, qt.IsNil)
+	c.Assert(strategy, qt.Equals, generator.SplitStrategyPerTable)
+
+	_, err = generator.ParseSplitStrategy("per-column")
+	c.Assert(err, qt.ErrorMatches, `unsupported split strategy "per-column" \(want single, per-table, or per-phase\)`)
+}
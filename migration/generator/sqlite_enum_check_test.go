@@ -0,0 +1,91 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+const sqliteEnumCheckModel = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+
+	//migrator:schema:field name="status" type="ENUM" enum="active,inactive" not_null="true"
+	Status string
+}
+`
+
+func TestGenerateMigration_SQLiteEnumCheckRoundTripsAndRebuildsOnValueChange(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	migrationsDir := filepath.Join(tempDir, "migrations")
+	modelFile := filepath.Join(modelsDir, "user.go")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.MkdirAll(migrationsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(modelFile, []byte(sqliteEnumCheckModel), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	defer dbschema.CloseAndWarn(conn)
+	opts := generator.GenerateMigrationOptions{GoEntitiesDir: modelsDir, DBConn: conn, MigrationName: "create_users", OutputDir: migrationsDir}
+
+	files, err := generator.GenerateMigration(ctx, opts)
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Files, qt.HasLen, 1)
+	mig, err := migrator.NewFSMigrator(conn, os.DirFS(migrationsDir))
+	c.Assert(err, qt.IsNil)
+	c.Assert(mig.MigrateUp(ctx), qt.IsNil)
+	_, err = conn.ExecContext(ctx, `INSERT INTO users (id, status) VALUES (1, 'active')`)
+	c.Assert(err, qt.IsNil)
+
+	// The CHECK read back from SQLite matches the enum, so nothing is re-emitted.
+	opts.MigrationName = "noop"
+	files, err = generator.GenerateMigration(ctx, opts)
+	c.Assert(err, qt.IsNil)
+	c.Assert(files, qt.IsNil)
+
+	model := strings.Replace(sqliteEnumCheckModel, `enum="active,inactive"`, `enum="active,inactive,banned"`, 1)
+	c.Assert(os.WriteFile(modelFile, []byte(model), 0o600), qt.IsNil)
+	opts.MigrationName = "add_banned_status"
+	files, err = generator.GenerateMigration(ctx, opts)
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Files, qt.HasLen, 1)
+	upSQL, err := os.ReadFile(files.Files[0].UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(upSQL), qt.Contains, `CREATE TABLE "__ptah_rebuild_users"`)
+	c.Assert(string(upSQL), qt.Contains, "CHECK (status IN ('active', 'inactive', 'banned'))")
+
+	mig, err = migrator.NewFSMigrator(conn, os.DirFS(migrationsDir))
+	c.Assert(err, qt.IsNil)
+	c.Assert(mig.MigrateUp(ctx), qt.IsNil)
+	_, err = conn.ExecContext(ctx, `INSERT INTO users (id, status) VALUES (2, 'banned')`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(sqliteTableSQL(c, conn, "users"), qt.Contains, "'banned'")
+
+	_, err = conn.ExecContext(ctx, `DELETE FROM users WHERE id = 2`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(mig.MigrateDownTo(ctx, files.Files[0].Version-1), qt.IsNil)
+	c.Assert(sqliteTableSQL(c, conn, "users"), qt.Not(qt.Contains), "'banned'")
+	c.Assert(sqliteUserStatus(c, conn), qt.Equals, "active")
+}
+
+func sqliteUserStatus(c *qt.C, conn *dbschema.DatabaseConnection) string {
+	c.Helper()
+	var status string
+	err := conn.QueryRowContext(context.Background(), "SELECT status FROM users WHERE id = 1").Scan(&status)
+	c.Assert(err, qt.IsNil)
+	return status
+}
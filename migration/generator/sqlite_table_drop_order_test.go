@@ -0,0 +1,131 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/ptaherr"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func TestGenerateMigration_SQLiteDropsReferencingTablesFirst(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn, modelsDir, migrationsDir := sqliteTableDropFixture(c, []string{
+		`CREATE TABLE accounts (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE projects (id INTEGER PRIMARY KEY, account_id INTEGER NOT NULL REFERENCES accounts (id))`,
+		`CREATE TABLE tasks (id INTEGER PRIMARY KEY, project_id INTEGER NOT NULL REFERENCES projects (id))`,
+		`INSERT INTO accounts (id) VALUES (1)`,
+		`INSERT INTO projects (id, account_id) VALUES (1, 1)`,
+		`INSERT INTO tasks (id, project_id) VALUES (1, 1)`,
+	})
+
+	files, err := generator.GenerateMigration(ctx, generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        conn,
+		MigrationName: "drop_projects",
+		OutputDir:     migrationsDir,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Files, qt.HasLen, 1)
+
+	mig, err := migrator.NewFSMigrator(conn, os.DirFS(migrationsDir))
+	c.Assert(err, qt.IsNil)
+	c.Assert(mig.MigrateUp(ctx), qt.IsNil)
+	for _, table := range []string{"accounts", "projects", "tasks"} {
+		c.Assert(sqliteSchemaObjectCount(c, conn, "table", table, table), qt.Equals, 0)
+	}
+
+	c.Assert(mig.MigrateDownTo(ctx, 0), qt.IsNil)
+	for _, table := range []string{"accounts", "projects", "tasks"} {
+		c.Assert(sqliteSchemaObjectCount(c, conn, "table", table, table), qt.Equals, 1)
+	}
+}
+
+func TestGenerateMigration_SQLiteCyclicTableDrops(t *testing.T) {
+	cyclicTables := []string{
+		`CREATE TABLE left_nodes (id INTEGER PRIMARY KEY, right_id INTEGER REFERENCES right_nodes (id))`,
+		`CREATE TABLE right_nodes (id INTEGER PRIMARY KEY, left_id INTEGER NOT NULL REFERENCES left_nodes (id))`,
+		`INSERT INTO left_nodes (id) VALUES (1)`,
+		`INSERT INTO right_nodes (id, left_id) VALUES (1, 1)`,
+		`UPDATE left_nodes SET right_id = 1`,
+	}
+
+	t.Run("rejected without the option", func(t *testing.T) {
+		c := qt.New(t)
+		conn, modelsDir, migrationsDir := sqliteTableDropFixture(c, cyclicTables)
+
+		_, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+			GoEntitiesDir: modelsDir,
+			DBConn:        conn,
+			MigrationName: "drop_nodes",
+			OutputDir:     migrationsDir,
+		})
+
+		c.Assert(err, qt.ErrorIs, ptaherr.ErrUnsupportedFeature)
+		c.Assert(err, qt.ErrorMatches, `.*sqlite: tables left_nodes, right_nodes reference each other through foreign keys.*`)
+	})
+
+	t.Run("deferred with the option", func(t *testing.T) {
+		c := qt.New(t)
+		ctx := context.Background()
+		conn, modelsDir, migrationsDir := sqliteTableDropFixture(c, cyclicTables)
+
+		files, err := generator.GenerateMigration(ctx, generator.GenerateMigrationOptions{
+			GoEntitiesDir:           modelsDir,
+			DBConn:                  conn,
+			MigrationName:           "drop_nodes",
+			OutputDir:               migrationsDir,
+			CascadeCyclicTableDrops: true,
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(files.Files, qt.HasLen, 1)
+		upSQL, err := os.ReadFile(files.Files[0].UpFile)
+		c.Assert(err, qt.IsNil)
+		c.Assert(string(upSQL), qt.Contains, "PRAGMA defer_foreign_keys = ON;")
+
+		mig, err := migrator.NewFSMigrator(conn, os.DirFS(migrationsDir))
+		c.Assert(err, qt.IsNil)
+		c.Assert(mig.MigrateUp(ctx), qt.IsNil)
+		c.Assert(sqliteSchemaObjectCount(c, conn, "table", "left_nodes", "left_nodes"), qt.Equals, 0)
+		c.Assert(sqliteSchemaObjectCount(c, conn, "table", "right_nodes", "right_nodes"), qt.Equals, 0)
+	})
+}
+
+// sqliteTableDropFixture runs statements against a fresh SQLite database and
+// returns it with a models directory that declares only an unrelated table,
+// so every table the statements create is removed.
+func sqliteTableDropFixture(c *qt.C, statements []string) (*dbschema.DatabaseConnection, string, string) {
+	c.Helper()
+	ctx := context.Background()
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	migrationsDir := filepath.Join(tempDir, "migrations")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.MkdirAll(migrationsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "setting.go"), []byte(sqliteTableDropModel), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+	for _, statement := range append([]string{`CREATE TABLE settings (id INTEGER PRIMARY KEY)`}, statements...) {
+		_, err := conn.ExecContext(ctx, statement)
+		c.Assert(err, qt.IsNil)
+	}
+	return conn, modelsDir, migrationsDir
+}
+
+const sqliteTableDropModel = `package models
+
+//migrator:schema:table name="settings"
+type Setting struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+}
+`
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/migration/planner"
+)
+
+// Migration directions reported in PlannedOperation.Direction.
+const (
+	DirectionUp   = planner.DirectionUp
+	DirectionDown = planner.DirectionDown
+)
+
+// PlannedOperation is one planner-produced operation handed to a
+// StatementFilter before it is rendered to SQL. A filter may return a
+// different Node (for example an ast.RawSQLNode) to rewrite the operation.
+type PlannedOperation = planner.PlannedOperation
+
+// StatementFilter inspects, rewrites, or drops planned operations before they
+// are rendered. Returning keep=false removes the operation from the migration;
+// otherwise the returned operation's Node is rendered in its place.
+//
+// The filter runs after planning (and after DiffPolicy has been applied) but
+// before rendering, safety assessment, and file writing. It is called once per
+// operation, in plan order, first for every up operation and then for every
+// down operation. Up and down migrations are planned independently from the
+// diff, so a filter that drops an operation should decide from stable facts
+// such as Table so the matching inverse operation is dropped too.
+type StatementFilter func(op PlannedOperation) (PlannedOperation, bool)
+
+// applyStatementFilter runs filter over nodes, returning the kept (and possibly
+// rewritten) nodes in their original order. A nil filter returns nodes as-is.
+func applyStatementFilter(filter StatementFilter, direction, dialect string, nodes []ast.Node) []ast.Node {
+	if filter == nil {
+		return nodes
+	}
+	kept := make([]ast.Node, 0, len(nodes))
+	for _, node := range nodes {
+		op, keep := filter(planner.NewPlannedOperation(direction, dialect, node))
+		if !keep || op.Node == nil {
+			continue
+		}
+		kept = append(kept, op.Node)
+	}
+	return kept
+}
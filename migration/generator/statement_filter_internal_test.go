@@ -0,0 +1,110 @@
+package generator
+
+// White-box testing required: the statement filter is threaded through the
+// unexported planGeneratedMigrationSpecs orchestration, so asserting that it
+// applies to both the up and the down migration means calling it directly.
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform/capability"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// recordingFilter drops every operation on dropTable and records the
+// direction and table of each operation it sees.
+type recordingFilter struct {
+	dropTable string
+	seen      []string
+}
+
+func (f *recordingFilter) filter(op PlannedOperation) (PlannedOperation, bool) {
+	f.seen = append(f.seen, op.Direction+":"+op.Table)
+	return op, op.Table != f.dropTable
+}
+
+func twoTableGeneratedSchema() *goschema.Database {
+	return &goschema.Database{
+		Tables: []goschema.Table{
+			{StructName: "User", Name: "users"},
+			{StructName: "LegacyAudit", Name: "legacy_audit"},
+		},
+		Fields: []goschema.Field{
+			{StructName: "User", Name: "id", Type: "INTEGER", Primary: true},
+			{StructName: "LegacyAudit", Name: "id", Type: "INTEGER", Primary: true},
+		},
+	}
+}
+
+func TestPlanGeneratedMigrationSpecs_StatementFilterDropsUpAndDown(t *testing.T) {
+	c := qt.New(t)
+	recorder := &recordingFilter{dropTable: "legacy_audit"}
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		&types.SchemaDiff{TablesAdded: []string{"users", "legacy_audit"}},
+		twoTableGeneratedSchema(),
+		&dbschematypes.DBSchema{},
+		postgresInfo(capability.Postgres17()),
+		100,
+		"create_tables",
+		planOptions{statementFilter: recorder.filter},
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs, qt.HasLen, 1)
+	c.Assert(specs[0].UpSQL, qt.Contains, `CREATE TABLE "users"`)
+	c.Assert(specs[0].UpSQL, qt.Not(qt.Contains), "legacy_audit")
+	c.Assert(specs[0].DownSQL, qt.Contains, `DROP TABLE IF EXISTS "users"`)
+	c.Assert(specs[0].DownSQL, qt.Not(qt.Contains), "legacy_audit")
+	c.Assert(recorder.seen, qt.DeepEquals, []string{"up:users", "up:legacy_audit", "down:users", "down:legacy_audit"})
+}
+
+func TestPlanGeneratedMigrationSpecs_StatementFilterRewritesOperations(t *testing.T) {
+	c := qt.New(t)
+	replacements := map[string]ast.Node{
+		DirectionUp:   ast.NewRawSQL("CREATE INDEX idx_users_email ON users (email) WITH (fillfactor = 90)"),
+		DirectionDown: ast.NewRawSQL("DROP INDEX idx_users_email"),
+	}
+	filter := func(op PlannedOperation) (PlannedOperation, bool) {
+		op.Node = replacements[op.Direction]
+		return op, true
+	}
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		&types.SchemaDiff{IndexesAdded: []string{"idx_users_email"}},
+		indexOnlyGeneratedSchema(),
+		&dbschematypes.DBSchema{},
+		postgresInfo(capability.Postgres17()),
+		100,
+		"add_index",
+		planOptions{statementFilter: filter},
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs, qt.HasLen, 1)
+	c.Assert(specs[0].UpSQL, qt.Contains, "WITH (fillfactor = 90);")
+	c.Assert(specs[0].DownSQL, qt.Contains, "DROP INDEX idx_users_email;")
+}
+
+func TestPlanGeneratedMigrationSpecs_StatementFilterDroppingEverythingIsNoOp(t *testing.T) {
+	c := qt.New(t)
+	dropAll := func(op PlannedOperation) (PlannedOperation, bool) { return op, false }
+
+	specs, _, err := planGeneratedMigrationSpecs(
+		&types.SchemaDiff{IndexesAdded: []string{"idx_users_email"}},
+		indexOnlyGeneratedSchema(),
+		&dbschematypes.DBSchema{},
+		postgresInfo(capability.Postgres17()),
+		100,
+		"add_index",
+		planOptions{statementFilter: dropAll},
+	)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(specs, qt.HasLen, 0)
+}
@@ -0,0 +1,50 @@
+package generator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/ptaherr"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+func TestGenerateMigration_StrictFailsOnAnnotationErrors(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"models/user.go": &fstest.MapFile{Data: []byte(`package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+
+	//migrator:schema:feild name="email" type="TEXT"
+	Email string
+
+	//migrator:schema:field name="name" tpye="TEXT"
+	Name string
+}
+`)},
+	}
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesFS:  fsys,
+		GoEntitiesDir: "models",
+		OutputDir:     t.TempDir(),
+		Strict:        true,
+	})
+
+	c.Assert(files, qt.IsNil)
+	var annotationErrors *generator.AnnotationErrors
+	c.Assert(errors.As(err, &annotationErrors), qt.IsTrue)
+	c.Assert(annotationErrors.Errors, qt.HasLen, 2)
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrUnknownDirective)
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrUnknownAttribute)
+	c.Assert(err, qt.ErrorMatches, `2 annotation error\(s\) in Go entities:
+  models/user.go:8:4: unknown annotation directive //migrator:schema:feild; did you mean //migrator:schema:field\?
+  models/user.go:11:38: unknown attribute "tpye" on //migrator:schema:field; did you mean type\?`)
+}
@@ -0,0 +1,72 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/ptaherr"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+	"github.com/stokaro/ptah/migration/planner"
+)
+
+const strictDialectModels = `package models
+
+//migrator:schema:function name="current_tenant" returns="TEXT" language="plpgsql" body="BEGIN RETURN current_setting('app.tenant', true); END;"
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+}
+`
+
+// generateStrictDialectMigration generates strictDialectModels for MySQL
+// against an empty SQLite database.
+func generateStrictDialectMigration(c *qt.C, strict bool) (*generator.MigrationFiles, error) {
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "models.go"), []byte(strictDialectModels), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(context.Background(), "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+
+	return generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        conn,
+		MigrationName: "create_users",
+		OutputDir:     filepath.Join(tempDir, "migrations"),
+		TargetDialect: "mysql",
+		StrictDialect: strict,
+	})
+}
+
+func TestGenerateMigration_ReportsSkippedFeatures(t *testing.T) {
+	c := qt.New(t)
+
+	files, err := generateStrictDialectMigration(c, false)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Files, qt.HasLen, 1)
+	c.Assert(files.SkippedFeatures, qt.HasLen, 1)
+	c.Assert(files.SkippedFeatures[0].Kind, qt.Equals, planner.SkippedFunction)
+	c.Assert(files.SkippedFeatures[0].Object, qt.Equals, "current_tenant")
+}
+
+func TestGenerateMigration_StrictDialectRejectsSkippedFeatures(t *testing.T) {
+	c := qt.New(t)
+
+	files, err := generateStrictDialectMigration(c, true)
+
+	c.Assert(files, qt.IsNil)
+	c.Assert(err, qt.ErrorIs, ptaherr.ErrUnsupportedFeature)
+	c.Assert(err, qt.ErrorMatches, `mysql cannot express 1 schema object\(s\): function current_tenant`)
+	var skippedErr *generator.SkippedFeaturesError
+	c.Assert(err, qt.ErrorAs, &skippedErr)
+	c.Assert(skippedErr.Features, qt.HasLen, 1)
+}
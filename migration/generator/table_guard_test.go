@@ -0,0 +1,107 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const tableGuardModels = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+}
+`
+
+// tableGuardDatabase holds the users table and two tables the models do not
+// declare.
+var tableGuardDatabase = []string{
+	"CREATE TABLE users (id INTEGER PRIMARY KEY)",
+	"CREATE TABLE orders (id INTEGER)",
+	"CREATE TABLE invoices (id INTEGER)",
+}
+
+// generateWithTableGuards generates models against a SQLite database created
+// by ddl.
+func generateWithTableGuards(c *qt.C, models string, ddl []string, opts generator.GenerateMigrationOptions) (*generator.MigrationFiles, error) {
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "models.go"), []byte(models), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(context.Background(), "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+	for _, statement := range ddl {
+		_, err := conn.Exec(statement)
+		c.Assert(err, qt.IsNil)
+	}
+
+	opts.GoEntitiesDir = modelsDir
+	opts.DBConn = conn
+	opts.MigrationName = "guarded"
+	opts.OutputDir = filepath.Join(tempDir, "migrations")
+	return generator.GenerateMigration(context.Background(), opts)
+}
+
+func TestGenerateMigration_RefusesTooFewTables(t *testing.T) {
+	tests := []struct {
+		name   string
+		models string
+		opts   generator.GenerateMigrationOptions
+	}{
+		{name: "no tables", models: "package models\n"},
+		{name: "below minimum", models: tableGuardModels, opts: generator.GenerateMigrationOptions{MinExpectedTables: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			files, err := generateWithTableGuards(c, tt.models, tableGuardDatabase, tt.opts)
+
+			c.Assert(files, qt.IsNil)
+			c.Assert(err, qt.ErrorIs, generator.ErrTooFewTables)
+		})
+	}
+}
+
+func TestGenerateMigration_AllowEmptySchema(t *testing.T) {
+	c := qt.New(t)
+
+	files, err := generateWithTableGuards(c, "package models\n", nil, generator.GenerateMigrationOptions{AllowEmptySchema: true})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(files, qt.IsNil)
+}
+
+func TestGenerateMigration_MaxDropRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    generator.GenerateMigrationOptions
+		wantErr error
+	}{
+		{name: "disabled by default"},
+		{name: "within the ratio", opts: generator.GenerateMigrationOptions{MaxDropRatio: 0.7}},
+		{name: "exceeds the ratio", opts: generator.GenerateMigrationOptions{MaxDropRatio: 0.5}, wantErr: generator.ErrTooManyTableDrops},
+		{name: "overridden", opts: generator.GenerateMigrationOptions{MaxDropRatio: 0.5, AllowMassDrop: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, err := generateWithTableGuards(c, tableGuardModels, tableGuardDatabase, tt.opts)
+
+			c.Assert(err, qt.ErrorIs, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const targetDialectModels = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="SERIAL" primary="true"
+	ID int64
+	//migrator:schema:field name="email" type="TEXT" not_null="true"
+	Email string
+}
+`
+
+func TestGenerateMigration_TargetDialect(t *testing.T) {
+	c := qt.New(t)
+	tempDir := c.TempDir()
+	modelsDir := filepath.Join(tempDir, "models")
+	migrationsDir := filepath.Join(tempDir, "migrations")
+	c.Assert(os.MkdirAll(modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.MkdirAll(migrationsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(modelsDir, "models.go"), []byte(targetDialectModels), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(context.Background(), "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+
+	files, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: modelsDir,
+		DBConn:        conn,
+		MigrationName: "create_users",
+		OutputDir:     migrationsDir,
+		TargetDialect: "postgresql",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Files, qt.HasLen, 1)
+
+	up, err := os.ReadFile(files.Files[0].UpFile)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(up), qt.Contains, "-- POSTGRES TABLE: users --")
+	c.Assert(string(up), qt.Contains, `"id" SERIAL PRIMARY KEY`)
+	c.Assert(string(up), qt.Not(qt.Contains), "AUTOINCREMENT")
+}
+
+func TestGenerateMigration_RejectsUnknownTargetDialect(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := generator.GenerateMigration(context.Background(), generator.GenerateMigrationOptions{
+		GoEntitiesDir: c.TempDir(),
+		OutputDir:     c.TempDir(),
+		TargetDialect: "oracle",
+	})
+
+	c.Assert(err, qt.ErrorMatches, `unknown target dialect "oracle"`)
+}
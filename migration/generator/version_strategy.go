@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+// VersionStrategy selects how GenerateMigration numbers the migration files it
+// writes.
+type VersionStrategy string
+
+const (
+	// VersionStrategyTimestamp versions migrations with the Unix timestamp of
+	// GenerateMigrationOptions.Clock, bumped past the newest migration already
+	// in OutputDir. It is the default.
+	VersionStrategyTimestamp VersionStrategy = "timestamp"
+	// VersionStrategySequential versions migrations one above the highest
+	// numeric prefix already in OutputDir, starting at 1 in an empty directory.
+	VersionStrategySequential VersionStrategy = "sequential"
+	// VersionStrategyExplicit uses GenerateMigrationOptions.Version verbatim.
+	VersionStrategyExplicit VersionStrategy = "explicit"
+)
+
+// ParseVersionStrategy parses a version strategy name. Empty selects
+// VersionStrategyTimestamp.
+func ParseVersionStrategy(value string) (VersionStrategy, error) {
+	switch strategy := VersionStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case "":
+		return VersionStrategyTimestamp, nil
+	case VersionStrategyTimestamp, VersionStrategySequential, VersionStrategyExplicit:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unsupported version strategy %q (want timestamp, sequential, or explicit)", value)
+	}
+}
+
+// migrationClock returns the clock configured on opts, defaulting to time.Now.
+func migrationClock(opts GenerateMigrationOptions) func() time.Time {
+	if opts.Clock != nil {
+		return opts.Clock
+	}
+	return time.Now
+}
+
+// resolveMigrationVersion picks the version of the first generated migration
+// according to opts.VersionStrategy. now is the generation time read from the
+// configured clock.
+func resolveMigrationVersion(opts GenerateMigrationOptions, now time.Time) (int64, error) {
+	strategy, err := ParseVersionStrategy(string(opts.VersionStrategy))
+	if err != nil {
+		return 0, err
+	}
+	if strategy != VersionStrategyExplicit && strings.TrimSpace(opts.Version) != "" {
+		return 0, fmt.Errorf("migration version %q requires the %s version strategy", opts.Version, VersionStrategyExplicit)
+	}
+
+	switch strategy {
+	case VersionStrategySequential:
+		return latestExistingMigrationVersion(opts.OutputDir) + 1, nil
+	case VersionStrategyExplicit:
+		return parseExplicitMigrationVersion(opts.Version)
+	default:
+		return nextAvailableMigrationVersion(opts.OutputDir, now.Unix(), opts.MigrationName), nil
+	}
+}
+
+func parseExplicitMigrationVersion(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("the %s version strategy requires a migration version", VersionStrategyExplicit)
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid migration version %q: must be a positive integer", value)
+	}
+	return version, nil
+}
+
+// ensureMigrationVersionsAvailable fails when any planned migration version is
+// already used by a migration file in outputDir, whatever its name, so a
+// generated migration never shadows or overwrites an existing one.
+func ensureMigrationVersionsAvailable(outputDir string, specs []generatedMigrationSpec) error {
+	existing := existingMigrationFilesByVersion(outputDir)
+	for _, spec := range specs {
+		if name, ok := existing[spec.Version]; ok {
+			return fmt.Errorf("migration version %d already exists in %s (%s); refusing to overwrite it", spec.Version, outputDir, name)
+		}
+	}
+	return nil
+}
+
+func existingMigrationFilesByVersion(outputDir string) map[int64]string {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil
+	}
+	files := make(map[int64]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		migrationFile, err := migrator.ParseMigrationFileName(entry.Name())
+		if err != nil {
+			continue
+		}
+		if _, ok := files[migrationFile.Version]; !ok {
+			files[migrationFile.Version] = filepath.Join(outputDir, entry.Name())
+		}
+	}
+	return files
+}
@@ -0,0 +1,170 @@
+package generator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/generator"
+)
+
+const versionStrategyModel = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int64
+}
+`
+
+var versionStrategyNow = time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+
+// versionStrategyFixture is an empty SQLite database, a models directory
+// declaring one table, and an empty migrations directory.
+type versionStrategyFixture struct {
+	conn          *dbschema.DatabaseConnection
+	modelsDir     string
+	migrationsDir string
+}
+
+func newVersionStrategyFixture(c *qt.C) versionStrategyFixture {
+	tempDir := c.TempDir()
+	fixture := versionStrategyFixture{
+		modelsDir:     filepath.Join(tempDir, "models"),
+		migrationsDir: filepath.Join(tempDir, "migrations"),
+	}
+	c.Assert(os.MkdirAll(fixture.modelsDir, 0o755), qt.IsNil)
+	c.Assert(os.MkdirAll(fixture.migrationsDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(fixture.modelsDir, "user.go"), []byte(versionStrategyModel), 0o600), qt.IsNil)
+
+	conn, err := dbschema.ConnectToDatabase(context.Background(), "sqlite://"+filepath.Join(tempDir, "app.db"))
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { dbschema.CloseAndWarn(conn) })
+	fixture.conn = conn
+	return fixture
+}
+
+func (f versionStrategyFixture) generate(opts generator.GenerateMigrationOptions) (*generator.MigrationFiles, error) {
+	opts.GoEntitiesDir = f.modelsDir
+	opts.DBConn = f.conn
+	opts.MigrationName = "create_users"
+	opts.OutputDir = f.migrationsDir
+	opts.Clock = func() time.Time { return versionStrategyNow }
+	return generator.GenerateMigration(context.Background(), opts)
+}
+
+func (f versionStrategyFixture) writeExisting(c *qt.C, name string) {
+	c.Assert(os.WriteFile(filepath.Join(f.migrationsDir, name), []byte("SELECT 1;\n"), 0o600), qt.IsNil)
+}
+
+func (f versionStrategyFixture) fileNames(c *qt.C) []string {
+	entries, err := os.ReadDir(f.migrationsDir)
+	c.Assert(err, qt.IsNil)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func readGeneratedFile(c *qt.C, path string) string {
+	content, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	return string(content)
+}
+
+func TestGenerateMigration_TimestampVersionUsesInjectedClock(t *testing.T) {
+	c := qt.New(t)
+	fixture := newVersionStrategyFixture(c)
+
+	files, err := fixture.generate(generator.GenerateMigrationOptions{})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Version, qt.Equals, versionStrategyNow.Unix())
+	c.Assert(filepath.Base(files.UpFile), qt.Equals, "1773500966_create_users.up.sql")
+	up := readGeneratedFile(c, files.UpFile)
+	c.Assert(up, qt.Contains, "-- Version: 1773500966\n-- Generated on: 2026-03-14T15:09:26Z\n-- Direction: UP\n")
+	down := readGeneratedFile(c, files.DownFile)
+	c.Assert(down, qt.Contains, "-- Version: 1773500966\n-- Generated on: 2026-03-14T15:09:26Z\n-- Direction: DOWN\n")
+}
+
+func TestGenerateMigration_SequentialVersionFollowsHighestExistingPrefix(t *testing.T) {
+	c := qt.New(t)
+	fixture := newVersionStrategyFixture(c)
+	fixture.writeExisting(c, "0000000002_seed.up.sql")
+	fixture.writeExisting(c, "0000000007_backfill.up.sql")
+	fixture.writeExisting(c, "0000000007_backfill.down.sql")
+
+	files, err := fixture.generate(generator.GenerateMigrationOptions{VersionStrategy: generator.VersionStrategySequential})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Version, qt.Equals, int64(8))
+	c.Assert(files.Files, qt.HasLen, 1)
+	c.Assert(filepath.Base(files.UpFile), qt.Equals, "0000000008_create_users.up.sql")
+	c.Assert(filepath.Base(files.DownFile), qt.Equals, "0000000008_create_users.down.sql")
+	c.Assert(readGeneratedFile(c, files.UpFile), qt.Contains, "-- Version: 8\n")
+}
+
+func TestGenerateMigration_SequentialVersionStartsAtOne(t *testing.T) {
+	c := qt.New(t)
+	fixture := newVersionStrategyFixture(c)
+
+	files, err := fixture.generate(generator.GenerateMigrationOptions{VersionStrategy: generator.VersionStrategySequential})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Version, qt.Equals, int64(1))
+	c.Assert(filepath.Base(files.UpFile), qt.Equals, "0000000001_create_users.up.sql")
+}
+
+func TestGenerateMigration_ExplicitVersion(t *testing.T) {
+	c := qt.New(t)
+	fixture := newVersionStrategyFixture(c)
+
+	files, err := fixture.generate(generator.GenerateMigrationOptions{
+		VersionStrategy: generator.VersionStrategyExplicit,
+		Version:         "42",
+	})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(files.Version, qt.Equals, int64(42))
+	c.Assert(files.Files[0].Version, qt.Equals, int64(42))
+	c.Assert(filepath.Base(files.UpFile), qt.Equals, "0000000042_create_users.up.sql")
+	c.Assert(readGeneratedFile(c, files.DownFile), qt.Contains, "-- Version: 42\n")
+}
+
+func TestGenerateMigration_ExplicitVersionConflictIsAnError(t *testing.T) {
+	c := qt.New(t)
+	fixture := newVersionStrategyFixture(c)
+	fixture.writeExisting(c, "0000000042_add_orders.up.sql")
+
+	_, err := fixture.generate(generator.GenerateMigrationOptions{
+		VersionStrategy: generator.VersionStrategyExplicit,
+		Version:         "42",
+	})
+
+	c.Assert(err, qt.ErrorMatches, `migration version 42 already exists in .*0000000042_add_orders\.up\.sql\); refusing to overwrite it`)
+	c.Assert(fixture.fileNames(c), qt.DeepEquals, []string{"0000000042_add_orders.up.sql"})
+}
+
+func TestGenerateMigration_RejectsInvalidVersionOptions(t *testing.T) {
+	c := qt.New(t)
+	fixture := newVersionStrategyFixture(c)
+
+	_, err := fixture.generate(generator.GenerateMigrationOptions{VersionStrategy: generator.VersionStrategyExplicit})
+	c.Assert(err, qt.ErrorMatches, `the explicit version strategy requires a migration version`)
+
+	_, err = fixture.generate(generator.GenerateMigrationOptions{VersionStrategy: generator.VersionStrategyExplicit, Version: "v2"})
+	c.Assert(err, qt.ErrorMatches, `invalid migration version "v2": must be a positive integer`)
+
+	_, err = fixture.generate(generator.GenerateMigrationOptions{Version: "42"})
+	c.Assert(err, qt.ErrorMatches, `migration version "42" requires the explicit version strategy`)
+
+	_, err = fixture.generate(generator.GenerateMigrationOptions{VersionStrategy: "semver"})
+	c.Assert(err, qt.ErrorMatches, `unsupported version strategy "semver" \(want timestamp, sequential, or explicit\)`)
+	c.Assert(fixture.fileNames(c), qt.HasLen, 0)
+}
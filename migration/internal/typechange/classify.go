@@ -0,0 +1,320 @@
+package typechange
+
+import (
+	"math"
+
+	"github.com/stokaro/ptah/core/platform"
+)
+
+// Kind classifies how a column type change treats the values already stored
+// in the column.
+type Kind string
+
+const (
+	// Widening changes keep every existing value representable.
+	Widening Kind = "widening"
+	// Narrowing changes stay in the same family but shrink the range,
+	// length, or precision, so existing values may not fit.
+	Narrowing Kind = "narrowing"
+	// Incompatible changes convert between type families; existing values
+	// may fail to convert or change meaning.
+	Incompatible Kind = "incompatible"
+)
+
+// unbounded is the capacity of types without a length limit.
+const unbounded = math.MaxInt
+
+// Classify reports how changing a column from oldType to newType on dialect
+// affects existing values. It returns "" when the types are the same or when
+// either type is outside the families the rules know, such as enums and
+// user-defined types.
+func Classify(oldType, newType, dialect string) Kind {
+	if Same(oldType, newType) {
+		return ""
+	}
+	oldSpec := classifySpec(oldType, dialect)
+	newSpec := classifySpec(newType, dialect)
+	if oldSpec.name == "" || newSpec.name == "" {
+		return ""
+	}
+	if oldSpec.kind == "" || newSpec.kind == "" {
+		return classifyByArgs(oldSpec, newSpec)
+	}
+	if oldSpec.kind == newSpec.kind {
+		return classifyWithinKind(oldSpec, newSpec, dialect)
+	}
+	return classifyAcrossKinds(oldSpec, newSpec, dialect)
+}
+
+// classifySpec extends parseSpec with the families only Classify needs.
+func classifySpec(raw, dialect string) spec {
+	parsed := parseSpec(raw)
+	if args, ok := DecimalArgs(raw, dialect); ok {
+		parsed.args = args
+		parsed.arg = 0
+		if len(args) > 0 {
+			parsed.arg = args[0]
+		}
+	}
+	if parsed.kind != "" {
+		return parsed
+	}
+	switch {
+	case serialRank(parsed.name) > 0:
+		parsed.kind = "integer"
+	case floatRank(parsed.name, dialect) > 0:
+		parsed.kind = "float"
+	case parsed.name == "bool" || parsed.name == "boolean":
+		parsed.kind = "boolean"
+	case temporalClass(parsed.name) != "":
+		parsed.kind = "temporal"
+	case binaryCapacity(parsed, dialect) > 0:
+		parsed.kind = "binary"
+	case parsed.name == "json" || parsed.name == "jsonb":
+		parsed.kind = "json"
+	case parsed.name == "uuid" || parsed.name == "uniqueidentifier":
+		parsed.kind = "uuid"
+	}
+	return parsed
+}
+
+// classifyByArgs handles types outside the known families: only a change of
+// the length or precision argument on the same type name is classified.
+func classifyByArgs(oldSpec, newSpec spec) Kind {
+	if oldSpec.name != newSpec.name || oldSpec.arg == 0 || newSpec.arg == 0 {
+		return ""
+	}
+	return compareCapacity(oldSpec.arg, newSpec.arg)
+}
+
+func classifyWithinKind(oldSpec, newSpec spec, dialect string) Kind {
+	switch oldSpec.kind {
+	case "string":
+		if isTextType(oldSpec.name) && isSizedString(newSpec.name) {
+			return Narrowing
+		}
+		return compareCapacity(stringCapacity(oldSpec, dialect), stringCapacity(newSpec, dialect))
+	case "integer":
+		return compareCapacity(anyIntegerRank(oldSpec.name), anyIntegerRank(newSpec.name))
+	case "decimal":
+		if len(oldSpec.args) == 0 && len(newSpec.args) > 0 || decimalNarrows(oldSpec.args, newSpec.args) {
+			return Narrowing
+		}
+		return Widening
+	case "float":
+		return compareCapacity(floatRank(oldSpec.name, dialect), floatRank(newSpec.name, dialect))
+	case "binary":
+		return compareCapacity(binaryCapacity(oldSpec, dialect), binaryCapacity(newSpec, dialect))
+	case "temporal":
+		return classifyTemporal(oldSpec, newSpec)
+	default:
+		// Spellings of the same family, such as json and jsonb, hold the
+		// same values.
+		return Widening
+	}
+}
+
+func classifyAcrossKinds(oldSpec, newSpec spec, dialect string) Kind {
+	switch {
+	case newSpec.kind == "string":
+		// Every non-string value has a text form; only a length limit can
+		// reject it.
+		if stringCapacity(newSpec, dialect) == unbounded {
+			return Widening
+		}
+		return Narrowing
+	case oldSpec.kind == "integer" && newSpec.kind == "decimal":
+		if len(newSpec.args) == 0 {
+			return Widening
+		}
+		scale := 0
+		if len(newSpec.args) > 1 {
+			scale = newSpec.args[1]
+		}
+		return compareCapacity(integerDigits(oldSpec.name), newSpec.args[0]-scale)
+	case oldSpec.kind == "integer" && newSpec.kind == "float":
+		// A float keeps 6 (real) or 15 (double) significant digits exactly.
+		digits := 6
+		if floatRank(newSpec.name, dialect) > 1 {
+			digits = 15
+		}
+		return compareCapacity(integerDigits(oldSpec.name), digits)
+	case isNumericKind(oldSpec.kind) && isNumericKind(newSpec.kind):
+		// Scale or precision is lost converting between decimal, float,
+		// and integer in the remaining directions.
+		return Narrowing
+	default:
+		return Incompatible
+	}
+}
+
+func classifyTemporal(oldSpec, newSpec spec) Kind {
+	oldClass := temporalClass(oldSpec.name)
+	newClass := temporalClass(newSpec.name)
+	switch {
+	case oldClass == newClass:
+		if oldSpec.arg > 0 && newSpec.arg > 0 || oldSpec.arg == 0 && newSpec.arg > 0 {
+			// Fractional-second precision defaults to the maximum (6).
+			oldPrecision := oldSpec.arg
+			if oldPrecision == 0 {
+				oldPrecision = 6
+			}
+			return compareCapacity(oldPrecision, newSpec.arg)
+		}
+		return Widening
+	case oldClass == "date" && newClass == "instant":
+		return Widening
+	case oldClass == "instant" && newClass == "date":
+		return Narrowing
+	default:
+		return Incompatible
+	}
+}
+
+// compareCapacity classifies a change from oldCapacity to newCapacity.
+func compareCapacity(oldCapacity, newCapacity int) Kind {
+	if newCapacity < oldCapacity {
+		return Narrowing
+	}
+	return Widening
+}
+
+func isNumericKind(kind string) bool {
+	return kind == "integer" || kind == "decimal" || kind == "float"
+}
+
+// stringCapacity returns the number of characters a string type holds.
+// MySQL-family TEXT types are bounded; PostgreSQL TEXT and an unsized VARCHAR
+// are not.
+func stringCapacity(s spec, dialect string) int {
+	if isTextType(s.name) {
+		if !isMySQLFamily(dialect) {
+			return unbounded
+		}
+		switch s.name {
+		case "tinytext":
+			return 255
+		case "text":
+			return 65535
+		case "mediumtext":
+			return 16777215
+		default:
+			return unbounded
+		}
+	}
+	if s.arg > 0 {
+		return s.arg
+	}
+	if s.name == "varchar" {
+		return unbounded
+	}
+	return 1
+}
+
+// binaryCapacity returns the number of bytes a binary type holds, or 0 when
+// the type is not a binary string.
+func binaryCapacity(s spec, dialect string) int {
+	switch s.name {
+	case "bytea", "longblob":
+		return unbounded
+	case "tinyblob":
+		return 255
+	case "blob":
+		if !isMySQLFamily(dialect) {
+			return unbounded
+		}
+		return 65535
+	case "mediumblob":
+		return 16777215
+	case "binary", "varbinary":
+		if s.arg > 0 {
+			return s.arg
+		}
+		if s.name == "varbinary" {
+			return unbounded
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+func serialRank(name string) int {
+	switch name {
+	case "smallserial", "serial2":
+		return 2
+	case "serial", "serial4":
+		return 4
+	case "bigserial", "serial8":
+		return 5
+	default:
+		return 0
+	}
+}
+
+func anyIntegerRank(name string) int {
+	if rank := integerRank(name); rank > 0 {
+		return rank
+	}
+	return serialRank(name)
+}
+
+// integerDigits returns the decimal digits every value of an integer type
+// fits in.
+func integerDigits(name string) int {
+	switch anyIntegerRank(name) {
+	case 1:
+		return 3
+	case 2:
+		return 5
+	case 3:
+		return 7
+	case 4:
+		return 10
+	default:
+		return 19
+	}
+}
+
+// floatRank orders single- before double-precision floats. PostgreSQL reads
+// an unsized FLOAT as double precision; MySQL reads it as single precision.
+func floatRank(name, dialect string) int {
+	switch name {
+	case "real", "float4":
+		return 1
+	case "double", "float8":
+		return 2
+	case "float":
+		if platform.IsPostgresFamily(dialect) {
+			return 2
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+// temporalClass groups date/time types: "date", "time", or "instant" for
+// timestamp-like types.
+func temporalClass(name string) string {
+	switch name {
+	case "date":
+		return "date"
+	case "time", "timetz", "time with time zone", "time without time zone":
+		return "time"
+	case "timestamp", "timestamptz", "datetime", "datetime2", "datetimeoffset",
+		"timestamp with time zone", "timestamp without time zone":
+		return "instant"
+	default:
+		return ""
+	}
+}
+
+func isMySQLFamily(dialect string) bool {
+	switch platform.NormalizeDialect(dialect) {
+	case platform.MySQL, platform.MariaDB:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,58 @@
+package typechange_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/migration/internal/typechange"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldType string
+		newType string
+		dialect string
+		want    typechange.Kind
+	}{
+		{name: "same type", oldType: "varchar(100)", newType: "VARCHAR(100)", dialect: "postgres", want: ""},
+		{name: "varchar grows", oldType: "varchar(100)", newType: "varchar(255)", dialect: "postgres", want: typechange.Widening},
+		{name: "varchar shrinks", oldType: "character varying(255)", newType: "VARCHAR(50)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "varchar to postgres text", oldType: "varchar(20000)", newType: "TEXT", dialect: "postgres", want: typechange.Widening},
+		{name: "varchar to mysql text", oldType: "varchar(20000)", newType: "TEXT", dialect: "mysql", want: typechange.Widening},
+		{name: "varchar to mysql tinytext", oldType: "varchar(1000)", newType: "TINYTEXT", dialect: "mysql", want: typechange.Narrowing},
+		{name: "mysql longtext to text", oldType: "longtext", newType: "TEXT", dialect: "mysql", want: typechange.Narrowing},
+		{name: "text to varchar", oldType: "text", newType: "VARCHAR(255)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "integer to bigint", oldType: "int4", newType: "BIGINT", dialect: "postgres", want: typechange.Widening},
+		{name: "bigserial to integer", oldType: "bigserial", newType: "INTEGER", dialect: "postgres", want: typechange.Narrowing},
+		{name: "integer to wide decimal", oldType: "integer", newType: "NUMERIC(12,2)", dialect: "postgres", want: typechange.Widening},
+		{name: "bigint to small decimal", oldType: "bigint", newType: "NUMERIC(10,2)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "decimal scale shrinks", oldType: "numeric(12,4)", newType: "NUMERIC(12,2)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "decimal grows", oldType: "numeric(10,2)", newType: "NUMERIC(14,2)", dialect: "postgres", want: typechange.Widening},
+		{name: "decimal to integer", oldType: "numeric(10,2)", newType: "INTEGER", dialect: "postgres", want: typechange.Narrowing},
+		{name: "postgres decimal constraint removed", oldType: "numeric(10,2)", newType: "NUMERIC", dialect: "postgres", want: typechange.Widening},
+		{name: "postgres decimal constraint added", oldType: "numeric", newType: "NUMERIC(10,2)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "mysql bare decimal grows", oldType: "decimal(10,0)", newType: "DECIMAL(12,2)", dialect: "mysql", want: typechange.Widening},
+		{name: "mysql decimal to bare decimal", oldType: "decimal(12,2)", newType: "DECIMAL", dialect: "mysql", want: typechange.Narrowing},
+		{name: "postgres float is double", oldType: "real", newType: "FLOAT", dialect: "postgres", want: typechange.Widening},
+		{name: "mysql double to float", oldType: "double", newType: "FLOAT", dialect: "mysql", want: typechange.Narrowing},
+		{name: "integer to text", oldType: "integer", newType: "TEXT", dialect: "postgres", want: typechange.Widening},
+		{name: "uuid to short varchar", oldType: "uuid", newType: "VARCHAR(10)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "date to timestamp", oldType: "date", newType: "TIMESTAMP", dialect: "postgres", want: typechange.Widening},
+		{name: "timestamp to date", oldType: "timestamp", newType: "DATE", dialect: "postgres", want: typechange.Narrowing},
+		{name: "timestamp precision shrinks", oldType: "timestamp(6)", newType: "TIMESTAMP(3)", dialect: "postgres", want: typechange.Narrowing},
+		{name: "json to jsonb", oldType: "json", newType: "JSONB", dialect: "postgres", want: typechange.Widening},
+		{name: "text to integer", oldType: "text", newType: "INTEGER", dialect: "postgres", want: typechange.Incompatible},
+		{name: "boolean to integer", oldType: "boolean", newType: "INTEGER", dialect: "postgres", want: typechange.Incompatible},
+		{name: "time to timestamp", oldType: "time", newType: "TIMESTAMP", dialect: "postgres", want: typechange.Incompatible},
+		{name: "unknown types", oldType: "mood", newType: "status", dialect: "postgres", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(typechange.Classify(tt.oldType, tt.newType, tt.dialect), qt.Equals, tt.want)
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/stokaro/ptah/core/platform"
 )
 
 var typeArgRe = regexp.MustCompile(`^([a-zA-Z0-9_ ]+)\(([^)]*)\)$`)
@@ -125,3 +127,57 @@ func decimalNarrows(oldArgs, newArgs []int) bool {
 	newIntegerDigits := newArgs[0] - newScale
 	return newScale < oldScale || newIntegerDigits < oldIntegerDigits
 }
+
+// ParametersDiffer reports whether two spellings of the same sized string or
+// decimal type declare different lengths, precisions, or scales. A side that
+// leaves the parameters unspecified has no opinion, so the result is false.
+// A missing decimal scale means 0.
+func ParametersDiffer(oldType, newType string) bool {
+	oldSpec := parseSpec(oldType)
+	newSpec := parseSpec(newType)
+	if len(oldSpec.args) == 0 || len(newSpec.args) == 0 {
+		return false
+	}
+	switch {
+	case isSizedString(oldSpec.name) && isSizedString(newSpec.name):
+		return oldSpec.arg != newSpec.arg
+	case oldSpec.kind == "decimal" && newSpec.kind == "decimal":
+		return oldSpec.arg != newSpec.arg || decimalScale(oldSpec.args) != decimalScale(newSpec.args)
+	default:
+		return false
+	}
+}
+
+func decimalScale(args []int) int {
+	if len(args) < 2 {
+		return 0
+	}
+	return args[1]
+}
+
+// DecimalArgs returns the precision and scale of a DECIMAL or NUMERIC type as
+// dialect stores it, filling in what the dialect assumes when they are
+// omitted: MySQL and MariaDB store a bare DECIMAL as DECIMAL(10,0), and
+// DECIMAL(p) has a scale of 0. PostgreSQL, CockroachDB, and YugabyteDB
+// NUMERIC without a precision is unconstrained and yields nil args. ok is
+// false for other types and for other dialects, whose reading of an omitted
+// precision is not modeled.
+func DecimalArgs(rawType, dialect string) (args []int, ok bool) {
+	parsed := parseSpec(rawType)
+	if parsed.kind != "decimal" {
+		return nil, false
+	}
+	switch platform.NormalizeDialect(dialect) {
+	case platform.Postgres, platform.CockroachDB, platform.YugabyteDB:
+		if len(parsed.args) == 0 {
+			return nil, true
+		}
+	case platform.MySQL, platform.MariaDB:
+		if len(parsed.args) == 0 {
+			return []int{10, 0}, true
+		}
+	default:
+		return nil, false
+	}
+	return []int{parsed.arg, decimalScale(parsed.args)}, true
+}
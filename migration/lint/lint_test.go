@@ -308,6 +308,8 @@ func TestLintFS_AtlasAnalyzerCatalogHazards(t *testing.T) {
 		{"set not null", "postgres", "ALTER TABLE t ALTER COLUMN c SET NOT NULL;", []string{"PG303"}},
 		{"add check", "postgres", "ALTER TABLE t ADD CONSTRAINT ck CHECK (id > 0);", []string{"PG305"}},
 		{"add foreign key", "postgres", "ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (p_id) REFERENCES p (id);", []string{"PG306"}},
+		{"add check not valid", "postgres", "ALTER TABLE t ADD CONSTRAINT ck CHECK (id > 0) NOT VALID;", []string{}},
+		{"add foreign key not valid", "postgres", "ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (p_id) REFERENCES p (id) NOT VALID;", []string{}},
 		{"set unlogged", "postgres", "ALTER TABLE t SET UNLOGGED;", []string{"PG307"}},
 		{"create trigger", "postgres", "CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW EXECUTE FUNCTION f();", []string{"PG308"}},
 		{"stored generated column", "postgres", "ALTER TABLE t ADD COLUMN g INT GENERATED ALWAYS AS (c * 2) STORED;", []string{"PG309"}},
@@ -1144,8 +1144,9 @@ func scanAddUniqueConstraint(w []string) bool {
 func scanAddCheckConstraint(w []string) bool {
 	for _, i := range clauseStarts(w) {
 		clause := w[i:clauseEnd(w, i)]
-		if hasWordPrefix(clause, "ADD", "CHECK") ||
-			hasWordPrefix(clause, "ADD", "CONSTRAINT") && slices.Contains(clause, "CHECK") {
+		if (hasWordPrefix(clause, "ADD", "CHECK") ||
+			hasWordPrefix(clause, "ADD", "CONSTRAINT") && slices.Contains(clause, "CHECK")) &&
+			!hasWordSeq(clause, "NOT", "VALID") {
 			return true
 		}
 	}
@@ -1155,8 +1156,9 @@ func scanAddCheckConstraint(w []string) bool {
 func scanAddForeignKey(w []string) bool {
 	for _, i := range clauseStarts(w) {
 		clause := w[i:clauseEnd(w, i)]
-		if hasWordPrefix(clause, "ADD", "FOREIGN", "KEY") ||
-			hasWordPrefix(clause, "ADD", "CONSTRAINT") && hasWordSeq(clause, "FOREIGN", "KEY") {
+		if (hasWordPrefix(clause, "ADD", "FOREIGN", "KEY") ||
+			hasWordPrefix(clause, "ADD", "CONSTRAINT") && hasWordSeq(clause, "FOREIGN", "KEY")) &&
+			!hasWordSeq(clause, "NOT", "VALID") {
 			return true
 		}
 	}
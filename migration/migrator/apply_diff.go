@@ -0,0 +1,123 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/safety"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// defaultApplyDiffDescription labels versions recorded by ApplyDiff when the
+// caller does not supply a description.
+const defaultApplyDiffDescription = "apply schema diff"
+
+// ErrDestructiveDiff is returned by ApplyDiff when the planned statements
+// drop or narrow existing objects and AllowDestructive is not set.
+var ErrDestructiveDiff = errors.New("destructive schema diff statements require AllowDestructive")
+
+// ApplyDiffOptions configures ApplyDiff.
+type ApplyDiffOptions struct {
+	// Generated is the target schema the diff was computed from. The planner
+	// reads full object definitions from it; it is required.
+	Generated *goschema.Database
+	// Version is recorded in the migrations table for the applied diff. Zero
+	// uses the current Unix time, raised above the highest applied version so
+	// the synthetic migration is never treated as out of order. An explicit
+	// version that is already applied is rejected rather than skipped.
+	Version int64
+	// Description labels the recorded version. Empty uses "apply schema diff".
+	Description string
+	// AllowDestructive permits plans with destructive statements, mirroring
+	// --allow-destructive on generated migrations.
+	AllowDestructive bool
+	// DryRun logs the planned migration instead of executing it or recording
+	// a version. It applies to this call only; the connection's writer is not
+	// switched to dry-run mode.
+	DryRun bool
+}
+
+// ApplyDiff plans the up statements for diff and applies them to conn as one
+// migration, without writing migration files. The statements run in the
+// migrator's per-migration transaction unless the plan contains statements
+// that cannot, such as concurrent index builds, and the migration is recorded
+// under a synthetic version in the migrations table. It takes the migration
+// lock like MigrateUp and refuses destructive plans unless
+// opts.AllowDestructive is set. A diff without changes is a no-op.
+func ApplyDiff(ctx context.Context, conn *dbschema.DatabaseConnection, diff *types.SchemaDiff, opts ApplyDiffOptions) error {
+	if conn == nil {
+		return fmt.Errorf("apply diff: database connection is required")
+	}
+	if diff == nil || !diff.HasChanges() {
+		return nil
+	}
+	if opts.Generated == nil {
+		return fmt.Errorf("apply diff: target schema is required to plan the diff")
+	}
+
+	info := conn.Info()
+	nodes, err := planner.GenerateSchemaDiffASTWithCapabilities(diff, opts.Generated, info.Dialect, info.Capabilities)
+	if err != nil {
+		return fmt.Errorf("apply diff: %w", err)
+	}
+	assessments, err := safety.AssessRenderedWithCapabilities(nodes, info.Dialect, info.Capabilities)
+	if err != nil {
+		return fmt.Errorf("apply diff: %w", err)
+	}
+	if safety.HasDestructiveAssessment(assessments) && !opts.AllowDestructive {
+		return ErrDestructiveDiff
+	}
+	upSQL, err := renderer.RenderSQLWithCapabilities(info.Dialect, info.Capabilities, nodes...)
+	if err != nil {
+		return fmt.Errorf("apply diff: %w", err)
+	}
+
+	description := opts.Description
+	if description == "" {
+		description = defaultApplyDiffDescription
+	}
+	noTransaction := planner.RequiresNoTransaction(info.Dialect, nodes)
+
+	provider := NewRegisteredMigrationProvider()
+	m := NewMigrator(conn, provider, WithDryRun(opts.DryRun))
+	return m.withMigrationLock(ctx, "apply diff", func(ctx context.Context) error {
+		version := opts.Version
+		if version == 0 {
+			current, err := m.GetCurrentVersion(ctx)
+			if err != nil {
+				return fmt.Errorf("apply diff: %w", err)
+			}
+			version = max(GetNextMigrationVersion(), current+1)
+		} else if err := m.failIfVersionApplied(ctx, version); err != nil {
+			return err
+		}
+		migration := CreateMigrationFromSQL(version, description, upSQL, "")
+		migration.UpNoTransaction = noTransaction
+		migration.NoTransaction = noTransaction
+		provider.Register(migration)
+		return m.migrateUpLocked(ctx, MigrateUpOptions{TargetVersion: version})
+	})
+}
+
+// failIfVersionApplied rejects an explicit ApplyDiff version that is already
+// recorded; migrateUpLocked would otherwise treat the registered migration as
+// applied and silently skip the diff.
+func (m *Migrator) failIfVersionApplied(ctx context.Context, version int64) error {
+	if err := m.Initialize(ctx); err != nil {
+		return fmt.Errorf("apply diff: failed to initialize migrations table: %w", err)
+	}
+	applied, err := m.GetAppliedMigrations(ctx)
+	if err != nil {
+		return fmt.Errorf("apply diff: %w", err)
+	}
+	if slices.Contains(applied, version) {
+		return fmt.Errorf("apply diff: version %d is already applied", version)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+	"github.com/stokaro/ptah/migration/schemadiff"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+const applyDiffUsersSource = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="TEXT" not_null="true"
+	Email string
+}
+`
+
+const applyDiffEmptySource = `package models
+`
+
+func openApplyDiffSQLite(t *testing.T) *dbschema.DatabaseConnection {
+	t.Helper()
+	conn, err := dbschema.ConnectToDatabase(context.Background(), "sqlite://"+filepath.Join(t.TempDir(), "apply.db"))
+	qt.Assert(t, err, qt.IsNil)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// diffAgainstLive parses source and compares it with the live SQLite schema.
+func diffAgainstLive(t *testing.T, conn *dbschema.DatabaseConnection, source string) (*goschema.Database, *difftypes.SchemaDiff) {
+	t.Helper()
+	generated, err := goschema.ParseSource("models.go", source)
+	qt.Assert(t, err, qt.IsNil)
+	live, err := conn.Reader().ReadSchema()
+	qt.Assert(t, err, qt.IsNil)
+	return &generated, schemadiff.CompareWithDialect(&generated, live, platform.SQLite)
+}
+
+func appliedVersions(t *testing.T, conn *dbschema.DatabaseConnection) []int64 {
+	t.Helper()
+	versions, err := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider()).GetAppliedMigrations(context.Background())
+	qt.Assert(t, err, qt.IsNil)
+	return versions
+}
+
+func TestApplyDiffCreatesObjectsAndRecordsVersion(t *testing.T) {
+	c := qt.New(t)
+	conn := openApplyDiffSQLite(t)
+	generated, diff := diffAgainstLive(t, conn, applyDiffUsersSource)
+
+	err := migrator.ApplyDiff(context.Background(), conn, diff, migrator.ApplyDiffOptions{Generated: generated, Version: 42})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(usersTableExists(t, conn), qt.IsTrue)
+	c.Assert(appliedVersions(t, conn), qt.DeepEquals, []int64{42})
+	_, remaining := diffAgainstLive(t, conn, applyDiffUsersSource)
+	c.Assert(remaining.HasChanges(), qt.IsFalse)
+}
+
+func TestApplyDiffRejectsAppliedVersion(t *testing.T) {
+	c := qt.New(t)
+	conn := openApplyDiffSQLite(t)
+	ctx := context.Background()
+	m := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(
+		migrator.CreateMigrationFromSQL(42, "marker", "SELECT 1;", ""),
+	))
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+	generated, diff := diffAgainstLive(t, conn, applyDiffUsersSource)
+
+	err := migrator.ApplyDiff(ctx, conn, diff, migrator.ApplyDiffOptions{Generated: generated, Version: 42})
+
+	c.Assert(err, qt.ErrorMatches, "apply diff: version 42 is already applied")
+	c.Assert(usersTableExists(t, conn), qt.IsFalse)
+	c.Assert(appliedVersions(t, conn), qt.DeepEquals, []int64{42})
+}
+
+func TestApplyDiffVersionFollowsAppliedMigrations(t *testing.T) {
+	c := qt.New(t)
+	conn := openApplyDiffSQLite(t)
+	ctx := context.Background()
+	future := migrator.GetNextMigrationVersion() + 1000
+	m := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(
+		migrator.CreateMigrationFromSQL(future, "marker", "SELECT 1;", ""),
+	))
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+	generated, diff := diffAgainstLive(t, conn, applyDiffUsersSource)
+
+	err := migrator.ApplyDiff(ctx, conn, diff, migrator.ApplyDiffOptions{Generated: generated})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(appliedVersions(t, conn), qt.DeepEquals, []int64{future, future + 1})
+}
+
+func TestApplyDiffRefusesDestructivePlans(t *testing.T) {
+	tests := []struct {
+		name             string
+		allowDestructive bool
+		wantErr          error
+		wantUsers        bool
+	}{
+		{name: "guarded", wantErr: migrator.ErrDestructiveDiff, wantUsers: true},
+		{name: "allowed", allowDestructive: true, wantUsers: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			conn := openApplyDiffSQLite(t)
+			_, err := conn.Exec("CREATE TABLE users (id INTEGER)")
+			c.Assert(err, qt.IsNil)
+			generated, diff := diffAgainstLive(t, conn, applyDiffEmptySource)
+
+			err = migrator.ApplyDiff(context.Background(), conn, diff, migrator.ApplyDiffOptions{
+				Generated:        generated,
+				AllowDestructive: tt.allowDestructive,
+			})
+
+			c.Assert(err, qt.Equals, tt.wantErr)
+			c.Assert(usersTableExists(t, conn), qt.Equals, tt.wantUsers)
+		})
+	}
+}
+
+func TestApplyDiffDryRunChangesNothing(t *testing.T) {
+	c := qt.New(t)
+	conn := openApplyDiffSQLite(t)
+	generated, diff := diffAgainstLive(t, conn, applyDiffUsersSource)
+
+	err := migrator.ApplyDiff(context.Background(), conn, diff, migrator.ApplyDiffOptions{Generated: generated, DryRun: true})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(usersTableExists(t, conn), qt.IsFalse)
+	c.Assert(conn.Writer().IsDryRun(), qt.IsFalse)
+	c.Assert(appliedVersions(t, conn), qt.HasLen, 0)
+}
@@ -0,0 +1,128 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/sqlutil"
+	"github.com/stokaro/ptah/dbschema"
+)
+
+// batchSavepoint guards each statement batch so a failed batch can be undone
+// and replayed statement by statement.
+const batchSavepoint = "ptah_batch"
+
+// ExecutionOptions configures how migration statements are sent to the
+// database.
+//
+// With BatchSize above one, consecutive statements of a transactional SQL
+// migration are joined and sent in a single round-trip, so a migration with
+// thousands of statements no longer pays the network latency once per
+// statement. Batching applies only where the driver executes several
+// statements per call and the migration runs inside a transaction:
+// PostgreSQL and SQLite under tx-mode file or all. no_transaction migrations,
+// tx-mode none, dry runs, statement interceptors, and other dialects execute
+// one statement at a time as before.
+//
+// Each batch runs under a savepoint. When a batch fails, the savepoint is
+// rolled back and the batch is replayed one statement at a time, so the
+// returned MigrationExecutionError still names the failing statement.
+type ExecutionOptions struct {
+	// BatchSize is the maximum number of statements sent per round-trip.
+	// Zero or one disables batching.
+	BatchSize int
+}
+
+// WithExecutionOptions configures statement batching. Batching is disabled by
+// default.
+func (m *Migrator) WithExecutionOptions(opts ExecutionOptions) *Migrator {
+	tmp := *m
+	tmp.execution = opts
+	return &tmp
+}
+
+type batchSizeKey struct{}
+
+// transactionalContext marks ctx as running migration bodies inside a
+// transaction, which lets SQL migrations batch their statements.
+func (m *Migrator) transactionalContext(ctx context.Context) context.Context {
+	if m.execution.BatchSize <= 1 {
+		return ctx
+	}
+	return context.WithValue(ctx, batchSizeKey{}, m.execution.BatchSize)
+}
+
+// statementBatchSize returns the batch size for statements executed on conn,
+// or one when they must run individually.
+func statementBatchSize(ctx context.Context, conn *dbschema.DatabaseConnection, mode migrationExecutionMode) int {
+	size, _ := ctx.Value(batchSizeKey{}).(int)
+	if size <= 1 || mode != migrationExecutionTransactional || conn.Writer().IsDryRun() {
+		return 1
+	}
+	switch platform.NormalizeDialect(conn.Info().Dialect) {
+	case platform.Postgres, platform.SQLite:
+		return size
+	default:
+		return 1
+	}
+}
+
+// executeStatementBatch runs statements[start:] up to size statements in one
+// call. On failure it rolls back to the batch savepoint and replays the batch
+// one statement at a time to attribute the error.
+func executeStatementBatch(
+	ctx context.Context,
+	conn *dbschema.DatabaseConnection,
+	statements []string,
+	start, size int,
+	errPrefix string,
+) error {
+	batch := statements[start:min(start+size, len(statements))]
+	expanded := make([]string, len(batch))
+	for i, stmt := range batch {
+		sql, err := sqlutil.ExpandEnvPlaceholders(stmt, os.LookupEnv)
+		if err != nil {
+			return &MigrationExecutionError{
+				Err:            fmt.Errorf("%s: %w", errPrefix, err),
+				Statement:      stmt,
+				StatementIndex: start + i + 1,
+				Total:          len(statements),
+			}
+		}
+		expanded[i] = sql
+	}
+
+	writer := conn.Writer()
+	if err := writer.ExecuteSQL(ctx, "SAVEPOINT "+batchSavepoint); err != nil {
+		return fmt.Errorf("failed to open statement batch: %w", err)
+	}
+	batchErr := writer.ExecuteSQL(ctx, joinStatements(expanded))
+	if batchErr == nil {
+		if err := writer.ExecuteSQL(ctx, "RELEASE SAVEPOINT "+batchSavepoint); err != nil {
+			return fmt.Errorf("failed to close statement batch: %w", err)
+		}
+		return nil
+	}
+	if err := writer.ExecuteSQL(ctx, "ROLLBACK TO SAVEPOINT "+batchSavepoint); err != nil {
+		return fmt.Errorf("failed to roll back statements %d-%d after %v: %w",
+			start+1, start+len(batch), &redactedStatementError{err: batchErr, expanded: joinStatements(expanded), stmt: joinStatements(batch)}, err)
+	}
+	for i, stmt := range batch {
+		if err := executeMigrationStatementWithEnv(ctx, conn, stmt, migrationExecutionTransactional); err != nil {
+			return &MigrationExecutionError{
+				Err:            fmt.Errorf("%s: %w", errPrefix, err),
+				Statement:      stmt,
+				StatementIndex: start + i + 1,
+				Total:          len(statements),
+			}
+		}
+	}
+	return writer.ExecuteSQL(ctx, "RELEASE SAVEPOINT "+batchSavepoint)
+}
+
+func joinStatements(statements []string) string {
+	return strings.Join(statements, ";\n") + ";"
+}
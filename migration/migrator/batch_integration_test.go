@@ -0,0 +1,93 @@
+package migrator_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func TestMigrateUp_BatchSizePostgresReportsFailingStatement(t *testing.T) {
+	dbURL := postgresTestURL(t)
+	c := qt.New(t)
+	ctx := context.Background()
+
+	conn, err := dbschema.ConnectToDatabase(ctx, dbURL)
+	c.Assert(err, qt.IsNil)
+	defer func() { _ = conn.Close() }()
+	cleanupBatchSchema(t, conn)
+	defer cleanupBatchSchema(t, conn)
+
+	err = batchMigrator(conn, postgresCreateTablesSQL(50, 37), 20).MigrateUp(ctx)
+
+	var execErr *migrator.MigrationExecutionError
+	c.Assert(err, qt.ErrorAs, &execErr)
+	c.Assert(execErr.StatementIndex, qt.Equals, 37)
+	c.Assert(execErr.Statement, qt.Equals, "INSERT INTO ptah_batch.missing (id) VALUES (37)")
+	c.Assert(schemaExists(t, conn, "ptah_batch"), qt.IsFalse)
+}
+
+// BenchmarkMigrateUp_BatchSizePostgres applies a 1000-statement migration to
+// PostgreSQL. Every unbatched statement pays one network round-trip.
+func BenchmarkMigrateUp_BatchSizePostgres(b *testing.B) {
+	dbURL := postgresTestURL(b)
+	ctx := context.Background()
+	conn, err := dbschema.ConnectToDatabase(ctx, dbURL)
+	qt.Assert(b, err, qt.IsNil)
+	defer func() { _ = conn.Close() }()
+
+	upSQL := postgresCreateTablesSQL(1000, 0)
+	for _, batchSize := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("batch=%d", batchSize), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				cleanupBatchSchema(b, conn)
+				m := batchMigrator(conn, upSQL, batchSize)
+				b.StartTimer()
+
+				qt.Assert(b, m.MigrateUp(ctx), qt.IsNil)
+			}
+			b.StopTimer()
+			cleanupBatchSchema(b, conn)
+		})
+	}
+}
+
+// postgresCreateTablesSQL returns a migration body that creates the
+// ptah_batch schema followed by n-1 tables in it. When failAt is positive,
+// that statement inserts into a missing table instead.
+func postgresCreateTablesSQL(n, failAt int) string {
+	var sb strings.Builder
+	sb.WriteString("CREATE SCHEMA ptah_batch;\n")
+	for i := 2; i <= n; i++ {
+		if i == failAt {
+			fmt.Fprintf(&sb, "INSERT INTO ptah_batch.missing (id) VALUES (%d);\n", i)
+			continue
+		}
+		fmt.Fprintf(&sb, "CREATE TABLE ptah_batch.t%04d (id INTEGER PRIMARY KEY);\n", i)
+	}
+	return sb.String()
+}
+
+func batchMigrator(conn *dbschema.DatabaseConnection, upSQL string, batchSize int) *migrator.Migrator {
+	migration := migrator.CreateMigrationFromSQL(1, "many_tables", upSQL, "DROP SCHEMA ptah_batch CASCADE;")
+	return migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(migration)).
+		WithMigrationsTable("", "schema_migrations_batch").
+		WithExecutionOptions(migrator.ExecutionOptions{BatchSize: batchSize})
+}
+
+func cleanupBatchSchema(tb testing.TB, conn *dbschema.DatabaseConnection) {
+	tb.Helper()
+
+	for _, statement := range []string{
+		"DROP SCHEMA IF EXISTS ptah_batch CASCADE",
+		"DROP TABLE IF EXISTS schema_migrations_batch",
+	} {
+		_, _ = conn.ExecContext(context.Background(), statement)
+	}
+}
@@ -0,0 +1,69 @@
+package migrator
+
+// White-box testing required: statement batching is enabled through a context
+// the migrator builds only around its migration transactions, and round-trips
+// are observable only at the writer.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema/dbschematest"
+)
+
+func manyCreateTables(n int) string {
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "CREATE TABLE batch_t%04d (id INTEGER PRIMARY KEY);\n", i)
+	}
+	return sb.String()
+}
+
+func countContaining(statements []string, substr string) int {
+	count := 0
+	for _, stmt := range statements {
+		if strings.Contains(stmt, substr) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestExecuteSQLStatements_BatchSizeGroupsStatementsPerRoundTrip(t *testing.T) {
+	tests := []struct {
+		name           string
+		dialect        string
+		batchSize      int
+		mode           migrationExecutionMode
+		wantRoundTrips int
+		wantSavepoints int
+	}{
+		{name: "postgres batched", dialect: "postgres", batchSize: 100, wantRoundTrips: 10, wantSavepoints: 10},
+		{name: "postgres partial last batch", dialect: "postgres", batchSize: 300, wantRoundTrips: 4, wantSavepoints: 4},
+		{name: "sqlite batched", dialect: "sqlite", batchSize: 500, wantRoundTrips: 2, wantSavepoints: 2},
+		{name: "unbatched by default", dialect: "postgres", wantRoundTrips: 1000},
+		{name: "no_transaction never batched", dialect: "postgres", batchSize: 100, mode: migrationExecutionNoTransaction, wantRoundTrips: 1000},
+		{name: "mysql never batched", dialect: "mysql", batchSize: 100, wantRoundTrips: 1000},
+		{name: "sqlserver never batched", dialect: "sqlserver", batchSize: 100, wantRoundTrips: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			fake := dbschematest.NewFakeConnection(t, tt.dialect, nil)
+			m := (&Migrator{}).WithExecutionOptions(ExecutionOptions{BatchSize: tt.batchSize})
+
+			err := executeSQLStatements(m.transactionalContext(context.Background()), fake.DatabaseConnection, manyCreateTables(1000), tt.mode)
+
+			c.Assert(err, qt.IsNil)
+			executed := fake.ExecutedSQL()
+			c.Assert(countContaining(executed, "CREATE TABLE batch_t"), qt.Equals, tt.wantRoundTrips)
+			c.Assert(countContaining(executed, "SAVEPOINT "+batchSavepoint), qt.Equals, 2*tt.wantSavepoints)
+			c.Assert(strings.Count(strings.Join(executed, "\n"), "CREATE TABLE batch_t"), qt.Equals, 1000)
+		})
+	}
+}
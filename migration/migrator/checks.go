@@ -172,7 +172,7 @@ func (e *CheckFailedError) Unwrap() error {
 // Checks are read-only assertions on the pre-migration state; on the
 // transactional apply paths conn is the migration's transaction connection, so
 // a failure rolls back with nothing applied.
-func runChecks(ctx context.Context, conn *dbschema.DatabaseConnection, version int64, checks []Check) error {
+func runChecks(ctx context.Context, conn dbschema.Querier, version int64, checks []Check) error {
 	for _, check := range checks {
 		var result any
 		if err := conn.QueryRowContext(ctx, check.Assert).Scan(&result); err != nil {
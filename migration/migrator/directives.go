@@ -1,6 +1,7 @@
 package migrator
 
 import (
+	"iter"
 	"strings"
 
 	"github.com/stokaro/ptah/internal/lexer"
@@ -12,6 +13,24 @@ import (
 //	-- +ptah no_transaction
 const directivePrefix = "+ptah"
 
+// metadataPrefix marks a generated-file metadata line:
+//
+//	-- ptah:dialect=postgres
+const metadataPrefix = "ptah:"
+
+// Metadata keys the generator writes into migration file headers.
+const (
+	// MetadataPtahVersion is the ptah version that generated the file.
+	MetadataPtahVersion = "version"
+	// MetadataSourceSHA is the git commit of the source entities, when known.
+	MetadataSourceSHA = "source_sha"
+	// MetadataDialect is the dialect the file's SQL targets.
+	MetadataDialect = "dialect"
+	// MetadataOptionsHash identifies the generation options that shaped the
+	// SQL, so files generated with different options can be told apart.
+	MetadataOptionsHash = "options_hash"
+)
+
 // ParseFileDirectives extracts `-- +ptah key=value` annotations from migration
 // SQL. Directives are file-scoped: every annotated line contributes to one
 // merged map (later lines win on duplicate keys). Bare no_transaction is a
@@ -27,23 +46,8 @@ const directivePrefix = "+ptah"
 // statement is not treated as a directive either.
 func ParseFileDirectives(sql string) map[string]string {
 	directives := map[string]string{}
-	lexr := lexer.NewLexer(sql)
-	for {
-		tok := lexr.NextToken()
-		if tok.Type == lexer.TokenEOF {
-			break
-		}
-		if tok.Type != lexer.TokenComment {
-			continue
-		}
-		body, ok := strings.CutPrefix(tok.Value, "--")
-		if !ok {
-			continue // block comment: not a directive carrier
-		}
-		if !commentStartsLine(sql, tok.Start) {
-			continue // trailing comment: not a directive
-		}
-		body, ok = strings.CutPrefix(strings.TrimSpace(body), directivePrefix)
+	for body := range lineComments(sql) {
+		body, ok := strings.CutPrefix(body, directivePrefix)
 		if !ok || (body != "" && body[0] != ' ' && body[0] != '\t') {
 			continue
 		}
@@ -63,6 +67,53 @@ func ParseFileDirectives(sql string) map[string]string {
 	return directives
 }
 
+// ParseFileMetadata extracts the `-- ptah:key=value` metadata lines the
+// generator writes into migration file headers, keyed without the ptah:
+// prefix (later lines win on duplicate keys). Like directives, a metadata line
+// must be a line comment that begins its physical line; lines without an
+// equals sign are ignored, so `-- ptah:nolint` lint suppressions never read as
+// metadata.
+func ParseFileMetadata(sql string) map[string]string {
+	metadata := map[string]string{}
+	for body := range lineComments(sql) {
+		body, ok := strings.CutPrefix(body, metadataPrefix)
+		if !ok {
+			continue
+		}
+		key, value, found := strings.Cut(body, "=")
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			continue
+		}
+		metadata[key] = strings.TrimSpace(value)
+	}
+	return metadata
+}
+
+// lineComments yields the trimmed bodies of the `--` comments in sql that
+// begin their physical line, skipping block comments, trailing comments and
+// comment markers inside string literals.
+func lineComments(sql string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		lexr := lexer.NewLexer(sql)
+		for {
+			tok := lexr.NextToken()
+			if tok.Type == lexer.TokenEOF {
+				return
+			}
+			if tok.Type != lexer.TokenComment {
+				continue
+			}
+			body, ok := strings.CutPrefix(tok.Value, "--")
+			if !ok || !commentStartsLine(sql, tok.Start) {
+				continue
+			}
+			if !yield(strings.TrimSpace(body)) {
+				return
+			}
+		}
+	}
+}
+
 // commentStartsLine reports whether only whitespace precedes the byte at pos
 // on its physical line.
 func commentStartsLine(sql string, pos int) bool {
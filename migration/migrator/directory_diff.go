@@ -0,0 +1,298 @@
+package migrator
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/sqlutil"
+	"github.com/stokaro/ptah/internal/parser"
+	"github.com/stokaro/ptah/migration/risk"
+	"github.com/stokaro/ptah/migration/safety"
+)
+
+// MigrationFileChange is one migration file that was added, removed, or
+// modified between two migration directories.
+type MigrationFileChange struct {
+	Version     int64  `json:"version"`
+	Name        string `json:"name"`
+	Direction   string `json:"direction"`
+	Path        string `json:"path"`
+	OldChecksum string `json:"old_checksum,omitempty"`
+	NewChecksum string `json:"new_checksum,omitempty"`
+}
+
+// MigrationOperation is one statement of an added up migration, classified
+// with the same rules the planner uses to flag destructive changes.
+type MigrationOperation struct {
+	Version int64 `json:"version"`
+	// Operation is the statement kind, such as "CREATE TABLE" or "DROP INDEX".
+	Operation string          `json:"operation"`
+	Table     string          `json:"table,omitempty"`
+	Severity  safety.Severity `json:"severity"`
+	Reason    string          `json:"reason"`
+}
+
+// DirectoryDiff summarizes what changed between two migration directories,
+// typically the same directory at two release refs.
+type DirectoryDiff struct {
+	Added    []MigrationFileChange `json:"added"`
+	Removed  []MigrationFileChange `json:"removed"`
+	Modified []MigrationFileChange `json:"modified"`
+	// Operations lists the statements of the added up migrations in
+	// version order.
+	Operations []MigrationOperation `json:"operations"`
+	// Tables lists every table the added up migrations touch, sorted.
+	Tables  []string        `json:"tables"`
+	Highest safety.Severity `json:"highest"`
+	// Errors describes released migrations whose content changed. Databases
+	// that already applied them record the old checksum, so Verify and
+	// checksum validation fail after the upgrade.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// HasErrors reports whether a released migration was modified.
+func (d *DirectoryDiff) HasErrors() bool {
+	return len(d.Errors) > 0
+}
+
+// DiffDirectories compares two migration directories file by file. Files are
+// matched by version and direction, so a renamed description with the same
+// SQL is not a change. The added up migrations are parsed and each statement
+// is classified with the safety rules; statements the parser does not model
+// are classified from their SQL text. A file present in both directories with
+// different content is reported in Modified and in Errors. The returned error
+// is only for directories that cannot be read.
+func DiffDirectories(oldFS, newFS fs.FS) (*DirectoryDiff, error) {
+	oldFiles, err := directoryFileContents(oldFS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read old migrations directory: %w", err)
+	}
+	newFiles, err := directoryFileContents(newFS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read new migrations directory: %w", err)
+	}
+
+	diff := &DirectoryDiff{Highest: safety.Safe}
+	tables := make(map[string]struct{})
+	for _, key := range sortedDirectoryFileKeys(newFiles) {
+		current := newFiles[key]
+		previous, released := oldFiles[key]
+		switch {
+		case !released:
+			diff.Added = append(diff.Added, current.change("", current.checksum))
+			if current.file.Direction == "up" {
+				diff.addOperations(current, tables)
+			}
+		case previous.checksum != current.checksum:
+			diff.Modified = append(diff.Modified, current.change(previous.checksum, current.checksum))
+			diff.Errors = append(diff.Errors, fmt.Sprintf(
+				"migration %s was modified after release: checksum %s, now %s",
+				current.file.Path, previous.checksum, current.checksum,
+			))
+		}
+	}
+	for _, key := range sortedDirectoryFileKeys(oldFiles) {
+		if _, ok := newFiles[key]; !ok {
+			previous := oldFiles[key]
+			diff.Removed = append(diff.Removed, previous.change(previous.checksum, ""))
+		}
+	}
+	for table := range tables {
+		diff.Tables = append(diff.Tables, table)
+	}
+	slices.Sort(diff.Tables)
+	return diff, nil
+}
+
+// RenderMarkdown writes the diff as a markdown report for release notes and
+// pull request descriptions.
+func (d *DirectoryDiff) RenderMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# Migration directory diff\n\n")
+	if len(d.Added)+len(d.Removed)+len(d.Modified) == 0 {
+		b.WriteString("No migration files changed.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	fmt.Fprintf(&b, "Highest severity: **%s**\n", d.Highest)
+	if len(d.Errors) > 0 {
+		b.WriteString("\n## Errors\n\n")
+		for _, message := range d.Errors {
+			fmt.Fprintf(&b, "- %s\n", message)
+		}
+	}
+	writeMarkdownFileChanges(&b, "Added migrations", d.Added)
+	writeMarkdownFileChanges(&b, "Removed migrations", d.Removed)
+	writeMarkdownFileChanges(&b, "Modified migrations", d.Modified)
+	if len(d.Operations) > 0 {
+		b.WriteString("\n## Operations\n\n")
+		b.WriteString("| Version | Operation | Table | Severity | Reason |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, op := range d.Operations {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n",
+				op.Version, op.Operation, markdownCell(op.Table), op.Severity, markdownCell(op.Reason))
+		}
+	}
+	if len(d.Tables) > 0 {
+		b.WriteString("\n## Affected tables\n\n")
+		for _, table := range d.Tables {
+			fmt.Fprintf(&b, "- `%s`\n", table)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownFileChanges(b *strings.Builder, title string, changes []MigrationFileChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	for _, change := range changes {
+		fmt.Fprintf(b, "- `%s` (version %d, %s)\n", change.Path, change.Version, change.Direction)
+	}
+}
+
+func markdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", `\|`)
+}
+
+type directoryFileKey struct {
+	version   int64
+	direction string
+}
+
+type directoryFile struct {
+	file     MigrationFile
+	sql      string
+	checksum string
+}
+
+func (f directoryFile) change(oldChecksum, newChecksum string) MigrationFileChange {
+	return MigrationFileChange{
+		Version:     f.file.Version,
+		Name:        f.file.Name,
+		Direction:   f.file.Direction,
+		Path:        f.file.Path,
+		OldChecksum: oldChecksum,
+		NewChecksum: newChecksum,
+	}
+}
+
+func directoryFileContents(fsys fs.FS) (map[directoryFileKey]directoryFile, error) {
+	files, err := DiscoverMigrationFiles(fsys, MigrationDirFormatAuto)
+	if err != nil {
+		return nil, err
+	}
+	contents := make(map[directoryFileKey]directoryFile, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", file.Path, err)
+		}
+		sql := string(data)
+		contents[directoryFileKey{version: file.Version, direction: file.Direction}] = directoryFile{
+			file:     file,
+			sql:      sql,
+			checksum: migrationChecksum(sql),
+		}
+	}
+	return contents, nil
+}
+
+func sortedDirectoryFileKeys(files map[directoryFileKey]directoryFile) []directoryFileKey {
+	keys := make([]directoryFileKey, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b directoryFileKey) int {
+		return cmp.Or(cmp.Compare(a.version, b.version), strings.Compare(a.direction, b.direction))
+	})
+	return keys
+}
+
+func (d *DirectoryDiff) addOperations(file directoryFile, tables map[string]struct{}) {
+	for _, statement := range sqlutil.SplitSQLStatements(file.sql) {
+		for _, op := range classifyMigrationStatement(file.file.Version, statement) {
+			d.Operations = append(d.Operations, op)
+			if op.Table != "" {
+				tables[op.Table] = struct{}{}
+			}
+			if risk.Rank(op.Severity) > risk.Rank(d.Highest) {
+				d.Highest = op.Severity
+			}
+		}
+	}
+}
+
+// classifyMigrationStatement parses one statement and classifies each node
+// it yields. Statements the parser rejects, such as DML, fall back to the
+// text-based classification.
+func classifyMigrationStatement(version int64, statement string) []MigrationOperation {
+	operation := statementOperation(statement)
+	list, err := parser.NewParser(statement).Parse()
+	if err != nil || len(list.Statements) == 0 {
+		assessment := safety.AssessSQL(statement)
+		return []MigrationOperation{{
+			Version:   version,
+			Operation: operation,
+			Severity:  assessment.Severity,
+			Reason:    assessment.Reason,
+		}}
+	}
+	ops := make([]MigrationOperation, 0, len(list.Statements))
+	for _, assessment := range safety.Assess(list.Statements) {
+		ops = append(ops, MigrationOperation{
+			Version:   version,
+			Operation: operation,
+			Table:     nodeTable(list.Statements[assessment.Index-1]),
+			Severity:  assessment.Severity,
+			Reason:    assessment.Reason,
+		})
+	}
+	return ops
+}
+
+func nodeTable(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.CreateTableNode:
+		return n.Name
+	case *ast.AlterTableNode:
+		return n.Name
+	case *ast.DropTableNode:
+		return n.Name
+	case *ast.IndexNode:
+		return n.Table
+	case *ast.DropIndexNode:
+		return n.Table
+	default:
+		return ""
+	}
+}
+
+// statementOperation names a statement by its leading keywords: the verb,
+// and for DDL the kind of object, skipping modifiers such as OR REPLACE and
+// UNIQUE.
+func statementOperation(statement string) string {
+	words := strings.Fields(strings.ToUpper(sqlutil.StripComments(statement)))
+	if len(words) == 0 {
+		return ""
+	}
+	verb := strings.TrimSuffix(words[0], ";")
+	if verb != "CREATE" && verb != "ALTER" && verb != "DROP" {
+		return verb
+	}
+	for _, word := range words[1:] {
+		switch word {
+		case "OR", "REPLACE", "UNIQUE", "TEMP", "TEMPORARY":
+			continue
+		}
+		return verb + " " + strings.TrimSuffix(word, ";")
+	}
+	return verb
+}
@@ -0,0 +1,118 @@
+package migrator_test
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/migration/migrator"
+	"github.com/stokaro/ptah/migration/safety"
+)
+
+func releasedMigrationsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"0000000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"0000000001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"0000000002_create_notes.up.sql":   {Data: []byte("CREATE TABLE notes (id INTEGER PRIMARY KEY);")},
+		"0000000002_create_notes.down.sql": {Data: []byte("DROP TABLE notes;")},
+	}
+}
+
+func TestDiffDirectories_SummarizesAddedMigrations(t *testing.T) {
+	c := qt.New(t)
+
+	newFS := releasedMigrationsFS()
+	newFS["0000000003_orders.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER);
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+ALTER TABLE users DROP COLUMN legacy_code;
+UPDATE orders SET user_id = 0 WHERE user_id IS NULL;`)}
+	newFS["0000000003_orders.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE orders;")}
+
+	diff, err := migrator.DiffDirectories(releasedMigrationsFS(), newFS)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(diff.HasErrors(), qt.IsFalse)
+	c.Assert(diff.Removed, qt.HasLen, 0)
+	c.Assert(diff.Modified, qt.HasLen, 0)
+	c.Assert(diff.Added, qt.HasLen, 2)
+	c.Assert(diff.Added[1].Path, qt.Equals, "0000000003_orders.up.sql")
+	c.Assert(diff.Tables, qt.DeepEquals, []string{"orders", "users"})
+	c.Assert(diff.Highest, qt.Equals, safety.Destructive)
+	c.Assert(diff.Operations, qt.HasLen, 4)
+	c.Assert(diff.Operations[0].Operation, qt.Equals, "CREATE TABLE")
+	c.Assert(diff.Operations[1].Operation, qt.Equals, "CREATE INDEX")
+	c.Assert(diff.Operations[1].Table, qt.Equals, "orders")
+	c.Assert(diff.Operations[2].Operation, qt.Equals, "ALTER TABLE")
+	c.Assert(diff.Operations[2].Severity, qt.Equals, safety.Destructive)
+	c.Assert(diff.Operations[3].Operation, qt.Equals, "UPDATE")
+}
+
+func TestDiffDirectories_FlagsModifiedReleasedMigration(t *testing.T) {
+	c := qt.New(t)
+
+	newFS := releasedMigrationsFS()
+	newFS["0000000002_create_notes.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT);")}
+	delete(newFS, "0000000001_create_users.down.sql")
+
+	diff, err := migrator.DiffDirectories(releasedMigrationsFS(), newFS)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(diff.HasErrors(), qt.IsTrue)
+	c.Assert(diff.Modified, qt.HasLen, 1)
+	c.Assert(diff.Modified[0].Version, qt.Equals, int64(2))
+	c.Assert(diff.Modified[0].OldChecksum, qt.Not(qt.Equals), diff.Modified[0].NewChecksum)
+	c.Assert(diff.Errors[0], qt.Contains, "0000000002_create_notes.up.sql was modified after release")
+	c.Assert(diff.Removed, qt.HasLen, 1)
+	c.Assert(diff.Removed[0].Path, qt.Equals, "0000000001_create_users.down.sql")
+	c.Assert(diff.Added, qt.HasLen, 0)
+}
+
+func TestDiffDirectories_RenamedDescriptionIsNotAChange(t *testing.T) {
+	c := qt.New(t)
+
+	newFS := releasedMigrationsFS()
+	newFS["0000000002_add_notes.up.sql"] = newFS["0000000002_create_notes.up.sql"]
+	delete(newFS, "0000000002_create_notes.up.sql")
+
+	diff, err := migrator.DiffDirectories(releasedMigrationsFS(), newFS)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(diff.Added, qt.HasLen, 0)
+	c.Assert(diff.Removed, qt.HasLen, 0)
+	c.Assert(diff.Modified, qt.HasLen, 0)
+}
+
+func TestDirectoryDiff_RenderMarkdown(t *testing.T) {
+	c := qt.New(t)
+
+	newFS := releasedMigrationsFS()
+	newFS["0000000002_create_notes.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE notes (id BIGINT PRIMARY KEY);")}
+	newFS["0000000003_drop_notes.up.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE notes;")}
+	diff, err := migrator.DiffDirectories(releasedMigrationsFS(), newFS)
+	c.Assert(err, qt.IsNil)
+
+	var out bytes.Buffer
+	err = diff.RenderMarkdown(&out)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(out.String(), qt.Contains, "Highest severity: **destructive**")
+	c.Assert(out.String(), qt.Contains, "## Errors\n\n- migration 0000000002_create_notes.up.sql was modified after release")
+	c.Assert(out.String(), qt.Contains, "## Added migrations\n\n- `0000000003_drop_notes.up.sql` (version 3, up)")
+	c.Assert(out.String(), qt.Contains, "| 3 | DROP TABLE | notes | destructive | DROP TABLE removes the table and all rows |")
+	c.Assert(out.String(), qt.Contains, "## Affected tables\n\n- `notes`")
+}
+
+func TestDirectoryDiff_RenderMarkdownWithoutChanges(t *testing.T) {
+	c := qt.New(t)
+
+	diff, err := migrator.DiffDirectories(releasedMigrationsFS(), releasedMigrationsFS())
+	c.Assert(err, qt.IsNil)
+
+	var out bytes.Buffer
+	err = diff.RenderMarkdown(&out)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(out.String(), qt.Equals, "# Migration directory diff\n\nNo migration files changed.\n")
+}
@@ -73,6 +73,15 @@
 //		log.Fatal(err)
 //	}
 //
+// NewMigrator takes a *dbschema.DatabaseConnection rather than the narrower
+// dbschema.SchemaReader, dbschema.SchemaWriter, and dbschema.Querier
+// interfaces. The migrator holds advisory locks and session settings on a
+// dedicated connection from its pool, runs each migration on a copy of the
+// connection bound to the migration transaction (or to a dry-run executor),
+// and passes that copy to every MigrationFunc. None of that is part of the
+// interfaces. Unit tests can pass the connection embedded in a
+// dbschematest.FakeConnection.
+//
 // # Migration History Table
 //
 // The migrator automatically creates and manages a `schema_migrations` table:
@@ -104,6 +113,24 @@
 // WithMigrationLockTimeout to bound the wait for that lock. Callers can detect
 // acquisition timeouts with IsMigrationLockTimeout.
 //
+// # Configuration
+//
+// NewMigrator accepts functional options that are applied before it returns,
+// so they are in effect from the first call. Options set independent
+// settings and may be combined in any order; a repeated option overrides the
+// earlier one. Without options the migrator keeps its documented defaults:
+//
+//	m := migrator.NewMigrator(conn, provider,
+//		migrator.WithMigrationsTable("infra", "ptah_migrations"),
+//		migrator.WithMigrationLockName("billing_migrate"),
+//		migrator.WithLogger(logger),
+//		migrator.WithPreflight(checkMaintenanceWindow),
+//		migrator.WithTransactionMode(migrator.MigrationTxModeAll),
+//		migrator.WithDryRun(dryRun),
+//	)
+//
+// Each option has a With method on Migrator that returns a configured copy.
+//
 // # Migration Operations
 //
 // The migrator supports several migration operations:
@@ -122,7 +149,7 @@
 // The migrator uses a provider pattern to supply migrations:
 //
 //   - NewFSMigrator(conn, fsys): Creates a migrator that loads migrations from a filesystem
-//   - NewMigrator(conn, provider): Creates a migrator with a custom migration provider
+//   - NewMigrator(conn, provider, opts...): Creates a migrator with a custom migration provider
 //   - NewRegisteredMigrationProvider(): Creates an in-memory provider for programmatic migrations
 //   - NewFSMigrationProvider(fsys): Creates a filesystem-based migration provider
 //
@@ -0,0 +1,23 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRedactedStatementErrorHidesExpandedValues(t *testing.T) {
+	c := qt.New(t)
+	expanded := "INSERT INTO secrets (value) VALUES ('s3cret')"
+	stmt := "INSERT INTO secrets (value) VALUES (:'PTAH_TEST_SECRET')"
+	driverErr := errors.New("syntax error in " + expanded + ": " + context.Canceled.Error())
+	err := &redactedStatementError{err: errors.Join(driverErr, context.Canceled), expanded: expanded, stmt: stmt}
+
+	c.Assert(err.Error(), qt.Contains, stmt)
+	for cause := error(err); cause != nil; cause = errors.Unwrap(cause) {
+		c.Assert(cause.Error(), qt.Not(qt.Contains), "s3cret")
+	}
+	c.Assert(errors.Is(err, context.Canceled), qt.IsTrue)
+}
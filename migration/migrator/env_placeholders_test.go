@@ -0,0 +1,96 @@
+package migrator_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func TestMigrationFuncExpandsEnvPlaceholders(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	t.Setenv("PTAH_TEST_SECRET", "it's s3cret")
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(t.TempDir(), "app.db"))
+	c.Assert(err, qt.IsNil)
+	defer conn.Close()
+	fsys := fstest.MapFS{"001_secret.up.sql": {Data: []byte(
+		"CREATE TABLE secrets (value TEXT NOT NULL);\n" +
+			"INSERT INTO secrets (value) VALUES (:'PTAH_TEST_SECRET');\n",
+	)}}
+
+	err = migrator.MigrationFuncFromSQLFilename("001_secret.up.sql", fsys)(ctx, conn)
+
+	c.Assert(err, qt.IsNil)
+	var value string
+	c.Assert(conn.QueryRowContext(ctx, "SELECT value FROM secrets").Scan(&value), qt.IsNil)
+	c.Assert(value, qt.Equals, "it's s3cret")
+}
+
+func TestMigrationFuncEnvPlaceholderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr string
+	}{
+		{
+			name:    "unset variable",
+			sql:     "SELECT :'PTAH_TEST_UNSET_SECRET';\n",
+			wantErr: `(?s).*environment variable PTAH_TEST_UNSET_SECRET referenced by placeholder :'PTAH_TEST_UNSET_SECRET' is not set.*`,
+		},
+		{
+			name:    "failed statement keeps the placeholder",
+			sql:     "INSERT INTO missing_table (value) VALUES (:'PTAH_TEST_SECRET');\n",
+			wantErr: `(?s).*INSERT INTO missing_table \(value\) VALUES \(:'PTAH_TEST_SECRET'\).*`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+			t.Setenv("PTAH_TEST_SECRET", "s3cret")
+			conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(t.TempDir(), "app.db"))
+			c.Assert(err, qt.IsNil)
+			defer conn.Close()
+			fsys := fstest.MapFS{"001_secret.up.sql": {Data: []byte(tt.sql)}}
+
+			err = migrator.MigrationFuncFromSQLFilename("001_secret.up.sql", fsys)(ctx, conn)
+
+			c.Assert(err, qt.ErrorMatches, tt.wantErr)
+			c.Assert(err.Error(), qt.Not(qt.Contains), "s3cret")
+		})
+	}
+}
+
+func TestRetryExpandsEnvPlaceholders(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	t.Setenv("PTAH_TEST_SECRET", "s3cret")
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(t.TempDir(), "app.db"))
+	c.Assert(err, qt.IsNil)
+	defer conn.Close()
+	fsys := fstest.MapFS{
+		"0000000001_secret.up.sql": {Data: []byte("-- +ptah no_transaction\n" +
+			"CREATE TABLE secrets (value TEXT NOT NULL);\n" +
+			"INSERT INTO secrets_log (value) VALUES (:'PTAH_TEST_SECRET');\n")},
+		"0000000001_secret.down.sql": {Data: []byte("DROP TABLE secrets;")},
+	}
+	m, err := migrator.NewFSMigrator(conn, fsys)
+	c.Assert(err, qt.IsNil)
+	m = m.WithRecoveryOperations(true)
+	c.Assert(m.MigrateUp(ctx), qt.IsNotNil)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE secrets_log (value TEXT NOT NULL)")
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(m.Retry(ctx, 1, []int{1}), qt.IsNil)
+
+	var value string
+	c.Assert(conn.QueryRowContext(ctx, "SELECT value FROM secrets_log").Scan(&value), qt.IsNil)
+	c.Assert(value, qt.Equals, "s3cret")
+}
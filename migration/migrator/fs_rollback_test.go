@@ -0,0 +1,106 @@
+package migrator_test
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+// rollbackFixture mirrors a directory of generated migration files. Every
+// down file appends its version to rollback_log so tests can observe which
+// down bodies ran and in what order.
+func rollbackFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"0000000001_create_log.up.sql":     {Data: []byte("CREATE TABLE rollback_log (version INTEGER NOT NULL);")},
+		"0000000001_create_log.down.sql":   {Data: []byte("DROP TABLE rollback_log;")},
+		"0000000002_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"0000000002_create_users.down.sql": {Data: []byte("DROP TABLE users; INSERT INTO rollback_log (version) VALUES (2);")},
+		"0000000003_create_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id INTEGER PRIMARY KEY);")},
+		"0000000003_create_posts.down.sql": {Data: []byte("DROP TABLE posts; INSERT INTO rollback_log (version) VALUES (3);")},
+	}
+}
+
+func openRollbackTestDB(c *qt.C) *dbschema.DatabaseConnection {
+	dbURL := (&url.URL{
+		Scheme: platform.SQLite,
+		Path:   filepath.Join(c.TempDir(), "ptah-rollback.sqlite"),
+	}).String()
+	conn, err := dbschema.ConnectToDatabase(context.Background(), dbURL)
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { c.Check(conn.Close(), qt.IsNil) })
+	return conn
+}
+
+func rolledBackVersions(c *qt.C, conn *dbschema.DatabaseConnection) []int64 {
+	rows, err := conn.QueryContext(context.Background(), "SELECT version FROM rollback_log ORDER BY rowid")
+	c.Assert(err, qt.IsNil)
+	defer rows.Close()
+	versions := []int64{}
+	for rows.Next() {
+		var version int64
+		c.Assert(rows.Scan(&version), qt.IsNil)
+		versions = append(versions, version)
+	}
+	c.Assert(rows.Err(), qt.IsNil)
+	return versions
+}
+
+func TestFSMigratorMigrateDownToRunsDownFilesInReverseOrder(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := openRollbackTestDB(c)
+
+	m, err := migrator.NewFSMigrator(conn, rollbackFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	c.Assert(m.MigrateDownTo(ctx, 1), qt.IsNil)
+
+	c.Assert(rolledBackVersions(c, conn), qt.DeepEquals, []int64{3, 2})
+	version, err := m.GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(1))
+}
+
+func TestFSMigratorMigrateDownToSkipsUnappliedFiles(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := openRollbackTestDB(c)
+
+	m, err := migrator.NewFSMigrator(conn, rollbackFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUpWithOptions(ctx, migrator.MigrateUpOptions{TargetVersion: 2}), qt.IsNil)
+
+	c.Assert(m.MigrateDownTo(ctx, 1), qt.IsNil)
+
+	c.Assert(rolledBackVersions(c, conn), qt.DeepEquals, []int64{2})
+}
+
+func TestFSMigratorMigrateDownToRejectsAppliedVersionWithoutDownFile(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := openRollbackTestDB(c)
+
+	m, err := migrator.NewFSMigrator(conn, rollbackFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	truncated := rollbackFixture()
+	delete(truncated, "0000000003_create_posts.up.sql")
+	delete(truncated, "0000000003_create_posts.down.sql")
+	m, err = migrator.NewFSMigrator(conn, truncated)
+	c.Assert(err, qt.IsNil)
+
+	err = m.MigrateDownTo(ctx, 1)
+
+	c.Assert(err, qt.ErrorMatches, `applied migration 3 is above target version 1 but is missing from the migration provider`)
+	c.Assert(rolledBackVersions(c, conn), qt.HasLen, 0)
+}
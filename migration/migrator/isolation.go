@@ -0,0 +1,60 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/types"
+)
+
+// WithIsolationLevel returns a copy of the migrator that begins every
+// migration transaction at level unless the migration sets its own
+// IsolationLevel. sql.LevelDefault, the zero value, keeps the database
+// default.
+//
+// The level is passed to database/sql BeginTx, so the driver decides which
+// levels it accepts. It is skipped on SQLite, whose transactions are always
+// serializable, and on ClickHouse, which runs migrations without
+// transactions. MySQL and MariaDB commit implicitly before most DDL
+// statements, so there the level only covers the data statements up to the
+// first DDL statement. Non-transactional migrations run on the connection pool
+// without the level.
+func (m *Migrator) WithIsolationLevel(level sql.IsolationLevel) *Migrator {
+	tmp := *m
+	tmp.isolationLevel = level
+	return &tmp
+}
+
+// migrationIsolationLevel returns the isolation level migration's transaction
+// begins with: its own level, or the migrator default.
+func (m *Migrator) migrationIsolationLevel(migration *Migration) sql.IsolationLevel {
+	if migration != nil && migration.IsolationLevel != sql.LevelDefault {
+		return migration.IsolationLevel
+	}
+	return m.isolationLevel
+}
+
+// beginMigrationTransaction begins a migration transaction at level. Dialects
+// that cannot set an isolation level begin a default transaction instead, and
+// a dry-run migrator begins none.
+func (m *Migrator) beginMigrationTransaction(ctx context.Context, level sql.IsolationLevel) (types.SchemaTransaction, error) {
+	if m.dryRun {
+		return dryRunExecutor{}, nil
+	}
+	writer := m.conn.SchemaWriter()
+	if level == sql.LevelDefault {
+		return writer.BeginTransaction(ctx)
+	}
+	dialect := platform.NormalizeDialect(m.conn.Info().Dialect)
+	if dialect == platform.SQLite || dialect == platform.ClickHouse {
+		m.logger.Debug("Transaction isolation level is not applied on this dialect", "dialect", dialect, "isolation", level.String())
+		return writer.BeginTransaction(ctx)
+	}
+	beginner, ok := writer.(types.SchemaTransactionBeginner)
+	if !ok {
+		return nil, fmt.Errorf("%s schema writer cannot begin a transaction at isolation level %s", dialect, level)
+	}
+	return beginner.BeginTransactionWithOptions(ctx, &sql.TxOptions{Isolation: level})
+}
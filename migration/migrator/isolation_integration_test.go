@@ -0,0 +1,95 @@
+package migrator_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func TestMigrateUp_PostgresIsolationLevelIntegration(t *testing.T) {
+	dbURL := postgresTestURL(t)
+	c := qt.New(t)
+	ctx := context.Background()
+
+	conn, err := dbschema.ConnectToDatabase(ctx, dbURL)
+	c.Assert(err, qt.IsNil)
+	defer func() { _ = conn.Close() }()
+
+	_, _ = conn.ExecContext(ctx, "DROP TABLE IF EXISTS schema_migrations_isolation")
+	_, _ = conn.ExecContext(ctx, "DROP TABLE IF EXISTS ptah_isolation_levels")
+	defer func() {
+		_, _ = conn.ExecContext(ctx, "DROP TABLE IF EXISTS schema_migrations_isolation")
+		_, _ = conn.ExecContext(ctx, "DROP TABLE IF EXISTS ptah_isolation_levels")
+	}()
+
+	recordLevel := "INSERT INTO ptah_isolation_levels (version, level) VALUES (%d, current_setting('transaction_isolation'));"
+	first := migrator.CreateMigrationFromSQL(1, "create_isolation_levels",
+		"CREATE TABLE ptah_isolation_levels (version INTEGER PRIMARY KEY, level TEXT NOT NULL);\n"+fmt.Sprintf(recordLevel, 1),
+		"DROP TABLE ptah_isolation_levels;")
+	second := migrator.CreateMigrationFromSQL(2, "record_serializable",
+		fmt.Sprintf(recordLevel, 2),
+		"DELETE FROM ptah_isolation_levels WHERE version = 2;")
+	second.IsolationLevel = sql.LevelSerializable
+
+	m := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(first, second)).
+		WithMigrationsTable("", "schema_migrations_isolation").
+		WithIsolationLevel(sql.LevelRepeatableRead)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	levels := map[int]string{}
+	rows, err := conn.QueryContext(ctx, "SELECT version, level FROM ptah_isolation_levels")
+	c.Assert(err, qt.IsNil)
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var version int
+		var level string
+		c.Assert(rows.Scan(&version, &level), qt.IsNil)
+		levels[version] = level
+	}
+	c.Assert(rows.Err(), qt.IsNil)
+	c.Assert(levels, qt.DeepEquals, map[int]string{1: "repeatable read", 2: "serializable"})
+}
+
+func TestMigrateUp_SQLiteIgnoresIsolationLevel(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(t.TempDir(), "isolation.db"))
+	c.Assert(err, qt.IsNil)
+	defer func() { _ = conn.Close() }()
+
+	migration := migrator.CreateMigrationFromSQL(1, "create_users",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY);", "DROP TABLE users;")
+	migration.IsolationLevel = sql.LevelReadCommitted
+	m := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(migration)).
+		WithIsolationLevel(sql.LevelRepeatableRead)
+
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+	version, err := m.GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(1))
+	c.Assert(m.MigrateDown(ctx), qt.IsNil)
+}
+
+func TestMigrateUp_TxModeAllRejectsMigrationIsolationLevel(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn, err := dbschema.ConnectToDatabase(ctx, "sqlite://"+filepath.Join(t.TempDir(), "isolation.db"))
+	c.Assert(err, qt.IsNil)
+	defer func() { _ = conn.Close() }()
+
+	migration := migrator.CreateMigrationFromSQL(1, "create_users",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY);", "DROP TABLE users;")
+	migration.IsolationLevel = sql.LevelSerializable
+	m := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(migration)).
+		WithTransactionMode(migrator.MigrationTxModeAll)
+
+	err = m.MigrateUp(ctx)
+	c.Assert(err, qt.ErrorMatches, `.*migration 1 sets isolation level Serializable and cannot run with tx-mode all.*`)
+}
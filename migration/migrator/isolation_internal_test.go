@@ -0,0 +1,33 @@
+package migrator
+
+// White-box testing required: the migration transaction only exposes the
+// resolved isolation level to the database driver.
+
+import (
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestMigrationIsolationLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		migrator  sql.IsolationLevel
+		migration sql.IsolationLevel
+		want      sql.IsolationLevel
+	}{
+		{name: "database default", want: sql.LevelDefault},
+		{name: "migrator default", migrator: sql.LevelRepeatableRead, want: sql.LevelRepeatableRead},
+		{name: "migration level", migration: sql.LevelSerializable, want: sql.LevelSerializable},
+		{name: "migration level overrides the migrator default", migrator: sql.LevelReadCommitted, migration: sql.LevelSerializable, want: sql.LevelSerializable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			m := (&Migrator{}).WithIsolationLevel(tt.migrator)
+			c.Assert(m.migrationIsolationLevel(&Migration{IsolationLevel: tt.migration}), qt.Equals, tt.want)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package migrator_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func TestParseFileMetadata(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want map[string]string
+	}{
+		{
+			name: "generated header",
+			sql: "-- Migration generated from schema differences\n-- Direction: UP\n" +
+				"-- ptah:version=v1.2.3\n-- ptah:dialect=postgres\n\nCREATE TABLE users (id INTEGER);\n",
+			want: map[string]string{"version": "v1.2.3", "dialect": "postgres"},
+		},
+		{
+			name: "unknown keys are kept and later lines win",
+			sql:  "-- ptah:future=1\n  -- ptah:dialect=mysql\n-- ptah:dialect=mariadb\n",
+			want: map[string]string{"future": "1", "dialect": "mariadb"},
+		},
+		{
+			name: "lines without a value and directives are not metadata",
+			sql:  "-- ptah:nolint destructive\n-- +ptah no_transaction\n-- ptah: =x\n",
+			want: map[string]string{},
+		},
+		{
+			name: "metadata-looking text in literals and trailing comments is ignored",
+			sql:  "INSERT INTO notes (body) VALUES ('\n-- ptah:dialect=mysql\n'); -- ptah:dialect=sqlite\n",
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			c.Assert(migrator.ParseFileMetadata(tt.sql), qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestMigratorWarnsOnDialectHeaderMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		warnings int
+	}{
+		{name: "other dialect warns on apply and rollback", dialect: "postgres", warnings: 2},
+		{name: "connection dialect", dialect: "sqlite", warnings: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+			conn := openRollbackTestDB(c)
+			header := "-- ptah:dialect=" + tt.dialect + "\n\n"
+			m, err := migrator.NewFSMigrator(conn, fstest.MapFS{
+				"0000000001_create_users.up.sql":   {Data: []byte(header + "CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+				"0000000001_create_users.down.sql": {Data: []byte(header + "DROP TABLE users;")},
+			})
+			c.Assert(err, qt.IsNil)
+			var logs bytes.Buffer
+			m = m.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+			c.Assert(m.MigrateUp(ctx), qt.IsNil)
+			c.Assert(m.MigrateDown(ctx), qt.IsNil)
+
+			c.Assert(bytes.Count(logs.Bytes(), []byte("file_dialect="+tt.dialect+" connection_dialect=sqlite")), qt.Equals, tt.warnings)
+		})
+	}
+}
@@ -2,8 +2,11 @@ package migrator
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io/fs"
+	"os"
 	"strconv"
 	"strings"
 
@@ -30,6 +33,21 @@ func splitSQLStatementsForConnection(conn *dbschema.DatabaseConnection, sql stri
 	return splitSQLStatementsForDialect(sql, conn.Info().Dialect)
 }
 
+// migrationStatements returns the statements a migration body executes on
+// conn. Execution and the PlanUp/PlanDownTo previews both split through it,
+// so a preview lists exactly the statements that run.
+func migrationStatements(conn *dbschema.DatabaseConnection, sql string) []string {
+	statements := splitSQLStatementsForConnection(conn, sql)
+	filtered := statements[:0]
+	for _, stmt := range statements {
+		stmt = strings.TrimSpace(stmt)
+		if stmt != "" {
+			filtered = append(filtered, stmt)
+		}
+	}
+	return filtered
+}
+
 func splitSQLStatementsForDialect(sql, dialect string) []string {
 	if strings.TrimSpace(dialect) == "" {
 		return SplitSQLStatements(sql)
@@ -376,6 +394,10 @@ type Migration struct {
 	// per-migration transaction. Execution uses the direction-specific fields.
 	NoTransaction                bool
 	directionalNoTransactionMode bool
+	// IsolationLevel is the isolation level of the migration's transaction in
+	// both directions. sql.LevelDefault uses the migrator default set with
+	// WithIsolationLevel. It has no effect on non-transactional migrations.
+	IsolationLevel sql.IsolationLevel
 }
 
 func (m *Migration) upExecutionMode() migrationExecutionMode {
@@ -430,15 +452,19 @@ func CreateMigrationFromSQL(version int64, description, upSQL, downSQL string) *
 
 // executeSQLStatements splits SQL into individual statements and executes them
 func executeSQLStatements(ctx context.Context, conn *dbschema.DatabaseConnection, sql string, mode migrationExecutionMode) error {
-	statements := splitSQLStatementsForConnection(conn, sql)
+	statements := migrationStatements(conn, sql)
 
-	for i, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue // Skip empty statements and comments
+	if batchSize := statementBatchSize(ctx, conn, mode); batchSize > 1 {
+		for start := 0; start < len(statements); start += batchSize {
+			if err := executeStatementBatch(ctx, conn, statements, start, batchSize, "failed to execute SQL statement"); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
 
-		if err := executeMigrationStatement(ctx, conn, stmt, mode); err != nil {
+	for i, stmt := range statements {
+		if err := executeMigrationStatementWithEnv(ctx, conn, stmt, mode); err != nil {
 			return &MigrationExecutionError{
 				Err:            fmt.Errorf("failed to execute SQL statement: %w", err),
 				Statement:      stmt,
@@ -470,13 +496,19 @@ func executeMigrationFileSQL(
 		}
 	}
 
-	statements := splitSQLStatementsForConnection(conn, sql)
-	for i, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
+	statements := migrationStatements(conn, sql)
+	// An interceptor decides per statement where it runs, so intercepted
+	// migrations are never batched.
+	if batchSize := statementBatchSize(ctx, conn, mode); batchSize > 1 && interceptor == nil {
+		for start := 0; start < len(statements); start += batchSize {
+			if err := executeStatementBatch(ctx, conn, statements, start, batchSize, "failed to execute migration SQL"); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
 
+	for i, stmt := range statements {
 		if interceptor != nil {
 			handled, err := interceptor.ExecuteStatement(ctx, conn, stmt, directives)
 			if err != nil {
@@ -492,7 +524,7 @@ func executeMigrationFileSQL(
 			}
 		}
 
-		if err := executeMigrationStatement(ctx, conn, stmt, mode); err != nil {
+		if err := executeMigrationStatementWithEnv(ctx, conn, stmt, mode); err != nil {
 			return &MigrationExecutionError{
 				Err:            fmt.Errorf("failed to execute migration SQL: %w", err),
 				Statement:      stmt,
@@ -521,6 +553,42 @@ func (e *MigrationExecutionError) Unwrap() error {
 	return e.Err
 }
 
+// executeMigrationStatementWithEnv expands the environment placeholders in
+// stmt (see sqlutil.EnvPlaceholder) and executes it. The expanded statement
+// exists only in memory: a dry run prints stmt as written, and an execution
+// error has the values replaced by their placeholders again.
+func executeMigrationStatementWithEnv(ctx context.Context, conn *dbschema.DatabaseConnection, stmt string, mode migrationExecutionMode) error {
+	if conn.Writer().IsDryRun() || !sqlutil.HasEnvPlaceholders(stmt) {
+		return executeMigrationStatement(ctx, conn, stmt, mode)
+	}
+	expanded, err := sqlutil.ExpandEnvPlaceholders(stmt, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	if err := executeMigrationStatement(ctx, conn, expanded, mode); err != nil {
+		return &redactedStatementError{err: err, expanded: expanded, stmt: stmt}
+	}
+	return nil
+}
+
+// redactedStatementError reports an error from an expanded statement with
+// the statement text put back in its unexpanded form. The driver error is
+// not exposed through Unwrap, since its message carries the expanded values;
+// errors.Is still matches it through Is.
+type redactedStatementError struct {
+	err      error
+	expanded string
+	stmt     string
+}
+
+func (e *redactedStatementError) Error() string {
+	return strings.ReplaceAll(e.err.Error(), e.expanded, e.stmt)
+}
+
+func (e *redactedStatementError) Is(target error) bool {
+	return errors.Is(e.err, target)
+}
+
 func executeMigrationStatement(ctx context.Context, conn *dbschema.DatabaseConnection, stmt string, mode migrationExecutionMode) error {
 	if mode == migrationExecutionTransactional {
 		return conn.Writer().ExecuteSQL(ctx, stmt)
@@ -540,7 +608,7 @@ func executeSQLOutsideTransaction(ctx context.Context, conn *dbschema.DatabaseCo
 	return err
 }
 
-func executeSQLOn(ctx context.Context, conn *dbschema.DatabaseConnection, sql string, args ...any) error {
+func executeSQLOn(ctx context.Context, conn dbschema.SchemaWriter, sql string, args ...any) error {
 	return conn.Writer().ExecuteSQL(ctx, sql, args...)
 }
 
@@ -77,6 +77,8 @@ type Migrator struct {
 	conn                 *dbschema.DatabaseConnection
 	migrationProvider    MigrationProvider
 	defaultTimeouts      MigrationTimeouts
+	sessionSettings      map[string]string
+	isolationLevel       sql.IsolationLevel
 	migrationsTable      string
 	migrationsSchema     string
 	revisionTableFormat  RevisionTableFormat
@@ -88,6 +90,12 @@ type Migrator struct {
 	logger               *slog.Logger
 	observer             Observer
 	skipChecks           bool
+	allowRecovery        bool
+	retry                RetryOptions
+	execution            ExecutionOptions
+	preflight            PreMigrationHook
+	dryRun               bool
+	tenant               *tenantRun
 }
 
 // NewFSMigrator creates a new migrator that loads migrations from a filesystem.
@@ -103,9 +111,12 @@ func NewFSMigrator(conn *dbschema.DatabaseConnection, fsys fs.FS, opts ...FSProv
 	return NewMigrator(conn, provider), nil
 }
 
-// NewMigrator creates a new migrator with the given database connection
-func NewMigrator(conn *dbschema.DatabaseConnection, provider MigrationProvider) *Migrator {
-	return &Migrator{
+// NewMigrator creates a new migrator with the given database connection,
+// configured by opts. Options are applied in order before NewMigrator
+// returns; without options the migrator uses the defaults documented on each
+// option.
+func NewMigrator(conn *dbschema.DatabaseConnection, provider MigrationProvider, opts ...Option) *Migrator {
+	m := &Migrator{
 		conn:                conn,
 		migrationProvider:   provider,
 		migrationsTable:     defaultPtahMigrationsTable,
@@ -116,6 +127,12 @@ func NewMigrator(conn *dbschema.DatabaseConnection, provider MigrationProvider)
 		logger:              slog.Default(),
 		observer:            NoopObserver{},
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
 }
 
 // WithLogger sets the logger for the migrator
@@ -323,6 +340,22 @@ BEGIN
         checksum NVARCHAR(64) NOT NULL DEFAULT ''
     )
 END`, sqlStringLiteral(m.sqlServerObjectName()), m.qualifiedMigrationsTable())
+	}
+	if m.sharedTenantRevisions() {
+		return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    %s TEXT NOT NULL,
+    version BIGINT NOT NULL,
+    description TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL,
+    state VARCHAR(32) NOT NULL DEFAULT 'applied',
+    applied INTEGER NOT NULL DEFAULT 1,
+    total INTEGER NOT NULL DEFAULT 1,
+    error TEXT NULL,
+    error_stmt TEXT NULL,
+    execution_time_ms BIGINT NOT NULL DEFAULT 0,
+    checksum VARCHAR(64) NOT NULL DEFAULT '',
+    PRIMARY KEY (%s, version)
+)`, m.qualifiedMigrationsTable(), tenantSchemaColumn, tenantSchemaColumn)
 	}
 	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
     version BIGINT PRIMARY KEY,
@@ -346,7 +379,7 @@ func (m *Migrator) getVersionSQL() string {
 			m.qualifiedMigrationsTable(),
 		)
 	}
-	return fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s WHERE state = 'applied'", m.qualifiedMigrationsTable())
+	return fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s WHERE state = 'applied'%s", m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) getAppliedMigrationsSQL() string {
@@ -357,11 +390,11 @@ func (m *Migrator) getAppliedMigrationsSQL() string {
 			m.atlasVersionNumberExpression(),
 		)
 	}
-	return fmt.Sprintf("SELECT version FROM %s WHERE state = 'applied' ORDER BY version", m.qualifiedMigrationsTable())
+	return fmt.Sprintf("SELECT version FROM %s WHERE state = 'applied'%s ORDER BY version", m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) deleteMigrationSQL() string {
-	return fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.qualifiedMigrationsTable())
+	return fmt.Sprintf("DELETE FROM %s WHERE version = ?%s", m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 // Initialize creates the migrations table if it doesn't exist
@@ -397,6 +430,9 @@ func (m *Migrator) Initialize(ctx context.Context) error {
 		if err := m.ensureMigrationsRevisionColumns(ctx); err != nil {
 			return fmt.Errorf("failed to prepare migrations revision columns: %w", err)
 		}
+		if err := m.ensureTenantRevisionTable(ctx); err != nil {
+			return err
+		}
 	}
 
 	// Mark as initialized
@@ -901,7 +937,10 @@ func (m *Migrator) migrateUpLocked(ctx context.Context, opts MigrateUpOptions) e
 	if err := m.validateUpTransactionMode(migrationsToApply); err != nil {
 		return err
 	}
-	if err := runPreMigrationHook(ctx, opts.Preflight, MigrationPlan{
+	if err := m.validateTenantMigrations(migrationsToApply); err != nil {
+		return err
+	}
+	if err := m.runPreMigrationHooks(ctx, opts.Preflight, MigrationPlan{
 		Direction:      MigrationDirectionUp,
 		CurrentVersion: currentVersion,
 		TargetVersion:  upTargetVersion(currentVersion, migrationsToApply),
@@ -1040,7 +1079,7 @@ func (m *Migrator) migrateDownToLocked(ctx context.Context, targetVersion int64,
 	if err != nil {
 		return err
 	}
-	if err := runPreMigrationHook(ctx, hook, MigrationPlan{
+	if err := m.runPreMigrationHooks(ctx, hook, MigrationPlan{
 		Direction:      MigrationDirectionDown,
 		CurrentVersion: currentVersion,
 		TargetVersion:  downTargetVersion(appliedMigrations, targetVersion),
@@ -1140,6 +1179,9 @@ func (m *Migrator) migrateUpTo(ctx context.Context, targetVersion int64) error {
 	if err := m.validateUpTransactionMode(migrationsToApply); err != nil {
 		return err
 	}
+	if err := m.validateTenantMigrations(migrationsToApply); err != nil {
+		return err
+	}
 	if span := rootSpanFromContext(ctx); span != nil {
 		span.SetAttributes(
 			attr("migration.current_version", currentVersion),
@@ -1157,12 +1199,23 @@ func (m *Migrator) migrateUpTo(ctx context.Context, targetVersion int64) error {
 	return nil
 }
 
-func runPreMigrationHook(ctx context.Context, hook PreMigrationHook, plan MigrationPlan) error {
-	if hook == nil || len(plan.Versions) == 0 {
+// runPreMigrationHooks runs the migrator's preflight hook, then the per-call
+// hook, for a plan that changes anything.
+func (m *Migrator) runPreMigrationHooks(ctx context.Context, hook PreMigrationHook, plan MigrationPlan) error {
+	if len(plan.Versions) == 0 {
 		return nil
 	}
-	plan.Versions = slices.Clone(plan.Versions)
-	return hook(ctx, plan)
+	for _, h := range []PreMigrationHook{m.preflight, hook} {
+		if h == nil {
+			continue
+		}
+		p := plan
+		p.Versions = slices.Clone(plan.Versions)
+		if err := h(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func migrationVersions(migrations []*Migration) []int64 {
@@ -1239,7 +1292,7 @@ func (m *Migrator) applyUpMigrationsInSingleTransaction(ctx context.Context, mig
 		return err
 	}
 
-	tx, err := m.conn.SchemaWriter().BeginTransaction(ctx)
+	tx, err := m.beginMigrationTransaction(ctx, m.isolationLevel)
 	if err != nil {
 		return fmt.Errorf("failed to begin tx-mode all transaction: %w", err)
 	}
@@ -1279,6 +1332,9 @@ func (m *Migrator) validateUpTransactionMode(migrations []*Migration) error {
 			if err := m.rejectChecksUnderTxModeAll(migration); err != nil {
 				return err
 			}
+			if m.migrationIsolationLevel(migration) != m.isolationLevel {
+				return fmt.Errorf("migration %d sets isolation level %s and cannot run with tx-mode all; use WithIsolationLevel for the shared transaction", migration.Version, migration.IsolationLevel)
+			}
 		}
 	case MigrationTxModeNone:
 		for _, migration := range migrations {
@@ -1317,6 +1373,7 @@ func (m *Migrator) applyUpMigrationObserved(ctx context.Context, migration *Migr
 	}()
 
 	m.logger.Info("Applying migration", "version", migration.Version, "description", migration.Description)
+	m.warnOnDialectMismatch(migration, migration.UpSQL)
 	if err := m.beginMigrationRevision(ctx, migration); err != nil {
 		return fmt.Errorf("failed to record pending migration %d: %w", migration.Version, err)
 	}
@@ -1326,6 +1383,27 @@ func (m *Migrator) applyUpMigrationObserved(ctx context.Context, migration *Migr
 	return m.applyUpMigrationTransactional(ctx, migration, startedAt)
 }
 
+// warnOnDialectMismatch logs a warning when sql, one of migration's bodies,
+// declares a ptah:dialect header other than the connection's dialect. The
+// migration still runs: the header records what the file was generated for,
+// and hand-edited or portable SQL may well apply cleanly elsewhere.
+func (m *Migrator) warnOnDialectMismatch(migration *Migration, sql string) {
+	declared := ParseFileMetadata(sql)[MetadataDialect]
+	if declared == "" {
+		return
+	}
+	connected := m.conn.Info().Dialect
+	if platform.NormalizeDialect(declared) == platform.NormalizeDialect(connected) {
+		return
+	}
+	m.logger.Warn("Migration file was generated for a different dialect than the connection",
+		"version", migration.Version,
+		"description", migration.Description,
+		"file_dialect", declared,
+		"connection_dialect", connected,
+	)
+}
+
 func (m *Migrator) applyUpMigrationForcedNoTransactionObserved(ctx context.Context, migration *Migration) (err error) {
 	observer := m.migrationObserver()
 	ctx, span := observer.StartSpan(ctx, "ptah.migrate.apply", m.migrationAttributes(MigrationDirectionUp, migration)...)
@@ -1346,6 +1424,7 @@ func (m *Migrator) applyUpMigrationForcedNoTransactionObserved(ctx context.Conte
 }
 
 func (m *Migrator) applyUpMigrationForcedNoTransactionAt(ctx context.Context, migration *Migration, startedAt time.Time) error {
+	m.warnOnDialectMismatch(migration, migration.UpSQL)
 	if err := m.beginMigrationRevision(ctx, migration); err != nil {
 		return fmt.Errorf("failed to record pending migration %d: %w", migration.Version, err)
 	}
@@ -1359,6 +1438,7 @@ func (m *Migrator) applyUpMigrationInExistingTransaction(
 	startedAt time.Time,
 ) error {
 	m.logger.Info("Applying migration in tx-mode all", "version", migration.Version, "description", migration.Description)
+	m.warnOnDialectMismatch(migration, migration.UpSQL)
 	// Pre-migration checks are rejected under tx-mode all by
 	// validateUpTransactionMode, because a check on the pool connection cannot
 	// observe earlier batched migrations' uncommitted changes and would evaluate
@@ -1367,7 +1447,7 @@ func (m *Migrator) applyUpMigrationInExistingTransaction(
 	if err != nil {
 		return fmt.Errorf("failed to apply timeouts for migration %d: %w", migration.Version, err)
 	}
-	if err := migration.Up(ctx, txConn); err != nil {
+	if err := migration.Up(m.transactionalContext(ctx), txConn); err != nil {
 		err = m.restoreTimeoutsAfterFailure(ctx, migration.Version, restoreTimeouts, err)
 		return fmt.Errorf("failed to apply migration %d: %w", migration.Version, err)
 	}
@@ -1431,72 +1511,61 @@ func (m *Migrator) applyUpMigrationTransactional(ctx context.Context, migration
 		)
 	}
 
-	tx, err := m.conn.SchemaWriter().BeginTransaction(ctx)
+	for attempt := 0; ; attempt++ {
+		prefix, err := m.runUpMigrationTransaction(ctx, migration)
+		if err == nil {
+			break
+		}
+		if !m.shouldRetryMigration(ctx, migration, attempt, err) {
+			return m.failMigrationWithDirtyState(ctx, migration, startedAt, err, migration.UpSQL, prefix)
+		}
+	}
+	if err := m.completeMigrationRevision(ctx, migration, startedAt); err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+
+	m.logger.Info("Applied migration", "version", migration.Version, "description", migration.Description)
+	return nil
+}
+
+// runUpMigrationTransaction runs one attempt of migration's up body in its own
+// transaction. On failure the transaction is rolled back and the returned
+// prefix describes the failed step for the dirty-state error.
+func (m *Migrator) runUpMigrationTransaction(ctx context.Context, migration *Migration) (string, error) {
+	tx, err := m.beginMigrationTransaction(ctx, m.migrationIsolationLevel(migration))
 	if err != nil {
-		return m.failMigrationWithDirtyState(
-			ctx,
-			migration,
-			startedAt,
-			err,
-			migration.UpSQL,
-			fmt.Sprintf("failed to begin transaction for migration %d", migration.Version),
-		)
+		return fmt.Sprintf("failed to begin transaction for migration %d", migration.Version), err
 	}
 	txConn := m.conn.WithExecutor(tx)
 
 	restoreTimeouts, err := m.applyTimeoutsWithRestore(ctx, txConn, mergeMigrationTimeouts(m.defaultTimeouts, migration.UpTimeouts))
 	if err != nil {
 		_ = tx.Rollback()
-		return m.failMigrationWithDirtyState(
-			ctx,
-			migration,
-			startedAt,
-			err,
-			migration.UpSQL,
-			fmt.Sprintf("failed to apply timeouts for migration %d", migration.Version),
-		)
+		return fmt.Sprintf("failed to apply timeouts for migration %d", migration.Version), err
 	}
 
-	if err := migration.Up(ctx, txConn); err != nil {
+	if err := migration.Up(m.transactionalContext(ctx), txConn); err != nil {
 		err = m.restoreTimeoutsAfterFailure(ctx, migration.Version, restoreTimeouts, err)
 		_ = tx.Rollback()
-		return m.failMigrationWithDirtyState(
-			ctx,
-			migration,
-			startedAt,
-			err,
-			migration.UpSQL,
-			fmt.Sprintf("failed to apply migration %d", migration.Version),
-		)
+		return fmt.Sprintf("failed to apply migration %d", migration.Version), err
 	}
 
 	if err := m.restoreTimeouts(ctx, migration.Version, restoreTimeouts); err != nil {
 		_ = tx.Rollback()
-		return m.failMigrationWithDirtyState(ctx, migration, startedAt, err, migration.UpSQL, "")
+		return "", err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return m.failMigrationWithDirtyState(
-			ctx,
-			migration,
-			startedAt,
-			err,
-			migration.UpSQL,
-			fmt.Sprintf("failed to commit transaction for migration %d", migration.Version),
-		)
+		return fmt.Sprintf("failed to commit transaction for migration %d", migration.Version), err
 	}
-	if err := m.completeMigrationRevision(ctx, migration, startedAt); err != nil {
-		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
-	}
-
-	m.logger.Info("Applied migration", "version", migration.Version, "description", migration.Description)
-	return nil
+	return "", nil
 }
 
 func (m *Migrator) applyUpMigrationNoTransaction(ctx context.Context, migration *Migration, startedAt time.Time) error {
 	if err := ensureNoTransactionHasNoTimeouts(migration.Version, mergeMigrationTimeouts(m.defaultTimeouts, migration.UpTimeouts)); err != nil {
 		return m.failMigrationWithDirtyStateWithMode(ctx, migration, startedAt, err, migration.UpSQL, "", MigrationTxModeNone)
 	}
+	m.warnSessionSettingsSkipped(migration.Version)
 	if err := m.runMigrationChecks(ctx, m.conn, migration); err != nil {
 		return m.failMigrationWithDirtyStateWithMode(
 			ctx,
@@ -1547,6 +1616,7 @@ func (m *Migrator) rollbackMigrationObserved(ctx context.Context, migration *Mig
 	}()
 
 	m.logger.Info("Rolling back migration", "version", migration.Version, "description", migration.Description)
+	m.warnOnDialectMismatch(migration, migration.DownSQL)
 	if err := m.beginRollbackRevision(ctx, migration); err != nil {
 		return fmt.Errorf("failed to record pending rollback %d: %w", migration.Version, err)
 	}
@@ -1615,7 +1685,7 @@ func (m *Migrator) rollbackMigrationTransactional(
 	startedAt time.Time,
 	deleteSQL string,
 ) error {
-	tx, err := m.conn.SchemaWriter().BeginTransaction(ctx)
+	tx, err := m.beginMigrationTransaction(ctx, m.migrationIsolationLevel(migration))
 	if err != nil {
 		return m.failMigrationWithDirtyState(
 			ctx,
@@ -1641,7 +1711,7 @@ func (m *Migrator) rollbackMigrationTransactional(
 		)
 	}
 
-	if err := migration.Down(ctx, txConn); err != nil {
+	if err := migration.Down(m.transactionalContext(ctx), txConn); err != nil {
 		err = m.restoreTimeoutsAfterFailure(ctx, migration.Version, restoreTimeouts, err)
 		_ = tx.Rollback()
 		return m.failMigrationWithDirtyState(
@@ -1688,6 +1758,7 @@ func (m *Migrator) rollbackMigrationNoTransaction(
 	if err := ensureNoTransactionHasNoTimeouts(migration.Version, mergeMigrationTimeouts(m.defaultTimeouts, migration.DownTimeouts)); err != nil {
 		return m.failMigrationWithDirtyStateWithMode(ctx, migration, startedAt, err, migration.DownSQL, "", MigrationTxModeNone)
 	}
+	m.warnSessionSettingsSkipped(migration.Version)
 	if err := migration.Down(ctx, m.conn); err != nil {
 		return m.failMigrationWithDirtyStateWithMode(
 			ctx,
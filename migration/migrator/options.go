@@ -0,0 +1,164 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// Option configures a Migrator created by NewMigrator. Options are applied in
+// order before NewMigrator returns, so they are in effect from the first call
+// on the migrator. Each option sets an independent setting; when the same
+// option is given twice, the last one wins.
+//
+// Every option has a With method counterpart on Migrator that returns a
+// configured copy, for callers that derive migrators from a shared base.
+type Option func(*Migrator)
+
+// WithMigrationsTable records applied migrations in schema.table instead of
+// the default schema_migrations table. An empty schema uses the connection's
+// default schema; an empty table keeps the default table name.
+func WithMigrationsTable(schema, table string) Option {
+	return func(m *Migrator) { *m = *m.WithMigrationsTable(schema, table) }
+}
+
+// WithRevisionTableFormat selects the revision metadata layout. The default is
+// RevisionTableFormatPtah. A migrations table set with WithMigrationsTable is
+// kept whichever order the two options are given in.
+func WithRevisionTableFormat(format RevisionTableFormat) Option {
+	return func(m *Migrator) { *m = *m.WithRevisionTableFormat(format) }
+}
+
+// WithMigrationLockName sets the advisory lock name that serializes migration
+// runs. The default is "ptah_migrate".
+func WithMigrationLockName(name string) Option {
+	return func(m *Migrator) { *m = *m.WithMigrationLockName(name) }
+}
+
+// WithMigrationLockTimeout bounds how long a migration run waits for the
+// advisory lock. Zero, the default, waits indefinitely.
+func WithMigrationLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) { *m = *m.WithMigrationLockTimeout(timeout) }
+}
+
+// WithLogger sets the migrator's logger. The default, and a nil logger, is
+// slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(m *Migrator) { *m = *m.WithLogger(l) }
+}
+
+// WithObserver sets the observer that receives migration spans, counters, and
+// durations. The default, and a nil observer, is NoopObserver.
+func WithObserver(observer Observer) Option {
+	return func(m *Migrator) { *m = *m.WithObserver(observer) }
+}
+
+// WithPreflight sets a hook that runs inside the migration lock before every
+// up and down run that changes anything, ahead of any per-call hook such as
+// MigrateUpOptions.Preflight. An error from the hook aborts the run before any
+// migration is applied. The default is no hook.
+func WithPreflight(hook PreMigrationHook) Option {
+	return func(m *Migrator) { *m = *m.WithPreflight(hook) }
+}
+
+// WithTransactionMode sets how pending up migrations are wrapped in
+// transactions. The default is MigrationTxModeFile.
+func WithTransactionMode(mode MigrationTxMode) Option {
+	return func(m *Migrator) { *m = *m.WithTransactionMode(mode) }
+}
+
+// WithIsolationLevel sets the isolation level of migration transactions. The
+// default is the driver's default level.
+func WithIsolationLevel(level sql.IsolationLevel) Option {
+	return func(m *Migrator) { *m = *m.WithIsolationLevel(level) }
+}
+
+// WithDryRun makes every run log its statements instead of executing them.
+// The default is false.
+func WithDryRun(dryRun bool) Option {
+	return func(m *Migrator) { *m = *m.WithDryRun(dryRun) }
+}
+
+// WithExecOrder sets how pending migrations below the current high-water mark
+// are handled. The default is ExecOrderLinear.
+func WithExecOrder(execOrder ExecOrder) Option {
+	return func(m *Migrator) { *m = *m.WithExecOrder(execOrder) }
+}
+
+// WithSkipChecks controls whether pre-migration `-- +ptah check` assertions
+// are skipped. The default, false, enforces them.
+func WithSkipChecks(skip bool) Option {
+	return func(m *Migrator) { *m = *m.WithSkipChecks(skip) }
+}
+
+// WithRecoveryOperations allows the recovery operations Force, ForceDown, and
+// Retry. The default, false, refuses them.
+func WithRecoveryOperations(allow bool) Option {
+	return func(m *Migrator) { *m = *m.WithRecoveryOperations(allow) }
+}
+
+// WithRetry sets the retry policy for transient migration failures. The
+// default is no retries.
+func WithRetry(opts RetryOptions) Option {
+	return func(m *Migrator) { *m = *m.WithRetry(opts) }
+}
+
+// WithSessionSettings sets session parameters applied before each migration.
+// The default is none.
+func WithSessionSettings(settings map[string]string) Option {
+	return func(m *Migrator) { *m = *m.WithSessionSettings(settings) }
+}
+
+// WithDefaultTimeouts sets the timeouts for migrations that declare none. The
+// default is no timeouts.
+func WithDefaultTimeouts(timeouts MigrationTimeouts) Option {
+	return func(m *Migrator) { *m = *m.WithDefaultTimeouts(timeouts) }
+}
+
+// WithExecutionOptions configures statement batching. The default disables
+// batching.
+func WithExecutionOptions(opts ExecutionOptions) Option {
+	return func(m *Migrator) { *m = *m.WithExecutionOptions(opts) }
+}
+
+// WithPreflight returns a copy of the migrator that runs hook before every up
+// and down run. See the WithPreflight option.
+func (m *Migrator) WithPreflight(hook PreMigrationHook) *Migrator {
+	tmp := *m
+	tmp.preflight = hook
+	return &tmp
+}
+
+// WithDryRun returns a copy of the migrator that logs its statements instead
+// of executing them. The copy runs against a dry-run view of the connection,
+// so the connection's writer and other users of the connection are not
+// affected.
+func (m *Migrator) WithDryRun(dryRun bool) *Migrator {
+	tmp := *m
+	if tmp.conn != nil && dryRun != tmp.dryRun {
+		if dryRun {
+			tmp.conn = tmp.conn.WithExecutor(dryRunExecutor{})
+		} else {
+			tmp.conn = tmp.conn.WithExecutor(nil)
+		}
+	}
+	tmp.dryRun = dryRun
+	return &tmp
+}
+
+// dryRunExecutor logs statements instead of executing them. A migrator
+// configured with WithDryRun uses it as the active executor of its own
+// connection copy and in place of its migration transactions.
+type dryRunExecutor struct{}
+
+func (dryRunExecutor) ExecuteSQL(_ context.Context, sqlExpr string, args ...any) error {
+	slog.Info("[DRY RUN] Would execute SQL", "sql", sqlExpr, "args", args)
+	return nil
+}
+
+func (dryRunExecutor) IsDryRun() bool { return true }
+
+func (dryRunExecutor) Commit() error { return nil }
+
+func (dryRunExecutor) Rollback() error { return nil }
@@ -0,0 +1,287 @@
+package migrator_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+// optionsProvider returns two migrations that create options_t1 and
+// options_t2. When failSecond is set, migration 2 fails after creating its
+// table.
+func optionsProvider(failSecond bool) *migrator.RegisteredMigrationProvider {
+	second := "CREATE TABLE options_t2 (id INTEGER PRIMARY KEY);"
+	if failSecond {
+		second += "\nINSERT INTO options_missing (id) VALUES (1);"
+	}
+	return migrator.NewRegisteredMigrationProvider(
+		migrator.CreateMigrationFromSQL(1, "first", "CREATE TABLE options_t1 (id INTEGER PRIMARY KEY);", "DROP TABLE options_t1;"),
+		migrator.CreateMigrationFromSQL(2, "second", second, "DROP TABLE options_t2;"),
+	)
+}
+
+func sqliteTables(tb testing.TB, conn *dbschema.DatabaseConnection) []string {
+	tb.Helper()
+	rows, err := conn.Query("SELECT name FROM sqlite_master WHERE type='table' ORDER BY name")
+	qt.Assert(tb, err, qt.IsNil)
+	defer rows.Close()
+	var names []string
+	for rows.Next() {
+		var name string
+		qt.Assert(tb, rows.Scan(&name), qt.IsNil)
+		names = append(names, name)
+	}
+	qt.Assert(tb, rows.Err(), qt.IsNil)
+	return names
+}
+
+// lockNameObserver records the lock.name attribute of lock acquisition spans.
+type lockNameObserver struct {
+	migrator.NoopObserver
+	mu    sync.Mutex
+	names []any
+}
+
+func (o *lockNameObserver) StartSpan(ctx context.Context, name string, attrs ...migrator.ObservationAttribute) (context.Context, migrator.ObservationSpan) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, a := range attrs {
+		if name == "ptah.lock.acquire" && a.Key == "lock.name" {
+			o.names = append(o.names, a.Value)
+		}
+	}
+	return o.NoopObserver.StartSpan(ctx, name, attrs...)
+}
+
+func TestNewMigrator_ZeroOptionsUsesDefaults(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(false))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"options_t1", "options_t2", "schema_migrations"})
+
+	c.Assert(m.MigrateDownTo(context.Background(), 1), qt.IsNil)
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"options_t1", "schema_migrations"})
+}
+
+func TestNewMigrator_NilOptionIsIgnored(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(false), nil)
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"options_t1", "options_t2", "schema_migrations"})
+}
+
+func TestNewMigrator_WithMigrationsTable(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithMigrationsTable("", "app_history"))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"app_history", "options_t1", "options_t2"})
+
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.IsNil)
+	version, err := m.GetCurrentVersion(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(0))
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"app_history"})
+}
+
+func TestNewMigrator_WithPreflightRunsOnUpAndDown(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	var plans []migrator.MigrationPlan
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithPreflight(func(_ context.Context, plan migrator.MigrationPlan) error {
+		plans = append(plans, plan)
+		return nil
+	}))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.IsNil)
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.IsNil)
+
+	c.Assert(plans, qt.DeepEquals, []migrator.MigrationPlan{
+		{Direction: migrator.MigrationDirectionUp, CurrentVersion: 0, TargetVersion: 2, Versions: []int64{1, 2}},
+		{Direction: migrator.MigrationDirectionDown, CurrentVersion: 2, TargetVersion: 0, Versions: []int64{2, 1}},
+	})
+}
+
+func TestNewMigrator_WithPreflightRunsBeforePerCallHook(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	var calls []string
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithPreflight(func(context.Context, migrator.MigrationPlan) error {
+		calls = append(calls, "option")
+		return nil
+	}))
+
+	err := m.MigrateUpWithPreflight(context.Background(), func(context.Context, migrator.MigrationPlan) error {
+		calls = append(calls, "call")
+		return nil
+	})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(calls, qt.DeepEquals, []string{"option", "call"})
+}
+
+func TestNewMigrator_WithPreflightErrorAbortsMigrateUp(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	hookErr := errors.New("preflight refused")
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithPreflight(func(context.Context, migrator.MigrationPlan) error {
+		return hookErr
+	}))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.ErrorIs, hookErr)
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"schema_migrations"})
+}
+
+func TestNewMigrator_WithPreflightErrorAbortsMigrateDownTo(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	c.Assert(migrator.NewMigrator(conn, optionsProvider(false)).MigrateUp(context.Background()), qt.IsNil)
+	hookErr := errors.New("preflight refused")
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithPreflight(func(context.Context, migrator.MigrationPlan) error {
+		return hookErr
+	}))
+
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.ErrorIs, hookErr)
+	version, err := m.GetCurrentVersion(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(2))
+}
+
+func TestNewMigrator_WithDryRunMigrateUpChangesNothing(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithDryRun(true))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+
+	c.Assert(sqliteTables(t, conn), qt.HasLen, 0)
+	c.Assert(conn.Writer().IsDryRun(), qt.IsFalse)
+}
+
+func TestNewMigrator_WithDryRunMigrateDownToChangesNothing(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	c.Assert(migrator.NewMigrator(conn, optionsProvider(false)).MigrateUp(context.Background()), qt.IsNil)
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithDryRun(true))
+
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.IsNil)
+
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"options_t1", "options_t2", "schema_migrations"})
+	c.Assert(conn.Writer().IsDryRun(), qt.IsFalse)
+	version, err := migrator.NewMigrator(conn, optionsProvider(false)).GetCurrentVersion(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(2))
+}
+
+func TestNewMigrator_WithDryRunLeavesConnectionUsable(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	// The preflight hook runs mid-run, so the real migrator shares the
+	// connection with a dry run in progress.
+	m := migrator.NewMigrator(conn, optionsProvider(false),
+		migrator.WithDryRun(true),
+		migrator.WithPreflight(func(ctx context.Context, _ migrator.MigrationPlan) error {
+			return migrator.NewMigrator(conn, optionsProvider(false)).MigrateUp(ctx)
+		}),
+	)
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"options_t1", "options_t2", "schema_migrations"})
+}
+
+func TestNewMigrator_WithTransactionModeAllRollsBackMigrateUp(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(true), migrator.WithTransactionMode(migrator.MigrationTxModeAll))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNotNil)
+
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"schema_migrations"})
+}
+
+func TestNewMigrator_DefaultTransactionModeKeepsEarlierMigrations(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(true))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNotNil)
+
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"options_t1", "schema_migrations"})
+}
+
+func TestNewMigrator_WithLogger(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	var buf bytes.Buffer
+	m := migrator.NewMigrator(conn, optionsProvider(false), migrator.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.IsNil)
+
+	c.Assert(buf.String(), qt.Contains, "All migrations applied successfully")
+	c.Assert(buf.String(), qt.Contains, "All migrations rolled back successfully")
+}
+
+func TestNewMigrator_WithObserverAndLockName(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	observer := &lockNameObserver{}
+	m := migrator.NewMigrator(conn, optionsProvider(false),
+		migrator.WithObserver(observer),
+		migrator.WithMigrationLockName("app_migrations"),
+	)
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	c.Assert(m.MigrateDownTo(context.Background(), 0), qt.IsNil)
+
+	c.Assert(observer.names, qt.DeepEquals, []any{"app_migrations", "app_migrations"})
+}
+
+func TestNewMigrator_OptionsCombineInAnyOrder(t *testing.T) {
+	c := qt.New(t)
+	opts := []migrator.Option{
+		migrator.WithMigrationsTable("", "app_history"),
+		migrator.WithRevisionTableFormat(migrator.RevisionTableFormatPtah),
+		migrator.WithTransactionMode(migrator.MigrationTxModeAll),
+		migrator.WithMigrationLockName("app_migrations"),
+	}
+	reversed := []migrator.Option{opts[3], opts[2], opts[1], opts[0]}
+	forwardConn := openSQLiteBatchDB(t)
+	reverseConn := openSQLiteBatchDB(t)
+
+	c.Assert(migrator.NewMigrator(forwardConn, optionsProvider(true), opts...).MigrateUp(context.Background()), qt.IsNotNil)
+	c.Assert(migrator.NewMigrator(reverseConn, optionsProvider(true), reversed...).MigrateUp(context.Background()), qt.IsNotNil)
+
+	c.Assert(sqliteTables(t, forwardConn), qt.DeepEquals, []string{"app_history"})
+	c.Assert(sqliteTables(t, reverseConn), qt.DeepEquals, []string{"app_history"})
+}
+
+func TestNewMigrator_LaterOptionWins(t *testing.T) {
+	c := qt.New(t)
+	conn := openSQLiteBatchDB(t)
+	m := migrator.NewMigrator(conn, optionsProvider(false),
+		migrator.WithDryRun(true),
+		migrator.WithMigrationsTable("", "first_history"),
+		migrator.WithDryRun(false),
+		migrator.WithMigrationsTable("", "app_history"),
+	)
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+
+	c.Assert(sqliteTables(t, conn), qt.DeepEquals, []string{"app_history", "options_t1", "options_t2"})
+}
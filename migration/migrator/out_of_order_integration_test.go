@@ -68,7 +68,7 @@ func TestOutOfOrderMigrationsPostgresIntegration(t *testing.T) {
 	c.Assert(finalStatus.PendingMigrations, qt.HasLen, 0)
 }
 
-func postgresTestURL(t *testing.T) string {
+func postgresTestURL(t testing.TB) string {
 	t.Helper()
 
 	dbURL := os.Getenv("POSTGRES_TEST_DSN")
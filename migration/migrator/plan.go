@@ -0,0 +1,81 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+)
+
+// PlannedExecution is one migration of a preview plan together with the SQL
+// statements running it would execute.
+type PlannedExecution struct {
+	Version     int64
+	Description string
+	Direction   MigrationDirection
+	// NoTransaction reports that the migration runs outside the normal
+	// per-migration transaction.
+	NoTransaction bool
+	// Statements are the individual statements in execution order, split the
+	// same way execution splits them. Migrations registered as Go functions
+	// without SQL have no statements.
+	Statements []string
+}
+
+// Pending returns the registered migrations that are not applied yet, in the
+// order MigrateUp applies them. Like MigrateUp, it fails with an
+// OutOfOrderError under the linear exec order when a pending migration is
+// older than the current version.
+func (m *Migrator) Pending(ctx context.Context) ([]*Migration, error) {
+	applied, err := m.GetAppliedMigrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	return m.migrationsToApply(m.migrationProvider.Migrations(), applied, 0)
+}
+
+// PlanUp returns, per pending migration, the statements MigrateUp would
+// execute. It does not change the schema or the revision table.
+func (m *Migrator) PlanUp(ctx context.Context) ([]PlannedExecution, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]PlannedExecution, 0, len(pending))
+	for _, migration := range pending {
+		plan = append(plan, m.plannedExecution(MigrationDirectionUp, migration, migration.UpSQL, migration.upExecutionMode()))
+	}
+	return plan, nil
+}
+
+// PlanDownTo returns, per migration to roll back, the statements
+// MigrateDownTo(ctx, targetVersion) would execute, newest migration first. It
+// does not change the schema or the revision table.
+func (m *Migrator) PlanDownTo(ctx context.Context, targetVersion int64) ([]PlannedExecution, error) {
+	applied, err := m.GetAppliedMigrations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	rollback, err := migrationsToRollback(migrationsByVersion(m.migrationProvider.Migrations()), applied, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]PlannedExecution, 0, len(rollback))
+	for _, migration := range rollback {
+		plan = append(plan, m.plannedExecution(MigrationDirectionDown, migration, migration.DownSQL, migration.downExecutionMode()))
+	}
+	return plan, nil
+}
+
+func (m *Migrator) plannedExecution(
+	direction MigrationDirection,
+	migration *Migration,
+	sql string,
+	mode migrationExecutionMode,
+) PlannedExecution {
+	return PlannedExecution{
+		Version:       migration.Version,
+		Description:   migration.Description,
+		Direction:     direction,
+		NoTransaction: mode == migrationExecutionNoTransaction,
+		Statements:    migrationStatements(m.conn, sql),
+	}
+}
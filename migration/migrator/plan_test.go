@@ -0,0 +1,112 @@
+package migrator_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func planFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"0000000001_create_notes.up.sql": {Data: []byte(`-- notes hold free text
+CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL);
+/* a semicolon inside a literal; must not split */
+INSERT INTO notes (id, body) VALUES (1, 'first; second');
+`)},
+		"0000000001_create_notes.down.sql": {Data: []byte("DROP TABLE notes;")},
+		"0000000002_create_tags.up.sql":    {Data: []byte("CREATE TABLE tags (id INTEGER PRIMARY KEY); CREATE INDEX tags_id ON tags (id);")},
+		"0000000002_create_tags.down.sql":  {Data: []byte("DROP INDEX tags_id; DROP TABLE tags;")},
+	}
+}
+
+func TestMigratorPendingAndPlanUp(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, err := migrator.NewFSMigrator(openRollbackTestDB(c), planFixture())
+	c.Assert(err, qt.IsNil)
+
+	pending, err := m.Pending(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pending, qt.HasLen, 2)
+	c.Assert(pending[0].Version, qt.Equals, int64(1))
+	c.Assert(pending[1].Version, qt.Equals, int64(2))
+
+	plan, err := m.PlanUp(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(plan, qt.DeepEquals, []migrator.PlannedExecution{
+		{
+			Version:     1,
+			Description: "Create Notes",
+			Direction:   migrator.MigrationDirectionUp,
+			Statements: []string{
+				"CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL)",
+				"INSERT INTO notes (id, body) VALUES (1, 'first; second')",
+			},
+		},
+		{
+			Version:     2,
+			Description: "Create Tags",
+			Direction:   migrator.MigrationDirectionUp,
+			Statements:  []string{"CREATE TABLE tags (id INTEGER PRIMARY KEY)", "CREATE INDEX tags_id ON tags (id)"},
+		},
+	})
+
+	applied, err := m.GetAppliedMigrations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(applied, qt.HasLen, 0)
+}
+
+func TestMigratorPlanUpMatchesExecutedStatements(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	recorder := &recordingInterceptor{handledSetup: true}
+	m, err := migrator.NewFSMigrator(openRollbackTestDB(c), planFixture(), migrator.WithStatementInterceptor(recorder))
+	c.Assert(err, qt.IsNil)
+
+	plan, err := m.PlanUp(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	var planned []string
+	for _, execution := range plan {
+		planned = append(planned, execution.Statements...)
+	}
+	c.Assert(recorder.statements, qt.DeepEquals, planned)
+
+	pending, err := m.Pending(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pending, qt.HasLen, 0)
+}
+
+func TestMigratorPlanDownTo(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, err := migrator.NewFSMigrator(openRollbackTestDB(c), planFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	plan, err := m.PlanDownTo(ctx, 0)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(plan, qt.DeepEquals, []migrator.PlannedExecution{
+		{
+			Version:     2,
+			Description: "Create Tags",
+			Direction:   migrator.MigrationDirectionDown,
+			Statements:  []string{"DROP INDEX tags_id", "DROP TABLE tags"},
+		},
+		{
+			Version:     1,
+			Description: "Create Notes",
+			Direction:   migrator.MigrationDirectionDown,
+			Statements:  []string{"DROP TABLE notes"},
+		},
+	})
+	version, err := m.GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(2))
+}
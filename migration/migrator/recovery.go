@@ -0,0 +1,244 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/stokaro/ptah/core/sqlutil"
+	"github.com/stokaro/ptah/dbschema"
+)
+
+// ErrRecoveryDisabled is returned by Force, ForceDown and Retry when the migrator was not
+// created with WithRecoveryOperations(true).
+var ErrRecoveryDisabled = errors.New("migration recovery operations are disabled; enable them with WithRecoveryOperations(true)")
+
+// WithRecoveryOperations controls whether the manual recovery operations Force,
+// ForceDown and Retry may run. They rewrite migration history without the usual safety
+// net, so the default (false) rejects them with ErrRecoveryDisabled; pass true
+// only from an operator-driven recovery path.
+func (m *Migrator) WithRecoveryOperations(allow bool) *Migrator {
+	tmp := *m
+	tmp.allowRecovery = allow
+	return &tmp
+}
+
+// Force records migration version as applied WITHOUT executing any of its
+// statements, clearing a dirty revision row left by a failed run.
+//
+// This is dangerous: Ptah trusts the recorded history, so forcing a version
+// whose statements did not all take effect leaves the schema silently out of
+// sync with the migration files. Use it only after verifying (or finishing)
+// the migration by hand. Force refuses to run when revisions above version
+// are recorded, and requires WithRecoveryOperations(true).
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if !m.allowRecovery {
+		return ErrRecoveryDisabled
+	}
+	return m.withMigrationLock(ctx, "force", func(ctx context.Context) error {
+		return m.forceLocked(ctx, version)
+	})
+}
+
+func (m *Migrator) forceLocked(ctx context.Context, version int64) error {
+	migration, err := m.recoveryMigration(ctx, version)
+	if err != nil {
+		return err
+	}
+	if err := m.failIfRevisionAbove(ctx, version); err != nil {
+		return err
+	}
+	m.logger.Warn("FORCING migration version without executing it; the recorded history is no longer verified against the schema",
+		"version", version,
+		"description", migration.Description,
+	)
+	if err := m.forceAppliedMigration(ctx, migration); err != nil {
+		return fmt.Errorf("failed to force migration %d: %w", version, err)
+	}
+	return nil
+}
+
+// ForceDownOptions configures ForceDownWithOptions.
+type ForceDownOptions struct {
+	Version int64
+	// Force skips the check that Version is the highest applied revision.
+	Force bool
+}
+
+// ForceDown runs only the down statements of migration version and removes its
+// revision row, leaving every other recorded version alone. It is meant for
+// repairing a single bad migration without rolling back the ones around it.
+//
+// ForceDown refuses to run unless version is the highest applied revision, and
+// requires WithRecoveryOperations(true). Use ForceDownWithOptions to override
+// the version check.
+func (m *Migrator) ForceDown(ctx context.Context, version int64) error {
+	return m.ForceDownWithOptions(ctx, ForceDownOptions{Version: version})
+}
+
+// ForceDownWithOptions runs only the down statements of migration opts.Version
+// and removes its revision row.
+//
+// With opts.Force it also rolls back a version that later revisions were
+// recorded after. This is dangerous: the later migrations may depend on the
+// objects the down statements drop, and they stay recorded as applied, so the
+// schema can end up out of sync with the recorded history.
+func (m *Migrator) ForceDownWithOptions(ctx context.Context, opts ForceDownOptions) error {
+	if !m.allowRecovery {
+		return ErrRecoveryDisabled
+	}
+	return m.withMigrationLock(ctx, "force down", func(ctx context.Context) error {
+		return m.forceDownLocked(ctx, opts)
+	})
+}
+
+func (m *Migrator) forceDownLocked(ctx context.Context, opts ForceDownOptions) error {
+	migration, err := m.recoveryMigration(ctx, opts.Version)
+	if err != nil {
+		return err
+	}
+	revision, err := m.getRevision(ctx, opts.Version)
+	if err != nil {
+		return err
+	}
+	if revision == nil {
+		return fmt.Errorf("migration %d is not applied; nothing to roll back", opts.Version)
+	}
+	if !opts.Force {
+		if err := m.failIfRevisionAbove(ctx, opts.Version); err != nil {
+			return err
+		}
+	}
+	m.logger.Warn("FORCING rollback of a single migration version; later versions are not rolled back",
+		"version", opts.Version,
+		"description", migration.Description,
+		"force", opts.Force,
+	)
+	deleteSQL := sqlutil.Rebind(m.conn.Info().Dialect, m.deleteMigrationSQL())
+	if err := m.rollbackMigration(ctx, migration, deleteSQL); err != nil {
+		return fmt.Errorf("failed to force down migration %d: %w", opts.Version, err)
+	}
+	return nil
+}
+
+// Retry re-runs the up statements of a failed migration outside a transaction,
+// skipping the 1-based statement indices in skipStatements, and records the
+// migration as applied once every remaining statement succeeds. It is meant for
+// no_transaction migrations (for example CREATE INDEX CONCURRENTLY) that failed
+// halfway: skip the statements that already took effect and re-run the rest.
+//
+// Statements are numbered as MigrationExecutionError.StatementIndex numbers
+// them, so the index of the original failure can be passed on directly. Retry
+// refuses migrations that are already applied cleanly and Go migrations,
+// which have no statements to re-run, and requires
+// WithRecoveryOperations(true). A statement that fails again leaves the
+// revision row dirty with the new error.
+func (m *Migrator) Retry(ctx context.Context, version int64, skipStatements []int) error {
+	if !m.allowRecovery {
+		return ErrRecoveryDisabled
+	}
+	return m.withMigrationLock(ctx, "retry", func(ctx context.Context) error {
+		return m.retryLocked(ctx, version, skipStatements)
+	})
+}
+
+func (m *Migrator) retryLocked(ctx context.Context, version int64, skipStatements []int) error {
+	migration, err := m.recoveryMigration(ctx, version)
+	if err != nil {
+		return err
+	}
+	revision, err := m.getRevision(ctx, version)
+	if err != nil {
+		return err
+	}
+	if revision != nil && !revision.Dirty {
+		return fmt.Errorf("migration %d is already applied; nothing to retry", version)
+	}
+
+	statements, err := recoveryStatements(m.conn, migration)
+	if err != nil {
+		return err
+	}
+	for _, index := range skipStatements {
+		if index < 1 || index > len(statements) {
+			return fmt.Errorf("skip statement index %d is out of range for migration %d (1-%d)", index, version, len(statements))
+		}
+	}
+	m.logger.Warn("RETRYING failed migration outside a transaction",
+		"version", version,
+		"description", migration.Description,
+		"skip_statements", skipStatements,
+	)
+
+	startedAt := time.Now()
+	skip := func(index int) bool {
+		if slices.Contains(skipStatements, index) {
+			m.logger.Warn("SKIPPING migration statement during retry", "version", version, "statement", index)
+			return true
+		}
+		return false
+	}
+	if execErr := m.rerunMigrationStatements(ctx, statements, skip); execErr != nil {
+		if failErr := m.failMigrationRevisionWithMode(ctx, migration, startedAt, execErr, migration.UpSQL, MigrationTxModeNone); failErr != nil {
+			m.logger.Warn("failed to record retry failure", "version", version, "error", failErr)
+		}
+		return fmt.Errorf("failed to retry migration %d at statement %d: %w", version, execErr.StatementIndex, execErr)
+	}
+	return m.forceAppliedMigration(ctx, migration)
+}
+
+// recoveryStatements returns the up statements Retry and RepairMigration
+// re-run, numbered exactly as MigrationExecutionError.StatementIndex numbers
+// them during a normal run. A Go migration has no statements to re-run, so
+// marking it applied afterwards would record work that never happened.
+func recoveryStatements(conn *dbschema.DatabaseConnection, migration *Migration) ([]string, error) {
+	if strings.TrimSpace(migration.UpSQL) == "" {
+		return nil, fmt.Errorf("migration %d has no SQL body; Go migrations cannot be re-run statement by statement", migration.Version)
+	}
+	return migrationStatements(conn, migration.UpSQL), nil
+}
+
+// rerunMigrationStatements executes statements outside a transaction,
+// skipping the 1-based indices for which skip reports true. Environment
+// placeholders are expanded as in a normal run.
+func (m *Migrator) rerunMigrationStatements(ctx context.Context, statements []string, skip func(index int) bool) *MigrationExecutionError {
+	for i, stmt := range statements {
+		index := i + 1
+		if skip(index) {
+			continue
+		}
+		if err := executeMigrationStatementWithEnv(ctx, m.conn, stmt, migrationExecutionNoTransaction); err != nil {
+			return &MigrationExecutionError{Err: err, Statement: stmt, StatementIndex: index, Total: len(statements)}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) recoveryMigration(ctx context.Context, version int64) (*Migration, error) {
+	if version <= 0 {
+		return nil, fmt.Errorf("migration version must be greater than zero")
+	}
+	if err := m.Initialize(ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize migrations table: %w", err)
+	}
+	migration := m.migrationByVersion(version)
+	if migration == nil {
+		return nil, fmt.Errorf("migration %d not found", version)
+	}
+	return migration, nil
+}
+
+func (m *Migrator) failIfRevisionAbove(ctx context.Context, version int64) error {
+	query := sqlutil.Rebind(m.conn.Info().Dialect, m.countRevisionsAboveSQL())
+	var count int
+	if err := m.conn.QueryRowContext(ctx, query, version).Scan(&count); err != nil {
+		return fmt.Errorf("failed to inspect migration revisions above version %d: %w", version, err)
+	}
+	if count > 0 {
+		return fmt.Errorf("schema migrations table contains revisions above version %d; refusing to rewrite migration history", version)
+	}
+	return nil
+}
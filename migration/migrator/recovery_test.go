@@ -0,0 +1,207 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+// recoveryFixture models a no_transaction migration whose first statement
+// commits before the second one fails because the audit table is missing.
+func recoveryFixture() fstest.MapFS {
+	return fstest.MapFS{
+		"0000000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"0000000001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"0000000002_add_index.up.sql": {Data: []byte("-- +ptah no_transaction\n" +
+			"CREATE INDEX idx_users_id ON users (id);\n" +
+			"INSERT INTO audit (note) VALUES ('indexed');")},
+		"0000000002_add_index.down.sql": {Data: []byte("DROP INDEX idx_users_id;")},
+	}
+}
+
+func failedRecoveryMigrator(c *qt.C) (*migrator.Migrator, *dbschema.DatabaseConnection) {
+	conn := openRollbackTestDB(c)
+	m, err := migrator.NewFSMigrator(conn, recoveryFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNotNil)
+	return m, conn
+}
+
+func TestMigratorRecoveryOperationsRequireOptIn(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, _ := failedRecoveryMigrator(c)
+
+	c.Assert(m.Force(ctx, 2), qt.ErrorIs, migrator.ErrRecoveryDisabled)
+	c.Assert(m.Retry(ctx, 2, []int{1}), qt.ErrorIs, migrator.ErrRecoveryDisabled)
+	version, err := m.WithRecoveryOperations(true).GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(1))
+}
+
+func TestMigratorForceRecordsVersionWithoutExecuting(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, conn := failedRecoveryMigrator(c)
+	m = m.WithRecoveryOperations(true)
+
+	c.Assert(m.Force(ctx, 2), qt.IsNil)
+
+	version, err := m.GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(2))
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+	var tables int
+	c.Assert(conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'audit'").Scan(&tables), qt.IsNil)
+	c.Assert(tables, qt.Equals, 0)
+}
+
+func TestMigratorForceRejectsRevisionsAboveVersion(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := openRollbackTestDB(c)
+	m, err := migrator.NewFSMigrator(conn, rollbackFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	err = m.WithRecoveryOperations(true).Force(ctx, 2)
+
+	c.Assert(err, qt.ErrorMatches, `schema migrations table contains revisions above version 2; refusing to rewrite migration history`)
+}
+
+func TestMigratorRetrySkipsStatementsThatAlreadyRan(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, conn := failedRecoveryMigrator(c)
+	m = m.WithRecoveryOperations(true)
+	_, err := conn.ExecContext(ctx, "CREATE TABLE audit (note TEXT)")
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(m.Retry(ctx, 2, []int{1}), qt.IsNil)
+
+	version, err := m.GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(2))
+	var notes int
+	c.Assert(conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit").Scan(&notes), qt.IsNil)
+	c.Assert(notes, qt.Equals, 1)
+}
+
+func TestMigratorRetryFailureKeepsMigrationDirty(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, _ := failedRecoveryMigrator(c)
+	m = m.WithRecoveryOperations(true)
+
+	err := m.Retry(ctx, 2, nil)
+
+	c.Assert(err, qt.ErrorMatches, `(?s)failed to retry migration 2 at statement 1: .*already exists.*`)
+	c.Assert(m.MigrateUp(ctx), qt.Satisfies, migrator.IsDirtyMigration)
+}
+
+func TestMigratorRetryRejectsInvalidRequests(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, _ := failedRecoveryMigrator(c)
+	m = m.WithRecoveryOperations(true)
+
+	c.Assert(m.Retry(ctx, 2, []int{3}), qt.ErrorMatches, `skip statement index 3 is out of range for migration 2 \(1-2\)`)
+	c.Assert(m.Retry(ctx, 1, nil), qt.ErrorMatches, `migration 1 is already applied; nothing to retry`)
+	c.Assert(m.Retry(ctx, 9, nil), qt.ErrorMatches, `migration 9 not found`)
+}
+
+func TestMigratorRetryRejectsGoMigrations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := openRollbackTestDB(c)
+	failing := &migrator.Migration{
+		Version:     1,
+		Description: "go_migration",
+		Up: func(context.Context, *dbschema.DatabaseConnection) error {
+			return errors.New("boom")
+		},
+		Down: func(context.Context, *dbschema.DatabaseConnection) error { return nil },
+	}
+	m := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(failing)).WithRecoveryOperations(true)
+	c.Assert(m.MigrateUp(ctx), qt.IsNotNil)
+
+	err := m.Retry(ctx, 1, nil)
+
+	c.Assert(err, qt.ErrorMatches, `migration 1 has no SQL body; Go migrations cannot be re-run statement by statement`)
+	c.Assert(m.MigrateUp(ctx), qt.Satisfies, migrator.IsDirtyMigration)
+}
+
+func appliedRollbackMigrator(c *qt.C) (*migrator.Migrator, *dbschema.DatabaseConnection) {
+	conn := openRollbackTestDB(c)
+	m, err := migrator.NewFSMigrator(conn, rollbackFixture())
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNil)
+	return m, conn
+}
+
+func TestMigratorForceDownRequiresOptIn(t *testing.T) {
+	c := qt.New(t)
+	m, conn := appliedRollbackMigrator(c)
+
+	c.Assert(m.ForceDown(context.Background(), 3), qt.ErrorIs, migrator.ErrRecoveryDisabled)
+	c.Assert(rolledBackVersions(c, conn), qt.DeepEquals, []int64{})
+}
+
+func TestMigratorForceDownRollsBackHighestVersion(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, conn := appliedRollbackMigrator(c)
+	m = m.WithRecoveryOperations(true)
+
+	c.Assert(m.ForceDown(ctx, 3), qt.IsNil)
+
+	c.Assert(rolledBackVersions(c, conn), qt.DeepEquals, []int64{3})
+	applied, err := m.GetAppliedMigrations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(applied, qt.DeepEquals, []int64{1, 2})
+}
+
+func TestMigratorForceDownRejectsVersionBelowHighest(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, conn := appliedRollbackMigrator(c)
+
+	err := m.WithRecoveryOperations(true).ForceDown(ctx, 2)
+
+	c.Assert(err, qt.ErrorMatches, `schema migrations table contains revisions above version 2; refusing to rewrite migration history`)
+	c.Assert(rolledBackVersions(c, conn), qt.DeepEquals, []int64{})
+}
+
+func TestMigratorForceDownWithForceRollsBackOnlyThatVersion(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, conn := appliedRollbackMigrator(c)
+	m = m.WithRecoveryOperations(true)
+
+	c.Assert(m.ForceDownWithOptions(ctx, migrator.ForceDownOptions{Version: 2, Force: true}), qt.IsNil)
+
+	c.Assert(rolledBackVersions(c, conn), qt.DeepEquals, []int64{2})
+	applied, err := m.GetAppliedMigrations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(applied, qt.DeepEquals, []int64{1, 3})
+	var tables int
+	c.Assert(conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'posts'").Scan(&tables), qt.IsNil)
+	c.Assert(tables, qt.Equals, 1)
+}
+
+func TestMigratorForceDownRejectsUnappliedVersions(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	m, _ := appliedRollbackMigrator(c)
+	m = m.WithRecoveryOperations(true)
+	c.Assert(m.ForceDown(ctx, 3), qt.IsNil)
+
+	c.Assert(m.ForceDown(ctx, 3), qt.ErrorMatches, `migration 3 is not applied; nothing to roll back`)
+	c.Assert(m.ForceDownWithOptions(ctx, migrator.ForceDownOptions{Version: 4, Force: true}), qt.ErrorMatches, `migration 4 not found`)
+}
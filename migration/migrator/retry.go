@@ -0,0 +1,150 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	mssql "github.com/microsoft/go-mssqldb"
+
+	"github.com/stokaro/ptah/core/platform"
+)
+
+// defaultRetryBackoff is the wait before the first retry when RetryOptions
+// leaves Backoff unset. Each further retry doubles the previous wait.
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// maxRetryBackoff caps the doubled wait, unless Backoff itself is longer.
+const maxRetryBackoff = time.Minute
+
+// RetryOptions configures how transactional migrations are retried after a
+// transient failure such as a serialization failure or a deadlock.
+//
+// Only migrations that run in their own transaction (the default tx-mode file)
+// are retried: on dialects with transactional DDL the failed transaction is
+// rolled back in full, so re-running it is safe. MySQL and MariaDB commit
+// implicitly before most DDL statements, so a failed attempt there may
+// already have applied part of the migration; DefaultRetryableErrors retries
+// nothing on those dialects, and a custom RetryableErrors should only accept
+// errors from migrations without DDL. no_transaction migrations and tx-mode
+// none/all runs are never retried because a failed attempt may already have
+// applied some statements.
+type RetryOptions struct {
+	// MaxRetries is the number of additional attempts after the first failure.
+	// Zero disables retries.
+	MaxRetries int
+	// RetryableErrors reports whether a migration error is transient. Nil uses
+	// DefaultRetryableErrors for the connection dialect.
+	RetryableErrors func(error) bool
+	// Backoff is the wait before the first retry; it doubles on every further
+	// retry up to one minute, or up to Backoff when that is longer. Zero uses
+	// 100ms.
+	Backoff time.Duration
+}
+
+// WithRetry configures retrying transactional migrations that fail with a
+// transient error. Retries are disabled by default.
+func (m *Migrator) WithRetry(opts RetryOptions) *Migrator {
+	tmp := *m
+	tmp.retry = opts
+	return &tmp
+}
+
+// DefaultRetryableErrors returns the transient-error classifier used for a
+// dialect when RetryOptions.RetryableErrors is nil:
+//
+//   - PostgreSQL: SQLSTATE 40001 (serialization_failure) and 40P01
+//     (deadlock_detected).
+//   - SQL Server: error 1205 (deadlock victim).
+//   - SQLite: SQLITE_BUSY ("database is locked").
+//
+// Other dialects, including MySQL and MariaDB, treat every error as
+// permanent: their implicit commit before DDL means a deadlocked migration
+// may be partly applied, and re-running it would replay statements that
+// already took effect.
+func DefaultRetryableErrors(dialect string) func(error) bool {
+	switch platform.NormalizeDialect(dialect) {
+	case platform.Postgres:
+		return isPostgresRetryableError
+	case platform.SQLServer:
+		return isSQLServerRetryableError
+	case platform.SQLite:
+		return isSQLiteRetryableError
+	default:
+		return func(error) bool { return false }
+	}
+}
+
+func isPostgresRetryableError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+func isSQLServerRetryableError(err error) bool {
+	var mssqlErr mssql.Error
+	return errors.As(err, &mssqlErr) && mssqlErr.Number == 1205
+}
+
+func isSQLiteRetryableError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "SQLITE_BUSY") || strings.Contains(msg, "database is locked")
+}
+
+// shouldRetryMigration reports whether a failed transactional attempt of
+// migration should be re-run, waiting out the backoff first. attempt is the
+// zero-based number of retries already made. It returns false without waiting
+// when retries are exhausted, the error is permanent, or ctx is done.
+func (m *Migrator) shouldRetryMigration(ctx context.Context, migration *Migration, attempt int, err error) bool {
+	if attempt >= m.retry.MaxRetries || ctx.Err() != nil {
+		return false
+	}
+	retryable := m.retry.RetryableErrors
+	if retryable == nil {
+		retryable = DefaultRetryableErrors(m.conn.Info().Dialect)
+	}
+	if !retryable(err) {
+		return false
+	}
+
+	backoff := m.retry.Backoff
+	if backoff <= 0 {
+		backoff = defaultRetryBackoff
+	}
+	wait := retryBackoff(backoff, attempt)
+	m.logger.Warn("Retrying migration after transient error",
+		"version", migration.Version,
+		"attempt", attempt+1,
+		"max_retries", m.retry.MaxRetries,
+		"backoff", wait,
+		"error", err,
+	)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// retryBackoff returns backoff doubled once per retry already made, capped at
+// maxRetryBackoff or backoff, whichever is longer, so a large attempt count
+// cannot overflow into a negative wait.
+func retryBackoff(backoff time.Duration, attempt int) time.Duration {
+	limit := max(backoff, maxRetryBackoff)
+	wait := backoff
+	for range attempt {
+		if wait > limit/2 {
+			return limit
+		}
+		wait *= 2
+	}
+	return wait
+}
@@ -0,0 +1,33 @@
+package migrator
+
+// White-box testing required: the backoff cap only matters after dozens of
+// retries, which a test cannot wait out through the public retry loop.
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first retry", backoff: 100 * time.Millisecond, attempt: 0, want: 100 * time.Millisecond},
+		{name: "doubles per retry", backoff: 100 * time.Millisecond, attempt: 3, want: 800 * time.Millisecond},
+		{name: "capped", backoff: 100 * time.Millisecond, attempt: 10, want: time.Minute},
+		{name: "no overflow", backoff: 100 * time.Millisecond, attempt: 200, want: time.Minute},
+		{name: "long backoff is its own cap", backoff: time.Hour, attempt: 5, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(retryBackoff(tt.backoff, tt.attempt), qt.Equals, tt.want)
+		})
+	}
+}
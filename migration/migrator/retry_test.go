@@ -0,0 +1,154 @@
+package migrator_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	mssql "github.com/microsoft/go-mssqldb"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+var errTransient = errors.New("transient conflict")
+
+// flakyMigration creates a table and then fails with failure for the first
+// failures attempts, counting every attempt. Because each attempt runs in its
+// own transaction, a retried attempt only succeeds if the failed one was
+// rolled back.
+type flakyMigration struct {
+	failures int
+	failure  error
+	attempts int
+}
+
+func (f *flakyMigration) up(ctx context.Context, conn *dbschema.DatabaseConnection) error {
+	f.attempts++
+	if err := conn.Writer().ExecuteSQL(ctx, "CREATE TABLE retried (id INTEGER PRIMARY KEY)"); err != nil {
+		return err
+	}
+	return f.failAttempt()
+}
+
+func (f *flakyMigration) failAttempt() error {
+	if f.attempts > f.failures {
+		return nil
+	}
+	return fmt.Errorf("attempt %d: %w", f.attempts, f.failure)
+}
+
+func flakyMigrator(c *qt.C, flaky *flakyMigration) *migrator.Migrator {
+	provider := migrator.NewRegisteredMigrationProvider(&migrator.Migration{
+		Version:     1,
+		Description: "Create retried table",
+		Up:          flaky.up,
+		Down:        migrator.NoopMigrationFunc,
+	})
+	return migrator.NewMigrator(openRollbackTestDB(c), provider)
+}
+
+func retryTransient(maxRetries int) migrator.RetryOptions {
+	return migrator.RetryOptions{
+		MaxRetries:      maxRetries,
+		RetryableErrors: func(err error) bool { return errors.Is(err, errTransient) },
+		Backoff:         time.Millisecond,
+	}
+}
+
+func TestMigratorRetriesTransientFailures(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	flaky := &flakyMigration{failures: 2, failure: errTransient}
+	m := flakyMigrator(c, flaky).WithRetry(retryTransient(2))
+
+	c.Assert(m.MigrateUp(ctx), qt.IsNil)
+
+	c.Assert(flaky.attempts, qt.Equals, 3)
+	version, err := m.GetCurrentVersion(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(version, qt.Equals, int64(1))
+}
+
+func TestMigratorRetryGivesUpAfterMaxRetries(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	flaky := &flakyMigration{failures: 3, failure: errTransient}
+	m := flakyMigrator(c, flaky).WithRetry(retryTransient(2))
+
+	err := m.MigrateUp(ctx)
+
+	c.Assert(err, qt.ErrorMatches, `failed to apply migration 1: attempt 3: transient conflict`)
+	c.Assert(flaky.attempts, qt.Equals, 3)
+	c.Assert(m.MigrateUp(ctx), qt.Satisfies, migrator.IsDirtyMigration)
+}
+
+func TestMigratorRetryFailsImmediatelyOnPermanentErrors(t *testing.T) {
+	c := qt.New(t)
+	flaky := &flakyMigration{failures: 1, failure: errors.New("syntax error")}
+	m := flakyMigrator(c, flaky).WithRetry(retryTransient(5))
+
+	c.Assert(m.MigrateUp(context.Background()), qt.ErrorMatches, `failed to apply migration 1: attempt 1: syntax error`)
+	c.Assert(flaky.attempts, qt.Equals, 1)
+}
+
+func TestMigratorDoesNotRetryByDefault(t *testing.T) {
+	c := qt.New(t)
+	flaky := &flakyMigration{failures: 1, failure: errTransient}
+	m := flakyMigrator(c, flaky)
+
+	c.Assert(m.MigrateUp(context.Background()), qt.IsNotNil)
+	c.Assert(flaky.attempts, qt.Equals, 1)
+}
+
+func TestMigratorRetryStopsWhenContextIsCanceled(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	flaky := &flakyMigration{failures: 1, failure: errTransient}
+	m := flakyMigrator(c, flaky).WithRetry(migrator.RetryOptions{
+		MaxRetries:      1,
+		RetryableErrors: func(err error) bool { cancel(); return errors.Is(err, errTransient) },
+		Backoff:         time.Hour,
+	})
+
+	c.Assert(m.MigrateUp(ctx), qt.IsNotNil)
+	c.Assert(flaky.attempts, qt.Equals, 1)
+}
+
+func TestDefaultRetryableErrors_MySQLDeadlocksAreNotRetried(t *testing.T) {
+	// MySQL and MariaDB commit implicitly before DDL, so a deadlocked
+	// migration may be partly applied and re-running it is not safe.
+	for _, dialect := range []string{"mysql", "mariadb"} {
+		t.Run(dialect, func(t *testing.T) {
+			c := qt.New(t)
+			retryable := migrator.DefaultRetryableErrors(dialect)
+			c.Assert(retryable(fmt.Errorf("failed to execute SQL: %w", &mysql.MySQLError{Number: 1213})), qt.IsFalse)
+		})
+	}
+}
+
+func TestDefaultRetryableErrors(t *testing.T) {
+	c := qt.New(t)
+	wrap := func(err error) error { return fmt.Errorf("failed to execute SQL: %w", err) }
+
+	postgres := migrator.DefaultRetryableErrors("postgres")
+	c.Assert(postgres(wrap(&pgconn.PgError{Code: "40001"})), qt.IsTrue)
+	c.Assert(postgres(wrap(&pgconn.PgError{Code: "40P01"})), qt.IsTrue)
+	c.Assert(postgres(wrap(&pgconn.PgError{Code: "42601"})), qt.IsFalse)
+	c.Assert(postgres(wrap(&pgconn.PgError{Code: "23505"})), qt.IsFalse)
+
+	sqlServer := migrator.DefaultRetryableErrors("sqlserver")
+	c.Assert(sqlServer(wrap(mssql.Error{Number: 1205})), qt.IsTrue)
+	c.Assert(sqlServer(wrap(mssql.Error{Number: 2627})), qt.IsFalse)
+
+	sqlite := migrator.DefaultRetryableErrors("sqlite")
+	c.Assert(sqlite(errors.New("database is locked (5) (SQLITE_BUSY)")), qt.IsTrue)
+	c.Assert(sqlite(errors.New("no such table: users")), qt.IsFalse)
+
+	c.Assert(migrator.DefaultRetryableErrors("clickhouse")(errTransient), qt.IsFalse)
+}
@@ -158,9 +158,9 @@ ORDER BY version`, m.qualifiedMigrationsTable())
 	}
 	return fmt.Sprintf(`SELECT version, description, state, applied, total, COALESCE(error, ''), COALESCE(error_stmt, ''), execution_time_ms, checksum, applied_at
 FROM %s
-WHERE state <> ?
+WHERE state <> ?%s
 ORDER BY version
-LIMIT 1`, m.qualifiedMigrationsTable())
+LIMIT 1`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) getRevisionSQL() string {
@@ -171,7 +171,7 @@ WHERE version = ?`, m.qualifiedMigrationsTable())
 	}
 	return fmt.Sprintf(`SELECT version, description, state, applied, total, COALESCE(error, ''), COALESCE(error_stmt, ''), execution_time_ms, checksum, applied_at
 FROM %s
-WHERE version = ?`, m.qualifiedMigrationsTable())
+WHERE version = ?%s`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) getAppliedRevisionsSQL() string {
@@ -187,8 +187,8 @@ ORDER BY %s`,
 	}
 	return fmt.Sprintf(`SELECT version, description, state, applied, total, COALESCE(error, ''), COALESCE(error_stmt, ''), execution_time_ms, checksum, applied_at
 FROM %s
-WHERE state = 'applied'
-ORDER BY version`, m.qualifiedMigrationsTable())
+WHERE state = 'applied'%s
+ORDER BY version`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) beginMigrationSQL() string {
@@ -196,8 +196,9 @@ func (m *Migrator) beginMigrationSQL() string {
 		return fmt.Sprintf(`INSERT INTO %s (version, description, type, applied, total, executed_at, execution_time, error, error_stmt, hash, partial_hashes, operator_version)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, m.qualifiedMigrationsTable())
 	}
-	return fmt.Sprintf(`INSERT INTO %s (version, description, applied_at, state, applied, total, error, error_stmt, execution_time_ms, checksum)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, m.qualifiedMigrationsTable())
+	column, value := m.tenantRevisionColumn()
+	return fmt.Sprintf(`INSERT INTO %s (version, description, applied_at, state, applied, total, error, error_stmt, execution_time_ms, checksum%s)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?%s)`, m.qualifiedMigrationsTable(), column, value)
 }
 
 func (m *Migrator) completeMigrationSQL() string {
@@ -214,7 +215,7 @@ SETTINGS mutations_sync = 1`, m.qualifiedMigrationsTable())
 	}
 	return fmt.Sprintf(`UPDATE %s
 SET state = ?, applied = ?, total = ?, error = NULL, error_stmt = NULL, execution_time_ms = ?, applied_at = ?
-WHERE version = ?`, m.qualifiedMigrationsTable())
+WHERE version = ?%s`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) beginRollbackSQL() string {
@@ -231,7 +232,7 @@ SETTINGS mutations_sync = 1`, m.qualifiedMigrationsTable())
 	}
 	return fmt.Sprintf(`UPDATE %s
 SET state = ?, applied = ?, total = ?, error = NULL, error_stmt = NULL, execution_time_ms = ?
-WHERE version = ?`, m.qualifiedMigrationsTable())
+WHERE version = ?%s`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) failMigrationSQL() string {
@@ -248,7 +249,7 @@ SETTINGS mutations_sync = 1`, m.qualifiedMigrationsTable())
 	}
 	return fmt.Sprintf(`UPDATE %s
 SET state = ?, applied = ?, total = ?, error = ?, error_stmt = ?, execution_time_ms = ?
-WHERE version = ?`, m.qualifiedMigrationsTable())
+WHERE version = ?%s`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) forceAppliedMigrationSQL() string {
@@ -257,9 +258,10 @@ func (m *Migrator) forceAppliedMigrationSQL() string {
 VALUES (?, ?, ?, ?, ?, ?, ?, NULL, NULL, ?, NULL, ?)
 %s`, m.qualifiedMigrationsTable(), m.forceAppliedConflictClause())
 	}
-	return fmt.Sprintf(`INSERT INTO %s (version, description, applied_at, state, applied, total, error, error_stmt, execution_time_ms, checksum)
-VALUES (?, ?, ?, ?, ?, ?, NULL, NULL, ?, ?)
-%s`, m.qualifiedMigrationsTable(), m.forceAppliedConflictClause())
+	column, value := m.tenantRevisionColumn()
+	return fmt.Sprintf(`INSERT INTO %s (version, description, applied_at, state, applied, total, error, error_stmt, execution_time_ms, checksum%s)
+VALUES (?, ?, ?, ?, ?, ?, NULL, NULL, ?, ?%s)
+%s`, m.qualifiedMigrationsTable(), column, value, m.forceAppliedConflictClause())
 }
 
 func (m *Migrator) forceAppliedUpdateSQL() string {
@@ -270,14 +272,14 @@ SETTINGS mutations_sync = 1`, m.qualifiedMigrationsTable())
 }
 
 func (m *Migrator) countRevisionsSQL() string {
-	return fmt.Sprintf(`SELECT COUNT(*) FROM %s`, m.qualifiedMigrationsTable())
+	return fmt.Sprintf(`SELECT COUNT(*) FROM %s%s`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" WHERE "))
 }
 
 func (m *Migrator) countRevisionsAboveSQL() string {
 	if m.revisionTableFormat.isAtlas() {
 		return fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s > ?`, m.qualifiedMigrationsTable(), m.atlasVersionNumberExpression())
 	}
-	return fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE version > ?`, m.qualifiedMigrationsTable())
+	return fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE version > ?%s`, m.qualifiedMigrationsTable(), m.tenantRevisionFilter(" AND "))
 }
 
 func (m *Migrator) atlasVersionNumberExpression() string {
@@ -356,7 +358,11 @@ hash = EXCLUDED.hash,
 partial_hashes = NULL,
 operator_version = EXCLUDED.operator_version`
 		}
-		return `ON CONFLICT (version) DO UPDATE SET
+		target := "version"
+		if m.sharedTenantRevisions() {
+			target = tenantSchemaColumn + ", version"
+		}
+		return `ON CONFLICT (` + target + `) DO UPDATE SET
 description = EXCLUDED.description,
 applied_at = EXCLUDED.applied_at,
 state = EXCLUDED.state,
@@ -843,7 +849,7 @@ func (m *Migrator) baselineMigrations(ctx context.Context, migrations []*Migrati
 	if m.isClickHouse() {
 		return m.baselineMigrationsNoTransaction(ctx, migrations)
 	}
-	tx, err := m.conn.SchemaWriter().BeginTransaction(ctx)
+	tx, err := m.beginMigrationTransaction(ctx, sql.LevelDefault)
 	if err != nil {
 		return fmt.Errorf("failed to begin baseline transaction: %w", err)
 	}
@@ -977,18 +983,16 @@ func (m *Migrator) migrationByVersion(version int64) *Migration {
 }
 
 func (m *Migrator) resumeMigration(ctx context.Context, migration *Migration, resumeFrom int) error {
-	statements := splitSQLStatementsForConnection(m.conn, migration.UpSQL)
+	statements, err := recoveryStatements(m.conn, migration)
+	if err != nil {
+		return err
+	}
 	if resumeFrom < 1 || resumeFrom > len(statements) {
 		return fmt.Errorf("resume-from must be between 1 and %d", len(statements))
 	}
-	for i := resumeFrom - 1; i < len(statements); i++ {
-		stmt := strings.TrimSpace(statements[i])
-		if stmt == "" {
-			continue
-		}
-		if err := executeSQLOutsideTransaction(ctx, m.conn, stmt); err != nil {
-			return fmt.Errorf("failed to resume migration %d at statement %d: %w", migration.Version, i+1, err)
-		}
+	skip := func(index int) bool { return index < resumeFrom }
+	if execErr := m.rerunMigrationStatements(ctx, statements, skip); execErr != nil {
+		return fmt.Errorf("failed to resume migration %d at statement %d: %w", migration.Version, execErr.StatementIndex, execErr)
 	}
 	return nil
 }
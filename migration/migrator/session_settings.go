@@ -0,0 +1,205 @@
+package migrator
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stokaro/ptah/core/platform"
+)
+
+// sessionSettingNamePattern limits setting names to plain (optionally dotted)
+// identifiers, so a name can be spliced into a SET statement unquoted.
+var sessionSettingNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sessionSetting is one session setting translated to a dialect's name and
+// value syntax.
+type sessionSetting struct {
+	name  string
+	value string
+}
+
+// SessionSettingStatements renders settings as the statements that scope them
+// to one transactional migration file: setup runs before the file's
+// statements and restore after them. PostgreSQL uses SET LOCAL, which the end
+// of the migration transaction resets, so restore is empty. MySQL and MariaDB
+// save each previous session value in a user variable, run SET SESSION, and
+// restore sets the saved value back, so the pooled connection is handed on
+// with its settings unchanged. Settings are emitted in name order.
+//
+// The portable names lock_timeout and statement_timeout are translated on
+// MySQL (lock_wait_timeout, max_execution_time) and MariaDB
+// (lock_wait_timeout, max_statement_time), with their values converted from
+// PostgreSQL duration syntax such as 5s or 10min. Other names pass through
+// unchanged. MySQL's max_execution_time only bounds read-only SELECT
+// statements, so on MySQL statement_timeout does not limit the DDL and DML a
+// migration runs; bound those with lock_timeout instead.
+//
+// The statements only hold on the connection of the migration transaction,
+// so they are not meant for no_transaction files, whose statements may run on
+// different pooled connections.
+func SessionSettingStatements(dialect string, settings map[string]string) (setup, restore []string, err error) {
+	return scopedSessionSettingStatements(dialect, settings, "@ptah_file_prev_")
+}
+
+// WithSessionSettings returns a copy of the migrator that applies settings
+// to the session of every transactional migration it runs and restores them
+// afterwards. PostgreSQL uses SET LOCAL, which the end of the migration
+// transaction resets; MySQL and MariaDB save the previous session values and
+// set them back. Names and values follow SessionSettingStatements.
+// Non-transactional migrations run on the connection pool and are executed
+// without the settings.
+func (m *Migrator) WithSessionSettings(settings map[string]string) *Migrator {
+	tmp := *m
+	tmp.sessionSettings = maps.Clone(settings)
+	return &tmp
+}
+
+// sessionSettingRestoreStatements returns the statements that apply the
+// migrator's settings inside a migration transaction and the statements that
+// undo them, in the order they must run. They save previous values under a
+// different prefix than SessionSettingStatements, so settings written into a
+// generated file nest inside them without losing the original values.
+func sessionSettingRestoreStatements(dialect string, settings map[string]string) (setupStatements, restoreStatements []string, err error) {
+	return scopedSessionSettingStatements(dialect, settings, "@ptah_prev_")
+}
+
+func scopedSessionSettingStatements(dialect string, settings map[string]string, prefix string) (setupStatements, restoreStatements []string, err error) {
+	resolved, err := resolveSessionSettings(dialect, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	if platform.NormalizeDialect(dialect) == platform.Postgres {
+		setup := make([]string, 0, len(resolved))
+		for _, setting := range resolved {
+			setup = append(setup, "SET LOCAL "+setting.name+" = "+setting.value)
+		}
+		return setup, nil, nil
+	}
+	setup := make([]string, 0, 2*len(resolved))
+	restore := make([]string, 0, len(resolved))
+	for _, setting := range resolved {
+		previous := prefix + strings.ReplaceAll(setting.name, ".", "_")
+		setup = append(setup,
+			"SET "+previous+" = @@SESSION."+setting.name,
+			"SET SESSION "+setting.name+" = "+setting.value,
+		)
+		restore = append(restore, "SET SESSION "+setting.name+" = "+previous)
+	}
+	return setup, reverseStrings(restore), nil
+}
+
+func resolveSessionSettings(dialect string, settings map[string]string) ([]sessionSetting, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	normalized := platform.NormalizeDialect(dialect)
+	switch normalized {
+	case platform.Postgres, platform.MySQL, platform.MariaDB:
+	default:
+		return nil, fmt.Errorf("session settings are not supported for dialect %q", dialect)
+	}
+
+	resolved := make([]sessionSetting, 0, len(settings))
+	for _, name := range slices.Sorted(maps.Keys(settings)) {
+		if !sessionSettingNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid session setting name %q", name)
+		}
+		value := strings.TrimSpace(settings[name])
+		if value == "" {
+			return nil, fmt.Errorf("session setting %s has an empty value", name)
+		}
+		setting, err := resolveSessionSetting(normalized, name, value)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, setting)
+	}
+	return resolved, nil
+}
+
+func resolveSessionSetting(dialect, name, value string) (sessionSetting, error) {
+	if dialect == platform.Postgres {
+		return sessionSetting{name: name, value: sessionStringLiteral(value)}, nil
+	}
+
+	var translated string
+	switch name {
+	case "lock_timeout":
+		translated = "lock_wait_timeout"
+	case "statement_timeout":
+		// max_execution_time only applies to SELECT; see SessionSettingStatements.
+		translated = "max_execution_time"
+		if dialect == platform.MariaDB {
+			translated = "max_statement_time"
+		}
+	default:
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return sessionSetting{name: name, value: value}, nil
+		}
+		return sessionSetting{name: name, value: sessionStringLiteral(value)}, nil
+	}
+
+	duration, err := parseSessionDuration(value)
+	if err != nil {
+		return sessionSetting{}, fmt.Errorf("invalid session setting %s value: %w", name, err)
+	}
+	switch translated {
+	case "max_execution_time":
+		return sessionSetting{name: translated, value: strconv.FormatInt(durationMillis(duration), 10)}, nil
+	case "max_statement_time":
+		return sessionSetting{name: translated, value: strconv.FormatFloat(duration.Seconds(), 'f', -1, 64)}, nil
+	default:
+		return sessionSetting{name: translated, value: strconv.FormatInt(durationSeconds(duration), 10)}, nil
+	}
+}
+
+// parseSessionDuration parses a positive duration in PostgreSQL setting syntax
+// (a bare number of milliseconds, or a number followed by ms, s, min, h or d),
+// falling back to Go duration syntax.
+func parseSessionDuration(value string) (time.Duration, error) {
+	units := []struct {
+		suffix string
+		unit   time.Duration
+	}{
+		{"ms", time.Millisecond},
+		{"min", time.Minute},
+		{"s", time.Second},
+		{"h", time.Hour},
+		{"d", 24 * time.Hour},
+		{"", time.Millisecond},
+	}
+	compact := strings.ReplaceAll(value, " ", "")
+	for _, candidate := range units {
+		number, ok := strings.CutSuffix(compact, candidate.suffix)
+		if !ok {
+			continue
+		}
+		amount, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			continue
+		}
+		if amount <= 0 {
+			return 0, fmt.Errorf("must be greater than zero")
+		}
+		return time.Duration(amount) * candidate.unit, nil
+	}
+	return parsePositiveDuration(value)
+}
+
+func sessionStringLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// warnSessionSettingsSkipped logs that a non-transactional migration runs
+// without the configured session settings.
+func (m *Migrator) warnSessionSettingsSkipped(version int64) {
+	if len(m.sessionSettings) == 0 {
+		return
+	}
+	m.logger.Warn("Session settings are not applied to non-transactional migrations", "version", version)
+}
@@ -0,0 +1,258 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/sqlutil"
+)
+
+// TenantRevisionTable selects where MigrateUpForSchemas records the applied
+// migrations of each tenant schema.
+type TenantRevisionTable string
+
+const (
+	// TenantRevisionTablePerSchema keeps the migrations table inside every
+	// tenant schema, so dropping a tenant schema drops its history with it.
+	TenantRevisionTablePerSchema TenantRevisionTable = "per_schema"
+	// TenantRevisionTableShared keeps the history of every tenant in the one
+	// table set with WithMigrationsTable, keyed by a schema_name column. The
+	// table layout differs from the one single-schema runs use, so give it a
+	// name of its own. It requires RevisionTableFormatPtah.
+	TenantRevisionTableShared TenantRevisionTable = "shared"
+)
+
+// MigrateForSchemasOptions configures MigrateUpForSchemasWithOptions.
+type MigrateForSchemasOptions struct {
+	// RevisionTable selects where applied migrations are recorded. Empty
+	// means TenantRevisionTablePerSchema.
+	RevisionTable TenantRevisionTable
+	// ContinueOnError migrates the remaining schemas after one fails. By
+	// default the run stops at the first failing schema.
+	ContinueOnError bool
+}
+
+// SchemaMigrationResult reports the outcome of migrating one tenant schema.
+type SchemaMigrationResult struct {
+	// Schema is the tenant schema.
+	Schema string
+	// Err is nil when every pending migration was applied to Schema.
+	Err error
+}
+
+// tenantSchemaSearchPath is the session setting that points unqualified
+// names in a migration at the tenant schema.
+const tenantSchemaSearchPath = "search_path"
+
+// tenantSchemaColumn is the column of the shared tenant revision table that
+// holds the schema a revision was applied to.
+const tenantSchemaColumn = "schema_name"
+
+// maxPostgreSQLIdentifierLength is the byte length beyond which PostgreSQL
+// silently truncates identifiers.
+const maxPostgreSQLIdentifierLength = 63
+
+// tenantRun scopes a migrator copy to one schema of a MigrateUpForSchemas
+// run.
+type tenantRun struct {
+	// schemas are all schemas of the run, none of which a migration may name.
+	schemas []string
+	schema  string
+	// shared records revisions in the shared table, scoped by schema.
+	shared bool
+}
+
+// MigrateUpForSchemas applies the pending migrations to every schema in
+// schemas, in order, with the default MigrateForSchemasOptions.
+func (m *Migrator) MigrateUpForSchemas(ctx context.Context, schemas []string) ([]SchemaMigrationResult, error) {
+	return m.MigrateUpForSchemasWithOptions(ctx, schemas, MigrateForSchemasOptions{})
+}
+
+// MigrateUpForSchemasWithOptions applies the same migrations to every schema
+// in schemas on PostgreSQL, one schema at a time. Each migration transaction
+// runs with search_path set to the tenant schema, so the migrations must use
+// unqualified names. Before a schema is migrated, each of its pending
+// migrations is checked for that; a Go migration cannot be checked and is
+// rejected. Pending migrations must also run in a transaction, because
+// search_path is applied with SET LOCAL, and must not declare pre-migration
+// checks, which run outside that transaction. A schema whose check fails
+// counts as a failed schema.
+//
+// The result holds one entry per schema attempted. Without ContinueOnError
+// the run stops at the first failure and later schemas are not attempted.
+// The returned error joins the failures of all attempted schemas.
+func (m *Migrator) MigrateUpForSchemasWithOptions(ctx context.Context, schemas []string, opts MigrateForSchemasOptions) ([]SchemaMigrationResult, error) {
+	if err := m.validateTenantSchemas(schemas, opts); err != nil {
+		return nil, err
+	}
+
+	results := make([]SchemaMigrationResult, 0, len(schemas))
+	var failures []error
+	for _, schema := range schemas {
+		m.logger.Info("Migrating tenant schema", "schema", schema)
+		err := m.forTenantSchema(schemas, schema, opts.RevisionTable).MigrateUp(ctx)
+		results = append(results, SchemaMigrationResult{Schema: schema, Err: err})
+		if err == nil {
+			continue
+		}
+		failures = append(failures, fmt.Errorf("schema %s: %w", schema, err))
+		if !opts.ContinueOnError {
+			break
+		}
+	}
+	return results, errors.Join(failures...)
+}
+
+func (m *Migrator) validateTenantSchemas(schemas []string, opts MigrateForSchemasOptions) error {
+	if platform.NormalizeDialect(m.connectionDialect()) != platform.Postgres {
+		return fmt.Errorf("migrating multiple schemas is only supported on PostgreSQL, not %q", m.connectionDialect())
+	}
+	switch opts.RevisionTable {
+	case "", TenantRevisionTablePerSchema, TenantRevisionTableShared:
+	default:
+		return fmt.Errorf("unknown tenant revision table %q", opts.RevisionTable)
+	}
+	if opts.RevisionTable == TenantRevisionTableShared && m.revisionTableFormat.isAtlas() {
+		return fmt.Errorf("shared tenant revision tables require the %s revision table format", RevisionTableFormatPtah)
+	}
+	if m.txMode == MigrationTxModeNone {
+		return fmt.Errorf("migrating multiple schemas requires transactional migrations, not tx-mode %s", m.txMode)
+	}
+	if len(schemas) == 0 {
+		return fmt.Errorf("no schemas to migrate")
+	}
+	seen := make(map[string]bool, len(schemas))
+	for _, schema := range schemas {
+		if strings.TrimSpace(schema) == "" {
+			return fmt.Errorf("schema names must not be empty")
+		}
+		if len(schema) > maxPostgreSQLIdentifierLength {
+			return fmt.Errorf("schema name %s is longer than the PostgreSQL limit of %d bytes", schema, maxPostgreSQLIdentifierLength)
+		}
+		if seen[schema] {
+			return fmt.Errorf("schema %s is listed more than once", schema)
+		}
+		seen[schema] = true
+	}
+	return nil
+}
+
+// validateTenantMigrations rejects every pending migration that would not
+// follow the search_path of the tenant schema it is applied to. It does
+// nothing outside a MigrateUpForSchemas run.
+func (m *Migrator) validateTenantMigrations(migrations []*Migration) error {
+	if m.tenant == nil {
+		return nil
+	}
+	for _, migration := range migrations {
+		if strings.TrimSpace(migration.UpSQL) == "" {
+			return fmt.Errorf("migration %d has no SQL body; Go migrations cannot be checked for schema qualifiers", migration.Version)
+		}
+		if migration.upExecutionMode() == migrationExecutionNoTransaction {
+			return fmt.Errorf("migration %d runs outside a transaction, so it cannot be applied per schema", migration.Version)
+		}
+		if !m.skipChecks {
+			checks, err := ParseChecks(migration.UpSQL)
+			if err != nil {
+				return fmt.Errorf("migration %d has invalid pre-migration check directives: %w", migration.Version, err)
+			}
+			if len(checks) > 0 {
+				return fmt.Errorf("migration %d declares pre-migration checks, which cannot run per schema; use WithSkipChecks to bypass them", migration.Version)
+			}
+		}
+		if err := schemaRelativeSQL(migration.UpSQL, m.tenant.schemas); err != nil {
+			return fmt.Errorf("migration %d is not schema-relative: %w", migration.Version, err)
+		}
+	}
+	return nil
+}
+
+// forTenantSchema returns a copy of the migrator that applies migrations to
+// schema, one of the schemas of the run, and records them in the table
+// revisionTable selects.
+func (m *Migrator) forTenantSchema(schemas []string, schema string, revisionTable TenantRevisionTable) *Migrator {
+	tmp := *m
+	tmp.sessionSettings = maps.Clone(m.sessionSettings)
+	if tmp.sessionSettings == nil {
+		tmp.sessionSettings = make(map[string]string, 1)
+	}
+	tmp.sessionSettings[tenantSchemaSearchPath] = m.quoteIdentifier(schema)
+	tmp.tenant = &tenantRun{schemas: schemas, schema: schema, shared: revisionTable == TenantRevisionTableShared}
+	if tmp.tenant.shared {
+		return tmp.WithMigrationsTable(m.migrationsSchema, m.migrationsTable)
+	}
+	return tmp.WithMigrationsTable(schema, m.migrationsTable)
+}
+
+// sharedTenantRevisions reports whether revisions are recorded in the shared
+// tenant revision table.
+func (m *Migrator) sharedTenantRevisions() bool {
+	return m.tenant != nil && m.tenant.shared
+}
+
+// tenantRevisionFilter returns the condition that limits the shared tenant
+// revision table to the current schema, prefixed with prefix, or "" when
+// revisions are not shared.
+func (m *Migrator) tenantRevisionFilter(prefix string) string {
+	if !m.sharedTenantRevisions() {
+		return ""
+	}
+	return prefix + tenantSchemaColumn + " = " + m.tenantSchemaLiteral()
+}
+
+// tenantRevisionColumn returns the column list suffix and value list suffix
+// that record the current schema in the shared tenant revision table.
+func (m *Migrator) tenantRevisionColumn() (column, value string) {
+	if !m.sharedTenantRevisions() {
+		return "", ""
+	}
+	return ", " + tenantSchemaColumn, ", " + m.tenantSchemaLiteral()
+}
+
+func (m *Migrator) tenantSchemaLiteral() string {
+	return "'" + strings.ReplaceAll(m.tenant.schema, "'", "''") + "'"
+}
+
+// ensureTenantRevisionTable rejects a shared tenant revision table created
+// for single-schema runs, which has no schema column.
+func (m *Migrator) ensureTenantRevisionTable(ctx context.Context) error {
+	if !m.sharedTenantRevisions() {
+		return nil
+	}
+	exists, err := m.migrationsColumnExists(ctx, tenantSchemaColumn)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("migrations table %s has no %s column; use WithMigrationsTable to name a table for tenant revisions only", m.qualifiedMigrationsTable(), tenantSchemaColumn)
+	}
+	return nil
+}
+
+var (
+	sqlStringLiteralPattern = regexp.MustCompile(`'(?:[^']|'')*'`)
+	searchPathPattern       = regexp.MustCompile(`(?i)\bsearch_path\b`)
+)
+
+// schemaRelativeSQL reports an error when sql names one of schemas, or the
+// public schema, as a qualifier, or changes search_path itself. String
+// literals and comments are ignored.
+func schemaRelativeSQL(sql string, schemas []string) error {
+	code := sqlStringLiteralPattern.ReplaceAllString(sqlutil.StripComments(sql), "''")
+	if searchPathPattern.MatchString(code) {
+		return fmt.Errorf("it sets search_path")
+	}
+	for _, schema := range append([]string{"public"}, schemas...) {
+		quoted := regexp.QuoteMeta(`"` + strings.ReplaceAll(schema, `"`, `""`) + `"`)
+		qualifier := regexp.MustCompile(`(?:^|[^\w$."])(?:` + quoted + `|(?i:` + regexp.QuoteMeta(schema) + `))\s*\.`)
+		if qualifier.MatchString(code) {
+			return fmt.Errorf("it qualifies names with schema %s", schema)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package migrator
+
+// White-box testing required: MigrateUpForSchemas only runs on PostgreSQL,
+// so the schema-relative SQL check, the pending migration check and the
+// shared revision table SQL are exercised on the unexported helpers.
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+)
+
+func TestSchemaRelativeSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr string
+	}{
+		{name: "unqualified names", sql: "CREATE TABLE users (id INTEGER PRIMARY KEY);\nALTER TABLE users ADD COLUMN email TEXT;"},
+		{name: "catalog schemas", sql: "SELECT 1 FROM pg_catalog.pg_class WHERE relname = 'users';"},
+		{name: "table alias column", sql: "UPDATE users u SET email = lower(u.email);"},
+		{name: "schema in string literal", sql: "INSERT INTO notes (body) VALUES ('see tenant_a.users');"},
+		{name: "schema in comment", sql: "-- copied from public.users\nCREATE TABLE users (id INTEGER);"},
+		{name: "similar schema name", sql: "CREATE TABLE x_tenant_a.users (id INTEGER);"},
+		{name: "tenant qualifier", sql: "CREATE TABLE tenant_a.users (id INTEGER);", wantErr: "it qualifies names with schema tenant_a"},
+		{name: "quoted tenant qualifier", sql: `CREATE INDEX idx ON "tenant_b" . users (id);`, wantErr: "it qualifies names with schema tenant_b"},
+		{name: "public qualifier", sql: "ALTER TABLE Public.users ADD COLUMN name TEXT;", wantErr: "it qualifies names with schema public"},
+		{name: "search path", sql: "SET search_path TO tenant_a;", wantErr: "it sets search_path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			err := schemaRelativeSQL(tt.sql, []string{"tenant_a", "tenant_b"})
+
+			c.Assert(errorString(err), qt.Equals, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateTenantMigrations(t *testing.T) {
+	goMigration := &Migration{
+		Version:     3,
+		Description: "backfill",
+		Up:          func(context.Context, *dbschema.DatabaseConnection) error { return nil },
+	}
+	tests := []struct {
+		name       string
+		migrations []*Migration
+		wantErr    string
+	}{
+		{name: "schema-relative SQL", migrations: []*Migration{CreateMigrationFromSQL(1, "notes", "CREATE TABLE notes (id INTEGER);", "DROP TABLE notes;")}},
+		{name: "go migration", migrations: []*Migration{goMigration}, wantErr: "migration 3 has no SQL body; Go migrations cannot be checked for schema qualifiers"},
+		{name: "no transaction", migrations: []*Migration{CreateMigrationFromSQL(4, "index", "-- +ptah no_transaction\nCREATE INDEX CONCURRENTLY idx ON notes (id);", "DROP INDEX idx;")}, wantErr: "migration 4 runs outside a transaction, so it cannot be applied per schema"},
+		{name: "qualified SQL", migrations: []*Migration{CreateMigrationFromSQL(5, "qualified", "CREATE TABLE tenant_b.notes (id INTEGER);", "DROP TABLE tenant_b.notes;")}, wantErr: "migration 5 is not schema-relative: it qualifies names with schema tenant_b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			m := NewMigrator(nil, NewRegisteredMigrationProvider())
+			m.tenant = &tenantRun{schemas: []string{"tenant_a", "tenant_b"}, schema: "tenant_a"}
+
+			c.Assert(errorString(m.validateTenantMigrations(tt.migrations)), qt.Equals, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateTenantMigrations_OutsideTenantRun(t *testing.T) {
+	c := qt.New(t)
+	m := NewMigrator(nil, NewRegisteredMigrationProvider())
+
+	err := m.validateTenantMigrations([]*Migration{{Version: 1, Up: func(context.Context, *dbschema.DatabaseConnection) error { return nil }}})
+
+	c.Assert(err, qt.IsNil)
+}
+
+func TestSharedTenantRevisionSQL(t *testing.T) {
+	c := qt.New(t)
+	base := NewMigrator(nil, NewRegisteredMigrationProvider()).WithMigrationsTable("", "tenant_migrations")
+	m := base.forTenantSchema([]string{"o'brien"}, "o'brien", TenantRevisionTableShared)
+
+	c.Assert(m.qualifiedMigrationsTable(), qt.Equals, `"tenant_migrations"`)
+	c.Assert(m.createMigrationsTableSQL(), qt.Contains, "PRIMARY KEY (schema_name, version)")
+	c.Assert(m.getAppliedMigrationsSQL(), qt.Equals, `SELECT version FROM "tenant_migrations" WHERE state = 'applied' AND schema_name = 'o''brien' ORDER BY version`)
+	c.Assert(m.countRevisionsSQL(), qt.Equals, `SELECT COUNT(*) FROM "tenant_migrations" WHERE schema_name = 'o''brien'`)
+	c.Assert(m.beginMigrationSQL(), qt.Contains, "checksum, schema_name)\nVALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 'o''brien')")
+	for _, query := range []string{m.getRevisionSQL(), m.completeMigrationSQL(), m.failMigrationSQL(), m.deleteMigrationSQL()} {
+		c.Assert(strings.HasSuffix(query, "WHERE version = ? AND schema_name = 'o''brien'"), qt.IsTrue, qt.Commentf("%s", query))
+	}
+
+	perSchema := base.forTenantSchema([]string{"tenant_a"}, "tenant_a", TenantRevisionTablePerSchema)
+	c.Assert(perSchema.qualifiedMigrationsTable(), qt.Equals, `"tenant_a"."tenant_migrations"`)
+	c.Assert(perSchema.countRevisionsSQL(), qt.Equals, `SELECT COUNT(*) FROM "tenant_a"."tenant_migrations"`)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
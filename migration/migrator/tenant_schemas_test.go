@@ -0,0 +1,210 @@
+package migrator_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func tenantMigrations() []*migrator.Migration {
+	return []*migrator.Migration{
+		migrator.CreateMigrationFromSQL(1, "create_notes",
+			"CREATE TABLE ptah_tenant_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL);",
+			"DROP TABLE ptah_tenant_notes;"),
+		migrator.CreateMigrationFromSQL(2, "seed_notes",
+			"INSERT INTO ptah_tenant_notes (id, body) VALUES (1, 'welcome');",
+			"DELETE FROM ptah_tenant_notes WHERE id = 1;"),
+	}
+}
+
+func tenantMigrator(conn *dbschema.DatabaseConnection, migrations ...*migrator.Migration) *migrator.Migrator {
+	return migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(migrations...)).
+		WithMigrationsTable("", "ptah_tenant_migrations")
+}
+
+func TestMigrateUpForSchemas_RejectsNonPostgres(t *testing.T) {
+	c := qt.New(t)
+	conn := openApplyDiffSQLite(t)
+
+	results, err := tenantMigrator(conn, tenantMigrations()...).MigrateUpForSchemas(context.Background(), []string{"tenant_a"})
+
+	c.Assert(err, qt.ErrorMatches, `migrating multiple schemas is only supported on PostgreSQL, not "sqlite"`)
+	c.Assert(results, qt.IsNil)
+}
+
+func TestMigrateUpForSchemas_PostgresIntegration(t *testing.T) {
+	dbURL := postgresTestURL(t)
+
+	tests := []struct {
+		name           string
+		revisionTable  migrator.TenantRevisionTable
+		revisionCounts []string
+	}{
+		{
+			name:          "per schema",
+			revisionTable: migrator.TenantRevisionTablePerSchema,
+			revisionCounts: []string{
+				"SELECT COUNT(*) FROM ptah_tenant_a.ptah_tenant_migrations",
+				"SELECT COUNT(*) FROM ptah_tenant_b.ptah_tenant_migrations",
+			},
+		},
+		{
+			name:          "shared",
+			revisionTable: migrator.TenantRevisionTableShared,
+			revisionCounts: []string{
+				"SELECT COUNT(*) FROM ptah_tenant_migrations WHERE schema_name = 'ptah_tenant_a'",
+				"SELECT COUNT(*) FROM ptah_tenant_migrations WHERE schema_name = 'ptah_tenant_b'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+			conn := connectTenantPostgres(t, dbURL)
+			m := tenantMigrator(conn, tenantMigrations()...)
+			opts := migrator.MigrateForSchemasOptions{RevisionTable: tt.revisionTable}
+			schemas := []string{"ptah_tenant_a", "ptah_tenant_b"}
+
+			results, err := m.MigrateUpForSchemasWithOptions(ctx, schemas, opts)
+			c.Assert(err, qt.IsNil)
+			c.Assert(results, qt.DeepEquals, []migrator.SchemaMigrationResult{{Schema: "ptah_tenant_a"}, {Schema: "ptah_tenant_b"}})
+
+			_, err = m.MigrateUpForSchemasWithOptions(ctx, schemas, opts)
+			c.Assert(err, qt.IsNil)
+
+			for _, table := range []string{"ptah_tenant_a.ptah_tenant_notes", "ptah_tenant_b.ptah_tenant_notes"} {
+				var rows int
+				c.Assert(conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&rows), qt.IsNil)
+				c.Assert(rows, qt.Equals, 1)
+			}
+			for _, query := range tt.revisionCounts {
+				var applied int
+				c.Assert(conn.QueryRowContext(ctx, query).Scan(&applied), qt.IsNil)
+				c.Assert(applied, qt.Equals, 2)
+			}
+		})
+	}
+}
+
+func TestMigrateUpForSchemas_FailurePolicyIntegration(t *testing.T) {
+	dbURL := postgresTestURL(t)
+
+	tests := []struct {
+		name            string
+		continueOnError bool
+		wantAttempted   []string
+	}{
+		{name: "abort", continueOnError: false, wantAttempted: []string{"ptah_tenant_missing"}},
+		{name: "continue", continueOnError: true, wantAttempted: []string{"ptah_tenant_missing", "ptah_tenant_a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+			conn := connectTenantPostgres(t, dbURL)
+			// Shared revision tables leave a missing tenant schema missing, so
+			// its first CREATE TABLE fails.
+			opts := migrator.MigrateForSchemasOptions{
+				RevisionTable:   migrator.TenantRevisionTableShared,
+				ContinueOnError: tt.continueOnError,
+			}
+
+			results, err := tenantMigrator(conn, tenantMigrations()...).
+				MigrateUpForSchemasWithOptions(ctx, []string{"ptah_tenant_missing", "ptah_tenant_a"}, opts)
+
+			c.Assert(err, qt.ErrorMatches, `(?s)schema ptah_tenant_missing: .*`)
+			c.Assert(resultSchemas(results), qt.DeepEquals, tt.wantAttempted)
+			c.Assert(results[0].Err, qt.IsNotNil)
+			c.Assert(results[len(results)-1].Err == nil, qt.Equals, tt.continueOnError)
+		})
+	}
+}
+
+func TestMigrateUpForSchemas_RejectsQualifiedMigrationIntegration(t *testing.T) {
+	c := qt.New(t)
+	conn := connectTenantPostgres(t, postgresTestURL(t))
+	qualified := migrator.CreateMigrationFromSQL(1, "qualified",
+		"CREATE TABLE public.ptah_tenant_notes (id INTEGER PRIMARY KEY);",
+		"DROP TABLE public.ptah_tenant_notes;")
+
+	results, err := tenantMigrator(conn, qualified).MigrateUpForSchemas(context.Background(), []string{"ptah_tenant_a"})
+
+	c.Assert(err, qt.ErrorMatches, "schema ptah_tenant_a: migration 1 is not schema-relative: it qualifies names with schema public")
+	c.Assert(resultSchemas(results), qt.DeepEquals, []string{"ptah_tenant_a"})
+}
+
+func TestMigrateUpForSchemas_ChecksOnlyPendingMigrationsIntegration(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+	conn := connectTenantPostgres(t, postgresTestURL(t))
+	// Version 1 names its schema, but it was applied by a single-schema run,
+	// so only the pending version 2 is checked.
+	qualified := migrator.CreateMigrationFromSQL(1, "create_notes",
+		"CREATE TABLE ptah_tenant_a.ptah_tenant_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL);",
+		"DROP TABLE ptah_tenant_a.ptah_tenant_notes;")
+	migrations := []*migrator.Migration{qualified, tenantMigrations()[1]}
+	single := migrator.NewMigrator(conn, migrator.NewRegisteredMigrationProvider(migrations...)).
+		WithMigrationsTable("ptah_tenant_a", "ptah_tenant_migrations")
+	c.Assert(single.MigrateTo(ctx, 1), qt.IsNil)
+
+	results, err := tenantMigrator(conn, migrations...).MigrateUpForSchemas(ctx, []string{"ptah_tenant_a"})
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.DeepEquals, []migrator.SchemaMigrationResult{{Schema: "ptah_tenant_a"}})
+}
+
+func TestMigrateUpForSchemas_RejectsLongSchemaNameIntegration(t *testing.T) {
+	c := qt.New(t)
+	conn := connectTenantPostgres(t, postgresTestURL(t))
+	long := "ptah_tenant_" + strings.Repeat("x", 52)
+
+	results, err := tenantMigrator(conn, tenantMigrations()...).MigrateUpForSchemas(context.Background(), []string{long})
+
+	c.Assert(err, qt.ErrorMatches, "schema name "+long+" is longer than the PostgreSQL limit of 63 bytes")
+	c.Assert(results, qt.IsNil)
+}
+
+func resultSchemas(results []migrator.SchemaMigrationResult) []string {
+	schemas := make([]string, 0, len(results))
+	for _, result := range results {
+		schemas = append(schemas, result.Schema)
+	}
+	return schemas
+}
+
+// connectTenantPostgres connects to dbURL with fresh ptah_tenant_a and
+// ptah_tenant_b schemas, dropped again when the test ends.
+func connectTenantPostgres(t *testing.T, dbURL string) *dbschema.DatabaseConnection {
+	t.Helper()
+	ctx := context.Background()
+	conn, err := dbschema.ConnectToDatabase(ctx, dbURL)
+	qt.Assert(t, err, qt.IsNil)
+	reset := func() {
+		for _, statement := range []string{
+			"DROP SCHEMA IF EXISTS ptah_tenant_a CASCADE",
+			"DROP SCHEMA IF EXISTS ptah_tenant_b CASCADE",
+			"DROP SCHEMA IF EXISTS ptah_tenant_missing CASCADE",
+			"DROP TABLE IF EXISTS ptah_tenant_migrations",
+		} {
+			_, _ = conn.ExecContext(ctx, statement)
+		}
+	}
+	reset()
+	_, err = conn.ExecContext(ctx, "CREATE SCHEMA ptah_tenant_a")
+	qt.Assert(t, err, qt.IsNil)
+	_, err = conn.ExecContext(ctx, "CREATE SCHEMA ptah_tenant_b")
+	qt.Assert(t, err, qt.IsNil)
+	t.Cleanup(func() {
+		reset()
+		_ = conn.Close()
+	})
+	return conn
+}
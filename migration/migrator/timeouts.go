@@ -146,14 +146,24 @@ func (m *Migrator) applyTimeoutsWithRestore(
 	conn *dbschema.DatabaseConnection,
 	timeouts MigrationTimeouts,
 ) (restoreTimeoutsFunc, error) {
-	if timeouts.IsZero() {
+	if timeouts.IsZero() && len(m.sessionSettings) == 0 {
 		return noopRestoreTimeouts, nil
 	}
 
-	setupStatements, restoreStatements, err := timeoutStatements(conn.Info().Dialect, timeouts)
+	var setupStatements, restoreStatements []string
+	if !timeouts.IsZero() {
+		var err error
+		setupStatements, restoreStatements, err = timeoutStatements(conn.Info().Dialect, timeouts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	settingsSetup, settingsRestore, err := sessionSettingRestoreStatements(conn.Info().Dialect, m.sessionSettings)
 	if err != nil {
 		return nil, err
 	}
+	setupStatements = append(setupStatements, settingsSetup...)
+	restoreStatements = append(settingsRestore, restoreStatements...)
 
 	for _, statement := range setupStatements {
 		if err := conn.Writer().ExecuteSQL(ctx, statement); err != nil {
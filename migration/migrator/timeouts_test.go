@@ -156,3 +156,172 @@ func TestDurationUnitCeilUsesIntegerMath(t *testing.T) {
 	c.Assert(durationMillis(maxDuration), qt.Equals, int64(maxDuration/time.Millisecond)+1)
 	c.Assert(durationSeconds(maxDuration), qt.Equals, int64(maxDuration/time.Second)+1)
 }
+
+func TestSessionSettingStatements(t *testing.T) {
+	settings := map[string]string{"statement_timeout": "10min", "lock_timeout": "5s"}
+	tests := []struct {
+		name        string
+		dialect     string
+		settings    map[string]string
+		wantSetup   []string
+		wantRestore []string
+	}{
+		{
+			name:      "postgres",
+			dialect:   "postgres",
+			settings:  settings,
+			wantSetup: []string{"SET LOCAL lock_timeout = '5s'", "SET LOCAL statement_timeout = '10min'"},
+		},
+		{
+			name:     "mysql",
+			dialect:  "mysql",
+			settings: settings,
+			wantSetup: []string{
+				"SET @ptah_file_prev_lock_wait_timeout = @@SESSION.lock_wait_timeout",
+				"SET SESSION lock_wait_timeout = 5",
+				"SET @ptah_file_prev_max_execution_time = @@SESSION.max_execution_time",
+				"SET SESSION max_execution_time = 600000",
+			},
+			wantRestore: []string{
+				"SET SESSION max_execution_time = @ptah_file_prev_max_execution_time",
+				"SET SESSION lock_wait_timeout = @ptah_file_prev_lock_wait_timeout",
+			},
+		},
+		{
+			name:     "mariadb",
+			dialect:  "mariadb",
+			settings: settings,
+			wantSetup: []string{
+				"SET @ptah_file_prev_lock_wait_timeout = @@SESSION.lock_wait_timeout",
+				"SET SESSION lock_wait_timeout = 5",
+				"SET @ptah_file_prev_max_statement_time = @@SESSION.max_statement_time",
+				"SET SESSION max_statement_time = 600",
+			},
+			wantRestore: []string{
+				"SET SESSION max_statement_time = @ptah_file_prev_max_statement_time",
+				"SET SESSION lock_wait_timeout = @ptah_file_prev_lock_wait_timeout",
+			},
+		},
+		{
+			name:     "mysql passes other names through",
+			dialect:  "mysql",
+			settings: map[string]string{"time_zone": "+00:00"},
+			wantSetup: []string{
+				"SET @ptah_file_prev_time_zone = @@SESSION.time_zone",
+				"SET SESSION time_zone = '+00:00'",
+			},
+			wantRestore: []string{"SET SESSION time_zone = @ptah_file_prev_time_zone"},
+		},
+		{
+			name:      "postgres quotes values",
+			dialect:   "postgres",
+			settings:  map[string]string{"application_name": "ptah's migrator"},
+			wantSetup: []string{"SET LOCAL application_name = 'ptah''s migrator'"},
+		},
+		{
+			name:     "bare number is milliseconds",
+			dialect:  "mysql",
+			settings: map[string]string{"lock_timeout": "1500"},
+			wantSetup: []string{
+				"SET @ptah_file_prev_lock_wait_timeout = @@SESSION.lock_wait_timeout",
+				"SET SESSION lock_wait_timeout = 2",
+			},
+			wantRestore: []string{"SET SESSION lock_wait_timeout = @ptah_file_prev_lock_wait_timeout"},
+		},
+		{
+			name:        "empty",
+			dialect:     "sqlite",
+			wantSetup:   []string{},
+			wantRestore: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			gotSetup, gotRestore, err := SessionSettingStatements(tt.dialect, tt.settings)
+			c.Assert(err, qt.IsNil)
+			c.Assert(gotSetup, qt.DeepEquals, tt.wantSetup)
+			c.Assert(gotRestore, qt.DeepEquals, tt.wantRestore)
+		})
+	}
+}
+
+func TestSessionSettingStatements_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		settings map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "unsupported dialect",
+			dialect:  "sqlite",
+			settings: map[string]string{"lock_timeout": "5s"},
+			wantErr:  `session settings are not supported for dialect "sqlite"`,
+		},
+		{
+			name:     "injected name",
+			dialect:  "postgres",
+			settings: map[string]string{"lock_timeout = 0; DROP TABLE users; --": "5s"},
+			wantErr:  `invalid session setting name .*`,
+		},
+		{
+			name:     "empty value",
+			dialect:  "postgres",
+			settings: map[string]string{"lock_timeout": " "},
+			wantErr:  `session setting lock_timeout has an empty value`,
+		},
+		{
+			name:     "bad duration",
+			dialect:  "mysql",
+			settings: map[string]string{"lock_timeout": "soon"},
+			wantErr:  `invalid session setting lock_timeout value: .*`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			_, _, err := SessionSettingStatements(tt.dialect, tt.settings)
+			c.Assert(err, qt.ErrorMatches, tt.wantErr)
+		})
+	}
+}
+
+func TestSessionSettingRestoreStatements(t *testing.T) {
+	tests := []struct {
+		name        string
+		dialect     string
+		wantSetup   []string
+		wantRestore []string
+	}{
+		{
+			name:      "postgres uses transaction scope",
+			dialect:   "postgres",
+			wantSetup: []string{"SET LOCAL lock_timeout = '5s'"},
+		},
+		{
+			name:    "mysql saves previous value",
+			dialect: "mysql",
+			wantSetup: []string{
+				"SET @ptah_prev_lock_wait_timeout = @@SESSION.lock_wait_timeout",
+				"SET SESSION lock_wait_timeout = 5",
+			},
+			wantRestore: []string{"SET SESSION lock_wait_timeout = @ptah_prev_lock_wait_timeout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			gotSetup, gotRestore, err := sessionSettingRestoreStatements(tt.dialect, map[string]string{"lock_timeout": "5s"})
+			c.Assert(err, qt.IsNil)
+			c.Assert(gotSetup, qt.DeepEquals, tt.wantSetup)
+			c.Assert(gotRestore, qt.DeepEquals, tt.wantRestore)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package migrator
+
+import (
+	"context"
+	"fmt"
+)
+
+// DiscrepancyKind names a problem Verify found in the migration history.
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyMissingMigration is an applied version with no registered
+	// migration. Its down migration is gone, so it cannot be rolled back.
+	DiscrepancyMissingMigration DiscrepancyKind = "missing_migration"
+	// DiscrepancyChecksumMismatch is an applied migration whose registered
+	// SQL no longer matches the checksum recorded when it was applied.
+	DiscrepancyChecksumMismatch DiscrepancyKind = "checksum_mismatch"
+)
+
+// Discrepancy records one mismatch between the migrations table and the
+// registered migrations.
+type Discrepancy struct {
+	Kind        DiscrepancyKind `json:"kind"`
+	Version     int64           `json:"version"`
+	Description string          `json:"description,omitempty"`
+	// StoredChecksum is the checksum recorded in the migrations table.
+	StoredChecksum string `json:"stored_checksum,omitempty"`
+	// CurrentChecksum is the checksum of the registered migration, set for
+	// checksum mismatches.
+	CurrentChecksum string `json:"current_checksum,omitempty"`
+}
+
+func (d Discrepancy) String() string {
+	switch d.Kind {
+	case DiscrepancyMissingMigration:
+		return fmt.Sprintf("migration %d (%s) is applied but has no migration file; it cannot be rolled back", d.Version, d.Description)
+	case DiscrepancyChecksumMismatch:
+		return fmt.Sprintf("migration %d (%s) was edited after it was applied: stored checksum %s, current %s",
+			d.Version, d.Description, d.StoredChecksum, d.CurrentChecksum)
+	default:
+		return fmt.Sprintf("migration %d: %s", d.Version, d.Kind)
+	}
+}
+
+// Verify checks the migration history against the registered migrations. It
+// reports applied versions that no longer have a migration, which cannot be
+// rolled back, and applied migrations whose SQL changed since they ran.
+// Rows recorded without a checksum are not compared. Unlike
+// GetMigrationStatus, which looks ahead at pending work, Verify is a health
+// check of what has already been applied. The result is ordered by version
+// and empty when the history is consistent.
+func (m *Migrator) Verify(ctx context.Context) ([]Discrepancy, error) {
+	revisions, err := m.GetAppliedRevisions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migration revisions: %w", err)
+	}
+	registered := make(map[int64]*Migration)
+	for _, migration := range m.MigrationProvider().Migrations() {
+		registered[migration.Version] = migration
+	}
+
+	var discrepancies []Discrepancy
+	for _, revision := range revisions {
+		migration, ok := registered[revision.Version]
+		if !ok {
+			discrepancies = append(discrepancies, Discrepancy{
+				Kind:           DiscrepancyMissingMigration,
+				Version:        revision.Version,
+				Description:    revision.Description,
+				StoredChecksum: normalizeAtlasRevisionHash(revision.Checksum),
+			})
+			continue
+		}
+		if revision.Checksum == "" {
+			continue
+		}
+		stored := normalizeAtlasRevisionHash(revision.Checksum)
+		if current := migrationRevisionHash(migration); stored != current {
+			discrepancies = append(discrepancies, Discrepancy{
+				Kind:            DiscrepancyChecksumMismatch,
+				Version:         revision.Version,
+				Description:     revision.Description,
+				StoredChecksum:  stored,
+				CurrentChecksum: current,
+			})
+		}
+	}
+	return discrepancies, nil
+}
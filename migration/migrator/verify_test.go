@@ -0,0 +1,134 @@
+package migrator_test
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema"
+	"github.com/stokaro/ptah/migration/migrator"
+)
+
+func verifyMigrationsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"000001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")},
+		"000001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"000002_create_posts.up.sql":   {Data: []byte("CREATE TABLE posts (id INTEGER PRIMARY KEY);")},
+		"000002_create_posts.down.sql": {Data: []byte("DROP TABLE posts;")},
+		"000003_create_tags.up.sql":    {Data: []byte("CREATE TABLE tags (id INTEGER PRIMARY KEY);")},
+		"000003_create_tags.down.sql":  {Data: []byte("DROP TABLE tags;")},
+	}
+}
+
+func TestMigratorVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(fstest.MapFS)
+		want    []migrator.DiscrepancyKind
+		version []int64
+	}{
+		{
+			name: "consistent history",
+			edit: func(fstest.MapFS) {},
+		},
+		{
+			name: "applied migration file deleted",
+			edit: func(fsys fstest.MapFS) {
+				delete(fsys, "000002_create_posts.up.sql")
+				delete(fsys, "000002_create_posts.down.sql")
+			},
+			want:    []migrator.DiscrepancyKind{migrator.DiscrepancyMissingMigration},
+			version: []int64{2},
+		},
+		{
+			name: "applied migration edited",
+			edit: func(fsys fstest.MapFS) {
+				fsys["000003_create_tags.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE tags (id INTEGER PRIMARY KEY, name TEXT);")}
+			},
+			want:    []migrator.DiscrepancyKind{migrator.DiscrepancyChecksumMismatch},
+			version: []int64{3},
+		},
+		{
+			name: "both",
+			edit: func(fsys fstest.MapFS) {
+				delete(fsys, "000001_create_users.up.sql")
+				delete(fsys, "000001_create_users.down.sql")
+				fsys["000003_create_tags.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE tags (id INTEGER);")}
+			},
+			want:    []migrator.DiscrepancyKind{migrator.DiscrepancyMissingMigration, migrator.DiscrepancyChecksumMismatch},
+			version: []int64{1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+			conn := openVerifyTestDB(c)
+
+			applied, err := migrator.NewFSMigrator(conn, verifyMigrationsFS())
+			c.Assert(err, qt.IsNil)
+			c.Assert(applied.MigrateUp(ctx), qt.IsNil)
+
+			fsys := verifyMigrationsFS()
+			tt.edit(fsys)
+			m, err := migrator.NewFSMigrator(conn, fsys)
+			c.Assert(err, qt.IsNil)
+
+			discrepancies, err := m.Verify(ctx)
+			c.Assert(err, qt.IsNil)
+			var kinds []migrator.DiscrepancyKind
+			var versions []int64
+			for _, discrepancy := range discrepancies {
+				kinds = append(kinds, discrepancy.Kind)
+				versions = append(versions, discrepancy.Version)
+			}
+			c.Assert(kinds, qt.DeepEquals, tt.want)
+			c.Assert(versions, qt.DeepEquals, tt.version)
+		})
+	}
+}
+
+func TestDiscrepancyString(t *testing.T) {
+	tests := []struct {
+		name        string
+		discrepancy migrator.Discrepancy
+		want        string
+	}{
+		{
+			name:        "missing migration",
+			discrepancy: migrator.Discrepancy{Kind: migrator.DiscrepancyMissingMigration, Version: 2, Description: "create_posts"},
+			want:        "migration 2 (create_posts) is applied but has no migration file; it cannot be rolled back",
+		},
+		{
+			name: "checksum mismatch",
+			discrepancy: migrator.Discrepancy{
+				Kind: migrator.DiscrepancyChecksumMismatch, Version: 3, Description: "create_tags",
+				StoredChecksum: "aaa", CurrentChecksum: "bbb",
+			},
+			want: "migration 3 (create_tags) was edited after it was applied: stored checksum aaa, current bbb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qt.New(t).Assert(tt.discrepancy.String(), qt.Equals, tt.want)
+		})
+	}
+}
+
+func openVerifyTestDB(c *qt.C) *dbschema.DatabaseConnection {
+	dbURL := (&url.URL{
+		Scheme: platform.SQLite,
+		Path:   filepath.Join(c.TempDir(), "ptah-verify.sqlite"),
+	}).String()
+	conn, err := dbschema.ConnectToDatabase(context.Background(), dbURL)
+	c.Assert(err, qt.IsNil)
+	c.Cleanup(func() { c.Check(conn.Close(), qt.IsNil) })
+	return conn
+}
@@ -0,0 +1,87 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func autoIncrementSource(tableAttrs string) string {
+	return `package models
+
+//migrator:schema:table name="orders"` + tableAttrs + `
+type Order struct {
+	//migrator:schema:field name="id" type="INT" primary="true" auto_increment="true"
+	ID int
+}
+`
+}
+
+func liveAutoIncrementOrders(counter int64) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+		Name:          "orders",
+		Type:          "BASE TABLE",
+		AutoIncrement: counter,
+		Columns: []dbtypes.DBColumn{{
+			Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true, IsAutoIncrement: true,
+		}},
+	}}}
+}
+
+func TestMySQLTableAutoIncrementIsRaised(t *testing.T) {
+	tests := []struct {
+		name       string
+		tableAttrs string
+	}{
+		{name: "table attribute", tableAttrs: ` auto_increment="1000"`},
+		{name: "platform override", tableAttrs: ` auto_increment="10" platform.mysql.auto_increment="1000"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", autoIncrementSource(tt.tableAttrs))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveAutoIncrementOrders(42), platform.MySQL)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.MySQL)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(diff.TablesModified, qt.HasLen, 1)
+			c.Assert(diff.TablesModified[0].AutoIncrement, qt.Equals, "42 -> 1000")
+			c.Assert(sql, qt.Contains, "ALTER TABLE `orders` AUTO_INCREMENT = 1000;")
+		})
+	}
+}
+
+func TestTableAutoIncrementIsNotDrift(t *testing.T) {
+	tests := []struct {
+		name       string
+		tableAttrs string
+		counter    int64
+		dialect    string
+	}{
+		{name: "not annotated", counter: 5000, dialect: platform.MySQL},
+		{name: "counter at the start value", tableAttrs: ` auto_increment="1000"`, counter: 1000, dialect: platform.MySQL},
+		{name: "counter past the start value is never lowered", tableAttrs: ` auto_increment="1000"`, counter: 5000, dialect: platform.MariaDB},
+		{name: "other dialects ignore it", tableAttrs: ` auto_increment="1000"`, counter: 42, dialect: platform.Postgres},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", autoIncrementSource(tt.tableAttrs))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveAutoIncrementOrders(tt.counter), tt.dialect)
+
+			c.Assert(diff.TablesModified, qt.HasLen, 0)
+		})
+	}
+}
@@ -119,3 +119,19 @@ func TestGetPlanner_DistributedSQLCapabilityWiring(t *testing.T) {
 	c.Assert(sql, qt.Not(qt.Contains), "CONCURRENTLY",
 		qt.Commentf("CockroachDB must stay on plain CREATE INDEX; got:\n%s", sql))
 }
+
+// TestGetPlanner_MariaDBFieldTypes proves the mariadb factory plans fields for
+// MariaDB rather than MySQL: JSONB becomes the LONGTEXT JSON alias instead of
+// passing through as a type neither server knows.
+func TestGetPlanner_MariaDBFieldTypes(t *testing.T) {
+	c := qt.New(t)
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Event", Name: "events"}},
+		Fields: []goschema.Field{{StructName: "Event", Name: "payload", Type: "JSONB", Nullable: true}},
+	}
+
+	sql, err := planner.GenerateSchemaDiffSQL(&types.SchemaDiff{TablesAdded: []string{"events"}}, generated, platform.MariaDB)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, "`payload` longtext CHARACTER SET utf8mb4 COLLATE utf8mb4_bin CHECK (json_valid(`payload`))")
+}
@@ -0,0 +1,129 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const commentedSource = `package models
+
+//migrator:schema:table name="docs" comment="Published documents"
+type Doc struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="title" type="TEXT" not_null="true" comment="Doc's title"
+	Title string
+}
+`
+
+const uncommentedTableSource = `package models
+
+//migrator:schema:table name="docs"
+type Doc struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="title" type="TEXT" not_null="true" comment="Display title"
+	Title string
+
+	//migrator:schema:field name="slug" type="TEXT" comment="URL path segment"
+	Slug string
+}
+`
+
+func liveDocsTable(tableComment, titleComment string) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+		Name:    "docs",
+		Type:    "BASE TABLE",
+		Comment: tableComment,
+		Columns: []dbtypes.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "title", DataType: "text", UDTName: "text", IsNullable: "NO", Comment: titleComment},
+		},
+	}}}
+}
+
+func TestPostgresCommentStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		live    *dbtypes.DBSchema
+		dialect string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "created table comments follow CREATE TABLE",
+			source:  commentedSource,
+			live:    &dbtypes.DBSchema{},
+			dialect: platform.Postgres,
+			want: []string{
+				`CREATE TABLE "docs"`,
+				`COMMENT ON TABLE "docs" IS 'Published documents';`,
+				`COMMENT ON COLUMN "docs"."title" IS 'Doc''s title';`,
+			},
+		},
+		{
+			name:    "comment changes do not alter the column",
+			source:  uncommentedTableSource,
+			live:    liveDocsTable("Old table comment", "Old title"),
+			dialect: platform.Postgres,
+			want: []string{
+				`ALTER TABLE "docs" ADD COLUMN "slug"`,
+				`COMMENT ON COLUMN "docs"."slug" IS 'URL path segment';`,
+				`COMMENT ON COLUMN "docs"."title" IS 'Display title';`,
+				`COMMENT ON TABLE "docs" IS NULL;`,
+			},
+			notWant: []string{`ALTER COLUMN "title"`},
+		},
+		{
+			name:    "mysql emits no COMMENT ON statements",
+			source:  commentedSource,
+			live:    &dbtypes.DBSchema{},
+			dialect: platform.MySQL,
+			want:    []string{"CREATE TABLE `docs`"},
+			notWant: []string{`COMMENT ON`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tt.source)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			position := -1
+			for _, want := range tt.want {
+				next := strings.Index(sql, want)
+				c.Assert(next > position, qt.IsTrue, qt.Commentf("%q out of order or missing in:\n%s", want, sql))
+				position = next
+			}
+			for _, notWant := range tt.notWant {
+				c.Assert(sql, qt.Not(qt.Contains), notWant)
+			}
+		})
+	}
+}
+
+func TestPostgresMatchingCommentsAreNotDrift(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", commentedSource)
+	c.Assert(err, qt.IsNil)
+
+	diff := schemadiff.CompareWithDialect(&generated, liveDocsTable("Published documents", "Doc's title"), platform.Postgres)
+
+	c.Assert(diff.HasChanges(), qt.IsFalse)
+}
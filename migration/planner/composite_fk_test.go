@@ -0,0 +1,160 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbschematypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const compositeFKSource = `package models
+
+//migrator:schema:table name="users"
+//migrator:schema:constraint name="users_pk" type="PRIMARY KEY" columns="tenant_id,id"
+type User struct {
+	//migrator:schema:field name="tenant_id" type="INTEGER" not_null="true"
+	TenantID int
+	//migrator:schema:field name="id" type="INTEGER" not_null="true"
+	ID int
+}
+
+//migrator:schema:table name="orders"
+//migrator:schema:constraint name="fk_orders_user" type="FOREIGN KEY" columns="tenant_id,user_id" foreign_table="users" foreign_columns="tenant_id,id" on_delete="CASCADE"
+type Order struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+	//migrator:schema:field name="tenant_id" type="INTEGER" not_null="true"
+	TenantID int
+	//migrator:schema:field name="user_id" type="INTEGER" not_null="true"
+	UserID int
+}
+`
+
+// compositeFKDatabase returns the live schema for compositeFKSource with the
+// foreign key referencing foreignColumns.
+func compositeFKDatabase(foreignColumns ...string) *dbschematypes.DBSchema {
+	foreignTable := "users"
+	deleteRule := "CASCADE"
+	integer := func(name string) dbschematypes.DBColumn {
+		return dbschematypes.DBColumn{Name: name, DataType: "integer", IsNullable: "NO"}
+	}
+	return &dbschematypes.DBSchema{
+		Tables: []dbschematypes.DBTable{
+			{Name: "users", Columns: []dbschematypes.DBColumn{integer("tenant_id"), integer("id")}},
+			{Name: "orders", Columns: []dbschematypes.DBColumn{
+				{Name: "id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+				integer("tenant_id"),
+				integer("user_id"),
+			}},
+		},
+		Constraints: []dbschematypes.DBConstraint{
+			{Name: "users_pk", TableName: "users", Type: "PRIMARY KEY", ColumnNames: []string{"tenant_id", "id"}},
+			{
+				Name:           "fk_orders_user",
+				TableName:      "orders",
+				Type:           "FOREIGN KEY",
+				ColumnNames:    []string{"tenant_id", "user_id"},
+				ForeignTable:   &foreignTable,
+				ForeignColumns: foreignColumns,
+				DeleteRule:     &deleteRule,
+			},
+		},
+	}
+}
+
+func TestCompositeForeignKeyRoundTripPreservesColumnOrder(t *testing.T) {
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{
+			dialect: platform.Postgres,
+			want:    `ALTER TABLE "orders" ADD CONSTRAINT "fk_orders_user" FOREIGN KEY ("tenant_id", "user_id") REFERENCES "users"("tenant_id", "id") ON DELETE CASCADE;`,
+		},
+		{
+			dialect: platform.MySQL,
+			want:    "ALTER TABLE `orders` ADD CONSTRAINT `fk_orders_user` FOREIGN KEY (`tenant_id`, `user_id`) REFERENCES `users`(`tenant_id`, `id`) ON DELETE CASCADE;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", compositeFKSource)
+			c.Assert(err, qt.IsNil)
+
+			created, err := planner.GenerateSchemaDiffSQL(schemadiff.Compare(&generated, &dbschematypes.DBSchema{}), &generated, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			c.Assert(created, qt.Contains, tt.want)
+
+			unchanged := schemadiff.Compare(&generated, compositeFKDatabase("tenant_id", "id"))
+			c.Assert(unchanged.ConstraintsAdded, qt.HasLen, 0)
+			c.Assert(unchanged.ConstraintsRemoved, qt.HasLen, 0)
+
+			// Reordering the referenced columns changes the key, so the
+			// constraint is recreated with the declared order.
+			reordered := schemadiff.Compare(&generated, compositeFKDatabase("id", "tenant_id"))
+			c.Assert(reordered.ConstraintsRemoved, qt.DeepEquals, []string{"fk_orders_user"})
+			c.Assert(reordered.ConstraintsAdded, qt.DeepEquals, []string{"fk_orders_user"})
+			recreated, err := planner.GenerateSchemaDiffSQL(reordered, &generated, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			c.Assert(recreated, qt.Contains, tt.want)
+		})
+	}
+}
+
+const selfReferencingCompositeFKSource = `package models
+
+//migrator:schema:table name="nodes"
+//migrator:schema:constraint name="nodes_pk" type="PRIMARY KEY" columns="tenant_id,id"
+//migrator:schema:constraint name="fk_nodes_parent" type="FOREIGN KEY" columns="tenant_id,parent_id" foreign_table="nodes" foreign_columns="tenant_id,id"
+type Node struct {
+	//migrator:schema:field name="tenant_id" type="INTEGER" not_null="true"
+	TenantID int
+	//migrator:schema:field name="id" type="INTEGER" not_null="true"
+	ID int
+	//migrator:schema:field name="parent_id" type="INTEGER"
+	ParentID int
+}
+`
+
+func TestSelfReferencingCompositeForeignKeyIsEmittedOnce(t *testing.T) {
+	tests := []struct {
+		dialect string
+		want    string
+	}{
+		{
+			dialect: platform.Postgres,
+			want:    `CONSTRAINT "fk_nodes_parent" FOREIGN KEY ("tenant_id", "parent_id") REFERENCES "nodes"("tenant_id", "id")`,
+		},
+		{
+			dialect: platform.MySQL,
+			want:    "CONSTRAINT `fk_nodes_parent` FOREIGN KEY (`tenant_id`, `parent_id`) REFERENCES `nodes`(`tenant_id`, `id`)",
+		},
+		{
+			dialect: platform.SQLite,
+			want:    `CONSTRAINT "fk_nodes_parent" FOREIGN KEY ("tenant_id", "parent_id") REFERENCES "nodes" ("tenant_id", "id")`,
+		},
+		{
+			dialect: platform.SQLServer,
+			want:    "CONSTRAINT [fk_nodes_parent] FOREIGN KEY ([tenant_id], [parent_id]) REFERENCES [nodes] ([tenant_id], [id])",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", selfReferencingCompositeFKSource)
+			c.Assert(err, qt.IsNil)
+
+			created, err := planner.GenerateSchemaDiffSQL(schemadiff.Compare(&generated, &dbschematypes.DBSchema{}), &generated, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			c.Assert(created, qt.Contains, tt.want)
+			c.Assert(strings.Count(created, "fk_nodes_parent"), qt.Equals, 1)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package planner
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// CustomStatementPhase places the statements of a CustomStatementGenerator
+// in a plan.
+type CustomStatementPhase string
+
+const (
+	// CustomStatementsFirst runs custom statements before every statement
+	// the dialect planner emits.
+	CustomStatementsFirst CustomStatementPhase = "first"
+	// CustomStatementsLast runs custom statements after every statement the
+	// dialect planner emits. It is the default.
+	CustomStatementsLast CustomStatementPhase = "last"
+)
+
+// CustomChange is one change reported by a config.CustomComparator, handed to
+// the statement generator registered under the same name.
+type CustomChange struct {
+	// Name is the comparator name.
+	Name string
+	// Dialect is the planning target.
+	Dialect string
+	// Table and Column identify the changed column.
+	Table  string
+	Column string
+	// Before and After are the values the comparator reported.
+	Before string
+	After  string
+}
+
+// CustomStatementFunc turns one custom change into SQL statements. It may
+// return no statements to leave the change out of the plan.
+type CustomStatementFunc func(change CustomChange) ([]string, error)
+
+// CustomStatementGenerator produces the SQL for changes recorded by the custom
+// comparator of the same name.
+type CustomStatementGenerator struct {
+	// Name matches the config.CustomComparator registration.
+	Name string
+	// Phase places the statements; empty means CustomStatementsLast.
+	Phase CustomStatementPhase
+	// Generate builds the statements for one change.
+	Generate CustomStatementFunc
+}
+
+// WithCustomStatementGenerator returns a copy of o with a statement generator
+// registered for the custom comparator called name. Registering a name again
+// replaces the earlier generator.
+func (o Options) WithCustomStatementGenerator(name string, phase CustomStatementPhase, fn CustomStatementFunc) Options {
+	generator := CustomStatementGenerator{Name: name, Phase: phase, Generate: fn}
+	o.CustomStatementGenerators = slices.DeleteFunc(slices.Clone(o.CustomStatementGenerators), func(existing CustomStatementGenerator) bool {
+		return existing.Name == name
+	})
+	o.CustomStatementGenerators = append(o.CustomStatementGenerators, generator)
+	return o
+}
+
+// splitCustomChanges returns diff without the custom comparator entries, so
+// dialect planners never see them, together with those entries in table,
+// column, and change-name order. diff itself is not modified.
+func splitCustomChanges(diff *types.SchemaDiff) (*types.SchemaDiff, []CustomChange) {
+	if diff == nil {
+		return nil, nil
+	}
+	var changes []CustomChange
+	var tables []types.TableDiff
+	stripped := false
+	for _, table := range diff.TablesModified {
+		var columns []types.ColumnDiff
+		for _, column := range table.ColumnsModified {
+			builtIn := make(map[string]string, len(column.Changes))
+			for _, key := range slices.Sorted(maps.Keys(column.Changes)) {
+				name, ok := strings.CutPrefix(key, config.CustomChangePrefix)
+				if !ok {
+					builtIn[key] = column.Changes[key]
+					continue
+				}
+				before, after, _ := strings.Cut(column.Changes[key], " -> ")
+				changes = append(changes, CustomChange{
+					Name:   name,
+					Table:  table.TableName,
+					Column: column.ColumnName,
+					Before: before,
+					After:  after,
+				})
+				stripped = true
+			}
+			if len(builtIn) > 0 {
+				column.Changes = builtIn
+				columns = append(columns, column)
+			}
+		}
+		table.ColumnsModified = columns
+		if table.HasChanges() {
+			tables = append(tables, table)
+		}
+	}
+	if !stripped {
+		return diff, nil
+	}
+	clone := *diff
+	clone.TablesModified = tables
+	return &clone, changes
+}
+
+// customStatementNodes builds the nodes for changes in the given phase. A
+// change without a registered generator becomes a comment, so it is visible
+// in the plan without guessing at SQL.
+func customStatementNodes(changes []CustomChange, dialect string, generators []CustomStatementGenerator, phase CustomStatementPhase) ([]ast.Node, error) {
+	var nodes []ast.Node
+	for _, change := range changes {
+		change.Dialect = dialect
+		generator, ok := customGeneratorFor(generators, change.Name)
+		if !ok {
+			if phase == CustomStatementsLast {
+				nodes = append(nodes, ast.NewComment(fmt.Sprintf("WARNING: no statement generator for custom change %s on %s.%s: %s -> %s",
+					change.Name, change.Table, change.Column, change.Before, change.After)))
+			}
+			continue
+		}
+		if customPhase(generator) != phase {
+			continue
+		}
+		statements, err := generator.Generate(change)
+		if err != nil {
+			return nil, fmt.Errorf("custom statement generator %s for %s.%s: %w", change.Name, change.Table, change.Column, err)
+		}
+		for _, statement := range statements {
+			nodes = append(nodes, ast.NewRawSQL(statement))
+		}
+	}
+	return nodes, nil
+}
+
+func customGeneratorFor(generators []CustomStatementGenerator, name string) (CustomStatementGenerator, bool) {
+	i := slices.IndexFunc(generators, func(generator CustomStatementGenerator) bool {
+		return generator.Name == name && generator.Generate != nil
+	})
+	if i < 0 {
+		return CustomStatementGenerator{}, false
+	}
+	return generators[i], true
+}
+
+func customPhase(generator CustomStatementGenerator) CustomStatementPhase {
+	if generator.Phase == "" {
+		return CustomStatementsLast
+	}
+	return generator.Phase
+}
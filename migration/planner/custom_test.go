@@ -0,0 +1,100 @@
+package planner_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func customChangeDiff() (*types.SchemaDiff, *goschema.Database) {
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{
+			{Name: "ssn", Type: "TEXT", StructName: "User"},
+			{Name: "email", Type: "VARCHAR(255)", StructName: "User"},
+		},
+	}
+	diff := &types.SchemaDiff{
+		TablesModified: []types.TableDiff{{
+			TableName: "users",
+			ColumnsModified: []types.ColumnDiff{
+				{ColumnName: "email", Changes: map[string]string{"type": "varchar(100) -> VARCHAR(255)"}},
+				{ColumnName: "ssn", Changes: map[string]string{"custom:encrypt": "none -> pgp"}},
+			},
+		}},
+	}
+	return diff, generated
+}
+
+func TestCustomStatementGeneratorPhases(t *testing.T) {
+	const custom = "UPDATE users SET ssn = pgp_sym_encrypt(ssn, 'key')"
+	tests := []struct {
+		name  string
+		phase planner.CustomStatementPhase
+		// pick selects the statement that must hold the custom SQL.
+		pick func([]string) string
+	}{
+		{name: "default is last", phase: "", pick: lastStatement},
+		{name: "last", phase: planner.CustomStatementsLast, pick: lastStatement},
+		{name: "first", phase: planner.CustomStatementsFirst, pick: firstStatement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			diff, generated := customChangeDiff()
+			var got planner.CustomChange
+			opts := planner.Options{}.WithCustomStatementGenerator("encrypt", tt.phase, func(change planner.CustomChange) ([]string, error) {
+				got = change
+				return []string{custom}, nil
+			})
+
+			statements, err := planner.GenerateSchemaDiffSQLStatementsWithOptions(diff, generated, platform.Postgres, opts)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(strings.Join(statements, "\n"), qt.Contains, `ALTER COLUMN "email" TYPE VARCHAR(255)`)
+			c.Assert(strings.Join(statements, "\n"), qt.Not(qt.Contains), `ALTER COLUMN "ssn"`)
+			c.Assert(strings.HasSuffix(tt.pick(statements), custom), qt.IsTrue)
+			c.Assert(got, qt.DeepEquals, planner.CustomChange{
+				Name: "encrypt", Dialect: platform.Postgres, Table: "users", Column: "ssn", Before: "none", After: "pgp",
+			})
+			c.Assert(diff.TablesModified[0].ColumnsModified[1].Changes, qt.HasLen, 1)
+		})
+	}
+}
+
+func firstStatement(statements []string) string { return statements[0] }
+
+func lastStatement(statements []string) string { return statements[len(statements)-1] }
+
+func TestCustomChangeWithoutGeneratorIsAComment(t *testing.T) {
+	c := qt.New(t)
+	diff, generated := customChangeDiff()
+
+	sql, err := planner.GenerateSchemaDiffSQL(diff, generated, platform.Postgres)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, "-- WARNING: no statement generator for custom change encrypt on users.ssn: none -> pgp")
+	c.Assert(sql, qt.Not(qt.Contains), `ALTER COLUMN "ssn"`)
+}
+
+func TestCustomStatementGeneratorErrorFailsPlanning(t *testing.T) {
+	c := qt.New(t)
+	diff, generated := customChangeDiff()
+	failure := errors.New("no key configured")
+	opts := planner.Options{}.WithCustomStatementGenerator("encrypt", "", func(planner.CustomChange) ([]string, error) {
+		return nil, failure
+	})
+
+	_, err := planner.GenerateSchemaDiffASTWithOptions(diff, generated, platform.Postgres, opts)
+
+	c.Assert(err, qt.ErrorIs, failure)
+	c.Assert(err, qt.ErrorMatches, ".*custom statement generator encrypt for users.ssn: no key configured")
+}
@@ -0,0 +1,70 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const dialectDefaultSource = `package models
+
+//migrator:schema:table name="sessions"
+type Session struct {
+	//migrator:schema:field name="id" type="VARCHAR(36)" primary="true" default_expr="gen_random_uuid()" platform.mysql.default_expr="UUID()"
+	ID string
+}
+`
+
+func TestDialectScopedDefaultExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		want    string
+		notWant string
+	}{
+		{name: "mysql uses its override", dialect: platform.MySQL, want: "DEFAULT UUID()", notWant: "gen_random_uuid"},
+		{name: "postgres uses the generic default", dialect: platform.Postgres, want: "DEFAULT gen_random_uuid()", notWant: "UUID()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", dialectDefaultSource)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, &dbtypes.DBSchema{}, tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Contains, tt.want)
+			c.Assert(sql, qt.Not(qt.Contains), tt.notWant)
+		})
+	}
+}
+
+func TestDialectScopedDefaultIsNotDrift(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", dialectDefaultSource)
+	c.Assert(err, qt.IsNil)
+	live := &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+		Name: "sessions",
+		Columns: []dbtypes.DBColumn{{
+			Name:          "id",
+			DataType:      "varchar",
+			ColumnType:    "varchar(36)",
+			IsNullable:    "NO",
+			IsPrimaryKey:  true,
+			ColumnDefault: new("uuid()"),
+		}},
+	}}}
+
+	diff := schemadiff.CompareWithDialect(&generated, live, platform.MySQL)
+
+	c.Assert(diff.HasChanges(), qt.IsFalse)
+}
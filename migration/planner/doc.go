@@ -36,6 +36,9 @@
 //   - GenerateSchemaDiffAST(): Generates AST nodes from schema differences
 //   - GenerateSchemaDiffSQL(): Generates complete SQL string from schema differences
 //   - GenerateSchemaDiffSQLStatements(): Generates individual SQL statements as string slice
+//   - GenerateSchemaDiffOperations(): Generates PlannedOperation values for Render
+//   - Render(): Renders planned operations as SQL, a JSON array, or a
+//     terminal plan ("+ CREATE TABLE posts", "- DROP INDEX idx_old")
 //   - GetPlanner(): Registry-backed function to get dialect-specific planners
 //   - Register(): Extension point for third-party planner dialects
 //
@@ -0,0 +1,149 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func TestGeneratedColumnVirtualityChangeRecreatesColumn(t *testing.T) {
+	tests := []struct {
+		name       string
+		dialect    string
+		genKind    string
+		genExpr    string
+		dbKind     string
+		wantSQL    []string
+		notWantSQL string
+	}{
+		{
+			name:    "mysql virtual to stored",
+			dialect: platform.MySQL,
+			genKind: "STORED",
+			genExpr: "price * 2",
+			dbKind:  "VIRTUAL",
+			wantSQL: []string{
+				"ALTER TABLE `orders` DROP COLUMN `total`;",
+				"ALTER TABLE `orders` ADD COLUMN `total` INT GENERATED ALWAYS AS (price * 2) STORED;",
+			},
+			notWantSQL: "MODIFY COLUMN",
+		},
+		{
+			name:    "mariadb stored to virtual",
+			dialect: platform.MariaDB,
+			genKind: "VIRTUAL",
+			genExpr: "price * 2",
+			dbKind:  "STORED",
+			wantSQL: []string{
+				"ALTER TABLE `orders` DROP COLUMN `total`;",
+				"ALTER TABLE `orders` ADD COLUMN `total` INT GENERATED ALWAYS AS (price * 2) VIRTUAL;",
+			},
+			notWantSQL: "MODIFY COLUMN",
+		},
+		{
+			name:    "expression change keeps modify column",
+			dialect: platform.MySQL,
+			genKind: "STORED",
+			genExpr: "price * 3",
+			dbKind:  "STORED",
+			wantSQL: []string{
+				"ALTER TABLE `orders` MODIFY COLUMN `total` INT GENERATED ALWAYS AS (price * 3) STORED;",
+			},
+			notWantSQL: "DROP COLUMN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated := &goschema.Database{
+				Tables: []goschema.Table{{Name: "orders", StructName: "Order"}},
+				Fields: []goschema.Field{
+					{Name: "id", Type: "INT", Primary: true, StructName: "Order"},
+					{Name: "price", Type: "INT", StructName: "Order"},
+					{Name: "total", Type: "INT", Nullable: true, StructName: "Order", GeneratedExpression: tt.genExpr, GeneratedKind: tt.genKind},
+				},
+			}
+			dbExpr := "(`price` * 2)"
+			database := &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+				Name: "orders",
+				Columns: []dbtypes.DBColumn{
+					{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+					{Name: "price", DataType: "int", ColumnType: "int", IsNullable: "NO"},
+					{Name: "total", DataType: "int", ColumnType: "int", IsNullable: "YES", GeneratedKind: tt.dbKind, GeneratedExpression: &dbExpr},
+				},
+			}}}
+
+			diff := schemadiff.CompareWithDialect(generated, database, tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, generated, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			for _, want := range tt.wantSQL {
+				c.Assert(sql, qt.Contains, want)
+			}
+			c.Assert(sql, qt.Not(qt.Contains), tt.notWantSQL)
+		})
+	}
+}
+
+func TestGeneratedColumnVirtualityChangeRecreatesIndexes(t *testing.T) {
+	c := qt.New(t)
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "orders", StructName: "Order"}},
+		Fields: []goschema.Field{
+			{Name: "id", Type: "INT", Primary: true, StructName: "Order"},
+			{Name: "price", Type: "INT", StructName: "Order"},
+			{Name: "total", Type: "INT", Nullable: true, StructName: "Order", GeneratedExpression: "price * 2", GeneratedKind: "STORED"},
+		},
+		Indexes: []goschema.Index{
+			{Name: "idx_orders_total", StructName: "Order", Fields: []string{"total"}},
+			{Name: "idx_orders_price_total", StructName: "Order", Fields: []string{"price", "total"}},
+			{Name: "idx_orders_price", StructName: "Order", Fields: []string{"price"}},
+		},
+	}
+	dbExpr := "(`price` * 2)"
+	database := &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name: "orders",
+			Columns: []dbtypes.DBColumn{
+				{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "price", DataType: "int", ColumnType: "int", IsNullable: "NO"},
+				{Name: "total", DataType: "int", ColumnType: "int", IsNullable: "YES", GeneratedKind: "VIRTUAL", GeneratedExpression: &dbExpr},
+			},
+		}},
+		Indexes: []dbtypes.DBIndex{
+			{Name: "idx_orders_total", TableName: "orders", Columns: []string{"total"}},
+			{Name: "idx_orders_price_total", TableName: "orders", Columns: []string{"price", "total"}},
+			{Name: "idx_orders_price", TableName: "orders", Columns: []string{"price"}},
+		},
+	}
+
+	diff := schemadiff.CompareWithDialect(generated, database, platform.MySQL)
+	sql, err := planner.GenerateSchemaDiffSQL(diff, generated, platform.MySQL)
+	c.Assert(err, qt.IsNil)
+
+	// The composite index is dropped before DROP COLUMN so MySQL cannot
+	// narrow it to (price); both indexes are created again after ADD COLUMN.
+	order := []string{
+		"DROP INDEX `idx_orders_price_total` ON `orders`;",
+		"ALTER TABLE `orders` DROP COLUMN `total`;",
+		"ALTER TABLE `orders` ADD COLUMN `total` INT GENERATED ALWAYS AS (price * 2) STORED;",
+		"CREATE INDEX `idx_orders_total` ON `orders` (`total`);",
+		"CREATE INDEX `idx_orders_price_total` ON `orders` (`price`, `total`);",
+	}
+	last := -1
+	for _, want := range order {
+		pos := strings.Index(sql, want)
+		c.Assert(pos, qt.Not(qt.Equals), -1, qt.Commentf("missing %q in:\n%s", want, sql))
+		c.Assert(pos > last, qt.IsTrue, qt.Commentf("%q out of order in:\n%s", want, sql))
+		last = pos
+	}
+	c.Assert(sql, qt.Not(qt.Contains), "idx_orders_price`")
+	c.Assert(strings.Count(sql, "CREATE INDEX `idx_orders_total`"), qt.Equals, 1)
+}
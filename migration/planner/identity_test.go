@@ -0,0 +1,243 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func identitySource(generation string) string {
+	return `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="id" type="BIGINT" primary="true" identity_generation="` + generation + `"
+	ID int64
+}
+`
+}
+
+func liveOrdersTable(identity, columnDefault string) *dbtypes.DBSchema {
+	column := dbtypes.DBColumn{
+		Name:               "id",
+		DataType:           "bigint",
+		UDTName:            "int8",
+		IsNullable:         "NO",
+		IsPrimaryKey:       true,
+		IsAutoIncrement:    true,
+		IdentityGeneration: identity,
+	}
+	if columnDefault != "" {
+		column.ColumnDefault = &columnDefault
+	}
+	return &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{Name: "orders", Type: "BASE TABLE", Columns: []dbtypes.DBColumn{column}}}}
+}
+
+func TestPostgresIdentityColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		live    *dbtypes.DBSchema
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "create always",
+			source: identitySource("always"),
+			live:   &dbtypes.DBSchema{},
+			want:   []string{`"id" BIGINT PRIMARY KEY NOT NULL GENERATED ALWAYS AS IDENTITY`},
+		},
+		{
+			name:   "create by default",
+			source: identitySource("by default"),
+			live:   &dbtypes.DBSchema{},
+			want:   []string{`"id" BIGINT PRIMARY KEY NOT NULL GENERATED BY DEFAULT AS IDENTITY`},
+		},
+		{
+			name:    "switch by default to always",
+			source:  identitySource("always"),
+			live:    liveOrdersTable("BY_DEFAULT", ""),
+			want:    []string{`ALTER TABLE "orders" ALTER COLUMN "id" SET GENERATED ALWAYS;`},
+			notWant: []string{"TYPE BIGINT", "DROP DEFAULT"},
+		},
+		{
+			name:   "serial becomes identity",
+			source: identitySource("by default"),
+			live:   liveOrdersTable("", "nextval('orders_id_seq'::regclass)"),
+			want: []string{
+				`ALTER TABLE "orders" ALTER COLUMN "id" DROP DEFAULT;`,
+				`ALTER TABLE "orders" ALTER COLUMN "id" ADD GENERATED BY DEFAULT AS IDENTITY;`,
+				`RESTART WITH %s', '"orders"', '"id"', (SELECT COALESCE(MAX("id"), 0) + 1 FROM "orders"));`,
+			},
+			notWant: []string{"TYPE BIGINT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tt.source)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, platform.Postgres)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+			c.Assert(err, qt.IsNil)
+			position := -1
+			for _, want := range tt.want {
+				next := strings.Index(sql, want)
+				c.Assert(next > position, qt.IsTrue, qt.Commentf("%q out of order or missing in:\n%s", want, sql))
+				position = next
+			}
+			for _, notWant := range tt.notWant {
+				c.Assert(sql, qt.Not(qt.Contains), notWant)
+			}
+		})
+	}
+}
+
+func TestPostgresIdentityColumnIsNotDrift(t *testing.T) {
+	tests := []struct {
+		name       string
+		generation string
+		live       string
+	}{
+		{name: "always", generation: "always", live: "ALWAYS"},
+		{name: "by default", generation: "by default", live: "BY_DEFAULT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", identitySource(tt.generation))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveOrdersTable(tt.live, ""), platform.Postgres)
+
+			c.Assert(diff.HasChanges(), qt.IsFalse)
+		})
+	}
+}
+
+func identityOptionsSource(attributes string) string {
+	return `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="id" type="BIGINT" primary="true" identity_generation="always" ` + attributes + `
+	ID int64
+}
+`
+}
+
+func liveIdentityOrders(generation, start, increment string) *dbtypes.DBSchema {
+	live := liveOrdersTable(generation, "")
+	live.Tables[0].Columns[0].IdentityStart = start
+	live.Tables[0].Columns[0].IdentityIncrement = increment
+	return live
+}
+
+func TestPostgresIdentitySequenceOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		live    *dbtypes.DBSchema
+		changes map[string]string
+		want    string
+	}{
+		{
+			name:    "increment changed",
+			source:  identityOptionsSource(`identity_increment="5"`),
+			live:    liveIdentityOrders("ALWAYS", "1", "1"),
+			changes: map[string]string{"identity_increment": "1 -> 5"},
+			want:    `ALTER TABLE "orders" ALTER COLUMN "id" SET INCREMENT BY 5;`,
+		},
+		{
+			name:    "start and increment changed",
+			source:  identityOptionsSource(`identity_start="1000" identity_increment="5"`),
+			live:    liveIdentityOrders("ALWAYS", "1", "1"),
+			changes: map[string]string{"identity_start": "1 -> 1000", "identity_increment": "1 -> 5"},
+			want:    `ALTER TABLE "orders" ALTER COLUMN "id" SET START WITH 1000 SET INCREMENT BY 5;`,
+		},
+		{
+			name:    "raw identity options",
+			source:  identityOptionsSource(`identity_options="START WITH 1000 INCREMENT BY 5"`),
+			live:    liveIdentityOrders("ALWAYS", "1000", "1"),
+			changes: map[string]string{"identity_increment": "1 -> 5"},
+			want:    `ALTER TABLE "orders" ALTER COLUMN "id" SET INCREMENT BY 5;`,
+		},
+		{
+			name:    "generation and increment changed",
+			source:  identityOptionsSource(`identity_increment="5"`),
+			live:    liveIdentityOrders("BY_DEFAULT", "1", "1"),
+			changes: map[string]string{"identity": "BY_DEFAULT -> ALWAYS", "identity_increment": "1 -> 5"},
+			want:    `ALTER TABLE "orders" ALTER COLUMN "id" SET GENERATED ALWAYS SET INCREMENT BY 5;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tt.source)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, platform.Postgres)
+			c.Assert(diff.TablesModified, qt.HasLen, 1)
+			c.Assert(diff.TablesModified[0].ColumnsModified, qt.HasLen, 1)
+			c.Assert(diff.TablesModified[0].ColumnsModified[0].Changes, qt.DeepEquals, tt.changes)
+
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Contains, tt.want)
+			c.Assert(sql, qt.Not(qt.Contains), "TYPE BIGINT")
+		})
+	}
+}
+
+func TestPostgresIdentitySequenceOptionsAreNotDrift(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		live   *dbtypes.DBSchema
+	}{
+		{
+			name:   "undeclared options ignore a non-default increment",
+			source: identitySource("always"),
+			live:   liveIdentityOrders("ALWAYS", "1", "5"),
+		},
+		{
+			name:   "declared options match",
+			source: identityOptionsSource(`identity_start="1000" identity_increment="5"`),
+			live:   liveIdentityOrders("ALWAYS", "1000", "5"),
+		},
+		{
+			name:   "raw options match",
+			source: identityOptionsSource(`identity_options="START 1000 INCREMENT 5 CACHE 10"`),
+			live:   liveIdentityOrders("ALWAYS", "1000", "5"),
+		},
+		{
+			name:   "live options not introspected",
+			source: identityOptionsSource(`identity_increment="5"`),
+			live:   liveIdentityOrders("ALWAYS", "", ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tt.source)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, platform.Postgres)
+
+			c.Assert(diff.HasChanges(), qt.IsFalse)
+		})
+	}
+}
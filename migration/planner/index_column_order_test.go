@@ -0,0 +1,145 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const usersWithoutNickname = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INT" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email"
+	Email string
+}
+`
+
+const usersWithNickname = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INT" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email"
+	Email string
+
+	//migrator:schema:field name="nickname" type="VARCHAR(64)"
+	//migrator:schema:index name="idx_users_nickname" fields="nickname"
+	Nickname string
+}
+`
+
+func liveUsers(columns []dbtypes.DBColumn, indexes ...dbtypes.DBIndex) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name: "users",
+			Type: "BASE TABLE",
+			Columns: append([]dbtypes.DBColumn{
+				{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", IsNullable: "YES"},
+			}, columns...),
+		}},
+		Indexes: indexes,
+	}
+}
+
+func TestRemovedColumnDropsItsIndexesFirst(t *testing.T) {
+	nickname := dbtypes.DBColumn{Name: "nickname", DataType: "varchar", ColumnType: "varchar(64)", IsNullable: "YES"}
+	tests := []struct {
+		name       string
+		dialect    string
+		dropIndex  string
+		dropColumn string
+	}{
+		{
+			name:       "mysql",
+			dialect:    platform.MySQL,
+			dropIndex:  "DROP INDEX `idx_users_nickname` ON `users`;",
+			dropColumn: "ALTER TABLE `users` DROP COLUMN `nickname`;",
+		},
+		{
+			name:       "mariadb",
+			dialect:    platform.MariaDB,
+			dropIndex:  "DROP INDEX IF EXISTS `idx_users_nickname` ON `users`;",
+			dropColumn: "ALTER TABLE `users` DROP COLUMN `nickname`;",
+		},
+		{
+			name:       "postgres",
+			dialect:    platform.Postgres,
+			dropIndex:  `DROP INDEX IF EXISTS "idx_users_nickname";`,
+			dropColumn: `ALTER TABLE "users" DROP COLUMN "nickname" CASCADE;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", usersWithoutNickname)
+			c.Assert(err, qt.IsNil)
+			live := liveUsers([]dbtypes.DBColumn{nickname},
+				dbtypes.DBIndex{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}},
+				dbtypes.DBIndex{Name: "idx_users_nickname", TableName: "users", Columns: []string{"nickname"}},
+			)
+
+			diff := schemadiff.CompareWithDialect(&generated, live, tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(strings.Count(sql, tt.dropIndex), qt.Equals, 1, qt.Commentf("%s", sql))
+			c.Assert(strings.Index(sql, tt.dropIndex) < strings.Index(sql, tt.dropColumn), qt.IsTrue, qt.Commentf("%s", sql))
+		})
+	}
+}
+
+func TestAddedColumnIsCreatedBeforeItsIndex(t *testing.T) {
+	tests := []struct {
+		name        string
+		dialect     string
+		addColumn   string
+		createIndex string
+	}{
+		{
+			name:        "mysql",
+			dialect:     platform.MySQL,
+			addColumn:   "ALTER TABLE `users` ADD COLUMN `nickname` VARCHAR(64);",
+			createIndex: "CREATE INDEX `idx_users_nickname` ON `users` (`nickname`);",
+		},
+		{
+			name:        "postgres",
+			dialect:     platform.Postgres,
+			addColumn:   `ALTER TABLE "users" ADD COLUMN "nickname" VARCHAR(64);`,
+			createIndex: `CREATE INDEX IF NOT EXISTS "idx_users_nickname" ON "users" ("nickname");`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", usersWithNickname)
+			c.Assert(err, qt.IsNil)
+			live := liveUsers(nil, dbtypes.DBIndex{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}})
+
+			diff := schemadiff.CompareWithDialect(&generated, live, tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Contains, tt.addColumn)
+			c.Assert(sql, qt.Contains, tt.createIndex)
+			c.Assert(strings.Index(sql, tt.addColumn) < strings.Index(sql, tt.createIndex), qt.IsTrue, qt.Commentf("%s", sql))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestGenerateSchemaDiffSQL_SkipsIndexDropsOnRemovedTables(t *testing.T) {
+	tests := []struct {
+		dialect string
+		kept    string
+	}{
+		{dialect: platform.Postgres, kept: `DROP INDEX IF EXISTS "idx_users_email";`},
+		{dialect: platform.MySQL, kept: "DROP INDEX `idx_users_email` ON `users`;"},
+		{dialect: platform.MariaDB, kept: "DROP INDEX IF EXISTS `idx_users_email` ON `users`;"},
+		{dialect: platform.SQLite, kept: `DROP INDEX IF EXISTS "idx_users_email";`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			c := qt.New(t)
+			diff := &types.SchemaDiff{
+				TablesRemoved:  []string{"sessions"},
+				IndexesRemoved: []string{"idx_sessions_token", "idx_users_email"},
+				IndexesRemovedWithTables: []types.IndexRemovalInfo{
+					{Name: "idx_sessions_token", TableName: "sessions", Columns: []string{"token"}},
+					{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}},
+				},
+			}
+
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &goschema.Database{}, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Contains, tt.kept)
+			c.Assert(sql, qt.Not(qt.Contains), "idx_sessions_token")
+			c.Assert(sql, qt.Contains, "sessions")
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func indexOpClassSource(ops string) string {
+	attr := ""
+	if ops != "" {
+		attr = ` ops="` + ops + `"`
+	}
+	return `package models
+
+//migrator:schema:table name="products"
+type Product struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="tenant" type="TEXT" not_null="true"
+	Tenant string
+
+	//migrator:schema:field name="name" type="TEXT" not_null="true"
+	Name string
+
+	//migrator:schema:index name="idx_products_search" fields="tenant,name"` + attr + `
+	_ int
+}
+`
+}
+
+func liveProductsWithOpClasses(opClasses ...dbtypes.DBIndexOperatorClass) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name: "products",
+			Type: "BASE TABLE",
+			Columns: []dbtypes.DBColumn{
+				{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "tenant", DataType: "text", UDTName: "text", IsNullable: "NO"},
+				{Name: "name", DataType: "text", UDTName: "text", IsNullable: "NO"},
+			},
+		}},
+		Indexes: []dbtypes.DBIndex{{
+			Name: "idx_products_search", TableName: "products", Columns: []string{"tenant", "name"}, OperatorClasses: opClasses,
+		}},
+	}
+}
+
+var (
+	textOps        = dbtypes.DBIndexOperatorClass{Name: "text_ops", Default: true}
+	textPatternOps = dbtypes.DBIndexOperatorClass{Name: "text_pattern_ops"}
+)
+
+func TestPostgresIndexOperatorClasses(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		live   *dbtypes.DBSchema
+		want   []string
+	}{
+		{
+			name:   "create renders a class after each column",
+			source: indexOpClassSource(",text_pattern_ops"),
+			live:   &dbtypes.DBSchema{},
+			want:   []string{`"idx_products_search" ON "products" ("tenant", "name" text_pattern_ops);`},
+		},
+		{
+			name:   "changed class recreates the index",
+			source: indexOpClassSource(",text_pattern_ops"),
+			live:   liveProductsWithOpClasses(textOps, textOps),
+			want: []string{
+				`DROP INDEX IF EXISTS "idx_products_search";`,
+				`"idx_products_search" ON "products" ("tenant", "name" text_pattern_ops);`,
+			},
+		},
+		{
+			name:   "dropped class recreates the index with the default",
+			source: indexOpClassSource(""),
+			live:   liveProductsWithOpClasses(textOps, textPatternOps),
+			want: []string{
+				`DROP INDEX IF EXISTS "idx_products_search";`,
+				`"idx_products_search" ON "products" ("tenant", "name");`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tt.source)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, platform.Postgres)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+			c.Assert(err, qt.IsNil)
+			position := -1
+			for _, want := range tt.want {
+				next := strings.Index(sql, want)
+				c.Assert(next > position, qt.IsTrue, qt.Commentf("%q out of order or missing in:\n%s", want, sql))
+				position = next
+			}
+		})
+	}
+}
+
+func TestPostgresIndexOperatorClassesAreNotDrift(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  string
+		live *dbtypes.DBSchema
+	}{
+		{name: "default classes", live: liveProductsWithOpClasses(textOps, textOps)},
+		{name: "explicit default class", ops: "text_ops,text_ops", live: liveProductsWithOpClasses(textOps, textOps)},
+		{name: "matching class", ops: ",TEXT_PATTERN_OPS", live: liveProductsWithOpClasses(textOps, textPatternOps)},
+		{name: "schema-qualified class", ops: ",pg_catalog.text_pattern_ops", live: liveProductsWithOpClasses(textOps, textPatternOps)},
+		{name: "reader without classes", ops: ",text_pattern_ops", live: liveProductsWithOpClasses()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", indexOpClassSource(tt.ops))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, platform.Postgres)
+
+			c.Assert(diff.HasChanges(), qt.IsFalse)
+		})
+	}
+}
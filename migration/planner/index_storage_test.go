@@ -0,0 +1,131 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func indexStorageSource(storage string) string {
+	attr := ""
+	if storage != "" {
+		attr = ` storage="` + storage + `"`
+	}
+	return `package models
+
+//migrator:schema:table name="orders"
+type Order struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="status" type="TEXT" not_null="true"
+	//migrator:schema:index name="idx_orders_status" fields="status"` + attr + `
+	Status string
+}
+`
+}
+
+func liveOrdersWithIndex(storageParams map[string]string) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name: "orders",
+			Type: "BASE TABLE",
+			Columns: []dbtypes.DBColumn{
+				{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "status", DataType: "text", UDTName: "text", IsNullable: "NO"},
+			},
+		}},
+		Indexes: []dbtypes.DBIndex{{
+			Name: "idx_orders_status", TableName: "orders", Columns: []string{"status"}, StorageParams: storageParams,
+		}},
+	}
+}
+
+func TestPostgresIndexStorageParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		live   *dbtypes.DBSchema
+		strict bool
+		want   []string
+	}{
+		{
+			name:   "create renders WITH",
+			source: indexStorageSource("fillfactor=70"),
+			live:   &dbtypes.DBSchema{},
+			want:   []string{`"idx_orders_status" ON "orders" ("status") WITH (fillfactor='70');`},
+		},
+		{
+			name:   "changed fillfactor recreates the index",
+			source: indexStorageSource("fillfactor=70"),
+			live:   liveOrdersWithIndex(map[string]string{"fillfactor": "90"}),
+			want: []string{
+				`DROP INDEX IF EXISTS "idx_orders_status";`,
+				`"idx_orders_status" ON "orders" ("status") WITH (fillfactor='70');`,
+			},
+		},
+		{
+			name:   "strict drops undeclared parameters",
+			source: indexStorageSource(""),
+			live:   liveOrdersWithIndex(map[string]string{"fillfactor": "70"}),
+			strict: true,
+			want: []string{
+				`DROP INDEX IF EXISTS "idx_orders_status";`,
+				`"idx_orders_status" ON "orders" ("status");`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tt.source)
+			c.Assert(err, qt.IsNil)
+			opts := config.DefaultCompareOptions()
+			opts.Dialect = platform.Postgres
+			opts.StrictIndexStorageParams = tt.strict
+
+			diff := schemadiff.CompareWithOptions(&generated, tt.live, opts)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+			c.Assert(err, qt.IsNil)
+			position := -1
+			for _, want := range tt.want {
+				next := strings.Index(sql, want)
+				c.Assert(next > position, qt.IsTrue, qt.Commentf("%q out of order or missing in:\n%s", want, sql))
+				position = next
+			}
+		})
+	}
+}
+
+func TestPostgresIndexStorageParamsAreNotDrift(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage string
+		live    map[string]string
+	}{
+		{name: "matching parameters", storage: "fillfactor=70, deduplicate_items=OFF", live: map[string]string{"fillfactor": "70", "deduplicate_items": "off"}},
+		{name: "undeclared parameters are preserved", live: map[string]string{"fillfactor": "70"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", indexStorageSource(tt.storage))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveOrdersWithIndex(tt.live), platform.Postgres)
+
+			c.Assert(diff.HasChanges(), qt.IsFalse)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func indexVisibilitySource(indexAttrs string) string {
+	return `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INT" primary="true"
+	ID int
+
+	//migrator:schema:field name="email" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_users_email" fields="email"` + indexAttrs + `
+	Email string
+}
+`
+}
+
+func liveIndexVisibilityUsers(indexes ...dbtypes.DBIndex) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name: "users",
+			Type: "BASE TABLE",
+			Columns: []dbtypes.DBColumn{
+				{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "email", DataType: "varchar", ColumnType: "varchar(255)", IsNullable: "YES"},
+			},
+		}},
+		Indexes: indexes,
+	}
+}
+
+func TestIndexVisibilityToggleAltersInPlace(t *testing.T) {
+	tests := []struct {
+		name       string
+		indexAttrs string
+		invisible  bool
+		dialect    string
+		want       string
+	}{
+		{
+			name:       "mysql hides an index",
+			indexAttrs: ` visible="false"`,
+			dialect:    platform.MySQL,
+			want:       "ALTER TABLE `users` ALTER INDEX `idx_users_email` INVISIBLE;",
+		},
+		{
+			name:      "mysql shows an index",
+			invisible: true,
+			dialect:   platform.MySQL,
+			want:      "ALTER TABLE `users` ALTER INDEX `idx_users_email` VISIBLE;",
+		},
+		{
+			name:       "mariadb ignores an index",
+			indexAttrs: ` visible="false"`,
+			dialect:    platform.MariaDB,
+			want:       "ALTER TABLE `users` ALTER INDEX `idx_users_email` IGNORED;",
+		},
+		{
+			name:      "mariadb stops ignoring an index",
+			invisible: true,
+			dialect:   platform.MariaDB,
+			want:      "ALTER TABLE `users` ALTER INDEX `idx_users_email` NOT IGNORED;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", indexVisibilitySource(tt.indexAttrs))
+			c.Assert(err, qt.IsNil)
+			live := liveIndexVisibilityUsers(dbtypes.DBIndex{
+				Name: "idx_users_email", TableName: "users", Columns: []string{"email"}, Invisible: tt.invisible,
+			})
+
+			diff := schemadiff.CompareWithDialect(&generated, live, tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(diff.IndexesAdded, qt.HasLen, 0)
+			c.Assert(diff.IndexesRemoved, qt.HasLen, 0)
+			c.Assert(diff.IndexesVisibilityChanged, qt.HasLen, 1)
+			c.Assert(sql, qt.Contains, tt.want)
+			c.Assert(sql, qt.Not(qt.Contains), "DROP INDEX")
+		})
+	}
+}
+
+func TestIndexVisibilityIsIgnoredOutsideMySQLFamily(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", indexVisibilitySource(` visible="false"`))
+	c.Assert(err, qt.IsNil)
+	live := liveIndexVisibilityUsers(dbtypes.DBIndex{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}})
+
+	diff := schemadiff.CompareWithDialect(&generated, live, platform.Postgres)
+
+	c.Assert(diff.HasChanges(), qt.IsFalse)
+}
+
+func TestNewInvisibleIndexRendering(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		want    string
+	}{
+		{name: "mysql", dialect: platform.MySQL, want: "CREATE INDEX `idx_users_email` ON `users` (`email`) INVISIBLE;"},
+		{name: "mariadb", dialect: platform.MariaDB, want: "CREATE INDEX `idx_users_email` ON `users` (`email`) IGNORED;"},
+		{name: "postgres warns and creates it visible", dialect: platform.Postgres, want: `-- POSTGRES: invisible index "idx_users_email" is MySQL-specific; created visible.`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", indexVisibilitySource(` visible="false"`))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveIndexVisibilityUsers(), tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, tt.dialect)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(diff.IndexesAdded, qt.DeepEquals, []string{"idx_users_email"})
+			c.Assert(sql, qt.Contains, tt.want)
+		})
+	}
+}
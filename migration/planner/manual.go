@@ -0,0 +1,52 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/internal/planner/dialects/postgres"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// ManualChange records one column change the planner cannot express as an
+// ALTER statement. The plan carries a MANUAL comment in its place, and the
+// change has to be applied by hand.
+type ManualChange struct {
+	// Table is the table holding the column.
+	Table string
+	// Column is the column name.
+	Column string
+	// Change describes what changed, e.g. "serial -> integer".
+	Change string
+}
+
+func (c ManualChange) String() string {
+	return fmt.Sprintf("%s.%s %s; ptah cannot alter this automatically", c.Table, c.Column, c.Change)
+}
+
+// ManualChanges lists the column changes in diff that planning for dialect
+// leaves to a MANUAL comment instead of SQL: switching a PostgreSQL column
+// between SERIAL and a plain integer, and generated-column changes the target
+// cannot apply with ALTER COLUMN SET EXPRESSION. Nil caps means the dialect's
+// default preset, matching the planner helpers. CI can fail when the result
+// is not empty.
+func ManualChanges(diff *types.SchemaDiff, dialect string, caps capability.Capabilities) []ManualChange {
+	if diff == nil || !platform.IsPostgresFamily(dialect) {
+		return nil
+	}
+	caps = Options{Capabilities: caps}.CapabilitiesFor(dialect)
+	var manual []ManualChange
+	for _, tableDiff := range diff.TablesModified {
+		for _, colDiff := range tableDiff.ColumnsModified {
+			for _, change := range postgres.ManualColumnChanges(colDiff, caps) {
+				manual = append(manual, ManualChange{
+					Table:  tableDiff.TableName,
+					Column: colDiff.ColumnName,
+					Change: change,
+				})
+			}
+		}
+	}
+	return manual
+}
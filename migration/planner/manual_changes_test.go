@@ -0,0 +1,154 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/platform/capability"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func manualChangeSource(field string) string {
+	return `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	` + field + `
+	ID int
+
+	//migrator:schema:field name="name" type="TEXT" not_null="true"
+	Name string
+}
+`
+}
+
+func liveManualChangeUsers(id dbtypes.DBColumn) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+		Name: "users",
+		Type: "BASE TABLE",
+		Columns: []dbtypes.DBColumn{
+			id,
+			{Name: "name", DataType: "text", UDTName: "text", IsNullable: "NO"},
+		},
+	}}}
+}
+
+func TestManualColumnChanges(t *testing.T) {
+	sequenceDefault := "nextval('users_id_seq'::regclass)"
+	upperName := "upper(name)"
+	tests := []struct {
+		name    string
+		field   string
+		liveID  dbtypes.DBColumn
+		caps    capability.Capabilities
+		want    []planner.ManualChange
+		wantSQL []string
+		notWant []string
+	}{
+		{
+			name:   "serial to integer",
+			field:  `//migrator:schema:field name="id" type="INTEGER" primary="true"`,
+			liveID: dbtypes.DBColumn{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true, IsAutoIncrement: true, ColumnDefault: &sequenceDefault},
+			want:   []planner.ManualChange{{Table: "users", Column: "id", Change: "serial -> integer"}},
+			wantSQL: []string{
+				"-- MANUAL: users.id serial -> integer; ptah cannot alter this automatically",
+			},
+			notWant: []string{"ALTER TABLE"},
+		},
+		{
+			name:   "integer to serial",
+			field:  `//migrator:schema:field name="id" type="SERIAL" primary="true"`,
+			liveID: dbtypes.DBColumn{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+			want:   []planner.ManualChange{{Table: "users", Column: "id", Change: "integer -> serial"}},
+			wantSQL: []string{
+				"-- MANUAL: users.id integer -> serial; ptah cannot alter this automatically",
+			},
+			notWant: []string{"ALTER TABLE"},
+		},
+		{
+			name:   "bigserial to bigint",
+			field:  `//migrator:schema:field name="id" type="BIGINT" primary="true"`,
+			liveID: dbtypes.DBColumn{Name: "id", DataType: "bigint", UDTName: "int8", IsNullable: "NO", IsPrimaryKey: true, IsAutoIncrement: true, ColumnDefault: &sequenceDefault},
+			want:   []planner.ManualChange{{Table: "users", Column: "id", Change: "bigserial -> bigint"}},
+		},
+		{
+			name:   "generated expression before postgres 17",
+			field:  `//migrator:schema:field name="id" type="TEXT" generated="lower(name)" generated_kind="STORED"`,
+			liveID: dbtypes.DBColumn{Name: "id", DataType: "text", UDTName: "text", IsNullable: "YES", GeneratedExpression: &upperName, GeneratedKind: "STORED"},
+			caps:   capability.Postgres16(),
+			want:   []planner.ManualChange{{Table: "users", Column: "id", Change: "generated expression (upper(name)) -> (lower(name)) needs PostgreSQL 17+"}},
+			wantSQL: []string{
+				"-- MANUAL: users.id generated expression (upper(name)) -> (lower(name)) needs PostgreSQL 17+; ptah cannot alter this automatically",
+			},
+			notWant: []string{"SET EXPRESSION"},
+		},
+		{
+			name:    "generated expression on postgres 17",
+			field:   `//migrator:schema:field name="id" type="TEXT" generated="lower(name)" generated_kind="STORED"`,
+			liveID:  dbtypes.DBColumn{Name: "id", DataType: "text", UDTName: "text", IsNullable: "YES", GeneratedExpression: &upperName, GeneratedKind: "STORED"},
+			caps:    capability.Postgres17(),
+			wantSQL: []string{`ALTER TABLE "users" ALTER COLUMN "id" SET EXPRESSION AS (lower(name));`},
+			notWant: []string{"MANUAL:"},
+		},
+		{
+			name:   "generated to plain",
+			field:  `//migrator:schema:field name="id" type="TEXT"`,
+			liveID: dbtypes.DBColumn{Name: "id", DataType: "text", UDTName: "text", IsNullable: "YES", GeneratedExpression: &upperName, GeneratedKind: "STORED"},
+			caps:   capability.Postgres17(),
+			want:   []planner.ManualChange{{Table: "users", Column: "id", Change: "generated STORED (upper(name)) -> plain"}},
+			wantSQL: []string{
+				"-- MANUAL: users.id generated STORED (upper(name)) -> plain; ptah cannot alter this automatically",
+			},
+			notWant: []string{"SET EXPRESSION"},
+		},
+		{
+			name:    "plain to generated",
+			field:   `//migrator:schema:field name="id" type="TEXT" generated="lower(name)" generated_kind="STORED"`,
+			liveID:  dbtypes.DBColumn{Name: "id", DataType: "text", UDTName: "text", IsNullable: "YES"},
+			caps:    capability.Postgres17(),
+			want:    []planner.ManualChange{{Table: "users", Column: "id", Change: "plain -> generated STORED (lower(name))"}},
+			notWant: []string{"SET EXPRESSION"},
+		},
+		{
+			name:   "serial column unchanged",
+			field:  `//migrator:schema:field name="id" type="SERIAL" primary="true"`,
+			liveID: dbtypes.DBColumn{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true, IsAutoIncrement: true, ColumnDefault: &sequenceDefault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", manualChangeSource(tt.field))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveManualChangeUsers(tt.liveID), platform.Postgres)
+			sql, err := planner.GenerateSchemaDiffSQLWithCapabilities(diff, &generated, platform.Postgres, tt.caps)
+			c.Assert(err, qt.IsNil)
+
+			c.Assert(planner.ManualChanges(diff, platform.Postgres, tt.caps), qt.DeepEquals, tt.want)
+			for _, want := range tt.wantSQL {
+				c.Assert(sql, qt.Contains, want)
+			}
+			for _, notWant := range tt.notWant {
+				c.Assert(sql, qt.Not(qt.Contains), notWant)
+			}
+		})
+	}
+}
+
+func TestManualChangesIgnoreOtherDialects(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", manualChangeSource(`//migrator:schema:field name="id" type="INT" primary="true" auto_increment="true"`))
+	c.Assert(err, qt.IsNil)
+
+	live := liveManualChangeUsers(dbtypes.DBColumn{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true, IsAutoIncrement: true})
+	diff := schemadiff.CompareWithDialect(&generated, live, platform.MySQL)
+
+	c.Assert(planner.ManualChanges(diff, platform.MySQL, nil), qt.HasLen, 0)
+}
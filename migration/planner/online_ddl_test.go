@@ -0,0 +1,105 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const onlineDDLSource = `package models
+
+//migrator:schema:table name="posts"
+type Post struct {
+	//migrator:schema:field name="id" type="INT" primary="true"
+	ID int
+
+	//migrator:schema:field name="title" type="VARCHAR(255)"
+	//migrator:schema:index name="idx_posts_title" fields="title"
+	Title string
+
+	//migrator:schema:field name="body" type="TEXT"
+	//migrator:schema:index name="idx_posts_body" fields="body" type="fulltext"
+	Body string
+
+	//migrator:schema:field name="title_length" type="INT" generated="CHAR_LENGTH(title)" generated_kind="STORED"
+	TitleLength int
+}
+`
+
+func livePostsWithID() *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+		Name: "posts",
+		Type: "BASE TABLE",
+		Columns: []dbtypes.DBColumn{
+			{Name: "id", DataType: "int", ColumnType: "int", IsNullable: "NO", IsPrimaryKey: true},
+		},
+	}}}
+}
+
+func TestMySQLOnlineDDLHints(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		online  bool
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "mysql marks eligible operations",
+			dialect: platform.MySQL,
+			online:  true,
+			want: []string{
+				"ALTER TABLE `posts` ADD COLUMN `title` VARCHAR(255), ALGORITHM=INPLACE, LOCK=NONE;",
+				"ALTER TABLE `posts` ADD COLUMN `body` TEXT, ALGORITHM=INPLACE, LOCK=NONE;",
+				"CREATE INDEX `idx_posts_title` ON `posts` (`title`) ALGORITHM=INPLACE LOCK=NONE;",
+				"CREATE FULLTEXT INDEX `idx_posts_body` ON `posts` (`body`);",
+			},
+			notWant: []string{"STORED, ALGORITHM", "(`body`) ALGORITHM"},
+		},
+		{
+			name:    "mariadb uses the same hints",
+			dialect: platform.MariaDB,
+			online:  true,
+			want: []string{
+				"ALTER TABLE `posts` ADD COLUMN `title` VARCHAR(255), ALGORITHM=INPLACE, LOCK=NONE;",
+				"CREATE INDEX `idx_posts_title` ON `posts` (`title`) ALGORITHM=INPLACE LOCK=NONE;",
+			},
+		},
+		{
+			name:    "off by default",
+			dialect: platform.MySQL,
+			notWant: []string{"ALGORITHM=", "LOCK="},
+		},
+		{
+			name:    "ignored by postgres",
+			dialect: platform.Postgres,
+			online:  true,
+			notWant: []string{"ALGORITHM=", "LOCK="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", onlineDDLSource)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, livePostsWithID(), tt.dialect)
+			sql, err := planner.GenerateSchemaDiffSQLWithOptions(diff, &generated, tt.dialect, planner.Options{MySQLOnlineDDL: tt.online})
+
+			c.Assert(err, qt.IsNil)
+			for _, want := range tt.want {
+				c.Assert(sql, qt.Contains, want)
+			}
+			for _, notWant := range tt.notWant {
+				c.Assert(sql, qt.Not(qt.Contains), notWant)
+			}
+		})
+	}
+}
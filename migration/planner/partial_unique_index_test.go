@@ -0,0 +1,113 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+// partialUniqueSource declares a plain unique constraint on username next to
+// a partial unique index on email. The index name deliberately follows the
+// PostgreSQL constraint naming pattern (table_column_key).
+const partialUniqueSource = `package models
+
+//migrator:schema:table name="users"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="username" type="TEXT" unique="true"
+	Username string
+
+	//migrator:schema:field name="email" type="TEXT"
+	//migrator:schema:index name="users_email_key" fields="email" unique="true" where="deleted_at IS NULL"
+	Email string
+
+	//migrator:schema:field name="deleted_at" type="TIMESTAMP"
+	DeletedAt *string
+}
+`
+
+func livePartialUniqueUsers(indexes ...dbtypes.DBIndex) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name: "users",
+			Type: "BASE TABLE",
+			Columns: []dbtypes.DBColumn{
+				{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "username", DataType: "text", UDTName: "text", IsNullable: "YES", IsUnique: true},
+				{Name: "email", DataType: "text", UDTName: "text", IsNullable: "YES"},
+				{Name: "deleted_at", DataType: "timestamp without time zone", UDTName: "timestamp", IsNullable: "YES"},
+			},
+		}},
+		Constraints: []dbtypes.DBConstraint{
+			{Name: "users_username_key", TableName: "users", Type: "UNIQUE", ColumnName: "username"},
+		},
+		Indexes: append([]dbtypes.DBIndex{
+			{Name: "users_username_key", TableName: "users", Columns: []string{"username"}, IsUnique: true},
+		}, indexes...),
+	}
+}
+
+func TestPartialUniqueIndexNextToUniqueConstraint(t *testing.T) {
+	tests := []struct {
+		name        string
+		live        *dbtypes.DBSchema
+		wantAdded   []string
+		wantRemoved []string
+		wantSQL     []string
+	}{
+		{
+			name:      "missing partial index is created with its predicate",
+			live:      livePartialUniqueUsers(),
+			wantAdded: []string{"users_email_key"},
+			wantSQL:   []string{`CREATE UNIQUE INDEX IF NOT EXISTS "users_email_key" ON "users" ("email") WHERE deleted_at IS NULL;`},
+		},
+		{
+			name: "matching partial index is unchanged",
+			live: livePartialUniqueUsers(dbtypes.DBIndex{
+				Name: "users_email_key", TableName: "users", Columns: []string{"email"}, IsUnique: true, Condition: "(deleted_at IS NULL)",
+			}),
+		},
+		{
+			name: "changed predicate rebuilds only the partial index",
+			live: livePartialUniqueUsers(dbtypes.DBIndex{
+				Name: "users_email_key", TableName: "users", Columns: []string{"email"}, IsUnique: true, Condition: "(deleted_at IS NOT NULL)",
+			}),
+			wantAdded:   []string{"users_email_key"},
+			wantRemoved: []string{"users_email_key"},
+			wantSQL: []string{
+				`DROP INDEX IF EXISTS "users_email_key";`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS "users_email_key" ON "users" ("email") WHERE deleted_at IS NULL;`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", partialUniqueSource)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, tt.live, platform.Postgres)
+			sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(diff.IndexesAdded, qt.DeepEquals, tt.wantAdded)
+			c.Assert(diff.IndexesRemoved, qt.DeepEquals, tt.wantRemoved)
+			c.Assert(diff.TablesModified, qt.HasLen, 0)
+			c.Assert(diff.ConstraintsAdded, qt.HasLen, 0)
+			c.Assert(diff.ConstraintsRemoved, qt.HasLen, 0)
+			for _, want := range tt.wantSQL {
+				c.Assert(sql, qt.Contains, want)
+			}
+			c.Assert(sql, qt.Not(qt.Contains), "users_username_key")
+		})
+	}
+}
@@ -169,6 +169,39 @@ type Options struct {
 	// deferring to the coarse destructive gate. Currently honored by the
 	// PostgreSQL-family planner.
 	SkipChangeKinds []diffpolicy.ChangeKind
+	// SafeNotNull plans nullable -> NOT NULL column changes as an explicit
+	// backfill-then-constrain sequence, with a NOT VALID check validated first
+	// when the target supports it. Currently honored by the PostgreSQL-family
+	// planner.
+	SafeNotNull bool
+	// SafeTypeChange plans incompatible column type changes as a copy into a
+	// temporary column that replaces the old one, instead of ALTER COLUMN
+	// TYPE. Currently honored by the PostgreSQL-family planner.
+	SafeTypeChange bool
+	// SafeVolatileDefault adds columns whose default calls a volatile
+	// function without the default, then sets it and backfills existing rows,
+	// instead of rewriting the table in ADD COLUMN ... DEFAULT. Currently
+	// honored by the PostgreSQL-family planner.
+	SafeVolatileDefault bool
+	// TwoStepConstraintValidation adds CHECK and FOREIGN KEY constraints on
+	// existing tables NOT VALID and validates them at the end of the plan when
+	// the target supports it. Currently honored by the PostgreSQL-family
+	// planner.
+	TwoStepConstraintValidation bool
+	// CustomStatementGenerators turn the changes recorded by
+	// config.CustomComparators into SQL. Custom changes never reach the
+	// dialect planner; see WithCustomStatementGenerator.
+	CustomStatementGenerators []CustomStatementGenerator
+	// MySQLOnlineDDL requests ALGORITHM=INPLACE, LOCK=NONE on the ADD COLUMN
+	// and CREATE INDEX statements that support it. Honored by the MySQL and
+	// MariaDB planners.
+	MySQLOnlineDDL bool
+	// CascadeCyclicTableDrops lets the plan drop removed tables whose
+	// database foreign keys reference each other. Honored by the SQLite
+	// planner, which defers foreign key checks for the drops; PostgreSQL
+	// drops tables with CASCADE and MySQL-family plans drop the foreign keys
+	// first, so neither needs it.
+	CascadeCyclicTableDrops bool
 }
 
 // CapabilitiesFor returns the configured capability set, falling back to the
@@ -324,7 +357,10 @@ func registerBuiltInPlanners() error {
 	}); err != nil {
 		return err
 	}
-	return registerPlannerFactory(platform.SQLite, func(Options) Planner {
+	return registerPlannerFactory(platform.SQLite, func(opts Options) Planner {
+		if opts.CascadeCyclicTableDrops {
+			return sqlite.New().WithCascadeCyclicTableDrops()
+		}
 		return sqlite.New()
 	})
 }
@@ -334,6 +370,18 @@ func registerPostgresFamilyPlanner(dialect string) error {
 		plan := postgres.NewWithCapabilities(opts.CapabilitiesFor(dialect)).
 			WithConcurrentIndexNames(opts.ConcurrentIndexNames...).
 			WithSkipChangeKinds(opts.SkipChangeKinds...)
+		if opts.SafeNotNull {
+			plan = plan.WithSafeNotNull()
+		}
+		if opts.SafeTypeChange {
+			plan = plan.WithSafeTypeChange()
+		}
+		if opts.SafeVolatileDefault {
+			plan = plan.WithSafeVolatileDefault()
+		}
+		if opts.TwoStepConstraintValidation {
+			plan = plan.WithTwoStepConstraintValidation()
+		}
 		if opts.ConcurrentIndexes {
 			return plan.WithConcurrentIndexes()
 		}
@@ -343,7 +391,11 @@ func registerPostgresFamilyPlanner(dialect string) error {
 
 func registerMySQLFamilyPlanner(dialect string) error {
 	return registerPlannerFactory(dialect, func(opts Options) Planner {
-		return mysql.NewWithCapabilities(opts.CapabilitiesFor(dialect))
+		plan := mysql.NewForDialect(dialect, opts.CapabilitiesFor(dialect))
+		if opts.MySQLOnlineDDL {
+			return plan.WithOnlineDDL()
+		}
+		return plan
 	})
 }
 
@@ -470,11 +522,23 @@ func GenerateSchemaDiffASTWithOptions(
 	if err != nil {
 		return nil, wrapPlanError(dialect, err)
 	}
+	diff, customChanges := splitCustomChanges(diff)
+	first, err := customStatementNodes(customChanges, dialect, opts.CustomStatementGenerators, CustomStatementsFirst)
+	if err != nil {
+		return nil, wrapPlanError(dialect, err)
+	}
 	nodes, err := planner.GenerateMigrationASTChecked(diff, generated)
 	if err != nil {
 		return nil, wrapPlanError(dialect, err)
 	}
-	return nodes, nil
+	last, err := customStatementNodes(customChanges, dialect, opts.CustomStatementGenerators, CustomStatementsLast)
+	if err != nil {
+		return nil, wrapPlanError(dialect, err)
+	}
+	if len(first) == 0 && len(last) == 0 {
+		return nodes, nil
+	}
+	return slices.Concat(first, nodes, last), nil
 }
 
 // NodeRequiresNoTransaction reports whether a single planned AST node must run
@@ -0,0 +1,326 @@
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/renderer"
+	"github.com/stokaro/ptah/migration/safety"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// Migration directions reported in PlannedOperation.Direction.
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// PlannedOperation is one planner-produced operation, ready to be filtered or
+// rendered with Render.
+type PlannedOperation struct {
+	// Direction is DirectionUp or DirectionDown.
+	Direction string
+	// Table is the table the operation targets, or "" when the operation is
+	// not table-scoped (extensions, enums, functions, roles, raw SQL, ...).
+	Table string
+	// Dialect is the dialect the operation was planned for and is rendered
+	// in.
+	Dialect string
+	// Node is the planned AST node.
+	Node ast.Node
+}
+
+// NewPlannedOperation wraps node, planned for dialect, deriving Table from
+// the node.
+func NewPlannedOperation(direction, dialect string, node ast.Node) PlannedOperation {
+	return PlannedOperation{Direction: direction, Table: operationTable(node), Dialect: dialect, Node: node}
+}
+
+// GenerateSchemaDiffOperations plans diff like GenerateSchemaDiffASTWithOptions
+// and returns the nodes as up operations.
+func GenerateSchemaDiffOperations(
+	diff *types.SchemaDiff,
+	generated *goschema.Database,
+	dialect string,
+	opts Options,
+) ([]PlannedOperation, error) {
+	nodes, err := GenerateSchemaDiffASTWithOptions(diff, generated, dialect, opts)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]PlannedOperation, 0, len(nodes))
+	for _, node := range nodes {
+		ops = append(ops, NewPlannedOperation(DirectionUp, dialect, node))
+	}
+	return ops, nil
+}
+
+// RenderFormat selects the output of Render.
+type RenderFormat string
+
+const (
+	// RenderFormatSQL renders the operations as migration SQL.
+	RenderFormatSQL RenderFormat = "sql"
+	// RenderFormatJSON renders the operations as a JSON array.
+	RenderFormatJSON RenderFormat = "json"
+	// RenderFormatPlan renders one line per change, signed "+" for additions,
+	// "~" for changes and "-" for removals. Destructive changes end with a
+	// "# destructive:" note giving the reason.
+	RenderFormatPlan RenderFormat = "plan"
+	// RenderFormatPlanColor is RenderFormatPlan with ANSI colors for
+	// terminals: green additions, yellow changes, red removals and bold red
+	// destructive changes.
+	RenderFormatPlanColor RenderFormat = "plan-color"
+)
+
+// Render renders ops in format. Operations must carry the dialect they were
+// planned for.
+func Render(ops []PlannedOperation, format RenderFormat) (string, error) {
+	switch format {
+	case RenderFormatSQL:
+		return renderOperationsSQL(ops)
+	case RenderFormatJSON:
+		if ops == nil {
+			ops = []PlannedOperation{}
+		}
+		data, err := json.MarshalIndent(ops, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	case RenderFormatPlan, RenderFormatPlanColor:
+		var out strings.Builder
+		for _, op := range ops {
+			for _, change := range operationChanges(op) {
+				out.WriteString(change.line(format == RenderFormatPlanColor))
+				out.WriteByte('\n')
+			}
+		}
+		return out.String(), nil
+	default:
+		return "", fmt.Errorf("unknown render format %q", format)
+	}
+}
+
+// renderOperationsSQL renders consecutive operations of one dialect together,
+// so the output matches GenerateSchemaDiffSQL.
+func renderOperationsSQL(ops []PlannedOperation) (string, error) {
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		dialect := ops[start].Dialect
+		end := start
+		var nodes []ast.Node
+		for end < len(ops) && ops[end].Dialect == dialect {
+			nodes = append(nodes, ops[end].Node)
+			end++
+		}
+		sql, err := renderOperationSQL(dialect, nodes...)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(sql)
+		start = end
+	}
+	return out.String(), nil
+}
+
+func renderOperationSQL(dialect string, nodes ...ast.Node) (string, error) {
+	if dialect == "" {
+		return "", fmt.Errorf("planned operation has no dialect")
+	}
+	sql, err := renderer.RenderSQL(dialect, nodes...)
+	if err != nil {
+		return "", wrapRenderError(dialect, err)
+	}
+	return sql, nil
+}
+
+// plannedOperationJSON is the JSON form of a PlannedOperation.
+type plannedOperationJSON struct {
+	Direction string          `json:"direction"`
+	Table     string          `json:"table,omitempty"`
+	Dialect   string          `json:"dialect"`
+	NodeType  string          `json:"node_type"`
+	Changes   []string        `json:"changes"`
+	SQL       string          `json:"sql"`
+	Severity  safety.Severity `json:"severity"`
+	Reason    string          `json:"reason"`
+}
+
+// MarshalJSON renders the operation's node to SQL in its dialect and reports
+// the changes and safety classification Render shows.
+func (op PlannedOperation) MarshalJSON() ([]byte, error) {
+	sql, err := renderOperationSQL(op.Dialect, op.Node)
+	if err != nil {
+		return nil, err
+	}
+	assessment := safety.Assess([]ast.Node{op.Node})[0]
+	changes := make([]string, 0, 1)
+	for _, change := range operationChanges(op) {
+		changes = append(changes, change.sign+" "+change.summary)
+	}
+	return json.Marshal(plannedOperationJSON{
+		Direction: op.Direction,
+		Table:     op.Table,
+		Dialect:   op.Dialect,
+		NodeType:  assessment.NodeType,
+		Changes:   changes,
+		SQL:       strings.TrimSpace(sql),
+		Severity:  assessment.Severity,
+		Reason:    assessment.Reason,
+	})
+}
+
+// plannedChange is one line of a text plan.
+type plannedChange struct {
+	sign    string
+	summary string
+	// destructive holds the reason a change is destructive, or "".
+	destructive string
+}
+
+const (
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiRed     = "\x1b[31m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiReset   = "\x1b[0m"
+)
+
+func (c plannedChange) line(color bool) string {
+	line := c.sign + " " + c.summary
+	if c.sign == "#" {
+		line = "  " + line
+	}
+	if c.destructive != "" {
+		line += "  # destructive: " + c.destructive
+	}
+	if !color {
+		return line
+	}
+	switch {
+	case c.destructive != "":
+		return ansiBoldRed + line + ansiReset
+	case c.sign == "+":
+		return ansiGreen + line + ansiReset
+	case c.sign == "~":
+		return ansiYellow + line + ansiReset
+	case c.sign == "-":
+		return ansiRed + line + ansiReset
+	default:
+		return line
+	}
+}
+
+// operationChanges summarizes node as plan lines: one per ALTER TABLE
+// operation, one for any other node, and a "#" note for comments.
+func operationChanges(op PlannedOperation) []plannedChange {
+	node := op.Node
+	switch n := node.(type) {
+	case *ast.CommentNode:
+		return []plannedChange{{sign: "#", summary: n.Text}}
+	case *ast.CreateTableNode:
+		return []plannedChange{{sign: "+", summary: "CREATE TABLE " + n.Name}}
+	case *ast.DropTableNode:
+		return []plannedChange{withSeverity(node, plannedChange{sign: "-", summary: "DROP TABLE " + n.Name})}
+	case *ast.IndexNode:
+		kind := "INDEX"
+		if n.Unique {
+			kind = "UNIQUE INDEX"
+		}
+		return []plannedChange{{sign: "+", summary: fmt.Sprintf("CREATE %s %s ON %s", kind, n.Name, n.Table)}}
+	case *ast.DropIndexNode:
+		return []plannedChange{{sign: "-", summary: "DROP INDEX " + n.Name}}
+	case *ast.AlterTableNode:
+		changes := make([]plannedChange, 0, len(n.Operations))
+		for _, op := range n.Operations {
+			single := &ast.AlterTableNode{Name: n.Name, Operations: []ast.AlterOperation{op}}
+			changes = append(changes, withSeverity(single, alterChange(n.Name, op)))
+		}
+		return changes
+	}
+	return []plannedChange{withSeverity(node, statementChange(op.Dialect, node))}
+}
+
+func alterChange(table string, op ast.AlterOperation) plannedChange {
+	switch o := op.(type) {
+	case *ast.AddColumnOperation:
+		return plannedChange{sign: "+", summary: fmt.Sprintf("ADD COLUMN %s.%s %s", table, o.Column.Name, o.Column.Type)}
+	case *ast.DropColumnOperation:
+		return plannedChange{sign: "-", summary: fmt.Sprintf("DROP COLUMN %s.%s", table, o.ColumnName)}
+	case *ast.ModifyColumnOperation:
+		change := o.Column.Type
+		if o.PreviousType != "" && !strings.EqualFold(o.PreviousType, o.Column.Type) {
+			change = o.PreviousType + "->" + o.Column.Type
+		}
+		return plannedChange{sign: "~", summary: fmt.Sprintf("ALTER %s.%s %s", table, o.Column.Name, change)}
+	case *ast.RenameColumnOperation:
+		return plannedChange{sign: "~", summary: fmt.Sprintf("RENAME COLUMN %s.%s -> %s", table, o.OldName, o.NewName)}
+	case *ast.AddConstraintOperation:
+		return plannedChange{sign: "+", summary: fmt.Sprintf("ADD CONSTRAINT %s ON %s", o.Constraint.Name, table)}
+	case *ast.DropConstraintOperation:
+		return plannedChange{sign: "-", summary: fmt.Sprintf("DROP CONSTRAINT %s ON %s", o.ConstraintName, table)}
+	default:
+		return plannedChange{sign: "~", summary: "ALTER TABLE " + table}
+	}
+}
+
+// statementChange summarizes a node without a dedicated summary by the first
+// line of its rendered SQL, signed by the statement's leading keyword.
+func statementChange(dialect string, node ast.Node) plannedChange {
+	summary := fmt.Sprintf("%T", node)
+	if sql, err := renderer.RenderSQL(dialect, node); err == nil {
+		summary, _, _ = strings.Cut(strings.TrimSpace(sql), "\n")
+		summary = strings.TrimSuffix(summary, ";")
+	}
+	keyword, _, _ := strings.Cut(strings.ToUpper(summary), " ")
+	switch keyword {
+	case "CREATE", "ADD", "INSERT", "GRANT":
+		return plannedChange{sign: "+", summary: summary}
+	case "DROP", "DELETE", "REVOKE":
+		return plannedChange{sign: "-", summary: summary}
+	default:
+		return plannedChange{sign: "~", summary: summary}
+	}
+}
+
+func withSeverity(node ast.Node, change plannedChange) plannedChange {
+	if assessment := safety.Assess([]ast.Node{node})[0]; assessment.Severity == safety.Destructive {
+		change.destructive = assessment.Reason
+	}
+	return change
+}
+
+// operationTable returns the table targeted by a planned node, or "" for
+// nodes that are not table-scoped.
+func operationTable(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.CreateTableNode:
+		return n.Name
+	case *ast.AlterTableNode:
+		return n.Name
+	case *ast.DropTableNode:
+		return n.Name
+	case *ast.IndexNode:
+		return n.Table
+	case *ast.DropIndexNode:
+		return n.Table
+	case *ast.CreateTriggerNode:
+		return n.Table
+	case *ast.DropTriggerNode:
+		return n.Table
+	case *ast.CreatePolicyNode:
+		return n.Table
+	case *ast.DropPolicyNode:
+		return n.Table
+	case *ast.AlterTableEnableRLSNode:
+		return n.Table
+	case *ast.AlterTableDisableRLSNode:
+		return n.Table
+	default:
+		return ""
+	}
+}
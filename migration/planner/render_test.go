@@ -0,0 +1,122 @@
+package planner_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/ast"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compares got with testdata/name, rewriting the file first
+// under -update.
+func assertGolden(c *qt.C, name, got string) {
+	c.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		c.Assert(os.MkdirAll("testdata", 0o755), qt.IsNil)
+		c.Assert(os.WriteFile(path, []byte(got), 0o644), qt.IsNil)
+	}
+	want, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, string(want))
+}
+
+// representativeDiff adds a table and a column, widens and narrows a
+// column, and drops a column, an index and a table.
+func representativeDiff() (*types.SchemaDiff, *goschema.Database) {
+	generated := &goschema.Database{
+		Tables: []goschema.Table{
+			{Name: "posts", StructName: "Post"},
+			{Name: "users", StructName: "User"},
+		},
+		Fields: []goschema.Field{
+			{Name: "id", Type: "INTEGER", StructName: "Post", Primary: true},
+			{Name: "title", Type: "TEXT", StructName: "Post"},
+			{Name: "id", Type: "INTEGER", StructName: "User", Primary: true},
+			{Name: "email", Type: "VARCHAR(255)", StructName: "User"},
+			{Name: "nickname", Type: "VARCHAR(32)", StructName: "User", Nullable: true},
+			{Name: "bio", Type: "TEXT", StructName: "User", Nullable: true},
+		},
+	}
+	diff := &types.SchemaDiff{
+		TablesAdded:   []string{"posts"},
+		TablesRemoved: []string{"legacy_sessions"},
+		TablesModified: []types.TableDiff{{
+			TableName:      "users",
+			ColumnsAdded:   []string{"bio"},
+			ColumnsRemoved: []string{"age"},
+			ColumnsModified: []types.ColumnDiff{
+				{ColumnName: "email", Changes: map[string]string{"type": "TEXT -> VARCHAR(255)"}, TypeChangeKind: types.TypeChangeNarrowing},
+				{ColumnName: "nickname", Changes: map[string]string{"type": "VARCHAR(16) -> VARCHAR(32)"}, TypeChangeKind: types.TypeChangeWidening},
+			},
+		}},
+		IndexesRemovedWithTables: []types.IndexRemovalInfo{{Name: "idx_old", TableName: "users"}},
+		IndexesRemoved:           []string{"idx_old"},
+	}
+
+	return diff, generated
+}
+
+func representativeOperations(c *qt.C) []planner.PlannedOperation {
+	c.Helper()
+	diff, generated := representativeDiff()
+	ops, err := planner.GenerateSchemaDiffOperations(diff, generated, platform.Postgres, planner.Options{})
+	c.Assert(err, qt.IsNil)
+	return ops
+}
+
+func TestRender_Golden(t *testing.T) {
+	tests := []struct {
+		format planner.RenderFormat
+		golden string
+	}{
+		{format: planner.RenderFormatSQL, golden: "render.sql"},
+		{format: planner.RenderFormatJSON, golden: "render.json"},
+		{format: planner.RenderFormatPlan, golden: "render.plan"},
+		{format: planner.RenderFormatPlanColor, golden: "render_color.plan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			c := qt.New(t)
+
+			got, err := planner.Render(representativeOperations(c), tt.format)
+
+			c.Assert(err, qt.IsNil)
+			assertGolden(c, tt.golden, got)
+		})
+	}
+}
+
+func TestRender_SQLMatchesGenerateSchemaDiffSQL(t *testing.T) {
+	c := qt.New(t)
+	diff, generated := representativeDiff()
+	want, err := planner.GenerateSchemaDiffSQL(diff, generated, platform.Postgres)
+	c.Assert(err, qt.IsNil)
+
+	got, err := planner.Render(representativeOperations(c), planner.RenderFormatSQL)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, want)
+}
+
+func TestRender_Errors(t *testing.T) {
+	c := qt.New(t)
+	op := planner.NewPlannedOperation(planner.DirectionUp, "", ast.NewDropTable("users"))
+
+	_, err := planner.Render([]planner.PlannedOperation{op}, planner.RenderFormatSQL)
+	c.Assert(err, qt.ErrorMatches, "planned operation has no dialect")
+
+	_, err = planner.Render(nil, "yaml")
+	c.Assert(err, qt.ErrorMatches, `unknown render format "yaml"`)
+}
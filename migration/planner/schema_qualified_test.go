@@ -0,0 +1,116 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const schemaQualifiedSource = `package models
+
+//migrator:schema:function name="get_current_tenant_id" schema="app" returns="TEXT" language="sql" volatility="STABLE" body="SELECT current_setting('app.current_tenant_id')"
+//migrator:schema:function name="audit_stamp" returns="TIMESTAMPTZ" language="sql" body="SELECT now()"
+//migrator:schema:table name="users" schema="app"
+//migrator:schema:rls:policy name="tenant_isolation" schema="app" table="users" for="ALL" to="PUBLIC" using="(tenant_id = get_current_tenant_id()) AND (audit_stamp() IS NOT NULL)"
+type User struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+
+	//migrator:schema:field name="tenant_id" type="TEXT" not_null="true"
+	TenantID string
+}
+`
+
+func liveSchemaQualifiedObjects(using string) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{
+		Tables: []dbtypes.DBTable{{
+			Name:   "users",
+			Schema: "app",
+			Type:   "BASE TABLE",
+			Columns: []dbtypes.DBColumn{
+				{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "tenant_id", DataType: "text", UDTName: "text", IsNullable: "NO"},
+			},
+			RLSEnabled: true,
+		}},
+		Functions: []dbtypes.DBFunction{
+			{
+				Name: "get_current_tenant_id", Schema: "app", Returns: "text", Language: "sql", Security: "INVOKER",
+				Volatility: "STABLE", Body: "SELECT current_setting('app.current_tenant_id')",
+			},
+			{Name: "audit_stamp", Returns: "timestamp with time zone", Language: "sql", Security: "INVOKER", Volatility: "VOLATILE", Body: "SELECT now()"},
+		},
+		RLSPolicies: []dbtypes.DBRLSPolicy{{
+			Name: "tenant_isolation", Table: "app.users", PolicyFor: "ALL", ToRoles: "PUBLIC", UsingExpression: using,
+		}},
+	}
+}
+
+func TestPostgresSchemaQualifiedFunctionsAndPolicies(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", schemaQualifiedSource)
+	c.Assert(err, qt.IsNil)
+
+	diff := schemadiff.CompareWithDialect(&generated, &dbtypes.DBSchema{}, platform.Postgres)
+	sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(diff.FunctionsAdded, qt.DeepEquals, []string{"app.get_current_tenant_id", "audit_stamp"})
+	c.Assert(diff.RLSPoliciesAdded, qt.DeepEquals, []string{"app.tenant_isolation"})
+	for _, want := range []string{
+		`CREATE OR REPLACE FUNCTION "app"."get_current_tenant_id"()`,
+		`CREATE OR REPLACE FUNCTION "audit_stamp"()`,
+		`CREATE POLICY "tenant_isolation" ON "app"."users"`,
+		`USING ((tenant_id = app.get_current_tenant_id()) AND (audit_stamp() IS NOT NULL))`,
+	} {
+		c.Assert(sql, qt.Contains, want)
+	}
+}
+
+func TestPostgresSchemaQualifiedPoliciesAreNotDrift(t *testing.T) {
+	tests := []struct {
+		name  string
+		using string
+	}{
+		{name: "catalog qualifies the call", using: "(tenant_id = app.get_current_tenant_id()) AND (audit_stamp() IS NOT NULL)"},
+		{name: "schema on the reading search_path", using: "(tenant_id = get_current_tenant_id()) AND (audit_stamp() IS NOT NULL)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", schemaQualifiedSource)
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveSchemaQualifiedObjects(tt.using), platform.Postgres)
+
+			c.Assert(diff.FunctionsAdded, qt.HasLen, 0)
+			c.Assert(diff.FunctionsRemoved, qt.HasLen, 0)
+			c.Assert(diff.RLSPoliciesAdded, qt.HasLen, 0)
+			c.Assert(diff.RLSPoliciesRemoved, qt.HasLen, 0)
+			c.Assert(diff.RLSPoliciesModified, qt.HasLen, 0)
+		})
+	}
+}
+
+func TestPostgresSameFunctionNameInAnotherSchemaIsDistinct(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", schemaQualifiedSource)
+	c.Assert(err, qt.IsNil)
+	live := liveSchemaQualifiedObjects("(tenant_id = app.get_current_tenant_id()) AND (audit_stamp() IS NOT NULL)")
+	live.Functions[0].Schema = ""
+
+	diff := schemadiff.CompareWithDialect(&generated, live, platform.Postgres)
+	sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(diff.FunctionsAdded, qt.DeepEquals, []string{"app.get_current_tenant_id"})
+	c.Assert(diff.FunctionsRemoved, qt.DeepEquals, []string{"get_current_tenant_id"})
+	c.Assert(sql, qt.Contains, `DROP FUNCTION IF EXISTS "get_current_tenant_id"()`)
+}
@@ -0,0 +1,157 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// Capabilities returns the capability matrix the planner assumes for dialect
+// when no live server version is known: the dialect's current-line preset.
+// Unknown dialects get nil.
+func Capabilities(dialect string) capability.Capabilities {
+	return capability.ForDialect(dialect)
+}
+
+// SkippedFeatureKind names the kind of schema object a SkippedFeature refers
+// to.
+type SkippedFeatureKind string
+
+const (
+	// SkippedFunction is a PostgreSQL-style stored function.
+	SkippedFunction SkippedFeatureKind = "function"
+	// SkippedPolicy is a row-level security policy.
+	SkippedPolicy SkippedFeatureKind = "policy"
+	// SkippedRLS is enabling or disabling row-level security on a table.
+	SkippedRLS SkippedFeatureKind = "rls"
+	// SkippedExtension is a PostgreSQL extension.
+	SkippedExtension SkippedFeatureKind = "extension"
+	// SkippedRole is a database role.
+	SkippedRole SkippedFeatureKind = "role"
+	// SkippedGrant is an object privilege grant or revoke.
+	SkippedGrant SkippedFeatureKind = "grant"
+)
+
+// SkippedFeature records one schema change the planner leaves out of a plan
+// because the target dialect cannot express it.
+type SkippedFeature struct {
+	// Kind is the kind of the skipped object.
+	Kind SkippedFeatureKind
+	// Object identifies the skipped object, e.g. a function, policy, table,
+	// extension, or role name.
+	Object string
+	// Capability is the capability the target lacks.
+	Capability capability.Capability
+	// Reason is a human-readable explanation of the skip.
+	Reason string
+}
+
+func (f SkippedFeature) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Kind, f.Object, f.Reason)
+}
+
+// SkippedFeatures lists the changes in diff that planning for dialect will
+// leave out because the target lacks the capability they need. Nil caps means
+// the dialect's default preset, matching the planner helpers. The result is
+// empty when the plan covers the whole diff.
+func SkippedFeatures(diff *types.SchemaDiff, dialect string, caps capability.Capabilities) []SkippedFeature {
+	if diff == nil {
+		return nil
+	}
+	caps = Options{Capabilities: caps}.CapabilitiesFor(dialect)
+	var skipped []SkippedFeature
+	add := func(kind SkippedFeatureKind, needed capability.Capability, what string, objects []string) {
+		if caps.Has(needed) {
+			return
+		}
+		for _, object := range objects {
+			skipped = append(skipped, SkippedFeature{
+				Kind:       kind,
+				Object:     object,
+				Capability: needed,
+				Reason:     fmt.Sprintf("%s does not support %s (capability %s)", dialect, what, needed),
+			})
+		}
+	}
+
+	add(SkippedExtension, capability.Extensions, "extensions",
+		concatNames(diff.ExtensionsAdded, diff.ExtensionsRemoved, extensionDiffNames(diff.ExtensionsModified)))
+	add(SkippedFunction, capability.Functions, "PostgreSQL-style functions",
+		concatNames(diff.FunctionsAdded, functionDiffNames(diff.FunctionsModified), diff.FunctionsRemoved))
+	add(SkippedRLS, capability.RowLevelSecurity, "row-level security",
+		concatNames(diff.RLSEnabledTablesAdded, diff.RLSEnabledTablesRemoved))
+	add(SkippedPolicy, capability.RowLevelSecurity, "row-level security policies",
+		concatNames(diff.RLSPoliciesAdded, policyDiffNames(diff.RLSPoliciesModified), policyRefNames(diff.RLSPoliciesRemoved)))
+	add(SkippedRole, capability.RoleManagement, "role management",
+		concatNames(diff.RolesAdded, roleDiffNames(diff.RolesModified), diff.RolesRemoved,
+			roleMembershipNames(diff.RoleMembershipsAdded), roleMembershipNames(diff.RoleMembershipsRemoved)))
+	add(SkippedGrant, capability.RoleManagement, "privilege grants",
+		concatNames(grantNames(diff.GrantsAdded), grantNames(diff.GrantsRemoved),
+			grantNames(diff.GrantOptionsAdded), grantNames(diff.GrantOptionsRevoked)))
+	return skipped
+}
+
+func concatNames(lists ...[]string) []string {
+	var names []string
+	for _, list := range lists {
+		names = append(names, list...)
+	}
+	return names
+}
+
+func extensionDiffNames(diffs []types.ExtensionDiff) []string {
+	names := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		names = append(names, diff.ExtensionName)
+	}
+	return names
+}
+
+func functionDiffNames(diffs []types.FunctionDiff) []string {
+	names := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		names = append(names, diff.FunctionName)
+	}
+	return names
+}
+
+func policyDiffNames(diffs []types.RLSPolicyDiff) []string {
+	names := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		names = append(names, diff.PolicyName+" ON "+diff.TableName)
+	}
+	return names
+}
+
+func policyRefNames(refs []types.RLSPolicyRef) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		names = append(names, ref.PolicyName+" ON "+ref.TableName)
+	}
+	return names
+}
+
+func roleDiffNames(diffs []types.RoleDiff) []string {
+	names := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		names = append(names, diff.RoleName)
+	}
+	return names
+}
+
+func roleMembershipNames(memberships []types.RoleMembership) []string {
+	names := make([]string, 0, len(memberships))
+	for _, membership := range memberships {
+		names = append(names, fmt.Sprintf("%s TO %s", membership.Role, membership.Member))
+	}
+	return names
+}
+
+func grantNames(grants []types.GrantRef) []string {
+	names := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		names = append(names, fmt.Sprintf("%s ON %s %s TO %s", grant.Privilege, grant.ObjectType, grant.ObjectName, grant.Role))
+	}
+	return names
+}
@@ -0,0 +1,98 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestCapabilitiesReturnsDialectPreset(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(planner.Capabilities(platform.MySQL), qt.DeepEquals, capability.MySQL80())
+	c.Assert(planner.Capabilities("pgx"), qt.DeepEquals, capability.Postgres17())
+	c.Assert(planner.Capabilities("oracle"), qt.IsNil)
+}
+
+func postgresOnlyDiff() *types.SchemaDiff {
+	return &types.SchemaDiff{
+		ExtensionsAdded:       []string{"pgcrypto"},
+		FunctionsAdded:        []string{"current_tenant"},
+		FunctionsModified:     []types.FunctionDiff{{FunctionName: "touch_updated_at"}},
+		RLSEnabledTablesAdded: []string{"posts"},
+		RLSPoliciesAdded:      []string{"posts_tenant"},
+		RLSPoliciesRemoved:    []types.RLSPolicyRef{{PolicyName: "posts_owner", TableName: "posts"}},
+		RolesAdded:            []string{"app_user"},
+		GrantsAdded:           []types.GrantRef{{Role: "app_user", Privilege: "SELECT", ObjectType: "TABLE", ObjectName: "posts"}},
+	}
+}
+
+func TestSkippedFeaturesListsObjectsTheDialectCannotExpress(t *testing.T) {
+	c := qt.New(t)
+
+	skipped := planner.SkippedFeatures(postgresOnlyDiff(), platform.MySQL, nil)
+
+	got := make([]string, 0, len(skipped))
+	for _, feature := range skipped {
+		got = append(got, string(feature.Kind)+" "+feature.Object)
+	}
+	c.Assert(got, qt.DeepEquals, []string{
+		"extension pgcrypto",
+		"function current_tenant",
+		"function touch_updated_at",
+		"rls posts",
+		"policy posts_tenant",
+		"policy posts_owner ON posts",
+		"role app_user",
+		"grant SELECT ON TABLE posts TO app_user",
+	})
+	c.Assert(skipped[0].Capability, qt.Equals, capability.Extensions)
+	c.Assert(skipped[0].String(), qt.Equals, "extension pgcrypto: mysql does not support extensions (capability extensions)")
+}
+
+func TestSkippedFeaturesFollowsTargetCapabilities(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		caps    capability.Capabilities
+		want    []planner.SkippedFeatureKind
+	}{
+		{name: "postgres", dialect: platform.Postgres, want: nil},
+		{
+			name:    "spanner",
+			dialect: platform.Spanner,
+			want: []planner.SkippedFeatureKind{
+				planner.SkippedExtension, planner.SkippedFunction, planner.SkippedFunction,
+				planner.SkippedRLS, planner.SkippedPolicy, planner.SkippedPolicy,
+				planner.SkippedRole, planner.SkippedGrant,
+			},
+		},
+		{
+			name:    "postgres without rls",
+			dialect: platform.Postgres,
+			caps:    capability.Postgres16().With(capability.RowLevelSecurity, false),
+			want:    []planner.SkippedFeatureKind{planner.SkippedRLS, planner.SkippedPolicy, planner.SkippedPolicy},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			var got []planner.SkippedFeatureKind
+			for _, feature := range planner.SkippedFeatures(postgresOnlyDiff(), tt.dialect, tt.caps) {
+				got = append(got, feature.Kind)
+			}
+			c.Assert(got, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestSkippedFeaturesNilDiff(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(planner.SkippedFeatures(nil, platform.MySQL, nil), qt.IsNil)
+}
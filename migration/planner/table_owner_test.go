@@ -0,0 +1,86 @@
+package planner_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	dbtypes "github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+func tableOwnerSource(tableAttrs string) string {
+	return `package models
+
+//migrator:schema:table name="accounts"` + tableAttrs + `
+type Account struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+}
+`
+}
+
+func liveAccountsOwnedBy(owner string) *dbtypes.DBSchema {
+	return &dbtypes.DBSchema{Tables: []dbtypes.DBTable{{
+		Name:  "accounts",
+		Type:  "BASE TABLE",
+		Owner: owner,
+		Columns: []dbtypes.DBColumn{
+			{Name: "id", DataType: "integer", UDTName: "int4", IsNullable: "NO", IsPrimaryKey: true},
+		},
+	}}}
+}
+
+func TestPostgresTableOwnerIsTransferred(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", tableOwnerSource(` owner="app_owner"`))
+	c.Assert(err, qt.IsNil)
+
+	diff := schemadiff.CompareWithDialect(&generated, liveAccountsOwnedBy("postgres"), platform.Postgres)
+	sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(diff.TablesModified, qt.HasLen, 1)
+	c.Assert(diff.TablesModified[0].Owner, qt.Equals, "postgres -> app_owner")
+	c.Assert(sql, qt.Contains, `ALTER TABLE "accounts" OWNER TO "app_owner";`)
+}
+
+func TestTableOwnerIsNotDrift(t *testing.T) {
+	tests := []struct {
+		name       string
+		tableAttrs string
+		owner      string
+		dialect    string
+	}{
+		{name: "not annotated", owner: "postgres", dialect: platform.Postgres},
+		{name: "owned by the annotated role", tableAttrs: ` owner="app_owner"`, owner: "app_owner", dialect: platform.Postgres},
+		{name: "MySQL ignores it", tableAttrs: ` owner="app_owner"`, owner: "root", dialect: platform.MySQL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSource("models.go", tableOwnerSource(tt.tableAttrs))
+			c.Assert(err, qt.IsNil)
+
+			diff := schemadiff.CompareWithDialect(&generated, liveAccountsOwnedBy(tt.owner), tt.dialect)
+
+			c.Assert(diff.TablesModified, qt.HasLen, 0)
+		})
+	}
+}
+
+func TestPostgresCreatedTableGetsOwner(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSource("models.go", tableOwnerSource(` owner="app_owner"`))
+	c.Assert(err, qt.IsNil)
+
+	diff := schemadiff.CompareWithDialect(&generated, &dbtypes.DBSchema{}, platform.Postgres)
+	sql, err := planner.GenerateSchemaDiffSQL(diff, &generated, platform.Postgres)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(sql, qt.Contains, `ALTER TABLE "accounts" OWNER TO "app_owner";`)
+}
@@ -0,0 +1,38 @@
+-- POSTGRES TABLE: posts --
+CREATE TABLE "posts" (
+  "id" INTEGER PRIMARY KEY NOT NULL,
+  "title" TEXT NOT NULL
+);
+
+-- Add/modify columns for table: users --
+-- ALTER statements: --
+ALTER TABLE "users" ADD COLUMN "bio" TEXT;
+
+-- WARNING: narrowing type change on users.email (TEXT -> VARCHAR(255)) can reject existing values; check the data first: SELECT MAX(LENGTH(email)) FROM users --
+-- ALTER statements: --
+ALTER TABLE "users" ALTER COLUMN "email" TYPE VARCHAR(255);
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM "users" WHERE "email" IS NULL LIMIT 1) THEN
+        UPDATE "users" SET "email" = '' WHERE "email" IS NULL;
+    END IF;
+END
+$$;
+ALTER TABLE "users" ALTER COLUMN "email" SET NOT NULL;
+ALTER TABLE "users" ALTER COLUMN "email" DROP DEFAULT;
+
+-- Modify column users.email: type: TEXT -> VARCHAR(255) --
+-- ALTER statements: --
+ALTER TABLE "users" ALTER COLUMN "nickname" TYPE VARCHAR(32);
+ALTER TABLE "users" ALTER COLUMN "nickname" DROP NOT NULL;
+ALTER TABLE "users" ALTER COLUMN "nickname" DROP DEFAULT;
+
+-- Modify column users.nickname: type: VARCHAR(16) -> VARCHAR(32) --
+DROP INDEX IF EXISTS "idx_old";
+-- Remove columns from table: users --
+-- ALTER statements: --
+ALTER TABLE "users" DROP COLUMN "age" CASCADE;
+
+-- WARNING: Dropping column users.age with CASCADE - This will delete data and dependent objects! --
+-- WARNING: This will delete all data!
+DROP TABLE IF EXISTS "legacy_sessions" CASCADE;
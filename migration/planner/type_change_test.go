@@ -0,0 +1,93 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/migration/planner"
+	"github.com/stokaro/ptah/migration/safety"
+	"github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestColumnTypeChangeWarnings(t *testing.T) {
+	tests := []struct {
+		name        string
+		dialect     string
+		change      string
+		kind        types.TypeChangeKind
+		newType     string
+		wantComment string
+		destructive bool
+	}{
+		{
+			name:        "postgres narrowing suggests a length check",
+			dialect:     platform.Postgres,
+			change:      "varchar(255) -> VARCHAR(50)",
+			kind:        types.TypeChangeNarrowing,
+			newType:     "VARCHAR(50)",
+			wantComment: "-- WARNING: narrowing type change on users.name (varchar(255) -> VARCHAR(50)) can reject existing values; check the data first: SELECT MAX(LENGTH(name)) FROM users",
+			destructive: true,
+		},
+		{
+			name:        "mysql narrowing suggests a range check",
+			dialect:     platform.MySQL,
+			change:      "bigint -> INT",
+			kind:        types.TypeChangeNarrowing,
+			newType:     "INT",
+			wantComment: "-- WARNING: narrowing type change on users.name (bigint -> INT) can reject existing values; check the data first: SELECT MIN(name), MAX(name) FROM users",
+			destructive: true,
+		},
+		{
+			name:        "postgres incompatible",
+			dialect:     platform.Postgres,
+			change:      "text -> INTEGER",
+			kind:        types.TypeChangeIncompatible,
+			newType:     "INTEGER",
+			wantComment: "-- WARNING: incompatible type change on users.name (text -> INTEGER); existing values may fail to convert",
+			destructive: true,
+		},
+		{
+			name:        "postgres widening",
+			dialect:     platform.Postgres,
+			change:      "varchar(50) -> VARCHAR(255)",
+			kind:        types.TypeChangeWidening,
+			newType:     "VARCHAR(255)",
+			destructive: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated := &goschema.Database{
+				Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+				Fields: []goschema.Field{{Name: "name", Type: tt.newType, StructName: "User"}},
+			}
+			diff := &types.SchemaDiff{
+				TablesModified: []types.TableDiff{{
+					TableName: "users",
+					ColumnsModified: []types.ColumnDiff{{
+						ColumnName:     "name",
+						Changes:        map[string]string{"type": tt.change},
+						TypeChangeKind: tt.kind,
+					}},
+				}},
+			}
+
+			sql, err := planner.GenerateSchemaDiffSQL(diff, generated, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			c.Assert(sql, qt.Contains, tt.wantComment)
+			c.Assert(strings.Count(sql, "-- WARNING:"), qt.Equals, strings.Count(tt.wantComment, "-- WARNING:"))
+
+			nodes, err := planner.GenerateSchemaDiffAST(diff, generated, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			assessments, err := safety.AssessRendered(nodes, tt.dialect)
+			c.Assert(err, qt.IsNil)
+			c.Assert(safety.HasDestructiveAssessment(assessments), qt.Equals, tt.destructive)
+		})
+	}
+}
@@ -65,11 +65,14 @@ func ClassifySchemaDiff(diff *types.SchemaDiff) []Finding {
 	add(&findings, "tables_added", len(diff.TablesAdded), Safe)
 	add(&findings, "tables_removed", len(diff.TablesRemoved), Destructive)
 	add(&findings, "enums_added", len(diff.EnumsAdded), Safe)
+	add(&findings, "enums_renamed", len(diff.EnumsRenamed), Safe)
 	add(&findings, "enums_removed", len(diff.EnumsRemoved), Destructive)
 	add(&findings, "indexes_added", len(diff.IndexesAdded), Warning)
 	add(&findings, "indexes_removed", len(diff.IndexesRemoved), Warning)
+	add(&findings, "indexes_visibility_changed", len(diff.IndexesVisibilityChanged), Warning)
 	add(&findings, "extensions_added", len(diff.ExtensionsAdded), Safe)
 	add(&findings, "extensions_removed", len(diff.ExtensionsRemoved), Destructive)
+	add(&findings, "extensions_modified", len(diff.ExtensionsModified), Warning)
 	add(&findings, "functions_added", len(diff.FunctionsAdded), Safe)
 	add(&findings, "functions_removed", len(diff.FunctionsRemoved), Destructive)
 	add(&findings, "functions_modified", len(diff.FunctionsModified), Warning)
@@ -81,6 +84,8 @@ func ClassifySchemaDiff(diff *types.SchemaDiff) []Finding {
 	add(&findings, "roles_added", len(diff.RolesAdded), Safe)
 	add(&findings, "roles_removed", len(diff.RolesRemoved), Destructive)
 	add(&findings, "roles_modified", len(diff.RolesModified), Warning)
+	add(&findings, "role_memberships_added", len(diff.RoleMembershipsAdded), Warning)
+	add(&findings, "role_memberships_removed", len(diff.RoleMembershipsRemoved), Destructive)
 	add(&findings, "constraints_added", len(diff.ConstraintsAdded), Warning)
 	add(&findings, "constraints_removed", len(diff.ConstraintsRemoved), Destructive)
 
@@ -90,6 +95,14 @@ func ClassifySchemaDiff(diff *types.SchemaDiff) []Finding {
 		add(&findings, "columns_modified", len(table.ColumnsModified), Warning)
 		add(&findings, "table_constraints_added", len(table.ConstraintsAdded), Warning)
 		add(&findings, "table_constraints_removed", len(table.ConstraintsRemoved), Destructive)
+		add(&findings, "table_inherits_added", len(table.InheritsAdded), Warning)
+		add(&findings, "table_inherits_removed", len(table.InheritsRemoved), Warning)
+		if table.Comment != "" {
+			add(&findings, "table_comments_modified", 1, Safe)
+		}
+		if table.Owner != "" {
+			add(&findings, "table_owners_modified", 1, Warning)
+		}
 	}
 	for _, enum := range diff.EnumsModified {
 		add(&findings, "enum_values_added", len(enum.ValuesAdded), Warning)
@@ -389,6 +402,9 @@ func classifyAlterOperation(op ast.AlterOperation) (Severity, string) {
 	case *ast.RenameTableOperation:
 		return Warning, "RENAME TABLE can break deployed readers and writers"
 	case *ast.AddConstraintOperation:
+		if o.Constraint != nil && o.Constraint.NotValid && o.Constraint.Type == ast.CheckConstraint {
+			return Safe, "ADD CONSTRAINT ... NOT VALID does not check existing rows"
+		}
 		return Warning, "ADD CONSTRAINT can fail on existing rows"
 	case *ast.AddColumnOperation:
 		if o.Column != nil && !o.Column.Nullable {
@@ -403,6 +419,11 @@ func classifyAlterOperation(op ast.AlterOperation) (Severity, string) {
 		return Warning, "ADD INDEX can affect write workload during build"
 	case *ast.ModifyTTLOperation:
 		return Warning, "MODIFY TTL can delete or move existing rows"
+	case *ast.InheritOperation:
+		if o.NoInherit {
+			return Warning, "NO INHERIT hides the table's rows from queries on the parent"
+		}
+		return Warning, "INHERIT makes the table's rows visible through the parent"
 	default:
 		return Safe, "does not remove data or tighten constraints"
 	}
@@ -412,8 +433,12 @@ func classifyModifyColumn(op *ast.ModifyColumnOperation) (Severity, string) {
 	if op == nil || op.Column == nil {
 		return Warning, "column modification needs manual review"
 	}
-	if IsTypeNarrowing(op.PreviousType, op.Column.Type) {
+	switch {
+	case IsTypeNarrowing(op.PreviousType, op.Column.Type),
+		typechange.Classify(op.PreviousType, op.Column.Type, "") == typechange.Narrowing:
 		return Destructive, fmt.Sprintf("column type narrows from %s to %s", op.PreviousType, op.Column.Type)
+	case typechange.Classify(op.PreviousType, op.Column.Type, "") == typechange.Incompatible:
+		return Destructive, fmt.Sprintf("column type changes incompatibly from %s to %s; existing values may fail to convert", op.PreviousType, op.Column.Type)
 	}
 	if op.HasPreviousNullable && !op.PreviousNullable && op.Column.Nullable {
 		return Destructive, "DROP NOT NULL removes a column-level data protection"
@@ -118,6 +118,17 @@ func TestClassifyASTStatements(t *testing.T) {
 			&ast.RenameTableOperation{NewName: "users"},
 		},
 	}), qt.Equals, safety.Warning)
+	c.Assert(safety.Classify(&ast.AlterTableNode{
+		Name: "users",
+		Operations: []ast.AlterOperation{
+			&ast.AddConstraintOperation{Constraint: &ast.ConstraintNode{
+				Type:       ast.CheckConstraint,
+				Name:       "users_status_not_null",
+				Expression: "status IS NOT NULL",
+				NotValid:   true,
+			}},
+		},
+	}), qt.Equals, safety.Safe)
 	c.Assert(safety.Classify(ast.NewIndex("users_email_key", "users", "email").SetUnique()), qt.Equals, safety.Warning)
 	c.Assert(safety.Classify(ast.NewRawSQL("DELETE FROM pg_enum WHERE enumlabel = 'archived'")), qt.Equals, safety.Destructive)
 	c.Assert(safety.Classify(ast.NewRawSQL("DROP TYPE status")), qt.Equals, safety.Destructive)
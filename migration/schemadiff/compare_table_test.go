@@ -0,0 +1,131 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+const compareTableSource = `package models
+
+type Timestamps struct {
+	//migrator:schema:field name="created_at" type="TIMESTAMP" not_null="true"
+	CreatedAt string
+
+	//migrator:schema:field name="updated_at" type="TIMESTAMP"
+	UpdatedAt string
+}
+
+//migrator:schema:table name="orders" primary_key="tenant_id,id"
+type Order struct {
+	//migrator:schema:field name="tenant_id" type="INTEGER"
+	TenantID int
+
+	//migrator:schema:field name="id" type="INTEGER"
+	ID int
+
+	//migrator:schema:field name="total" type="TEXT" not_null="true"
+	Total string
+
+	//migrator:embedded mode="inline"
+	Timestamps
+}
+
+//migrator:schema:table name="customers"
+type Customer struct {
+	//migrator:schema:field name="id" type="INTEGER" primary="true"
+	ID int
+}
+`
+
+func tenantOrders(columns ...types.DBColumn) *types.DBTable {
+	return &types.DBTable{
+		Name: "tenant_42_orders",
+		Type: "TABLE",
+		Columns: append([]types.DBColumn{
+			{Name: "tenant_id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+			{Name: "total", DataType: "text", IsNullable: "NO"},
+		}, columns...),
+	}
+}
+
+func TestCompareTable(t *testing.T) {
+	createdAt := types.DBColumn{Name: "created_at", DataType: "timestamp without time zone", IsNullable: "NO"}
+	updatedAt := types.DBColumn{Name: "updated_at", DataType: "timestamp without time zone", IsNullable: "YES"}
+	tests := []struct {
+		name string
+		live *types.DBTable
+		want difftypes.TableDiff
+	}{
+		{
+			name: "embedded columns and primary key columns match",
+			live: tenantOrders(createdAt, updatedAt),
+			want: difftypes.TableDiff{TableName: "orders"},
+		},
+		{
+			name: "missing embedded column is added",
+			live: tenantOrders(createdAt),
+			want: difftypes.TableDiff{TableName: "orders", ColumnsAdded: []string{"updated_at"}},
+		},
+		{
+			name: "live column without a field is removed",
+			live: tenantOrders(createdAt, updatedAt, types.DBColumn{Name: "legacy", DataType: "text", IsNullable: "YES"}),
+			want: difftypes.TableDiff{TableName: "orders", ColumnsRemoved: []string{"legacy"}},
+		},
+		{
+			name: "embedded column nullability drift",
+			live: tenantOrders(types.DBColumn{Name: "created_at", DataType: "timestamp without time zone", IsNullable: "YES"}, updatedAt),
+			want: difftypes.TableDiff{TableName: "orders", ColumnsModified: []difftypes.ColumnDiff{
+				{ColumnName: "created_at", Changes: map[string]string{"nullable": "true -> false"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSources(map[string][]byte{"models.go": []byte(compareTableSource)})
+			c.Assert(err, qt.IsNil)
+			opts := config.DefaultCompareOptions()
+			opts.Dialect = "postgres"
+
+			tableDiff, err := schemadiff.CompareTable(generated, "Order", tt.live, opts)
+
+			c.Assert(err, qt.IsNil)
+			c.Assert(tableDiff, qt.DeepEquals, tt.want)
+			c.Assert(tableDiff.HasChanges(), qt.Equals, tt.want.HasChanges())
+		})
+	}
+}
+
+func TestCompareTable_MatchesFullComparison(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSources(map[string][]byte{"models.go": []byte(compareTableSource)})
+	c.Assert(err, qt.IsNil)
+	live := tenantOrders(types.DBColumn{Name: "created_at", DataType: "timestamp without time zone", IsNullable: "YES"})
+	live.Name = "orders"
+
+	tableDiff, err := schemadiff.CompareTable(generated, "Order", live, nil)
+	c.Assert(err, qt.IsNil)
+	diff := schemadiff.Compare(generated, &types.DBSchema{Tables: []types.DBTable{*live}})
+
+	c.Assert(diff.TablesModified, qt.DeepEquals, []difftypes.TableDiff{tableDiff})
+}
+
+func TestCompareTable_UnknownStruct(t *testing.T) {
+	c := qt.New(t)
+	generated, err := goschema.ParseSources(map[string][]byte{"models.go": []byte(compareTableSource)})
+	c.Assert(err, qt.IsNil)
+
+	_, err = schemadiff.CompareTable(generated, "Invoice", tenantOrders(), nil)
+
+	c.Assert(err, qt.ErrorIs, schemadiff.ErrTableNotFound)
+	c.Assert(err, qt.ErrorMatches, `schemadiff: struct "Invoice": no table for struct`)
+}
@@ -1,6 +1,7 @@
 package schemadiff_test
 
 import (
+	"slices"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -8,6 +9,7 @@ import (
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/migration/schemadiff"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
 )
 
 func TestCompareWithDialect_TableLevelCompositePrimaryKeyMatchesIntrospectedPostgresPrimaryKey(t *testing.T) {
@@ -104,3 +106,102 @@ func compositePrimaryKeySchema() *goschema.Database {
 		},
 	}
 }
+
+// membershipSchemas declares memberships with a table-level primary key of
+// declaredKey and the database with one of databaseKey. Only user_id moves
+// between the keys; its declared and database nullability are given.
+func membershipSchemas(declaredKey, databaseKey []string, declaredNullable bool, dbNullable string) (*goschema.Database, *types.DBSchema) {
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Membership", Name: "memberships", PrimaryKey: declaredKey}},
+		Fields: []goschema.Field{
+			{StructName: "Membership", Name: "org_id", Type: "INTEGER"},
+			{StructName: "Membership", Name: "user_id", Type: "INTEGER", Nullable: declaredNullable},
+		},
+	}
+	database := &types.DBSchema{
+		Tables: []types.DBTable{{
+			Name: "memberships",
+			Type: "TABLE",
+			Columns: []types.DBColumn{
+				{Name: "org_id", DataType: "integer", IsNullable: "NO", IsPrimaryKey: true},
+				{Name: "user_id", DataType: "integer", IsNullable: dbNullable, IsPrimaryKey: slices.Contains(databaseKey, "user_id")},
+			},
+		}},
+		Constraints: []types.DBConstraint{{
+			Name:        "memberships_pkey",
+			TableName:   "memberships",
+			Type:        "PRIMARY KEY",
+			ColumnNames: databaseKey,
+		}},
+	}
+	return generated, database
+}
+
+// modifiedColumnChanges returns the changes recorded for table.column, or nil
+// when the column is unchanged.
+func modifiedColumnChanges(diff *difftypes.SchemaDiff, table, column string) map[string]string {
+	for _, tableDiff := range diff.TablesModified {
+		if tableDiff.TableName != table {
+			continue
+		}
+		for _, colDiff := range tableDiff.ColumnsModified {
+			if colDiff.ColumnName == column {
+				return colDiff.Changes
+			}
+		}
+	}
+	return nil
+}
+
+func TestCompareWithDialect_PrimaryKeyMembershipImpliesNullability(t *testing.T) {
+	tests := []struct {
+		name             string
+		declaredKey      []string
+		databaseKey      []string
+		declaredNullable bool
+		dbNullable       string
+		want             map[string]string
+	}{
+		{
+			name:             "joining key sets NOT NULL on a nullable column",
+			declaredKey:      []string{"org_id", "user_id"},
+			databaseKey:      []string{"org_id"},
+			declaredNullable: true,
+			dbNullable:       "YES",
+			want:             map[string]string{"nullable": "true -> false"},
+		},
+		{
+			name:        "joining key leaves a NOT NULL column alone",
+			declaredKey: []string{"org_id", "user_id"},
+			databaseKey: []string{"org_id"},
+			dbNullable:  "NO",
+		},
+		{
+			name:        "leaving key keeps a column declared NOT NULL",
+			declaredKey: []string{"org_id"},
+			databaseKey: []string{"org_id", "user_id"},
+			dbNullable:  "NO",
+		},
+		{
+			name:             "leaving key drops NOT NULL from a nullable column",
+			declaredKey:      []string{"org_id"},
+			databaseKey:      []string{"org_id", "user_id"},
+			declaredNullable: true,
+			dbNullable:       "NO",
+			want:             map[string]string{"nullable": "false -> true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, database := membershipSchemas(tt.declaredKey, tt.databaseKey, tt.declaredNullable, tt.dbNullable)
+
+			diff := schemadiff.CompareWithDialect(generated, database, "postgres")
+
+			c.Assert(modifiedColumnChanges(diff, "memberships", "user_id"), qt.DeepEquals, tt.want)
+			c.Assert(diff.ConstraintsAdded, qt.DeepEquals, []string{"memberships_pkey"})
+			c.Assert(diff.ConstraintsRemoved, qt.DeepEquals, []string{"memberships_pkey"})
+		})
+	}
+}
@@ -28,6 +28,9 @@
 //
 // This function performs comprehensive comparison and returns a detailed difference report.
 //
+// CompareTable diffs a single struct against a single live table with the same
+// rules, for callers that already hold one table, such as a tenant copy.
+//
 // # Comparison Categories
 //
 // The schema comparison covers these main areas:
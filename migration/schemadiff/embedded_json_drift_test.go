@@ -0,0 +1,66 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+)
+
+const embeddedJSONSchema = `package entities
+
+type Settings struct {
+	Theme string
+}
+
+//migrator:schema:table name="profiles"
+type Profile struct {
+	//migrator:schema:field name="id" type="BIGINT" primary="true"
+	ID int64
+
+	//migrator:embedded mode="json" type="JSONB" nullable="true" default_expr="'{}'::jsonb" platform.mysql.type="JSON" platform.mysql.default_expr="(JSON_OBJECT())"
+	Settings Settings
+}
+`
+
+func TestCompare_EmbeddedJSONColumnConverges(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		column   types.DBColumn
+		idColumn types.DBColumn
+	}{
+		{
+			name:     "postgres jsonb with default expression",
+			dialect:  "postgres",
+			idColumn: types.DBColumn{Name: "id", DataType: "bigint", UDTName: "int8", IsNullable: "NO", IsPrimaryKey: true},
+			column:   types.DBColumn{Name: "settings_data", DataType: "jsonb", UDTName: "jsonb", IsNullable: "YES", ColumnDefault: new("'{}'::jsonb")},
+		},
+		{
+			name:     "mysql type and default overrides",
+			dialect:  "mysql",
+			idColumn: types.DBColumn{Name: "id", DataType: "bigint", ColumnType: "bigint", IsNullable: "NO", IsPrimaryKey: true},
+			column:   types.DBColumn{Name: "settings_data", DataType: "json", ColumnType: "json", IsNullable: "YES", ColumnDefault: new("json_object()")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated, err := goschema.ParseSources(map[string][]byte{"profile.go": []byte(embeddedJSONSchema)})
+			c.Assert(err, qt.IsNil)
+			live := &types.DBSchema{Tables: []types.DBTable{{
+				Name:    "profiles",
+				Columns: []types.DBColumn{tt.idColumn, tt.column},
+			}}}
+
+			diff := schemadiff.CompareWithDialect(generated, live, tt.dialect)
+
+			c.Assert(diff.TablesModified, qt.HasLen, 0)
+			c.Assert(diff.HasChanges(), qt.IsFalse)
+		})
+	}
+}
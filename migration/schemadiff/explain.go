@@ -0,0 +1,42 @@
+package schemadiff
+
+import (
+	"fmt"
+	"strings"
+
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// Explain formats the change explanations recorded on diff, one block per
+// column change, showing the raw values, the normalized values they were
+// compared as, and the rule that reported the change. The explanations are
+// only present when the diff was produced with config.CompareOptions.Explain.
+//
+// Example output:
+//
+//	users.email
+//	  type: varchar(100) -> VARCHAR(255)
+//	    compared: varchar -> varchar
+//	    rule: normalized types match; length, precision, or scale differs
+func Explain(diff *difftypes.SchemaDiff) string {
+	var b strings.Builder
+	if diff != nil {
+		for _, table := range diff.TablesModified {
+			for _, column := range table.ColumnsModified {
+				if len(column.Explanations) == 0 {
+					continue
+				}
+				fmt.Fprintf(&b, "%s.%s\n", table.TableName, column.ColumnName)
+				for _, explanation := range column.Explanations {
+					fmt.Fprintf(&b, "  %s: %s -> %s\n", explanation.Change, explanation.Old, explanation.New)
+					fmt.Fprintf(&b, "    compared: %s -> %s\n", explanation.NormalizedOld, explanation.NormalizedNew)
+					fmt.Fprintf(&b, "    rule: %s\n", explanation.Rule)
+				}
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "No column change explanations; compare with config.CompareOptions.Explain set to record them.\n"
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package schemadiff_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func explainFixture() (*goschema.Database, *types.DBSchema) {
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{
+			{StructName: "User", Name: "email", Type: "VARCHAR(255)", Nullable: true},
+			{StructName: "User", Name: "status", Type: "TEXT", Default: "active", Nullable: true},
+		},
+	}
+	database := &types.DBSchema{Tables: []types.DBTable{{
+		Name: "users",
+		Columns: []types.DBColumn{
+			{Name: "email", DataType: "character varying", UDTName: "varchar", CharacterMaxLength: new(100), IsNullable: "YES"},
+			{Name: "status", DataType: "text", UDTName: "text", IsNullable: "YES", ColumnDefault: new("'pending'::text")},
+		},
+	}}}
+	return generated, database
+}
+
+func TestCompareWithOptions_ExplainRecordsNormalizedValues(t *testing.T) {
+	c := qt.New(t)
+	generated, database := explainFixture()
+	opts := config.DefaultCompareOptions()
+	opts.Dialect = "postgres"
+	opts.Explain = true
+
+	diff := schemadiff.CompareWithOptions(generated, database, opts)
+
+	c.Assert(diff.TablesModified, qt.HasLen, 1)
+	columns := diff.TablesModified[0].ColumnsModified
+	c.Assert(columns, qt.HasLen, 2)
+	c.Assert(columns[0].Explanations, qt.DeepEquals, []difftypes.ChangeExplanation{{
+		Change:        "type",
+		Old:           "varchar(100)",
+		New:           "VARCHAR(255)",
+		NormalizedOld: "varchar",
+		NormalizedNew: "varchar",
+		Rule:          "normalized types match; length, precision, or scale differs",
+	}})
+	c.Assert(columns[1].Explanations, qt.DeepEquals, []difftypes.ChangeExplanation{{
+		Change:        "default_expr",
+		Old:           "'pending'::text",
+		New:           "active",
+		NormalizedOld: "pending",
+		NormalizedNew: "active",
+		Rule:          "defaults differ after normalization as text (database) and text (annotation)",
+	}})
+	c.Assert(schemadiff.Explain(diff), qt.Equals, `users.email
+  type: varchar(100) -> VARCHAR(255)
+    compared: varchar -> varchar
+    rule: normalized types match; length, precision, or scale differs
+users.status
+  default_expr: 'pending'::text -> active
+    compared: pending -> active
+    rule: defaults differ after normalization as text (database) and text (annotation)
+`)
+}
+
+func TestCompareWithOptions_ExplainIsOffByDefault(t *testing.T) {
+	c := qt.New(t)
+	generated, database := explainFixture()
+
+	diff := schemadiff.CompareWithDialect(generated, database, "postgres")
+
+	c.Assert(diff.TablesModified[0].ColumnsModified[0].Explanations, qt.IsNil)
+	c.Assert(schemadiff.Explain(diff), qt.Contains, "No column change explanations")
+}
@@ -1,12 +1,19 @@
 package compare
 
 import (
+	"regexp"
 	"strings"
 	"unicode"
 )
 
+// notValidSuffixPattern matches the NOT VALID marker PostgreSQL appends to the
+// definition of a constraint that was added without validating existing rows.
+// Validation state is not part of the desired schema, so it is ignored.
+var notValidSuffixPattern = regexp.MustCompile(`(?i)\s+NOT\s+VALID\s*$`)
+
 func normalizeCheckExpression(expr string) string {
-	expr = trimBalancedCheckParens(strings.TrimSpace(expr))
+	expr = notValidSuffixPattern.ReplaceAllString(strings.TrimSpace(expr), "")
+	expr = trimBalancedCheckParens(expr)
 
 	normalizer := checkExpressionNormalizer{expr: expr}
 	return normalizer.normalize()
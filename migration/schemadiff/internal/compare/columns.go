@@ -1,14 +1,19 @@
 package compare
 
 import (
+	"cmp"
 	"fmt"
+	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform"
 	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/convert/fromschema"
 	"github.com/stokaro/ptah/migration/internal/typechange"
 	"github.com/stokaro/ptah/migration/schemadiff/internal/normalize"
 	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
@@ -93,6 +98,15 @@ func TableColumnsWithDialect(
 	dbTable types.DBTable,
 	generated *goschema.Database,
 	dialect string,
+) difftypes.TableDiff {
+	return tableColumns(genTable, dbTable, generated, &config.CompareOptions{Dialect: dialect})
+}
+
+func tableColumns(
+	genTable goschema.Table,
+	dbTable types.DBTable,
+	generated *goschema.Database,
+	opts *config.CompareOptions,
 ) difftypes.TableDiff {
 	tableDiff := difftypes.TableDiff{TableName: genTable.QualifiedName()}
 
@@ -123,19 +137,37 @@ func TableColumnsWithDialect(
 		}
 	}
 
-	for colName := range dbColumns {
-		if _, exists := genColumns[colName]; !exists {
+	external := make(map[string]bool, len(genTable.ExternalColumns))
+	for _, colName := range genTable.ExternalColumns {
+		external[colName] = true
+		if _, exists := dbColumns[colName]; exists {
+			tableDiff.ColumnsExternal = append(tableDiff.ColumnsExternal, colName)
+		}
+	}
+
+	for colName, dbCol := range dbColumns {
+		// Externally managed columns (for example a trigger-maintained
+		// search vector) may exist without being declared on the entity.
+		if external[colName] {
+			continue
+		}
+		// A column inherited from a PostgreSQL parent table is owned by the
+		// parent; the child cannot drop it and its annotation need not repeat it.
+		if _, exists := genColumns[colName]; !exists && !dbCol.Inherited {
 			tableDiff.ColumnsRemoved = append(tableDiff.ColumnsRemoved, colName)
 		}
 	}
 
 	// Find modified columns
 	for colName, genCol := range genColumns {
-		if dbCol, exists := dbColumns[colName]; exists {
-			if columnInTablePrimaryKey(genTable, genCol.Name) {
-				genCol = normalizeTablePrimaryKeyColumn(genCol, dbCol)
+		if dbCol, exists := dbColumns[colName]; exists && !external[colName] {
+			genCol, keyOwned := tablePrimaryKeyColumn(genTable, genCol)
+			colDiff := columns(genCol, dbCol, opts)
+			if keyOwned {
+				withoutColumnChange(&colDiff, "primary_key")
 			}
-			colDiff := ColumnsWithDialect(genCol, dbCol, dialect)
+			columnComment(&colDiff, genCol, dbCol, opts)
+			customColumnChanges(&colDiff, genCol, dbCol, opts.CustomComparators, opts.Explain)
 			if len(colDiff.Changes) > 0 {
 				tableDiff.ColumnsModified = append(tableDiff.ColumnsModified, colDiff)
 			}
@@ -145,6 +177,7 @@ func TableColumnsWithDialect(
 	// Sort for consistent output
 	sort.Strings(tableDiff.ColumnsAdded)
 	sort.Strings(tableDiff.ColumnsRemoved)
+	sort.Strings(tableDiff.ColumnsExternal)
 	sort.Slice(tableDiff.ColumnsModified, func(i, j int) bool {
 		return tableDiff.ColumnsModified[i].ColumnName < tableDiff.ColumnsModified[j].ColumnName
 	})
@@ -152,6 +185,32 @@ func TableColumnsWithDialect(
 	return tableDiff
 }
 
+// customColumnChanges records the changes reported by custom comparators
+// under their config.CustomChangeKey names, in registration order.
+func customColumnChanges(
+	colDiff *difftypes.ColumnDiff,
+	genCol goschema.Field,
+	dbCol types.DBColumn,
+	comparators []config.NamedComparator,
+	explain bool,
+) {
+	for _, comparator := range comparators {
+		if comparator.Compare == nil {
+			continue
+		}
+		if before, after, changed := comparator.Compare(genCol, dbCol); changed {
+			key := config.CustomChangeKey(comparator.Name)
+			colDiff.Changes[key] = fmt.Sprintf("%s -> %s", before, after)
+			if explain {
+				colDiff.Explanations = append(colDiff.Explanations, difftypes.ChangeExplanation{
+					Change: key, Old: before, New: after, NormalizedOld: before, NormalizedNew: after,
+					Rule: "custom comparator " + comparator.Name,
+				})
+			}
+		}
+	}
+}
+
 // Columns performs detailed property-level comparison between a generated column and database column.
 //
 // This function is the most granular level of schema comparison, analyzing individual
@@ -242,25 +301,65 @@ func Columns(genCol goschema.Field, dbCol types.DBColumn) difftypes.ColumnDiff {
 // ColumnsWithDialect compares two columns using dialect-specific expression
 // normalization where catalog readback rewrites equivalent SQL.
 func ColumnsWithDialect(genCol goschema.Field, dbCol types.DBColumn, dialect string) difftypes.ColumnDiff {
+	return columns(genCol, dbCol, &config.CompareOptions{Dialect: dialect})
+}
+
+// ColumnsWithOptions compares two columns under opts, honoring its dialect,
+// Explain, IgnoreDefaults, and TypeEquivalences settings.
+func ColumnsWithOptions(genCol goschema.Field, dbCol types.DBColumn, opts *config.CompareOptions) difftypes.ColumnDiff {
+	if opts == nil {
+		opts = config.DefaultCompareOptions()
+	}
+	return columns(genCol, dbCol, opts)
+}
+
+// columns compares two columns and, when opts.Explain is set, records a
+// ChangeExplanation for every change it reports.
+func columns(genCol goschema.Field, dbCol types.DBColumn, opts *config.CompareOptions) difftypes.ColumnDiff {
+	dialect, explain := opts.Dialect, opts.Explain
 	colDiff := difftypes.ColumnDiff{
 		ColumnName: genCol.Name,
 		Changes:    make(map[string]string),
 	}
+	record := func(change, oldRaw, newRaw, oldNormalized, newNormalized, rule string) {
+		if explain {
+			colDiff.Explanations = append(colDiff.Explanations, difftypes.ChangeExplanation{
+				Change:        change,
+				Old:           oldRaw,
+				New:           newRaw,
+				NormalizedOld: oldNormalized,
+				NormalizedNew: newNormalized,
+				Rule:          rule,
+			})
+		}
+	}
 
 	// ClickHouse-only guard: older goschema models cannot express
 	// MATERIALIZED / ALIAS / EPHEMERAL columns. Once the schema side carries a
 	// generated expression, compare it normally below.
-	if dbCol.GeneratedKind != "" && genCol.GeneratedExpression == "" {
+	if platform.NormalizeDialect(dialect) == platform.ClickHouse && dbCol.GeneratedKind != "" && genCol.GeneratedExpression == "" {
 		return colDiff
 	}
 
+	genCol.Type = fieldTypeForDialect(genCol, dialect)
 	dbRawType := rawDBColumnType(dbCol)
 	genType, dbType := normalizeColumnTypesForDialect(genCol.Type, dbRawType, dialect)
 
-	if genType != dbType {
+	switch {
+	case declaredEquivalentTypes(genCol.Type, dbRawType, dialect, opts.TypeEquivalences):
+		// Declared interchangeable through CompareOptions.TypeEquivalences.
+	case !strings.EqualFold(genType, dbType):
 		colDiff.Changes["type"] = fmt.Sprintf("%s -> %s", dbType, genType)
-	} else if shouldReportNarrowingTypeChange(dbRawType, genCol.Type, dialect) {
+		record("type", dbRawType, genCol.Type, dbType, genType, "normalized types differ")
+	case shouldReportNarrowingTypeChange(dbRawType, genCol.Type, dialect):
+		colDiff.Changes["type"] = fmt.Sprintf("%s -> %s", dbRawType, genCol.Type)
+		record("type", dbRawType, genCol.Type, dbType, genType, "normalized types match; the declared type is narrower")
+	case shouldReportTypeParameterChange(dbRawType, genCol.Type, dialect):
 		colDiff.Changes["type"] = fmt.Sprintf("%s -> %s", dbRawType, genCol.Type)
+		record("type", dbRawType, genCol.Type, dbType, genType, "normalized types match; length, precision, or scale differs")
+	}
+	if colDiff.Changes["type"] != "" {
+		colDiff.TypeChangeKind = difftypes.TypeChangeKind(typechange.Classify(dbRawType, genCol.Type, dialect))
 	}
 
 	// Compare nullable (primary keys are always NOT NULL regardless of the field definition)
@@ -271,6 +370,11 @@ func ColumnsWithDialect(genCol goschema.Field, dbCol types.DBColumn, dialect str
 	dbNullable := dbCol.IsNullable == "YES"
 	if genNullable != dbNullable {
 		colDiff.Changes["nullable"] = fmt.Sprintf("%t -> %t", dbNullable, genNullable)
+		rule := "nullability differs"
+		if genCol.Primary && genCol.Nullable {
+			rule = "primary key columns are NOT NULL"
+		}
+		record("nullable", "is_nullable="+dbCol.IsNullable, fmt.Sprintf("nullable=%t", genCol.Nullable), strconv.FormatBool(dbNullable), strconv.FormatBool(genNullable), rule)
 	}
 
 	// Compare primary key
@@ -278,6 +382,7 @@ func ColumnsWithDialect(genCol goschema.Field, dbCol types.DBColumn, dialect str
 	dbPrimary := dbCol.IsPrimaryKey
 	if genPrimary != dbPrimary {
 		colDiff.Changes["primary_key"] = fmt.Sprintf("%t -> %t", dbPrimary, genPrimary)
+		record("primary_key", strconv.FormatBool(dbPrimary), strconv.FormatBool(genPrimary), strconv.FormatBool(dbPrimary), strconv.FormatBool(genPrimary), "primary key flag differs")
 	}
 
 	// Compare unique
@@ -285,16 +390,30 @@ func ColumnsWithDialect(genCol goschema.Field, dbCol types.DBColumn, dialect str
 	dbUnique := dbCol.IsUnique
 	if genUnique != dbUnique {
 		colDiff.Changes["unique"] = fmt.Sprintf("%t -> %t", dbUnique, genUnique)
+		record("unique", strconv.FormatBool(dbUnique), strconv.FormatBool(genUnique), strconv.FormatBool(dbUnique), strconv.FormatBool(genUnique), "unique flag differs")
+	}
+	if diff := identityColumnDiff(genCol, dbCol, dialect); diff != "" {
+		colDiff.Changes["identity"] = diff
+		oldIdentity, newIdentity, _ := strings.Cut(diff, " -> ")
+		record("identity", oldIdentity, newIdentity, oldIdentity, newIdentity, "identity generation differs")
+	}
+	for _, option := range identityOptionDiffs(genCol, dbCol, dialect) {
+		colDiff.Changes[option.change] = option.before + " -> " + option.after
+		record(option.change, option.before, option.after, option.before, option.after, "identity sequence option differs")
 	}
 	if diff := generatedColumnDiff(genCol, dbCol, dialect); diff != "" {
 		colDiff.Changes["generated"] = diff
+		oldGenerated, newGenerated, _ := strings.Cut(diff, " -> ")
+		record("generated", oldGenerated, newGenerated, oldGenerated, newGenerated, "generated expression or kind differs")
+	}
+	if diff := updateExpressionDiff(genCol, dbCol, dialect); diff != "" {
+		colDiff.Changes["on_update"] = diff
+		record("on_update", dbCol.UpdateExpression, genCol.UpdateExpression,
+			normalizeUpdateExpression(dbCol.UpdateExpression), normalizeUpdateExpression(genCol.UpdateExpression), "ON UPDATE expression differs")
 	}
 
 	// Compare default values (simplified)
-	genDefault := genCol.Default
-	if genDefault == "" {
-		genDefault = genCol.DefaultExpr
-	}
+	genDefault := fieldDefaultForDialect(genCol, dialect)
 	dbDefault := ""
 	if dbCol.ColumnDefault != nil {
 		dbDefault = *dbCol.ColumnDefault
@@ -308,10 +427,18 @@ func ColumnsWithDialect(genCol goschema.Field, dbCol types.DBColumn, dialect str
 	// default_expr="nextval('seq')"), compare it normally; normalize.DefaultValue
 	// reconciles the ::regclass read-back form (issue #675). The genDefault==""
 	// guard alone carries the feature, so a genuine sequence default that the
-	// model does not declare is still reported as drift.
+	// model does not declare is still reported as drift. Identity columns draw
+	// from their own sequence the same way, and the identity comparison above
+	// already covers a switch between SERIAL and identity.
 	skipImplicitSequenceDefault := genDefault == "" &&
-		(dbCol.IsAutoIncrement || strings.Contains(strings.ToUpper(genCol.Type), "SERIAL"))
-	if !skipImplicitSequenceDefault {
+		(dbCol.IsAutoIncrement || dbCol.IdentityGeneration != "" || genCol.IdentityGeneration != "" ||
+			strings.Contains(strings.ToUpper(genCol.Type), "SERIAL"))
+	if diff := serialColumnDiff(genCol, dbCol, genDefault, dbRawType, dialect); diff != "" {
+		colDiff.Changes["serial"] = diff
+		oldSerial, newSerial, _ := strings.Cut(diff, " -> ")
+		record("serial", dbDefault, genCol.Type, oldSerial, newSerial, "SERIAL sequence default differs")
+	}
+	if !skipImplicitSequenceDefault && !opts.IgnoreDefaults {
 		normalizedDbDefault := normalize.DefaultValue(dbDefault, dbType)
 
 		idxName := "default"
@@ -323,29 +450,172 @@ func ColumnsWithDialect(genCol goschema.Field, dbCol types.DBColumn, dialect str
 
 		if normalizeGenDefaultFn != normalizedDbDefault {
 			colDiff.Changes[idxName] = fmt.Sprintf("%s -> %s", dbDefault, genDefault)
+			record(idxName, dbDefault, genDefault, normalizedDbDefault, normalizeGenDefaultFn, defaultRule(genCol, dialect, dbType, genType))
 		}
 	}
 
 	return colDiff
 }
 
+// serialColumnDiff reports a PostgreSQL column switching between SERIAL and
+// its plain integer type, e.g. "serial -> integer". An auto_increment field
+// counts as SERIAL on the desired side. The type comparison folds
+// SERIAL into its integer type and the sequence default is skipped above, so
+// without this the switch would go unnoticed. Identity columns and columns
+// declaring their own default are compared elsewhere.
+func serialColumnDiff(genCol goschema.Field, dbCol types.DBColumn, genDefault, dbRawType, dialect string) string {
+	if platform.NormalizeDialect(dialect) != platform.Postgres || genDefault != "" ||
+		genCol.IdentityGeneration != "" || dbCol.IdentityGeneration != "" {
+		return ""
+	}
+	genType := strings.ToLower(strings.TrimSpace(genCol.Type))
+	dbType := strings.ToLower(cmp.Or(dbCol.DataType, dbRawType))
+	genSerial := genCol.AutoInc || strings.Contains(genType, "serial") || strings.Contains(genType, "auto_increment")
+	dbSerial := dbCol.IsAutoIncrement
+	if genSerial == dbSerial {
+		return ""
+	}
+	if dbSerial {
+		return serialTypeFor(dbType) + " -> " + genType
+	}
+	return dbType + " -> " + genType
+}
+
+// serialTypeFor names the SERIAL pseudo-type backed by an integer type.
+func serialTypeFor(integerType string) string {
+	switch integerType {
+	case "smallint", "int2":
+		return "smallserial"
+	case "bigint", "int8":
+		return "bigserial"
+	default:
+		return "serial"
+	}
+}
+
+// defaultRule describes how a default comparison was made, naming the
+// dialect override when one supplied the desired value.
+func defaultRule(genCol goschema.Field, dialect, dbType, genType string) string {
+	rule := fmt.Sprintf("defaults differ after normalization as %s (database) and %s (annotation)", dbType, genType)
+	overrides := genCol.Overrides[strings.ToLower(dialect)]
+	_, hasDefault := overrides["default"]
+	_, hasDefaultExpr := overrides["default_expr"]
+	if hasDefault || hasDefaultExpr {
+		rule += fmt.Sprintf("; annotation default from platform.%s override", strings.ToLower(dialect))
+	}
+	return rule
+}
+
+// fieldTypeForDialect returns the column type the renderer emits for genCol
+// on dialect: a platform.<dialect>.type override wins over the declared type.
+func fieldTypeForDialect(genCol goschema.Field, dialect string) string {
+	if override := genCol.Overrides[strings.ToLower(dialect)]["type"]; override != "" {
+		return override
+	}
+	return genCol.Type
+}
+
+// fieldDefaultForDialect returns the default the renderer emits for genCol on
+// dialect: a platform.<dialect>.default or platform.<dialect>.default_expr
+// override wins over the generic default and default_expr, and a generic
+// UUID generator is translated the way fromschema renders it (UUID() on MySQL).
+func fieldDefaultForDialect(genCol goschema.Field, dialect string) string {
+	overrides := genCol.Overrides[strings.ToLower(dialect)]
+	if expr, ok := overrides["default_expr"]; ok {
+		return expr
+	}
+	if value, ok := overrides["default"]; ok {
+		return value
+	}
+	if genCol.Default != "" {
+		return genCol.Default
+	}
+	return fromschema.PlatformDefaultExpr(genCol.DefaultExpr, fieldTypeForDialect(genCol, dialect), platform.NormalizeDialect(dialect))
+}
+
 func normalizeColumnTypesForDialect(genType, dbType, dialect string) (generatedType, databaseType string) {
 	switch platform.NormalizeDialect(dialect) {
 	case platform.SQLite:
 		return normalize.Type(sqliteRenderedColumnType(genType)), normalize.Type(dbType)
+	case platform.MariaDB:
+		return normalize.Type(mariaDBRenderedColumnType(genType)), normalize.Type(dbType)
+	case platform.MySQL:
+		return normalize.Type(mysqlRenderedColumnType(genType)), normalize.Type(dbType)
 	default:
 		return normalize.Type(genType), normalize.Type(dbType)
 	}
 }
 
+// declaredEquivalentTypes reports whether the TypeEquivalences rules make the
+// two types equal. The rules rewrite both sides alike; when neither side
+// matches a rule, the built-in comparison decides alone.
+func declaredEquivalentTypes(genType, dbType, dialect string, equivalences map[string]string) bool {
+	mappedGen, genMapped := equivalentType(genType, equivalences)
+	mappedDB, dbMapped := equivalentType(dbType, equivalences)
+	if !genMapped && !dbMapped {
+		return false
+	}
+	normalizedGen, normalizedDB := normalizeColumnTypesForDialect(mappedGen, mappedDB, dialect)
+	return strings.EqualFold(normalizedGen, normalizedDB) &&
+		!shouldReportNarrowingTypeChange(mappedDB, mappedGen, dialect) &&
+		!shouldReportTypeParameterChange(mappedDB, mappedGen, dialect)
+}
+
+func equivalentType(rawType string, equivalences map[string]string) (string, bool) {
+	for typ, equivalent := range equivalences {
+		if strings.EqualFold(strings.TrimSpace(typ), strings.TrimSpace(rawType)) {
+			return equivalent, true
+		}
+	}
+	return rawType, false
+}
+
 func shouldReportNarrowingTypeChange(dbType, genType, dialect string) bool {
 	if platform.NormalizeDialect(dialect) == platform.SQLite &&
-		normalize.Type(dbType) == normalize.Type(sqliteRenderedColumnType(genType)) {
+		strings.EqualFold(normalize.Type(dbType), normalize.Type(sqliteRenderedColumnType(genType))) {
 		return false
 	}
 	return typechange.IsNarrowing(dbType, genType)
 }
 
+// shouldReportTypeParameterChange reports a length, precision, or scale
+// change that normalize.Type folds away, such as VARCHAR(100) ->
+// VARCHAR(255). Decimal types are compared in their canonical form, so an
+// omitted precision counts as what the dialect stores for it. SQLite ignores
+// declared lengths, so it never reports one.
+func shouldReportTypeParameterChange(dbType, genType, dialect string) bool {
+	if platform.NormalizeDialect(dialect) == platform.SQLite {
+		return false
+	}
+	dbNumeric, dbOK := normalize.NumericType(dbType, dialect)
+	genNumeric, genOK := normalize.NumericType(genType, dialect)
+	if dbOK && genOK {
+		return dbNumeric != genNumeric
+	}
+	return typechange.ParametersDiffer(dbType, genType)
+}
+
+// mysqlRenderedColumnType returns the type MySQL reports for a generated
+// column type. MySQL has no UUID type, so a UUID field renders as CHAR(36).
+func mysqlRenderedColumnType(rawType string) string {
+	if strings.EqualFold(strings.TrimSpace(rawType), "UUID") {
+		return "CHAR(36)"
+	}
+	return rawType
+}
+
+// mariaDBRenderedColumnType returns the type MariaDB reports for a generated
+// column type. JSON is an alias for LONGTEXT there (the json_valid CHECK is
+// what marks the column), so a JSON or JSONB field reads back as longtext.
+func mariaDBRenderedColumnType(rawType string) string {
+	switch strings.ToUpper(strings.TrimSpace(rawType)) {
+	case "JSON", "JSONB":
+		return "longtext"
+	default:
+		return rawType
+	}
+}
+
 func sqliteRenderedColumnType(rawType string) string {
 	upper := strings.ToUpper(strings.TrimSpace(rawType))
 	base := upper
@@ -370,14 +640,30 @@ func sqliteRenderedColumnType(rawType string) string {
 	}
 }
 
-func normalizeTablePrimaryKeyColumn(genCol goschema.Field, dbCol types.DBColumn) goschema.Field {
-	genCol.Nullable = false
-	genCol.Primary = dbCol.IsPrimaryKey
-	return genCol
+// tablePrimaryKeyColumn marks genCol primary exactly when it belongs to the
+// table-level primary key of table, and reports whether table declares one.
+//
+// Nullability follows membership: a member is NOT NULL whatever the field
+// declares, so joining the key implies SET NOT NULL on a nullable column,
+// while a column leaving the key is compared by its declared nullability, so
+// a field still declared NOT NULL needs no change. Membership itself is
+// compared as the PRIMARY KEY constraint, so the caller drops the column's
+// primary_key change when the table declares a key.
+func tablePrimaryKeyColumn(table goschema.Table, genCol goschema.Field) (goschema.Field, bool) {
+	columns := tablePrimaryKeyColumns(table)
+	if len(columns) == 0 {
+		return genCol, false
+	}
+	genCol.Primary = slices.Contains(columns, genCol.Name)
+	return genCol, true
 }
 
-func columnInTablePrimaryKey(table goschema.Table, column string) bool {
-	return slices.Contains(tablePrimaryKeyColumns(table), column)
+// withoutColumnChange removes change and its explanations from colDiff.
+func withoutColumnChange(colDiff *difftypes.ColumnDiff, change string) {
+	delete(colDiff.Changes, change)
+	colDiff.Explanations = slices.DeleteFunc(colDiff.Explanations, func(explanation difftypes.ChangeExplanation) bool {
+		return explanation.Change == change
+	})
 }
 
 func tablePrimaryKeyColumns(table goschema.Table) []string {
@@ -393,6 +679,79 @@ func tablePrimaryKeyColumns(table goschema.Table) []string {
 	return columns
 }
 
+// identityColumnDiff compares PostgreSQL identity generation as
+// "old -> new", writing NONE for a column that is not an identity column.
+// Other dialects model auto-increment differently and are not compared.
+func identityColumnDiff(genCol goschema.Field, dbCol types.DBColumn, dialect string) string {
+	if !platform.IsPostgresFamily(dialect) {
+		return ""
+	}
+	genIdentity := identityGenerationOrNone(genCol.IdentityGeneration)
+	dbIdentity := identityGenerationOrNone(dbCol.IdentityGeneration)
+	if genIdentity == dbIdentity {
+		return ""
+	}
+	return fmt.Sprintf("%s -> %s", dbIdentity, genIdentity)
+}
+
+// identityOption is one differing identity sequence option.
+type identityOption struct {
+	change, before, after string
+}
+
+// identityOptionPatterns extract START WITH and INCREMENT BY from the raw
+// identity_options annotation.
+var identityOptionPatterns = map[string]*regexp.Regexp{
+	"identity_start":     regexp.MustCompile(`(?i)\bSTART\s+(?:WITH\s+)?([+-]?\d+)`),
+	"identity_increment": regexp.MustCompile(`(?i)\bINCREMENT\s+(?:BY\s+)?([+-]?\d+)`),
+}
+
+// identityOptionDiffs compares the START WITH and INCREMENT BY options
+// declared on a PostgreSQL identity column with those of the sequence the
+// column owns. Only columns that are identity columns on both sides are
+// compared, and an option the field does not declare is not compared, so the
+// sequence defaults never show up as drift.
+func identityOptionDiffs(genCol goschema.Field, dbCol types.DBColumn, dialect string) []identityOption {
+	if !platform.IsPostgresFamily(dialect) || genCol.IdentityGeneration == "" || dbCol.IdentityGeneration == "" {
+		return nil
+	}
+	var diffs []identityOption
+	for _, option := range []identityOption{
+		{change: "identity_start", before: dbCol.IdentityStart, after: genCol.IdentityStart},
+		{change: "identity_increment", before: dbCol.IdentityIncrement, after: genCol.IdentityIncrement},
+	} {
+		if option.after == "" {
+			if match := identityOptionPatterns[option.change].FindStringSubmatch(genCol.IdentityOptions); match != nil {
+				option.after = match[1]
+			}
+		}
+		if option.before == "" || option.after == "" || sameIdentityOption(option.before, option.after) {
+			continue
+		}
+		diffs = append(diffs, option)
+	}
+	return diffs
+}
+
+// sameIdentityOption compares two sequence option values as integers,
+// falling back to their trimmed text.
+func sameIdentityOption(a, b string) bool {
+	x, errA := strconv.ParseInt(strings.TrimSpace(a), 10, 64)
+	y, errB := strconv.ParseInt(strings.TrimSpace(b), 10, 64)
+	if errA != nil || errB != nil {
+		return strings.TrimSpace(a) == strings.TrimSpace(b)
+	}
+	return x == y
+}
+
+func identityGenerationOrNone(generation string) string {
+	generation = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(generation), " ", "_"))
+	if generation == "" {
+		return "NONE"
+	}
+	return generation
+}
+
 func generatedColumnDiff(genCol goschema.Field, dbCol types.DBColumn, dialect string) string {
 	genExpr := normalizeGeneratedExpression(genCol.GeneratedExpression, dialect)
 	dbExpr := ""
@@ -407,6 +766,40 @@ func generatedColumnDiff(genCol goschema.Field, dbCol types.DBColumn, dialect st
 	return fmt.Sprintf("%s %s -> %s %s", dbKind, dbExpr, genKind, genExpr)
 }
 
+// updateExpressionDiff compares the MySQL/MariaDB column ON UPDATE clause.
+// Other dialects have no such clause, so it is never reported for them.
+func updateExpressionDiff(genCol goschema.Field, dbCol types.DBColumn, dialect string) string {
+	switch platform.NormalizeDialect(dialect) {
+	case platform.MySQL, platform.MariaDB:
+	default:
+		return ""
+	}
+	genExpr := normalizeUpdateExpression(genCol.UpdateExpression)
+	dbExpr := normalizeUpdateExpression(dbCol.UpdateExpression)
+	if genExpr == dbExpr {
+		return ""
+	}
+	return fmt.Sprintf("%s -> %s", cmp.Or(dbExpr, "none"), cmp.Or(genExpr, "none"))
+}
+
+// normalizeUpdateExpression folds the spellings of the current timestamp:
+// MariaDB reads back current_timestamp(), and NOW() and LOCALTIMESTAMP are
+// synonyms, while a fractional-seconds precision is kept.
+func normalizeUpdateExpression(expression string) string {
+	expression = strings.ToLower(strings.Join(strings.Fields(expression), ""))
+	for _, synonym := range []string{"now", "localtimestamp", "current_timestamp"} {
+		rest, ok := strings.CutPrefix(expression, synonym)
+		if !ok {
+			continue
+		}
+		if rest == "()" {
+			rest = ""
+		}
+		return "current_timestamp" + rest
+	}
+	return expression
+}
+
 func normalizeGeneratedExpression(expression, dialect string) string {
 	expression = normalize.Expression(expression)
 	switch platform.NormalizeDialect(dialect) {
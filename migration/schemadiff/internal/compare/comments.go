@@ -0,0 +1,101 @@
+package compare
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform/capability"
+	"github.com/stokaro/ptah/dbschema/types"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// commentsCompared reports whether comments are compared for dialect. Only
+// targets that set comments with standalone COMMENT ON statements are
+// covered: their readers report column comments and their planner can apply
+// a comment change without touching the column definition.
+func commentsCompared(dialect string) bool {
+	return capability.ForDialect(dialect).Has(capability.CommentOn)
+}
+
+// TableComment records a table comment change in tableDiff.Comment as
+// "old -> new". The generated side honors a platform.<dialect>.comment
+// override.
+func TableComment(genTable goschema.Table, dbTable types.DBTable, tableDiff *difftypes.TableDiff, dialect string) {
+	if !commentsCompared(dialect) {
+		return
+	}
+	genComment := genTable.Comment
+	if override, ok := genTable.Overrides[strings.ToLower(dialect)]["comment"]; ok {
+		genComment = override
+	}
+	if genComment != dbTable.Comment {
+		tableDiff.Comment = fmt.Sprintf("%s -> %s", quoteComment(dbTable.Comment), quoteComment(genComment))
+	}
+}
+
+// columnComment records a column comment change under Changes["comment"].
+// With opts.StructuredComments, comments that both parse as key=value sets
+// are compared as sets.
+func columnComment(colDiff *difftypes.ColumnDiff, genCol goschema.Field, dbCol types.DBColumn, opts *config.CompareOptions) {
+	if !commentsCompared(opts.Dialect) {
+		return
+	}
+	genComment := genCol.Comment
+	if override, ok := genCol.Overrides[strings.ToLower(opts.Dialect)]["comment"]; ok {
+		genComment = override
+	}
+	if genComment == dbCol.Comment {
+		return
+	}
+	rule := "comments differ"
+	if opts.StructuredComments {
+		dbPairs, dbOK := parseStructuredComment(dbCol.Comment)
+		genPairs, genOK := parseStructuredComment(genComment)
+		if dbOK && genOK {
+			if maps.Equal(dbPairs, genPairs) {
+				return
+			}
+			rule = "structured comments differ"
+		}
+	}
+	before, after := quoteComment(dbCol.Comment), quoteComment(genComment)
+	colDiff.Changes["comment"] = fmt.Sprintf("%s -> %s", before, after)
+	if opts.Explain {
+		colDiff.Explanations = append(colDiff.Explanations, difftypes.ChangeExplanation{
+			Change: "comment", Old: dbCol.Comment, New: genComment, NormalizedOld: before, NormalizedNew: after,
+			Rule: rule,
+		})
+	}
+}
+
+// parseStructuredComment parses a comment written as semicolon-separated
+// key=value pairs, e.g. "a=1;b=2", ignoring whitespace around keys and values
+// and empty segments. It reports false for anything else, including a pair
+// without "=", an empty key, or a repeated key.
+func parseStructuredComment(comment string) (map[string]string, bool) {
+	pairs := make(map[string]string)
+	for segment := range strings.SplitSeq(comment, ";") {
+		if strings.TrimSpace(segment) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(segment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, false
+		}
+		if _, duplicate := pairs[key]; duplicate {
+			return nil, false
+		}
+		pairs[key] = strings.TrimSpace(value)
+	}
+	return pairs, true
+}
+
+// quoteComment renders a comment as a SQL string literal for change
+// descriptions, so an absent comment reads as an empty literal.
+func quoteComment(comment string) string {
+	return "'" + strings.ReplaceAll(comment, "'", "''") + "'"
+}
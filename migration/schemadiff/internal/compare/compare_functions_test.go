@@ -221,3 +221,28 @@ func TestFunctions_PopulatesModifiedList(t *testing.T) {
 	c.Assert(diff.FunctionsModified[0].FunctionName, qt.Equals, "f")
 	c.Assert(diff.FunctionsModified[0].Changes["body"], qt.Not(qt.Equals), "")
 }
+
+func TestFunctions_RecordsTriggersOfRemovedFunctions(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{Functions: []goschema.Function{{Name: "kept_fn"}}}
+	database := &dbtypes.DBSchema{Functions: []dbtypes.DBFunction{
+		{Name: "kept_fn", Triggers: []dbtypes.DBFunctionTrigger{{Name: "kept_trg", Table: "users"}}},
+		{Name: "audit_fn", Triggers: []dbtypes.DBFunctionTrigger{
+			{Name: "orders_audit", Table: "orders"},
+			{Name: "accounts_audit", Schema: "billing", Table: "accounts"},
+		}},
+		{Name: "unused_fn"},
+	}}
+	diff := &difftypes.SchemaDiff{}
+
+	compare.Functions(generated, database, diff)
+
+	c.Assert(diff.FunctionsRemoved, qt.DeepEquals, []string{"audit_fn", "unused_fn"})
+	c.Assert(diff.FunctionTriggers, qt.DeepEquals, map[string][]difftypes.TriggerRef{
+		"audit_fn": {
+			{TriggerName: "accounts_audit", TableName: "billing.accounts"},
+			{TriggerName: "orders_audit", TableName: "orders"},
+		},
+	})
+}
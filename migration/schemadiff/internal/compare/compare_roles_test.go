@@ -5,6 +5,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/migration/schemadiff/internal/compare"
@@ -18,7 +19,7 @@ func TestRolesComparison(t *testing.T) {
 		database := &types.DBSchema{Roles: []types.DBRole{}}
 		diff := &difftypes.SchemaDiff{}
 
-		compare.Roles(generated, database, diff)
+		compare.Roles(generated, database, diff, nil)
 
 		c.Assert(diff.RolesAdded, qt.HasLen, 0)
 		c.Assert(diff.RolesRemoved, qt.HasLen, 0)
@@ -36,7 +37,7 @@ func TestRolesComparison(t *testing.T) {
 		database := &types.DBSchema{Roles: []types.DBRole{}}
 		diff := &difftypes.SchemaDiff{}
 
-		compare.Roles(generated, database, diff)
+		compare.Roles(generated, database, diff, nil)
 
 		c.Assert(diff.RolesAdded, qt.HasLen, 2)
 		c.Assert(diff.RolesAdded, qt.Contains, "app_user")
@@ -56,7 +57,7 @@ func TestRolesComparison(t *testing.T) {
 		}
 		diff := &difftypes.SchemaDiff{}
 
-		compare.Roles(generated, database, diff)
+		compare.Roles(generated, database, diff, nil)
 
 		// Roles should not be automatically removed for safety
 		c.Assert(diff.RolesAdded, qt.HasLen, 0)
@@ -78,7 +79,7 @@ func TestRolesComparison(t *testing.T) {
 		}
 		diff := &difftypes.SchemaDiff{}
 
-		compare.Roles(generated, database, diff)
+		compare.Roles(generated, database, diff, nil)
 
 		c.Assert(diff.RolesAdded, qt.HasLen, 0)
 		c.Assert(diff.RolesRemoved, qt.HasLen, 0)
@@ -107,7 +108,7 @@ func TestRolesComparison(t *testing.T) {
 		}
 		diff := &difftypes.SchemaDiff{}
 
-		compare.Roles(generated, database, diff)
+		compare.Roles(generated, database, diff, nil)
 
 		c.Assert(diff.RolesAdded, qt.HasLen, 1)
 		c.Assert(diff.RolesAdded[0], qt.Equals, "new_role")
@@ -138,7 +139,7 @@ func TestRolesComparison(t *testing.T) {
 		}
 		diff := &difftypes.SchemaDiff{}
 
-		compare.Roles(generated, database, diff)
+		compare.Roles(generated, database, diff, nil)
 
 		// Check added roles are sorted
 		c.Assert(diff.RolesAdded, qt.DeepEquals, []string{"a_role", "z_role"})
@@ -268,6 +269,142 @@ func TestRoleDefinitionsComparison(t *testing.T) {
 	})
 }
 
+func TestRoleDefinitionsPasswordVerification(t *testing.T) {
+	const storedVerifier = "SCRAM-SHA-256$4096:c2FsdA==$c3RvcmVk:c2VydmVy"
+	tests := []struct {
+		name     string
+		role     goschema.Role
+		database types.DBRole
+		want     map[string]string
+	}{
+		{
+			name:     "matching SCRAM verifier",
+			role:     goschema.Role{Name: "app_user", Password: storedVerifier},
+			database: types.DBRole{Name: "app_user", HasPassword: true, PasswordHash: storedVerifier},
+			want:     map[string]string{},
+		},
+		{
+			name:     "different SCRAM verifier",
+			role:     goschema.Role{Name: "app_user", Password: "SCRAM-SHA-256$4096:c2FsdA==$bmV3:c2VydmVy"},
+			database: types.DBRole{Name: "app_user", HasPassword: true, PasswordHash: storedVerifier},
+			want:     map[string]string{"password": "password_update_required"},
+		},
+		{
+			name:     "SCRAM verifier without a readable stored verifier",
+			role:     goschema.Role{Name: "app_user", Password: "SCRAM-SHA-256$4096:c2FsdA==$bmV3:c2VydmVy"},
+			database: types.DBRole{Name: "app_user", HasPassword: true},
+			want:     map[string]string{},
+		},
+		{
+			name:     "plaintext password is not compared with the stored verifier",
+			role:     goschema.Role{Name: "app_user", Password: "s3cret"},
+			database: types.DBRole{Name: "app_user", HasPassword: true, PasswordHash: storedVerifier},
+			want:     map[string]string{},
+		},
+		{
+			name:     "environment password on a role without a password",
+			role:     goschema.Role{Name: "app_user", PasswordEnv: "APP_USER_PASSWORD"},
+			database: types.DBRole{Name: "app_user"},
+			want:     map[string]string{"password": "password_update_required"},
+		},
+		{
+			name:     "environment password on a role with a password",
+			role:     goschema.Role{Name: "app_user", PasswordEnv: "APP_USER_PASSWORD"},
+			database: types.DBRole{Name: "app_user", HasPassword: true, PasswordHash: storedVerifier},
+			want:     map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			diff := compare.RoleDefinitions(tt.role, tt.database)
+
+			c.Assert(diff.Changes, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+func TestRolesComparisonSkipsPasswords(t *testing.T) {
+	c := qt.New(t)
+	generated := &goschema.Database{Roles: []goschema.Role{
+		{Name: "app_user", Login: true, PasswordEnv: "APP_USER_PASSWORD"},
+		{Name: "reporter", Password: "s3cret"},
+	}}
+	database := &types.DBSchema{Roles: []types.DBRole{
+		{Name: "app_user"},
+		{Name: "reporter"},
+	}}
+	diff := &difftypes.SchemaDiff{}
+
+	compare.Roles(generated, database, diff, config.WithSkipRolePasswords(true))
+
+	c.Assert(diff.RolesModified, qt.DeepEquals, []difftypes.RoleDiff{
+		{RoleName: "app_user", Changes: map[string]string{"login": "false -> true"}},
+	})
+}
+
+func TestRolesComparisonMemberships(t *testing.T) {
+	tests := []struct {
+		name        string
+		generated   []goschema.Role
+		database    []types.DBRole
+		opts        *config.CompareOptions
+		wantAdded   []difftypes.RoleMembership
+		wantRemoved []difftypes.RoleMembership
+	}{
+		{
+			name:      "new role gets its memberships",
+			generated: []goschema.Role{{Name: "app_user", MemberOf: []string{"app_readonly", "app_audit"}}},
+			wantAdded: []difftypes.RoleMembership{
+				{Role: "app_audit", Member: "app_user"},
+				{Role: "app_readonly", Member: "app_user"},
+			},
+		},
+		{
+			name:      "missing membership on existing role",
+			generated: []goschema.Role{{Name: "app_user", MemberOf: []string{"app_readonly", "app_audit"}}},
+			database:  []types.DBRole{{Name: "app_user", MemberOf: []string{"app_readonly"}}},
+			wantAdded: []difftypes.RoleMembership{{Role: "app_audit", Member: "app_user"}},
+		},
+		{
+			name:      "undeclared membership is kept by default",
+			generated: []goschema.Role{{Name: "app_user", MemberOf: []string{"app_readonly"}}},
+			database:  []types.DBRole{{Name: "app_user", MemberOf: []string{"app_readonly", "dba_granted"}}},
+		},
+		{
+			name:        "undeclared membership is revoked when enabled",
+			generated:   []goschema.Role{{Name: "app_user", MemberOf: []string{"app_readonly"}}},
+			database:    []types.DBRole{{Name: "app_user", MemberOf: []string{"app_readonly", "dba_granted"}}},
+			opts:        &config.CompareOptions{RevokeUndeclaredRoleMemberships: true},
+			wantRemoved: []difftypes.RoleMembership{{Role: "dba_granted", Member: "app_user"}},
+		},
+		{
+			name:      "roles without annotations are ignored",
+			generated: []goschema.Role{{Name: "app_user"}},
+			database: []types.DBRole{
+				{Name: "app_user"},
+				{Name: "legacy_user", MemberOf: []string{"app_readonly"}},
+			},
+			opts: &config.CompareOptions{RevokeUndeclaredRoleMemberships: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			diff := &difftypes.SchemaDiff{}
+
+			compare.Roles(&goschema.Database{Roles: tt.generated}, &types.DBSchema{Roles: tt.database}, diff, tt.opts)
+
+			c.Assert(diff.RoleMembershipsAdded, qt.DeepEquals, tt.wantAdded)
+			c.Assert(diff.RoleMembershipsRemoved, qt.DeepEquals, tt.wantRemoved)
+			c.Assert(diff.RolesModified, qt.HasLen, 0)
+		})
+	}
+}
+
 func TestGrantsComparison(t *testing.T) {
 	t.Run("adds table and schema grants", func(t *testing.T) {
 		c := qt.New(t)
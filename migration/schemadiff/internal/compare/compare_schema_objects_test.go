@@ -123,6 +123,30 @@ func TestViews_DetectsExplicitQualifierChange(t *testing.T) {
 	c.Assert(diff.ViewsModified[0].Changes["body"], qt.Not(qt.Equals), "")
 }
 
+func TestTablesAndColumns_NeverRemovesMaterializedViewsReadAsTables(t *testing.T) {
+	c := qt.New(t)
+	diff := &difftypes.SchemaDiff{}
+	generated := &goschema.Database{
+		MaterializedViews: []goschema.MaterializedView{{Name: "user_stats", Body: "SELECT 1"}},
+	}
+	database := &dbschematypes.DBSchema{
+		Tables: []dbschematypes.DBTable{
+			{Name: "user_stats", Type: "BASE TABLE"},
+			{Name: "order_totals", Type: "BASE TABLE"},
+		},
+		MatViews: []dbschematypes.DBMatView{
+			{Name: "user_stats", Body: "SELECT 1"},
+			{Name: "order_totals", Body: "SELECT 2"},
+		},
+	}
+
+	compare.TablesAndColumns(generated, database, diff)
+	compare.MaterializedViews(generated, database, diff)
+
+	c.Assert(diff.TablesRemoved, qt.HasLen, 0)
+	c.Assert(diff.MaterializedViewsRemoved, qt.DeepEquals, []string{"order_totals"})
+}
+
 func TestMaterializedViews_DetectsBodyChange(t *testing.T) {
 	c := qt.New(t)
 	diff := &difftypes.SchemaDiff{}
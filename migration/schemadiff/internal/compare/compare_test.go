@@ -5,6 +5,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/migration/schemadiff/internal/compare"
@@ -304,6 +305,443 @@ func TestColumns_HappyPath(t *testing.T) {
 	}
 }
 
+func TestColumns_PostgreSQLSpecificTypesRoundTrip(t *testing.T) {
+	tests := []struct {
+		genType  string
+		dataType string
+		udtName  string
+	}{
+		{"INET", "inet", "inet"},
+		{"CIDR", "cidr", "cidr"},
+		{"INTERVAL(6)", "interval", "interval"},
+		{"TSVECTOR", "tsvector", "tsvector"},
+		{"MONEY", "money", "money"},
+		{"TIMESTAMP(3) WITH TIME ZONE", "timestamp with time zone", "timestamptz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.genType, func(t *testing.T) {
+			c := qt.New(t)
+
+			result := compare.ColumnsWithDialect(
+				goschema.Field{Name: "value", Type: tt.genType, Nullable: true},
+				types.DBColumn{Name: "value", DataType: tt.dataType, UDTName: tt.udtName, IsNullable: "YES"},
+				"postgres",
+			)
+
+			c.Assert(result.Changes, qt.HasLen, 0)
+		})
+	}
+}
+
+func TestColumns_PostgreSQLTimeZoneTypes(t *testing.T) {
+	reported := []struct {
+		dataType string
+		udtName  string
+	}{
+		{"timestamp without time zone", "timestamp"},
+		{"timestamp with time zone", "timestamptz"},
+		{"time without time zone", "time"},
+		{"time with time zone", "timetz"},
+	}
+	tests := []struct {
+		annotation string
+		matches    string
+	}{
+		{"TIMESTAMP", "timestamp"},
+		{"TIMESTAMP(6)", "timestamp"},
+		{"TIMESTAMP WITHOUT TIME ZONE", "timestamp"},
+		{"TIMESTAMPTZ", "timestamptz"},
+		{"TIMESTAMP WITH TIME ZONE", "timestamptz"},
+		{"TIMESTAMP(3) WITH TIME ZONE", "timestamptz"},
+		{"TIME", "time"},
+		{"TIME WITHOUT TIME ZONE", "time"},
+		{"TIMETZ", "timetz"},
+		{"TIME WITH TIME ZONE", "timetz"},
+	}
+
+	for _, tt := range tests {
+		for _, db := range reported {
+			t.Run(tt.annotation+" vs "+db.dataType, func(t *testing.T) {
+				c := qt.New(t)
+
+				result := compare.ColumnsWithDialect(
+					goschema.Field{Name: "at", Type: tt.annotation, Nullable: true},
+					types.DBColumn{Name: "at", DataType: db.dataType, UDTName: db.udtName, IsNullable: "YES"},
+					"postgres",
+				)
+
+				_, changed := result.Changes["type"]
+				c.Assert(changed, qt.Equals, tt.matches != db.udtName, qt.Commentf("changes: %v", result.Changes))
+			})
+		}
+	}
+}
+
+func TestColumns_JSONDefaultsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   string
+		genType   string
+		genExpr   string
+		dataType  string
+		dbDefault string
+	}{
+		{"postgres jsonb cast", "postgres", "JSONB", "'{}'::jsonb", "jsonb", "'{}'::jsonb"},
+		{"postgres jsonb plain literal", "postgres", "JSONB", "'{}'", "jsonb", "'{}'::jsonb"},
+		{"postgres json array", "postgres", "JSON", "'[]'", "json", "'[]'::json"},
+		{"postgres jsonb document", "postgres", "JSONB", `'{"tags":[],"enabled":true}'::jsonb`, "jsonb", `'{"tags": [], "enabled": true}'::jsonb`},
+		{"mysql json_object", "mysql", "JSON", "(JSON_OBJECT())", "json", "json_object()"},
+		{"mysql literal vs json_object", "mysql", "JSON", "'{}'", "json", "json_object()"},
+		{"mysql json_array", "mysql", "JSON", "(JSON_ARRAY())", "json", "json_array()"},
+		{"mysql charset introducer", "mysql", "JSON", "('{}')", "json", `_utf8mb4\'{}\'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			dbDefault := tt.dbDefault
+
+			result := compare.ColumnsWithDialect(
+				goschema.Field{Name: "settings", Type: tt.genType, DefaultExpr: tt.genExpr, Nullable: true},
+				types.DBColumn{Name: "settings", DataType: tt.dataType, UDTName: tt.dataType, ColumnType: tt.dataType, IsNullable: "YES", ColumnDefault: &dbDefault},
+				tt.dialect,
+			)
+
+			c.Assert(result.Changes, qt.HasLen, 0)
+		})
+	}
+}
+
+func TestColumns_EnumDefaultCastRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		genCol    goschema.Field
+		dbDefault string
+	}{
+		{"literal vs cast", goschema.Field{Name: "status", Type: "enum_user_status", Default: "active", Nullable: true}, "'active'::enum_user_status"},
+		{"literal vs qualified cast", goschema.Field{Name: "status", Type: "enum_user_status", Default: "active", Nullable: true}, "'active'::public.enum_user_status"},
+		{"cast vs cast", goschema.Field{Name: "status", Type: "enum_user_status", DefaultExpr: "'active'::enum_user_status", Nullable: true}, "'active'::enum_user_status"},
+		{"cast vs literal", goschema.Field{Name: "status", Type: "enum_user_status", DefaultExpr: "'active'::enum_user_status", Nullable: true}, "'active'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			dbDefault := tt.dbDefault
+
+			result := compare.ColumnsWithDialect(
+				tt.genCol,
+				types.DBColumn{Name: "status", DataType: "USER-DEFINED", UDTName: "enum_user_status", IsNullable: "YES", ColumnDefault: &dbDefault},
+				"postgres",
+			)
+
+			c.Assert(result.Changes, qt.HasLen, 0, qt.Commentf("changes: %v", result.Changes))
+		})
+	}
+}
+
+func TestColumns_EnumDefaultCastReportsChangedValue(t *testing.T) {
+	c := qt.New(t)
+	dbDefault := "'pending'::enum_user_status"
+
+	result := compare.ColumnsWithDialect(
+		goschema.Field{Name: "status", Type: "enum_user_status", Default: "active", Nullable: true},
+		types.DBColumn{Name: "status", DataType: "USER-DEFINED", UDTName: "enum_user_status", IsNullable: "YES", ColumnDefault: &dbDefault},
+		"postgres",
+	)
+
+	c.Assert(result.Changes, qt.DeepEquals, map[string]string{"default_expr": "'pending'::enum_user_status -> active"})
+}
+
+func TestColumns_MariaDBJSONReadsBackAsLongtext(t *testing.T) {
+	tests := []struct {
+		genType     string
+		wantChanges int
+	}{
+		{"JSON", 0},
+		{"JSONB", 0},
+		{"VARCHAR(255)", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.genType, func(t *testing.T) {
+			c := qt.New(t)
+
+			result := compare.ColumnsWithDialect(
+				goschema.Field{Name: "payload", Type: tt.genType, Nullable: true},
+				types.DBColumn{Name: "payload", DataType: "longtext", UDTName: "longtext", ColumnType: "longtext", IsNullable: "YES"},
+				"mariadb",
+			)
+
+			c.Assert(result.Changes, qt.HasLen, tt.wantChanges, qt.Commentf("changes: %v", result.Changes))
+		})
+	}
+}
+
+func TestColumns_UUIDPrimaryKeyDefaultsRoundTrip(t *testing.T) {
+	uuidDefault := "uuid()"
+	binaryDefault := "uuid_to_bin(uuid())"
+	mariaBinaryDefault := "unhex(replace(uuid(),'-',''))"
+	tests := []struct {
+		name      string
+		overrides map[string]map[string]string
+		dbType    string
+		dbDefault *string
+		dialect   string
+	}{
+		{name: "mysql char(36)", dbType: "char(36)", dbDefault: &uuidDefault, dialect: "mysql"},
+		{
+			name:      "mysql binary(16)",
+			overrides: map[string]map[string]string{"mysql": {"type": "BINARY(16)"}},
+			dbType:    "binary(16)",
+			dbDefault: &binaryDefault,
+			dialect:   "mysql",
+		},
+		{name: "mariadb native uuid", dbType: "uuid", dbDefault: &uuidDefault, dialect: "mariadb"},
+		{
+			name:      "mariadb binary(16)",
+			overrides: map[string]map[string]string{"mariadb": {"type": "BINARY(16)"}},
+			dbType:    "binary(16)",
+			dbDefault: &mariaBinaryDefault,
+			dialect:   "mariadb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			result := compare.ColumnsWithDialect(
+				goschema.Field{Name: "id", Type: "UUID", Primary: true, DefaultExpr: "gen_random_uuid()", Overrides: tt.overrides},
+				types.DBColumn{Name: "id", DataType: tt.dbType, UDTName: tt.dbType, ColumnType: tt.dbType, IsNullable: "NO", IsPrimaryKey: true, ColumnDefault: tt.dbDefault},
+				tt.dialect,
+			)
+
+			c.Assert(result.Changes, qt.HasLen, 0, qt.Commentf("changes: %v", result.Changes))
+		})
+	}
+}
+
+func TestColumns_MySQLUUIDTypeChangeIsReported(t *testing.T) {
+	c := qt.New(t)
+
+	result := compare.ColumnsWithDialect(
+		goschema.Field{Name: "id", Type: "UUID", Primary: true},
+		types.DBColumn{Name: "id", DataType: "varchar", UDTName: "varchar", ColumnType: "varchar(64)", IsNullable: "NO", IsPrimaryKey: true},
+		"mysql",
+	)
+
+	c.Assert(result.Changes["type"], qt.Equals, "varchar -> CHAR(36)")
+}
+
+func TestColumnsWithOptions_IgnoreDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		genCol    goschema.Field
+		dbDefault string
+	}{
+		{"literal", goschema.Field{Name: "status", Type: "VARCHAR(20)", Default: "active"}, "'archived'::character varying"},
+		{"expression", goschema.Field{Name: "created_at", Type: "TIMESTAMP", DefaultExpr: "CURRENT_TIMESTAMP"}, "'2020-01-01 00:00:00'::timestamp without time zone"},
+		{"dropped default", goschema.Field{Name: "attempts", Type: "INTEGER"}, "3"},
+		{"added default", goschema.Field{Name: "enabled", Type: "BOOLEAN", Default: "true"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			dbCol := types.DBColumn{Name: tt.genCol.Name, DataType: tt.genCol.Type, IsNullable: "NO"}
+			dbCol.ColumnDefault = nonEmptyDefault(tt.dbDefault)
+
+			reported := compare.ColumnsWithOptions(tt.genCol, dbCol, &config.CompareOptions{Dialect: "postgres"})
+			ignored := compare.ColumnsWithOptions(tt.genCol, dbCol, &config.CompareOptions{Dialect: "postgres", IgnoreDefaults: true, Explain: true})
+
+			c.Assert(reported.Changes, qt.Not(qt.HasLen), 0)
+			c.Assert(ignored.Changes, qt.HasLen, 0, qt.Commentf("changes: %v", ignored.Changes))
+			c.Assert(ignored.Explanations, qt.HasLen, 0)
+		})
+	}
+}
+
+func TestColumnsWithOptions_TypeEquivalences(t *testing.T) {
+	equivalences := map[string]string{"email_address": "VARCHAR(320)"}
+	tests := []struct {
+		name     string
+		genType  string
+		dbCol    types.DBColumn
+		expected string
+	}{
+		{
+			name:    "domain on the database side",
+			genType: "VARCHAR(320)",
+			dbCol:   types.DBColumn{DataType: "USER-DEFINED", UDTName: "email_address"},
+		},
+		{
+			name:    "domain on the annotation side",
+			genType: "email_address",
+			dbCol:   types.DBColumn{DataType: "character varying", UDTName: "varchar", CharacterMaxLength: new(320)},
+		},
+		{
+			name:    "keys match case-insensitively",
+			genType: "varchar(320)",
+			dbCol:   types.DBColumn{DataType: "USER-DEFINED", UDTName: "Email_Address"},
+		},
+		{
+			name:     "equivalent type with a different length is still reported",
+			genType:  "email_address",
+			dbCol:    types.DBColumn{DataType: "character varying", UDTName: "varchar", CharacterMaxLength: new(255)},
+			expected: "varchar -> email_address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			genCol := goschema.Field{Name: "email", Type: tt.genType, Nullable: true}
+			dbCol := tt.dbCol
+			dbCol.Name, dbCol.IsNullable = "email", "YES"
+
+			reported := compare.ColumnsWithOptions(genCol, dbCol, &config.CompareOptions{Dialect: "postgres"})
+			result := compare.ColumnsWithOptions(genCol, dbCol, &config.CompareOptions{Dialect: "postgres", TypeEquivalences: equivalences})
+
+			c.Assert(reported.Changes["type"], qt.Not(qt.Equals), "")
+			c.Assert(result.Changes["type"], qt.Equals, tt.expected)
+		})
+	}
+}
+
+func TestColumns_OnUpdateTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		genExpr  string
+		dbExpr   string
+		expected string
+	}{
+		{"mysql missing clause", "mysql", "CURRENT_TIMESTAMP", "", "none -> current_timestamp"},
+		{"mysql extra clause", "mysql", "", "CURRENT_TIMESTAMP", "current_timestamp -> none"},
+		{"mysql precision change", "mysql", "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP", "current_timestamp -> current_timestamp(6)"},
+		{"mysql same clause", "mysql", "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)", ""},
+		{"mariadb read-back spelling", "mariadb", "CURRENT_TIMESTAMP", "current_timestamp()", ""},
+		{"now synonym", "mysql", "NOW()", "CURRENT_TIMESTAMP", ""},
+		{"postgres ignores the clause", "postgres", "CURRENT_TIMESTAMP", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			result := compare.ColumnsWithDialect(
+				goschema.Field{Name: "updated_at", Type: "TIMESTAMP", Nullable: true, UpdateExpression: tt.genExpr},
+				types.DBColumn{Name: "updated_at", DataType: "timestamp", ColumnType: "timestamp", IsNullable: "YES", UpdateExpression: tt.dbExpr},
+				tt.dialect,
+			)
+
+			c.Assert(result.Changes["on_update"], qt.Equals, tt.expected)
+		})
+	}
+}
+
+func TestColumns_JSONDefaultDocumentChangeIsReported(t *testing.T) {
+	c := qt.New(t)
+	dbDefault := "'{}'::jsonb"
+
+	result := compare.ColumnsWithDialect(
+		goschema.Field{Name: "settings", Type: "JSONB", DefaultExpr: `'{"enabled": true}'::jsonb`, Nullable: true},
+		types.DBColumn{Name: "settings", DataType: "jsonb", UDTName: "jsonb", IsNullable: "YES", ColumnDefault: &dbDefault},
+		"postgres",
+	)
+
+	c.Assert(result.Changes["default_expr"], qt.Equals, `'{}'::jsonb -> '{"enabled": true}'::jsonb`)
+}
+
+func TestColumns_PostgreSQLArraysRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		genType   string
+		genExpr   string
+		udtName   string
+		dbDefault string
+	}{
+		{"text empty constructor", "TEXT[]", "ARRAY[]::TEXT[]", "_text", "ARRAY[]::text[]"},
+		{"text empty literal", "TEXT[]", "'{}'", "_text", "'{}'::text[]"},
+		{"text values", "TEXT[]", "ARRAY['a','b']", "_text", "ARRAY['a'::text, 'b'::text]"},
+		{"varchar values", "VARCHAR(32)[]", "'{a,b}'", "_varchar", "'{a,b}'::character varying[]"},
+		{"integer multi-dimensional", "INTEGER[][]", "ARRAY[ARRAY[1,2],ARRAY[3,4]]", "_int4", "'{{1,2},{3,4}}'::integer[]"},
+		{"uuid no default", "UUID[]", "", "_uuid", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			dbDefault := tt.dbDefault
+
+			result := compare.ColumnsWithDialect(
+				goschema.Field{Name: "tags", Type: tt.genType, DefaultExpr: tt.genExpr, Nullable: true},
+				types.DBColumn{Name: "tags", DataType: "ARRAY", UDTName: tt.udtName, IsNullable: "YES", ColumnDefault: &dbDefault},
+				"postgres",
+			)
+
+			c.Assert(result.Changes, qt.HasLen, 0)
+		})
+	}
+}
+
+func TestColumns_ArrayToScalarIsTypeChange(t *testing.T) {
+	c := qt.New(t)
+
+	result := compare.ColumnsWithDialect(
+		goschema.Field{Name: "tags", Type: "TEXT", Nullable: true},
+		types.DBColumn{Name: "tags", DataType: "ARRAY", UDTName: "_text", IsNullable: "YES"},
+		"postgres",
+	)
+
+	c.Assert(result.Changes["type"], qt.Equals, "text[] -> text")
+}
+
+func TestColumns_ArrayDefaultValueChangeIsReported(t *testing.T) {
+	c := qt.New(t)
+	dbDefault := "'{a}'::text[]"
+
+	result := compare.ColumnsWithDialect(
+		goschema.Field{Name: "tags", Type: "TEXT[]", DefaultExpr: "ARRAY['a','b']", Nullable: true},
+		types.DBColumn{Name: "tags", DataType: "ARRAY", UDTName: "_text", IsNullable: "YES", ColumnDefault: &dbDefault},
+		"postgres",
+	)
+
+	c.Assert(result.Changes["default_expr"], qt.Equals, "'{a}'::text[] -> ARRAY['a','b']")
+}
+
+func TestColumns_IntervalToIntegerIsTypeChange(t *testing.T) {
+	c := qt.New(t)
+
+	result := compare.ColumnsWithDialect(
+		goschema.Field{Name: "duration", Type: "INTEGER", Nullable: true},
+		types.DBColumn{Name: "duration", DataType: "interval", UDTName: "interval", IsNullable: "YES"},
+		"postgres",
+	)
+
+	c.Assert(result.Changes["type"], qt.Equals, "interval -> integer")
+}
+
+func TestColumns_MySQLUnknownTypesKeepTheirSpelling(t *testing.T) {
+	c := qt.New(t)
+
+	unchanged := compare.ColumnsWithDialect(
+		goschema.Field{Name: "area", Type: "GEOMETRY", Nullable: true},
+		types.DBColumn{Name: "area", DataType: "geometry", ColumnType: "geometry", IsNullable: "YES"},
+		"mysql",
+	)
+	changed := compare.ColumnsWithDialect(
+		goschema.Field{Name: "area", Type: "MULTIPOLYGON", Nullable: true},
+		types.DBColumn{Name: "area", DataType: "geometry", ColumnType: "geometry", IsNullable: "YES"},
+		"mysql",
+	)
+
+	c.Assert(unchanged.Changes, qt.HasLen, 0)
+	c.Assert(changed.Changes["type"], qt.Equals, "geometry -> MULTIPOLYGON")
+}
+
 func TestColumns_UnhappyPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -534,6 +972,108 @@ func TestEnums_UnhappyPath(t *testing.T) {
 	}
 }
 
+func TestEnums_Renames(t *testing.T) {
+	tests := []struct {
+		name            string
+		generated       []goschema.Enum
+		database        []types.DBEnum
+		expectedRenamed []difftypes.EnumRename
+		expectedAdded   []string
+		expectedRemoved []string
+		expectedChanged []difftypes.EnumDiff
+	}{
+		{
+			name:            "rename_from renames the existing type",
+			generated:       []goschema.Enum{{Name: "order_status", Values: []string{"new", "paid"}, RenameFrom: "order_state"}},
+			database:        []types.DBEnum{{Name: "order_state", Values: []string{"new", "paid"}}},
+			expectedRenamed: []difftypes.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+		},
+		{
+			name:            "value changes are reported under the new name",
+			generated:       []goschema.Enum{{Name: "order_status", Values: []string{"new", "paid", "refunded"}, RenameFrom: "order_state"}},
+			database:        []types.DBEnum{{Name: "order_state", Values: []string{"new", "paid"}}},
+			expectedRenamed: []difftypes.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+			expectedChanged: []difftypes.EnumDiff{{EnumName: "order_status", ValuesAdded: []string{"refunded"}}},
+		},
+		{
+			name:            "values removed under the new name",
+			generated:       []goschema.Enum{{Name: "order_status", Values: []string{"paid", "shipped"}, RenameFrom: "order_state"}},
+			database:        []types.DBEnum{{Name: "order_state", Values: []string{"new", "paid"}}},
+			expectedRenamed: []difftypes.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+			expectedChanged: []difftypes.EnumDiff{{EnumName: "order_status", ValuesAdded: []string{"shipped"}, ValuesRemoved: []string{"new"}}},
+		},
+		{
+			name:            "old type without values",
+			generated:       []goschema.Enum{{Name: "order_status", Values: []string{"new"}, RenameFrom: "order_state"}},
+			database:        []types.DBEnum{{Name: "order_state"}},
+			expectedRenamed: []difftypes.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+			expectedChanged: []difftypes.EnumDiff{{EnumName: "order_status", ValuesAdded: []string{"new"}}},
+		},
+		{
+			name:            "disjoint values still rename",
+			generated:       []goschema.Enum{{Name: "order_status", Values: []string{"high", "low"}, RenameFrom: "order_state"}},
+			database:        []types.DBEnum{{Name: "order_state", Values: []string{"new", "paid"}}},
+			expectedRenamed: []difftypes.EnumRename{{OldName: "order_state", NewName: "order_status"}},
+			expectedChanged: []difftypes.EnumDiff{{EnumName: "order_status", ValuesAdded: []string{"high", "low"}, ValuesRemoved: []string{"new", "paid"}}},
+		},
+		{
+			name:            "matching values without rename_from drop and create",
+			generated:       []goschema.Enum{{Name: "order_status", Values: []string{"new", "paid"}}},
+			database:        []types.DBEnum{{Name: "order_state", Values: []string{"new", "paid"}}},
+			expectedAdded:   []string{"order_status"},
+			expectedRemoved: []string{"order_state"},
+		},
+		{
+			name:      "new name already exists",
+			generated: []goschema.Enum{{Name: "order_status", Values: []string{"new"}, RenameFrom: "order_state"}},
+			database: []types.DBEnum{
+				{Name: "order_state", Values: []string{"new"}},
+				{Name: "order_status", Values: []string{"new"}},
+			},
+			expectedRemoved: []string{"order_state"},
+		},
+		{
+			name:          "old name missing from the database",
+			generated:     []goschema.Enum{{Name: "order_status", Values: []string{"new"}, RenameFrom: "order_state"}},
+			database:      []types.DBEnum{},
+			expectedAdded: []string{"order_status"},
+		},
+		{
+			name: "old name still declared",
+			generated: []goschema.Enum{
+				{Name: "order_state", Values: []string{"new"}},
+				{Name: "order_status", Values: []string{"new"}, RenameFrom: "order_state"},
+			},
+			database:      []types.DBEnum{{Name: "order_state", Values: []string{"new"}}},
+			expectedAdded: []string{"order_status"},
+		},
+		{
+			name: "two enums claim the same old name",
+			generated: []goschema.Enum{
+				{Name: "order_status", Values: []string{"new"}, RenameFrom: "order_state"},
+				{Name: "payment_status", Values: []string{"new"}, RenameFrom: "order_state"},
+			},
+			database:        []types.DBEnum{{Name: "order_state", Values: []string{"new"}}},
+			expectedAdded:   []string{"order_status", "payment_status"},
+			expectedRemoved: []string{"order_state"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			diff := &difftypes.SchemaDiff{}
+			compare.Enums(&goschema.Database{Enums: tt.generated}, &types.DBSchema{Enums: tt.database}, diff)
+
+			c.Assert(diff.EnumsRenamed, qt.DeepEquals, tt.expectedRenamed)
+			c.Assert(diff.EnumsAdded, qt.DeepEquals, tt.expectedAdded)
+			c.Assert(diff.EnumsRemoved, qt.DeepEquals, tt.expectedRemoved)
+			c.Assert(diff.EnumsModified, qt.DeepEquals, tt.expectedChanged)
+		})
+	}
+}
+
 func TestEnumValues_HappyPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -757,6 +1297,43 @@ func TestIndexes_HappyPath(t *testing.T) {
 				IndexesRemoved: []string{"idx_users_c"},
 			},
 		},
+		{
+			name: "index explicit nulls distinct matches the default",
+			generated: func() *goschema.Database {
+				nullsDistinct := true
+				return &goschema.Database{
+					Indexes: []goschema.Index{
+						{Name: "idx_users_c", StructName: "users", Fields: []string{"c"}, Unique: true, NullsDistinct: &nullsDistinct},
+					},
+				}
+			}(),
+			database: &types.DBSchema{
+				Indexes: []types.DBIndex{
+					{Name: "idx_users_c", TableName: "users", Columns: []string{"c"}, IsUnique: true},
+				},
+			},
+			expected: &difftypes.SchemaDiff{},
+		},
+		{
+			name: "index nulls not distinct unchanged",
+			generated: func() *goschema.Database {
+				nullsDistinct := false
+				return &goschema.Database{
+					Indexes: []goschema.Index{
+						{Name: "idx_users_c", StructName: "users", Fields: []string{"c"}, Unique: true, NullsDistinct: &nullsDistinct},
+					},
+				}
+			}(),
+			database: func() *types.DBSchema {
+				nullsDistinct := false
+				return &types.DBSchema{
+					Indexes: []types.DBIndex{
+						{Name: "idx_users_c", TableName: "users", Columns: []string{"c"}, IsUnique: true, NullsDistinct: &nullsDistinct},
+					},
+				}
+			}(),
+			expected: &difftypes.SchemaDiff{},
+		},
 		{
 			name: "partial index condition changed",
 			generated: &goschema.Database{
@@ -1019,6 +1596,125 @@ func TestIndexes_UnhappyPath(t *testing.T) {
 	}
 }
 
+func TestIndexes_ExpressionIndexes(t *testing.T) {
+	generated := &goschema.Database{
+		Indexes: []goschema.Index{
+			{Name: "idx_users_email", StructName: "User", Fields: []string{"email"}},
+			{Name: "idx_users_email_lower", StructName: "User", Fields: []string{"lower(email)"}, Parts: []goschema.IndexPart{{Expr: "lower(email)"}}},
+		},
+	}
+	tests := []struct {
+		name            string
+		database        *types.DBSchema
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{
+			name: "PostgreSQL canonical expression matches",
+			database: &types.DBSchema{Indexes: []types.DBIndex{
+				{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}},
+				{Name: "idx_users_email_lower", TableName: "users", Columns: []string{"lower((email)::text)"}},
+			}},
+		},
+		{
+			name: "MySQL canonical expression matches",
+			database: &types.DBSchema{Indexes: []types.DBIndex{
+				{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}},
+				{Name: "idx_users_email_lower", TableName: "users", Columns: []string{"(LOWER(`email`))"}},
+			}},
+		},
+		{
+			name: "changed expression is recreated",
+			database: &types.DBSchema{Indexes: []types.DBIndex{
+				{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}},
+				{Name: "idx_users_email_lower", TableName: "users", Columns: []string{"upper((email)::text)"}},
+			}},
+			expectedAdded:   []string{"idx_users_email_lower"},
+			expectedRemoved: []string{"idx_users_email_lower"},
+		},
+		{
+			name: "plain column replaced by expression is recreated",
+			database: &types.DBSchema{Indexes: []types.DBIndex{
+				{Name: "idx_users_email", TableName: "users", Columns: []string{"email"}},
+				{Name: "idx_users_email_lower", TableName: "users", Columns: []string{"email"}},
+			}},
+			expectedAdded:   []string{"idx_users_email_lower"},
+			expectedRemoved: []string{"idx_users_email_lower"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			diff := &difftypes.SchemaDiff{}
+			compare.Indexes(generated, tt.database, diff)
+
+			c.Assert(diff.IndexesAdded, qt.DeepEquals, tt.expectedAdded)
+			c.Assert(diff.IndexesRemoved, qt.DeepEquals, tt.expectedRemoved)
+		})
+	}
+}
+
+func TestIndexesWithDialect_MySQLIndexTypes(t *testing.T) {
+	tests := []struct {
+		name            string
+		dialect         string
+		genType         string
+		dbType          string
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{name: "fulltext matches fulltext", dialect: "mysql", genType: "fulltext", dbType: "FULLTEXT"},
+		{name: "default matches btree", dialect: "mysql"},
+		{name: "explicit btree matches btree", dialect: "mariadb", genType: "btree"},
+		{name: "hash matches InnoDB btree", dialect: "mysql", genType: "hash"},
+		{name: "hash matches hash", dialect: "mysql", genType: "hash", dbType: "HASH"},
+		{
+			name:            "fulltext replacing btree is recreated",
+			dialect:         "mysql",
+			genType:         "fulltext",
+			expectedAdded:   []string{"idx_posts_body"},
+			expectedRemoved: []string{"idx_posts_body"},
+		},
+		{
+			name:            "btree replacing spatial is recreated",
+			dialect:         "mariadb",
+			dbType:          "SPATIAL",
+			expectedAdded:   []string{"idx_posts_body"},
+			expectedRemoved: []string{"idx_posts_body"},
+		},
+		{
+			name:            "btree replacing hash is recreated",
+			dialect:         "mysql",
+			genType:         "btree",
+			dbType:          "HASH",
+			expectedAdded:   []string{"idx_posts_body"},
+			expectedRemoved: []string{"idx_posts_body"},
+		},
+		{name: "type ignored on postgres", dialect: "postgres", genType: "fulltext"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			generated := &goschema.Database{Indexes: []goschema.Index{
+				{Name: "idx_posts_body", StructName: "Post", Fields: []string{"body"}, Type: tt.genType},
+			}}
+			database := &types.DBSchema{Indexes: []types.DBIndex{
+				{Name: "idx_posts_body", TableName: "posts", Columns: []string{"body"}, Type: tt.dbType},
+			}}
+
+			diff := &difftypes.SchemaDiff{}
+			compare.IndexesWithDialect(generated, database, diff, tt.dialect)
+
+			c.Assert(diff.IndexesAdded, qt.DeepEquals, tt.expectedAdded)
+			c.Assert(diff.IndexesRemoved, qt.DeepEquals, tt.expectedRemoved)
+		})
+	}
+}
+
 // Note: The isConstraintBasedUniqueIndex function is tested indirectly through
 // the integration tests and the main Indexes function tests, which provide
 // comprehensive coverage of the constraint detection logic.
@@ -1126,7 +1822,8 @@ func TestTableColumns_EdgeCases(t *testing.T) {
 	c.Assert(result.TableName, qt.Equals, "users")
 	c.Assert(result.ColumnsModified, qt.HasLen, 1)
 	c.Assert(result.ColumnsModified[0].ColumnName, qt.Equals, "name")
-	c.Assert(result.ColumnsModified[0].Changes, qt.HasLen, 1) // Only nullable should change (types are both varchar)
+	c.Assert(result.ColumnsModified[0].Changes, qt.HasLen, 2)
+	c.Assert(result.ColumnsModified[0].Changes["type"], qt.Equals, "VARCHAR(100) -> VARCHAR(255)")
 	c.Assert(result.ColumnsModified[0].Changes["nullable"], qt.Equals, "true -> false")
 }
 
@@ -1846,3 +2543,271 @@ func TestTableColumns_WithEmbeddedFields(t *testing.T) {
 	c.Assert(result.TableName, qt.Equals, "users")
 	c.Assert(result.ColumnsAdded, qt.DeepEquals, []string{"created_at", "updated_at"})
 }
+
+func TestColumns_TypeChangeKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		genType string
+		dbCol   types.DBColumn
+		dialect string
+		want    difftypes.TypeChangeKind
+	}{
+		{
+			name:    "varchar grows",
+			genType: "VARCHAR(255)",
+			dbCol:   types.DBColumn{DataType: "character varying", UDTName: "varchar", ColumnType: "character varying(100)"},
+			dialect: "postgres",
+			want:    difftypes.TypeChangeWidening,
+		},
+		{
+			name:    "varchar shrinks",
+			genType: "VARCHAR(50)",
+			dbCol:   types.DBColumn{DataType: "character varying", UDTName: "varchar", ColumnType: "character varying(100)"},
+			dialect: "postgres",
+			want:    difftypes.TypeChangeNarrowing,
+		},
+		{
+			name:    "date to timestamp",
+			genType: "TIMESTAMP",
+			dbCol:   types.DBColumn{DataType: "date", UDTName: "date"},
+			dialect: "postgres",
+			want:    difftypes.TypeChangeWidening,
+		},
+		{
+			name:    "text to integer",
+			genType: "INTEGER",
+			dbCol:   types.DBColumn{DataType: "text", UDTName: "text"},
+			dialect: "postgres",
+			want:    difftypes.TypeChangeIncompatible,
+		},
+		{
+			name:    "mysql varchar to tinytext",
+			genType: "TINYTEXT",
+			dbCol:   types.DBColumn{DataType: "varchar", ColumnType: "varchar(1000)"},
+			dialect: "mysql",
+			want:    difftypes.TypeChangeNarrowing,
+		},
+		{
+			name:    "unchanged",
+			genType: "TEXT",
+			dbCol:   types.DBColumn{DataType: "text", UDTName: "text"},
+			dialect: "postgres",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			tt.dbCol.Name = "col"
+			tt.dbCol.IsNullable = "YES"
+
+			result := compare.ColumnsWithDialect(goschema.Field{Name: "col", Type: tt.genType, Nullable: true}, tt.dbCol, tt.dialect)
+
+			c.Assert(result.TypeChangeKind, qt.Equals, tt.want)
+		})
+	}
+}
+
+func TestColumns_TypeParameterChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		genType  string
+		dbCol    types.DBColumn
+		dialect  string
+		expected string
+	}{
+		{
+			name:     "postgres varchar grows",
+			genType:  "VARCHAR(255)",
+			dbCol:    types.DBColumn{DataType: "character varying", UDTName: "varchar", CharacterMaxLength: func() *int { value := 100; return &value }()},
+			dialect:  "postgres",
+			expected: "varchar(100) -> VARCHAR(255)",
+		},
+		{
+			name:     "mysql varchar grows",
+			genType:  "VARCHAR(255)",
+			dbCol:    types.DBColumn{DataType: "varchar", ColumnType: "varchar(100)"},
+			dialect:  "mysql",
+			expected: "varchar(100) -> VARCHAR(255)",
+		},
+		{
+			name:     "numeric precision grows",
+			genType:  "NUMERIC(14,2)",
+			dbCol:    types.DBColumn{DataType: "numeric", UDTName: "numeric", NumericPrecision: func() *int { value := 10; return &value }(), NumericScale: func() *int { value := 2; return &value }()},
+			dialect:  "postgres",
+			expected: "numeric(10,2) -> NUMERIC(14,2)",
+		},
+		{
+			name:     "decimal without scale matches zero scale",
+			genType:  "DECIMAL(10)",
+			dbCol:    types.DBColumn{DataType: "decimal", ColumnType: "decimal(10,0)"},
+			dialect:  "mysql",
+			expected: "",
+		},
+		{
+			name:     "unspecified length has no opinion",
+			genType:  "VARCHAR",
+			dbCol:    types.DBColumn{DataType: "character varying", UDTName: "varchar", CharacterMaxLength: func() *int { value := 100; return &value }()},
+			dialect:  "postgres",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			tt.dbCol.Name = "col"
+			tt.dbCol.IsNullable = "YES"
+
+			result := compare.ColumnsWithDialect(goschema.Field{Name: "col", Type: tt.genType, Nullable: true}, tt.dbCol, tt.dialect)
+
+			c.Assert(result.Changes["type"], qt.Equals, tt.expected)
+		})
+	}
+}
+
+// TestColumns_DecimalPrecisionMatrix declares DECIMAL/NUMERIC forms against
+// the columns PostgreSQL and MySQL report after creating them, so matching
+// forms never re-migrate and real precision changes are classified.
+func TestColumns_DecimalPrecisionMatrix(t *testing.T) {
+	postgresNumeric := func(precision, scale *int) types.DBColumn {
+		return types.DBColumn{DataType: "numeric", UDTName: "numeric", NumericPrecision: precision, NumericScale: scale}
+	}
+	mysqlDecimal := func(columnType string) types.DBColumn {
+		return types.DBColumn{DataType: "decimal", ColumnType: columnType}
+	}
+	tests := []struct {
+		name       string
+		genType    string
+		dbCol      types.DBColumn
+		dialect    string
+		wantChange string
+		wantKind   difftypes.TypeChangeKind
+	}{
+		{name: "postgres same precision and scale", genType: "NUMERIC(10,2)", dbCol: postgresNumeric(new(10), new(2)), dialect: "postgres"},
+		{name: "postgres decimal spelling", genType: "DECIMAL(10,2)", dbCol: postgresNumeric(new(10), new(2)), dialect: "postgres"},
+		{name: "postgres omitted scale", genType: "NUMERIC(10)", dbCol: postgresNumeric(new(10), new(0)), dialect: "postgres"},
+		{name: "postgres unconstrained", genType: "NUMERIC", dbCol: postgresNumeric(nil, nil), dialect: "postgres"},
+		{name: "postgres unconstrained decimal spelling", genType: "DECIMAL", dbCol: postgresNumeric(nil, nil), dialect: "postgres"},
+		{
+			name: "postgres precision grows", genType: "NUMERIC(12,2)", dbCol: postgresNumeric(new(10), new(2)), dialect: "postgres",
+			wantChange: "numeric(10,2) -> NUMERIC(12,2)", wantKind: difftypes.TypeChangeWidening,
+		},
+		{
+			name: "postgres precision shrinks", genType: "NUMERIC(10,2)", dbCol: postgresNumeric(new(12), new(2)), dialect: "postgres",
+			wantChange: "numeric(12,2) -> NUMERIC(10,2)", wantKind: difftypes.TypeChangeNarrowing,
+		},
+		{
+			name: "postgres constraint removed", genType: "NUMERIC", dbCol: postgresNumeric(new(10), new(2)), dialect: "postgres",
+			wantChange: "numeric(10,2) -> NUMERIC", wantKind: difftypes.TypeChangeWidening,
+		},
+		{
+			name: "postgres constraint added", genType: "NUMERIC(10,2)", dbCol: postgresNumeric(nil, nil), dialect: "postgres",
+			wantChange: "numeric -> NUMERIC(10,2)", wantKind: difftypes.TypeChangeNarrowing,
+		},
+		{name: "mysql bare decimal", genType: "DECIMAL", dbCol: mysqlDecimal("decimal(10,0)"), dialect: "mysql"},
+		{name: "mysql bare numeric", genType: "NUMERIC", dbCol: mysqlDecimal("decimal(10,0)"), dialect: "mysql"},
+		{name: "mysql omitted scale", genType: "DECIMAL(12)", dbCol: mysqlDecimal("decimal(12,0)"), dialect: "mysql"},
+		{name: "mysql numeric spelling", genType: "NUMERIC(12,2)", dbCol: mysqlDecimal("decimal(12,2)"), dialect: "mysql"},
+		{name: "mysql unsigned", genType: "DECIMAL(12,2) UNSIGNED", dbCol: mysqlDecimal("decimal(12,2) unsigned"), dialect: "mysql"},
+		{name: "mariadb bare decimal", genType: "DECIMAL", dbCol: mysqlDecimal("decimal(10,0)"), dialect: "mariadb"},
+		{
+			name: "mysql precision grows", genType: "DECIMAL(12,2)", dbCol: mysqlDecimal("decimal(10,2)"), dialect: "mysql",
+			wantChange: "decimal(10,2) -> DECIMAL(12,2)", wantKind: difftypes.TypeChangeWidening,
+		},
+		{
+			name: "mysql bare decimal from wider column", genType: "DECIMAL", dbCol: mysqlDecimal("decimal(12,2)"), dialect: "mysql",
+			wantChange: "decimal(12,2) -> DECIMAL", wantKind: difftypes.TypeChangeNarrowing,
+		},
+		{
+			name: "mysql scale grows within precision", genType: "DECIMAL(10,4)", dbCol: mysqlDecimal("decimal(10,2)"), dialect: "mysql",
+			wantChange: "decimal(10,2) -> DECIMAL(10,4)", wantKind: difftypes.TypeChangeNarrowing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			tt.dbCol.Name = "amount"
+			tt.dbCol.IsNullable = "YES"
+
+			result := compare.ColumnsWithDialect(goschema.Field{Name: "amount", Type: tt.genType, Nullable: true}, tt.dbCol, tt.dialect)
+
+			c.Assert(result.Changes["type"], qt.Equals, tt.wantChange)
+			c.Assert(result.TypeChangeKind, qt.Equals, tt.wantKind)
+		})
+	}
+}
+
+func TestColumns_DialectScopedDefault(t *testing.T) {
+	uuidField := goschema.Field{
+		Name:        "id",
+		Type:        "VARCHAR(36)",
+		Nullable:    true,
+		DefaultExpr: "gen_random_uuid()",
+		Overrides:   map[string]map[string]string{"mysql": {"default_expr": "UUID()"}},
+	}
+	literalField := goschema.Field{
+		Name:      "status",
+		Type:      "VARCHAR(20)",
+		Nullable:  true,
+		Default:   "active",
+		Overrides: map[string]map[string]string{"postgres": {"default": "pending"}},
+	}
+	tests := []struct {
+		name    string
+		field   goschema.Field
+		dbCol   types.DBColumn
+		dialect string
+		want    map[string]string
+	}{
+		{
+			name:    "mysql uses its override",
+			field:   uuidField,
+			dbCol:   types.DBColumn{Name: "id", DataType: "varchar", ColumnType: "varchar(36)", IsNullable: "YES", ColumnDefault: new("uuid()")},
+			dialect: "mysql",
+			want:    map[string]string{},
+		},
+		{
+			name:    "postgres falls back to the generic default",
+			field:   uuidField,
+			dbCol:   types.DBColumn{Name: "id", DataType: "character varying", UDTName: "varchar", CharacterMaxLength: new(36), IsNullable: "YES", ColumnDefault: new("gen_random_uuid()")},
+			dialect: "postgres",
+			want:    map[string]string{},
+		},
+		{
+			name:    "mysql reports a database default that differs from its override",
+			field:   uuidField,
+			dbCol:   types.DBColumn{Name: "id", DataType: "varchar", ColumnType: "varchar(36)", IsNullable: "YES", ColumnDefault: new("gen_random_uuid()")},
+			dialect: "mysql",
+			want:    map[string]string{"default_expr": "gen_random_uuid() -> UUID()"},
+		},
+		{
+			name:    "literal override",
+			field:   literalField,
+			dbCol:   types.DBColumn{Name: "status", DataType: "character varying", UDTName: "varchar", CharacterMaxLength: new(20), IsNullable: "YES", ColumnDefault: new("'pending'::character varying")},
+			dialect: "postgres",
+			want:    map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			diff := compare.ColumnsWithDialect(tt.field, tt.dbCol, tt.dialect)
+
+			c.Assert(diff.Changes, qt.DeepEquals, tt.want)
+		})
+	}
+}
+
+// nonEmptyDefault returns the catalog default for value, with "" meaning the
+// column has no default.
+func nonEmptyDefault(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -1,6 +1,8 @@
 package compare
 
 import (
+	"strings"
+
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/internal/convert/fromschema"
@@ -15,7 +17,8 @@ import (
 // otherwise it falls back to the PostgreSQL convention
 // "<table>_<column>_check" — which is what PostgreSQL itself uses for
 // unnamed inline column-level CHECKs, so the name lines up with whatever the
-// reader sees on the DB side.
+// reader sees on the DB side. MySQL and MariaDB name them differently; see
+// inlineCheckName.
 //
 // Columns that do not yet exist in the database are deliberately skipped:
 // those CHECKs ship inline as part of CREATE TABLE / ALTER TABLE ADD COLUMN,
@@ -60,7 +63,7 @@ func synthesizeFieldLevelCheckConstraints(generated *goschema.Database, database
 		}
 		name := f.CheckName
 		if name == "" {
-			name = tableLeafName + "_" + f.Name + "_check"
+			name = inlineCheckName(database, tableName, tableLeafName, f)
 		}
 		synthesized = append(synthesized, goschema.Constraint{
 			StructName:      f.StructName,
@@ -73,6 +76,46 @@ func synthesizeFieldLevelCheckConstraints(generated *goschema.Database, database
 	return synthesized
 }
 
+// inlineCheckName returns the name the database gave the unnamed inline CHECK
+// of field. PostgreSQL names it "<table>_<column>_check", the name ptah
+// expects. MySQL names it "<table>_chk_<n>" and MariaDB after the column, so
+// an existing check there is found by its expression instead; without that,
+// the annotation's check would be re-added on every generation.
+func inlineCheckName(database *types.DBSchema, tableName, tableLeafName string, field goschema.Field) string {
+	name := tableLeafName + "_" + field.Name + "_check"
+	expression := normalizeCheckExpression(field.Check)
+	var autoNamed string
+	for _, constraint := range database.Constraints {
+		if constraint.Type != "CHECK" || constraint.QualifiedTableName() != tableName {
+			continue
+		}
+		if constraint.Name == name {
+			return name
+		}
+		if autoNamed == "" && isAutoNamedInlineCheck(constraint.Name, tableLeafName, field.Name) &&
+			normalizeCheckExpression(getStringValue(constraint.CheckClause)) == expression {
+			autoNamed = constraint.Name
+		}
+	}
+	if autoNamed != "" {
+		return autoNamed
+	}
+	return name
+}
+
+// isAutoNamedInlineCheck reports whether name is one MySQL ("<table>_chk_<n>")
+// or MariaDB (the column name) generates for an unnamed inline CHECK.
+func isAutoNamedInlineCheck(name, tableName, columnName string) bool {
+	if name == columnName {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(name, tableName+"_chk_")
+	if !ok || suffix == "" {
+		return false
+	}
+	return strings.Trim(suffix, "0123456789") == ""
+}
+
 func synthesizeTablePrimaryKeyConstraints(
 	generated *goschema.Database,
 	database *types.DBSchema,
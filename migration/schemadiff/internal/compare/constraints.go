@@ -13,18 +13,19 @@ import (
 
 // Constraints compares constraint definitions between generated and database schemas.
 //
-// This function identifies differences in table-level constraints such as EXCLUDE,
+// This function identifies differences in table-level constraints such as
 // CHECK, UNIQUE, PRIMARY KEY, and FOREIGN KEY constraints. It compares constraints
 // defined through Go struct annotations with constraints that exist in the database.
 //
 // # Constraint Types Supported
 //
-//   - EXCLUDE: PostgreSQL EXCLUDE constraints for preventing conflicts
 //   - CHECK: Table-level CHECK constraints for data validation
 //   - UNIQUE: Table-level UNIQUE constraints spanning multiple columns
 //   - PRIMARY KEY: Composite primary key constraints
 //   - FOREIGN KEY: Table-level foreign key constraints
 //
+// PostgreSQL EXCLUDE constraints are delegated to ExcludeConstraints.
+//
 // # Comparison Logic
 //
 // The function performs constraint comparison by:
@@ -68,6 +69,10 @@ func Constraints(generated *goschema.Database, database *types.DBSchema, diff *d
 	// Create maps for detailed constraint comparison
 	genConstraints := make(map[string]goschema.Constraint)
 	for _, constraint := range generated.Constraints {
+		// EXCLUDE constraints are compared by ExcludeConstraints below.
+		if isExcludeConstraintType(constraint.Type) {
+			continue
+		}
 		// Use table.constraint_name as the key for comparison to handle constraints with same names in different tables
 		key := constraint.Table + "." + constraint.Name
 		genConstraints[key] = constraint
@@ -125,7 +130,7 @@ func Constraints(generated *goschema.Database, database *types.DBSchema, diff *d
 	dbConstraints := make(map[string]types.DBConstraint)
 	for _, constraint := range database.Constraints {
 		// Skip field-level constraints that are represented in field definitions
-		if isFieldLevelConstraint(constraint, generated, synthesizedFKKeys) {
+		if isFieldLevelConstraint(constraint, generated, synthesizedFKKeys) || isExcludeConstraintType(constraint.Type) {
 			continue
 		}
 
@@ -164,9 +169,17 @@ func Constraints(generated *goschema.Database, database *types.DBSchema, diff *d
 		}
 	}
 
-	// Sort for consistent output. Planners pair the bare name lists with the
-	// *WithTables slices through name-keyed maps, not by index, so each list
-	// can be sorted independently.
+	// EXCLUDE constraints need PostgreSQL-specific normalization and are
+	// skipped on other dialects.
+	ExcludeConstraints(generated, database, diff, opts)
+
+	sortConstraintDiff(diff)
+}
+
+// sortConstraintDiff sorts the constraint changes for consistent output.
+// Planners pair the bare name lists with the *WithTables slices through
+// name-keyed maps, not by index, so each list can be sorted independently.
+func sortConstraintDiff(diff *difftypes.SchemaDiff) {
 	sort.Strings(diff.ConstraintsAdded)
 	sort.Strings(diff.ConstraintsRemoved)
 	sort.Slice(diff.ConstraintsAddedWithTables, func(i, j int) bool {
@@ -235,8 +248,6 @@ func constraintDefinitionsChanged(genConstraint goschema.Constraint, dbConstrain
 
 	// Type-specific comparisons
 	switch genConstraint.Type {
-	case "EXCLUDE":
-		return excludeConstraintChanged(genConstraint, dbConstraint)
 	case "CHECK":
 		return checkConstraintChanged(genConstraint, dbConstraint)
 	case "UNIQUE":
@@ -256,26 +267,6 @@ func primaryKeyConstraintChanged(genConstraint goschema.Constraint, dbConstraint
 		!stringSetsEqual(genConstraint.IncludeColumns, dbConstraint.IncludeColumns)
 }
 
-// excludeConstraintChanged compares EXCLUDE constraint definitions
-func excludeConstraintChanged(genConstraint goschema.Constraint, dbConstraint types.DBConstraint) bool {
-	// Compare using method
-	if genConstraint.UsingMethod != getStringValue(dbConstraint.UsingMethod) {
-		return true
-	}
-
-	// Compare exclude elements
-	if genConstraint.ExcludeElements != getStringValue(dbConstraint.ExcludeElements) {
-		return true
-	}
-
-	// Compare WHERE condition
-	if genConstraint.WhereCondition != getStringValue(dbConstraint.WhereCondition) {
-		return true
-	}
-
-	return false
-}
-
 // checkConstraintChanged compares CHECK constraint definitions.
 func checkConstraintChanged(genConstraint goschema.Constraint, dbConstraint types.DBConstraint) bool {
 	dbClause := getStringValue(dbConstraint.CheckClause)
@@ -292,5 +283,5 @@ func checkConstraintChanged(genConstraint goschema.Constraint, dbConstraint type
 func uniqueConstraintChanged(genConstraint goschema.Constraint, dbConstraint types.DBConstraint) bool {
 	return !stringSetsEqual(genConstraint.Columns, dbConstraint.ColumnNamesOrDefault()) ||
 		!stringSetsEqual(genConstraint.IncludeColumns, dbConstraint.IncludeColumns) ||
-		!boolPtrEqual(genConstraint.NullsDistinct, dbConstraint.NullsDistinct)
+		!nullsDistinctEqual(genConstraint.NullsDistinct, dbConstraint.NullsDistinct)
 }
@@ -217,6 +217,32 @@ func TestConstraints(t *testing.T) {
 			},
 			expected: &difftypes.SchemaDiff{},
 		},
+		{
+			name: "CHECK constraint added NOT VALID matches declared constraint",
+			generated: &goschema.Database{
+				Constraints: []goschema.Constraint{
+					{
+						StructName:      "Product",
+						Name:            "positive_price",
+						Type:            "CHECK",
+						Table:           "products",
+						CheckExpression: "price > 0",
+						NotValid:        true,
+					},
+				},
+			},
+			database: &types.DBSchema{
+				Constraints: []types.DBConstraint{
+					{
+						Name:        "positive_price",
+						TableName:   "products",
+						Type:        "CHECK",
+						CheckClause: new("((price > 0)) NOT VALID"),
+					},
+				},
+			},
+			expected: &difftypes.SchemaDiff{},
+		},
 		{
 			name: "UNIQUE constraint added",
 			generated: &goschema.Database{
@@ -322,6 +348,68 @@ func TestConstraints(t *testing.T) {
 				ConstraintsRemoved: []string{"users_c_key"},
 			},
 		},
+		{
+			name: "UNIQUE constraint explicit nulls distinct matches the default",
+			generated: func() *goschema.Database {
+				nullsDistinct := true
+				return &goschema.Database{
+					Constraints: []goschema.Constraint{
+						{
+							StructName:    "User",
+							Name:          "users_c_key",
+							Type:          "UNIQUE",
+							Table:         "users",
+							Columns:       []string{"c"},
+							NullsDistinct: &nullsDistinct,
+						},
+					},
+				}
+			}(),
+			database: &types.DBSchema{
+				Constraints: []types.DBConstraint{
+					{
+						Name:        "users_c_key",
+						TableName:   "users",
+						Type:        "UNIQUE",
+						ColumnNames: []string{"c"},
+					},
+				},
+			},
+			expected: &difftypes.SchemaDiff{},
+		},
+		{
+			name: "UNIQUE constraint nulls not distinct unchanged",
+			generated: func() *goschema.Database {
+				nullsDistinct := false
+				return &goschema.Database{
+					Constraints: []goschema.Constraint{
+						{
+							StructName:    "User",
+							Name:          "users_c_key",
+							Type:          "UNIQUE",
+							Table:         "users",
+							Columns:       []string{"c"},
+							NullsDistinct: &nullsDistinct,
+						},
+					},
+				}
+			}(),
+			database: func() *types.DBSchema {
+				nullsDistinct := false
+				return &types.DBSchema{
+					Constraints: []types.DBConstraint{
+						{
+							Name:          "users_c_key",
+							TableName:     "users",
+							Type:          "UNIQUE",
+							ColumnNames:   []string{"c"},
+							NullsDistinct: &nullsDistinct,
+						},
+					},
+				}
+			}(),
+			expected: &difftypes.SchemaDiff{},
+		},
 		{
 			name: "FOREIGN KEY constraint added",
 			generated: &goschema.Database{
@@ -1139,6 +1227,93 @@ func TestConstraints_FieldLevelCheck(t *testing.T) {
 			},
 			expected: &difftypes.SchemaDiff{},
 		},
+		{
+			name: "unnamed field-level CHECK matches MySQL auto-named check",
+			generated: &goschema.Database{
+				Tables: []goschema.Table{{StructName: "File", Name: "files"}},
+				Fields: []goschema.Field{
+					{StructName: "File", Name: "category", Type: "TEXT", Check: "category IN ('a','b')"},
+				},
+			},
+			database: &types.DBSchema{
+				Tables: []types.DBTable{filesTable},
+				Constraints: []types.DBConstraint{
+					{
+						Name:        "files_chk_1",
+						TableName:   "files",
+						Type:        "CHECK",
+						CheckClause: new("(`category` in (_utf8mb4'a',_utf8mb4'b'))"),
+					},
+				},
+			},
+			expected: &difftypes.SchemaDiff{},
+		},
+		{
+			name: "unnamed field-level CHECK matches MariaDB column-named check",
+			generated: &goschema.Database{
+				Tables: []goschema.Table{{StructName: "File", Name: "files"}},
+				Fields: []goschema.Field{
+					{StructName: "File", Name: "category", Type: "TEXT", Check: "category IN ('a','b')"},
+				},
+			},
+			database: &types.DBSchema{
+				Tables: []types.DBTable{filesTable},
+				Constraints: []types.DBConstraint{
+					{
+						Name:        "category",
+						TableName:   "files",
+						Type:        "CHECK",
+						CheckClause: new("`category` in ('a','b')"),
+					},
+				},
+			},
+			expected: &difftypes.SchemaDiff{},
+		},
+		{
+			name: "auto-named MySQL check with another expression is replaced",
+			generated: &goschema.Database{
+				Tables: []goschema.Table{{StructName: "File", Name: "files"}},
+				Fields: []goschema.Field{
+					{StructName: "File", Name: "category", Type: "TEXT", Check: "category IN ('a','b','c')"},
+				},
+			},
+			database: &types.DBSchema{
+				Tables: []types.DBTable{filesTable},
+				Constraints: []types.DBConstraint{
+					{
+						Name:        "files_chk_1",
+						TableName:   "files",
+						Type:        "CHECK",
+						CheckClause: new("(`category` in (_utf8mb4'a',_utf8mb4'b'))"),
+					},
+				},
+			},
+			expected: &difftypes.SchemaDiff{
+				ConstraintsAdded:   []string{"files_category_check"},
+				ConstraintsRemoved: []string{"files_chk_1"},
+			},
+		},
+		{
+			name: "MariaDB implicit JSON check belongs to the column",
+			generated: &goschema.Database{
+				Tables: []goschema.Table{{StructName: "File", Name: "files"}},
+				Fields: []goschema.Field{
+					{StructName: "File", Name: "category", Type: "JSON"},
+				},
+			},
+			database: &types.DBSchema{
+				Tables: []types.DBTable{filesTable},
+				Constraints: []types.DBConstraint{
+					{
+						Name:        "category",
+						TableName:   "files",
+						Type:        "CHECK",
+						CheckClause: new("json_valid(`category`)"),
+					},
+				},
+			},
+			expected: &difftypes.SchemaDiff{},
+		},
 		{
 			name: "NOT NULL CHECK (internal Postgres representation) is not touched by field-level CHECK synthesis",
 			generated: &goschema.Database{
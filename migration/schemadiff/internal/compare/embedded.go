@@ -27,27 +27,17 @@ func processEmbeddedFieldsForStruct(embeddedFields []goschema.EmbeddedField, all
 			generatedFields = processEmbeddedInlineModeRecursiveForSchemaDiff(generatedFields, embedded, allFields, embeddedFields, structName)
 		case "json":
 			// JSON MODE: Create a single JSON/JSONB column for the embedded struct
-			jsonField := goschema.Field{
-				StructName: structName,
-				FieldName:  embedded.EmbeddedTypeName,
-				Name:       embedded.Name,
-				Type:       embedded.Type,
-				Nullable:   embedded.Nullable,
-				Comment:    embedded.Comment,
-			}
-			generatedFields = append(generatedFields, jsonField)
+			// Same column as the generator path, including default and overrides
+			generatedFields = append(generatedFields, embedded.JSONColumn(structName))
 		case "relation":
-			// RELATION MODE: Create a foreign key field
-			// Create platform-specific overrides for MySQL/MariaDB behavior
-			overrides := make(map[string]map[string]string)
-			overrides["mysql"] = map[string]string{"type": "INT"}
-			overrides["mariadb"] = map[string]string{"type": "INT"}
+			// RELATION MODE: Create a foreign key field typed like the referenced column
+			refType, overrides := embedded.RelationColumnType()
 
 			relationField := goschema.Field{
 				StructName: structName,
 				FieldName:  embedded.EmbeddedTypeName + "ID",
 				Name:       embedded.Field,
-				Type:       "INTEGER", // Default to INTEGER for foreign keys
+				Type:       refType,
 				Foreign:    embedded.Ref,
 				OnDelete:   embedded.OnDelete, // Mirror of fromschema.processEmbeddedRelationMode — keeps the diff path in agreement with the generate path on FK actions (#117).
 				OnUpdate:   embedded.OnUpdate,
@@ -92,22 +92,35 @@ func Enums(generated *goschema.Database, database *types.DBSchema, diff *difftyp
 		dbEnums[enum.Name] = enum
 	}
 
+	// Renamed enums are neither added nor removed; their values are compared
+	// against the type under its old name.
+	renames := EnumRenames(generated, database)
+	renamedFrom := make(map[string]string, len(renames))
+	for oldName, newName := range renames {
+		renamedFrom[newName] = oldName
+		diff.EnumsRenamed = append(diff.EnumsRenamed, difftypes.EnumRename{OldName: oldName, NewName: newName})
+	}
+
 	// Find added and removed enums
 	for enumName := range genEnums {
-		if _, exists := dbEnums[enumName]; !exists {
+		if _, exists := dbEnums[enumName]; !exists && renamedFrom[enumName] == "" {
 			diff.EnumsAdded = append(diff.EnumsAdded, enumName)
 		}
 	}
 
 	for enumName := range dbEnums {
-		if _, exists := genEnums[enumName]; !exists {
+		if _, exists := genEnums[enumName]; !exists && renames[enumName] == "" {
 			diff.EnumsRemoved = append(diff.EnumsRemoved, enumName)
 		}
 	}
 
 	// Find modified enums
 	for enumName, genEnum := range genEnums {
-		if dbEnum, exists := dbEnums[enumName]; exists {
+		dbName := enumName
+		if oldName := renamedFrom[enumName]; oldName != "" {
+			dbName = oldName
+		}
+		if dbEnum, exists := dbEnums[dbName]; exists {
 			enumDiff := EnumValues(genEnum, dbEnum)
 			if len(enumDiff.ValuesAdded) > 0 || len(enumDiff.ValuesRemoved) > 0 {
 				diff.EnumsModified = append(diff.EnumsModified, enumDiff)
@@ -121,6 +134,48 @@ func Enums(generated *goschema.Database, database *types.DBSchema, diff *difftyp
 	sort.Slice(diff.EnumsModified, func(i, j int) bool {
 		return diff.EnumsModified[i].EnumName < diff.EnumsModified[j].EnumName
 	})
+	sort.Slice(diff.EnumsRenamed, func(i, j int) bool {
+		return diff.EnumsRenamed[i].NewName < diff.EnumsRenamed[j].NewName
+	})
+}
+
+// EnumRenames returns the enum renames declared with rename_from that apply
+// to database, keyed by old name. A rename applies only when the database has
+// the old type and not the new one, the target schema does not declare the
+// old name as well. Once it applies, a declared rename is never turned into a
+// drop and a create, whatever the values: added and removed values follow the
+// rename under the new name. Values alone never imply a rename, so two
+// unrelated enums that happen to share a value set are not taken for one.
+func EnumRenames(generated *goschema.Database, database *types.DBSchema) map[string]string {
+	if generated == nil || database == nil {
+		return nil
+	}
+	genNames := make(map[string]bool, len(generated.Enums))
+	for _, enum := range generated.Enums {
+		genNames[enum.Name] = true
+	}
+	dbNames := make(map[string]bool, len(database.Enums))
+	for _, enum := range database.Enums {
+		dbNames[enum.Name] = true
+	}
+
+	renames := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for _, enum := range generated.Enums {
+		oldName := enum.RenameFrom
+		if oldName == "" || oldName == enum.Name || !dbNames[oldName] || dbNames[enum.Name] || genNames[oldName] {
+			continue
+		}
+		if _, claimed := renames[oldName]; claimed {
+			// Two enums claim the same old type; rename neither.
+			ambiguous[oldName] = true
+		}
+		renames[oldName] = enum.Name
+	}
+	for oldName := range ambiguous {
+		delete(renames, oldName)
+	}
+	return renames
 }
 
 // EnumValues performs detailed value-level comparison between generated and database enum types.
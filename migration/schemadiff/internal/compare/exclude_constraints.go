@@ -0,0 +1,203 @@
+package compare
+
+import (
+	"strings"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
+	"github.com/stokaro/ptah/dbschema/types"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// ExcludeConstraints compares PostgreSQL EXCLUDE constraints between generated
+// and database schemas.
+//
+// EXCLUDE constraints come from //migrator:schema:exclude (or
+// //migrator:schema:constraint type="EXCLUDE") on the generated side and from
+// pg_constraint rows with contype = 'x' on the database side. They are matched
+// by table and constraint name; a constraint whose index method, element list
+// or WHERE predicate differs is reported as removed and added again, because
+// PostgreSQL cannot alter an exclusion constraint in place.
+//
+// The element list and predicate are normalized before comparison so the
+// canonical form returned by pg_get_constraintdef (quoted identifiers,
+// parenthesized expressions, literal casts, different spacing) matches the
+// annotation as written.
+//
+// EXCLUDE constraints only exist in PostgreSQL, so the comparison is skipped
+// when opts names any other dialect. Constraints calls this function and
+// leaves EXCLUDE constraints out of its own comparison.
+func ExcludeConstraints(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff, opts *config.CompareOptions) {
+	if opts != nil && opts.Dialect != "" && !platform.IsPostgresFamily(opts.Dialect) {
+		return
+	}
+
+	genConstraints := make(map[string]goschema.Constraint)
+	for _, constraint := range generated.Constraints {
+		if isExcludeConstraintType(constraint.Type) {
+			genConstraints[constraint.Table+"."+constraint.Name] = constraint
+		}
+	}
+	dbConstraints := make(map[string]types.DBConstraint)
+	for _, constraint := range database.Constraints {
+		if isExcludeConstraintType(constraint.Type) {
+			dbConstraints[constraint.QualifiedTableName()+"."+constraint.Name] = constraint
+		}
+	}
+	if len(genConstraints) == 0 && len(dbConstraints) == 0 {
+		return
+	}
+
+	for key, genConstraint := range genConstraints {
+		dbConstraint, exists := dbConstraints[key]
+		if exists && !excludeConstraintChanged(genConstraint, dbConstraint) {
+			continue
+		}
+		if exists {
+			diff.ConstraintsRemoved = append(diff.ConstraintsRemoved, dbConstraint.Name)
+			diff.ConstraintsRemovedWithTables = appendConstraintRemoval(diff.ConstraintsRemovedWithTables, dbConstraint)
+		}
+		diff.ConstraintsAdded = append(diff.ConstraintsAdded, genConstraint.Name)
+		diff.ConstraintsAddedWithTables = appendConstraintAddition(diff.ConstraintsAddedWithTables, genConstraint)
+	}
+	for key, dbConstraint := range dbConstraints {
+		if _, exists := genConstraints[key]; !exists {
+			diff.ConstraintsRemoved = append(diff.ConstraintsRemoved, dbConstraint.Name)
+			diff.ConstraintsRemovedWithTables = appendConstraintRemoval(diff.ConstraintsRemovedWithTables, dbConstraint)
+		}
+	}
+
+	sortConstraintDiff(diff)
+}
+
+func isExcludeConstraintType(constraintType string) bool {
+	return strings.EqualFold(strings.TrimSpace(constraintType), "EXCLUDE")
+}
+
+// excludeConstraintChanged compares EXCLUDE constraint definitions after
+// normalizing both sides.
+func excludeConstraintChanged(genConstraint goschema.Constraint, dbConstraint types.DBConstraint) bool {
+	if normalizeExcludeMethod(genConstraint.UsingMethod) != normalizeExcludeMethod(getStringValue(dbConstraint.UsingMethod)) {
+		return true
+	}
+	if normalizeExcludeElements(genConstraint.ExcludeElements) != normalizeExcludeElements(getStringValue(dbConstraint.ExcludeElements)) {
+		return true
+	}
+	return normalizeExcludePredicate(genConstraint.WhereCondition) != normalizeExcludePredicate(getStringValue(dbConstraint.WhereCondition))
+}
+
+// normalizeExcludeMethod lowercases the index method; PostgreSQL defaults
+// EXCLUDE constraints to btree when USING is omitted.
+func normalizeExcludeMethod(method string) string {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		return "btree"
+	}
+	return method
+}
+
+// normalizeExcludeElements normalizes an "expr WITH op, ..." element list
+// element by element: expressions lose identifier quotes, redundant outer
+// parentheses, literal casts and whitespace, and operators are trimmed.
+func normalizeExcludeElements(elements string) string {
+	parts := splitTopLevelSQLList(elements)
+	normalized := make([]string, 0, len(parts))
+	for _, part := range parts {
+		expr, operator := splitExcludeElement(part)
+		expr = normalizeCheckExpression(normalizeSQLCaseAndIdentifierQuotes(expr, ""))
+		normalized = append(normalized, expr+" with "+strings.Join(strings.Fields(operator), ""))
+	}
+	return strings.Join(normalized, ", ")
+}
+
+// splitExcludeElement splits one EXCLUDE element at its last top-level WITH
+// keyword into the expression (with any operator class) and the operator.
+func splitExcludeElement(element string) (expr, operator string) {
+	depth := 0
+	split := -1
+	for i := 0; i < len(element); i++ {
+		switch element[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '\'', '"':
+			i = skipQuotedSQL(element, i)
+		default:
+			if depth == 0 && isExcludeWithKeyword(element, i) {
+				split = i
+			}
+		}
+	}
+	if split < 0 {
+		return strings.TrimSpace(element), ""
+	}
+	return strings.TrimSpace(element[:split]), strings.TrimSpace(element[split+len("with"):])
+}
+
+func isExcludeWithKeyword(value string, start int) bool {
+	end := start + len("with")
+	if end > len(value) || !strings.EqualFold(value[start:end], "with") {
+		return false
+	}
+	if start > 0 && !isSQLWhitespace(value[start-1]) {
+		return false
+	}
+	return end < len(value) && isSQLWhitespace(value[end])
+}
+
+func normalizeExcludePredicate(predicate string) string {
+	if strings.TrimSpace(predicate) == "" {
+		return ""
+	}
+	return normalizeCheckExpression(normalizeSQLCaseAndIdentifierQuotes(predicate, ""))
+}
+
+// splitTopLevelSQLList splits value on commas that are outside parentheses
+// and quotes.
+func splitTopLevelSQLList(value string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '\'', '"':
+			i = skipQuotedSQL(value, i)
+		case ',':
+			if depth == 0 {
+				parts = appendNonEmptySQLPart(parts, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return appendNonEmptySQLPart(parts, value[start:])
+}
+
+func appendNonEmptySQLPart(parts []string, part string) []string {
+	if part = strings.TrimSpace(part); part != "" {
+		parts = append(parts, part)
+	}
+	return parts
+}
+
+// skipQuotedSQL returns the index of the quote closing the quoted section
+// that starts at start, treating doubled quotes as escapes.
+func skipQuotedSQL(value string, start int) int {
+	quote := value[start]
+	for i := start + 1; i < len(value); i++ {
+		if value[i] != quote {
+			continue
+		}
+		if i+1 < len(value) && value[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(value) - 1
+}
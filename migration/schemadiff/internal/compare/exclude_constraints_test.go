@@ -0,0 +1,109 @@
+package compare_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/config"
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff/internal/compare"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func bookingExcludeConstraint(elements, where string) goschema.Constraint {
+	return goschema.Constraint{
+		Name:            "no_overlapping_bookings",
+		Type:            "EXCLUDE",
+		Table:           "bookings",
+		UsingMethod:     "gist",
+		ExcludeElements: elements,
+		WhereCondition:  where,
+	}
+}
+
+func bookingExcludeDBConstraint(method, elements, where string) types.DBConstraint {
+	return types.DBConstraint{
+		Name:            "no_overlapping_bookings",
+		TableName:       "bookings",
+		Type:            "EXCLUDE",
+		UsingMethod:     &method,
+		ExcludeElements: &elements,
+		WhereCondition:  &where,
+	}
+}
+
+func excludeDiff(generated goschema.Constraint, database types.DBConstraint, dialect string) *difftypes.SchemaDiff {
+	diff := &difftypes.SchemaDiff{}
+	compare.ExcludeConstraints(
+		&goschema.Database{Constraints: []goschema.Constraint{generated}},
+		&types.DBSchema{Constraints: []types.DBConstraint{database}},
+		diff,
+		&config.CompareOptions{Dialect: dialect},
+	)
+	return diff
+}
+
+func TestExcludeConstraints_CanonicalDatabaseFormMatchesAnnotation(t *testing.T) {
+	c := qt.New(t)
+
+	diff := excludeDiff(
+		bookingExcludeConstraint("room_id WITH =, tsrange(starts_at,ends_at) WITH &&", "status = 'active'"),
+		bookingExcludeDBConstraint(
+			"GIST",
+			`"room_id" WITH =, tsrange(starts_at, ends_at) WITH &&`,
+			"(status = 'active'::text)",
+		),
+		"postgres",
+	)
+
+	c.Assert(diff.ConstraintsAdded, qt.HasLen, 0)
+	c.Assert(diff.ConstraintsRemoved, qt.HasLen, 0)
+}
+
+func TestExcludeConstraints_OperatorChangeRecreatesConstraint(t *testing.T) {
+	c := qt.New(t)
+
+	diff := excludeDiff(
+		bookingExcludeConstraint("room_id WITH =, during WITH &&", ""),
+		bookingExcludeDBConstraint("gist", "room_id WITH =, during WITH =", ""),
+		"postgres",
+	)
+
+	c.Assert(diff.ConstraintsRemoved, qt.DeepEquals, []string{"no_overlapping_bookings"})
+	c.Assert(diff.ConstraintsAdded, qt.DeepEquals, []string{"no_overlapping_bookings"})
+	c.Assert(diff.ConstraintsRemovedWithTables, qt.DeepEquals, []difftypes.ConstraintRemovalInfo{{
+		Name:      "no_overlapping_bookings",
+		TableName: "bookings",
+		Type:      "EXCLUDE",
+	}})
+}
+
+func TestExcludeConstraints_PredicateChangeRecreatesConstraint(t *testing.T) {
+	c := qt.New(t)
+
+	diff := excludeDiff(
+		bookingExcludeConstraint("room_id WITH =", "NOT cancelled"),
+		bookingExcludeDBConstraint("gist", "room_id WITH =", ""),
+		"postgres",
+	)
+
+	c.Assert(diff.ConstraintsRemoved, qt.DeepEquals, []string{"no_overlapping_bookings"})
+	c.Assert(diff.ConstraintsAdded, qt.DeepEquals, []string{"no_overlapping_bookings"})
+}
+
+func TestExcludeConstraints_SkippedOnMySQL(t *testing.T) {
+	c := qt.New(t)
+	diff := &difftypes.SchemaDiff{}
+
+	compare.ExcludeConstraints(
+		&goschema.Database{Constraints: []goschema.Constraint{bookingExcludeConstraint("room_id WITH =", "")}},
+		&types.DBSchema{},
+		diff,
+		&config.CompareOptions{Dialect: "mysql"},
+	)
+
+	c.Assert(diff.ConstraintsAdded, qt.HasLen, 0)
+	c.Assert(diff.ConstraintsRemoved, qt.HasLen, 0)
+}
@@ -28,7 +28,8 @@ import (
 // The function performs comparison in three phases:
 //  1. **Extension Filtering**: Removes ignored extensions from consideration
 //  2. **Extension Discovery**: Creates lookup maps for efficient extension comparison
-//  3. **Extension Diff Analysis**: Identifies added and removed extensions
+//  3. **Extension Diff Analysis**: Identifies added and removed extensions, and
+//     installed extensions whose version differs from a pinned version
 //
 // # PostgreSQL Extension Considerations
 //
@@ -57,6 +58,7 @@ import (
 // Modifies the provided diff parameter by populating:
 //   - diff.ExtensionsAdded: Extensions that need to be created
 //   - diff.ExtensionsRemoved: Extensions that exist in database but not in target schema
+//   - diff.ExtensionsModified: Extensions whose installed version differs from the annotation's version
 //
 // # Example Usage
 //
@@ -118,7 +120,26 @@ func Extensions(generated *goschema.Database, database *types.DBSchema, diff *di
 		}
 	}
 
+	// Find extensions whose installed version differs from a pinned version.
+	// Version comparison is opt-in: an annotation without a version accepts
+	// whatever is installed.
+	diff.ExtensionsModified = nil
+	for extensionName, genExtension := range genExtensions {
+		dbExtension, exists := dbExtensions[extensionName]
+		if !exists || genExtension.Version == "" || genExtension.Version == dbExtension.Version {
+			continue
+		}
+		diff.ExtensionsModified = append(diff.ExtensionsModified, difftypes.ExtensionDiff{
+			ExtensionName:  extensionName,
+			CurrentVersion: dbExtension.Version,
+			TargetVersion:  genExtension.Version,
+		})
+	}
+
 	// Sort for consistent output
 	sort.Strings(diff.ExtensionsAdded)
 	sort.Strings(diff.ExtensionsRemoved)
+	sort.Slice(diff.ExtensionsModified, func(i, j int) bool {
+		return diff.ExtensionsModified[i].ExtensionName < diff.ExtensionsModified[j].ExtensionName
+	})
 }
@@ -71,7 +71,7 @@ func TestExtensions(t *testing.T) {
 			expectedRemoved: []string{"uuid-ossp"},
 		},
 		{
-			name: "extensions with different versions - no version comparison",
+			name: "extensions with different versions - reported as modified, not added",
 			generatedExtensions: []goschema.Extension{
 				{Name: "postgis", Version: "3.1"},
 			},
@@ -123,6 +123,76 @@ func TestExtensions(t *testing.T) {
 	}
 }
 
+func TestExtensions_VersionPinning(t *testing.T) {
+	tests := []struct {
+		name                string
+		generatedExtensions []goschema.Extension
+		databaseExtensions  []types.DBExtension
+		expectedModified    []difftypes.ExtensionDiff
+	}{
+		{
+			name:                "pinned version newer than installed is an upgrade",
+			generatedExtensions: []goschema.Extension{{Name: "pg_trgm", Version: "1.6"}},
+			databaseExtensions:  []types.DBExtension{{Name: "pg_trgm", Version: "1.5", Schema: "public"}},
+			expectedModified: []difftypes.ExtensionDiff{
+				{ExtensionName: "pg_trgm", CurrentVersion: "1.5", TargetVersion: "1.6"},
+			},
+		},
+		{
+			name:                "pinned version older than installed is still reported",
+			generatedExtensions: []goschema.Extension{{Name: "pg_trgm", Version: "1.5"}},
+			databaseExtensions:  []types.DBExtension{{Name: "pg_trgm", Version: "1.6", Schema: "public"}},
+			expectedModified: []difftypes.ExtensionDiff{
+				{ExtensionName: "pg_trgm", CurrentVersion: "1.6", TargetVersion: "1.5"},
+			},
+		},
+		{
+			name:                "same version produces no change",
+			generatedExtensions: []goschema.Extension{{Name: "pg_trgm", Version: "1.6"}},
+			databaseExtensions:  []types.DBExtension{{Name: "pg_trgm", Version: "1.6", Schema: "public"}},
+			expectedModified:    nil,
+		},
+		{
+			name:                "unpinned version is not compared",
+			generatedExtensions: []goschema.Extension{{Name: "pg_trgm", IfNotExists: true}},
+			databaseExtensions:  []types.DBExtension{{Name: "pg_trgm", Version: "1.5", Schema: "public"}},
+			expectedModified:    nil,
+		},
+		{
+			name: "modified extensions are sorted by name",
+			generatedExtensions: []goschema.Extension{
+				{Name: "postgis", Version: "3.4"},
+				{Name: "btree_gin", Version: "1.3"},
+			},
+			databaseExtensions: []types.DBExtension{
+				{Name: "postgis", Version: "3.3", Schema: "public"},
+				{Name: "btree_gin", Version: "1.2", Schema: "public"},
+			},
+			expectedModified: []difftypes.ExtensionDiff{
+				{ExtensionName: "btree_gin", CurrentVersion: "1.2", TargetVersion: "1.3"},
+				{ExtensionName: "postgis", CurrentVersion: "3.3", TargetVersion: "3.4"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			diff := &difftypes.SchemaDiff{}
+			compare.Extensions(
+				&goschema.Database{Extensions: tt.generatedExtensions},
+				&types.DBSchema{Extensions: tt.databaseExtensions},
+				diff, nil,
+			)
+
+			c.Assert(diff.ExtensionsModified, qt.DeepEquals, tt.expectedModified)
+			c.Assert(diff.ExtensionsAdded, qt.HasLen, 0)
+			c.Assert(diff.ExtensionsRemoved, qt.HasLen, 0)
+		})
+	}
+}
+
 func TestExtensions_RealWorldScenarios(t *testing.T) {
 	tests := []struct {
 		name        string
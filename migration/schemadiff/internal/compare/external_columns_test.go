@@ -0,0 +1,95 @@
+package compare_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff/internal/compare"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestTableColumns_ExternalColumns(t *testing.T) {
+	tests := []struct {
+		name             string
+		external         []string
+		expectedRemoved  []string
+		expectedModified int
+		expectedExternal []string
+	}{
+		{
+			name:             "undeclared columns are dropped",
+			expectedRemoved:  []string{"audit_hash", "search_vector"},
+			expectedModified: 1,
+		},
+		{
+			name:             "external columns are skipped",
+			external:         []string{"search_vector", "audit_hash"},
+			expectedExternal: []string{"audit_hash", "search_vector"},
+			expectedModified: 1,
+		},
+		{
+			name:             "missing external column is not reported",
+			external:         []string{"search_vector", "legacy"},
+			expectedRemoved:  []string{"audit_hash"},
+			expectedExternal: []string{"search_vector"},
+			expectedModified: 1,
+		},
+		{
+			name:             "declared external column is not compared",
+			external:         []string{"title", "search_vector", "audit_hash"},
+			expectedExternal: []string{"audit_hash", "search_vector", "title"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			genTable := goschema.Table{StructName: "Article", Name: "articles", ExternalColumns: tt.external}
+			generated := &goschema.Database{
+				Tables: []goschema.Table{genTable},
+				Fields: []goschema.Field{
+					{StructName: "Article", Name: "title", Type: "TEXT", Nullable: true},
+				},
+			}
+			dbTable := types.DBTable{Name: "articles", Columns: []types.DBColumn{
+				{Name: "title", DataType: "character varying", UDTName: "varchar", IsNullable: "NO"},
+				{Name: "search_vector", DataType: "tsvector", UDTName: "tsvector", IsNullable: "YES"},
+				{Name: "audit_hash", DataType: "text", UDTName: "text", IsNullable: "YES"},
+			}}
+
+			tableDiff := compare.TableColumnsWithDialect(genTable, dbTable, generated, "postgres")
+
+			c.Assert(tableDiff.ColumnsRemoved, qt.DeepEquals, tt.expectedRemoved)
+			c.Assert(tableDiff.ColumnsExternal, qt.DeepEquals, tt.expectedExternal)
+			c.Assert(tableDiff.ColumnsModified, qt.HasLen, tt.expectedModified)
+		})
+	}
+}
+
+func TestTablesAndColumns_ExternalColumnsDoNotModifyTable(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{
+		Tables: []goschema.Table{
+			{StructName: "Article", Name: "articles", ExternalColumns: []string{"search_vector"}},
+		},
+		Fields: []goschema.Field{
+			{StructName: "Article", Name: "title", Type: "TEXT", Nullable: true},
+		},
+	}
+	database := &types.DBSchema{Tables: []types.DBTable{
+		{Name: "articles", Columns: []types.DBColumn{
+			{Name: "title", DataType: "text", UDTName: "text", IsNullable: "YES"},
+			{Name: "search_vector", DataType: "tsvector", UDTName: "tsvector", IsNullable: "YES"},
+		}},
+	}}
+
+	diff := &difftypes.SchemaDiff{}
+	compare.TablesAndColumnsWithDialect(generated, database, diff, "postgres")
+
+	c.Assert(diff.TablesModified, qt.HasLen, 0)
+}
@@ -95,6 +95,15 @@ func isFieldLevelConstraint(dbConstraint types.DBConstraint, generated *goschema
 		if strings.Contains(dbConstraint.Name, "_not_null") {
 			return true
 		}
+		// MariaDB adds CHECK (json_valid(col)) to every JSON column and names
+		// it after the column. The renderer emits it with the column, so it
+		// is owned by the column lifecycle unless the field declares the
+		// check itself.
+		key := dbConstraint.QualifiedTableName() + "." + dbConstraint.Name
+		if field, exists := fieldMap[key]; exists && field.Check == "" &&
+			strings.EqualFold(normalizeCheckExpression(getStringValue(dbConstraint.CheckClause)), "json_valid("+field.Name+")") {
+			return true
+		}
 		// Regular CHECK constraints from `check=` annotations are surfaced
 		// to the diff via synthesized goschema.Constraint entries (see
 		// synthesizeFieldLevelCheckConstraints in Constraints). Letting the
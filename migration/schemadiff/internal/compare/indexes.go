@@ -1,9 +1,11 @@
 package compare
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/core/platform"
 	"github.com/stokaro/ptah/dbschema/types"
@@ -84,6 +86,22 @@ func isConstraintBasedUniqueIndex(indexName, tableName string, columns []string)
 	return false
 }
 
+// isImplicitUniqueIndex reports whether a database unique index backs a UNIQUE
+// constraint and so is compared with the constraints rather than the indexes.
+// The naming heuristic of isConstraintBasedUniqueIndex is only a guess: an
+// index with a WHERE predicate can never back a constraint, and a unique index
+// the schema declares under the same name is compared as an index, so a
+// partial unique index such as users_email_key stays visible to the diff.
+func isImplicitUniqueIndex(index types.DBIndex, genIndexes map[string]goschema.Index) bool {
+	if !index.IsUnique || strings.TrimSpace(index.Condition) != "" {
+		return false
+	}
+	if genIndex, ok := genIndexes[index.Name]; ok && genIndex.Unique {
+		return false
+	}
+	return isConstraintBasedUniqueIndex(index.Name, index.TableName, index.Columns)
+}
+
 // isMySQLConstraintBasedUniqueIndex checks if an index follows MySQL/MariaDB constraint-based patterns.
 // This helper function encapsulates the complex logic for detecting MySQL/MariaDB constraint-based
 // unique indexes that follow table_column naming patterns but are not custom indexes.
@@ -177,6 +195,13 @@ func Indexes(generated *goschema.Database, database *types.DBSchema, diff *difft
 }
 
 func IndexesWithDialect(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff, dialect string) {
+	IndexesWithOptions(generated, database, diff, &config.CompareOptions{Dialect: dialect})
+}
+
+// IndexesWithOptions compares indexes like IndexesWithDialect, taking the
+// dialect and the storage parameter policy from opts.
+func IndexesWithOptions(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff, opts *config.CompareOptions) {
+	dialect := opts.Dialect
 	// Create sets for comparison
 	genIndexes := make(map[string]goschema.Index)
 	for _, index := range generated.Indexes {
@@ -214,7 +239,7 @@ func IndexesWithDialect(generated *goschema.Database, database *types.DBSchema,
 
 		// Skip constraint-based unique indexes (automatically created by UNIQUE constraints)
 		// but allow explicitly defined unique indexes (created via schema annotations)
-		if index.IsUnique && isConstraintBasedUniqueIndex(index.Name, index.TableName, index.Columns) {
+		if isImplicitUniqueIndex(index, genIndexes) {
 			continue
 		}
 		if _, ok := uniqueConstraintIndexes[index.QualifiedTableName()+"."+index.Name]; ok {
@@ -236,12 +261,21 @@ func IndexesWithDialect(generated *goschema.Database, database *types.DBSchema,
 		switch {
 		case !exists:
 			diff.IndexesAdded = append(diff.IndexesAdded, indexName)
-		case indexDefinitionsChanged(genIndex, dbIndex):
+		case indexDefinitionsChanged(genIndex, dbIndex) ||
+			mysqlIndexTypeChanged(genIndex, dbIndex, dialect) ||
+			indexStorageParamsChanged(genIndex.StorageParams, dbIndex.StorageParams, opts.StrictIndexStorageParams):
 			diff.IndexesAdded = append(diff.IndexesAdded, indexName)
 			diff.IndexesRemoved = append(diff.IndexesRemoved, indexName)
 			diff.IndexesRemovedWithTables = append(diff.IndexesRemovedWithTables, difftypes.IndexRemovalInfo{
 				Name:      indexName,
 				TableName: dbIndex.QualifiedTableName(),
+				Columns:   dbIndex.Columns,
+			})
+		case mysqlIndexVisibilityChanged(genIndex, dbIndex, dialect):
+			diff.IndexesVisibilityChanged = append(diff.IndexesVisibilityChanged, difftypes.IndexVisibilityChange{
+				Name:      indexName,
+				TableName: dbIndex.QualifiedTableName(),
+				Visible:   indexVisible(genIndex),
 			})
 		}
 	}
@@ -252,6 +286,7 @@ func IndexesWithDialect(generated *goschema.Database, database *types.DBSchema,
 			diff.IndexesRemovedWithTables = append(diff.IndexesRemovedWithTables, difftypes.IndexRemovalInfo{
 				Name:      indexName,
 				TableName: dbIndex.QualifiedTableName(),
+				Columns:   dbIndex.Columns,
 			})
 		}
 	}
@@ -264,6 +299,9 @@ func IndexesWithDialect(generated *goschema.Database, database *types.DBSchema,
 	sort.Slice(diff.IndexesRemovedWithTables, func(i, j int) bool {
 		return diff.IndexesRemovedWithTables[i].Name < diff.IndexesRemovedWithTables[j].Name
 	})
+	sort.Slice(diff.IndexesVisibilityChanged, func(i, j int) bool {
+		return diff.IndexesVisibilityChanged[i].Name < diff.IndexesVisibilityChanged[j].Name
+	})
 }
 
 func isSQLiteInternalAutoindex(indexName, dialect string) bool {
@@ -272,8 +310,178 @@ func isSQLiteInternalAutoindex(indexName, dialect string) bool {
 }
 
 func indexDefinitionsChanged(genIndex goschema.Index, dbIndex types.DBIndex) bool {
-	return !boolPtrEqual(genIndex.NullsDistinct, dbIndex.NullsDistinct) ||
-		indexPredicateChanged(genIndex.Condition, dbIndex.Condition)
+	return !nullsDistinctEqual(genIndex.NullsDistinct, dbIndex.NullsDistinct) ||
+		indexPredicateChanged(genIndex.Condition, dbIndex.Condition) ||
+		indexExpressionsChanged(genIndex, dbIndex) ||
+		indexOperatorClassesChanged(genIndex, dbIndex)
+}
+
+// indexOperatorClassesChanged reports whether the declared PostgreSQL
+// operator classes differ from the database ones. A key without a declared
+// class matches the default class for its type, which pg_get_indexdef omits;
+// a declared class matches by name, so spelling out a default such as
+// jsonb_ops does not churn either. Readers that report no operator classes
+// never produce a change.
+func indexOperatorClassesChanged(genIndex goschema.Index, dbIndex types.DBIndex) bool {
+	declared := generatedIndexOperatorClasses(genIndex)
+	if len(dbIndex.OperatorClasses) == 0 || len(declared) != len(dbIndex.OperatorClasses) {
+		return false
+	}
+	for i, opClass := range dbIndex.OperatorClasses {
+		if !operatorClassMatches(declared[i], opClass) {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedIndexOperatorClasses returns the declared operator class of each
+// key, falling back to the index-wide class for parts without their own.
+func generatedIndexOperatorClasses(index goschema.Index) []string {
+	keys := generatedIndexKeys(index)
+	opClasses := make([]string, len(keys))
+	for i := range keys {
+		opClasses[i] = index.Operator
+		if i < len(index.Parts) && index.Parts[i].Operator != "" {
+			opClasses[i] = index.Parts[i].Operator
+		}
+	}
+	return opClasses
+}
+
+func operatorClassMatches(declared string, opClass types.DBIndexOperatorClass) bool {
+	declared = strings.TrimSpace(declared)
+	if declared == "" {
+		return opClass.Default
+	}
+	if _, name, ok := strings.Cut(declared, "."); ok {
+		declared = name
+	}
+	return strings.EqualFold(strings.Trim(declared, `"`), opClass.Name)
+}
+
+// indexStorageParamsChanged reports whether the declared index storage
+// parameters differ from the database ones. An index whose annotation
+// declares no parameters keeps whatever the database has, so a fillfactor a
+// DBA tuned by hand is not dropped, unless strict is set. Keys and values are
+// compared case-insensitively because PostgreSQL stores reloptions as written.
+func indexStorageParamsChanged(generated, database map[string]string, strict bool) bool {
+	if len(generated) == 0 && !strict {
+		return false
+	}
+	if len(generated) != len(database) {
+		return true
+	}
+	normalized := make(map[string]string, len(database))
+	for key, value := range database {
+		normalized[strings.ToLower(key)] = normalizeStorageParamValue(value)
+	}
+	for key, value := range generated {
+		dbValue, ok := normalized[strings.ToLower(key)]
+		if !ok || dbValue != normalizeStorageParamValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeStorageParamValue(value string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(value), `'"`))
+}
+
+// mysqlIndexTypeChanged reports whether a MySQL/MariaDB index changed
+// between an ordinary (BTREE or HASH), FULLTEXT and SPATIAL index. Neither
+// engine can change the type of an existing index, so a change is planned as
+// drop and recreate.
+//
+// A generated HASH index matches a database BTREE index: InnoDB accepts
+// USING HASH but silently builds a B-tree, so treating them as different
+// would re-plan the index on every run. Index types of other dialects
+// (PostgreSQL access methods, ClickHouse skipping indexes) are compared
+// elsewhere or not at all.
+func mysqlIndexTypeChanged(genIndex goschema.Index, dbIndex types.DBIndex, dialect string) bool {
+	if !isMySQLFamilyDialect(dialect) {
+		return false
+	}
+	genType := normalizeMySQLIndexType(genIndex.Type)
+	dbType := normalizeMySQLIndexType(dbIndex.Type)
+	if genType == "HASH" && dbType == "BTREE" {
+		return false
+	}
+	return genType != dbType
+}
+
+// mysqlIndexVisibilityChanged reports whether the declared MySQL/MariaDB index
+// visibility differs from the database. Other dialects have no invisible
+// indexes, so the attribute never produces a change there.
+func mysqlIndexVisibilityChanged(genIndex goschema.Index, dbIndex types.DBIndex, dialect string) bool {
+	return isMySQLFamilyDialect(dialect) && indexVisible(genIndex) == dbIndex.Invisible
+}
+
+// indexVisible reports the declared visibility; an unspecified attribute
+// means visible.
+func indexVisible(index goschema.Index) bool {
+	return index.Visible == nil || *index.Visible
+}
+
+// normalizeMySQLIndexType upper-cases a MySQL index type and maps the empty
+// value and types MySQL does not know (for example a PostgreSQL "gin" shared
+// with a PostgreSQL target) to the BTREE default the renderer falls back to.
+func normalizeMySQLIndexType(indexType string) string {
+	indexType = strings.ToUpper(strings.TrimSpace(indexType))
+	switch indexType {
+	case "HASH", "FULLTEXT", "SPATIAL":
+		return indexType
+	default:
+		return "BTREE"
+	}
+}
+
+// indexExpressionsChanged compares index key parts when either side is an
+// expression index. Plain column indexes keep name-only matching so that
+// dialect-specific column spellings do not cause churn.
+//
+// Databases store expressions in a canonical form (PostgreSQL renders
+// lower(email) as lower((email)::text), MySQL wraps identifiers in
+// backticks), so both sides are normalized before comparison.
+func indexExpressionsChanged(genIndex goschema.Index, dbIndex types.DBIndex) bool {
+	generated := generatedIndexKeys(genIndex)
+	if !slices.ContainsFunc(generated, isIndexExpression) && !slices.ContainsFunc(dbIndex.Columns, isIndexExpression) {
+		return false
+	}
+	if len(generated) != len(dbIndex.Columns) {
+		return true
+	}
+	for i := range generated {
+		if normalizeIndexExpression(generated[i]) != normalizeIndexExpression(dbIndex.Columns[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func generatedIndexKeys(index goschema.Index) []string {
+	if len(index.Parts) == 0 {
+		return index.Fields
+	}
+	keys := make([]string, 0, len(index.Parts))
+	for _, part := range index.Parts {
+		if part.Expr != "" {
+			keys = append(keys, part.Expr)
+			continue
+		}
+		keys = append(keys, part.Name)
+	}
+	return keys
+}
+
+func isIndexExpression(key string) bool {
+	return strings.ContainsAny(key, "()")
+}
+
+func normalizeIndexExpression(expr string) string {
+	normalized := normalizeCheckExpression(strings.ReplaceAll(expr, `"`, ""))
+	return trimBalancedCheckParens(indexColumnCastPattern.ReplaceAllString(normalized, "$1"))
 }
 
 func indexPredicateChanged(generated, database string) bool {
@@ -0,0 +1,90 @@
+package compare_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/migration/schemadiff/internal/compare"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestTableInheritance(t *testing.T) {
+	tests := []struct {
+		name            string
+		dialect         string
+		genInherits     []string
+		dbInherits      []string
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{name: "unchanged", genInherits: []string{"cities"}, dbInherits: []string{"cities"}},
+		{name: "quoted parent matches", dialect: "postgres", genInherits: []string{`"cities"`}, dbInherits: []string{"cities"}},
+		{name: "parent added", dialect: "postgres", genInherits: []string{"cities", "geo.places"}, dbInherits: []string{"cities"}, expectedAdded: []string{"geo.places"}},
+		{name: "parent removed", dialect: "postgres", dbInherits: []string{"cities"}, expectedRemoved: []string{"cities"}},
+		{name: "parent replaced", genInherits: []string{"towns"}, dbInherits: []string{"cities"}, expectedAdded: []string{"towns"}, expectedRemoved: []string{"cities"}},
+		{name: "skipped on mysql", dialect: "mysql", genInherits: []string{"cities"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			tableDiff := difftypes.TableDiff{TableName: "capitals"}
+			compare.TableInheritance(
+				goschema.Table{Name: "capitals", Inherits: tt.genInherits},
+				types.DBTable{Name: "capitals", Inherits: tt.dbInherits},
+				&tableDiff,
+				tt.dialect,
+			)
+
+			c.Assert(tableDiff.InheritsAdded, qt.DeepEquals, tt.expectedAdded)
+			c.Assert(tableDiff.InheritsRemoved, qt.DeepEquals, tt.expectedRemoved)
+		})
+	}
+}
+
+func TestTablesAndColumns_InheritedColumnsAreNotRemoved(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{
+		Tables: []goschema.Table{
+			{StructName: "City", Name: "cities"},
+			{StructName: "Capital", Name: "capitals", Inherits: []string{"cities"}},
+		},
+		Fields: []goschema.Field{
+			{StructName: "City", Name: "name", Type: "TEXT", Nullable: true},
+			{StructName: "Capital", Name: "state", Type: "TEXT", Nullable: true},
+		},
+	}
+	database := &types.DBSchema{Tables: []types.DBTable{
+		{Name: "cities", Columns: []types.DBColumn{
+			{Name: "name", DataType: "text", UDTName: "text", IsNullable: "YES"},
+		}},
+		{Name: "capitals", Inherits: []string{"cities"}, Columns: []types.DBColumn{
+			{Name: "name", DataType: "text", UDTName: "text", IsNullable: "YES", Inherited: true},
+			{Name: "state", DataType: "text", UDTName: "text", IsNullable: "YES"},
+		}},
+	}}
+
+	diff := &difftypes.SchemaDiff{}
+	compare.TablesAndColumnsWithDialect(generated, database, diff, "postgres")
+
+	c.Assert(diff.TablesModified, qt.HasLen, 0)
+}
+
+func TestTablesAndColumns_ReportsInheritanceChange(t *testing.T) {
+	c := qt.New(t)
+
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{StructName: "Capital", Name: "capitals", Inherits: []string{"cities"}}},
+	}
+	database := &types.DBSchema{Tables: []types.DBTable{{Name: "capitals"}}}
+
+	diff := &difftypes.SchemaDiff{}
+	compare.TablesAndColumnsWithDialect(generated, database, diff, "postgres")
+
+	c.Assert(diff.TablesModified, qt.DeepEquals, []difftypes.TableDiff{{TableName: "capitals", InheritsAdded: []string{"cities"}}})
+}
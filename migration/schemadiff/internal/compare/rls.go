@@ -6,6 +6,7 @@ import (
 
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
+	"github.com/stokaro/ptah/internal/convert/fromschema"
 	"github.com/stokaro/ptah/migration/schemadiff/internal/normalize"
 	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
 )
@@ -69,14 +70,20 @@ import (
 // Results are sorted alphabetically for consistent output across multiple runs.
 func RLSPolicies(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff) {
 	// Build lookup maps for RLS policy comparison
+	// Policies are keyed by schema and name. Calls to schema-qualified
+	// functions are qualified on both sides, matching what the planner emits
+	// and covering catalogs that print them bare because the schema is on
+	// the reading connection's search_path.
 	generatedPolicyMap := make(map[string]goschema.RLSPolicy)
 	for _, rlsPolicy := range generated.RLSPolicies {
-		generatedPolicyMap[rlsPolicy.Name] = rlsPolicy
+		generatedPolicyMap[rlsPolicy.QualifiedName()] = fromschema.QualifyPolicyFunctionCalls(rlsPolicy, generated.Functions)
 	}
 
 	databasePolicyMap := make(map[string]types.DBRLSPolicy)
 	for _, rlsPolicy := range database.RLSPolicies {
-		databasePolicyMap[rlsPolicy.Name] = rlsPolicy
+		rlsPolicy.UsingExpression = fromschema.QualifyFunctionCalls(rlsPolicy.UsingExpression, generated.Functions)
+		rlsPolicy.WithCheckExpression = fromschema.QualifyFunctionCalls(rlsPolicy.WithCheckExpression, generated.Functions)
+		databasePolicyMap[rlsPolicy.QualifiedName()] = rlsPolicy
 	}
 
 	// Find added policies (inline logic to avoid duplication detection)
@@ -90,7 +97,7 @@ func RLSPolicies(generated *goschema.Database, database *types.DBSchema, diff *d
 	for policyName, dbPolicy := range databasePolicyMap {
 		if _, exists := generatedPolicyMap[policyName]; !exists {
 			policyRef := difftypes.RLSPolicyRef{
-				PolicyName: policyName,
+				PolicyName: dbPolicy.Name,
 				TableName:  dbPolicy.Table,
 			}
 			diff.RLSPoliciesRemoved = append(diff.RLSPoliciesRemoved, policyRef)
@@ -243,7 +250,7 @@ func RLSEnabledTables(generated *goschema.Database, database *types.DBSchema, di
 func RLSPolicyDefinitions(genPolicy goschema.RLSPolicy, dbPolicy types.DBRLSPolicy) difftypes.RLSPolicyDiff {
 	policyDiff := difftypes.RLSPolicyDiff{
 		PolicyName: genPolicy.Name,
-		TableName:  genPolicy.Table,
+		TableName:  genPolicy.QualifiedTable(),
 		Changes:    make(map[string]string),
 	}
 
@@ -3,7 +3,9 @@ package compare
 import (
 	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
 	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
@@ -58,6 +60,7 @@ import (
 //   - generated: Target schema parsed from Go struct annotations
 //   - database: Current database schema from database introspection
 //   - diff: SchemaDiff structure to populate with discovered differences
+//   - opts: Comparison options; SkipRolePasswords drops password changes
 //
 // # Side Effects
 //
@@ -65,11 +68,14 @@ import (
 //   - diff.RolesAdded: Roles that need to be created
 //   - diff.RolesRemoved: Always empty (roles are not automatically removed for safety)
 //   - diff.RolesModified: Roles with attribute differences
+//   - diff.RoleMembershipsAdded: Declared member_of memberships to grant
+//   - diff.RoleMembershipsRemoved: Undeclared memberships to revoke, only
+//     with opts.RevokeUndeclaredRoleMemberships
 //
 // # Output Consistency
 //
 // Results are sorted alphabetically for consistent output across multiple runs.
-func Roles(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff) {
+func Roles(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff, opts *config.CompareOptions) {
 	// Build lookup maps for role comparison
 	generatedRoleMap := make(map[string]goschema.Role)
 	for _, role := range generated.Roles {
@@ -94,10 +100,15 @@ func Roles(generated *goschema.Database, database *types.DBSchema, diff *difftyp
 	// be dangerous and break authentication/authorization.
 	// If role removal is needed, it should be done explicitly by the DBA.
 
+	roleMemberships(generatedRoleMap, databaseRoleMap, diff, opts != nil && opts.RevokeUndeclaredRoleMemberships)
+
 	// Detect role attribute modifications
 	for roleName, generatedRole := range generatedRoleMap {
 		if databaseRole, roleExists := databaseRoleMap[roleName]; roleExists {
 			roleComparison := RoleDefinitions(generatedRole, databaseRole)
+			if opts != nil && opts.SkipRolePasswords {
+				delete(roleComparison.Changes, "password")
+			}
 			if len(roleComparison.Changes) > 0 {
 				diff.RolesModified = append(diff.RolesModified, roleComparison)
 			}
@@ -112,6 +123,47 @@ func Roles(generated *goschema.Database, database *types.DBSchema, diff *difftyp
 	})
 }
 
+// roleMemberships records the member_of memberships missing from the
+// database. Undeclared memberships of annotated roles are recorded for
+// revocation only when revokeUndeclared is set, because they may have been
+// granted outside the migrations.
+func roleMemberships(generated map[string]goschema.Role, database map[string]types.DBRole, diff *difftypes.SchemaDiff, revokeUndeclared bool) {
+	for memberName, role := range generated {
+		declared := make(map[string]bool, len(role.MemberOf))
+		for _, granted := range role.MemberOf {
+			declared[granted] = true
+		}
+		existing := make(map[string]bool)
+		for _, granted := range database[memberName].MemberOf {
+			existing[granted] = true
+		}
+		for granted := range declared {
+			if !existing[granted] {
+				diff.RoleMembershipsAdded = append(diff.RoleMembershipsAdded, difftypes.RoleMembership{Role: granted, Member: memberName})
+			}
+		}
+		if !revokeUndeclared {
+			continue
+		}
+		for granted := range existing {
+			if !declared[granted] {
+				diff.RoleMembershipsRemoved = append(diff.RoleMembershipsRemoved, difftypes.RoleMembership{Role: granted, Member: memberName})
+			}
+		}
+	}
+	sortRoleMemberships(diff.RoleMembershipsAdded)
+	sortRoleMemberships(diff.RoleMembershipsRemoved)
+}
+
+func sortRoleMemberships(memberships []difftypes.RoleMembership) {
+	sort.Slice(memberships, func(i, j int) bool {
+		if memberships[i].Member != memberships[j].Member {
+			return memberships[i].Member < memberships[j].Member
+		}
+		return memberships[i].Role < memberships[j].Role
+	})
+}
+
 // RoleDefinitions compares individual role definitions and returns detailed differences.
 //
 // This function performs attribute-by-attribute comparison of PostgreSQL role definitions,
@@ -175,9 +227,7 @@ func RoleDefinitions(generated goschema.Role, database types.DBRole) difftypes.R
 	}
 
 	// Compare password (special handling for security)
-	// We only detect if a password needs to be set, not compare actual values
-	if generated.Password != "" && !database.HasPassword {
-		// If target has password but database role doesn't, mark for update
+	if passwordUpdateRequired(generated, database) {
 		roleDiff.Changes["password"] = "password_update_required"
 	}
 
@@ -208,3 +258,26 @@ func RoleDefinitions(generated goschema.Role, database types.DBRole) difftypes.R
 
 	return roleDiff
 }
+
+// scramVerifierPrefix starts a PostgreSQL SCRAM-SHA-256 password verifier,
+// the form rolpassword stores under password_encryption=scram-sha-256.
+const scramVerifierPrefix = "SCRAM-SHA-256$"
+
+// passwordUpdateRequired reports whether the role password has to be set.
+// A role without a password in the database always needs one. Otherwise
+// only a pre-hashed SCRAM verifier can be checked: it is compared with the
+// stored verifier when the reader could see it (pg_authid is readable by
+// superusers only). Plaintext and environment passwords cannot be compared,
+// so an existing password is kept.
+func passwordUpdateRequired(generated goschema.Role, database types.DBRole) bool {
+	if generated.Password == "" && generated.PasswordEnv == "" {
+		return false
+	}
+	if !database.HasPassword {
+		return true
+	}
+	if generated.PasswordEnv != "" || !strings.HasPrefix(generated.Password, scramVerifierPrefix) || database.PasswordHash == "" {
+		return false
+	}
+	return generated.Password != database.PasswordHash
+}
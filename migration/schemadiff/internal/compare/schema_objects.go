@@ -83,18 +83,21 @@ func Functions(generated *goschema.Database, database *types.DBSchema, diff *dif
 	// Build lookup maps for function comparison
 	generatedFunctionMap := make(map[string]goschema.Function)
 	for _, fn := range generated.Functions {
-		generatedFunctionMap[fn.Name] = fn
+		generatedFunctionMap[fn.QualifiedName()] = fn
 	}
 
 	databaseFunctionMap := make(map[string]types.DBFunction)
 	for _, fn := range database.Functions {
-		databaseFunctionMap[fn.Name] = fn
+		databaseFunctionMap[fn.QualifiedName()] = fn
 	}
 
 	// Use generic comparison helper for add/remove detection
 	addedFunctions, removedFunctions := compareNamedItems(generatedFunctionMap, databaseFunctionMap)
 	diff.FunctionsAdded = append(diff.FunctionsAdded, addedFunctions...)
 	diff.FunctionsRemoved = append(diff.FunctionsRemoved, removedFunctions...)
+	for _, functionName := range removedFunctions {
+		functionTriggers(databaseFunctionMap[functionName], diff)
+	}
 
 	// Detect function definition modifications
 	for functionName, generatedFunction := range generatedFunctionMap {
@@ -114,6 +117,23 @@ func Functions(generated *goschema.Database, database *types.DBSchema, diff *dif
 	})
 }
 
+// functionTriggers records the triggers still executing fn, which is being
+// removed, so the planner can tell whether DROP FUNCTION can succeed.
+func functionTriggers(fn types.DBFunction, diff *difftypes.SchemaDiff) {
+	if len(fn.Triggers) == 0 {
+		return
+	}
+	refs := make([]difftypes.TriggerRef, 0, len(fn.Triggers))
+	for _, trigger := range fn.Triggers {
+		refs = append(refs, difftypes.TriggerRef{TriggerName: trigger.Name, TableName: trigger.QualifiedTable()})
+	}
+	sortTriggerRefs(refs)
+	if diff.FunctionTriggers == nil {
+		diff.FunctionTriggers = make(map[string][]difftypes.TriggerRef)
+	}
+	diff.FunctionTriggers[fn.QualifiedName()] = refs
+}
+
 // Views compares view definitions between generated and database schemas.
 func Views(generated *goschema.Database, database *types.DBSchema, diff *difftypes.SchemaDiff) {
 	ViewsWithDialect(generated, database, diff, "")
@@ -281,7 +301,7 @@ func MaterializedViews(generated *goschema.Database, database *types.DBSchema, d
 //  2. CREATE OR REPLACE FUNCTION with new definition
 func FunctionDefinitions(genFunction goschema.Function, dbFunction types.DBFunction) difftypes.FunctionDiff {
 	functionDiff := difftypes.FunctionDiff{
-		FunctionName: genFunction.Name,
+		FunctionName: genFunction.QualifiedName(),
 		Changes:      make(map[string]string),
 	}
 
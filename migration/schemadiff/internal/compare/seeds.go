@@ -0,0 +1,38 @@
+package compare
+
+import (
+	"slices"
+
+	"github.com/stokaro/ptah/core/goschema"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+// Seeds selects the seed rows that accompany the changes already in diff.
+//
+// A seed is included when diff creates its table, or when it is declared
+// always="true" and diff has any other change. Table contents are never
+// introspected, so seeds must be selected after every other comparison and
+// never turn an otherwise empty diff into a migration.
+func Seeds(generated *goschema.Database, diff *difftypes.SchemaDiff) {
+	if len(generated.Seeds) == 0 || !diff.HasChanges() {
+		return
+	}
+	tablesAdded := make(map[string]bool, len(diff.TablesAdded))
+	for _, table := range diff.TablesAdded {
+		tablesAdded[table] = true
+	}
+	for _, seed := range generated.Seeds {
+		if !seed.Always && !tablesAdded[seed.Table] {
+			continue
+		}
+		rows := make([][]string, len(seed.Rows))
+		for i, row := range seed.Rows {
+			rows[i] = slices.Clone(row)
+		}
+		diff.SeedsAdded = append(diff.SeedsAdded, difftypes.SeedData{
+			Table:   seed.Table,
+			Columns: slices.Clone(seed.Columns),
+			Rows:    rows,
+		})
+	}
+}
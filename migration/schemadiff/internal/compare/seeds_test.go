@@ -0,0 +1,43 @@
+package compare_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/migration/schemadiff/internal/compare"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
+)
+
+func TestSeeds(t *testing.T) {
+	generated := &goschema.Database{
+		Seeds: []goschema.Seed{
+			{Table: "roles", Columns: []string{"id", "name"}, Rows: [][]string{{"1", "admin"}}},
+			{Table: "countries", Columns: []string{"code"}, Rows: [][]string{{"DE"}}, Always: true},
+		},
+	}
+	tests := []struct {
+		name     string
+		diff     difftypes.SchemaDiff
+		expected []string
+	}{
+		{name: "no changes", diff: difftypes.SchemaDiff{}},
+		{name: "seeded table created", diff: difftypes.SchemaDiff{TablesAdded: []string{"roles"}}, expected: []string{"roles", "countries"}},
+		{name: "other change carries always seeds", diff: difftypes.SchemaDiff{IndexesAdded: []string{"idx_users_email"}}, expected: []string{"countries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			diff := tt.diff
+
+			compare.Seeds(generated, &diff)
+
+			tables := make([]string, 0, len(diff.SeedsAdded))
+			for _, seed := range diff.SeedsAdded {
+				tables = append(tables, seed.Table)
+			}
+			c.Assert(tables, qt.DeepEquals, append([]string{}, tt.expected...))
+		})
+	}
+}
@@ -32,6 +32,10 @@ var (
 			`(?:[a-z_][a-z0-9_]*(?:\.[a-z_][a-z0-9_]*)*|[0-9]+(?:\.[0-9]+)?|'[^']*'|true|false|null))\)`,
 	)
 	schemaQualifierPattern = regexp.MustCompile(`\b[a-z_][a-z0-9_]*\.`)
+
+	// indexColumnCastPattern matches a parenthesized column followed by a
+	// type cast, e.g. "(email)::text", after whitespace has been removed.
+	indexColumnCastPattern = regexp.MustCompile(`\(([a-z_][a-z0-9_]*)\)::[a-z_][a-z0-9_\[\].]*`)
 )
 
 func nonEmptyNames(names []string) []string {
@@ -81,11 +85,11 @@ func stringSetsEqual(left, right []string) bool {
 	return slices.Equal(left, right)
 }
 
-func boolPtrEqual(left, right *bool) bool {
-	if left == nil || right == nil {
-		return left == right
-	}
-	return *left == *right
+// nullsDistinctEqual compares NULLS [NOT] DISTINCT settings. Unset means the
+// NULLS DISTINCT default, and PostgreSQL only prints the clause for NULLS NOT
+// DISTINCT, so an explicit nulls_distinct="true" must not read as drift.
+func nullsDistinctEqual(left, right *bool) bool {
+	return (left == nil || *left) == (right == nil || *right)
 }
 
 func cloneBoolPtr(value *bool) *bool {
@@ -1,9 +1,17 @@
 package compare
 
 import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/stokaro/ptah/config"
 	"github.com/stokaro/ptah/core/goschema"
+	"github.com/stokaro/ptah/core/platform"
 	"github.com/stokaro/ptah/dbschema/types"
 	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
 )
@@ -78,6 +86,17 @@ func TablesAndColumnsWithDialect(
 	database *types.DBSchema,
 	diff *difftypes.SchemaDiff,
 	dialect string,
+) {
+	TablesAndColumnsWithOptions(generated, database, diff, &config.CompareOptions{Dialect: dialect})
+}
+
+// TablesAndColumnsWithOptions performs table and column comparison using the
+// dialect and custom column comparators from opts.
+func TablesAndColumnsWithOptions(
+	generated *goschema.Database,
+	database *types.DBSchema,
+	diff *difftypes.SchemaDiff,
+	opts *config.CompareOptions,
 ) {
 	// Create maps for quick lookup
 	genTables := make(map[string]goschema.Table)
@@ -85,8 +104,15 @@ func TablesAndColumnsWithDialect(
 		genTables[table.QualifiedName()] = table
 	}
 
+	// A materialized view is never a table. Readers that also report it as a
+	// table (older catalogs list it alongside base tables) must not turn it
+	// into a TablesRemoved entry; MaterializedViews owns its lifecycle.
+	matViews := materializedViewNames(generated, database)
 	dbTables := make(map[string]types.DBTable)
 	for _, table := range database.Tables {
+		if matViews[table.QualifiedName()] {
+			continue
+		}
 		dbTables[table.QualifiedName()] = table
 	}
 
@@ -106,8 +132,8 @@ func TablesAndColumnsWithDialect(
 	// Find modified tables (compare columns)
 	for tableName, genTable := range genTables {
 		if dbTable, exists := dbTables[tableName]; exists {
-			tableDiff := TableColumnsWithDialect(genTable, dbTable, generated, dialect)
-			if len(tableDiff.ColumnsAdded) > 0 || len(tableDiff.ColumnsRemoved) > 0 || len(tableDiff.ColumnsModified) > 0 {
+			tableDiff := Table(genTable, dbTable, generated, opts)
+			if tableDiff.HasChanges() {
 				diff.TablesModified = append(diff.TablesModified, tableDiff)
 			}
 		}
@@ -119,4 +145,147 @@ func TablesAndColumnsWithDialect(
 	sort.Slice(diff.TablesModified, func(i, j int) bool {
 		return diff.TablesModified[i].TableName < diff.TablesModified[j].TableName
 	})
+	diff.TableDropDependencies = tableDropDependencies(database, diff.TablesRemoved)
+}
+
+// tableDropDependencies maps each removed table to the removed tables its
+// database foreign keys reference, or returns nil when there are none.
+func tableDropDependencies(database *types.DBSchema, removed []string) map[string][]string {
+	if len(removed) < 2 {
+		return nil
+	}
+	var dependencies map[string][]string
+	for _, constraint := range database.Constraints {
+		if constraint.ForeignTable == nil || !strings.EqualFold(constraint.Type, "FOREIGN KEY") {
+			continue
+		}
+		table := constraint.QualifiedTableName()
+		foreignSchema := cmp.Or(constraint.ForeignSchema, constraint.Schema)
+		referenced := types.QualifyTableName(foreignSchema, *constraint.ForeignTable)
+		if referenced == table || !slices.Contains(removed, table) || !slices.Contains(removed, referenced) ||
+			slices.Contains(dependencies[table], referenced) {
+			continue
+		}
+		if dependencies == nil {
+			dependencies = make(map[string][]string)
+		}
+		dependencies[table] = append(dependencies[table], referenced)
+	}
+	for _, referenced := range dependencies {
+		slices.Sort(referenced)
+	}
+	return dependencies
+}
+
+// Table compares one generated table with its live counterpart: columns,
+// including the ones embedded fields expand to, inheritance parents, the
+// table comment and owner, and the AUTO_INCREMENT counter.
+func Table(
+	genTable goschema.Table,
+	dbTable types.DBTable,
+	generated *goschema.Database,
+	opts *config.CompareOptions,
+) difftypes.TableDiff {
+	tableDiff := tableColumns(genTable, dbTable, generated, opts)
+	TableInheritance(genTable, dbTable, &tableDiff, opts.Dialect)
+	TableComment(genTable, dbTable, &tableDiff, opts.Dialect)
+	TableAutoIncrement(genTable, dbTable, &tableDiff, opts.Dialect)
+	TableOwner(genTable, dbTable, &tableDiff, opts.Dialect)
+	return tableDiff
+}
+
+func materializedViewNames(generated *goschema.Database, database *types.DBSchema) map[string]bool {
+	names := make(map[string]bool, len(generated.MaterializedViews)+len(database.MatViews))
+	for _, view := range generated.MaterializedViews {
+		names[view.Name] = true
+	}
+	for _, view := range database.MatViews {
+		names[view.QualifiedName()] = true
+	}
+	return names
+}
+
+// TableInheritance records PostgreSQL INHERITS parents that the generated
+// table adds or drops compared with the database table. Parents are compared
+// by name as written; an unqualified name means a table in the default schema
+// on both sides. Declarative partitions are not reported as inheritance by the
+// reader, so they never show up here.
+//
+// Table inheritance only exists in PostgreSQL, so the comparison is skipped
+// when dialect names any other dialect.
+func TableInheritance(genTable goschema.Table, dbTable types.DBTable, tableDiff *difftypes.TableDiff, dialect string) {
+	if dialect != "" && !platform.IsPostgresFamily(dialect) {
+		return
+	}
+	genParents := normalizedInheritParents(genTable.Inherits)
+	dbParents := normalizedInheritParents(dbTable.Inherits)
+	for _, parent := range genParents {
+		if !slices.Contains(dbParents, parent) {
+			tableDiff.InheritsAdded = append(tableDiff.InheritsAdded, parent)
+		}
+	}
+	for _, parent := range dbParents {
+		if !slices.Contains(genParents, parent) {
+			tableDiff.InheritsRemoved = append(tableDiff.InheritsRemoved, parent)
+		}
+	}
+}
+
+// normalizedInheritParents trims parent names and strips identifier quotes.
+// Declaration order is kept because it is the order ALTER TABLE ... INHERIT
+// statements are emitted in.
+func normalizedInheritParents(parents []string) []string {
+	normalized := make([]string, 0, len(parents))
+	for _, parent := range parents {
+		parent = strings.ReplaceAll(strings.TrimSpace(parent), `"`, "")
+		if parent != "" && !slices.Contains(normalized, parent) {
+			normalized = append(normalized, parent)
+		}
+	}
+	return normalized
+}
+
+// TableOwner records in tableDiff.Owner a PostgreSQL table owned by another
+// role than its annotation declares. Tables without an owner annotation are
+// not compared, so teams that do not manage ownership see no changes.
+func TableOwner(genTable goschema.Table, dbTable types.DBTable, tableDiff *difftypes.TableDiff, dialect string) {
+	if !platform.IsPostgresFamily(dialect) {
+		return
+	}
+	owner := strings.TrimSpace(genTable.Owner)
+	if owner == "" || dbTable.Owner == "" || owner == dbTable.Owner {
+		return
+	}
+	tableDiff.Owner = fmt.Sprintf("%s -> %s", dbTable.Owner, owner)
+}
+
+// TableAutoIncrement records in tableDiff.AutoIncrement a MySQL/MariaDB table
+// whose AUTO_INCREMENT counter is below the annotated start value. The
+// generated side honors a platform.<dialect>.auto_increment override.
+//
+// The live counter advances with every insert, so only an explicitly
+// annotated value is compared, and the counter is only ever raised. An
+// annotated value below the live counter is not applied: MySQL cannot move
+// the counter below the current maximum key, so a warning is logged instead.
+func TableAutoIncrement(genTable goschema.Table, dbTable types.DBTable, tableDiff *difftypes.TableDiff, dialect string) {
+	if !isMySQLFamilyDialect(dialect) {
+		return
+	}
+	value := genTable.AutoIncrement
+	if override, ok := genTable.Overrides[strings.ToLower(dialect)]["auto_increment"]; ok {
+		value = override
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || start < 1 || dbTable.AutoIncrement == 0 || start == dbTable.AutoIncrement {
+		return
+	}
+	if start < dbTable.AutoIncrement {
+		slog.Warn("Annotated AUTO_INCREMENT start is below the live counter; not lowering it",
+			"table", tableDiff.TableName,
+			"annotated", start,
+			"current", dbTable.AutoIncrement,
+		)
+		return
+	}
+	tableDiff.AutoIncrement = fmt.Sprintf("%d -> %d", dbTable.AutoIncrement, start)
 }
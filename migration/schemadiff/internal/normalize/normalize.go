@@ -1,7 +1,13 @@
 package normalize
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
+
+	"github.com/stokaro/ptah/migration/internal/typechange"
 )
 
 // Type normalizes database type names for cross-platform comparison.
@@ -17,8 +23,15 @@ import (
 //   - Integer variations (INT, INTEGER, BIGINT, etc.) → "integer"
 //   - SERIAL types (SERIAL, BIGSERIAL) → "integer" (for comparison purposes)
 //   - Boolean variations (BOOL, BOOLEAN, TINYINT(1)) → "boolean"
-//   - Timestamp variations → "timestamp"
-//   - Decimal variations (DECIMAL, NUMERIC) → "decimal"
+//   - Timestamp variations → "timestamp"; TIMESTAMPTZ and TIMESTAMP WITH
+//     TIME ZONE → "timestamptz", so the two PostgreSQL types stay distinct
+//   - Decimal variations (DECIMAL, NUMERIC) → "decimal"; NumericType
+//     canonicalizes their precision and scale
+//   - PostgreSQL network, text-search, money, INTERVAL, and TIME types keep
+//     their own names, ignoring precision suffixes (INTERVAL(6) → "interval")
+//   - Unrecognized types (enums, MySQL spatial and JSON types, custom types)
+//     pass through with their original spelling, without being folded into
+//     another family; compare them with strings.EqualFold
 //
 // # Database-Specific Handling
 //
@@ -46,8 +59,22 @@ import (
 //
 // Returns a normalized type name suitable for cross-database comparison.
 func Type(typeName string) string {
-	// Convert to lowercase for case-insensitive comparison
-	typeName = strings.ToLower(typeName)
+	original := strings.TrimSpace(typeName)
+	// Convert to lowercase for case-insensitive matching
+	typeName = strings.ToLower(original)
+
+	// Arrays normalize their element type and keep a single [] marker.
+	// Without this, TEXT[] and _text would fold into plain "text" and an
+	// array/scalar change would go unnoticed.
+	if element, ok := arrayElementType(typeName); ok {
+		return Type(element) + "[]"
+	}
+
+	// Types with a fixed catalog spelling are matched before the substring
+	// families below; otherwise INTERVAL and POINT would fold into "integer".
+	if normalized, ok := fixedType(typeName); ok {
+		return normalized
+	}
 
 	switch {
 	case strings.Contains(typeName, "varchar"):
@@ -70,8 +97,107 @@ func Type(typeName string) string {
 		return "decimal"
 	default:
 		// Return as-is for unrecognized types (enums, custom types, etc.)
-		return typeName
+		return original
+	}
+}
+
+// niladicCallPattern matches an argument-free function call such as UUID().
+var niladicCallPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\(\)$`)
+
+// arraySuffixPattern matches trailing array markers: one or more [] / [N]
+// dimensions, or the SQL-standard ARRAY / ARRAY[N] spelling.
+var arraySuffixPattern = regexp.MustCompile(`(\s*\[\d*\])+$|\s+array(\s*\[\d*\])?$`)
+
+// arrayElementType returns the element type of a lower-cased PostgreSQL array
+// type. Annotations spell arrays as TEXT[], INTEGER[][] or INTEGER ARRAY, while
+// the catalog reports the element udt_name prefixed with an underscore (_text,
+// _int4). PostgreSQL does not enforce declared dimensions, so every spelling
+// maps to the same one-dimensional form.
+func arrayElementType(typeName string) (string, bool) {
+	if loc := arraySuffixPattern.FindStringIndex(typeName); loc != nil && loc[0] > 0 {
+		return strings.TrimSpace(typeName[:loc[0]]), true
 	}
+	if len(typeName) > 1 && strings.HasPrefix(typeName, "_") {
+		return typeName[1:], true
+	}
+	return "", false
+}
+
+// fixedTypes maps type spellings, with precision suffixes removed, to the
+// name used for comparison. PostgreSQL reports these through udt_name
+// (inet, interval, timestamptz, ...), so each annotation spelling must
+// land on the same value as its catalog form.
+var fixedTypes = map[string]string{
+	"inet":                        "inet",
+	"cidr":                        "cidr",
+	"macaddr":                     "macaddr",
+	"macaddr8":                    "macaddr8",
+	"interval":                    "interval",
+	"tsvector":                    "tsvector",
+	"tsquery":                     "tsquery",
+	"money":                       "money",
+	"point":                       "point",
+	"time":                        "time",
+	"time without time zone":      "time",
+	"timetz":                      "timetz",
+	"time with time zone":         "timetz",
+	"timestamp":                   "timestamp",
+	"timestamp without time zone": "timestamp",
+	"timestamptz":                 "timestamptz",
+	"timestamp with time zone":    "timestamptz",
+}
+
+func fixedType(typeName string) (string, bool) {
+	base := strings.Join(strings.Fields(stripTypeModifiers(typeName)), " ")
+	// INTERVAL accepts a field restriction such as "interval day to second".
+	if strings.HasPrefix(base, "interval ") {
+		base = "interval"
+	}
+	normalized, ok := fixedTypes[base]
+	return normalized, ok
+}
+
+// stripTypeModifiers removes parenthesized precision/length modifiers, so
+// "timestamp(6) with time zone" becomes "timestamp  with time zone".
+func stripTypeModifiers(typeName string) string {
+	var builder strings.Builder
+	depth := 0
+	for _, ch := range typeName {
+		switch {
+		case ch == '(':
+			depth++
+		case ch == ')' && depth > 0:
+			depth--
+		case depth == 0:
+			builder.WriteRune(ch)
+		}
+	}
+	return builder.String()
+}
+
+// NumericType returns the canonical form of a DECIMAL or NUMERIC type as
+// dialect stores it, so spellings the database reports alike compare equal:
+// "decimal(p,s)" with an omitted scale as 0 and, on MySQL and MariaDB, a bare
+// DECIMAL as decimal(10,0). A PostgreSQL-family NUMERIC without a precision
+// is unconstrained and returns "decimal".
+//
+//	NumericType("NUMERIC(10)", "postgres")   // → "decimal(10,0)", ok
+//	NumericType("NUMERIC", "postgres")       // → "decimal", ok
+//	NumericType("DECIMAL", "mysql")          // → "decimal(10,0)", ok
+//	NumericType("decimal(12,2)", "mariadb")  // → "decimal(12,2)", ok
+//
+// ok is false for other types and for dialects whose reading of an omitted
+// precision is not modeled; callers compare those by their declared
+// parameters alone.
+func NumericType(typeName, dialect string) (string, bool) {
+	args, ok := typechange.DecimalArgs(typeName, dialect)
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return "decimal", true
+	}
+	return fmt.Sprintf("decimal(%d,%d)", args[0], args[1]), true
 }
 
 // DefaultValue normalizes default values for cross-database comparison.
@@ -111,6 +237,15 @@ func Type(typeName string) string {
 //	DefaultValue("'0'::bigint", "integer")   // → "0"
 //	DefaultValue("'active'::text", "text")   // → "active"
 //
+//	// JSON documents (casts, MySQL expression defaults, spacing)
+//	DefaultValue("'{}'::jsonb", "jsonb")     // → "{}"
+//	DefaultValue("json_object()", "json")    // → "{}"
+//	DefaultValue(`'{"a": 1}'`, "jsonb")      // → `{"a":1}`
+//
+//	// Argument-free function calls
+//	DefaultValue("UUID()", "varchar")   // → "uuid()"
+//	DefaultValue("(UUID_TO_BIN(UUID()))", "binary") // → "uuid_to_bin(uuid())"
+//
 //	// NULL handling
 //	DefaultValue("NULL", "varchar")     // → ""
 //	DefaultValue("", "integer")        // → ""
@@ -127,6 +262,8 @@ func DefaultValue(defaultValue, typeName string) string {
 	if defaultValue == "" {
 		return ""
 	}
+	// Type keeps the spelling of unrecognized types, such as JSON.
+	typeName = strings.ToLower(typeName)
 
 	if normalizedExpression := normalizeTemporalDefaultExpression(defaultValue, typeName); normalizedExpression != "" {
 		return normalizedExpression
@@ -136,6 +273,10 @@ func DefaultValue(defaultValue, typeName string) string {
 		return normalizedSequence
 	}
 
+	if normalizedUUID := normalizeUUIDDefaultExpression(defaultValue); normalizedUUID != "" {
+		return normalizedUUID
+	}
+
 	cleanValue := defaultValue
 
 	// MariaDB/MySQL returns 'NULL' string for columns without explicit defaults
@@ -144,6 +285,20 @@ func DefaultValue(defaultValue, typeName string) string {
 		return ""
 	}
 
+	if normalizedArray, ok := normalizeArrayDefaultValue(cleanValue, typeName); ok {
+		return normalizedArray
+	}
+
+	if normalizedJSON, ok := normalizeJSONDefaultValue(cleanValue, typeName); ok {
+		return normalizedJSON
+	}
+
+	// MySQL reads function defaults back in lower case (UUID() as uuid());
+	// function names are case-insensitive on every supported dialect.
+	if niladicCallPattern.MatchString(cleanValue) {
+		return strings.ToLower(cleanValue)
+	}
+
 	// Handle PostgreSQL type casting syntax (e.g., 'user'::text, '0'::bigint)
 	// Remove the ::type suffix before processing quotes
 	// We need to find the last :: to handle cases like 'value::with::colons'::text
@@ -173,9 +328,176 @@ func DefaultValue(defaultValue, typeName string) string {
 	return cleanValue
 }
 
+// normalizeArrayDefaultValue canonicalizes PostgreSQL array defaults to the
+// array literal form {a,b}. Annotations usually write ARRAY[]::TEXT[] or
+// ARRAY['a','b'], while the catalog reads defaults back as '{}'::text[] or
+// ARRAY['a'::text, 'b'::text]. Casts on the array and on its elements, quotes,
+// and whitespace are removed; nested arrays keep their braces. It reports false
+// for non-array types or values that are not array constructors or literals.
+func normalizeArrayDefaultValue(defaultValue, typeName string) (string, bool) {
+	if !strings.HasSuffix(typeName, "[]") {
+		return "", false
+	}
+	return canonicalArrayValue(stripTopLevelCast(Expression(defaultValue)))
+}
+
+func canonicalArrayValue(value string) (string, bool) {
+	switch {
+	case len(value) >= 7 && strings.EqualFold(value[:6], "array[") && strings.HasSuffix(value, "]"):
+		return canonicalArrayElements(value[6:len(value)-1], canonicalArrayConstructorElement), true
+	case len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'"):
+		return canonicalArrayValue(strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+	case len(value) >= 2 && strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}"):
+		return canonicalArrayElements(value[1:len(value)-1], canonicalArrayLiteralElement), true
+	default:
+		return "", false
+	}
+}
+
+func canonicalArrayElements(body string, element func(string) string) string {
+	parts := splitTopLevelSQL(body)
+	elements := make([]string, 0, len(parts))
+	for _, part := range parts {
+		elements = append(elements, element(part))
+	}
+	return "{" + strings.Join(elements, ",") + "}"
+}
+
+// canonicalArrayConstructorElement normalizes one ARRAY[...] element: a nested
+// constructor, or a literal with an optional cast such as 'a'::text.
+func canonicalArrayConstructorElement(element string) string {
+	element = Expression(stripTopLevelCast(Expression(element)))
+	if nested, ok := canonicalArrayValue(element); ok && !strings.HasPrefix(element, "'") {
+		return nested
+	}
+	if len(element) >= 2 && strings.HasPrefix(element, "'") && strings.HasSuffix(element, "'") {
+		return strings.ReplaceAll(element[1:len(element)-1], "''", "'")
+	}
+	return element
+}
+
+// canonicalArrayLiteralElement normalizes one element of a {...} literal.
+func canonicalArrayLiteralElement(element string) string {
+	element = strings.TrimSpace(element)
+	if strings.HasPrefix(element, "{") && strings.HasSuffix(element, "}") {
+		return canonicalArrayElements(element[1:len(element)-1], canonicalArrayLiteralElement)
+	}
+	if len(element) >= 2 && strings.HasPrefix(element, `"`) && strings.HasSuffix(element, `"`) {
+		return strings.ReplaceAll(element[1:len(element)-1], `\"`, `"`)
+	}
+	return element
+}
+
+// stripTopLevelCast removes a trailing ::type cast that is not nested inside
+// quotes, brackets, braces, or parentheses.
+func stripTopLevelCast(value string) string {
+	depth := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\'', '"':
+			next, ok := skipQuotedSQL(value, i, value[i])
+			if !ok {
+				return value
+			}
+			i = next
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ':':
+			if depth == 0 && i+1 < len(value) && value[i+1] == ':' {
+				return strings.TrimSpace(value[:i])
+			}
+		}
+	}
+	return value
+}
+
+// splitTopLevelSQL splits a comma-separated list, ignoring commas inside
+// quotes, brackets, braces, or parentheses. An empty list yields no parts.
+func splitTopLevelSQL(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\'', '"':
+			if next, ok := skipQuotedSQL(value, i, value[i]); ok {
+				i = next
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(value[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(value[start:]))
+}
+
+// mysqlCharsetIntroducerPattern matches the charset introducer MySQL prefixes
+// to string literals inside expression defaults, e.g. _utf8mb4\'{}\'.
+var mysqlCharsetIntroducerPattern = regexp.MustCompile(`^_[a-z0-9]+\\?'`)
+
+// normalizeJSONDefaultValue canonicalizes JSON column defaults so every engine's
+// spelling of the same document compares equal: PostgreSQL reads back
+// '{}'::jsonb, annotations often write '{}', and MySQL 8 reports expression
+// defaults as json_object() or _utf8mb4\'{}\'. Casts, quotes, charset
+// introducers, and redundant parentheses are removed, JSON_OBJECT()/JSON_ARRAY()
+// become {} and [], and the remaining document is parsed and re-serialized in
+// compact form with sorted keys. It reports false when the value is not a
+// recognizable JSON default, so the general handling applies.
+func normalizeJSONDefaultValue(defaultValue, typeName string) (string, bool) {
+	value := Expression(defaultValue)
+	switch strings.ToLower(value) {
+	case "json_object()":
+		return "{}", true
+	case "json_array()":
+		return "[]", true
+	}
+	if typeName != "json" && typeName != "jsonb" {
+		return "", false
+	}
+
+	if loc := mysqlCharsetIntroducerPattern.FindStringIndex(strings.ToLower(value)); loc != nil {
+		value = strings.ReplaceAll(value[loc[1]-1:], `\'`, "'")
+	}
+	for {
+		lower := strings.ToLower(value)
+		trimmed := strings.TrimSuffix(strings.TrimSuffix(lower, "::jsonb"), "::json")
+		if trimmed == lower {
+			break
+		}
+		value = Expression(value[:len(trimmed)])
+	}
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return "", false
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
 func normalizeTemporalDefaultExpression(defaultValue, typeName string) string {
 	normalizedType := strings.ToLower(strings.TrimSpace(typeName))
-	if normalizedType != "" && normalizedType != "timestamp" {
+	if normalizedType != "" && normalizedType != "timestamp" && normalizedType != "timestamptz" {
 		return ""
 	}
 	normalizedValue := strings.ToUpper(strings.TrimSpace(defaultValue))
@@ -188,6 +510,19 @@ func normalizeTemporalDefaultExpression(defaultValue, typeName string) string {
 	}
 }
 
+// normalizeUUIDDefaultExpression canonicalizes a MySQL/MariaDB UUID()
+// default expression. Ptah renders it parenthesized, e.g. (UUID()) or
+// (UUID_TO_BIN(UUID())), while the server reads it back unparenthesized and in
+// lower case. It returns "" when the value is not a call built on UUID().
+func normalizeUUIDDefaultExpression(defaultValue string) string {
+	value := strings.ToLower(strings.ReplaceAll(Expression(defaultValue), " ", ""))
+	if value != "uuid()" && !strings.HasPrefix(value, "uuid_to_bin(uuid()") &&
+		!strings.HasPrefix(value, "unhex(replace(uuid()") {
+		return ""
+	}
+	return value
+}
+
 // normalizeSequenceDefaultExpression canonicalizes a nextval(...) column
 // default so a declared nextval('seq') matches the nextval('seq'::regclass)
 // form PostgreSQL stores and reads back. It returns "" when the value is not a
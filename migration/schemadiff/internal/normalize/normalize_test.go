@@ -52,7 +52,7 @@ func TestType(t *testing.T) {
 		// Timestamp variations
 		{"timestamp lowercase", "timestamp", "timestamp"},
 		{"timestamp uppercase", "TIMESTAMP", "timestamp"},
-		{"timestamp with timezone", "TIMESTAMP WITH TIME ZONE", "timestamp"},
+		{"timestamp with timezone", "TIMESTAMP WITH TIME ZONE", "timestamptz"},
 		{"timestamp without timezone", "TIMESTAMP WITHOUT TIME ZONE", "timestamp"},
 
 		// Decimal variations
@@ -62,11 +62,15 @@ func TestType(t *testing.T) {
 		{"numeric", "NUMERIC", "decimal"},
 		{"numeric with precision", "NUMERIC(5,2)", "decimal"},
 
-		// Unrecognized types (should return as-is, lowercased)
-		{"enum type", "ENUM('a','b','c')", "enum('a','b','c')"},
-		{"json type", "JSON", "json"},
-		{"uuid type", "UUID", "uuid"},
-		{"custom type", "MyCustomType", "mycustomtype"},
+		// Unrecognized types (should return as-is, in their original spelling)
+		{"enum type", "ENUM('a','b','c')", "ENUM('a','b','c')"},
+		{"json type", "JSON", "JSON"},
+		{"uuid type", "UUID", "UUID"},
+		{"custom type", "MyCustomType", "MyCustomType"},
+		{"mysql geometry", "GEOMETRY", "GEOMETRY"},
+		{"mysql point is not an integer", "POINT", "point"},
+		{"mysql datetime with precision", "DATETIME(6)", "DATETIME(6)"},
+		{"unrecognized type is trimmed", "  geometry  ", "geometry"},
 
 		// Edge cases
 		{"empty string", "", ""},
@@ -84,6 +88,133 @@ func TestType(t *testing.T) {
 	}
 }
 
+// TestType_PostgreSQLCatalogTypes pairs each annotation spelling with the
+// data_type / udt_name that information_schema.columns reports for it; the
+// reader compares on udt_name, so both must normalize to the same value.
+func TestType_PostgreSQLCatalogTypes(t *testing.T) {
+	tests := []struct {
+		annotation string
+		dataType   string
+		udtName    string
+		expected   string
+	}{
+		{"INET", "inet", "inet", "inet"},
+		{"CIDR", "cidr", "cidr", "cidr"},
+		{"MACADDR", "macaddr", "macaddr", "macaddr"},
+		{"INTERVAL", "interval", "interval", "interval"},
+		{"INTERVAL(6)", "interval", "interval", "interval"},
+		{"INTERVAL DAY TO SECOND(3)", "interval", "interval", "interval"},
+		{"TSVECTOR", "tsvector", "tsvector", "tsvector"},
+		{"TSQUERY", "tsquery", "tsquery", "tsquery"},
+		{"MONEY", "money", "money", "money"},
+		{"TIME", "time without time zone", "time", "time"},
+		{"TIME(3)", "time without time zone", "time", "time"},
+		{"TIME WITHOUT TIME ZONE", "time without time zone", "time", "time"},
+		{"TIMETZ", "time with time zone", "timetz", "timetz"},
+		{"TIME WITH TIME ZONE", "time with time zone", "timetz", "timetz"},
+		{"TIME(6) WITH TIME ZONE", "time with time zone", "timetz", "timetz"},
+		{"TIMESTAMP", "timestamp without time zone", "timestamp", "timestamp"},
+		{"TIMESTAMP(3)", "timestamp without time zone", "timestamp", "timestamp"},
+		{"TIMESTAMP WITHOUT TIME ZONE", "timestamp without time zone", "timestamp", "timestamp"},
+		{"TIMESTAMPTZ", "timestamp with time zone", "timestamptz", "timestamptz"},
+		{"TIMESTAMPTZ(3)", "timestamp with time zone", "timestamptz", "timestamptz"},
+		{"TIMESTAMP WITH TIME ZONE", "timestamp with time zone", "timestamptz", "timestamptz"},
+		{"TIMESTAMP(6) WITH TIME ZONE", "timestamp with time zone", "timestamptz", "timestamptz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.annotation, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(normalize.Type(tt.annotation), qt.Equals, tt.expected)
+			c.Assert(normalize.Type(tt.dataType), qt.Equals, tt.expected)
+			c.Assert(normalize.Type(tt.udtName), qt.Equals, tt.expected)
+		})
+	}
+}
+
+func TestType_DistinguishesTimeZones(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(normalize.Type("TIMESTAMP"), qt.Not(qt.Equals), normalize.Type("TIMESTAMPTZ"))
+	c.Assert(normalize.Type("TIME"), qt.Not(qt.Equals), normalize.Type("TIMETZ"))
+}
+
+func TestType_DistinguishesIntervalFromInteger(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(normalize.Type("INTERVAL"), qt.Not(qt.Equals), normalize.Type("INTEGER"))
+}
+
+func TestType_PostgreSQLArrays(t *testing.T) {
+	tests := []struct {
+		annotation string
+		udtName    string
+		expected   string
+	}{
+		{"TEXT[]", "_text", "text[]"},
+		{"INTEGER[]", "_int4", "integer[]"},
+		{"BIGINT[]", "_int8", "integer[]"},
+		{"INTEGER[][]", "_int4", "integer[]"},
+		{"INTEGER[3][3]", "_int4", "integer[]"},
+		{"INTEGER ARRAY", "_int4", "integer[]"},
+		{"VARCHAR(64)[]", "_varchar", "varchar[]"},
+		{"NUMERIC(10,2)[]", "_numeric", "decimal[]"},
+		{"BOOLEAN[]", "_bool", "boolean[]"},
+		{"UUID[]", "_uuid", "uuid[]"},
+		{"JSONB[]", "_jsonb", "jsonb[]"},
+		{"TIMESTAMPTZ[]", "_timestamptz", "timestamptz[]"},
+		{"INET[]", "_inet", "inet[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.annotation, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(normalize.Type(tt.annotation), qt.Equals, tt.expected)
+			c.Assert(normalize.Type(tt.udtName), qt.Equals, tt.expected)
+		})
+	}
+}
+
+func TestType_DistinguishesArrayFromScalar(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(normalize.Type("_text"), qt.Not(qt.Equals), normalize.Type("TEXT"))
+	c.Assert(normalize.Type("INTEGER[]"), qt.Not(qt.Equals), normalize.Type("INTEGER"))
+}
+
+func TestNumericType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		dialect  string
+		expected string
+		ok       bool
+	}{
+		{name: "postgres precision and scale", input: "NUMERIC(10,2)", dialect: "postgres", expected: "decimal(10,2)", ok: true},
+		{name: "postgres catalog spelling", input: "numeric(10,2)", dialect: "postgres", expected: "decimal(10,2)", ok: true},
+		{name: "postgres decimal spelling", input: "DECIMAL(10, 2)", dialect: "postgres", expected: "decimal(10,2)", ok: true},
+		{name: "postgres omitted scale", input: "NUMERIC(10)", dialect: "postgres", expected: "decimal(10,0)", ok: true},
+		{name: "postgres unconstrained", input: "NUMERIC", dialect: "postgres", expected: "decimal", ok: true},
+		{name: "cockroachdb unconstrained", input: "DECIMAL", dialect: "cockroachdb", expected: "decimal", ok: true},
+		{name: "mysql bare decimal", input: "DECIMAL", dialect: "mysql", expected: "decimal(10,0)", ok: true},
+		{name: "mysql bare numeric", input: "NUMERIC", dialect: "mysql", expected: "decimal(10,0)", ok: true},
+		{name: "mysql omitted scale", input: "DECIMAL(12)", dialect: "mysql", expected: "decimal(12,0)", ok: true},
+		{name: "mysql unsigned", input: "decimal(12,2) unsigned", dialect: "mysql", expected: "decimal(12,2)", ok: true},
+		{name: "mariadb bare decimal", input: "DECIMAL", dialect: "mariadb", expected: "decimal(10,0)", ok: true},
+		{name: "unmodeled dialect", input: "DECIMAL(10,2)", dialect: "sqlserver", expected: "", ok: false},
+		{name: "not a decimal", input: "INTEGER", dialect: "postgres", expected: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			result, ok := normalize.NumericType(tt.input, tt.dialect)
+			c.Assert(result, qt.Equals, tt.expected)
+			c.Assert(ok, qt.Equals, tt.ok)
+		})
+	}
+}
+
 func TestDefaultValue(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -104,6 +235,18 @@ func TestDefaultValue(t *testing.T) {
 		{"double quotes with spaces", "\" test value \"", "varchar", " test value "},
 		{"no quotes", "plain_value", "varchar", "plain_value"},
 
+		// Argument-free function calls fold case
+		{"function call uppercase", "UUID()", "varchar", "uuid()"},
+		{"function call lowercase", "gen_random_uuid()", "uuid", "gen_random_uuid()"},
+		{"quoted call stays literal", "'UUID()'", "varchar", "UUID()"},
+
+		// MySQL/MariaDB UUID generators drop the rendered parentheses
+		{"parenthesized uuid", "(UUID())", "char", "uuid()"},
+		{"uuid to bin rendered", "(UUID_TO_BIN(UUID()))", "binary", "uuid_to_bin(uuid())"},
+		{"uuid to bin read back", "uuid_to_bin(uuid())", "binary", "uuid_to_bin(uuid())"},
+		{"mariadb unhex uuid rendered", "(UNHEX(REPLACE(UUID(), '-', '')))", "binary", "unhex(replace(uuid(),'-',''))"},
+		{"mariadb unhex uuid read back", "unhex(replace(uuid(),'-',''))", "binary", "unhex(replace(uuid(),'-',''))"},
+
 		// Boolean normalization for boolean types
 		{"boolean true string", "true", "boolean", "true"},
 		{"boolean false string", "false", "boolean", "false"},
@@ -132,6 +275,7 @@ func TestDefaultValue(t *testing.T) {
 		{"timestamp current timestamp lowercase", "current_timestamp()", "timestamp", "CURRENT_TIMESTAMP"},
 		{"timestamp current timestamp uppercase", "CURRENT_TIMESTAMP", "timestamp", "CURRENT_TIMESTAMP"},
 		{"timestamp current timestamp without type", "CURRENT_TIMESTAMP()", "", "CURRENT_TIMESTAMP"},
+		{"timestamptz now", "now()", "timestamptz", "NOW"},
 
 		// Sequence-backed defaults: the introspected ::regclass form must
 		// normalize to the declared nextval('seq') form (issue #675).
@@ -187,6 +331,40 @@ func TestDefaultValue(t *testing.T) {
 		{"type cast without value", "::text", "text", ""},
 		{"malformed type cast", "'value':", "text", "value':"},
 		{"type cast with schema", "'value'::public.custom_type", "text", "value"},
+
+		// JSON defaults: PostgreSQL casts, MySQL 8 expression defaults, and
+		// insignificant whitespace all canonicalize to the compact document.
+		{"jsonb empty object cast", "'{}'::jsonb", "jsonb", "{}"},
+		{"json empty array cast", "'[]'::json", "json", "[]"},
+		{"jsonb bare literal", "'{}'", "jsonb", "{}"},
+		{"jsonb unquoted literal", "{}", "jsonb", "{}"},
+		{"jsonb spacing and key order", `'{"b": [1, 2], "a": "x"}'::jsonb`, "jsonb", `{"a":"x","b":[1,2]}`},
+		{"jsonb embedded quote", `'{"name": "O''Brien"}'::jsonb`, "jsonb", `{"name":"O'Brien"}`},
+		{"json mysql json_object", "json_object()", "json", "{}"},
+		{"json mysql json_array", "json_array()", "json", "[]"},
+		{"json mysql parenthesized json_object", "(JSON_OBJECT())", "json", "{}"},
+		{"json mysql charset introducer", `_utf8mb4\'{}\'`, "json", "{}"},
+		{"json mysql charset introducer document", `_utf8mb4\'{"a": 1}\'`, "json", `{"a":1}`},
+		{"json_object on text type", "json_object()", "text", "{}"},
+		{"jsonb invalid document falls back", "'not json'::jsonb", "jsonb", "not json"},
+
+		// PostgreSQL array defaults: constructors and literals, with or without
+		// casts on the array and its elements, normalize to {a,b}.
+		{"array empty constructor", "ARRAY[]::TEXT[]", "text[]", "{}"},
+		{"array empty literal cast", "'{}'::text[]", "text[]", "{}"},
+		{"array empty literal", "'{}'", "text[]", "{}"},
+		{"array constructor element casts", "ARRAY['a'::text, 'b'::text]", "text[]", "{a,b}"},
+		{"array constructor plain", "ARRAY['a','b']", "text[]", "{a,b}"},
+		{"array literal with spaces", "'{a, b}'::text[]", "text[]", "{a,b}"},
+		{"array literal varchar cast", "'{a,b}'::character varying[]", "varchar[]", "{a,b}"},
+		{"array literal quoted element", `'{"a b",c}'::text[]`, "text[]", "{a b,c}"},
+		{"array constructor quoted element", "ARRAY['a b'::text, 'c'::text]", "text[]", "{a b,c}"},
+		{"array integer constructor", "ARRAY[1, 2, 3]", "integer[]", "{1,2,3}"},
+		{"array integer literal cast", "'{1,2,3}'::integer[]", "integer[]", "{1,2,3}"},
+		{"array multi-dimensional constructor", "ARRAY[ARRAY[1, 2], ARRAY[3, 4]]", "integer[]", "{{1,2},{3,4}}"},
+		{"array multi-dimensional literal", "'{{1,2},{3,4}}'::integer[]", "integer[]", "{{1,2},{3,4}}"},
+		{"array element numeric cast", "ARRAY[(1)::numeric, (2.5)::numeric]", "decimal[]", "{1,2.5}"},
+		{"array element with escaped quote", "ARRAY['it''s'::text]", "text[]", "{it's}"},
 	}
 
 	for _, tt := range tests {
@@ -1,6 +1,9 @@
 package schemadiff
 
 import (
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/stokaro/ptah/config"
@@ -60,17 +63,16 @@ func CompareWithOptions(generated *goschema.Database, database *types.DBSchema,
 	}
 
 	diff := &difftypes.SchemaDiff{}
-	generated, database = normalizeInlineEnumsForCompare(generated, database, opts)
-	generated = normalizeGeneratedColumnsForCompare(generated, opts)
+	generated, database = normalizeForCompare(generated, database, opts)
 
 	// Compare tables and their column structures
-	compare.TablesAndColumnsWithDialect(generated, database, diff, opts.Dialect)
+	compare.TablesAndColumnsWithOptions(generated, database, diff, opts)
 
 	// Compare enum type definitions and values
 	compare.Enums(generated, database, diff)
 
 	// Compare database index definitions
-	compare.IndexesWithDialect(generated, database, diff, opts.Dialect)
+	compare.IndexesWithOptions(generated, database, diff, opts)
 
 	// Compare PostgreSQL extensions with configuration options
 	compare.Extensions(generated, database, diff, opts)
@@ -78,7 +80,7 @@ func CompareWithOptions(generated *goschema.Database, database *types.DBSchema,
 	// Compare PostgreSQL functions (PostgreSQL-specific feature)
 	compare.Functions(generated, database, diff)
 
-	// Compare PostgreSQL standalone sequences (PostgreSQL-specific feature)
+	// Compare standalone sequences (PostgreSQL and MariaDB)
 	compare.Sequences(generated, database, diff)
 
 	// Compare PostgreSQL user-defined types (domains, composites, ranges)
@@ -98,7 +100,7 @@ func CompareWithOptions(generated *goschema.Database, database *types.DBSchema,
 	compare.RLSEnabledTables(generated, database, diff)
 
 	// Compare roles (PostgreSQL-specific feature)
-	compare.Roles(generated, database, diff)
+	compare.Roles(generated, database, diff, opts)
 
 	// Compare role privilege grants (PostgreSQL-specific feature)
 	compare.Grants(generated, database, diff)
@@ -106,15 +108,121 @@ func CompareWithOptions(generated *goschema.Database, database *types.DBSchema,
 	// Compare table-level constraints (EXCLUDE, CHECK, UNIQUE, etc.)
 	compare.Constraints(generated, database, diff, opts)
 
+	// Select seed rows last: they only accompany the changes found above
+	compare.Seeds(generated, diff)
+
 	return diff
 }
 
+// ErrTableNotFound reports that CompareTable found no table for the struct
+// name in the generated schema.
+var ErrTableNotFound = errors.New("no table for struct")
+
+// CompareTable compares the table declared by one Go struct of generated with
+// one live table, without diffing the rest of the schema. It runs the same
+// column comparison as CompareWithOptions, including embedded field expansion,
+// primary key nullability rules and dialect normalization, and reports the
+// table's TableDiff even when it has no changes; use TableDiff.HasChanges to
+// test for drift.
+//
+// The live table is matched by the caller, so its name may differ from the
+// annotated one, as with per-tenant copies of a table. Indexes, constraints
+// and other objects outside the table's columns are not compared.
+//
+// Example:
+//
+//	tableDiff, err := schemadiff.CompareTable(generated, "Order", &liveTable, opts)
+//	if err != nil {
+//		return err
+//	}
+//	if tableDiff.HasChanges() {
+//		// migrate the tenant table
+//	}
+func CompareTable(generated *goschema.Database, structName string, db *types.DBTable, opts *config.CompareOptions) (difftypes.TableDiff, error) {
+	if generated == nil {
+		return difftypes.TableDiff{}, errors.New("schemadiff: generated schema is nil")
+	}
+	if db == nil {
+		return difftypes.TableDiff{}, fmt.Errorf("schemadiff: live table for struct %q is nil", structName)
+	}
+	if opts == nil {
+		opts = config.DefaultCompareOptions()
+	}
+	generated, database := normalizeForCompare(generated, &types.DBSchema{Tables: []types.DBTable{*db}}, opts)
+	index := slices.IndexFunc(generated.Tables, func(table goschema.Table) bool {
+		return table.StructName == structName
+	})
+	if index < 0 {
+		return difftypes.TableDiff{}, fmt.Errorf("schemadiff: struct %q: %w", structName, ErrTableNotFound)
+	}
+	return compare.Table(generated.Tables[index], database.Tables[0], generated, opts), nil
+}
+
+// normalizeForCompare rewrites generated and database into the shape the
+// comparison expects for opts.Dialect.
+func normalizeForCompare(
+	generated *goschema.Database,
+	database *types.DBSchema,
+	opts *config.CompareOptions,
+) (*goschema.Database, *types.DBSchema) {
+	generated, database = normalizeInlineEnumsForCompare(generated, database, opts)
+	database = normalizeRenamedEnumsForCompare(generated, database)
+	generated = normalizeSequencesForCompare(generated, opts)
+	return normalizeGeneratedColumnsForCompare(generated, opts), database
+}
+
+// normalizeSequencesForCompare drops the sequence options MariaDB cannot
+// store: every MariaDB sequence is BIGINT and has no OWNED BY, so comparing
+// them would report a change no migration can apply.
+func normalizeSequencesForCompare(generated *goschema.Database, opts *config.CompareOptions) *goschema.Database {
+	if generated == nil || opts == nil || len(generated.Sequences) == 0 ||
+		platform.NormalizeDialect(opts.Dialect) != platform.MariaDB {
+		return generated
+	}
+	normalizedGenerated := *generated
+	normalizedGenerated.Sequences = append([]goschema.Sequence(nil), generated.Sequences...)
+	for i := range normalizedGenerated.Sequences {
+		normalizedGenerated.Sequences[i].AsType = ""
+		normalizedGenerated.Sequences[i].OwnedBy = ""
+	}
+	return &normalizedGenerated
+}
+
+// normalizeRenamedEnumsForCompare points database columns of a renamed enum
+// type at the new name. ALTER TYPE ... RENAME TO carries the columns along,
+// so they must not show up as type changes.
+func normalizeRenamedEnumsForCompare(generated *goschema.Database, database *types.DBSchema) *types.DBSchema {
+	renames := compare.EnumRenames(generated, database)
+	if len(renames) == 0 {
+		return database
+	}
+	renamed := func(name string) string {
+		if newName, ok := renames[name]; ok {
+			return newName
+		}
+		return name
+	}
+	normalizedDatabase := *database
+	normalizedDatabase.Tables = append([]types.DBTable(nil), database.Tables...)
+	for i := range normalizedDatabase.Tables {
+		table := &normalizedDatabase.Tables[i]
+		table.Columns = append([]types.DBColumn(nil), table.Columns...)
+		for j := range table.Columns {
+			column := &table.Columns[j]
+			column.DataType = renamed(column.DataType)
+			column.UDTName = renamed(column.UDTName)
+			column.ColumnType = renamed(column.ColumnType)
+		}
+	}
+	return &normalizedDatabase
+}
+
 func normalizeInlineEnumsForCompare(
 	generated *goschema.Database,
 	database *types.DBSchema,
 	opts *config.CompareOptions,
 ) (*goschema.Database, *types.DBSchema) {
-	if generated == nil || database == nil || opts == nil || !isInlineEnumDialect(opts.Dialect) {
+	if generated == nil || database == nil || opts == nil || platform.EnumStrategyFor(opts.Dialect) == platform.EnumStrategyNativeType {
 		return generated, database
 	}
 
@@ -179,15 +287,6 @@ func defaultGeneratedColumnKind(dialect string) string {
 	}
 }
 
-func isInlineEnumDialect(dialect string) bool {
-	switch platform.NormalizeDialect(dialect) {
-	case platform.MySQL, platform.MariaDB, platform.SQLite, platform.SQLServer:
-		return true
-	default:
-		return false
-	}
-}
-
 func sqliteInlineEnumCheck(field goschema.Field) string {
 	return enumCheck(field)
 }
@@ -1,6 +1,8 @@
 package schemadiff_test
 
 import (
+	"maps"
+	"slices"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -9,6 +11,7 @@ import (
 	"github.com/stokaro/ptah/core/goschema"
 	"github.com/stokaro/ptah/dbschema/types"
 	"github.com/stokaro/ptah/migration/schemadiff"
+	difftypes "github.com/stokaro/ptah/migration/schemadiff/types"
 )
 
 func TestCompare_DefaultBehavior(t *testing.T) {
@@ -82,6 +85,58 @@ func TestCompareWithDialect_MySQLFamilyInlineEnumsMatchGeneratedEnumFields(t *te
 	}
 }
 
+func renamedEnumSchemas(dbColumn types.DBColumn) (*goschema.Database, *types.DBSchema) {
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "orders", StructName: "Order"}},
+		Fields: []goschema.Field{
+			{StructName: "Order", Name: "id", Type: "int", Primary: true},
+			{StructName: "Order", Name: "status", Type: "order_status", Enum: []string{"new", "paid"}, Default: "new"},
+		},
+		Enums: []goschema.Enum{{Name: "order_status", Values: []string{"new", "paid"}, RenameFrom: "order_state"}},
+	}
+	database := &types.DBSchema{
+		Tables: []types.DBTable{{
+			Name: "orders",
+			Type: "TABLE",
+			Columns: []types.DBColumn{
+				{Name: "id", DataType: "int", IsNullable: "NO", IsPrimaryKey: true},
+				dbColumn,
+			},
+		}},
+		Enums: []types.DBEnum{{Name: "order_state", Values: []string{"new", "paid"}}},
+	}
+	return generated, database
+}
+
+func TestCompareWithDialect_PostgresRenamedEnumColumnsKeepTheirType(t *testing.T) {
+	c := qt.New(t)
+
+	dbDefault := "'new'::order_state"
+	generated, database := renamedEnumSchemas(types.DBColumn{
+		Name: "status", DataType: "USER-DEFINED", UDTName: "order_state", IsNullable: "NO", ColumnDefault: &dbDefault,
+	})
+
+	diff := schemadiff.CompareWithDialect(generated, database, "postgres")
+
+	c.Assert(diff.EnumsRenamed, qt.DeepEquals, []difftypes.EnumRename{{OldName: "order_state", NewName: "order_status"}})
+	c.Assert(diff.EnumsAdded, qt.HasLen, 0)
+	c.Assert(diff.EnumsRemoved, qt.HasLen, 0)
+	c.Assert(diff.TablesModified, qt.HasLen, 0)
+	c.Assert(database.Tables[0].Columns[1].UDTName, qt.Equals, "order_state")
+}
+
+func TestCompareWithDialect_MySQLRenamedInlineEnumIsNoChange(t *testing.T) {
+	c := qt.New(t)
+
+	generated, database := renamedEnumSchemas(types.DBColumn{
+		Name: "status", DataType: "enum('new','paid')", IsNullable: "NO", ColumnDefault: new("new"),
+	})
+
+	diff := schemadiff.CompareWithDialect(generated, database, "mysql")
+
+	c.Assert(diff.HasChanges(), qt.IsFalse)
+}
+
 func TestCompareWithDialect_GeneratedColumnDefaultKindMatchesDialect(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -125,6 +180,129 @@ func TestCompareWithDialect_GeneratedColumnDefaultKindMatchesDialect(t *testing.
 	}
 }
 
+func TestCompareWithOptions_IgnoreDefaults(t *testing.T) {
+	c := qt.New(t)
+	dbDefault := "'archived'::text"
+	generated := &goschema.Database{
+		Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+		Fields: []goschema.Field{{StructName: "User", Name: "status", Type: "TEXT", Nullable: true, Default: "active"}},
+	}
+	database := &types.DBSchema{Tables: []types.DBTable{{
+		Name: "users",
+		Type: "TABLE",
+		Columns: []types.DBColumn{{
+			Name: "status", DataType: "text", IsNullable: "YES", ColumnDefault: &dbDefault,
+		}},
+	}}}
+	opts := config.WithIgnoreDefaults(true)
+	opts.Dialect = "postgres"
+
+	diff := schemadiff.CompareWithOptions(generated, database, opts)
+
+	c.Assert(diff.TablesModified, qt.HasLen, 0)
+	c.Assert(diff.HasChanges(), qt.IsFalse)
+}
+
+func TestCompare_RecordsForeignKeysBetweenRemovedTables(t *testing.T) {
+	c := qt.New(t)
+	foreignTable := func(name string) *string { return &name }
+	database := &types.DBSchema{
+		Tables: []types.DBTable{
+			{Name: "accounts", Type: "TABLE"},
+			{Name: "projects", Type: "TABLE"},
+			{Name: "tasks", Type: "TABLE"},
+			{Name: "users", Type: "TABLE"},
+		},
+		Constraints: []types.DBConstraint{
+			{Name: "projects_account_fk", TableName: "projects", Type: "FOREIGN KEY", ForeignTable: foreignTable("accounts")},
+			{Name: "tasks_project_fk", TableName: "tasks", Type: "FOREIGN KEY", ForeignTable: foreignTable("projects")},
+			{Name: "tasks_user_fk", TableName: "tasks", Type: "FOREIGN KEY", ForeignTable: foreignTable("users")},
+		},
+	}
+	generated := &goschema.Database{Tables: []goschema.Table{{Name: "users", StructName: "User"}}}
+
+	diff := schemadiff.Compare(generated, database)
+
+	c.Assert(diff.TablesRemoved, qt.DeepEquals, []string{"accounts", "projects", "tasks"})
+	c.Assert(diff.TableDropDependencies, qt.DeepEquals, map[string][]string{
+		"projects": {"accounts"},
+		"tasks":    {"projects"},
+	})
+}
+
+func TestCompareWithOptions_StructuredComments(t *testing.T) {
+	tests := []struct {
+		name           string
+		dbComment      string
+		genComment     string
+		wantVerbatim   bool
+		wantStructured bool
+	}{
+		{name: "identical", dbComment: "a=1;b=2", genComment: "a=1;b=2"},
+		{name: "reordered", dbComment: "a=1;b=2", genComment: "b=2;a=1", wantVerbatim: true},
+		{name: "spacing and trailing separator", dbComment: "a=1; b=2", genComment: "b = 2;a=1;", wantVerbatim: true},
+		{name: "changed value", dbComment: "a=1;b=2", genComment: "b=3;a=1", wantVerbatim: true, wantStructured: true},
+		{name: "added key", dbComment: "a=1", genComment: "a=1;b=2", wantVerbatim: true, wantStructured: true},
+		{name: "malformed pair falls back to verbatim", dbComment: "a=1;b", genComment: "b;a=1", wantVerbatim: true, wantStructured: true},
+		{name: "repeated key falls back to verbatim", dbComment: "a=1;a=1", genComment: "a=1", wantVerbatim: true, wantStructured: true},
+		{name: "plain text", dbComment: "Login address", genComment: "Contact address", wantVerbatim: true, wantStructured: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			generated := &goschema.Database{
+				Tables: []goschema.Table{{Name: "users", StructName: "User"}},
+				Fields: []goschema.Field{{StructName: "User", Name: "email", Type: "TEXT", Nullable: true, Comment: tt.genComment}},
+			}
+			database := &types.DBSchema{Tables: []types.DBTable{{
+				Name:    "users",
+				Type:    "TABLE",
+				Columns: []types.DBColumn{{Name: "email", DataType: "text", IsNullable: "YES", Comment: tt.dbComment}},
+			}}}
+			verbatim := config.WithStructuredComments(false)
+			verbatim.Dialect = "postgres"
+			structured := config.WithStructuredComments(true)
+			structured.Dialect = "postgres"
+
+			c.Assert(schemadiff.CompareWithOptions(generated, database, verbatim).HasChanges(), qt.Equals, tt.wantVerbatim)
+			c.Assert(schemadiff.CompareWithOptions(generated, database, structured).HasChanges(), qt.Equals, tt.wantStructured)
+		})
+	}
+}
+
+func TestCompareWithDialect_SequenceOptionsMariaDBCannotStore(t *testing.T) {
+	tests := []struct {
+		dialect     string
+		wantChanges []string
+	}{
+		{dialect: "mariadb", wantChanges: nil},
+		{dialect: "postgres", wantChanges: []string{"as", "owned_by"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			c := qt.New(t)
+			start := int64(1000)
+			generated := &goschema.Database{Sequences: []goschema.Sequence{{
+				Name: "order_numbers", AsType: "integer", Start: &start, OwnedBy: "orders.number",
+			}}}
+			database := &types.DBSchema{Sequences: []types.DBSequence{{
+				Name: "order_numbers", DataType: "bigint", Start: &start,
+			}}}
+
+			diff := schemadiff.CompareWithDialect(generated, database, tt.dialect)
+
+			var changes []string
+			for _, sequenceDiff := range diff.SequencesModified {
+				changes = slices.Sorted(maps.Keys(sequenceDiff.Changes))
+			}
+			c.Assert(changes, qt.DeepEquals, tt.wantChanges)
+			c.Assert(generated.Sequences[0].AsType, qt.Equals, "integer")
+		})
+	}
+}
+
 func TestCompareWithDialect_GeneratedColumnCatalogExpressionsMatch(t *testing.T) {
 	tests := []struct {
 		name               string
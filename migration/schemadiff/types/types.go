@@ -1,5 +1,7 @@
 package types
 
+import "slices"
+
 // IndexRemovalInfo contains information about an index that needs to be removed,
 // including both the index name and the table it belongs to.
 // This is needed for databases like MySQL/MariaDB that require the table name
@@ -10,6 +12,38 @@ type IndexRemovalInfo struct {
 
 	// TableName is the name of the table that the index belongs to
 	TableName string `json:"table_name"`
+
+	// Columns are the columns the database index covers, when known. Planners
+	// use them to drop the index before a column it covers.
+	Columns []string `json:"columns,omitempty"`
+}
+
+// ExtensionDiff describes an installed extension whose version differs from
+// the version pinned by the target schema, so it is updated in place with
+// ALTER EXTENSION ... UPDATE TO.
+type ExtensionDiff struct {
+	// ExtensionName is the name of the extension.
+	ExtensionName string `json:"extension_name"`
+
+	// CurrentVersion is the version installed in the database.
+	CurrentVersion string `json:"current_version"`
+
+	// TargetVersion is the version the schema pins.
+	TargetVersion string `json:"target_version"`
+}
+
+// IndexVisibilityChange describes a MySQL/MariaDB index whose definition is
+// unchanged but whose optimizer visibility differs, so it can be toggled in
+// place with ALTER INDEX instead of being dropped and recreated.
+type IndexVisibilityChange struct {
+	// Name is the name of the index.
+	Name string `json:"name"`
+
+	// TableName is the name of the table that the index belongs to.
+	TableName string `json:"table_name"`
+
+	// Visible is the target visibility.
+	Visible bool `json:"visible"`
 }
 
 // ConstraintRemovalInfo contains information about a constraint that needs to be
@@ -122,6 +156,11 @@ type SchemaDiff struct {
 	// but not in the target schema (potentially dangerous - data loss)
 	TablesRemoved []string `json:"tables_removed"`
 
+	// TableDropDependencies maps removed tables to the other removed tables
+	// their database foreign keys reference. The entities no longer declare
+	// these keys, so the planner reads the drop order from here.
+	TableDropDependencies map[string][]string `json:"table_drop_dependencies,omitempty"`
+
 	// TablesModified contains detailed information about tables that exist in both
 	// schemas but have structural differences (columns, constraints, etc.)
 	TablesModified []TableDiff `json:"tables_modified"`
@@ -138,6 +177,10 @@ type SchemaDiff struct {
 	// schemas but have different values (additions/removals)
 	EnumsModified []EnumDiff `json:"enums_modified"`
 
+	// EnumsRenamed contains enum types renamed through the enum annotation's
+	// rename_from attribute. EnumsModified refers to them by the new name.
+	EnumsRenamed []EnumRename `json:"enums_renamed,omitempty"`
+
 	// IndexesAdded contains names of indexes that exist in the target schema
 	// but not in the current database schema
 	IndexesAdded []string `json:"indexes_added"`
@@ -151,6 +194,10 @@ type SchemaDiff struct {
 	// table names in DROP INDEX statements (e.g., MySQL/MariaDB).
 	IndexesRemovedWithTables []IndexRemovalInfo `json:"indexes_removed_with_tables"`
 
+	// IndexesVisibilityChanged contains MySQL/MariaDB indexes whose visibility
+	// (INVISIBLE/IGNORED) differs while the definition is unchanged.
+	IndexesVisibilityChanged []IndexVisibilityChange `json:"indexes_visibility_changed,omitempty"`
+
 	// ExtensionsAdded contains names of PostgreSQL extensions that exist in the target schema
 	// but not in the current database schema
 	ExtensionsAdded []string `json:"extensions_added"`
@@ -159,6 +206,11 @@ type SchemaDiff struct {
 	// but not in the target schema (potentially dangerous - may break existing functionality)
 	ExtensionsRemoved []string `json:"extensions_removed"`
 
+	// ExtensionsModified contains installed extensions whose version differs
+	// from the version the target schema pins. Extensions without a pinned
+	// version are never compared.
+	ExtensionsModified []ExtensionDiff `json:"extensions_modified,omitempty"`
+
 	// FunctionsAdded contains names of PostgreSQL functions that exist in the target schema
 	// but not in the current database schema
 	FunctionsAdded []string `json:"functions_added"`
@@ -171,6 +223,10 @@ type SchemaDiff struct {
 	// schemas but have different definitions (parameters, body, attributes, etc.)
 	FunctionsModified []FunctionDiff `json:"functions_modified"`
 
+	// FunctionTriggers maps removed functions to the database triggers that still
+	// execute them. PostgreSQL refuses DROP FUNCTION while such a trigger exists.
+	FunctionTriggers map[string][]TriggerRef `json:"function_triggers,omitempty"`
+
 	// SequencesAdded contains names of standalone sequences that exist in the target
 	// schema but not in the current database schema.
 	SequencesAdded []string `json:"sequences_added"`
@@ -262,6 +318,15 @@ type SchemaDiff struct {
 	// schemas but have different definitions (attributes, passwords, etc.)
 	RolesModified []RoleDiff `json:"roles_modified"`
 
+	// RoleMembershipsAdded contains role memberships (GRANT role TO member)
+	// that annotated roles declare but the database lacks.
+	RoleMembershipsAdded []RoleMembership `json:"role_memberships_added,omitempty"`
+
+	// RoleMembershipsRemoved contains memberships of annotated roles that the
+	// annotations no longer declare. It is filled only when
+	// CompareOptions.RevokeUndeclaredRoleMemberships is set.
+	RoleMembershipsRemoved []RoleMembership `json:"role_memberships_removed,omitempty"`
+
 	// GrantsAdded contains PostgreSQL privilege grants that exist in the target
 	// schema but not in the current database schema.
 	GrantsAdded []GrantRef `json:"grants_added"`
@@ -304,6 +369,29 @@ type SchemaDiff struct {
 	// (ConstraintsRemoved by name, this one by table then name), so consumers must
 	// correlate entries by constraint name, never by position.
 	ConstraintsRemovedWithTables []ConstraintRemovalInfo `json:"constraints_removed_with_tables"`
+
+	// SeedsAdded contains seed rows to insert, either because their table is
+	// created by this diff or because the seed is declared always="true".
+	// Seeds only accompany other changes and never make a diff non-empty on
+	// their own.
+	SeedsAdded []SeedData `json:"seeds_added,omitempty"`
+
+	// SeedsRemoved contains seed rows to delete. Comparison never produces
+	// it; it is the reverse of SeedsAdded in down migrations.
+	SeedsRemoved []SeedData `json:"seeds_removed,omitempty"`
+}
+
+// SeedData describes rows seeded into one table.
+type SeedData struct {
+	// Table is the table receiving the rows.
+	Table string `json:"table"`
+
+	// Columns lists the columns populated by each row.
+	Columns []string `json:"columns"`
+
+	// Rows holds the row values in Columns order. The bare value NULL stands
+	// for SQL NULL.
+	Rows [][]string `json:"rows"`
 }
 
 // HasChanges returns true if the diff contains any schema changes requiring migration.
@@ -348,7 +436,8 @@ func (d *SchemaDiff) HasChanges() bool {
 		d.hasTriggerChanges() ||
 		d.hasRLSChanges() ||
 		d.hasRoleChanges() ||
-		d.hasConstraintChanges()
+		d.hasConstraintChanges() ||
+		d.hasSeedChanges()
 }
 
 // hasTableChanges returns true if there are any table-related changes
@@ -362,19 +451,22 @@ func (d *SchemaDiff) hasTableChanges() bool {
 func (d *SchemaDiff) hasEnumChanges() bool {
 	return len(d.EnumsAdded) > 0 ||
 		len(d.EnumsRemoved) > 0 ||
-		len(d.EnumsModified) > 0
+		len(d.EnumsModified) > 0 ||
+		len(d.EnumsRenamed) > 0
 }
 
 // hasIndexChanges returns true if there are any index-related changes
 func (d *SchemaDiff) hasIndexChanges() bool {
 	return len(d.IndexesAdded) > 0 ||
-		len(d.IndexesRemoved) > 0
+		len(d.IndexesRemoved) > 0 ||
+		len(d.IndexesVisibilityChanged) > 0
 }
 
 // hasExtensionChanges returns true if there are any extension-related changes
 func (d *SchemaDiff) hasExtensionChanges() bool {
 	return len(d.ExtensionsAdded) > 0 ||
-		len(d.ExtensionsRemoved) > 0
+		len(d.ExtensionsRemoved) > 0 ||
+		len(d.ExtensionsModified) > 0
 }
 
 // hasFunctionChanges returns true if there are any function-related changes
@@ -430,18 +522,69 @@ func (d *SchemaDiff) hasRoleChanges() bool {
 	return len(d.RolesAdded) > 0 ||
 		len(d.RolesRemoved) > 0 ||
 		len(d.RolesModified) > 0 ||
+		len(d.RoleMembershipsAdded) > 0 ||
+		len(d.RoleMembershipsRemoved) > 0 ||
 		len(d.GrantsAdded) > 0 ||
 		len(d.GrantsRemoved) > 0 ||
 		len(d.GrantOptionsAdded) > 0 ||
 		len(d.GrantOptionsRevoked) > 0
 }
 
+// hasSeedChanges returns true if there are seed rows to insert or delete
+func (d *SchemaDiff) hasSeedChanges() bool {
+	return len(d.SeedsAdded) > 0 ||
+		len(d.SeedsRemoved) > 0
+}
+
 // hasConstraintChanges returns true if there are any constraint-related changes
 func (d *SchemaDiff) hasConstraintChanges() bool {
 	return len(d.ConstraintsAdded) > 0 ||
 		len(d.ConstraintsRemoved) > 0
 }
 
+// BlockingFunctionTriggers returns the triggers in FunctionTriggers[function]
+// that this diff neither drops nor replaces, directly or with their table.
+// A non-empty result means dropping the function would fail.
+func (d *SchemaDiff) BlockingFunctionTriggers(function string) []TriggerRef {
+	handled := make(map[TriggerRef]bool, len(d.TriggersRemoved)+len(d.TriggersModified))
+	for _, ref := range d.TriggersRemoved {
+		handled[ref] = true
+	}
+	for _, triggerDiff := range d.TriggersModified {
+		handled[TriggerRef{TriggerName: triggerDiff.TriggerName, TableName: triggerDiff.TableName}] = true
+	}
+	var blocking []TriggerRef
+	for _, ref := range d.FunctionTriggers[function] {
+		if !handled[ref] && !slices.Contains(d.TablesRemoved, ref.TableName) {
+			blocking = append(blocking, ref)
+		}
+	}
+	return blocking
+}
+
+// IndexDroppedWithTable reports whether index belongs to a table in
+// TablesRemoved, so dropping the table already removes the index.
+func (d *SchemaDiff) IndexDroppedWithTable(index IndexRemovalInfo) bool {
+	return slices.Contains(d.TablesRemoved, index.TableName)
+}
+
+// IndexNameDroppedWithTables is IndexDroppedWithTable for a name from
+// IndexesRemoved: it reports true when IndexesRemovedWithTables records the
+// name and every recorded table is in TablesRemoved.
+func (d *SchemaDiff) IndexNameDroppedWithTables(name string) bool {
+	found := false
+	for _, index := range d.IndexesRemovedWithTables {
+		if index.Name != name {
+			continue
+		}
+		if !d.IndexDroppedWithTable(index) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
 // TableDiff represents structural differences within a specific database table.
 //
 // This structure captures all types of changes that can occur to a table's structure,
@@ -479,6 +622,41 @@ type TableDiff struct {
 	// ConstraintsRemoved contains names of constraints that need to be removed from the table
 	// (potentially dangerous - may affect data integrity)
 	ConstraintsRemoved []string `json:"constraints_removed"`
+
+	// InheritsAdded contains PostgreSQL parent tables the table must start
+	// inheriting from (ALTER TABLE ... INHERIT parent)
+	InheritsAdded []string `json:"inherits_added,omitempty"`
+
+	// InheritsRemoved contains PostgreSQL parent tables the table must stop
+	// inheriting from (ALTER TABLE ... NO INHERIT parent)
+	InheritsRemoved []string `json:"inherits_removed,omitempty"`
+
+	// Comment records a PostgreSQL table comment change as "old -> new",
+	// using '' for an absent comment. Empty when the comment is unchanged.
+	Comment string `json:"comment,omitempty"`
+
+	// AutoIncrement records a MySQL/MariaDB table AUTO_INCREMENT counter that
+	// must be raised to the annotated start value, as "old -> new". Empty when
+	// no change is needed.
+	AutoIncrement string `json:"auto_increment,omitempty"`
+
+	// Owner records a PostgreSQL table owner change as "old -> new". It is
+	// only set for tables whose annotation declares an owner.
+	Owner string `json:"owner,omitempty"`
+
+	// ColumnsExternal lists database columns the table annotation declares as
+	// externally managed (external_columns). They were skipped by the column
+	// comparison; the list is informational and never triggers a migration.
+	ColumnsExternal []string `json:"columns_external,omitempty"`
+}
+
+// HasChanges reports whether the table needs a migration. ColumnsExternal is
+// informational and does not count.
+func (d TableDiff) HasChanges() bool {
+	return len(d.ColumnsAdded) > 0 || len(d.ColumnsRemoved) > 0 || len(d.ColumnsModified) > 0 ||
+		len(d.ConstraintsAdded) > 0 || len(d.ConstraintsRemoved) > 0 ||
+		len(d.InheritsAdded) > 0 || len(d.InheritsRemoved) > 0 || d.Comment != "" || d.AutoIncrement != "" ||
+		d.Owner != ""
 }
 
 // ColumnDiff represents specific property changes within a database column.
@@ -513,7 +691,50 @@ type ColumnDiff struct {
 	// Changes maps change types to their old->new value transitions
 	// Format: "change_type" -> "old_value -> new_value"
 	Changes map[string]string `json:"changes"`
-}
+
+	// TypeChangeKind classifies the "type" change for the target dialect.
+	// It is empty when the type is unchanged or the types fall outside the
+	// families the classifier knows, such as enums and user-defined types.
+	TypeChangeKind TypeChangeKind `json:"type_change_kind,omitempty"`
+
+	// Explanations record, for each entry in Changes, the values the
+	// comparison used to decide it. They are only filled in when
+	// config.CompareOptions.Explain is set.
+	Explanations []ChangeExplanation `json:"explanations,omitempty"`
+}
+
+// ChangeExplanation shows why a column property was reported as changed: the
+// raw values from the database and the annotation, the forms they were
+// compared in, and the rule that decided they differ.
+type ChangeExplanation struct {
+	// Change is the key of the entry in ColumnDiff.Changes.
+	Change string `json:"change"`
+	// Old and New are the raw database and annotation values.
+	Old string `json:"old"`
+	New string `json:"new"`
+	// NormalizedOld and NormalizedNew are the values actually compared.
+	NormalizedOld string `json:"normalized_old"`
+	NormalizedNew string `json:"normalized_new"`
+	// Rule names the comparison that reported the change.
+	Rule string `json:"rule"`
+}
+
+// TypeChangeKind classifies how a column type change treats the values
+// already stored in the column.
+type TypeChangeKind string
+
+const (
+	// TypeChangeWidening keeps every existing value representable, e.g.
+	// VARCHAR(100) -> VARCHAR(255) or INTEGER -> BIGINT.
+	TypeChangeWidening TypeChangeKind = "widening"
+	// TypeChangeNarrowing stays in the same family but shrinks the range,
+	// length, or precision, e.g. VARCHAR(255) -> VARCHAR(50). Existing values
+	// may not fit.
+	TypeChangeNarrowing TypeChangeKind = "narrowing"
+	// TypeChangeIncompatible converts between type families, e.g. TEXT ->
+	// INTEGER. Existing values may fail to convert or change meaning.
+	TypeChangeIncompatible TypeChangeKind = "incompatible"
+)
 
 // EnumDiff represents changes to enum type values.
 //
@@ -546,6 +767,13 @@ type EnumDiff struct {
 	ValuesRemoved []string `json:"values_removed"`
 }
 
+// EnumRename describes an enum type renamed in place, which keeps the columns
+// that use it.
+type EnumRename struct {
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+}
+
 // FunctionDiff represents changes to PostgreSQL function definitions.
 //
 // This structure captures modifications to function definitions, including changes
@@ -721,6 +949,16 @@ type RoleDiff struct {
 	Changes map[string]string `json:"changes"`
 }
 
+// RoleMembership identifies one PostgreSQL role membership: Role is granted
+// to Member.
+type RoleMembership struct {
+	// Role is the granted role.
+	Role string `json:"role"`
+
+	// Member is the role receiving or losing the membership.
+	Member string `json:"member"`
+}
+
 // GrantRef identifies one PostgreSQL privilege grant.
 type GrantRef struct {
 	// Role is the role receiving or losing the privilege.
@@ -103,3 +103,36 @@ func TestSchemaDiff_HasChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaDiff_IndexNameDroppedWithTables(t *testing.T) {
+	diff := &types.SchemaDiff{
+		TablesRemoved:  []string{"sessions", "audit.events"},
+		IndexesRemoved: []string{"idx_id", "idx_sessions_token", "idx_events_at", "idx_users_email", "idx_unknown"},
+		IndexesRemovedWithTables: []types.IndexRemovalInfo{
+			{Name: "idx_events_at", TableName: "audit.events"},
+			{Name: "idx_id", TableName: "sessions"},
+			{Name: "idx_id", TableName: "users"},
+			{Name: "idx_sessions_token", TableName: "sessions"},
+			{Name: "idx_users_email", TableName: "users"},
+		},
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "idx_sessions_token", want: true},
+		{name: "idx_events_at", want: true},
+		{name: "idx_users_email", want: false},
+		// The name also belongs to a table that stays.
+		{name: "idx_id", want: false},
+		// No recorded table, so nothing proves the table drop removes it.
+		{name: "idx_unknown", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := qt.New(t)
+			c.Assert(diff.IndexNameDroppedWithTables(tt.name), qt.Equals, tt.want)
+		})
+	}
+}